@@ -0,0 +1,91 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// GrantAccess grants an MCP client temporary access to the tools of a tool group.
+func (c *Client) GrantAccess(input *types.CreateAccessGrantInput) (*types.AccessGrant, error) {
+	u, _ := c.constructAPIEndpoint("/access-grants")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize access grant into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var grant types.AccessGrant
+	if err := json.NewDecoder(resp.Body).Decode(&grant); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &grant, nil
+}
+
+// ListAccessGrants fetches the access grants that haven't expired yet.
+func (c *Client) ListAccessGrants() ([]*types.AccessGrant, error) {
+	u, _ := c.constructAPIEndpoint("/access-grants")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var grants []*types.AccessGrant
+	if err := json.NewDecoder(resp.Body).Decode(&grants); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return grants, nil
+}
+
+// RevokeAccessGrant ends an access grant before it expires.
+func (c *Client) RevokeAccessGrant(id uint) error {
+	u, _ := c.constructAPIEndpoint("/access-grants/" + strconv.FormatUint(uint64(id), 10))
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
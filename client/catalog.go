@@ -0,0 +1,182 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Snapshot takes a snapshot of the catalog of the gateway: all its MCP servers and tools.
+// The environment of STDIO servers is left out because it often contains secrets.
+func (c *Client) Snapshot() (*types.CatalogSnapshot, error) {
+	servers, err := c.ListServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	tools, err := c.ListTools("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+	for _, s := range servers {
+		s.Env = nil
+	}
+	slices.SortFunc(servers, func(a, b *types.McpServer) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(tools, func(a, b *types.Tool) int { return strings.Compare(a.Name, b.Name) })
+	return &types.CatalogSnapshot{
+		Registry: c.baseURL,
+		TakenAt:  time.Now().UTC(),
+		Servers:  servers,
+		Tools:    tools,
+	}, nil
+}
+
+// ErrInvalidCatalogSignature is returned when the signature of a published catalog snapshot cannot be verified
+var ErrInvalidCatalogSignature = errors.New("invalid catalog snapshot signature")
+
+// VerifyCatalogSnapshot verifies a catalog snapshot published by MCPJungle (see CATALOG_PUBLISH_URL) against its
+// signature, the content of the file published next to it, and returns the snapshot.
+// keys are the server's signing keys, see GetResultSigningKeys.
+func VerifyCatalogSnapshot(payload, signature []byte, keys *types.JWKS) (*types.CatalogSnapshot, error) {
+	var header struct {
+		Typ string `json:"typ"`
+	}
+	if err := verifyDetachedJWS(string(bytes.TrimSpace(signature)), payload, keys, &header); err != nil {
+		if errors.Is(err, errJWSMismatch) {
+			return nil, fmt.Errorf("%w: the snapshot was modified", ErrInvalidCatalogSignature)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCatalogSignature, err)
+	}
+	if header.Typ != types.CatalogSnapshotJWSType {
+		return nil, fmt.Errorf("%w: not the signature of a catalog snapshot", ErrInvalidCatalogSignature)
+	}
+	var snapshot types.CatalogSnapshot
+	if err := json.Unmarshal(payload, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid catalog snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// DiffCatalogs compares two catalogs. It reports the servers & tools present in only one of them,
+// and the differences in the configuration, schemas and enablement of those present in both.
+// Settings that don't affect what MCP clients see (eg- versions, costs, hedging) are ignored.
+func DiffCatalogs(from, to *types.CatalogSnapshot) *types.CatalogDiff {
+	d := &types.CatalogDiff{From: from.Registry, To: to.Registry}
+	d.ServersOnlyInFrom, d.ServersOnlyInTo, d.ServersChanged = diffEntities(
+		from.Servers, to.Servers, func(s *types.McpServer) string { return s.Name }, diffServer,
+	)
+	d.ToolsOnlyInFrom, d.ToolsOnlyInTo, d.ToolsChanged = diffEntities(
+		from.Tools, to.Tools, func(t *types.Tool) string { return t.Name }, diffTool,
+	)
+	return d
+}
+
+func diffEntities[T any](
+	from, to []T, name func(T) string, diff func(a, b T) []string,
+) (onlyInFrom, onlyInTo []string, changed []types.CatalogChange) {
+	byName := make(map[string]T, len(to))
+	for _, e := range to {
+		byName[name(e)] = e
+	}
+	seen := make(map[string]bool, len(from))
+	for _, a := range from {
+		n := name(a)
+		seen[n] = true
+		b, ok := byName[n]
+		if !ok {
+			onlyInFrom = append(onlyInFrom, n)
+			continue
+		}
+		if changes := diff(a, b); len(changes) > 0 {
+			changed = append(changed, types.CatalogChange{Name: n, Changes: changes})
+		}
+	}
+	for _, b := range to {
+		if n := name(b); !seen[n] {
+			onlyInTo = append(onlyInTo, n)
+		}
+	}
+	slices.Sort(onlyInFrom)
+	slices.Sort(onlyInTo)
+	slices.SortFunc(changed, func(a, b types.CatalogChange) int { return strings.Compare(a.Name, b.Name) })
+	return onlyInFrom, onlyInTo, changed
+}
+
+func diffServer(a, b *types.McpServer) []string {
+	var changes []string
+	changes = diffField(changes, "transport", a.Transport, b.Transport)
+	changes = diffField(changes, "description", a.Description, b.Description)
+	changes = diffField(changes, "region", a.Region, b.Region)
+	changes = diffField(changes, "group", a.Group, b.Group)
+	changes = diffField(changes, "url", a.URL, b.URL)
+	changes = diffField(changes, "command", a.Command, b.Command)
+	if !slices.Equal(a.Args, b.Args) {
+		changes = append(changes, fmt.Sprintf("args: %q -> %q", a.Args, b.Args))
+	}
+	changes = diffField(changes, "keep_warm", a.KeepWarm, b.KeepWarm)
+	return changes
+}
+
+func diffTool(a, b *types.Tool) []string {
+	var changes []string
+	changes = diffField(changes, "enabled", a.Enabled, b.Enabled)
+	if a.EnrichedDescription() != b.EnrichedDescription() {
+		changes = append(changes, "description changed")
+	}
+	changes = append(changes, diffInputSchema(a.InputSchema, b.InputSchema)...)
+	if !reflect.DeepEqual(a.OutputSchema, b.OutputSchema) {
+		changes = append(changes, "output_schema changed")
+	}
+	if !reflect.DeepEqual(a.Annotations, b.Annotations) {
+		changes = append(changes, "annotations changed")
+	}
+	return changes
+}
+
+// diffInputSchema describes the parameters added to, removed from or changed in a tool's input schema.
+func diffInputSchema(a, b types.ToolInputSchema) []string {
+	var added, removed, changed []string
+	for p, schema := range a.Properties {
+		other, ok := b.Properties[p]
+		switch {
+		case !ok:
+			removed = append(removed, p)
+		case !reflect.DeepEqual(schema, other):
+			changed = append(changed, p)
+		}
+	}
+	for p := range b.Properties {
+		if _, ok := a.Properties[p]; !ok {
+			added = append(added, p)
+		}
+	}
+
+	var changes []string
+	for _, c := range []struct {
+		what   string
+		params []string
+	}{{"added", added}, {"removed", removed}, {"changed", changed}} {
+		if len(c.params) > 0 {
+			slices.Sort(c.params)
+			changes = append(changes, fmt.Sprintf("input_schema: parameters %s: %s", c.what, strings.Join(c.params, ", ")))
+		}
+	}
+	requiredA, requiredB := slices.Sorted(slices.Values(a.Required)), slices.Sorted(slices.Values(b.Required))
+	if !slices.Equal(requiredA, requiredB) {
+		changes = append(changes, fmt.Sprintf("input_schema: required %v -> %v", requiredA, requiredB))
+	}
+	return changes
+}
+
+func diffField[T comparable](changes []string, field string, a, b T) []string {
+	if a == b {
+		return changes
+	}
+	return append(changes, fmt.Sprintf("%s: %v -> %v", field, a, b))
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	from := &types.CatalogSnapshot{
+		Registry: "http://prod",
+		Servers: []*types.McpServer{
+			{Name: "github", Transport: "streamable_http", URL: "https://a"},
+			{Name: "slack", Transport: "stdio", Command: "npx"},
+		},
+		Tools: []*types.Tool{
+			{Name: "github__search", Enabled: true, InputSchema: types.ToolInputSchema{
+				Properties: map[string]any{"q": map[string]any{"type": "string"}, "page": map[string]any{"type": "integer"}},
+				Required:   []string{"q"},
+			}},
+			{Name: "slack__post", Enabled: true},
+		},
+	}
+	to := &types.CatalogSnapshot{
+		Registry: "http://staging",
+		Servers: []*types.McpServer{
+			{Name: "github", Transport: "streamable_http", URL: "https://b", Version: 3},
+			{Name: "jira", Transport: "stdio", Command: "uvx"},
+		},
+		Tools: []*types.Tool{
+			{Name: "github__search", Enabled: false, CostPerCall: 1, InputSchema: types.ToolInputSchema{
+				Properties: map[string]any{"q": map[string]any{"type": "string"}, "limit": map[string]any{"type": "integer"}},
+				Required:   []string{"q", "limit"},
+			}},
+			{Name: "jira__create", Enabled: true},
+		},
+	}
+
+	d := DiffCatalogs(from, to)
+	want := &types.CatalogDiff{
+		From:              "http://prod",
+		To:                "http://staging",
+		ServersOnlyInFrom: []string{"slack"},
+		ServersOnlyInTo:   []string{"jira"},
+		ServersChanged:    []types.CatalogChange{{Name: "github", Changes: []string{"url: https://a -> https://b"}}},
+		ToolsOnlyInFrom:   []string{"slack__post"},
+		ToolsOnlyInTo:     []string{"jira__create"},
+		ToolsChanged: []types.CatalogChange{{Name: "github__search", Changes: []string{
+			"enabled: true -> false",
+			"input_schema: parameters added: limit",
+			"input_schema: parameters removed: page",
+			"input_schema: required [q] -> [limit q]",
+		}}},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("DiffCatalogs() = %+v, want %+v", d, want)
+	}
+	if !DiffCatalogs(from, from).Empty() {
+		t.Error("DiffCatalogs() of a catalog with itself is not empty")
+	}
+}
@@ -1,10 +1,14 @@
 package client
 
 import (
+	"encoding/json"
 	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 )
 
 // Client represents a client for interacting with the MCPJungle HTTP API
@@ -12,14 +16,27 @@ type Client struct {
 	baseURL     string
 	accessToken string
 	httpClient  *http.Client
+	observer    RequestObserver
+
+	// loadToken loads the access token on the first request that needs it, see WithAccessTokenLoader
+	loadToken     func() (string, error)
+	loadTokenOnce sync.Once
+	loadTokenErr  error
 }
 
-func NewClient(baseURL string, accessToken string, httpClient *http.Client) *Client {
-	return &Client{
+func NewClient(baseURL string, accessToken string, httpClient *http.Client, opts ...Option) *Client {
+	c := &Client{
 		baseURL:     baseURL,
 		accessToken: accessToken,
 		httpClient:  httpClient,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.observer != nil {
+		c.httpClient = c.observe(httpClient)
+	}
+	return c
 }
 
 // constructAPIEndpoint constructs the full API endpoint URL where a request must be sent
@@ -30,12 +47,51 @@ func (c *Client) constructAPIEndpoint(suffixPath string) (string, error) {
 // newRequest creates a new HTTP request with the specified method, URL, and body.
 // It automatically adds the Authorization header if an access token is present.
 func (c *Client) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	if c.loadToken != nil {
+		c.loadTokenOnce.Do(func() {
+			var token string
+			if token, c.loadTokenErr = c.loadToken(); c.loadTokenErr == nil && token != "" {
+				c.accessToken = token
+			}
+		})
+		if c.loadTokenErr != nil {
+			return nil, c.loadTokenErr
+		}
+	}
+	return newRequestWithToken(method, url, body, c.accessToken)
+}
+
+// newRequestWithToken creates a new HTTP request authenticated with the given access token, unless it is empty.
+func newRequestWithToken(method, url string, body io.Reader, accessToken string) (*http.Request, error) {
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
-	if c.accessToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
 	}
 	return req, nil
 }
+
+// setVersionQuery adds the version that a change is based on to the query of the request, unless it is 0.
+func setVersionQuery(req *http.Request, version int) {
+	if version == 0 {
+		return
+	}
+	q := req.URL.Query()
+	q.Set("version", strconv.Itoa(version))
+	req.URL.RawQuery = q.Encode()
+}
+
+// decodeVersionConflict returns the *types.VersionConflict contained in the body of a 409 Conflict response,
+// or nil if the response is not a version conflict.
+func decodeVersionConflict(statusCode int, body []byte) error {
+	if statusCode != http.StatusConflict {
+		return nil
+	}
+	var conflict types.VersionConflict
+	if err := json.Unmarshal(body, &conflict); err != nil || conflict.Kind == "" {
+		return nil
+	}
+	return &conflict
+}
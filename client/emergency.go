@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// GetEmergencyStatus fetches whether all tool calls are disabled by the break-glass switch.
+func (c *Client) GetEmergencyStatus() (*types.EmergencyStatus, error) {
+	u, _ := c.constructAPIEndpoint("/emergency")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.doEmergencyRequest(req)
+}
+
+// EmergencyDisableAll disables all tool calls, except those to the tools & MCP servers in input.AllowedTools.
+func (c *Client) EmergencyDisableAll(input *types.EmergencyDisableInput) (*types.EmergencyStatus, error) {
+	u, _ := c.constructAPIEndpoint("/emergency/disable-all")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize input into JSON: %w", err)
+	}
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doEmergencyRequest(req)
+}
+
+// EmergencyEnableAll turns the break-glass switch off, tool calls are accepted again.
+func (c *Client) EmergencyEnableAll() (*types.EmergencyStatus, error) {
+	u, _ := c.constructAPIEndpoint("/emergency/enable-all")
+	req, err := c.newRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.doEmergencyRequest(req)
+}
+
+func (c *Client) doEmergencyRequest(req *http.Request) (*types.EmergencyStatus, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var status types.EmergencyStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &status, nil
+}
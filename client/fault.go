@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// CreateFaultRule creates a new fault injection rule.
+func (c *Client) CreateFaultRule(rule *types.FaultRule) (*types.FaultRule, error) {
+	u, _ := c.constructAPIEndpoint("/faults")
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize fault rule into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.FaultRule
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListFaultRules fetches all fault injection rules.
+func (c *Client) ListFaultRules() ([]*types.FaultRule, error) {
+	u, _ := c.constructAPIEndpoint("/faults")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var rules []*types.FaultRule
+	if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteFaultRule deletes a fault injection rule by name.
+func (c *Client) DeleteFaultRule(name string) error {
+	u, _ := c.constructAPIEndpoint("/faults/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// StarTool adds a tool to the favorites of the current user.
+func (c *Client) StarTool(name string) error {
+	return c.doFavoriteRequest(http.MethodPut, "/users/me/favorites/"+url.PathEscape(name))
+}
+
+// UnstarTool removes a tool from the favorites of the current user.
+func (c *Client) UnstarTool(name string) error {
+	return c.doFavoriteRequest(http.MethodDelete, "/users/me/favorites/"+url.PathEscape(name))
+}
+
+// ListFavoriteTools fetches the canonical names of the tools starred by the current user.
+func (c *Client) ListFavoriteTools() ([]string, error) {
+	u, _ := c.constructAPIEndpoint("/users/me/favorites")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var tools []string
+	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return tools, nil
+}
+
+// SetToolView creates a personal view of the current user on the tools, or replaces the view with the same name.
+func (c *Client) SetToolView(view *types.ToolView) (*types.ToolView, error) {
+	u, _ := c.constructAPIEndpoint("/users/me/views")
+	body, err := json.Marshal(view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize input into JSON: %w", err)
+	}
+	req, err := c.newRequest(http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var saved types.ToolView
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &saved, nil
+}
+
+// ListToolViews fetches the personal views of the current user.
+func (c *Client) ListToolViews() ([]*types.ToolView, error) {
+	u, _ := c.constructAPIEndpoint("/users/me/views")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var views []*types.ToolView
+	if err := json.NewDecoder(resp.Body).Decode(&views); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return views, nil
+}
+
+// DeleteToolView deletes a personal view of the current user.
+func (c *Client) DeleteToolView(name string) error {
+	return c.doFavoriteRequest(http.MethodDelete, "/users/me/views/"+url.PathEscape(name))
+}
+
+// ListToolsInView fetches the tools in a view of the current user:
+// types.ToolViewFavorites, types.ToolViewFavoritesFirst or the name of one of their personal views.
+func (c *Client) ListToolsInView(view string) ([]*types.Tool, error) {
+	u, _ := c.constructAPIEndpoint("/tools")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Add("view", view)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", req.URL.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var tools []*types.Tool
+	if err := json.NewDecoder(resp.Body).Decode(&tools); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return tools, nil
+}
+
+func (c *Client) doFavoriteRequest(method, path string) error {
+	u, _ := c.constructAPIEndpoint(path)
+	req, err := c.newRequest(method, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// ListFeatures fetches the experimental features of the server along with their state.
+func (c *Client) ListFeatures() ([]*types.Feature, error) {
+	u, _ := c.constructAPIEndpoint("/features")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var features []*types.Feature
+	if err := json.NewDecoder(resp.Body).Decode(&features); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return features, nil
+}
+
+// SetFeature turns an experimental feature on or off for the whole deployment.
+func (c *Client) SetFeature(input *types.SetFeatureInput) error {
+	u, _ := c.constructAPIEndpoint("/features")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize input into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
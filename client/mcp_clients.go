@@ -36,13 +36,17 @@ func (c *Client) ListMcpClients() ([]types.McpClient, error) {
 	return clients, nil
 }
 
-func (c *Client) DeleteMcpClient(name string) error {
+// DeleteMcpClient deletes an MCP client by name.
+// If version is not 0, the client is only deleted if it was not modified since that version,
+// otherwise a *types.VersionConflict is returned.
+func (c *Client) DeleteMcpClient(name string, version int) error {
 	u, _ := c.constructAPIEndpoint("/clients/" + name)
 
 	req, err := c.newRequest(http.MethodDelete, u, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	setVersionQuery(req, version)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -52,6 +56,9 @@ func (c *Client) DeleteMcpClient(name string) error {
 
 	if resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
 	}
 
@@ -41,6 +41,39 @@ func (c *Client) RegisterServer(server *types.RegisterServerInput) (*types.McpSe
 	return &registeredServer, nil
 }
 
+// PreflightServer test-connects to an MCP server without registering it.
+// It returns the tools that would become available if the server was registered.
+func (c *Client) PreflightServer(server *types.RegisterServerInput) (*types.ServerPreflightResult, error) {
+	u, _ := c.constructAPIEndpoint("/servers/preflight")
+	body, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize server data into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var result types.ServerPreflightResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
 // ListServers fetches the list of registered servers.
 func (c *Client) ListServers() ([]*types.McpServer, error) {
 	u, _ := c.constructAPIEndpoint("/servers")
@@ -68,9 +101,12 @@ func (c *Client) ListServers() ([]*types.McpServer, error) {
 }
 
 // DeregisterServer deletes a server by name.
-func (c *Client) DeregisterServer(name string) error {
+// If version is not 0, the server is only deregistered if it was not modified since that version,
+// otherwise a *types.VersionConflict is returned.
+func (c *Client) DeregisterServer(name string, version int) error {
 	u, _ := c.constructAPIEndpoint("/servers/" + name)
 	req, _ := c.newRequest(http.MethodDelete, u, nil)
+	setVersionQuery(req, version)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -80,7 +116,153 @@ func (c *Client) DeregisterServer(name string) error {
 
 	if resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("unexpected status from server: %s, body: %s", resp.Status, body)
 	}
 	return nil
 }
+
+// GetServerCapabilities fetches the protocol version & capabilities negotiated with an MCP server.
+// If refresh is true, mcpjungle re-negotiates them with the server instead of returning the recorded ones.
+func (c *Client) GetServerCapabilities(name string, refresh bool) (*types.ServerCapabilities, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/capabilities")
+	if refresh {
+		u += "?refresh=true"
+	}
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var caps types.ServerCapabilities
+	if err := json.NewDecoder(resp.Body).Decode(&caps); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &caps, nil
+}
+
+// GetServerHealth fetches the health of an MCP server.
+// If refresh is true, mcpjungle checks the health of the server first instead of returning the last observed one.
+func (c *Client) GetServerHealth(name string, refresh bool) (*types.ServerHealth, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/health")
+	if refresh {
+		u += "?refresh=true"
+	}
+	var h types.ServerHealth
+	if err := c.getHealth(u, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// GetServersHealth fetches the health of all the MCP servers.
+func (c *Client) GetServersHealth() (*types.ServersHealth, error) {
+	u, _ := c.constructAPIEndpoint("/health/servers")
+	var h types.ServersHealth
+	if err := c.getHealth(u, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// getHealth fetches a health report, which is also returned along with a 503 status if servers are unhealthy.
+func (c *Client) getHealth(u string, dst any) error {
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// PinServer records the identity currently reported by an MCP server as its pinned identity.
+// If identityPinning is not empty, the server's pinning mode is changed too.
+func (c *Client) PinServer(name string, identityPinning string) (*types.McpServer, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/pin")
+	body, err := json.Marshal(&types.PinServerInput{IdentityPinning: identityPinning})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize input into JSON: %w", err)
+	}
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var server types.McpServer
+	if err := json.NewDecoder(resp.Body).Decode(&server); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &server, nil
+}
+
+// StopServerGroup stops all the MCP servers of a server group: their tools are disabled and
+// mcpjungle disconnects from them until the group is started again.
+func (c *Client) StopServerGroup(group string) (*types.ServerGroupLifecycleResult, error) {
+	return c.serverGroupLifecycle(group, "stop")
+}
+
+// StartServerGroup starts the stopped MCP servers of a server group and re-enables their tools.
+func (c *Client) StartServerGroup(group string) (*types.ServerGroupLifecycleResult, error) {
+	return c.serverGroupLifecycle(group, "start")
+}
+
+func (c *Client) serverGroupLifecycle(group, action string) (*types.ServerGroupLifecycleResult, error) {
+	u, _ := c.constructAPIEndpoint("/server-groups/" + group + "/" + action)
+	req, err := c.newRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var result types.ServerGroupLifecycleResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
@@ -36,8 +36,36 @@ func (c *Client) ListTools(server string) ([]*types.Tool, error) {
 	return tools, nil
 }
 
+// LintTools returns the quality issues found in the definitions of the enabled tools.
+func (c *Client) LintTools() ([]*types.ToolLintIssue, error) {
+	u, _ := c.constructAPIEndpoint("/tools/lint")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var issues []*types.ToolLintIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return issues, nil
+}
+
 // EnableTools enables a tool or all tools provided by an MCP server.
-func (c *Client) EnableTools(name string) ([]string, error) {
+// If version is not 0, name must be a tool and the change is rejected with a *types.VersionConflict
+// if the tool was modified since that version.
+func (c *Client) EnableTools(name string, version int) ([]string, error) {
 	u, _ := c.constructAPIEndpoint("/tools/enable")
 	req, err := c.newRequest(http.MethodPost, u, nil)
 	if err != nil {
@@ -47,6 +75,7 @@ func (c *Client) EnableTools(name string) ([]string, error) {
 	q := req.URL.Query()
 	q.Add("entity", name)
 	req.URL.RawQuery = q.Encode()
+	setVersionQuery(req, version)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -56,6 +85,9 @@ func (c *Client) EnableTools(name string) ([]string, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
 	}
 
@@ -67,7 +99,8 @@ func (c *Client) EnableTools(name string) ([]string, error) {
 }
 
 // DisableTools disables a tool or all tools provided by an MCP server.
-func (c *Client) DisableTools(name string) ([]string, error) {
+// If version is not 0, name must be a tool, see EnableTools.
+func (c *Client) DisableTools(name string, version int) ([]string, error) {
 	u, _ := c.constructAPIEndpoint("/tools/disable")
 	req, err := c.newRequest(http.MethodPost, u, nil)
 	if err != nil {
@@ -77,6 +110,7 @@ func (c *Client) DisableTools(name string) ([]string, error) {
 	q := req.URL.Query()
 	q.Add("entity", name)
 	req.URL.RawQuery = q.Encode()
+	setVersionQuery(req, version)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -86,6 +120,9 @@ func (c *Client) DisableTools(name string) ([]string, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
 	}
 
@@ -125,6 +162,39 @@ func (c *Client) GetTool(name string) (*types.Tool, error) {
 // InvokeTool sends a JSON payload to invoke a tool.
 // For now, this function only supports invoking tools that return a string response.
 func (c *Client) InvokeTool(name string, input map[string]any) (*types.ToolInvokeResult, error) {
+	return c.InvokeToolAs(name, input, nil)
+}
+
+// InvokeToolAs invokes a tool on behalf of an MCP client or user, exactly as they would.
+// Only an admin can impersonate other callers. If as is nil, the tool is invoked as the current user.
+func (c *Client) InvokeToolAs(name string, input map[string]any, as *types.Impersonation) (*types.ToolInvokeResult, error) {
+	req, err := c.newInvokeRequest(name, input, as)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to server failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result *types.ToolInvokeResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
+
+// newInvokeRequest creates the request that invokes a tool with the given input, on behalf of as if not nil.
+func (c *Client) newInvokeRequest(name string, input map[string]any, as *types.Impersonation) (*http.Request, error) {
 	// We need to insert the tool name into the POST payload
 	// In order not to mutate the user-supplied input, create a shallow copy of the input
 	// and add the name field to it.
@@ -141,23 +211,161 @@ func (c *Client) InvokeTool(name string, input map[string]any) (*types.ToolInvok
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if as != nil && as.Client != "" {
+		req.Header.Set(types.ImpersonateClientHeader, as.Client)
+	}
+	if as != nil && as.User != "" {
+		req.Header.Set(types.ImpersonateUserHeader, as.User)
+	}
+	return req, nil
+}
+
+// SetToolDocs enriches the description of a tool, replacing any documentation previously added to it.
+func (c *Client) SetToolDocs(input *types.SetToolDocsInput) error {
+	u, _ := c.constructAPIEndpoint("/tools/docs")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool documentation into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request to server failed: %w", err)
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
 	}
 	defer resp.Body.Close()
 
-	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, string(respBody))
+// SetToolOutputSchema sets (or, given an empty schema, removes) the output schema of a tool.
+func (c *Client) SetToolOutputSchema(input *types.SetToolOutputSchemaInput) error {
+	u, _ := c.constructAPIEndpoint("/tools/output-schema")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool output schema into JSON: %w", err)
 	}
 
-	var result *types.ToolInvokeResult
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	return result, nil
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// SetToolHedging configures hedged requests for a tool.
+func (c *Client) SetToolHedging(input *types.SetToolHedgingInput) error {
+	u, _ := c.constructAPIEndpoint("/tools/hedging")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool hedging config into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// SetToolPayloadLogging configures the logging of the arguments & results of a sample of the calls to a tool.
+func (c *Client) SetToolPayloadLogging(input *types.SetToolPayloadLoggingInput) error {
+	u, _ := c.constructAPIEndpoint("/tools/payload-logging")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool payload logging config into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// SetToolScripts attaches CEL scripts to a tool.
+func (c *Client) SetToolScripts(input *types.SetToolScriptsInput) error {
+	u, _ := c.constructAPIEndpoint("/tools/scripts")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool scripts into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
 }
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusObserver returns a RequestObserver that records client-side metrics in reg.
+// The metrics follow the naming of the gateway's own metrics:
+//   - mcpjungle_client_requests_total{method, endpoint, code}, code is "error" if no response was received
+//   - mcpjungle_client_request_duration_seconds{method, endpoint}
+//
+// It returns an error if the metrics are already registered in reg.
+func PrometheusObserver(reg prometheus.Registerer) (RequestObserver, error) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mcpjungle",
+		Subsystem: "client",
+		Name:      "requests_total",
+		Help:      "Number of requests made to the MCPJungle API by endpoint and status code.",
+	}, []string{"method", "endpoint", "code"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "mcpjungle",
+		Subsystem: "client",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests made to the MCPJungle API, as seen by the client.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
+	for _, c := range []prometheus.Collector{requests, duration} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(s RequestStats) {
+		code := "error"
+		if s.Err == nil {
+			code = strconv.Itoa(s.StatusCode)
+		}
+		requests.WithLabelValues(s.Method, s.Endpoint, code).Inc()
+		duration.WithLabelValues(s.Method, s.Endpoint).Observe(s.Duration.Seconds())
+	}, nil
+}
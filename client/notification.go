@@ -0,0 +1,73 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// ListNotifications fetches the most recent notifications about operational events, newest first.
+func (c *Client) ListNotifications(unreadOnly bool, limit int) ([]*types.Notification, error) {
+	u, _ := c.constructAPIEndpoint("/notifications")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	if unreadOnly {
+		q.Add("unread", "true")
+	}
+	if limit != 0 {
+		q.Add("limit", strconv.Itoa(limit))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var notifications []*types.Notification
+	if err := json.NewDecoder(resp.Body).Decode(&notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkNotificationsRead marks the notifications with the given IDs as read.
+// If no IDs are given, all notifications are marked as read.
+func (c *Client) MarkNotificationsRead(ids ...uint) error {
+	u, _ := c.constructAPIEndpoint("/notifications/read")
+	body, err := json.Marshal(&types.MarkNotificationsReadInput{IDs: ids})
+	if err != nil {
+		return fmt.Errorf("failed to serialize input into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/api"
+)
+
+// RequestStats describes a request made by the client to the MCPJungle API.
+type RequestStats struct {
+	Method string
+
+	// Path is the path of the request relative to the API's base path, eg- "/servers/github"
+	Path string
+
+	// Endpoint is the first segment of Path, eg- "servers". Unlike Path, it is suitable as a metric label.
+	Endpoint string
+
+	// StatusCode is 0 if no response was received
+	StatusCode int
+
+	Duration time.Duration
+
+	// Err is the error that prevented a response from being received, if any
+	Err error
+}
+
+// RequestObserver is called after every request made by the client, eg- to record client-side metrics.
+// It is called synchronously, so it must not block.
+type RequestObserver func(RequestStats)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithRequestObserver makes the client report the latency and outcome of every API request to o.
+func WithRequestObserver(o RequestObserver) Option {
+	return func(c *Client) {
+		c.observer = o
+	}
+}
+
+// WithAccessTokenLoader makes the client load its access token with load, on the first request that needs it,
+// if none was supplied to NewClient. The error of load is returned by every request, so that commands which
+// don't talk to the API aren't affected by a token that can't be loaded.
+func WithAccessTokenLoader(load func() (string, error)) Option {
+	return func(c *Client) {
+		if c.accessToken == "" {
+			c.loadToken = load
+		}
+	}
+}
+
+// observedTransport reports every request that passes through it to an observer
+type observedTransport struct {
+	next     http.RoundTripper
+	observer RequestObserver
+}
+
+func (t *observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	path := req.URL.Path
+	if i := strings.Index(path, api.V0PathPrefix); i >= 0 {
+		path = path[i+len(api.V0PathPrefix):]
+	}
+	endpoint, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	stats := RequestStats{
+		Method:   req.Method,
+		Path:     path,
+		Endpoint: endpoint,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		stats.StatusCode = resp.StatusCode
+	}
+	t.observer(stats)
+	return resp, err
+}
+
+// observe makes httpClient report its requests to the client's observer.
+// It returns a copy of httpClient, so the caller's client is left untouched.
+func (c *Client) observe(httpClient *http.Client) *http.Client {
+	observed := *httpClient
+	next := observed.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	observed.Transport = &observedTransport{next: next, observer: c.observer}
+	return &observed
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestObserver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/features") {
+			_, _ = w.Write([]byte(`[{"name": "hedging", "enabled": true}]`))
+			return
+		}
+		http.Error(w, `{"error": "server not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var stats []RequestStats
+	httpClient := &http.Client{}
+	c := NewClient(srv.URL, "", httpClient, WithRequestObserver(func(s RequestStats) {
+		stats = append(stats, s)
+	}))
+	if httpClient.Transport != nil {
+		t.Error("NewClient() modified the caller's http.Client")
+	}
+
+	features, err := c.ListFeatures()
+	if err != nil {
+		t.Fatalf("ListFeatures() error = %v", err)
+	}
+	if len(features) != 1 || features[0].Name != "hedging" {
+		t.Errorf("ListFeatures() = %v", features)
+	}
+	if err := c.DeregisterServer("github", 0); err == nil {
+		t.Error("DeregisterServer() succeeded on a 404 response, want error")
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("observer called %d times, want 2", len(stats))
+	}
+	if s := stats[0]; s.Method != http.MethodGet || s.Path != "/features" || s.Endpoint != "features" ||
+		s.StatusCode != http.StatusOK || s.Err != nil {
+		t.Errorf("unexpected stats for ListFeatures: %+v", s)
+	}
+	if s := stats[1]; s.Method != http.MethodDelete || s.Path != "/servers/github" || s.Endpoint != "servers" ||
+		s.StatusCode != http.StatusNotFound || s.Err != nil {
+		t.Errorf("unexpected stats for DeregisterServer: %+v", s)
+	}
+}
+
+func TestPrometheusObserver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/features") {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	observer, err := PrometheusObserver(reg)
+	if err != nil {
+		t.Fatalf("PrometheusObserver() error = %v", err)
+	}
+	if _, err := PrometheusObserver(reg); err == nil {
+		t.Error("PrometheusObserver() registered the metrics twice, want error")
+	}
+
+	c := NewClient(srv.URL, "", &http.Client{}, WithRequestObserver(observer))
+	if _, err := c.ListFeatures(); err != nil {
+		t.Fatalf("ListFeatures() error = %v", err)
+	}
+	if _, err := c.ListServers(); err == nil {
+		t.Error("ListServers() succeeded on a 500 response, want error")
+	}
+
+	expected := `
+# HELP mcpjungle_client_requests_total Number of requests made to the MCPJungle API by endpoint and status code.
+# TYPE mcpjungle_client_requests_total counter
+mcpjungle_client_requests_total{code="200",endpoint="features",method="GET"} 1
+mcpjungle_client_requests_total{code="500",endpoint="servers",method="GET"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "mcpjungle_client_requests_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(reg, "mcpjungle_client_request_duration_seconds"); n != 2 {
+		t.Errorf("request duration has %d series, want 2", n)
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// CreatePrompt creates a new prompt template.
+func (c *Client) CreatePrompt(prompt *types.Prompt) (*types.Prompt, error) {
+	u, _ := c.constructAPIEndpoint("/prompts")
+	body, err := json.Marshal(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize prompt into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.Prompt
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListPrompts fetches all prompt templates.
+func (c *Client) ListPrompts() ([]*types.Prompt, error) {
+	u, _ := c.constructAPIEndpoint("/prompts")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var prompts []*types.Prompt
+	if err := json.NewDecoder(resp.Body).Decode(&prompts); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return prompts, nil
+}
+
+// SetPromptOverride sets the template of a prompt used for a specific MCP client.
+// An empty template removes the client's override.
+func (c *Client) SetPromptOverride(input *types.SetPromptOverrideInput) error {
+	u, _ := c.constructAPIEndpoint("/prompts/override")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize prompt override into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// DeletePrompt deletes a prompt template by name.
+func (c *Client) DeletePrompt(name string) error {
+	u, _ := c.constructAPIEndpoint("/prompts/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// CreateReplaySuite records a replay suite by calling the saved calls of an MCP server's tools.
+func (c *Client) CreateReplaySuite(input *types.CreateReplaySuiteInput) (*types.ReplaySuite, error) {
+	u, _ := c.constructAPIEndpoint("/replay-suites")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize replay suite into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.ReplaySuite
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListReplaySuites fetches all replay suites.
+func (c *Client) ListReplaySuites() ([]*types.ReplaySuite, error) {
+	u, _ := c.constructAPIEndpoint("/replay-suites")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var suites []*types.ReplaySuite
+	if err := json.NewDecoder(resp.Body).Decode(&suites); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return suites, nil
+}
+
+// RunReplaySuite replays the calls of a replay suite and returns the diff report.
+// If server is set, the calls are replayed against that MCP server instead of the recorded one.
+func (c *Client) RunReplaySuite(name, server string) (*types.ReplayReport, error) {
+	u, _ := c.constructAPIEndpoint("/replay-suites/" + name + "/run")
+	body, err := json.Marshal(&types.RunReplaySuiteInput{Server: server})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize replay input into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var report types.ReplayReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &report, nil
+}
+
+// DeleteReplaySuite deletes a replay suite by name.
+func (c *Client) DeleteReplaySuite(name string) error {
+	u, _ := c.constructAPIEndpoint("/replay-suites/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrInvalidResultSignature is returned when the signature of a tool result cannot be verified
+var ErrInvalidResultSignature = errors.New("invalid tool result signature")
+
+// GetResultSigningKeys fetches the public keys that verify the signatures of tool results.
+// The set is empty if the server does not sign tool results.
+func (c *Client) GetResultSigningKeys() (*types.JWKS, error) {
+	u, _ := url.JoinPath(c.baseURL, "/.well-known/jwks.json")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var keys types.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &keys, nil
+}
+
+// VerifyToolResultSignature verifies that a tool result passed through MCPJungle unmodified.
+// result can be a types.ToolInvokeResult or any other value that serializes to a tool result,
+// eg- the result of a call made via the MCP proxy. keys are the server's result signing keys,
+// see GetResultSigningKeys. It returns the name of the tool that produced the result.
+func VerifyToolResultSignature(result any, keys *types.JWKS) (string, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the result: %w", err)
+	}
+	var r struct {
+		Meta map[string]any `json:"_meta"`
+	}
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return "", fmt.Errorf("failed to parse the result: %w", err)
+	}
+	jws, _ := r.Meta[types.ToolResultSignatureMetaKey].(string)
+	if !strings.Contains(jws, "..") {
+		return "", fmt.Errorf("%w: the result is not signed", ErrInvalidResultSignature)
+	}
+
+	content, err := types.NewToolResultSigningContent(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the result: %w", err)
+	}
+	payload, err := types.ToolResultSigningPayload(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the result: %w", err)
+	}
+
+	var header struct {
+		Tool string `json:"tool"`
+	}
+	if err := verifyDetachedJWS(jws, payload, keys, &header); err != nil {
+		if errors.Is(err, errJWSMismatch) {
+			return "", fmt.Errorf("%w: the result was modified", ErrInvalidResultSignature)
+		}
+		return "", fmt.Errorf("%w: %v", ErrInvalidResultSignature, err)
+	}
+	return header.Tool, nil
+}
+
+// errJWSMismatch is returned by verifyDetachedJWS when the signature was made by a known key over another payload
+var errJWSMismatch = errors.New("the signature does not match the payload")
+
+// verifyDetachedJWS verifies an EdDSA JWS with a detached payload (RFC 7515 Appendix F) against keys,
+// and decodes its protected header into header.
+func verifyDetachedJWS(jws string, payload []byte, keys *types.JWKS, header any) error {
+	encodedHeader, encodedSig, ok := strings.Cut(jws, "..")
+	if !ok {
+		return errors.New("malformed signature")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(encodedHeader)
+	if err != nil {
+		return errors.New("malformed header")
+	}
+	var h struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &h); err != nil || h.Alg != "EdDSA" {
+		return errors.New("malformed header")
+	}
+	if err := json.Unmarshal(headerJSON, header); err != nil {
+		return errors.New("malformed header")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	for _, k := range keys.Keys {
+		if k.Kid != h.Kid || k.Kty != "OKP" || k.Crv != "Ed25519" {
+			continue
+		}
+		pub, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			continue
+		}
+		if ed25519.Verify(pub, []byte(signingInput), sig) {
+			return nil
+		}
+		return errJWSMismatch
+	}
+	return fmt.Errorf("unknown key %s", h.Kid)
+}
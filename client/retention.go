@@ -0,0 +1,77 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Prune deletes old rows from the server's history tables.
+// The policies in the input replace the server's configured retention policies for this run.
+func (c *Client) Prune(input *types.PruneInput) ([]*types.PruneResult, error) {
+	u, _ := c.constructAPIEndpoint("/prune")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize retention policies into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var results []*types.PruneResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return results, nil
+}
+
+// PurgeSubject deletes the data stored by mcpjungle about an MCP client or user
+// and returns the report that verifies the deletion.
+func (c *Client) PurgeSubject(subject *types.DataSubject) (*types.PurgeReport, error) {
+	u, _ := c.constructAPIEndpoint("/purge-subject")
+	body, err := json.Marshal(subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize data subject into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var report types.PurgeReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// GetReadOnly fetches whether the registry is in read-only mode.
+func (c *Client) GetReadOnly() (*types.ReadOnlyStatus, error) {
+	u, _ := c.constructAPIEndpoint("/read-only")
+	req, _ := c.newRequest(http.MethodGet, u, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var status types.ReadOnlyStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &status, nil
+}
+
+// SetReadOnly turns the read-only mode of the registry on or off.
+func (c *Client) SetReadOnly(enabled bool) error {
+	u, _ := c.constructAPIEndpoint("/read-only")
+	body, err := json.Marshal(&types.SetReadOnlyInput{Enabled: enabled})
+	if err != nil {
+		return fmt.Errorf("failed to serialize input into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// GetMetadata describes the registry server, eg- its version and how to authenticate to it.
+// It doesn't require authentication, nor the server to be initialized.
+func (c *Client) GetMetadata() (*types.ServerMetadata, error) {
+	u, _ := c.constructAPIEndpoint("/meta")
+	req, _ := c.newRequest(http.MethodGet, u, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var meta types.ServerMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &meta, nil
+}
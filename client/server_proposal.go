@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ProposeServer proposes the registration of an MCP server, which an admin then approves or rejects.
+func (c *Client) ProposeServer(server *types.RegisterServerInput) (*types.ServerProposal, error) {
+	body, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize server data into JSON: %w", err)
+	}
+	return c.sendServerProposalRequest("/server-proposals", body, http.StatusCreated)
+}
+
+// ListServerProposals lists the proposed server registrations with the given status (all of them if empty).
+// Users that aren't admins only get their own proposals.
+func (c *Client) ListServerProposals(status types.ServerProposalStatus) ([]*types.ServerProposal, error) {
+	u, _ := c.constructAPIEndpoint("/server-proposals")
+	if status != "" {
+		u += "?" + url.Values{"status": {string(status)}}.Encode()
+	}
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var proposals []*types.ServerProposal
+	if err := json.NewDecoder(resp.Body).Decode(&proposals); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return proposals, nil
+}
+
+// ApproveServerProposal registers the server of a pending proposal.
+func (c *Client) ApproveServerProposal(id uint, comment string) (*types.ServerProposal, error) {
+	return c.reviewServerProposal(id, "approve", comment)
+}
+
+// RejectServerProposal turns down a pending proposal.
+func (c *Client) RejectServerProposal(id uint, comment string) (*types.ServerProposal, error) {
+	return c.reviewServerProposal(id, "reject", comment)
+}
+
+// PreflightServerProposal test-connects to the server of a pending proposal and returns the proposal
+// along with the tools & capabilities that the server provides.
+func (c *Client) PreflightServerProposal(id uint) (*types.ServerProposal, error) {
+	path := "/server-proposals/" + strconv.FormatUint(uint64(id), 10) + "/preflight"
+	return c.sendServerProposalRequest(path, []byte("{}"), http.StatusOK)
+}
+
+func (c *Client) reviewServerProposal(id uint, verdict, comment string) (*types.ServerProposal, error) {
+	body, err := json.Marshal(types.ReviewServerProposalInput{Comment: comment})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize review into JSON: %w", err)
+	}
+	path := "/server-proposals/" + strconv.FormatUint(uint64(id), 10) + "/" + verdict
+	return c.sendServerProposalRequest(path, body, http.StatusOK)
+}
+
+func (c *Client) sendServerProposalRequest(path string, body []byte, expectedStatus int) (*types.ServerProposal, error) {
+	u, _ := c.constructAPIEndpoint(path)
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var p types.ServerProposal
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &p, nil
+}
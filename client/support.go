@@ -0,0 +1,32 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetSupportBundle downloads a support bundle (a gzipped tarball) describing the state of the registry
+// and writes it to w.
+func (c *Client) GetSupportBundle(w io.Writer) error {
+	u, _ := c.constructAPIEndpoint("/support-bundle")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download support bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+)
+
+// CreateToolGroup creates a new tool group.
+func (c *Client) CreateToolGroup(group *types.ToolGroup) (*types.ToolGroup, error) {
+	u, _ := c.constructAPIEndpoint("/tool-groups")
+	body, err := json.Marshal(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize tool group into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.ToolGroup
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListToolGroups fetches all tool groups.
+func (c *Client) ListToolGroups() ([]*types.ToolGroup, error) {
+	u, _ := c.constructAPIEndpoint("/tool-groups")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var toolGroups []*types.ToolGroup
+	if err := json.NewDecoder(resp.Body).Decode(&toolGroups); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return toolGroups, nil
+}
+
+// DeleteToolGroup deletes a tool group by name.
+func (c *Client) DeleteToolGroup(name string) error {
+	u, _ := c.constructAPIEndpoint("/tool-groups/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// CreateExtractionProfile creates a new extraction profile.
+func (c *Client) CreateExtractionProfile(profile *types.ExtractionProfile) (*types.ExtractionProfile, error) {
+	u, _ := c.constructAPIEndpoint("/extraction-profiles")
+	body, err := json.Marshal(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize extraction profile into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.ExtractionProfile
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListExtractionProfiles fetches all extraction profiles.
+func (c *Client) ListExtractionProfiles() ([]*types.ExtractionProfile, error) {
+	u, _ := c.constructAPIEndpoint("/extraction-profiles")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var extractionProfiles []*types.ExtractionProfile
+	if err := json.NewDecoder(resp.Body).Decode(&extractionProfiles); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return extractionProfiles, nil
+}
+
+// DeleteExtractionProfile deletes an extraction profile by name.
+func (c *Client) DeleteExtractionProfile(name string) error {
+	u, _ := c.constructAPIEndpoint("/extraction-profiles/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
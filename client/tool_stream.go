@@ -0,0 +1,123 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ToolStream is the result of a tool call, received progressively.
+// The content can be consumed chunk by chunk with Next or Chunks, or as a single stream of text by reading
+// the ToolStream (as an io.Reader, it only returns the text content and skips images, audio, etc).
+// Don't mix both styles on the same stream. The ToolStream must be closed once it is no longer needed.
+type ToolStream struct {
+	body    io.ReadCloser
+	dec     *json.Decoder
+	result  *types.ToolInvokeResult
+	err     error
+	pending string
+	// index of the content item that the last text chunk read belongs to, -1 before the first one
+	lastIndex int
+}
+
+// InvokeToolStream invokes a tool and returns its result as a stream of content chunks, so that large results
+// can be forwarded as they are received instead of being buffered in full.
+// Only an admin can impersonate other callers. If as is nil, the tool is invoked as the current user.
+func (c *Client) InvokeToolStream(name string, input map[string]any, as *types.Impersonation) (*ToolStream, error) {
+	req, err := c.newInvokeRequest(name, input, as)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", types.ToolInvokeStreamContentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to server failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Type") != types.ToolInvokeStreamContentType {
+		resp.Body.Close()
+		return nil, errors.New("the server does not support streaming tool results, upgrade it or use InvokeTool")
+	}
+	return &ToolStream{body: resp.Body, dec: json.NewDecoder(resp.Body), lastIndex: -1}, nil
+}
+
+// Next returns the next content chunk of the result.
+// It returns io.EOF once all the content has been received, after which Result is available.
+func (s *ToolStream) Next() (*types.ToolInvokeChunk, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var chunk types.ToolInvokeChunk
+	if err := s.dec.Decode(&chunk); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = fmt.Errorf("failed to read tool result: %w", err)
+		return nil, s.err
+	}
+	if chunk.Result != nil {
+		s.result = chunk.Result
+		s.err = io.EOF
+		return nil, s.err
+	}
+	return &chunk, nil
+}
+
+// Chunks returns an iterator over the remaining content chunks of the result.
+// The iteration stops after the last chunk, or after yielding an error.
+func (s *ToolStream) Chunks() iter.Seq2[*types.ToolInvokeChunk, error] {
+	return func(yield func(*types.ToolInvokeChunk, error) bool) {
+		for {
+			chunk, err := s.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Read reads the text content of the result. Consecutive text content items are separated by a newline.
+func (s *ToolStream) Read(p []byte) (int, error) {
+	for s.pending == "" {
+		chunk, err := s.Next()
+		if err != nil {
+			return 0, err
+		}
+		text, ok := chunk.Text()
+		if !ok {
+			continue
+		}
+		if s.lastIndex >= 0 && chunk.Index != s.lastIndex {
+			text = "\n" + text
+		}
+		s.lastIndex = chunk.Index
+		s.pending = text
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Result returns the rest of the result once all the content has been received (metadata, error flag and
+// structured content), nil before. Its Content is always empty.
+func (s *ToolStream) Result() *types.ToolInvokeResult {
+	return s.result
+}
+
+// Close releases the connection used by the stream.
+func (s *ToolStream) Close() error {
+	return s.body.Close()
+}
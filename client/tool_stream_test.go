@@ -0,0 +1,81 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestInvokeToolStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != types.ToolInvokeStreamContentType {
+			http.Error(w, "streaming not requested", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", types.ToolInvokeStreamContentType)
+		_, _ = io.WriteString(w, `{"index":0,"content":{"type":"text","text":"hello "}}
+{"index":0,"content":{"type":"text","text":"world"}}
+{"index":1,"content":{"type":"image","data":"aGk=","mimeType":"image/png"}}
+{"index":2,"content":{"type":"text","text":"bye"}}
+{"index":3,"result":{"isError":true,"content":null}}
+`)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	s, err := c.InvokeToolStream("fs__read", nil, nil)
+	if err != nil {
+		t.Fatalf("InvokeToolStream() error = %v", err)
+	}
+	text, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+	if string(text) != "hello world\nbye" {
+		t.Errorf("stream text = %q, want %q", text, "hello world\nbye")
+	}
+	if r := s.Result(); r == nil || !r.IsError {
+		t.Errorf("Result() = %+v, want an error result", r)
+	}
+	_ = s.Close()
+
+	s, err = c.InvokeToolStream("fs__read", nil, nil)
+	if err != nil {
+		t.Fatalf("InvokeToolStream() error = %v", err)
+	}
+	defer s.Close()
+	var indexes []int
+	for chunk, err := range s.Chunks() {
+		if err != nil {
+			t.Fatalf("Chunks() error = %v", err)
+		}
+		indexes = append(indexes, chunk.Index)
+	}
+	if len(indexes) != 4 || indexes[3] != 2 {
+		t.Errorf("Chunks() yielded indexes %v, want [0 0 1 2]", indexes)
+	}
+}
+
+func TestInvokeToolStreamTruncated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", types.ToolInvokeStreamContentType)
+		_, _ = io.WriteString(w, `{"index":0,"content":{"type":"text","text":"partial"}}`+"\n")
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "", &http.Client{})
+
+	s, err := c.InvokeToolStream("fs__read", nil, nil)
+	if err != nil {
+		t.Fatalf("InvokeToolStream() error = %v", err)
+	}
+	defer s.Close()
+	if _, err := io.ReadAll(s); err == nil {
+		t.Error("reading a stream without its final chunk succeeded, want error")
+	}
+	if s.Result() != nil {
+		t.Error("Result() is set for a truncated stream")
+	}
+}
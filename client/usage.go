@@ -0,0 +1,363 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// SetToolCost sets the pricing of a tool.
+func (c *Client) SetToolCost(input *types.SetToolCostInput) error {
+	u, _ := c.constructAPIEndpoint("/tools/cost")
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tool cost into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		if conflict := decodeVersionConflict(resp.StatusCode, body); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// CreateBudget creates a new budget.
+func (c *Client) CreateBudget(budget *types.Budget) (*types.Budget, error) {
+	u, _ := c.constructAPIEndpoint("/budgets")
+	body, err := json.Marshal(budget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize budget into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.Budget
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListBudgets fetches all budgets along with their spend in the current period.
+func (c *Client) ListBudgets() ([]*types.Budget, error) {
+	u, _ := c.constructAPIEndpoint("/budgets")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var budgets []*types.Budget
+	if err := json.NewDecoder(resp.Body).Decode(&budgets); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return budgets, nil
+}
+
+// DeleteBudget deletes a budget by name.
+func (c *Client) DeleteBudget(name string) error {
+	u, _ := c.constructAPIEndpoint("/budgets/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ListInvocations fetches the most recent tool invocations, newest first, optionally filtered by tool name.
+// If limit is zero, the server's default limit applies.
+func (c *Client) ListInvocations(tool string, limit int) ([]*types.ToolInvocation, error) {
+	u, _ := c.constructAPIEndpoint("/invocations")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	if tool != "" {
+		q.Add("tool", tool)
+	}
+	if limit != 0 {
+		q.Add("limit", strconv.Itoa(limit))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var invocations []*types.ToolInvocation
+	if err := json.NewDecoder(resp.Body).Decode(&invocations); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return invocations, nil
+}
+
+// TopTools fetches the most called tools within the given period (eg- "7d"), most called first.
+func (c *Client) TopTools(since string, limit int) ([]*types.ToolUsage, error) {
+	u, _ := c.constructAPIEndpoint("/usage/tools/top")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	if since != "" {
+		q.Add("since", since)
+	}
+	if limit != 0 {
+		q.Add("limit", strconv.Itoa(limit))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var usage []*types.ToolUsage
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return usage, nil
+}
+
+// UnusedTools fetches the tools that haven't been called within the given period (eg- "30d").
+func (c *Client) UnusedTools(since string) ([]*types.UnusedTool, error) {
+	u, _ := c.constructAPIEndpoint("/usage/tools/unused")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if since != "" {
+		q := req.URL.Query()
+		q.Add("since", since)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var unused []*types.UnusedTool
+	if err := json.NewDecoder(resp.Body).Decode(&unused); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return unused, nil
+}
+
+// MetricsSummary fetches the calls made within the given period (eg- "1d") aggregated by MCP server,
+// tool group and MCP client.
+func (c *Client) MetricsSummary(since string) (*types.MetricsSummary, error) {
+	u, _ := c.constructAPIEndpoint("/metrics/summary")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if since != "" {
+		q := req.URL.Query()
+		q.Add("since", since)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var summary types.MetricsSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &summary, nil
+}
+
+// CreateSLO creates a new SLO.
+func (c *Client) CreateSLO(slo *types.SLO) (*types.SLO, error) {
+	u, _ := c.constructAPIEndpoint("/slos")
+	body, err := json.Marshal(slo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize SLO into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.SLO
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListSLOs fetches all SLOs along with their compliance in the current window.
+func (c *Client) ListSLOs() ([]*types.SLO, error) {
+	u, _ := c.constructAPIEndpoint("/slos")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var slos []*types.SLO
+	if err := json.NewDecoder(resp.Body).Decode(&slos); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return slos, nil
+}
+
+// DeleteSLO deletes an SLO by name.
+func (c *Client) DeleteSLO(name string) error {
+	u, _ := c.constructAPIEndpoint("/slos/" + name)
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ClientBreakdown fetches the breakdown of the MCP proxy sessions initialized within the given period (eg- "30d")
+// by client application, protocol version & capability.
+func (c *Client) ClientBreakdown(since string) (*types.ClientBreakdown, error) {
+	u, _ := c.constructAPIEndpoint("/usage/clients")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if since != "" {
+		q := req.URL.Query()
+		q.Add("since", since)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var breakdown types.ClientBreakdown
+	if err := json.NewDecoder(resp.Body).Decode(&breakdown); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &breakdown, nil
+}
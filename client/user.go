@@ -96,11 +96,11 @@ func (c *Client) ListUsers() ([]*types.User, error) {
 func (c *Client) Whoami(accessToken string) (*types.User, error) {
 	u, _ := c.constructAPIEndpoint("/users/whoami")
 
-	req, err := c.newRequest(http.MethodGet, u, nil)
+	// the stored access token is never loaded, the request is authenticated with the supplied one
+	req, err := newRequestWithToken(http.MethodGet, u, nil, accessToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestDeleteMcpClientVersionConflict(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error": "client cursor was modified concurrently", "kind": "client", "name": "cursor", "expected_version": 1, "current_version": 2}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", &http.Client{})
+	err := c.DeleteMcpClient("cursor", 1)
+	var conflict *types.VersionConflict
+	if !errors.As(err, &conflict) {
+		t.Fatalf("DeleteMcpClient() error = %v, want a version conflict", err)
+	}
+	if conflict.CurrentVersion != 2 {
+		t.Errorf("conflict current version = %d, want 2", conflict.CurrentVersion)
+	}
+	if query != "version=1" {
+		t.Errorf("request query = %q, want version=1", query)
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// DefaultWebhookTolerance is the default maximum age of a webhook payload accepted by VerifyWebhookSignature
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// ErrInvalidWebhookSignature is returned when a webhook payload's signature cannot be verified
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// VerifyWebhookSignature verifies that a webhook payload was sent by MCPJungle.
+// body is the raw request body and header is the value of the types.WebhookSignatureHeader header.
+// secrets contains the webhook key secrets known to the receiver, the payload is trusted if it was signed
+// with any of them. Keeping both the old and the new secret during a key rotation avoids rejecting webhooks.
+// Payloads signed more than tolerance ago are rejected to prevent replay attacks.
+// A tolerance of 0 means DefaultWebhookTolerance.
+func VerifyWebhookSignature(body []byte, header string, secrets []string, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed signature header", ErrInvalidWebhookSignature)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp '%s'", ErrInvalidWebhookSignature, timestamp)
+	}
+	age := time.Since(time.Unix(ts, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp is outside the tolerance of %s", ErrInvalidWebhookSignature, tolerance)
+	}
+
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		expected := mac.Sum(nil)
+		for _, s := range signatures {
+			got, err := hex.DecodeString(s)
+			if err == nil && hmac.Equal(got, expected) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: no signature matches the given secrets", ErrInvalidWebhookSignature)
+}
+
+// RotateWebhookKey creates a new key for signing webhook payloads.
+// The previous keys remain valid for the given grace period (eg- "24h").
+// The returned key contains its secret, which cannot be retrieved again.
+func (c *Client) RotateWebhookKey(gracePeriod string) (*types.WebhookKey, error) {
+	u, _ := c.constructAPIEndpoint("/webhook-keys")
+	body, err := json.Marshal(&types.RotateWebhookKeyInput{GracePeriod: gracePeriod})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize request into JSON: %w", err)
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var key types.WebhookKey
+	if err := json.NewDecoder(resp.Body).Decode(&key); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &key, nil
+}
+
+// ListWebhookKeys fetches the keys currently used for signing webhook payloads.
+func (c *Client) ListWebhookKeys() ([]*types.WebhookKey, error) {
+	u, _ := c.constructAPIEndpoint("/webhook-keys")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var keys []*types.WebhookKey
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return keys, nil
+}
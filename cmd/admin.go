@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adminPruneCmdInvocationsOlderThan   string
+	adminPruneCmdInvocationsMaxRows     int
+	adminPruneCmdNotificationsOlderThan string
+	adminPruneCmdNotificationsMaxRows   int
+
+	adminPurgeSubjectCmdClient string
+	adminPurgeSubjectCmdUser   string
+
+	adminResetTokenCmdUsername string
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Maintenance operations on the registry",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "20",
+	},
+}
+
+var adminPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old tool invocation records and notifications",
+	Long: "Delete old rows from the history tables now, instead of waiting for the server's background pruner.\n" +
+		"By default, the retention policies configured on the server are applied. The flags replace them for this run.\n" +
+		"Invocations made in the current month (which count towards budgets) and unread notifications are never deleted.\n" +
+		"\neg- mcpjungle admin prune --invocations-older-than 90d --notifications-max-rows 1000",
+	RunE: runAdminPrune,
+}
+
+var adminPurgeSubjectCmd = &cobra.Command{
+	Use:   "purge-subject",
+	Short: "Delete all stored data about an MCP client or user",
+	Long: "Delete the tool invocation history, server proposals, notifications and locally stored audit events " +
+		"associated with an MCP client or user, eg- to honor a data deletion request.\n" +
+		"Calls a user made while impersonating a client and proposals they reviewed are kept, without their username.\n" +
+		"The report verifies that no record is left and lists the data that must be purged outside of mcpjungle.\n" +
+		"\neg- mcpjungle admin purge-subject --user alice",
+	RunE: runAdminPurgeSubject,
+}
+
+var adminResetTokenCmd = &cobra.Command{
+	Use:     "reset-token",
+	Aliases: []string{"reset-password"},
+	Short:   "Issue a new access token for the admin user (recovery)",
+	Long: "Replace the access token of the admin user with a new one, eg- when the admin token was lost.\n" +
+		"This command does not talk to the server, it connects directly to the database configured in " + DBUrlEnvVar +
+		" (or the .env file of the current directory) like the server does, so it must be run by someone with access to the database.\n" +
+		"The old token stops working immediately.\n" +
+		"\neg- mcpjungle admin reset-token --username admin",
+	RunE: runAdminResetToken,
+}
+
+func init() {
+	adminPruneCmd.Flags().StringVar(
+		&adminPruneCmdInvocationsOlderThan, "invocations-older-than", "", "Delete tool invocation records older than this (eg- 90d)",
+	)
+	adminPruneCmd.Flags().IntVar(
+		&adminPruneCmdInvocationsMaxRows, "invocations-max-rows", 0, "Only keep this many of the most recent tool invocation records",
+	)
+	adminPruneCmd.Flags().StringVar(
+		&adminPruneCmdNotificationsOlderThan, "notifications-older-than", "", "Delete read notifications older than this (eg- 30d)",
+	)
+	adminPruneCmd.Flags().IntVar(
+		&adminPruneCmdNotificationsMaxRows, "notifications-max-rows", 0, "Only keep this many of the most recent read notifications",
+	)
+	adminCmd.AddCommand(adminPruneCmd)
+
+	adminPurgeSubjectCmd.Flags().StringVar(
+		&adminPurgeSubjectCmdClient, "client", "", "Name of the MCP client whose data is purged",
+	)
+	adminPurgeSubjectCmd.Flags().StringVar(
+		&adminPurgeSubjectCmdUser, "user", "", "Username of the user whose data is purged",
+	)
+	adminPurgeSubjectCmd.MarkFlagsOneRequired("client", "user")
+	adminPurgeSubjectCmd.MarkFlagsMutuallyExclusive("client", "user")
+	adminCmd.AddCommand(adminPurgeSubjectCmd)
+
+	adminResetTokenCmd.Flags().StringVar(
+		&adminResetTokenCmdUsername, "username", "", "Username of the user whose token is reset (default: the admin user)",
+	)
+	adminCmd.AddCommand(adminResetTokenCmd)
+	rootCmd.AddCommand(adminCmd)
+}
+
+func runAdminPrune(cmd *cobra.Command, args []string) error {
+	input := &types.PruneInput{Policies: make(map[string]types.RetentionPolicy)}
+	if cmd.Flags().Changed("invocations-older-than") || cmd.Flags().Changed("invocations-max-rows") {
+		input.Policies[types.RetentionTableInvocations] = types.RetentionPolicy{
+			MaxAge:  adminPruneCmdInvocationsOlderThan,
+			MaxRows: adminPruneCmdInvocationsMaxRows,
+		}
+	}
+	if cmd.Flags().Changed("notifications-older-than") || cmd.Flags().Changed("notifications-max-rows") {
+		input.Policies[types.RetentionTableNotifications] = types.RetentionPolicy{
+			MaxAge:  adminPruneCmdNotificationsOlderThan,
+			MaxRows: adminPruneCmdNotificationsMaxRows,
+		}
+	}
+
+	results, err := apiClient.Prune(input)
+	if err != nil {
+		return fmt.Errorf("failed to prune history: %w", err)
+	}
+
+	return renderOutput(cmd, results, func() error {
+		if len(results) == 0 {
+			cmd.Println("No retention policy is configured, nothing was pruned")
+			return nil
+		}
+		for _, r := range results {
+			cmd.Printf("%s: deleted %d rows\n", r.Table, r.Deleted)
+		}
+		return nil
+	})
+}
+
+func runAdminPurgeSubject(cmd *cobra.Command, args []string) error {
+	subject := &types.DataSubject{Client: adminPurgeSubjectCmdClient, User: adminPurgeSubjectCmdUser}
+	report, err := apiClient.PurgeSubject(subject)
+	if err != nil {
+		return fmt.Errorf("failed to purge data of %s: %w", subject, err)
+	}
+
+	return renderOutput(cmd, report, func() error {
+		for _, r := range report.Results {
+			cmd.Printf("%s: deleted %d, anonymized %d, remaining %d", r.Store, r.Deleted, r.Anonymized, r.Remaining)
+			if r.Error != "" {
+				cmd.Printf(" (error: %s)", r.Error)
+			}
+			cmd.Println()
+		}
+		if report.Verified {
+			cmd.Printf("\nVerified: no stored data about the %s is left\n", subject)
+		} else {
+			cmd.Printf("\nNOT verified: some data about the %s could not be purged, see above\n", subject)
+		}
+		if len(report.Notes) > 0 {
+			cmd.Println("\nNotes:")
+			for _, n := range report.Notes {
+				cmd.Printf("  - %s\n", n)
+			}
+		}
+		return nil
+	})
+}
+
+func runAdminResetToken(cmd *cobra.Command, args []string) error {
+	_ = godotenv.Load()
+
+	dbOpts, err := dbOptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	conn, err := db.NewDBConnection(os.Getenv(DBUrlEnvVar), dbOpts)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	if sqlDB, err := conn.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	u, err := user.NewUserService(conn).ResetAccessToken(adminResetTokenCmdUsername)
+	if err != nil {
+		return fmt.Errorf("failed to reset the access token: %w", err)
+	}
+
+	cmd.Printf("Issued a new access token for user %s, the old token no longer works.\n\n", u.Username)
+	cmd.Printf("Access token: %s\n\n", u.AccessToken)
+	cmd.Println("Store it safely, then log in with: mcpjungle login <access token>")
+	return nil
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage client contexts for multiple registries",
+	Long: "A context is a named profile that stores the URL of a MCPJungle registry along with your access token for it.\n" +
+		"Contexts let you switch between multiple gateways (eg- dev, staging & prod) without passing the\n" +
+		"--registry flag or exporting environment variables every time.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "7",
+	},
+}
+
+var setContextCmd = &cobra.Command{
+	Use:   "set-context <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create or update a context",
+	RunE:  runSetContext,
+}
+
+var useContextCmd = &cobra.Command{
+	Use:   "use-context <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Set the current context",
+	RunE:  runUseContext,
+}
+
+var getContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List all contexts",
+	RunE:  runGetContexts,
+}
+
+var currentContextCmd = &cobra.Command{
+	Use:   "current-context",
+	Short: "Display the current context",
+	RunE:  runCurrentContext,
+}
+
+var deleteContextCmd = &cobra.Command{
+	Use:   "delete-context <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a context",
+	RunE:  runDeleteContext,
+}
+
+var (
+	setContextCmdRegistry    string
+	setContextCmdAccessToken string
+)
+
+func init() {
+	setContextCmd.Flags().StringVar(
+		&setContextCmdRegistry,
+		"registry-url",
+		"",
+		"Base URL of the MCPJungle registry server for this context",
+	)
+	setContextCmd.Flags().StringVar(
+		&setContextCmdAccessToken,
+		"token",
+		"",
+		"Access token to use with this registry.\n"+
+			"You can also run 'mcpjungle login --context <name>' later to store the token.",
+	)
+
+	configCmd.AddCommand(setContextCmd)
+	configCmd.AddCommand(useContextCmd)
+	configCmd.AddCommand(getContextsCmd)
+	configCmd.AddCommand(currentContextCmd)
+	configCmd.AddCommand(deleteContextCmd)
+
+	rootCmd.AddCommand(configCmd)
+}
+
+func runSetContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cfg := config.Load()
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]*config.Context)
+	}
+
+	ctx, exists := cfg.Contexts[name]
+	if !exists {
+		if setContextCmdRegistry == "" {
+			return fmt.Errorf("required flag \"registry-url\" not set for new context '%s'", name)
+		}
+		ctx = &config.Context{}
+		cfg.Contexts[name] = ctx
+	}
+	if setContextCmdRegistry != "" {
+		ctx.RegistryURL = setContextCmdRegistry
+	}
+
+	var storage config.CredentialStorage
+	if setContextCmdAccessToken != "" {
+		// the token is kept out of the (plaintext) client configuration, scoped to the context
+		var err error
+		storage, err = config.SaveCredential(config.CredentialKey(name, ctx.RegistryURL), setContextCmdAccessToken)
+		if err != nil {
+			return fmt.Errorf("failed to store access token: %w", err)
+		}
+		ctx.AccessToken = ""
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save client configuration: %w", err)
+	}
+	if storage != "" {
+		cmd.Printf("Access token of context '%s' saved to your %s\n", name, storage)
+	}
+	if exists {
+		cmd.Printf("Context '%s' updated\n", name)
+	} else {
+		cmd.Printf("Context '%s' created\n", name)
+		cmd.Printf("Run 'mcpjungle config use-context %s' to start using it\n", name)
+	}
+	return nil
+}
+
+func runUseContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cfg := config.Load()
+	if _, ok := cfg.Contexts[name]; !ok {
+		return fmt.Errorf("context '%s' does not exist", name)
+	}
+	cfg.CurrentContext = name
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save client configuration: %w", err)
+	}
+	cmd.Printf("Switched to context '%s'\n", name)
+	return nil
+}
+
+func runGetContexts(cmd *cobra.Command, args []string) error {
+	cfg := config.Load()
+
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type contextView struct {
+		Name        string `json:"name"`
+		RegistryURL string `json:"registry_url"`
+		Current     bool   `json:"current"`
+		LoggedIn    bool   `json:"logged_in"`
+	}
+	views := make([]contextView, len(names))
+	for i, name := range names {
+		views[i] = contextView{
+			Name:        name,
+			RegistryURL: cfg.Contexts[name].RegistryURL,
+			Current:     name == cfg.CurrentContext,
+			LoggedIn:    contextLoggedIn(name, cfg.Contexts[name]),
+		}
+	}
+
+	return renderOutput(cmd, views, func() error {
+		if len(views) == 0 {
+			cmd.Println("There are no contexts in the client configuration")
+			return nil
+		}
+		for _, v := range views {
+			marker := " "
+			if v.Current {
+				marker = "*"
+			}
+			cmd.Printf("%s %s  %s\n", marker, v.Name, v.RegistryURL)
+		}
+		return nil
+	})
+}
+
+func runCurrentContext(cmd *cobra.Command, args []string) error {
+	cfg := config.Load()
+	if cfg.CurrentContext == "" {
+		cmd.Println("No context is in use")
+		return nil
+	}
+	cmd.Println(cfg.CurrentContext)
+	return nil
+}
+
+func runDeleteContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cfg := config.Load()
+	if _, ok := cfg.Contexts[name]; !ok {
+		return fmt.Errorf("context '%s' does not exist", name)
+	}
+	delete(cfg.Contexts, name)
+	if cfg.CurrentContext == name {
+		cfg.CurrentContext = ""
+	}
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save client configuration: %w", err)
+	}
+	cmd.Printf("Context '%s' deleted\n", name)
+	return nil
+}
+
+// contextLoggedIn returns true if an access token is available for the client context.
+func contextLoggedIn(name string, ctx *config.Context) bool {
+	return ctx.AccessToken != "" ||
+		config.HasCredential(config.CredentialKey(name, ctx.RegistryURL)) ||
+		config.HasCredential(ctx.RegistryURL)
+}
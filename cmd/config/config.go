@@ -1,9 +1,11 @@
 package config
 
 import (
-	"gopkg.in/yaml.v3"
+	"fmt"
 	"os"
 	"path/filepath"
+
+	"gopkg.in/yaml.v3"
 )
 
 const ClientConfigFileName = ".mcpjungle.conf"
@@ -11,7 +13,58 @@ const ClientConfigFileName = ".mcpjungle.conf"
 // ClientConfig represents the MCPJungle client configuration stored in the user's home directory.
 // It can contain configuration for both a standard user and an admin user.
 type ClientConfig struct {
-	AccessToken string `yaml:"access_token"`
+	// AccessToken is the token used when no context is in use.
+	// This keeps the config compatible with setups that only talk to a single registry.
+	AccessToken string `yaml:"access_token,omitempty"`
+
+	// CurrentContext is the name of the context used by default when the --context flag is not supplied.
+	CurrentContext string `yaml:"current_context,omitempty"`
+
+	// Contexts contains named profiles, each pointing to a different MCPJungle registry.
+	// This is useful when managing multiple gateways (eg- dev, staging & prod).
+	Contexts map[string]*Context `yaml:"contexts,omitempty"`
+}
+
+// Context is a named profile that holds everything the CLI needs to talk to one MCPJungle registry.
+type Context struct {
+	// RegistryURL is the base URL of the MCPJungle registry server
+	RegistryURL string `yaml:"registry_url"`
+
+	// AccessToken is the token used to authenticate with this registry
+	AccessToken string `yaml:"access_token,omitempty"`
+}
+
+// GetContext returns the context with the given name.
+// If name is empty, the current context is returned.
+// If no context is selected at all, nil is returned without an error.
+func (c *ClientConfig) GetContext(name string) (*Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+	if name == "" {
+		return nil, nil
+	}
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context '%s' does not exist", name)
+	}
+	return ctx, nil
+}
+
+// SetAccessToken stores the access token in the given context.
+// If name is empty, the token is stored in the current context or, if no context is in use,
+// at the top level of the configuration.
+func (c *ClientConfig) SetAccessToken(name, token string) error {
+	ctx, err := c.GetContext(name)
+	if err != nil {
+		return err
+	}
+	if ctx == nil {
+		c.AccessToken = token
+		return nil
+	}
+	ctx.AccessToken = token
+	return nil
 }
 
 // AbsPath returns the absolute path to the client configuration file.
@@ -32,11 +85,16 @@ func Save(c *ClientConfig) error {
 	if err != nil {
 		return err
 	}
-	f, err := os.Create(path)
+	// the config may contain access tokens, so only the current user should be able to access it
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
+	// the mode only applies when the file is created, tighten the permissions of an existing file too
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
 
 	encoder := yaml.NewEncoder(f)
 	defer encoder.Close()
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveRestrictsPermissions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// an existing config readable by others is restricted to the user, as it may contain access tokens
+	path := filepath.Join(home, ClientConfigFileName)
+	if err := os.WriteFile(path, []byte("current_context: dev\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ClientConfig{Contexts: map[string]*Context{"prod": {RegistryURL: "https://mcpjungle.example.com"}}}
+	if err := cfg.SetAccessToken("prod", "mcpj_secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("config file has permissions %o, want 600", perm)
+	}
+	loaded := Load()
+	if ctx, err := loaded.GetContext("prod"); err != nil || ctx.AccessToken != "mcpj_secret" {
+		t.Errorf("GetContext() = %v, %v, want the saved access token", ctx, err)
+	}
+	if loaded.CurrentContext != "" {
+		t.Errorf("CurrentContext = %q, want the previous content of the file to be replaced", loaded.CurrentContext)
+	}
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"gopkg.in/yaml.v3"
+)
+
+// CredentialsFileName is the file used to store access tokens when the OS keychain is not available
+const CredentialsFileName = ".mcpjungle.credentials"
+
+// keyringService is the service name under which access tokens are stored in the OS keychain
+const keyringService = "mcpjungle"
+
+// CredentialsPassphraseEnvVar holds the passphrase that encrypts the access tokens stored in the credentials file.
+// Without it, the tokens are stored in plaintext (readable only by the user).
+const CredentialsPassphraseEnvVar = "MCPJUNGLE_CREDENTIALS_PASSPHRASE"
+
+// encryptedTokenPrefix marks the tokens of the credentials file that are encrypted with a passphrase
+const encryptedTokenPrefix = "enc:v1:"
+
+// passphraseIterations is the number of PBKDF2 iterations that derive the encryption key from the passphrase.
+// It is paid by every command that loads an encrypted token, so it is kept below a second on slow machines.
+const passphraseIterations = 200_000
+
+// ErrPassphraseRequired is returned when loading an access token encrypted with a passphrase that wasn't supplied.
+var ErrPassphraseRequired = errors.New(
+	"the access token is encrypted, supply its passphrase via the " + CredentialsPassphraseEnvVar + " environment variable",
+)
+
+// passphrase encrypts the access tokens stored in the credentials file, see SetPassphrase
+var passphrase = os.Getenv(CredentialsPassphraseEnvVar)
+
+// SetPassphrase sets the passphrase that encrypts the access tokens stored in the credentials file,
+// eg- after prompting the user for it. It overrides CredentialsPassphraseEnvVar.
+func SetPassphrase(p string) {
+	passphrase = p
+}
+
+// HasPassphrase returns true if a passphrase encrypts the access tokens stored in the credentials file.
+func HasPassphrase() bool {
+	return passphrase != ""
+}
+
+// CredentialKey returns the key under which the access token for a registry is stored.
+// Tokens obtained in a client context are scoped to it, so that several contexts pointing to the same
+// registry (eg- as an admin and as a standard user) don't share a token.
+func CredentialKey(contextName, registryURL string) string {
+	if contextName == "" {
+		return registryURL
+	}
+	return contextName + "@" + registryURL
+}
+
+// CredentialStorage describes where an access token was stored
+type CredentialStorage string
+
+const (
+	CredentialStorageKeychain CredentialStorage = "OS keychain"
+	CredentialStorageFile     CredentialStorage = "credentials file"
+)
+
+// SaveCredential stores the access token under the given key (see CredentialKey).
+// The token is stored in the OS keychain if one is available.
+// Otherwise, it falls back to a credentials file in the user's home directory that only the user can read,
+// in which the token is encrypted if a passphrase is set.
+func SaveCredential(key, token string) (CredentialStorage, error) {
+	if err := keyring.Set(keyringService, key, token); err == nil {
+		return CredentialStorageKeychain, nil
+	}
+
+	creds, err := loadCredentialsFile()
+	if err != nil {
+		return "", err
+	}
+	if passphrase != "" {
+		if token, err = encryptToken(token, passphrase); err != nil {
+			return "", fmt.Errorf("failed to encrypt access token: %w", err)
+		}
+	}
+	creds[key] = token
+	if err := saveCredentialsFile(creds); err != nil {
+		return "", fmt.Errorf("failed to save credentials file: %w", err)
+	}
+	return CredentialStorageFile, nil
+}
+
+// LoadCredential returns the access token stored under the given key (see CredentialKey).
+// If no token is stored, an empty string is returned without an error.
+// It returns ErrPassphraseRequired if the token is encrypted and no passphrase is set.
+func LoadCredential(key string) (string, error) {
+	token, err := keyring.Get(keyringService, key)
+	if err == nil {
+		return token, nil
+	}
+
+	creds, err := loadCredentialsFile()
+	if err != nil {
+		return "", err
+	}
+	token = creds[key]
+	if !strings.HasPrefix(token, encryptedTokenPrefix) {
+		return token, nil
+	}
+	if passphrase == "" {
+		return "", ErrPassphraseRequired
+	}
+	return decryptToken(token, passphrase)
+}
+
+// HasCredential returns true if an access token is stored under the given key, without decrypting it.
+func HasCredential(key string) bool {
+	if _, err := keyring.Get(keyringService, key); err == nil {
+		return true
+	}
+	creds, err := loadCredentialsFile()
+	return err == nil && creds[key] != ""
+}
+
+// DeleteCredential wipes the access token stored under the given key from
+// both the OS keychain and the credentials file.
+// It is an idempotent operation.
+func DeleteCredential(key string) error {
+	// the keychain may not be available on this machine or may not contain the token,
+	// neither of which is an error for logout
+	_ = keyring.Delete(keyringService, key)
+
+	creds, err := loadCredentialsFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := creds[key]; !ok {
+		return nil
+	}
+	delete(creds, key)
+	return saveCredentialsFile(creds)
+}
+
+// encryptToken encrypts an access token with AES-256-GCM, using a key derived from the passphrase with PBKDF2.
+// The result contains the random salt & nonce, so encrypting the same token twice gives different results.
+func encryptToken(token, passphrase string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	gcm, err := passphraseCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := append(salt, nonce...)
+	data = gcm.Seal(data, nonce, []byte(token), nil)
+	return encryptedTokenPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decryptToken decrypts an access token encrypted by encryptToken.
+func decryptToken(encrypted, passphrase string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedTokenPrefix))
+	if err != nil || len(data) < 16 {
+		return "", errors.New("the encrypted access token is corrupted")
+	}
+	gcm, err := passphraseCipher(passphrase, data[:16])
+	if err != nil {
+		return "", err
+	}
+	data = data[16:]
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("the encrypted access token is corrupted")
+	}
+	token, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt the access token, the passphrase is wrong")
+	}
+	return string(token), nil
+}
+
+// passphraseCipher returns the AES-256-GCM cipher keyed by the passphrase and salt.
+func passphraseCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, passphraseIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// credentialsFilePath returns the absolute path to the credentials file
+func credentialsFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, CredentialsFileName), nil
+}
+
+// loadCredentialsFile reads the credentials file, which maps registry URLs to access tokens.
+// A missing file is treated as an empty set of credentials.
+func loadCredentialsFile() (map[string]string, error) {
+	creds := make(map[string]string)
+
+	path, err := credentialsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return creds, nil
+		}
+		return nil, fmt.Errorf("failed to read credentials file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// saveCredentialsFile writes the credentials file with permissions that only allow the current user to access it.
+func saveCredentialsFile(creds map[string]string) error {
+	path, err := credentialsFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile does not change the permissions of an existing file, so enforce them explicitly
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestEncryptToken(t *testing.T) {
+	encrypted, err := encryptToken("mcpj_secret", "correct horse")
+	if err != nil {
+		t.Fatalf("encryptToken() error = %v", err)
+	}
+	if !strings.HasPrefix(encrypted, encryptedTokenPrefix) || strings.Contains(encrypted, "mcpj_secret") {
+		t.Fatalf("encryptToken() = %q, want an encrypted token", encrypted)
+	}
+	again, _ := encryptToken("mcpj_secret", "correct horse")
+	if again == encrypted {
+		t.Error("encrypting a token twice gave the same result, want a random salt & nonce")
+	}
+
+	token, err := decryptToken(encrypted, "correct horse")
+	if err != nil || token != "mcpj_secret" {
+		t.Errorf("decryptToken() = %q, %v, want mcpj_secret", token, err)
+	}
+	if _, err := decryptToken(encrypted, "wrong"); err == nil {
+		t.Error("decryptToken() succeeded with a wrong passphrase")
+	}
+}
+
+func TestCredentialKey(t *testing.T) {
+	if got := CredentialKey("", "http://localhost:8080"); got != "http://localhost:8080" {
+		t.Errorf("CredentialKey() = %q, want the registry URL when no context is in use", got)
+	}
+	if got := CredentialKey("prod", "http://localhost:8080"); got != "prod@http://localhost:8080" {
+		t.Errorf("CredentialKey() = %q, want the key scoped to the context", got)
+	}
+}
+
+func TestSaveCredentialToFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	keyring.MockInitWithError(errors.New("no keychain available"))
+	t.Cleanup(func() { SetPassphrase("") })
+
+	// an existing credentials file readable by others is restricted to the user
+	path := filepath.Join(home, CredentialsFileName)
+	if err := os.WriteFile(path, []byte("http://old:8080: mcpj_old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	storage, err := SaveCredential("http://localhost:8080", "mcpj_secret")
+	if err != nil || storage != CredentialStorageFile {
+		t.Fatalf("SaveCredential() = %q, %v, want the credentials file", storage, err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("credentials file has permissions %o, want 600", perm)
+	}
+	for key, want := range map[string]string{"http://localhost:8080": "mcpj_secret", "http://old:8080": "mcpj_old"} {
+		if token, err := LoadCredential(key); err != nil || token != want {
+			t.Errorf("LoadCredential(%s) = %q, %v, want %q", key, token, err, want)
+		}
+	}
+
+	// with a passphrase, the token is encrypted in the file
+	SetPassphrase("correct horse")
+	if _, err := SaveCredential("prod@http://localhost:8080", "mcpj_prod"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "mcpj_prod") {
+		t.Error("the credentials file contains the access token in plaintext despite the passphrase")
+	}
+	if token, err := LoadCredential("prod@http://localhost:8080"); err != nil || token != "mcpj_prod" {
+		t.Errorf("LoadCredential() = %q, %v, want mcpj_prod", token, err)
+	}
+	SetPassphrase("")
+	if _, err := LoadCredential("prod@http://localhost:8080"); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("LoadCredential() error = %v, want ErrPassphraseRequired", err)
+	}
+
+	if err := DeleteCredential("http://localhost:8080"); err != nil {
+		t.Fatal(err)
+	}
+	if HasCredential("http://localhost:8080") {
+		t.Error("the access token is still stored after DeleteCredential")
+	}
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	connectCmdToken      string
+	connectCmdServerName string
+	connectCmdConfigPath string
+	connectCmdWrite      bool
+)
+
+// connectApp describes how an MCP client application is configured to talk to an MCP server
+type connectApp struct {
+	// serversKey is the top-level key in the app's configuration file that holds the MCP servers
+	serversKey string
+	// defaultConfigPath returns the default location of the app's MCP configuration file
+	defaultConfigPath func() (string, error)
+	// serverEntry returns the configuration entry that points the app at mcpjungle
+	serverEntry func(mcpURL, token string) map[string]any
+}
+
+var connectApps = map[string]connectApp{
+	"claude": {
+		serversKey:        "mcpServers",
+		defaultConfigPath: claudeDesktopConfigPath,
+		serverEntry: func(mcpURL, token string) map[string]any {
+			// Claude Desktop only supports stdio servers, so it connects to mcpjungle via the mcp-remote bridge
+			args := []any{"mcp-remote", mcpURL}
+			if strings.HasPrefix(mcpURL, "http://") {
+				args = append(args, "--allow-http")
+			}
+			entry := map[string]any{"command": "npx", "args": args}
+			if token != "" {
+				// the header value is passed via an env var because Claude mangles args containing spaces
+				entry["args"] = append(args, "--header", "Authorization:${AUTH_HEADER}")
+				entry["env"] = map[string]any{"AUTH_HEADER": "Bearer " + token}
+			}
+			return entry
+		},
+	},
+	"cursor": {
+		serversKey:        "mcpServers",
+		defaultConfigPath: homeConfigPath(".cursor", "mcp.json"),
+		serverEntry: func(mcpURL, token string) map[string]any {
+			return withAuthHeader(map[string]any{"url": mcpURL}, token)
+		},
+	},
+	"vscode": {
+		serversKey: "servers",
+		defaultConfigPath: func() (string, error) {
+			// VS Code reads MCP servers from the workspace configuration
+			return filepath.Join(".vscode", "mcp.json"), nil
+		},
+		serverEntry: func(mcpURL, token string) map[string]any {
+			return withAuthHeader(map[string]any{"type": "http", "url": mcpURL}, token)
+		},
+	},
+	"windsurf": {
+		serversKey:        "mcpServers",
+		defaultConfigPath: homeConfigPath(".codeium", "windsurf", "mcp_config.json"),
+		serverEntry: func(mcpURL, token string) map[string]any {
+			return withAuthHeader(map[string]any{"serverUrl": mcpURL}, token)
+		},
+	},
+}
+
+var connectCmd = &cobra.Command{
+	Use:       "connect <claude|cursor|vscode|windsurf>",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"claude", "cursor", "vscode", "windsurf"},
+	Short:     "Generate the configuration to connect an MCP client app to MCPJungle",
+	Long: "Generate the configuration snippet that connects an MCP client application to this MCPJungle's /mcp endpoint.\n" +
+		"By default, the snippet is printed so you can paste it into the app's configuration file.\n" +
+		"Use --write to add it directly to the app's configuration file instead. Other servers configured in the file are left untouched.\n" +
+		"\nIn production mode, supply the access token of the MCP client (see 'mcpjungle create mcp-client') using --token.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupBasic),
+		"order": "3",
+	},
+	RunE: runConnect,
+}
+
+func init() {
+	connectCmd.Flags().StringVar(
+		&connectCmdToken,
+		"token",
+		"",
+		"Access token of the MCP client, sent by the app in the Authorization header",
+	)
+	connectCmd.Flags().StringVar(
+		&connectCmdServerName,
+		"name",
+		"mcpjungle",
+		"Name under which MCPJungle is added to the app's MCP servers",
+	)
+	connectCmd.Flags().StringVar(
+		&connectCmdConfigPath,
+		"config-path",
+		"",
+		"Path to the app's configuration file (defaults to the app's standard location)",
+	)
+	connectCmd.Flags().BoolVar(
+		&connectCmdWrite,
+		"write",
+		false,
+		"Add the configuration to the app's configuration file instead of printing it",
+	)
+
+	rootCmd.AddCommand(connectCmd)
+}
+
+func runConnect(cmd *cobra.Command, args []string) error {
+	app := connectApps[args[0]]
+	mcpURL := strings.TrimSuffix(activeRegistryURL, "/") + "/mcp"
+	entry := app.serverEntry(mcpURL, connectCmdToken)
+
+	if !connectCmdWrite {
+		snippet := map[string]any{
+			app.serversKey: map[string]any{connectCmdServerName: entry},
+		}
+		b, err := json.MarshalIndent(snippet, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize configuration: %w", err)
+		}
+		cmd.Println(string(b))
+		return nil
+	}
+
+	path := connectCmdConfigPath
+	if path == "" {
+		var err error
+		if path, err = app.defaultConfigPath(); err != nil {
+			return err
+		}
+	}
+	if err := patchAppConfig(path, app.serversKey, connectCmdServerName, entry); err != nil {
+		return err
+	}
+	cmd.Printf("Added %s to %s\n", connectCmdServerName, path)
+	cmd.Printf("Restart %s to pick up the new configuration\n", args[0])
+	return nil
+}
+
+// patchAppConfig adds (or replaces) the named server entry in the app's JSON configuration file.
+// The file is created if it doesn't exist. All other content of the file is preserved.
+func patchAppConfig(path, serversKey, name string, entry map[string]any) error {
+	conf := make(map[string]any)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &conf); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	servers, ok := conf[serversKey].(map[string]any)
+	if !ok {
+		if _, exists := conf[serversKey]; exists {
+			return fmt.Errorf("unexpected format of '%s' in %s: expected an object", serversKey, path)
+		}
+		servers = make(map[string]any)
+	}
+	servers[name] = entry
+	conf[serversKey] = servers
+
+	out, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize configuration: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	// the file may contain access tokens, so keep it private to the user
+	if err := os.WriteFile(path, append(out, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// withAuthHeader adds the Authorization header to a server entry if a token is supplied.
+func withAuthHeader(entry map[string]any, token string) map[string]any {
+	if token != "" {
+		entry["headers"] = map[string]any{"Authorization": "Bearer " + token}
+	}
+	return entry
+}
+
+// homeConfigPath returns a function that resolves the given path relative to the user's home directory.
+func homeConfigPath(elem ...string) func() (string, error) {
+	return func() (string, error) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		return filepath.Join(append([]string{home}, elem...)...), nil
+	}
+}
+
+// claudeDesktopConfigPath returns the location of Claude Desktop's configuration file on the current OS.
+func claudeDesktopConfigPath() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return homeConfigPath("Library", "Application Support", "Claude", "claude_desktop_config.json")()
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "Claude", "claude_desktop_config.json"), nil
+	default:
+		return homeConfigPath(".config", "Claude", "claude_desktop_config.json")()
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPatchAppConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	existing := `{"theme": "dark", "mcpServers": {"other": {"url": "http://other/mcp"}}}`
+	if err := os.WriteFile(path, []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := map[string]any{"url": "http://localhost:8080/mcp"}
+	if err := patchAppConfig(path, "mcpServers", "mcpjungle", entry); err != nil {
+		t.Fatalf("patchAppConfig() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conf map[string]any
+	if err := json.Unmarshal(data, &conf); err != nil {
+		t.Fatalf("patched config is not valid JSON: %v", err)
+	}
+	if conf["theme"] != "dark" {
+		t.Errorf("unrelated settings were not preserved: %v", conf)
+	}
+	servers := conf["mcpServers"].(map[string]any)
+	if _, ok := servers["other"]; !ok {
+		t.Errorf("existing server entry was removed: %v", servers)
+	}
+	if got := servers["mcpjungle"].(map[string]any)["url"]; got != "http://localhost:8080/mcp" {
+		t.Errorf("mcpjungle entry url = %v", got)
+	}
+}
+
+func TestPatchAppConfigCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vscode", "mcp.json")
+	if err := patchAppConfig(path, "servers", "mcpjungle", map[string]any{"type": "http"}); err != nil {
+		t.Fatalf("patchAppConfig() error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("config file was not created: %v", err)
+	}
+}
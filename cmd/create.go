@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
+	"os"
 	"strings"
+	"time"
 )
 
 var createCmd = &cobra.Command{
@@ -39,9 +41,189 @@ var createUserCmd = &cobra.Command{
 	RunE: runCreateUser,
 }
 
+var createBudgetCmd = &cobra.Command{
+	Use:   "budget [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a spend budget for an MCP client or user",
+	Long: "Create a budget that limits how much an MCP client or a user can spend on tool calls within a period.\n" +
+		"The cost of each tool call is determined by the tool's pricing (see 'mcpjungle set-cost').\n" +
+		"An alert is raised (and sent to the webhook, if any) when the spend crosses the alert threshold and when it reaches the max spend.\n" +
+		"With --hard-stop, tool calls that would take the spend beyond the max spend are rejected.",
+	RunE: runCreateBudget,
+}
+
+var createSLOCmd = &cobra.Command{
+	Use:   "slo [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a latency & error rate objective for a tool or MCP server",
+	Long: "Create a service level objective (SLO) for the calls made to a tool or to all tools of an MCP server,\n" +
+		"eg- p95 latency of at most 2s and an error rate of at most 1%.\n" +
+		"Compliance is computed over a rolling window of invocation history, see 'mcpjungle list slos'.\n" +
+		"It is also exported as the mcpjungle_slo_compliant metric for alerting.",
+	RunE: runCreateSLO,
+}
+
+var createFaultCmd = &cobra.Command{
+	Use:   "fault [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Inject faults into calls to a tool or MCP server",
+	Long: "Create a fault rule that injects latency, errors or dropped responses into a share of the calls\n" +
+		"made to a tool or to all tools of an MCP server, to validate how agents and retry policies handle failures.\n" +
+		"Faults are injected into every attempt of a call, retries included.\n" +
+		"Rules only take effect while the fault_injection feature is on (see 'mcpjungle set-feature').",
+	RunE: runCreateFault,
+}
+
+var createPromptCmd = &cobra.Command{
+	Use:   "prompt [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a prompt template served to MCP clients",
+	Long: "Create a prompt template that the MCP proxy serves to MCP clients via prompts/list and prompts/get.\n" +
+		"Occurrences of {{argument}} in the template are replaced by the values that the client supplies.\n" +
+		"Every argument used in the template must be declared with --arg or --optional-arg.\n" +
+		"\neg- mcpjungle create prompt review --template 'Review this {{language}} code: {{code}}' --arg language --arg 'code=Code to review'",
+	RunE: runCreatePrompt,
+}
+
+var createResidencyPolicyCmd = &cobra.Command{
+	Use:   "residency-policy [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Restrict an MCP client or user to MCP servers in specific regions",
+	Long: "Create a data residency policy that restricts the tool calls of an MCP client or user to MCP servers\n" +
+		"tagged with one of the allowed regions (see the --region flag of 'mcpjungle register').\n" +
+		"Calls to servers in other regions (or without a region) are rejected.\n" +
+		"With '--action reroute', such calls are instead sent to an equivalent server in an allowed region,\n" +
+		"ie, a server that provides a tool with the same name. If there is no such server, the call is rejected.",
+	RunE: runCreateResidencyPolicy,
+}
+
+var createPolicyCmd = &cobra.Command{
+	Use:   "policy [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a policy that is evaluated before every tool invocation",
+	Long: "Create a policy written in CEL (https://cel.dev) that decides whether a tool call is allowed.\n" +
+		"The expression has access to the following variables:\n" +
+		"  client  name of the MCP client making the call via the MCP proxy (production mode only)\n" +
+		"  user    name of the user making the call via the API (production mode only)\n" +
+		"  tool    the tool's metadata: name, server, description and annotations\n" +
+		"  args    the input arguments of the call\n" +
+		"\nThe expression must return either a boolean (true to allow the call, false to deny it) or an object\n" +
+		"with an 'action' ('allow' | 'deny' | 'transform'), an optional 'reason' and, for 'transform', the new 'args'.\n" +
+		"Policies are evaluated in order of priority (lowest first). The first policy that denies a call stops it.\n" +
+		"\nWith --language args, the policy is a JSON document that restricts the argument values that clients or\n" +
+		"users may send to tools, and the arguments removed before calls are forwarded (see the README).\n" +
+		"With --language egress, the policy is a JSON document that allowlists the domains and file paths that\n" +
+		"arguments may point to, calls with arguments pointing anywhere else are denied (see the README).\n" +
+		"\neg- mcpjungle create policy no-destructive-tools --expr '!(has(tool.annotations.destructiveHint) && tool.annotations.destructiveHint)'\n" +
+		"    mcpjungle create policy analytics-only --language args -f analytics-only.json\n" +
+		"    mcpjungle create policy no-exfiltration --language egress -f egress.json",
+	RunE: runCreatePolicy,
+}
+
+var createWebhookKeyCmd = &cobra.Command{
+	Use:   "webhook-key",
+	Args:  cobra.NoArgs,
+	Short: "Create a new key for signing outbound webhooks",
+	Long: "Create a new secret key that MCPJungle uses to sign the payloads of outbound webhooks (eg- budget alerts).\n" +
+		"Every webhook request carries an HMAC-SHA256 signature in the " + types.WebhookSignatureHeader + " header,\n" +
+		"which receivers can check using the key's secret (see client.VerifyWebhookSignature in the Go SDK).\n" +
+		"The new key replaces the current one. Use --grace to keep signing with the previous keys as well for a while,\n" +
+		"so that receivers have time to switch to the new secret.",
+	RunE: runCreateWebhookKey,
+}
+
+var createToolGroupCmd = &cobra.Command{
+	Use:   "group [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a group of tools served on its own MCP endpoint",
+	Long: "Create a tool group that contains a subset of the tools in the registry.\n" +
+		"The group is served by a dedicated MCP proxy at /v0/groups/{name}/mcp, which only exposes the group's tools.\n" +
+		"Optionally attach an extraction profile to slim down the results of tool calls made via the group.\n" +
+		"\neg- mcpjungle create group search --tools brave__web_search,github__search_code --extraction-profile titles-only",
+	RunE: runCreateToolGroup,
+}
+
+var createViewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a personal view on the tools (Production mode)",
+	Long: "Create a view that only contains some tools and MCP servers (all their tools), or replace your view with\n" +
+		"the same name. List its tools with 'mcpjungle list tools --view <name>'.\n" +
+		"MCP sessions can select one of your views to only list its tools, see the README.\n" +
+		"\neg- mcpjungle create view triage --tools github,pagerduty__list_incidents",
+	RunE: runCreateView,
+}
+
+var createExtractionProfileCmd = &cobra.Command{
+	Use:   "extraction-profile [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Create a profile that extracts parts of tool results",
+	Long: "Create an extraction profile containing a JSONPath expression for each tool whose results should be slimmed down.\n" +
+		"When a tool returns JSON text, only the parts selected by the expression are returned to the MCP client.\n" +
+		"A single selected value is returned as-is, multiple values are returned as a JSON array.\n" +
+		"Supported JSONPath syntax: $, .name, ['name'], [0], [-1], .*, [*] and ..name\n" +
+		"Profiles take effect when attached to a tool group (see 'mcpjungle create group').\n" +
+		"\neg- mcpjungle create extraction-profile titles-only --rule 'brave__web_search=$.results[*].title'",
+	RunE: runCreateExtractionProfile,
+}
+
 var (
 	createMcpClientCmdAllowedServers string
 	createMcpClientCmdDescription    string
+	createMcpClientCmdAllowIPs       string
+	createMcpClientCmdDenyIPs        string
+	createMcpClientCmdAttributes     []string
+	createMcpClientCmdLanguage       string
+	createMcpClientCmdToolGroup      string
+	createMcpClientCmdSpiffeID       string
+
+	createBudgetCmdClient         string
+	createBudgetCmdUser           string
+	createBudgetCmdMaxSpend       float64
+	createBudgetCmdPeriod         string
+	createBudgetCmdAlertThreshold float64
+	createBudgetCmdWebhookURL     string
+	createBudgetCmdHardStop       bool
+
+	createSLOCmdTool         string
+	createSLOCmdServer       string
+	createSLOCmdLatency      time.Duration
+	createSLOCmdPercentile   float64
+	createSLOCmdMaxErrorRate float64
+	createSLOCmdWindow       string
+
+	createFaultCmdTool    string
+	createFaultCmdServer  string
+	createFaultCmdType    string
+	createFaultCmdRate    float64
+	createFaultCmdLatency time.Duration
+
+	createPromptCmdTemplate     string
+	createPromptCmdFile         string
+	createPromptCmdDescription  string
+	createPromptCmdArgs         []string
+	createPromptCmdOptionalArgs []string
+
+	createResidencyPolicyCmdClient  string
+	createResidencyPolicyCmdUser    string
+	createResidencyPolicyCmdRegions string
+	createResidencyPolicyCmdAction  string
+
+	createPolicyCmdExpression  string
+	createPolicyCmdFile        string
+	createPolicyCmdDescription string
+	createPolicyCmdPriority    int
+	createPolicyCmdLanguage    string
+
+	createWebhookKeyCmdGracePeriod string
+
+	createToolGroupCmdTools             string
+	createViewCmdTools                  string
+	createToolGroupCmdDescription       string
+	createToolGroupCmdExtractionProfile string
+
+	createExtractionProfileCmdRules       []string
+	createExtractionProfileCmdDescription string
 )
 
 func init() {
@@ -58,27 +240,259 @@ func init() {
 		"",
 		"Description of the MCP client. This is optional and can be used to provide additional context.",
 	)
+	createMcpClientCmd.Flags().StringVar(
+		&createMcpClientCmdAllowIPs,
+		"allow-ips",
+		"",
+		"Comma-separated list of CIDRs or IP addresses that this client is allowed to connect from.\n"+
+			"By default, the client can connect from anywhere.",
+	)
+	createMcpClientCmd.Flags().StringVar(
+		&createMcpClientCmdDenyIPs,
+		"deny-ips",
+		"",
+		"Comma-separated list of CIDRs or IP addresses that this client is never allowed to connect from",
+	)
+	createMcpClientCmd.Flags().StringArrayVar(
+		&createMcpClientCmdAttributes,
+		"attr",
+		nil,
+		"Attribute of the client in the form <key>=<value> (eg- team=payments), can be repeated.\n"+
+			"Prompt templates can refer to attributes as {{client.<key>}}.",
+	)
+	createMcpClientCmd.Flags().StringVar(
+		&createMcpClientCmdLanguage,
+		"language",
+		"",
+		"Language (eg- fr) that the text results of tool calls are translated into for this client.\n"+
+			"Requires the server to be started with a translation provider (see TRANSLATION_PROVIDER).",
+	)
+	createMcpClientCmd.Flags().StringVar(
+		&createMcpClientCmdToolGroup,
+		"tool-group",
+		"",
+		"Tool group that the client is bound to. The client is served the tools of the group on /mcp,\n"+
+			"without having to be configured with the group's URL.",
+	)
+	createMcpClientCmd.Flags().StringVar(
+		&createMcpClientCmdSpiffeID,
+		"spiffe-id",
+		"",
+		"SPIFFE ID of the client's workload (eg- spiffe://example.org/ns/prod/sa/agent).\n"+
+			"The client can authenticate with an SVID with this ID (mTLS) instead of its access token.",
+	)
+
+	createBudgetCmd.Flags().StringVar(&createBudgetCmdClient, "client", "", "Name of the MCP client whose spend is limited")
+	createBudgetCmd.Flags().StringVar(&createBudgetCmdUser, "user", "", "Name of the user whose spend is limited")
+	createBudgetCmd.Flags().Float64Var(&createBudgetCmdMaxSpend, "max-spend", 0, "Maximum spend allowed within a period")
+	createBudgetCmd.Flags().StringVar(
+		&createBudgetCmdPeriod,
+		"period",
+		"monthly",
+		"Period after which the spend is reset ('daily' | 'monthly' | 'total')",
+	)
+	createBudgetCmd.Flags().Float64Var(
+		&createBudgetCmdAlertThreshold,
+		"alert-at",
+		80,
+		"Percentage of the max spend at which an alert is raised (0 to only alert when the budget is exceeded)",
+	)
+	createBudgetCmd.Flags().StringVar(
+		&createBudgetCmdWebhookURL,
+		"webhook",
+		"",
+		"URL that receives a POST request with the details of every alert raised for this budget",
+	)
+	createBudgetCmd.Flags().BoolVar(
+		&createBudgetCmdHardStop,
+		"hard-stop",
+		false,
+		"Reject tool calls that would exceed the budget instead of only raising alerts",
+	)
+	createBudgetCmd.MarkFlagsOneRequired("client", "user")
+	createBudgetCmd.MarkFlagsMutuallyExclusive("client", "user")
+	_ = createBudgetCmd.MarkFlagRequired("max-spend")
+
+	createSLOCmd.Flags().StringVar(&createSLOCmdTool, "tool", "", "Name of the tool whose calls are tracked")
+	createSLOCmd.Flags().StringVar(
+		&createSLOCmdServer, "server", "", "Name of the MCP server whose tool calls are tracked",
+	)
+	createSLOCmd.Flags().DurationVar(
+		&createSLOCmdLatency, "latency", 0, "Latency objective, eg- 2s. By default, latency is not tracked.",
+	)
+	createSLOCmd.Flags().Float64Var(
+		&createSLOCmdPercentile, "percentile", 95, "Percentile of calls that must meet the latency objective",
+	)
+	createSLOCmd.Flags().Float64Var(
+		&createSLOCmdMaxErrorRate,
+		"max-error-rate",
+		0,
+		"Highest percentage of failed calls allowed. By default, the error rate is not tracked.",
+	)
+	createSLOCmd.Flags().StringVar(
+		&createSLOCmdWindow, "window", "1d", "Rolling period over which compliance is computed (eg- 1d, 12h)",
+	)
+	createSLOCmd.MarkFlagsOneRequired("tool", "server")
+	createSLOCmd.MarkFlagsMutuallyExclusive("tool", "server")
+	createSLOCmd.MarkFlagsOneRequired("latency", "max-error-rate")
+
+	createFaultCmd.Flags().StringVar(&createFaultCmdTool, "tool", "", "Name of the tool whose calls are affected")
+	createFaultCmd.Flags().StringVar(
+		&createFaultCmdServer, "server", "", "Name of the MCP server whose tool calls are affected",
+	)
+	createFaultCmd.Flags().StringVar(
+		&createFaultCmdType,
+		"type",
+		"",
+		"Fault to inject ('latency' | 'error' | 'drop'). 'drop' calls the tool but discards its response.",
+	)
+	createFaultCmd.Flags().Float64Var(
+		&createFaultCmdRate, "rate", 100, "Percentage of calls that the fault is injected into",
+	)
+	createFaultCmd.Flags().DurationVar(
+		&createFaultCmdLatency, "latency", 0, "Delay added to calls by a latency fault (eg- 3s)",
+	)
+	createFaultCmd.MarkFlagsOneRequired("tool", "server")
+	createFaultCmd.MarkFlagsMutuallyExclusive("tool", "server")
+	_ = createFaultCmd.MarkFlagRequired("type")
+
+	createPromptCmd.Flags().StringVar(&createPromptCmdTemplate, "template", "", "Text of the prompt template")
+	createPromptCmd.Flags().StringVarP(
+		&createPromptCmdFile, "file", "f", "", "Path to a file containing the text of the prompt template",
+	)
+	createPromptCmd.Flags().StringVar(&createPromptCmdDescription, "description", "", "Description of the prompt")
+	createPromptCmd.Flags().StringArrayVar(
+		&createPromptCmdArgs,
+		"arg",
+		nil,
+		"Required argument in the form <name> or <name>=<description>, can be repeated",
+	)
+	createPromptCmd.Flags().StringArrayVar(
+		&createPromptCmdOptionalArgs,
+		"optional-arg",
+		nil,
+		"Optional argument in the form <name> or <name>=<description>, can be repeated",
+	)
+	createPromptCmd.MarkFlagsOneRequired("template", "file")
+	createPromptCmd.MarkFlagsMutuallyExclusive("template", "file")
+
+	createResidencyPolicyCmd.Flags().StringVar(
+		&createResidencyPolicyCmdClient, "client", "", "Name of the MCP client whose tool calls are restricted",
+	)
+	createResidencyPolicyCmd.Flags().StringVar(
+		&createResidencyPolicyCmdUser, "user", "", "Name of the user whose tool calls are restricted",
+	)
+	createResidencyPolicyCmd.Flags().StringVar(
+		&createResidencyPolicyCmdRegions, "regions", "", "Comma-separated list of regions that tool calls may be served from",
+	)
+	createResidencyPolicyCmd.Flags().StringVar(
+		&createResidencyPolicyCmdAction,
+		"action",
+		"reject",
+		"What to do with calls to servers outside the allowed regions ('reject' | 'reroute')",
+	)
+	createResidencyPolicyCmd.MarkFlagsOneRequired("client", "user")
+	createResidencyPolicyCmd.MarkFlagsMutuallyExclusive("client", "user")
+	_ = createResidencyPolicyCmd.MarkFlagRequired("regions")
+
+	createPolicyCmd.Flags().StringVar(&createPolicyCmdExpression, "expr", "", "Expression of the policy")
+	createPolicyCmd.Flags().StringVarP(
+		&createPolicyCmdFile, "file", "f", "", "Path to a file containing the expression of the policy",
+	)
+	createPolicyCmd.Flags().StringVar(&createPolicyCmdDescription, "description", "", "Description of the policy")
+	createPolicyCmd.Flags().IntVar(
+		&createPolicyCmdPriority, "priority", 0, "Priority of the policy, policies are evaluated lowest first",
+	)
+	createPolicyCmd.Flags().StringVar(
+		&createPolicyCmdLanguage, "language", "cel", "Language of the policy: cel, args or egress",
+	)
+	createPolicyCmd.MarkFlagsOneRequired("expr", "file")
+	createPolicyCmd.MarkFlagsMutuallyExclusive("expr", "file")
 
 	createCmd.AddCommand(createMcpClientCmd)
 	createCmd.AddCommand(createUserCmd)
+	createCmd.AddCommand(createBudgetCmd)
+	createCmd.AddCommand(createSLOCmd)
+	createCmd.AddCommand(createFaultCmd)
+	createCmd.AddCommand(createPromptCmd)
+	createCmd.AddCommand(createResidencyPolicyCmd)
+	createWebhookKeyCmd.Flags().StringVar(
+		&createWebhookKeyCmdGracePeriod,
+		"grace",
+		"",
+		"Duration for which the previous keys remain valid (eg- 24h). By default, they expire immediately.",
+	)
+
+	createCmd.AddCommand(createPolicyCmd)
+	createCmd.AddCommand(createWebhookKeyCmd)
+
+	createToolGroupCmd.Flags().StringVar(
+		&createToolGroupCmdTools, "tools", "", "Comma-separated list of the canonical names of the tools in the group",
+	)
+	createToolGroupCmd.Flags().StringVar(&createToolGroupCmdDescription, "description", "", "Description of the group")
+	createToolGroupCmd.Flags().StringVar(
+		&createToolGroupCmdExtractionProfile,
+		"extraction-profile",
+		"",
+		"Name of the extraction profile applied to the results of the group's tool calls",
+	)
+	_ = createToolGroupCmd.MarkFlagRequired("tools")
+
+	createViewCmd.Flags().StringVar(
+		&createViewCmdTools, "tools", "", "Comma-separated list of the canonical names of tools and names of MCP servers in the view",
+	)
+	_ = createViewCmd.MarkFlagRequired("tools")
+
+	createExtractionProfileCmd.Flags().StringArrayVar(
+		&createExtractionProfileCmdRules,
+		"rule",
+		nil,
+		"Extraction rule in the form <tool name>=<JSONPath expression>, can be repeated",
+	)
+	createExtractionProfileCmd.Flags().StringVar(
+		&createExtractionProfileCmdDescription, "description", "", "Description of the profile",
+	)
+	_ = createExtractionProfileCmd.MarkFlagRequired("rule")
+
+	createCmd.AddCommand(createToolGroupCmd)
+	createCmd.AddCommand(createViewCmd)
+	createCmd.AddCommand(createExtractionProfileCmd)
 
 	rootCmd.AddCommand(createCmd)
 }
 
-func runCreateMcpClient(cmd *cobra.Command, args []string) error {
-	// convert the comma-separated list of allowed servers into a slice
-	allowList := make([]string, 0)
-	for _, s := range strings.Split(createMcpClientCmdAllowedServers, ",") {
+// splitCommaList converts a comma-separated list into a slice, ignoring empty entries.
+// It always returns a non-nil slice.
+func splitCommaList(list string) []string {
+	items := make([]string, 0)
+	for _, s := range strings.Split(list, ",") {
 		trimmed := strings.TrimSpace(s)
 		if trimmed != "" {
-			allowList = append(allowList, trimmed)
+			items = append(items, trimmed)
 		}
 	}
+	return items
+}
 
+func runCreateMcpClient(cmd *cobra.Command, args []string) error {
+	attrs := make(map[string]string, len(createMcpClientCmdAttributes))
+	for _, a := range createMcpClientCmdAttributes {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("invalid attribute '%s', expected <key>=<value>", a)
+		}
+		attrs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
 	c := &types.McpClient{
 		Name:        args[0],
 		Description: createMcpClientCmdDescription,
-		AllowList:   allowList,
+		AllowList:   splitCommaList(createMcpClientCmdAllowedServers),
+		IPAllowList: splitCommaList(createMcpClientCmdAllowIPs),
+		IPDenyList:  splitCommaList(createMcpClientCmdDenyIPs),
+		Attributes:  attrs,
+		Language:    createMcpClientCmdLanguage,
+		ToolGroup:   createMcpClientCmdToolGroup,
+		SpiffeID:    createMcpClientCmdSpiffeID,
 	}
 
 	token, err := apiClient.CreateMcpClient(c)
@@ -96,6 +510,24 @@ func runCreateMcpClient(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println("This client does not have access to any MCP servers.")
 	}
+	if len(c.IPAllowList) > 0 {
+		fmt.Println("Allowed networks: " + strings.Join(c.IPAllowList, ","))
+	}
+	if len(c.IPDenyList) > 0 {
+		fmt.Println("Denied networks: " + strings.Join(c.IPDenyList, ","))
+	}
+	if len(c.Attributes) > 0 {
+		fmt.Printf("Attributes: %v\n", c.Attributes)
+	}
+	if c.Language != "" {
+		fmt.Printf("Results translated into: %s\n", c.Language)
+	}
+	if c.ToolGroup != "" {
+		fmt.Printf("Bound to tool group: %s\n", c.ToolGroup)
+	}
+	if c.SpiffeID != "" {
+		fmt.Printf("SPIFFE ID: %s\n", c.SpiffeID)
+	}
 
 	fmt.Printf("\nAccess token: %s\n", token)
 	fmt.Println("Your client should send this token in the `Authorization: Bearer {token}` HTTP header.")
@@ -123,3 +555,194 @@ func runCreateUser(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCreateBudget(cmd *cobra.Command, args []string) error {
+	b := &types.Budget{
+		Name:           args[0],
+		Client:         createBudgetCmdClient,
+		User:           createBudgetCmdUser,
+		MaxSpend:       createBudgetCmdMaxSpend,
+		Period:         createBudgetCmdPeriod,
+		AlertThreshold: createBudgetCmdAlertThreshold,
+		WebhookURL:     createBudgetCmdWebhookURL,
+		HardStop:       createBudgetCmdHardStop,
+	}
+	if _, err := apiClient.CreateBudget(b); err != nil {
+		return fmt.Errorf("failed to create budget: %w", err)
+	}
+	cmd.Printf("Budget '%s' created successfully\n", b.Name)
+	return nil
+}
+
+func runCreateSLO(cmd *cobra.Command, args []string) error {
+	slo := &types.SLO{
+		Name:              args[0],
+		Tool:              createSLOCmdTool,
+		Server:            createSLOCmdServer,
+		LatencyPercentile: createSLOCmdPercentile,
+		LatencyTargetMs:   createSLOCmdLatency.Milliseconds(),
+		MaxErrorRate:      createSLOCmdMaxErrorRate,
+		Window:            createSLOCmdWindow,
+	}
+	if _, err := apiClient.CreateSLO(slo); err != nil {
+		return fmt.Errorf("failed to create SLO: %w", err)
+	}
+	cmd.Printf("SLO '%s' created successfully\n", slo.Name)
+	return nil
+}
+
+func runCreateFault(cmd *cobra.Command, args []string) error {
+	rule := &types.FaultRule{
+		Name:      args[0],
+		Tool:      createFaultCmdTool,
+		Server:    createFaultCmdServer,
+		Fault:     createFaultCmdType,
+		Rate:      createFaultCmdRate,
+		LatencyMs: createFaultCmdLatency.Milliseconds(),
+	}
+	if _, err := apiClient.CreateFaultRule(rule); err != nil {
+		return fmt.Errorf("failed to create fault rule: %w", err)
+	}
+	cmd.Printf("Fault rule '%s' created successfully\n", rule.Name)
+	return nil
+}
+
+func runCreatePrompt(cmd *cobra.Command, args []string) error {
+	template := createPromptCmdTemplate
+	if createPromptCmdFile != "" {
+		data, err := os.ReadFile(createPromptCmdFile)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt template file %s: %w", createPromptCmdFile, err)
+		}
+		template = string(data)
+	}
+	p := &types.Prompt{
+		Name:        args[0],
+		Description: createPromptCmdDescription,
+		Template:    template,
+	}
+	for _, a := range createPromptCmdArgs {
+		p.Arguments = append(p.Arguments, parsePromptArgument(a, true))
+	}
+	for _, a := range createPromptCmdOptionalArgs {
+		p.Arguments = append(p.Arguments, parsePromptArgument(a, false))
+	}
+	if _, err := apiClient.CreatePrompt(p); err != nil {
+		return fmt.Errorf("failed to create prompt: %w", err)
+	}
+	cmd.Printf("Prompt '%s' created successfully\n", p.Name)
+	return nil
+}
+
+// parsePromptArgument parses a prompt argument in the form <name> or <name>=<description>.
+func parsePromptArgument(arg string, required bool) types.PromptArgument {
+	name, description, _ := strings.Cut(arg, "=")
+	return types.PromptArgument{
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		Required:    required,
+	}
+}
+
+func runCreateResidencyPolicy(cmd *cobra.Command, args []string) error {
+	regions := make([]string, 0)
+	for _, r := range strings.Split(createResidencyPolicyCmdRegions, ",") {
+		trimmed := strings.TrimSpace(r)
+		if trimmed != "" {
+			regions = append(regions, trimmed)
+		}
+	}
+	p := &types.ResidencyPolicy{
+		Name:           args[0],
+		Client:         createResidencyPolicyCmdClient,
+		User:           createResidencyPolicyCmdUser,
+		AllowedRegions: regions,
+		Action:         createResidencyPolicyCmdAction,
+	}
+	if _, err := apiClient.CreateResidencyPolicy(p); err != nil {
+		return fmt.Errorf("failed to create residency policy: %w", err)
+	}
+	cmd.Printf("Residency policy '%s' created successfully\n", p.Name)
+	return nil
+}
+
+func runCreatePolicy(cmd *cobra.Command, args []string) error {
+	expr := createPolicyCmdExpression
+	if createPolicyCmdFile != "" {
+		data, err := os.ReadFile(createPolicyCmdFile)
+		if err != nil {
+			return fmt.Errorf("failed to read policy file %s: %w", createPolicyCmdFile, err)
+		}
+		expr = string(data)
+	}
+	p := &types.Policy{
+		Name:        args[0],
+		Description: createPolicyCmdDescription,
+		Language:    createPolicyCmdLanguage,
+		Expression:  expr,
+		Priority:    createPolicyCmdPriority,
+	}
+	if _, err := apiClient.CreatePolicy(p); err != nil {
+		return fmt.Errorf("failed to create policy: %w", err)
+	}
+	cmd.Printf("Policy '%s' created successfully\n", p.Name)
+	return nil
+}
+
+func runCreateWebhookKey(cmd *cobra.Command, args []string) error {
+	key, err := apiClient.RotateWebhookKey(createWebhookKeyCmdGracePeriod)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook key: %w", err)
+	}
+	return renderOutput(cmd, key, func() error {
+		cmd.Printf("Webhook key '%s' created successfully\n", key.KeyID)
+		cmd.Printf("Secret: %s\n\n", key.Secret)
+		cmd.Println("Store this secret with your webhook receivers, it cannot be retrieved again.")
+		return nil
+	})
+}
+
+func runCreateToolGroup(cmd *cobra.Command, args []string) error {
+	g := &types.ToolGroup{
+		Name:              args[0],
+		Description:       createToolGroupCmdDescription,
+		IncludedTools:     splitCommaList(createToolGroupCmdTools),
+		ExtractionProfile: createToolGroupCmdExtractionProfile,
+	}
+	if _, err := apiClient.CreateToolGroup(g); err != nil {
+		return fmt.Errorf("failed to create tool group: %w", err)
+	}
+	cmd.Printf("Tool group '%s' created successfully\n", g.Name)
+	cmd.Printf("Its MCP proxy is available at %s/v0/groups/%s/mcp\n", strings.TrimSuffix(activeRegistryURL, "/"), g.Name)
+	return nil
+}
+
+func runCreateView(cmd *cobra.Command, args []string) error {
+	v, err := apiClient.SetToolView(&types.ToolView{Name: args[0], Tools: splitCommaList(createViewCmdTools)})
+	if err != nil {
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+	cmd.Printf("View '%s' saved, list its tools with 'mcpjungle list tools --view %s'\n", v.Name, v.Name)
+	return nil
+}
+
+func runCreateExtractionProfile(cmd *cobra.Command, args []string) error {
+	rules := make(map[string]string, len(createExtractionProfileCmdRules))
+	for _, r := range createExtractionProfileCmdRules {
+		tool, expr, ok := strings.Cut(r, "=")
+		if !ok || strings.TrimSpace(tool) == "" {
+			return fmt.Errorf("invalid rule '%s', expected <tool name>=<JSONPath expression>", r)
+		}
+		rules[strings.TrimSpace(tool)] = strings.TrimSpace(expr)
+	}
+	p := &types.ExtractionProfile{
+		Name:        args[0],
+		Description: createExtractionProfileCmdDescription,
+		Rules:       rules,
+	}
+	if _, err := apiClient.CreateExtractionProfile(p); err != nil {
+		return fmt.Errorf("failed to create extraction profile: %w", err)
+	}
+	cmd.Printf("Extraction profile '%s' created successfully\n", p.Name)
+	return nil
+}
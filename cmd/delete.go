@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +25,8 @@ var deleteMcpClientCmd = &cobra.Command{
 	RunE: runDeleteMcpClient,
 }
 
+var deleteMcpClientCmdVersion int
+
 var deleteUserCmd = &cobra.Command{
 	Use:   "user [username]",
 	Args:  cobra.ExactArgs(1),
@@ -31,16 +35,99 @@ var deleteUserCmd = &cobra.Command{
 	RunE:  runDeleteUser,
 }
 
+var deleteBudgetCmd = &cobra.Command{
+	Use:   "budget [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a spend budget",
+	RunE:  runDeleteBudget,
+}
+
+var deleteSLOCmd = &cobra.Command{
+	Use:   "slo [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a service level objective",
+	RunE:  runDeleteSLO,
+}
+
+var deleteFaultCmd = &cobra.Command{
+	Use:   "fault [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a fault injection rule",
+	RunE:  runDeleteFault,
+}
+
+var deletePromptCmd = &cobra.Command{
+	Use:   "prompt [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a prompt template",
+	RunE:  runDeletePrompt,
+}
+
+var deleteResidencyPolicyCmd = &cobra.Command{
+	Use:   "residency-policy [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a data residency policy",
+	RunE:  runDeleteResidencyPolicy,
+}
+
+var deleteGrantCmd = &cobra.Command{
+	Use:   "grant [id]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Revoke a temporary access grant before it expires",
+	RunE:  runDeleteGrant,
+}
+
+var deletePolicyCmd = &cobra.Command{
+	Use:   "policy [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a tool invocation policy",
+	RunE:  runDeletePolicy,
+}
+
+var deleteToolGroupCmd = &cobra.Command{
+	Use:   "group [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a tool group",
+	Long:  "Delete a tool group. Its MCP proxy stops serving immediately, the tools themselves are not affected.",
+	RunE:  runDeleteToolGroup,
+}
+
+var deleteViewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete one of your personal views on the tools (Production mode)",
+	RunE:  runDeleteView,
+}
+
+var deleteExtractionProfileCmd = &cobra.Command{
+	Use:   "extraction-profile [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete an extraction profile",
+	Long:  "Delete an extraction profile. A profile cannot be deleted while it is attached to a tool group.",
+	RunE:  runDeleteExtractionProfile,
+}
+
 func init() {
+	deleteMcpClientCmd.Flags().IntVar(&deleteMcpClientCmdVersion, "if-version", 0, "Reject the deletion if the client was modified since this version (see 'mcpjungle list mcp-clients')")
 	deleteCmd.AddCommand(deleteMcpClientCmd)
 	deleteCmd.AddCommand(deleteUserCmd)
+	deleteCmd.AddCommand(deleteBudgetCmd)
+	deleteCmd.AddCommand(deleteSLOCmd)
+	deleteCmd.AddCommand(deleteFaultCmd)
+	deleteCmd.AddCommand(deletePromptCmd)
+	deleteCmd.AddCommand(deleteResidencyPolicyCmd)
+	deleteCmd.AddCommand(deleteGrantCmd)
+	deleteCmd.AddCommand(deletePolicyCmd)
+	deleteCmd.AddCommand(deleteToolGroupCmd)
+	deleteCmd.AddCommand(deleteViewCmd)
+	deleteCmd.AddCommand(deleteExtractionProfileCmd)
 
 	rootCmd.AddCommand(deleteCmd)
 }
 
 func runDeleteMcpClient(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	if err := apiClient.DeleteMcpClient(name); err != nil {
+	if err := apiClient.DeleteMcpClient(name, deleteMcpClientCmdVersion); err != nil {
 		return fmt.Errorf("failed to delete the client: %w", err)
 	}
 	fmt.Printf("MCP client '%s' deleted successfully (if it existed)!\n", name)
@@ -55,3 +142,95 @@ func runDeleteUser(cmd *cobra.Command, args []string) error {
 	cmd.Printf("User '%s' deleted successfully (if they existed)\n", username)
 	return nil
 }
+
+func runDeleteBudget(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteBudget(name); err != nil {
+		return fmt.Errorf("failed to delete the budget: %w", err)
+	}
+	cmd.Printf("Budget '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeleteSLO(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteSLO(name); err != nil {
+		return fmt.Errorf("failed to delete the SLO: %w", err)
+	}
+	cmd.Printf("SLO '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeleteFault(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteFaultRule(name); err != nil {
+		return fmt.Errorf("failed to delete the fault rule: %w", err)
+	}
+	cmd.Printf("Fault rule '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeletePrompt(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeletePrompt(name); err != nil {
+		return fmt.Errorf("failed to delete the prompt: %w", err)
+	}
+	cmd.Printf("Prompt '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeleteView(cmd *cobra.Command, args []string) error {
+	if err := apiClient.DeleteToolView(args[0]); err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	cmd.Printf("View '%s' deleted\n", args[0])
+	return nil
+}
+
+func runDeleteGrant(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid access grant ID %s", args[0])
+	}
+	if err := apiClient.RevokeAccessGrant(uint(id)); err != nil {
+		return fmt.Errorf("failed to revoke the access grant: %w", err)
+	}
+	cmd.Printf("Access grant %d revoked\n", id)
+	return nil
+}
+
+func runDeleteResidencyPolicy(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteResidencyPolicy(name); err != nil {
+		return fmt.Errorf("failed to delete the residency policy: %w", err)
+	}
+	cmd.Printf("Residency policy '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeletePolicy(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeletePolicy(name); err != nil {
+		return fmt.Errorf("failed to delete the policy: %w", err)
+	}
+	cmd.Printf("Policy '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeleteToolGroup(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteToolGroup(name); err != nil {
+		return fmt.Errorf("failed to delete the tool group: %w", err)
+	}
+	cmd.Printf("Tool group '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
+
+func runDeleteExtractionProfile(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteExtractionProfile(name); err != nil {
+		return fmt.Errorf("failed to delete the extraction profile: %w", err)
+	}
+	cmd.Printf("Extraction profile '%s' deleted successfully (if it existed)\n", name)
+	return nil
+}
@@ -17,13 +17,16 @@ var deregisterMCPServerCmd = &cobra.Command{
 	},
 }
 
+var deregisterMCPServerCmdVersion int
+
 func init() {
+	deregisterMCPServerCmd.Flags().IntVar(&deregisterMCPServerCmdVersion, "if-version", 0, "Reject the deregistration if the server was modified since this version (see 'mcpjungle list servers')")
 	rootCmd.AddCommand(deregisterMCPServerCmd)
 }
 
 func runDeregisterMCPServer(cmd *cobra.Command, args []string) error {
 	server := args[0]
-	if err := apiClient.DeregisterServer(server); err != nil {
+	if err := apiClient.DeregisterServer(server, deregisterMCPServerCmdVersion); err != nil {
 		return fmt.Errorf("failed to deregister MCP server %s: %w", server, err)
 	}
 	fmt.Printf("Successfully deregistered MCP server %s\n", server)
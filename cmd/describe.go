@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mcpjungle/mcpjungle/internal/schema"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Show detailed information about a resource",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupBasic),
+		"order": "4",
+	},
+}
+
+var describeToolCmd = &cobra.Command{
+	Use:   "tool <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Show a tool's full input schema, annotations and an example invocation",
+	Long: "Prints everything mcpjungle knows about a tool: its description, annotations and full input schema.\n" +
+		"It also generates an example input payload from the schema, which you can use as a starting point\n" +
+		"for 'mcpjungle invoke'.",
+	RunE: runDescribeTool,
+}
+
+var describeServerCmd = &cobra.Command{
+	Use:   "server <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Show the protocol version and capabilities negotiated with an MCP server",
+	Long: "Prints the MCP protocol version that mcpjungle negotiated with an MCP server and the capabilities\n" +
+		"(tools, prompts, resources, logging, etc) that the server advertised.\n" +
+		"These are recorded when the server is registered. Use --refresh to negotiate them again.",
+	RunE: runDescribeServer,
+}
+
+var describeServerCmdRefresh bool
+
+func init() {
+	describeServerCmd.Flags().BoolVar(
+		&describeServerCmdRefresh,
+		"refresh",
+		false,
+		"Re-connect to the MCP server to negotiate its capabilities again",
+	)
+
+	describeCmd.AddCommand(describeToolCmd)
+	describeCmd.AddCommand(describeServerCmd)
+	rootCmd.AddCommand(describeCmd)
+}
+
+// toolDescription is the machine-readable output of the describe tool command
+type toolDescription struct {
+	*types.Tool
+	ExampleInput map[string]any `json:"example_input"`
+}
+
+// generateToolExampleInput generates an example input payload for the given tool from its input schema.
+func generateToolExampleInput(t *types.Tool) map[string]any {
+	return schema.ExampleObject(t.InputSchema.Properties)
+}
+
+func runDescribeTool(cmd *cobra.Command, args []string) error {
+	t, err := apiClient.GetTool(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get tool '%s': %w", args[0], err)
+	}
+	d := &toolDescription{
+		Tool:         t,
+		ExampleInput: generateToolExampleInput(t),
+	}
+
+	return renderOutput(cmd, d, func() error {
+		status := "ENABLED"
+		if !t.Enabled {
+			status = "DISABLED"
+		}
+		fmt.Printf("%s  [%s]\n", t.Name, status)
+		if d := t.EnrichedDescription(); d != "" {
+			fmt.Println(d)
+		}
+
+		if a := t.Annotations; a != nil {
+			fmt.Println()
+			fmt.Println("Annotations:")
+			if a.Title != "" {
+				fmt.Println("  Title: " + a.Title)
+			}
+			printHint := func(name string, v *bool) {
+				if v != nil {
+					fmt.Printf("  %s: %t\n", name, *v)
+				}
+			}
+			printHint("Read-only", a.ReadOnlyHint)
+			printHint("Destructive", a.DestructiveHint)
+			printHint("Idempotent", a.IdempotentHint)
+			printHint("Open world", a.OpenWorldHint)
+		}
+
+		if t.CostPerCall > 0 || t.CostPerUnit > 0 {
+			fmt.Println()
+			fmt.Printf("Cost: %g per call", t.CostPerCall)
+			if t.CostPerUnit > 0 {
+				fmt.Printf(" + %g per unit of '%s'", t.CostPerUnit, t.CostUnitArg)
+			}
+			fmt.Println()
+		}
+		if t.HedgeDelayMs > 0 {
+			fmt.Printf("Hedged after: %dms\n", t.HedgeDelayMs)
+		}
+		if t.PayloadSampleRate > 0 {
+			fmt.Printf("Payload logging: %g%% of calls", t.PayloadSampleRate*100)
+			if len(t.PayloadRedactions) > 0 {
+				fmt.Printf(", redacting %s", strings.Join(t.PayloadRedactions, ", "))
+			}
+			fmt.Println()
+		}
+		if t.ArgsScript != "" {
+			fmt.Printf("Arguments script: %s\n", t.ArgsScript)
+		}
+		if t.ResultScript != "" {
+			fmt.Printf("Result script: %s\n", t.ResultScript)
+		}
+		if t.Version > 0 {
+			fmt.Printf("Version: %d\n", t.Version)
+		}
+
+		fmt.Println()
+		fmt.Println("Input Parameters:")
+		if len(t.InputSchema.Properties) == 0 {
+			fmt.Println("  This tool does not require any input parameters.")
+		}
+		for _, name := range schema.SortedPropertyNames(t.InputSchema.Properties, t.InputSchema.Required) {
+			p, _ := t.InputSchema.Properties[name].(map[string]any)
+			requiredOrOptional := "optional"
+			if slices.Contains(t.InputSchema.Required, name) {
+				requiredOrOptional = "required"
+			}
+			fmt.Printf("  %s (%s, %s)\n", name, schema.SchemaType(p), requiredOrOptional)
+			if desc, ok := p["description"].(string); ok && desc != "" {
+				fmt.Println("      " + desc)
+			}
+		}
+
+		fmt.Println()
+		fmt.Println("Full Input Schema:")
+		s, err := json.MarshalIndent(t.InputSchema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize input schema: %w", err)
+		}
+		fmt.Println(string(s))
+
+		if len(t.OutputSchema) > 0 {
+			fmt.Println()
+			fmt.Println("Output Schema:")
+			s, err := json.MarshalIndent(t.OutputSchema, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize output schema: %w", err)
+			}
+			fmt.Println(string(s))
+		}
+
+		example, err := json.Marshal(d.ExampleInput)
+		if err != nil {
+			return fmt.Errorf("failed to serialize example input: %w", err)
+		}
+		fmt.Println()
+		fmt.Println("Example invocation:")
+		fmt.Printf("  mcpjungle invoke %s --input '%s'\n", t.Name, example)
+		fmt.Println()
+		fmt.Printf("Run 'mcpjungle invoke %s --interactive' to edit this example before invoking the tool\n", t.Name)
+		return nil
+	})
+}
+
+func runDescribeServer(cmd *cobra.Command, args []string) error {
+	caps, err := apiClient.GetServerCapabilities(args[0], describeServerCmdRefresh)
+	if err != nil {
+		return fmt.Errorf("failed to get capabilities of MCP server '%s': %w", args[0], err)
+	}
+
+	return renderOutput(cmd, caps, func() error {
+		fmt.Println(caps.Name)
+		if caps.ServerInfo != nil {
+			fmt.Printf("Implementation: %s %s\n", caps.ServerInfo.Name, caps.ServerInfo.Version)
+		}
+		fmt.Println("Protocol version: " + caps.ProtocolVersion)
+
+		fmt.Println()
+		fmt.Println("Capabilities:")
+		if len(caps.Capabilities) == 0 {
+			fmt.Println("  This server did not advertise any capabilities.")
+		}
+		names := make([]string, 0, len(caps.Capabilities))
+		for name := range caps.Capabilities {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			opts, _ := caps.Capabilities[name].(map[string]any)
+			if len(opts) == 0 {
+				fmt.Println("  " + name)
+				continue
+			}
+			o, _ := json.Marshal(opts)
+			fmt.Printf("  %s %s\n", name, o)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffCmdFrom string
+	diffCmdTo   string
+
+	snapshotCmdFile string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the tool catalogs of two gateways or snapshots",
+	Long: "Report the MCP servers and tools present in one catalog but not the other, and the differences in the\n" +
+		"configuration, schemas and enablement of those present in both. Use it to keep environments in sync.\n" +
+		"--from and --to each take the name of a client context (see 'mcpjungle config') or the path of a\n" +
+		"snapshot file created with 'mcpjungle snapshot'. --to defaults to the registry in use.\n" +
+		"The command exits with an error if the catalogs differ.\n" +
+		"\neg- mcpjungle diff --from prod --to staging\n" +
+		"    mcpjungle diff --from prod-2025-01-01.json --to prod",
+	RunE: runDiff,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "27",
+	},
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export the tool catalog of the registry to a file",
+	Long: "Write the MCP servers and tools of the registry to a JSON file, to compare it later with 'mcpjungle diff'.\n" +
+		"The environment of STDIO servers is left out because it often contains secrets.\n" +
+		"\neg- mcpjungle --context prod snapshot --file prod.json",
+	RunE: runSnapshot,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "28",
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffCmdFrom, "from", "", "Client context or snapshot file of the base catalog")
+	diffCmd.Flags().StringVar(&diffCmdTo, "to", "", "Client context or snapshot file of the compared catalog")
+	_ = diffCmd.MarkFlagRequired("from")
+	rootCmd.AddCommand(diffCmd)
+
+	snapshotCmd.Flags().StringVarP(&snapshotCmdFile, "file", "f", "", "Path of the snapshot file to write (stdout if empty)")
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	from, err := loadCatalog(diffCmdFrom)
+	if err != nil {
+		return err
+	}
+	to, err := loadCatalog(diffCmdTo)
+	if err != nil {
+		return err
+	}
+	d := client.DiffCatalogs(from, to)
+	d.From, d.To = catalogLabel(diffCmdFrom, from), catalogLabel(diffCmdTo, to)
+
+	err = renderOutput(cmd, d, func() error {
+		if d.Empty() {
+			cmd.Printf("No differences between %s and %s\n", d.From, d.To)
+			return nil
+		}
+		printNames := func(title string, names []string) {
+			if len(names) == 0 {
+				return
+			}
+			cmd.Println(title)
+			for _, n := range names {
+				cmd.Printf("  %s\n", n)
+			}
+		}
+		printChanges := func(title string, changes []types.CatalogChange) {
+			if len(changes) == 0 {
+				return
+			}
+			cmd.Println(title)
+			for _, c := range changes {
+				cmd.Printf("  %s\n", c.Name)
+				for _, change := range c.Changes {
+					cmd.Printf("    %s\n", change)
+				}
+			}
+		}
+		printNames(fmt.Sprintf("Servers only in %s:", d.From), d.ServersOnlyInFrom)
+		printNames(fmt.Sprintf("Servers only in %s:", d.To), d.ServersOnlyInTo)
+		printChanges("Servers that differ:", d.ServersChanged)
+		printNames(fmt.Sprintf("Tools only in %s:", d.From), d.ToolsOnlyInFrom)
+		printNames(fmt.Sprintf("Tools only in %s:", d.To), d.ToolsOnlyInTo)
+		printChanges("Tools that differ:", d.ToolsChanged)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !d.Empty() {
+		return fmt.Errorf("the catalogs of %s and %s differ", d.From, d.To)
+	}
+	return nil
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	s, err := apiClient.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to take a snapshot of the catalog: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+	if snapshotCmdFile == "" {
+		cmd.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(snapshotCmdFile, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	cmd.Printf("Snapshot of %d servers and %d tools written to %s\n", len(s.Servers), len(s.Tools), snapshotCmdFile)
+	return nil
+}
+
+// loadCatalog reads the snapshot file at source or, if there's no such file, takes a snapshot of the registry
+// of the client context named source. An empty source stands for the registry in use.
+func loadCatalog(source string) (*types.CatalogSnapshot, error) {
+	if source == "" {
+		return apiClient.Snapshot()
+	}
+	data, err := os.ReadFile(source)
+	if err == nil {
+		var s types.CatalogSnapshot
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("invalid snapshot file %s: %w", source, err)
+		}
+		return &s, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read snapshot file %s: %w", source, err)
+	}
+
+	cfg := config.Load()
+	if _, ok := cfg.Contexts[source]; !ok {
+		return nil, fmt.Errorf("'%s' is neither a snapshot file nor a client context", source)
+	}
+	c, _, err := newContextClient(cfg, source, "", false)
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take a snapshot of context %s: %w", source, err)
+	}
+	return s, nil
+}
+
+// catalogLabel names a catalog in the output of diff.
+func catalogLabel(source string, s *types.CatalogSnapshot) string {
+	if source == "" {
+		return s.Registry
+	}
+	return source
+}
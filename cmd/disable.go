@@ -16,13 +16,16 @@ var disableCmd = &cobra.Command{
 	},
 }
 
+var disableCmdVersion int
+
 func init() {
+	disableCmd.Flags().IntVar(&disableCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
 	rootCmd.AddCommand(disableCmd)
 }
 
 func runDisableTools(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	toolsDisabled, err := apiClient.DisableTools(name)
+	toolsDisabled, err := apiClient.DisableTools(name, disableCmdVersion)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	emergencyDisableCmdAllow  string
+	emergencyDisableCmdReason string
+)
+
+var emergencyCmd = &cobra.Command{
+	Use:   "emergency",
+	Short: "Disable all tool calls at once during an incident",
+	Long: "The break-glass switch instantly disables all tool calls, via the MCP proxies and the API, on all instances\n" +
+		"of the registry, eg- when an agent misbehaves. Tools stay registered and can still be listed.\n" +
+		"Turning it on or off raises a notification. It works even while the registry is in read-only mode.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "31",
+	},
+}
+
+var emergencyDisableCmd = &cobra.Command{
+	Use:   "disable-all",
+	Args:  cobra.NoArgs,
+	Short: "Disable all tool calls, optionally except some tools or servers",
+	Long: "Disable all tool calls until 'mcpjungle emergency enable-all'.\n" +
+		"\neg- mcpjungle emergency disable-all --allow pagerduty,slack__post_message --reason \"agent deleting prod data\"",
+	RunE: runEmergencyDisable,
+}
+
+var emergencyEnableCmd = &cobra.Command{
+	Use:   "enable-all",
+	Args:  cobra.NoArgs,
+	Short: "Enable tool calls again",
+	RunE:  runEmergencyEnable,
+}
+
+var emergencyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Args:  cobra.NoArgs,
+	Short: "Show whether tool calls are disabled",
+	RunE:  runEmergencyStatus,
+}
+
+func init() {
+	emergencyDisableCmd.Flags().StringVar(
+		&emergencyDisableCmdAllow,
+		"allow",
+		"",
+		"Comma-separated list of tools (canonical names) and MCP servers that can still be called",
+	)
+	emergencyDisableCmd.Flags().StringVar(&emergencyDisableCmdReason, "reason", "", "Reason, shown to the callers of the tools")
+
+	emergencyCmd.AddCommand(emergencyDisableCmd)
+	emergencyCmd.AddCommand(emergencyEnableCmd)
+	emergencyCmd.AddCommand(emergencyStatusCmd)
+	rootCmd.AddCommand(emergencyCmd)
+}
+
+func runEmergencyDisable(cmd *cobra.Command, args []string) error {
+	status, err := apiClient.EmergencyDisableAll(&types.EmergencyDisableInput{
+		AllowedTools: splitCommaList(emergencyDisableCmdAllow),
+		Reason:       emergencyDisableCmdReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to disable tool calls: %w", err)
+	}
+	return renderOutput(cmd, status, func() error {
+		printEmergencyStatus(cmd, status)
+		return nil
+	})
+}
+
+func runEmergencyEnable(cmd *cobra.Command, args []string) error {
+	status, err := apiClient.EmergencyEnableAll()
+	if err != nil {
+		return fmt.Errorf("failed to enable tool calls: %w", err)
+	}
+	return renderOutput(cmd, status, func() error {
+		printEmergencyStatus(cmd, status)
+		return nil
+	})
+}
+
+func runEmergencyStatus(cmd *cobra.Command, args []string) error {
+	status, err := apiClient.GetEmergencyStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get the emergency status: %w", err)
+	}
+	return renderOutput(cmd, status, func() error {
+		printEmergencyStatus(cmd, status)
+		return nil
+	})
+}
+
+func printEmergencyStatus(cmd *cobra.Command, status *types.EmergencyStatus) {
+	if !status.Active {
+		cmd.Println("Tool calls are enabled")
+		return
+	}
+	cmd.Println("ALL TOOL CALLS ARE DISABLED")
+	if len(status.AllowedTools) > 0 {
+		cmd.Println("Except: " + strings.Join(status.AllowedTools, ","))
+	}
+	if status.Reason != "" {
+		cmd.Println("Reason: " + status.Reason)
+	}
+	if status.ActivatedAt != nil {
+		since := status.ActivatedAt.Local().Format(time.DateTime)
+		if status.ActivatedBy != "" {
+			since += " by " + status.ActivatedBy
+		}
+		cmd.Println("Since: " + since)
+	}
+}
@@ -19,13 +19,16 @@ var enableCmd = &cobra.Command{
 	},
 }
 
+var enableCmdVersion int
+
 func init() {
+	enableCmd.Flags().IntVar(&enableCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
 	rootCmd.AddCommand(enableCmd)
 }
 
 func runEnableTools(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	toolsEnabled, err := apiClient.EnableTools(name)
+	toolsEnabled, err := apiClient.EnableTools(name, enableCmdVersion)
 	if err != nil {
 		return fmt.Errorf("failed to enable %s: %w", name, err)
 	}
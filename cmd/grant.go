@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	grantCmdClient    string
+	grantCmdToolGroup string
+	grantCmdDuration  time.Duration
+	grantCmdReason    string
+)
+
+var grantCmd = &cobra.Command{
+	Use:   "grant",
+	Args:  cobra.NoArgs,
+	Short: "Grant an MCP client temporary access to a tool group",
+	Long: "Allow an MCP client to call the tools of a tool group for a limited time (at most 7 days),\n" +
+		"regardless of the MCP servers in its allow list, eg- when an on-call agent temporarily needs elevated tools.\n" +
+		"The grant expires on its own. List active grants with 'mcpjungle list grants' and revoke one early\n" +
+		"with 'mcpjungle delete grant <id>'.\n" +
+		"\neg- mcpjungle grant --client oncall-agent --tool-group k8s-admin --duration 2h --reason INC-1234",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "30",
+	},
+	RunE: runGrant,
+}
+
+func init() {
+	grantCmd.Flags().StringVar(&grantCmdClient, "client", "", "Name of the MCP client that is granted access")
+	grantCmd.Flags().StringVar(&grantCmdToolGroup, "tool-group", "", "Name of the tool group whose tools the client can call")
+	grantCmd.Flags().DurationVar(&grantCmdDuration, "duration", time.Hour, "How long the grant lasts (eg- 30m, 2h)")
+	grantCmd.Flags().StringVar(&grantCmdReason, "reason", "", "Justification of the grant, eg- an incident number")
+	_ = grantCmd.MarkFlagRequired("client")
+	_ = grantCmd.MarkFlagRequired("tool-group")
+	rootCmd.AddCommand(grantCmd)
+}
+
+func runGrant(cmd *cobra.Command, args []string) error {
+	g, err := apiClient.GrantAccess(&types.CreateAccessGrantInput{
+		Client:    grantCmdClient,
+		ToolGroup: grantCmdToolGroup,
+		Duration:  grantCmdDuration.String(),
+		Reason:    grantCmdReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to grant access: %w", err)
+	}
+	return renderOutput(cmd, g, func() error {
+		cmd.Printf(
+			"Granted MCP client %s access to tool group %s until %s (grant ID: %d)\n",
+			g.Client, g.ToolGroup, g.ExpiresAt.Local().Format(time.DateTime), g.ID,
+		)
+		return nil
+	})
+}
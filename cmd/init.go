@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// initEnvFile is the file that the server's configuration is written to, the server loads it at startup
+const initEnvFile = ".env"
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Args:  cobra.NoArgs,
+	Short: "Set up MCPJungle interactively",
+	Long: "Walk through the setup of an MCPJungle server: choose the mode it runs in, its database and port,\n" +
+		"and where its metrics are exported to. The configuration is written to the .env file of the current\n" +
+		"directory, which the server loads when started from it.\n" +
+		"In Production mode, the server is initialized and the admin user created once the server is running.",
+	RunE: runInit,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupBasic),
+		"order": "0",
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	fmt.Println("This wizard will guide you through setting up MCPJungle.")
+	fmt.Println()
+
+	env, err := promptServerEnv()
+	if err != nil {
+		return err
+	}
+	mode := model.ServerMode(env[ServerModeEnvVar])
+	port := env[BindPortEnvVar]
+
+	fmt.Println()
+	fmt.Println("Configuration:")
+	for _, name := range configEnvVars {
+		if v, ok := env[name]; ok {
+			if isSecretEnvVar(name) {
+				v = "(hidden)"
+			}
+			fmt.Printf("  %s=%s\n", name, v)
+		}
+	}
+	confirm, err := promptLine(fmt.Sprintf("Write this configuration to %s? [Y/n]: ", initEnvFile))
+	if err != nil {
+		return err
+	}
+	written := confirm == "" || strings.EqualFold(confirm, "y") || strings.EqualFold(confirm, "yes")
+	if written {
+		if err := writeEnvFile(initEnvFile, env); err != nil {
+			return err
+		}
+		fmt.Printf("Configuration written to %s\n", initEnvFile)
+	}
+
+	fmt.Println()
+	// the server can only be initialized from here if the CLI talks to the configured server
+	registryFlag, reachable := "", true
+	if cmd.Flags().Changed("registry") {
+		registryFlag = " --registry " + registryServerURL
+	} else if port != BindPortDefault {
+		registryFlag, reachable = " --registry http://127.0.0.1:"+port, false
+	}
+	initialized := false
+	if mode == model.ModeProd && reachable {
+		initialized, err = promptInitProdServer()
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Next steps:")
+	step := 1
+	if !written {
+		fmt.Printf("  %d. Set the environment variables of the configuration above\n", step)
+		step++
+	}
+	if !initialized {
+		fmt.Printf("  %d. Start the server from this directory: mcpjungle start\n", step)
+		step++
+		if mode == model.ModeProd {
+			fmt.Printf("  %d. Create the admin user: mcpjungle%s init-server\n", step, registryFlag)
+			step++
+		}
+	}
+	fmt.Printf("  %d. Register your first MCP server: mcpjungle%s register --interactive\n", step, registryFlag)
+	step++
+	fmt.Printf("  %d. Point your MCP clients to http://127.0.0.1:%s/mcp\n", step, port)
+	if mode == model.ModeProd {
+		fmt.Printf("     and give each of them an access token: mcpjungle%s create mcp-client --help\n", registryFlag)
+	}
+	return nil
+}
+
+// promptServerEnv asks the user for the configuration of the server and returns it as environment variables.
+func promptServerEnv() (map[string]string, error) {
+	env := make(map[string]string)
+
+	fmt.Println("Development mode is meant for individual users: no authentication is required.")
+	fmt.Println("Production mode is meant for teams: users & MCP clients need access tokens, issued by an admin.")
+	mode, err := promptLine("Mode (development or production) [development]: ")
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(mode) {
+	case "", "dev", string(model.ModeDev):
+		env[ServerModeEnvVar] = string(model.ModeDev)
+	case "prod", string(model.ModeProd):
+		env[ServerModeEnvVar] = string(model.ModeProd)
+	default:
+		return nil, fmt.Errorf("invalid mode '%s', must be development or production", mode)
+	}
+
+	fmt.Println()
+	dsn, err := promptSecret("PostgreSQL database URL, leave empty to use an embedded SQLite database (input is hidden): ")
+	if err != nil {
+		return nil, err
+	}
+	if dsn != "" {
+		fmt.Println("Connecting to the database...")
+		if err := checkDatabase(dsn); err != nil {
+			fmt.Printf("Failed to connect to the database: %v\n", err)
+			keep, err := promptLine("Use this database URL anyway? [y/N]: ")
+			if err != nil {
+				return nil, err
+			}
+			if !strings.EqualFold(keep, "y") && !strings.EqualFold(keep, "yes") {
+				return nil, errors.New("aborted, no database configured")
+			}
+		} else {
+			fmt.Println("Connected to the database.")
+		}
+		env[DBUrlEnvVar] = dsn
+	}
+
+	port, err := promptLine(fmt.Sprintf("Port [%s]: ", BindPortDefault))
+	if err != nil {
+		return nil, err
+	}
+	if port == "" {
+		port = BindPortDefault
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return nil, fmt.Errorf("invalid port '%s'", port)
+	}
+	env[BindPortEnvVar] = port
+
+	fmt.Println()
+	fmt.Println("Metrics are always served on /metrics. They can also be pushed to a Prometheus remote-write endpoint\n" +
+		"or sent to a Datadog agent, if nothing can scrape the server.")
+	export, err := promptLine("Export metrics (none, remote-write or statsd) [none]: ")
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(export) {
+	case "", "none":
+	case "remote-write":
+		u, err := promptRequired("Remote-write URL (eg- https://prometheus.example.com/api/v1/write): ")
+		if err != nil {
+			return nil, err
+		}
+		if pu, err := url.Parse(u); err != nil || (pu.Scheme != "http" && pu.Scheme != "https") {
+			return nil, fmt.Errorf("invalid remote-write URL '%s'", u)
+		}
+		env[MetricsRemoteWriteURLEnvVar] = u
+		token, err := promptSecret("Bearer token (optional, input is hidden): ")
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			env[MetricsRemoteWriteBearerTokenEnvVar] = token
+		}
+	case "statsd":
+		addr, err := promptLine("DogStatsD agent address [127.0.0.1:8125]: ")
+		if err != nil {
+			return nil, err
+		}
+		if addr == "" {
+			addr = "127.0.0.1:8125"
+		}
+		env[StatsdAddrEnvVar] = addr
+		tags, err := promptLine("Tags added to all the metrics (eg- env:prod,team:platform, optional): ")
+		if err != nil {
+			return nil, err
+		}
+		if tags != "" {
+			env[StatsdTagsEnvVar] = tags
+		}
+	default:
+		return nil, fmt.Errorf("invalid metrics export '%s', must be none, remote-write or statsd", export)
+	}
+	return env, nil
+}
+
+// checkDatabase connects to a PostgreSQL database to check that the server will be able to use it.
+func checkDatabase(dsn string) error {
+	conn, err := db.NewDBConnection(dsn, db.Options{})
+	if err != nil {
+		return err
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	return sqlDB.Ping()
+}
+
+// promptInitProdServer initializes the server running in Production mode once the user has started it.
+// It returns false if the user skipped the initialization.
+func promptInitProdServer() (bool, error) {
+	fmt.Println("In Production mode, the server must be initialized to create the admin user.")
+	answer, err := promptLine(
+		"Start the server from this directory in another terminal (mcpjungle start), " +
+			"then press Enter to initialize it, or type 'skip': ",
+	)
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(answer, "skip") {
+		return false, nil
+	}
+	meta, err := apiClient.GetMetadata()
+	if err != nil {
+		fmt.Printf("Failed to reach the server at %s: %v\n", activeRegistryURL, err)
+		return false, nil
+	}
+	if meta.Initialized {
+		fmt.Println("The server is already initialized.")
+		return true, nil
+	}
+	if err := initProdServer(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeEnvFile adds the given variables to an env file, keeping the other variables already in it.
+// The file is only readable by its owner since it can contain secrets.
+func writeEnvFile(path string, vars map[string]string) error {
+	env := make(map[string]string)
+	if _, err := os.Stat(path); err == nil {
+		if env, err = godotenv.Read(path); err != nil {
+			return fmt.Errorf("failed to read existing %s: %w", path, err)
+		}
+	}
+	for k, v := range vars {
+		env[k] = v
+	}
+	content, err := godotenv.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to serialize configuration: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return os.Chmod(path, 0o600)
+}
+
+// isSecretEnvVar returns true if the value of an environment variable must not be displayed.
+func isSecretEnvVar(name string) bool {
+	switch name {
+	case DBUrlEnvVar, DBReadReplicaUrlEnvVar, RateLimitRedisURLEnvVar, MetricsRemoteWriteBearerTokenEnvVar, MetricsRemoteWritePasswordEnvVar:
+		return true
+	}
+	return false
+}
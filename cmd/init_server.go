@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/mcpjungle/mcpjungle/cmd/config"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +22,11 @@ func init() {
 }
 
 func runInitServer(cmd *cobra.Command, args []string) error {
+	return initProdServer()
+}
+
+// initProdServer initializes the server in Production mode, creating the admin user, and stores the admin's access token.
+func initProdServer() error {
 	fmt.Println("Initializing the MCPJungle Server in Production Mode...")
 	resp, err := apiClient.InitServer()
 	if err != nil {
@@ -33,19 +37,11 @@ func runInitServer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("server initialization failed: no admin access token received")
 	}
 
-	// Create new client configuration
-	cfg := &config.ClientConfig{
-		AccessToken: resp.AdminAccessToken,
-	}
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("failed to create client configuration: %w", err)
-	}
-
-	cfgPath, err := config.AbsPath()
+	storage, err := storeAccessToken(resp.AdminAccessToken)
 	if err != nil {
-		return fmt.Errorf("failed to get client configuration path: %w", err)
+		return err
 	}
-	fmt.Println("Your Admin access token has been saved to", cfgPath)
+	fmt.Printf("Your Admin access token has been saved to your %s\n", storage)
 
 	fmt.Println("All done!")
 	return nil
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/joho/godotenv"
+)
+
+func TestWriteEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("GITHUB_TOKEN=abc\nPORT=9000\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeEnvFile(path, map[string]string{BindPortEnvVar: "8081", DBUrlEnvVar: "postgres://u:p@db/mcp?sslmode=disable"})
+	if err != nil {
+		t.Fatalf("writeEnvFile() error = %v", err)
+	}
+	env, err := godotenv.Read(path)
+	if err != nil {
+		t.Fatalf("failed to read written env file: %v", err)
+	}
+	want := map[string]string{
+		"GITHUB_TOKEN": "abc",
+		"PORT":         "8081",
+		"DATABASE_URL": "postgres://u:p@db/mcp?sslmode=disable",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0o600 {
+			t.Errorf("env file mode = %v, want 0600", fi.Mode().Perm())
+		}
+	}
+}
@@ -4,12 +4,22 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 	"os"
-	"time"
+	"os/exec"
+	"strings"
 )
 
-var invokeCmdInput string
+var (
+	invokeCmdInput       string
+	invokeCmdInteractive bool
+	invokeCmdEdit        bool
+	invokeCmdRaw         bool
+
+	invokeCmdAsClient string
+	invokeCmdAsUser   string
+)
 
 var invokeToolCmd = &cobra.Command{
 	Use:   "invoke <name>",
@@ -25,6 +35,42 @@ var invokeToolCmd = &cobra.Command{
 
 func init() {
 	invokeToolCmd.Flags().StringVar(&invokeCmdInput, "input", "{}", "valid JSON payload")
+	invokeToolCmd.Flags().BoolVarP(
+		&invokeCmdInteractive,
+		"interactive",
+		"i",
+		false,
+		"Prompt for every property of the tool's input schema, validate the values and confirm the input\n"+
+			"before invoking the tool.",
+	)
+	invokeToolCmd.Flags().BoolVarP(
+		&invokeCmdEdit,
+		"edit",
+		"e",
+		false,
+		"Open the tool's input in your $EDITOR before invoking it.\n"+
+			"The input is prefilled with an example generated from the tool's input schema.",
+	)
+	invokeToolCmd.MarkFlagsMutuallyExclusive("interactive", "edit")
+	invokeToolCmd.Flags().BoolVar(
+		&invokeCmdRaw,
+		"raw",
+		false,
+		"Print the result as JSON as is, instead of rendering tables, markdown and saving images to files",
+	)
+	invokeToolCmd.Flags().StringVar(
+		&invokeCmdAsClient,
+		"as-client",
+		"",
+		"(admin only) Invoke the tool exactly as the given MCP client would, to troubleshoot its access",
+	)
+	invokeToolCmd.Flags().StringVar(
+		&invokeCmdAsUser,
+		"as-user",
+		"",
+		"(admin only) Invoke the tool exactly as the given user would, to troubleshoot their access",
+	)
+	invokeToolCmd.MarkFlagsMutuallyExclusive("as-client", "as-user")
 	rootCmd.AddCommand(invokeToolCmd)
 }
 
@@ -96,16 +142,95 @@ func getAudioContent(c map[string]any) ([]byte, string, error) {
 	return audioData, ext, nil
 }
 
+// editToolInput opens the user's editor with an example input generated from the tool's schema
+// and returns the JSON payload saved by the user.
+func editToolInput(name string) (string, error) {
+	t, err := apiClient.GetTool(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tool '%s': %w", name, err)
+	}
+	example, err := json.MarshalIndent(generateToolExampleInput(t), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate example input: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "mcpjungle-invoke-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(example); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	f.Close()
+
+	editorCmd := editorCommand(os.Getenv("EDITOR"), f.Name())
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run editor %s: %w", editorCmd.Args[0], err)
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited input: %w", err)
+	}
+	return string(edited), nil
+}
+
+// editorCommand returns the command that opens a file in the given editor.
+// The editor may contain arguments (eg- "code --wait"), vi is used if it is empty.
+func editorCommand(editor, file string) *exec.Cmd {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], file)...)
+}
+
 func runInvokeTool(cmd *cobra.Command, args []string) error {
+	rawInput := invokeCmdInput
+	var err error
+	switch {
+	case invokeCmdInteractive:
+		rawInput, err = fillToolInputForm(args[0])
+	case invokeCmdEdit:
+		rawInput, err = editToolInput(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
 	var input map[string]any
-	if err := json.Unmarshal([]byte(invokeCmdInput), &input); err != nil {
+	if err := json.Unmarshal([]byte(rawInput), &input); err != nil {
 		return fmt.Errorf("invalid input: %w", err)
 	}
 
-	result, err := apiClient.InvokeTool(args[0], input)
+	var as *types.Impersonation
+	if invokeCmdAsClient != "" || invokeCmdAsUser != "" {
+		as = &types.Impersonation{Client: invokeCmdAsClient, User: invokeCmdAsUser}
+	}
+	result, err := apiClient.InvokeToolAs(args[0], input, as)
 	if err != nil {
 		return fmt.Errorf("failed to invoke tool: %w", err)
 	}
+	return printToolInvokeResult(result, invokeCmdRaw)
+}
+
+// printToolInvokeResult prints the result of a tool invocation.
+// Text content is rendered according to what it contains (see renderTextContent), images and audio are saved
+// to temporary files whose paths are printed. If raw is true, the result is printed as JSON as is instead.
+func printToolInvokeResult(result *types.ToolInvokeResult, raw bool) error {
+	if raw {
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		fmt.Println(string(b))
+		return nil
+	}
 
 	if result.IsError {
 		fmt.Println("The tool returned an error:")
@@ -119,6 +244,7 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 	// result Content needs to be printed regardless of whether the tool returned an error or not
 	// because it may contain useful information
 	fmt.Println()
+	color := colorOutput()
 	for _, c := range result.Content {
 		cType, ok := c["type"]
 		if !ok {
@@ -132,15 +258,15 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			fmt.Println(textContent)
+			renderTextContent(os.Stdout, textContent, color)
 
 		case "image":
 			imgData, ext, err := getImageContent(c)
 			if err != nil {
 				return err
 			}
-			filename := fmt.Sprintf("image_%d%s", time.Now().UnixNano(), ext)
-			if err := os.WriteFile(filename, imgData, 0644); err != nil {
+			filename, err := writeTempFile("mcpjungle-image-*"+ext, imgData)
+			if err != nil {
 				return fmt.Errorf("failed to write image to disk: %w", err)
 			}
 			fmt.Printf("[Image saved as %s]\n", filename)
@@ -150,13 +276,37 @@ func runInvokeTool(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			filename := fmt.Sprintf("audio_%d%s", time.Now().UnixNano(), ext)
-			if err := os.WriteFile(filename, audioData, 0644); err != nil {
+			filename, err := writeTempFile("mcpjungle-audio-*"+ext, audioData)
+			if err != nil {
 				return fmt.Errorf("failed to write audio to disk: %w", err)
 			}
 			fmt.Printf("[Audio saved as %s]\n", filename)
+
+		case "resource_link":
+			fmt.Printf("[Resource %v]\n", c["uri"])
+
+		case "resource":
+			resource, _ := c["resource"].(map[string]any)
+			fmt.Printf("[Resource %v]\n", resource["uri"])
+			if text, ok := resource["text"].(string); ok {
+				renderTextContent(os.Stdout, text, color)
+			}
 		}
 	}
 
 	return nil
 }
+
+// writeTempFile writes data to a new file in the temporary directory, named after the given pattern
+// (see os.CreateTemp), and returns its path.
+func writeTempFile(pattern string, data []byte) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/schema"
+)
+
+// errInvocationCancelled is returned when the user doesn't confirm the input filled in the form
+var errInvocationCancelled = errors.New("invocation cancelled")
+
+// fillToolInputForm prompts for every property of a tool's input schema, required ones first.
+// Every value is validated against the property's schema before moving on to the next one,
+// then the whole input is shown and the user is asked to confirm it.
+func fillToolInputForm(name string) (string, error) {
+	t, err := apiClient.GetTool(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tool '%s': %w", name, err)
+	}
+	properties := t.InputSchema.Properties
+	input := make(map[string]any, len(properties))
+	if len(properties) == 0 {
+		fmt.Printf("Tool %s takes no input.\n", name)
+	} else {
+		fmt.Printf("Enter the input of %s, properties marked with * are required.\n", name)
+	}
+
+	required := make(map[string]bool, len(t.InputSchema.Required))
+	for _, r := range t.InputSchema.Required {
+		required[r] = true
+	}
+	for _, prop := range schema.SortedPropertyNames(properties, t.InputSchema.Required) {
+		ps, _ := properties[prop].(map[string]any)
+		fmt.Println()
+		fmt.Println(formFieldHeader(prop, ps, required[prop]))
+		for {
+			answer, err := promptLine(formFieldPrompt(prop, ps))
+			if err != nil {
+				return "", err
+			}
+			v, set, err := parseFormValue(ps, answer, required[prop])
+			if err != nil {
+				fmt.Printf("  %v\n", err)
+				continue
+			}
+			if set {
+				input[prop] = v
+			}
+			break
+		}
+	}
+
+	inputSchema := map[string]any{"type": "object", "properties": properties}
+	if len(t.InputSchema.Required) > 0 {
+		requiredNames := make([]any, len(t.InputSchema.Required))
+		for i, r := range t.InputSchema.Required {
+			requiredNames[i] = r
+		}
+		inputSchema["required"] = requiredNames
+	}
+	if violations := schema.Validate(inputSchema, input); len(violations) > 0 {
+		return "", fmt.Errorf("the input does not conform to the tool's input schema:\n  %s", strings.Join(violations, "\n  "))
+	}
+
+	payload, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode input: %w", err)
+	}
+	fmt.Printf("\nInput:\n%s\n\n", payload)
+	confirm, err := promptLine(fmt.Sprintf("Invoke %s with this input? [Y/n]: ", name))
+	if err != nil {
+		return "", err
+	}
+	if confirm != "" && !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		return "", errInvocationCancelled
+	}
+	return string(payload), nil
+}
+
+// formFieldHeader describes a property of the form: its name, type, description and allowed values.
+func formFieldHeader(name string, ps map[string]any, required bool) string {
+	var b strings.Builder
+	b.WriteString(name)
+	if required {
+		b.WriteString("*")
+	}
+	if t := schema.SchemaType(ps); t != "" {
+		if t == "array" {
+			if items, ok := ps["items"].(map[string]any); ok && schema.SchemaType(items) != "" {
+				t = "array of " + schema.SchemaType(items)
+			}
+		}
+		b.WriteString(" (" + t + ")")
+	}
+	if d, ok := ps["description"].(string); ok && d != "" {
+		b.WriteString(": " + d)
+	}
+	if enum, ok := ps["enum"].([]any); ok && len(enum) > 0 {
+		choices := make([]string, len(enum))
+		for i, e := range enum {
+			choices[i] = fmt.Sprintf("%d) %v", i+1, e)
+		}
+		b.WriteString("\n  choices: " + strings.Join(choices, ", "))
+	}
+	switch schema.SchemaType(ps) {
+	case "array":
+		b.WriteString("\n  enter a JSON array, or comma-separated values")
+	case "object":
+		b.WriteString("\n  enter a JSON object")
+	}
+	return b.String()
+}
+
+// formFieldPrompt returns the prompt of a property, showing its default value if it has one.
+func formFieldPrompt(name string, ps map[string]any) string {
+	if d, ok := ps["default"]; ok {
+		b, _ := json.Marshal(d)
+		return fmt.Sprintf("  %s [%s]: ", name, b)
+	}
+	return fmt.Sprintf("  %s: ", name)
+}
+
+// parseFormValue converts the answer entered for a property into a value of the property's type and validates it.
+// set is false if the property is left out of the input, ie, the answer is empty and the property has no default.
+func parseFormValue(ps map[string]any, answer string, required bool) (v any, set bool, err error) {
+	if answer == "" {
+		if d, ok := ps["default"]; ok {
+			return d, true, nil
+		}
+		if required {
+			return nil, false, errors.New("a value is required")
+		}
+		return nil, false, nil
+	}
+
+	if enum, ok := ps["enum"].([]any); ok && len(enum) > 0 {
+		// a choice is entered as is, or picked by its number
+		for _, e := range enum {
+			if fmt.Sprint(e) == answer {
+				return e, true, nil
+			}
+		}
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(enum) {
+			return enum[i-1], true, nil
+		}
+	}
+
+	switch schema.SchemaType(ps) {
+	case "string":
+		v = answer
+	case "integer":
+		n, err := strconv.ParseInt(answer, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("'%s' is not an integer", answer)
+		}
+		v = float64(n)
+	case "number":
+		n, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("'%s' is not a number", answer)
+		}
+		v = n
+	case "boolean":
+		switch strings.ToLower(answer) {
+		case "y", "yes", "true":
+			v = true
+		case "n", "no", "false":
+			v = false
+		default:
+			return nil, false, fmt.Errorf("'%s' is not a boolean, answer yes or no", answer)
+		}
+	case "array":
+		if strings.HasPrefix(answer, "[") {
+			if err := json.Unmarshal([]byte(answer), &v); err != nil {
+				return nil, false, fmt.Errorf("invalid JSON array: %w", err)
+			}
+			break
+		}
+		items, _ := ps["items"].(map[string]any)
+		var list []any
+		for _, item := range strings.Split(answer, ",") {
+			iv, _, err := parseFormValue(items, strings.TrimSpace(item), true)
+			if err != nil {
+				return nil, false, err
+			}
+			list = append(list, iv)
+		}
+		v = list
+	case "object":
+		if err := json.Unmarshal([]byte(answer), &v); err != nil {
+			return nil, false, fmt.Errorf("invalid JSON object: %w", err)
+		}
+	default:
+		// the type is not declared, the answer is taken as JSON if it is valid JSON and as a string otherwise
+		if err := json.Unmarshal([]byte(answer), &v); err != nil {
+			v = answer
+		}
+	}
+
+	if violations := schema.Validate(ps, v); len(violations) > 0 {
+		return nil, false, errors.New(strings.Join(violations, ", "))
+	}
+	return v, true, nil
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+)
+
+// maxTableCellWidth is the width beyond which the values in tables rendered from JSON results are truncated
+const maxTableCellWidth = 40
+
+// ANSI escape sequences used to render markdown in the terminal
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiItalic    = "\033[3m"
+	ansiUnderline = "\033[4m"
+	ansiCyan      = "\033[36m"
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBold    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalic  = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*)\*|(^|[^_\w])_([^_\s][^_]*)_`)
+	markdownCode    = regexp.MustCompile("`([^`]+)`")
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownList    = regexp.MustCompile(`^(\s*)([-*+]|\d+\.)\s+`)
+)
+
+// colorOutput returns true if stdout is a terminal that ANSI escape sequences can be written to.
+// Colors are turned off by setting NO_COLOR (see no-color.org).
+func colorOutput() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// renderTextContent writes the text returned by a tool in the most readable form:
+// arrays of JSON objects as tables, other JSON values indented, markdown with ANSI styles and anything else as is.
+func renderTextContent(w io.Writer, text string, color bool) {
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		var v any
+		if err := json.Unmarshal([]byte(trimmed), &v); err == nil {
+			if rows, ok := tableRows(v); ok {
+				renderTable(w, rows)
+				return
+			}
+			b, _ := json.MarshalIndent(v, "", "  ")
+			fmt.Fprintln(w, string(b))
+			return
+		}
+	}
+	if looksLikeMarkdown(text) {
+		fmt.Fprintln(w, renderMarkdown(text, color))
+		return
+	}
+	fmt.Fprintln(w, text)
+}
+
+// tableRows returns the rows of a JSON value that can be shown as a table: a non-empty array of objects,
+// or an object with a single field holding such an array (eg- {"results": [...]}).
+func tableRows(v any) ([]map[string]any, bool) {
+	if obj, ok := v.(map[string]any); ok && len(obj) == 1 {
+		for _, inner := range obj {
+			return tableRows(inner)
+		}
+	}
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return nil, false
+	}
+	rows := make([]map[string]any, len(items))
+	for i, item := range items {
+		row, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		rows[i] = row
+	}
+	return rows, true
+}
+
+// renderTable writes rows of JSON objects as a table, with a column for every field found in any row.
+// Nested values are shown as compact JSON and long values are truncated.
+func renderTable(w io.Writer, rows []map[string]any) {
+	var columns []string
+	for _, row := range rows {
+		var fields []string
+		for k := range row {
+			if !slices.Contains(columns, k) {
+				fields = append(fields, k)
+			}
+		}
+		// the order of the fields of JSON objects is lost, columns are sorted to be stable
+		slices.Sort(fields)
+		columns = append(columns, fields...)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = tableCell(row[c])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	_ = tw.Flush()
+}
+
+// tableCell formats a JSON value for a cell of a table
+func tableCell(v any) string {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	case float64, bool:
+		s = fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		s = string(b)
+	}
+	// cells must fit on one line for the columns to be aligned
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxTableCellWidth {
+		s = string(r[:maxTableCellWidth-1]) + "…"
+	}
+	return s
+}
+
+// looksLikeMarkdown returns true if the text contains markdown block elements (headings, lists, code blocks,
+// quotes) or links.
+func looksLikeMarkdown(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if markdownHeading.MatchString(line) ||
+			strings.HasPrefix(line, "```") ||
+			strings.HasPrefix(line, "> ") ||
+			markdownList.MatchString(line) ||
+			markdownLink.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderMarkdown renders markdown for the terminal: headings, emphasis, code and links are styled with ANSI
+// escape sequences and list bullets are replaced with •. Without color, only the markup is simplified.
+func renderMarkdown(text string, color bool) string {
+	var out []string
+	inCodeBlock := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			out = append(out, "    "+ansiStyle(line, color, ansiCyan))
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if m := markdownHeading.FindStringSubmatch(trimmed); m != nil {
+			heading := strings.TrimSpace(m[2])
+			if len(m[1]) == 1 {
+				out = append(out, ansiStyle(strings.ToUpper(heading), color, ansiBold, ansiUnderline))
+			} else {
+				out = append(out, ansiStyle(heading, color, ansiBold))
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			out = append(out, ansiStyle("│ "+renderInlineMarkdown(strings.TrimSpace(trimmed[1:]), color), color, ansiDim))
+			continue
+		}
+		if m := markdownList.FindStringSubmatch(line); m != nil {
+			bullet := m[2]
+			if !strings.HasSuffix(bullet, ".") {
+				bullet = "•"
+			}
+			line = m[1] + bullet + " " + line[len(m[0]):]
+		}
+		out = append(out, renderInlineMarkdown(line, color))
+	}
+	return strings.Join(out, "\n")
+}
+
+// ansiStyle wraps s in the given ANSI escape sequences if color is true
+func ansiStyle(s string, color bool, codes ...string) string {
+	if !color {
+		return s
+	}
+	return strings.Join(codes, "") + s + ansiReset
+}
+
+// renderInlineMarkdown styles the emphasis, code spans and links of a line of markdown
+func renderInlineMarkdown(line string, color bool) string {
+	line = markdownCode.ReplaceAllStringFunc(line, func(s string) string {
+		return ansiStyle(markdownCode.FindStringSubmatch(s)[1], color, ansiCyan)
+	})
+	line = markdownLink.ReplaceAllStringFunc(line, func(s string) string {
+		m := markdownLink.FindStringSubmatch(s)
+		return m[1] + " (" + ansiStyle(m[2], color, ansiUnderline) + ")"
+	})
+	line = markdownBold.ReplaceAllStringFunc(line, func(s string) string {
+		m := markdownBold.FindStringSubmatch(s)
+		return ansiStyle(m[1]+m[2], color, ansiBold)
+	})
+	line = markdownItalic.ReplaceAllStringFunc(line, func(s string) string {
+		m := markdownItalic.FindStringSubmatch(s)
+		return m[1] + m[3] + ansiStyle(m[2]+m[4], color, ansiItalic)
+	})
+	return line
+}
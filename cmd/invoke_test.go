@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{"", []string{"vi", "input.json"}},
+		{"nano", []string{"nano", "input.json"}},
+		{"code --wait", []string{"code", "--wait", "input.json"}},
+		{" vim  -u NONE ", []string{"vim", "-u", "NONE", "input.json"}},
+	}
+	for _, tt := range tests {
+		if got := editorCommand(tt.editor, "input.json").Args; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("editorCommand(%q) args = %v, want %v", tt.editor, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]any
+		answer   string
+		required bool
+		want     any
+		wantSet  bool
+		wantErr  bool
+	}{
+		{"string", map[string]any{"type": "string"}, "hello", false, "hello", true, false},
+		{"integer", map[string]any{"type": "integer"}, "42", false, 42.0, true, false},
+		{"not an integer", map[string]any{"type": "integer"}, "4.2", false, nil, false, true},
+		{"below minimum", map[string]any{"type": "integer", "minimum": 1.0}, "0", false, nil, false, true},
+		{"number", map[string]any{"type": "number"}, "0.5", false, 0.5, true, false},
+		{"boolean", map[string]any{"type": "boolean"}, "yes", false, true, true, false},
+		{"not a boolean", map[string]any{"type": "boolean"}, "maybe", false, nil, false, true},
+		{"enum value", map[string]any{"type": "string", "enum": []any{"asc", "desc"}}, "desc", false, "desc", true, false},
+		{"enum choice", map[string]any{"type": "string", "enum": []any{"asc", "desc"}}, "2", false, "desc", true, false},
+		{"not in enum", map[string]any{"type": "string", "enum": []any{"asc", "desc"}}, "up", false, nil, false, true},
+		{"numeric enum value", map[string]any{"type": "integer", "enum": []any{5.0, 1.0}}, "1", false, 1.0, true, false},
+		{"default", map[string]any{"type": "integer", "default": 10.0}, "", true, 10.0, true, false},
+		{"optional left out", map[string]any{"type": "string"}, "", false, nil, false, false},
+		{"required left out", map[string]any{"type": "string"}, "", true, nil, false, true},
+		{
+			"comma-separated array",
+			map[string]any{"type": "array", "items": map[string]any{"type": "integer"}},
+			"1, 2",
+			false,
+			[]any{1.0, 2.0},
+			true,
+			false,
+		},
+		{"JSON array", map[string]any{"type": "array"}, `["a", 1]`, false, []any{"a", 1.0}, true, false},
+		{"object", map[string]any{"type": "object"}, `{"a": 1}`, false, map[string]any{"a": 1.0}, true, false},
+		{"invalid object", map[string]any{"type": "object"}, `{"a"`, false, nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, set, err := parseFormValue(tt.schema, tt.answer, tt.required)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFormValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if set != tt.wantSet || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFormValue() = %v (set = %v), want %v (set = %v)", got, set, tt.want, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestRenderTextContent(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			"array of objects as a table",
+			`[{"name": "mcpjungle", "stars": 500}, {"name": "mcp-go", "stars": 6000, "archived": false}]`,
+			"NAME       STARS  ARCHIVED\nmcpjungle  500    \nmcp-go     6000   false\n",
+		},
+		{
+			"single field holding an array",
+			`{"results": [{"id": 1}, {"id": 2}]}`,
+			"ID\n1\n2\n",
+		},
+		{"other JSON indented", `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}\n"},
+		{
+			"markdown without color",
+			"# Report\n- **done**: see [docs](https://mcpjungle.com)\n```\ncode\n```",
+			"REPORT\n• done: see docs (https://mcpjungle.com)\n    code\n",
+		},
+		{"plain text", "hello world", "hello world\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			renderTextContent(&b, tt.text, false)
+			if b.String() != tt.want {
+				t.Errorf("renderTextContent() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownWithColor(t *testing.T) {
+	got := renderMarkdown("## Title\nuse `ls` and *care*", true)
+	want := ansiBold + "Title" + ansiReset + "\nuse " + ansiCyan + "ls" + ansiReset + " and " + ansiItalic + "care" + ansiReset
+	if got != want {
+		t.Errorf("renderMarkdown() = %q, want %q", got, want)
+	}
+}
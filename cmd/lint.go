@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the quality of resources",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "18",
+	},
+}
+
+var lintToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Flag tools with poor descriptions or schemas",
+	Long: "Check the enabled tools for missing or short descriptions, untyped parameters and names\n" +
+		"used by more than one MCP server. These make it harder for agents to pick and call the right tool.\n" +
+		"The command fails if any issue is found, so it can be used in CI.\n\n" +
+		"Issues marked as blocked come from rules enforced by the server (see the TOOL_LINT_ENFORCE env var),\n" +
+		"the MCP proxy does not expose those tools until they are fixed, eg- with 'mcpjungle set-tool-docs'.",
+	RunE: runLintTools,
+}
+
+func init() {
+	lintCmd.AddCommand(lintToolsCmd)
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLintTools(cmd *cobra.Command, args []string) error {
+	issues, err := apiClient.LintTools()
+	if err != nil {
+		return fmt.Errorf("failed to lint tools: %w", err)
+	}
+
+	err = renderOutput(cmd, issues, func() error {
+		if len(issues) == 0 {
+			fmt.Println("No issues found")
+			return nil
+		}
+		for _, i := range issues {
+			blocked := ""
+			if i.Blocked {
+				blocked = " [BLOCKED]"
+			}
+			fmt.Printf("%s: %s (%s)%s\n", i.Tool, i.Message, i.Rule, blocked)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d issue(s)", len(issues))
+	}
+	return nil
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 )
 
 var listCmd = &cobra.Command{
@@ -16,7 +20,10 @@ var listCmd = &cobra.Command{
 	},
 }
 
-var listToolsCmdServerName string
+var (
+	listToolsCmdServerName string
+	listToolsCmdView       string
+)
 
 var listToolsCmd = &cobra.Command{
 	Use:   "tools",
@@ -46,6 +53,116 @@ var listUsersCmd = &cobra.Command{
 	RunE:  runListUsers,
 }
 
+var listBudgetsCmd = &cobra.Command{
+	Use:   "budgets",
+	Short: "List spend budgets",
+	Long:  "List the budgets of MCP clients & users along with their spend in the current period.",
+	RunE:  runListBudgets,
+}
+
+var listSLOsCmd = &cobra.Command{
+	Use:   "slos",
+	Short: "List service level objectives",
+	Long:  "List the latency & error rate objectives of tools and MCP servers along with their compliance in the current window.",
+	RunE:  runListSLOs,
+}
+
+var listFaultsCmd = &cobra.Command{
+	Use:   "faults",
+	Short: "List fault injection rules",
+	Long:  "List the rules that inject faults into calls to tools and MCP servers while the fault_injection feature is on.",
+	RunE:  runListFaults,
+}
+
+var listPromptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "List prompt templates",
+	Long:  "List the prompt templates that the MCP proxy serves to MCP clients.",
+	RunE:  runListPrompts,
+}
+
+var listFavoritesCmd = &cobra.Command{
+	Use:   "favorites",
+	Short: "List the tools you starred (Production mode)",
+	RunE:  runListFavorites,
+}
+
+var listViewsCmd = &cobra.Command{
+	Use:   "views",
+	Short: "List your personal views on the tools (Production mode)",
+	RunE:  runListViews,
+}
+
+var listGrantsCmd = &cobra.Command{
+	Use:   "grants",
+	Short: "List the temporary access grants that haven't expired",
+	RunE:  runListGrants,
+}
+
+var listResidencyPoliciesCmd = &cobra.Command{
+	Use:   "residency-policies",
+	Short: "List data residency policies",
+	RunE:  runListResidencyPolicies,
+}
+
+var listPoliciesCmd = &cobra.Command{
+	Use:   "policies",
+	Short: "List tool invocation policies",
+	Long:  "List the policies evaluated before every tool invocation, in the order in which they are evaluated.",
+	RunE:  runListPolicies,
+}
+
+var listWebhookKeysCmd = &cobra.Command{
+	Use:   "webhook-keys",
+	Short: "List the keys used for signing outbound webhooks",
+	RunE:  runListWebhookKeys,
+}
+
+var listToolGroupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "List tool groups",
+	RunE:  runListToolGroups,
+}
+
+var listExtractionProfilesCmd = &cobra.Command{
+	Use:   "extraction-profiles",
+	Short: "List extraction profiles",
+	RunE:  runListExtractionProfiles,
+}
+
+var (
+	listInvocationsCmdTool  string
+	listInvocationsCmdLimit int
+)
+
+var listInvocationsCmd = &cobra.Command{
+	Use:   "invocations",
+	Short: "List recent tool invocations",
+	Long:  "List the most recent tool calls made via mcpjungle, newest first, along with their cost and number of attempts.",
+	RunE:  runListInvocations,
+}
+
+var (
+	listNotificationsCmdUnread   bool
+	listNotificationsCmdLimit    int
+	listNotificationsCmdMarkRead bool
+)
+
+var listNotificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "List notifications about operational events",
+	Long: "List notifications about operational events that need your attention, newest first.\n" +
+		"eg- MCP servers that mcpjungle failed to connect to and budgets that crossed their alert threshold.",
+	RunE: runListNotifications,
+}
+
+var listFeaturesCmd = &cobra.Command{
+	Use:   "features",
+	Short: "List experimental features",
+	Long:  "List the experimental features of mcpjungle and whether they are turned on in this deployment.",
+	RunE:  runListFeatures,
+}
+
 func init() {
 	listToolsCmd.Flags().StringVar(
 		&listToolsCmdServerName,
@@ -53,134 +170,679 @@ func init() {
 		"",
 		"Filter tools by server name",
 	)
+	listToolsCmd.Flags().StringVar(
+		&listToolsCmdView,
+		"view",
+		"",
+		"Only list the tools in one of your views: favorites, favorites-first or the name of a personal view (Production mode)",
+	)
 
 	listCmd.AddCommand(listToolsCmd)
 	listCmd.AddCommand(listServersCmd)
 	listCmd.AddCommand(listMcpClientsCmd)
 	listCmd.AddCommand(listUsersCmd)
+	listCmd.AddCommand(listBudgetsCmd)
+	listCmd.AddCommand(listSLOsCmd)
+	listCmd.AddCommand(listFaultsCmd)
+	listCmd.AddCommand(listPromptsCmd)
+	listCmd.AddCommand(listResidencyPoliciesCmd)
+	listCmd.AddCommand(listGrantsCmd)
+	listCmd.AddCommand(listFavoritesCmd)
+	listCmd.AddCommand(listViewsCmd)
+	listCmd.AddCommand(listPoliciesCmd)
+	listCmd.AddCommand(listWebhookKeysCmd)
+	listInvocationsCmd.Flags().StringVar(&listInvocationsCmdTool, "tool", "", "Filter invocations by tool name")
+	listInvocationsCmd.Flags().IntVar(&listInvocationsCmdLimit, "limit", 20, "Maximum number of invocations to list")
+	listCmd.AddCommand(listInvocationsCmd)
+
+	listCmd.AddCommand(listToolGroupsCmd)
+	listCmd.AddCommand(listExtractionProfilesCmd)
+
+	listCmd.AddCommand(listFeaturesCmd)
+
+	listNotificationsCmd.Flags().BoolVar(&listNotificationsCmdUnread, "unread", false, "Only list unread notifications")
+	listNotificationsCmd.Flags().IntVar(
+		&listNotificationsCmdLimit, "limit", 20, "Maximum number of notifications to list",
+	)
+	listNotificationsCmd.Flags().BoolVar(
+		&listNotificationsCmdMarkRead, "mark-read", false, "Mark the listed notifications as read",
+	)
+	listCmd.AddCommand(listNotificationsCmd)
 
 	rootCmd.AddCommand(listCmd)
 }
 
 func runListTools(cmd *cobra.Command, args []string) error {
-	tools, err := apiClient.ListTools(listToolsCmdServerName)
+	var (
+		tools []*types.Tool
+		err   error
+	)
+	switch {
+	case listToolsCmdView != "" && listToolsCmdServerName != "":
+		return fmt.Errorf("--view and --server cannot be used together")
+	case listToolsCmdView != "":
+		tools, err = apiClient.ListToolsInView(listToolsCmdView)
+	default:
+		tools, err = apiClient.ListTools(listToolsCmdServerName)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to list tools: %w", err)
 	}
 
-	if len(tools) == 0 {
-		fmt.Println("There are no tools in the registry")
+	return renderOutput(cmd, tools, func() error {
+		if len(tools) == 0 {
+			fmt.Println("There are no tools in the registry")
+			return nil
+		}
+		for i, t := range tools {
+			ed := "ENABLED"
+			if !t.Enabled {
+				ed = "DISABLED"
+			}
+			fmt.Printf("%d. %s  [%s]\n", i+1, t.Name, ed)
+			fmt.Println(t.Description)
+			fmt.Println()
+		}
+
+		fmt.Println("Run 'usage <tool name>' to see a tool's usage or 'invoke <tool name>' to call one")
+		return nil
+	})
+}
+
+func runListServers(cmd *cobra.Command, args []string) error {
+	servers, err := apiClient.ListServers()
+	if err != nil {
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	return renderOutput(cmd, servers, func() error {
+		if len(servers) == 0 {
+			fmt.Println("There are no MCP servers in the registry")
+			return nil
+		}
+		for i, s := range servers {
+			fmt.Printf("%d. %s\n", i+1, s.Name)
+
+			if s.Description != "" {
+				fmt.Println(s.Description)
+			}
+
+			fmt.Println("Transport: " + s.Transport)
+			if s.Version > 0 {
+				fmt.Printf("Version: %d\n", s.Version)
+			}
+			if s.Region != "" {
+				fmt.Println("Region: " + s.Region)
+			}
+			if s.ReplicaOf != "" {
+				fmt.Println("Replica of: " + s.ReplicaOf)
+			}
+			if s.KeepWarm {
+				fmt.Println("Kept warm: yes")
+			}
+			if s.Group != "" {
+				fmt.Println("Group: " + s.Group)
+			}
+			if s.Stopped {
+				fmt.Println("Stopped: yes")
+			}
+			if s.Status == types.ServerStatusRestarting || s.Restarts > 0 {
+				fmt.Printf("Status: %s (restarted %d times after crashing)\n", s.Status, s.Restarts)
+			}
+			if s.ServerInfo != nil {
+				identity := s.ServerInfo.Name + " " + s.ServerInfo.Version
+				if s.IdentityPinning != "" && s.IdentityPinning != string(types.IdentityPinningOff) {
+					identity += " (pinned: " + s.IdentityPinning + ")"
+				}
+				fmt.Println("Identity: " + identity)
+			}
+			if rp := s.RetryPolicy; rp != nil {
+				fmt.Printf("Retries: up to %d attempts\n", rp.MaxAttempts)
+			}
+
+			switch types.McpServerTransport(s.Transport) {
+			case types.TransportStreamableHTTP:
+				fmt.Println("URL: " + s.URL)
+			case types.TransportStdio:
+				if len(s.Args) > 0 {
+					fmt.Println("Command: " + s.Command + " " + strings.Join(s.Args, " "))
+				} else {
+					fmt.Println("Command: " + s.Command)
+				}
+
+				if len(s.Env) > 0 {
+					fmt.Printf("Environment variables: %s\n", s.Env)
+				}
+			}
+
+			if i < len(servers)-1 {
+				fmt.Println()
+			}
+		}
 		return nil
+	})
+}
+
+func runListMcpClients(cmd *cobra.Command, args []string) error {
+	clients, err := apiClient.ListMcpClients()
+	if err != nil {
+		return fmt.Errorf("failed to list MCP clients: %w", err)
 	}
-	for i, t := range tools {
-		ed := "ENABLED"
-		if !t.Enabled {
-			ed = "DISABLED"
+
+	return renderOutput(cmd, clients, func() error {
+		if len(clients) == 0 {
+			fmt.Println("There are no MCP clients in the registry")
+			return nil
+		}
+		for i, c := range clients {
+			fmt.Printf("%d. %s\n", i+1, c.Name)
+
+			if c.Description != "" {
+				fmt.Println("Description: ", c.Description)
+			}
+
+			if len(c.AllowList) > 0 {
+				fmt.Println("Allowed servers: " + strings.Join(c.AllowList, ","))
+			} else {
+				fmt.Println("This client does not have access to any MCP servers.")
+			}
+			if len(c.IPAllowList) > 0 {
+				fmt.Println("Allowed networks: " + strings.Join(c.IPAllowList, ","))
+			}
+			if len(c.IPDenyList) > 0 {
+				fmt.Println("Denied networks: " + strings.Join(c.IPDenyList, ","))
+			}
+			if len(c.Attributes) > 0 {
+				fmt.Printf("Attributes: %v\n", c.Attributes)
+			}
+			if c.Language != "" {
+				fmt.Printf("Results translated into: %s\n", c.Language)
+			}
+			if c.ToolGroup != "" {
+				fmt.Printf("Bound to tool group: %s\n", c.ToolGroup)
+			}
+			if c.SpiffeID != "" {
+				fmt.Printf("SPIFFE ID: %s\n", c.SpiffeID)
+			}
+			if c.Version > 0 {
+				fmt.Printf("Version: %d\n", c.Version)
+			}
+
+			if i < len(clients)-1 {
+				fmt.Println()
+			}
 		}
-		fmt.Printf("%d. %s  [%s]\n", i+1, t.Name, ed)
-		fmt.Println(t.Description)
-		fmt.Println()
+		return nil
+	})
+}
+
+func runListUsers(cmd *cobra.Command, args []string) error {
+	users, err := apiClient.ListUsers()
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
 	}
 
-	fmt.Println("Run 'usage <tool name>' to see a tool's usage or 'invoke <tool name>' to call one")
+	return renderOutput(cmd, users, func() error {
+		if len(users) == 0 {
+			cmd.Println("There are no users in the registry")
+			return nil
+		}
+		for i, u := range users {
+			if u.Role == string(types.UserRoleAdmin) {
+				cmd.Printf("%d. %s  [ADMIN]\n", i+1, u.Username)
+			} else {
+				cmd.Printf("%d. %s\n", i+1, u.Username)
+			}
 
-	return nil
+			if i < len(users)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
 }
 
-func runListServers(cmd *cobra.Command, args []string) error {
-	servers, err := apiClient.ListServers()
+func runListBudgets(cmd *cobra.Command, args []string) error {
+	budgets, err := apiClient.ListBudgets()
 	if err != nil {
-		return fmt.Errorf("failed to list servers: %w", err)
+		return fmt.Errorf("failed to list budgets: %w", err)
+	}
+
+	return renderOutput(cmd, budgets, func() error {
+		if len(budgets) == 0 {
+			cmd.Println("There are no budgets in the registry")
+			return nil
+		}
+		for i, b := range budgets {
+			if b.HardStop {
+				cmd.Printf("%d. %s  [HARD STOP]\n", i+1, b.Name)
+			} else {
+				cmd.Printf("%d. %s\n", i+1, b.Name)
+			}
+			if b.Client != "" {
+				cmd.Println("MCP client: " + b.Client)
+			} else {
+				cmd.Println("User: " + b.User)
+			}
+			cmd.Printf("Spend: %.4f of %.4f (%s)\n", b.Spend, b.MaxSpend, b.Period)
+			if b.AlertThreshold > 0 {
+				cmd.Printf("Alert at: %.0f%%\n", b.AlertThreshold)
+			}
+			if b.WebhookURL != "" {
+				cmd.Println("Webhook: " + b.WebhookURL)
+			}
+
+			if i < len(budgets)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
+}
+
+func runListSLOs(cmd *cobra.Command, args []string) error {
+	slos, err := apiClient.ListSLOs()
+	if err != nil {
+		return fmt.Errorf("failed to list SLOs: %w", err)
+	}
+
+	return renderOutput(cmd, slos, func() error {
+		if len(slos) == 0 {
+			cmd.Println("There are no SLOs in the registry")
+			return nil
+		}
+		for i, s := range slos {
+			if s.Status.Compliant {
+				cmd.Printf("%d. %s  [OK]\n", i+1, s.Name)
+			} else {
+				cmd.Printf("%d. %s  [VIOLATED]\n", i+1, s.Name)
+			}
+			if s.Tool != "" {
+				cmd.Println("Tool: " + s.Tool)
+			} else {
+				cmd.Println("MCP server: " + s.Server)
+			}
+			cmd.Printf("Calls: %d (last %s)\n", s.Status.Calls, s.Window)
+			if s.LatencyTargetMs > 0 {
+				cmd.Printf(
+					"Latency p%g: %dms (objective: %dms)\n",
+					s.LatencyPercentile, s.Status.LatencyMs, s.LatencyTargetMs,
+				)
+			}
+			if s.MaxErrorRate > 0 {
+				cmd.Printf("Error rate: %.2f%% (objective: %g%%)\n", s.Status.ErrorRate, s.MaxErrorRate)
+			}
+
+			if i < len(slos)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
+}
+
+func runListFaults(cmd *cobra.Command, args []string) error {
+	rules, err := apiClient.ListFaultRules()
+	if err != nil {
+		return fmt.Errorf("failed to list fault rules: %w", err)
 	}
 
-	if len(servers) == 0 {
-		fmt.Println("There are no MCP servers in the registry")
+	return renderOutput(cmd, rules, func() error {
+		if len(rules) == 0 {
+			cmd.Println("There are no fault rules in the registry")
+			return nil
+		}
+		for i, r := range rules {
+			cmd.Printf("%d. %s\n", i+1, r.Name)
+			if r.Tool != "" {
+				cmd.Println("Tool: " + r.Tool)
+			} else {
+				cmd.Println("MCP server: " + r.Server)
+			}
+			if r.Fault == "latency" {
+				cmd.Printf("Fault: latency of %dms in %g%% of calls\n", r.LatencyMs, r.Rate)
+			} else {
+				cmd.Printf("Fault: %s in %g%% of calls\n", r.Fault, r.Rate)
+			}
+
+			if i < len(rules)-1 {
+				cmd.Println()
+			}
+		}
 		return nil
+	})
+}
+
+func runListPrompts(cmd *cobra.Command, args []string) error {
+	prompts, err := apiClient.ListPrompts()
+	if err != nil {
+		return fmt.Errorf("failed to list prompts: %w", err)
 	}
-	for i, s := range servers {
-		fmt.Printf("%d. %s\n", i+1, s.Name)
 
-		if s.Description != "" {
-			fmt.Println(s.Description)
+	return renderOutput(cmd, prompts, func() error {
+		if len(prompts) == 0 {
+			cmd.Println("There are no prompts in the registry")
+			return nil
+		}
+		for i, p := range prompts {
+			cmd.Printf("%d. %s\n", i+1, p.Name)
+			if p.Description != "" {
+				cmd.Println(p.Description)
+			}
+			for _, a := range p.Arguments {
+				line := "Argument: " + a.Name
+				if a.Required {
+					line += " (required)"
+				}
+				if a.Description != "" {
+					line += " - " + a.Description
+				}
+				cmd.Println(line)
+			}
+			if len(p.Overrides) > 0 {
+				cmd.Println("Overridden for clients: " + strings.Join(slices.Sorted(maps.Keys(p.Overrides)), ", "))
+			}
+
+			if i < len(prompts)-1 {
+				cmd.Println()
+			}
 		}
+		return nil
+	})
+}
 
-		fmt.Println("Transport: " + s.Transport)
+func runListResidencyPolicies(cmd *cobra.Command, args []string) error {
+	policies, err := apiClient.ListResidencyPolicies()
+	if err != nil {
+		return fmt.Errorf("failed to list residency policies: %w", err)
+	}
 
-		t, _ := types.ValidateTransport(s.Transport)
-		if t == types.TransportStreamableHTTP {
-			fmt.Println("URL: " + s.URL)
-		} else {
-			if len(s.Args) > 0 {
-				fmt.Println("Command: " + s.Command + " " + strings.Join(s.Args, " "))
+	return renderOutput(cmd, policies, func() error {
+		if len(policies) == 0 {
+			cmd.Println("There are no residency policies in the registry")
+			return nil
+		}
+		for i, p := range policies {
+			cmd.Printf("%d. %s  [%s]\n", i+1, p.Name, strings.ToUpper(p.Action))
+			if p.Client != "" {
+				cmd.Println("MCP client: " + p.Client)
 			} else {
-				fmt.Println("Command: " + s.Command)
+				cmd.Println("User: " + p.User)
 			}
+			cmd.Println("Allowed regions: " + strings.Join(p.AllowedRegions, ","))
 
-			if len(s.Env) > 0 {
-				fmt.Printf("Environment variables: %s\n", s.Env)
+			if i < len(policies)-1 {
+				cmd.Println()
 			}
 		}
+		return nil
+	})
+}
 
-		if i < len(servers)-1 {
-			fmt.Println()
+func runListFavorites(cmd *cobra.Command, args []string) error {
+	tools, err := apiClient.ListFavoriteTools()
+	if err != nil {
+		return fmt.Errorf("failed to list favorite tools: %w", err)
+	}
+	return renderOutput(cmd, tools, func() error {
+		if len(tools) == 0 {
+			cmd.Println("You haven't starred any tools, star one with 'mcpjungle star <tool name>'")
+			return nil
+		}
+		for _, t := range tools {
+			cmd.Println(t)
+		}
+		return nil
+	})
+}
+
+func runListViews(cmd *cobra.Command, args []string) error {
+	views, err := apiClient.ListToolViews()
+	if err != nil {
+		return fmt.Errorf("failed to list views: %w", err)
+	}
+	return renderOutput(cmd, views, func() error {
+		if len(views) == 0 {
+			cmd.Println("You don't have any views, create one with 'mcpjungle create view'")
+			return nil
+		}
+		for _, v := range views {
+			cmd.Printf("%s: %s\n", v.Name, strings.Join(v.Tools, ", "))
 		}
+		return nil
+	})
+}
+
+func runListGrants(cmd *cobra.Command, args []string) error {
+	grants, err := apiClient.ListAccessGrants()
+	if err != nil {
+		return fmt.Errorf("failed to list access grants: %w", err)
 	}
 
-	return nil
+	return renderOutput(cmd, grants, func() error {
+		if len(grants) == 0 {
+			cmd.Println("There are no active access grants")
+			return nil
+		}
+		for i, g := range grants {
+			cmd.Printf("%d. [ID %d] %s -> tool group %s\n", i+1, g.ID, g.Client, g.ToolGroup)
+			cmd.Printf("Expires: %s (in %s)\n", g.ExpiresAt.Local().Format(time.DateTime), time.Until(g.ExpiresAt).Round(time.Minute))
+			if g.GrantedBy != "" {
+				cmd.Println("Granted by: " + g.GrantedBy)
+			}
+			if g.Reason != "" {
+				cmd.Println("Reason: " + g.Reason)
+			}
+
+			if i < len(grants)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
 }
 
-func runListMcpClients(cmd *cobra.Command, args []string) error {
-	clients, err := apiClient.ListMcpClients()
+func runListPolicies(cmd *cobra.Command, args []string) error {
+	policies, err := apiClient.ListPolicies()
 	if err != nil {
-		return fmt.Errorf("failed to list MCP clients: %w", err)
+		return fmt.Errorf("failed to list policies: %w", err)
 	}
 
-	if len(clients) == 0 {
-		fmt.Println("There are no MCP clients in the registry")
+	return renderOutput(cmd, policies, func() error {
+		if len(policies) == 0 {
+			cmd.Println("There are no policies in the registry")
+			return nil
+		}
+		for i, p := range policies {
+			cmd.Printf("%d. %s  [priority %d]\n", i+1, p.Name, p.Priority)
+			if p.Description != "" {
+				cmd.Println(p.Description)
+			}
+			cmd.Printf("%s: %s\n", strings.ToUpper(p.Language), strings.TrimSpace(p.Expression))
+
+			if i < len(policies)-1 {
+				cmd.Println()
+			}
+		}
 		return nil
+	})
+}
+
+func runListWebhookKeys(cmd *cobra.Command, args []string) error {
+	keys, err := apiClient.ListWebhookKeys()
+	if err != nil {
+		return fmt.Errorf("failed to list webhook keys: %w", err)
 	}
-	for i, c := range clients {
-		fmt.Printf("%d. %s\n", i+1, c.Name)
 
-		if c.Description != "" {
-			fmt.Println("Description: ", c.Description)
+	return renderOutput(cmd, keys, func() error {
+		if len(keys) == 0 {
+			cmd.Println("There are no webhook keys, outbound webhooks are not signed")
+			return nil
+		}
+		for i, k := range keys {
+			if k.ExpiresAt == nil {
+				cmd.Printf("%d. %s  (current, created %s)\n", i+1, k.KeyID, k.CreatedAt.Format(time.RFC3339))
+			} else {
+				cmd.Printf("%d. %s  (expires %s)\n", i+1, k.KeyID, k.ExpiresAt.Format(time.RFC3339))
+			}
 		}
+		return nil
+	})
+}
 
-		if len(c.AllowList) > 0 {
-			fmt.Println("Allowed servers: " + strings.Join(c.AllowList, ","))
-		} else {
-			fmt.Println("This client does not have access to any MCP servers.")
+func runListToolGroups(cmd *cobra.Command, args []string) error {
+	groups, err := apiClient.ListToolGroups()
+	if err != nil {
+		return fmt.Errorf("failed to list tool groups: %w", err)
+	}
+
+	return renderOutput(cmd, groups, func() error {
+		if len(groups) == 0 {
+			cmd.Println("There are no tool groups in the registry")
+			return nil
 		}
+		for i, g := range groups {
+			cmd.Printf("%d. %s\n", i+1, g.Name)
+			if g.Description != "" {
+				cmd.Println(g.Description)
+			}
+			cmd.Println("Tools: " + strings.Join(g.IncludedTools, ","))
+			if g.ExtractionProfile != "" {
+				cmd.Println("Extraction profile: " + g.ExtractionProfile)
+			}
+			cmd.Printf("MCP endpoint: %s/v0/groups/%s/mcp\n", strings.TrimSuffix(activeRegistryURL, "/"), g.Name)
 
-		if i < len(clients)-1 {
-			fmt.Println()
+			if i < len(groups)-1 {
+				cmd.Println()
+			}
 		}
+		return nil
+	})
+}
+
+func runListExtractionProfiles(cmd *cobra.Command, args []string) error {
+	profiles, err := apiClient.ListExtractionProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to list extraction profiles: %w", err)
 	}
 
-	return nil
+	return renderOutput(cmd, profiles, func() error {
+		if len(profiles) == 0 {
+			cmd.Println("There are no extraction profiles in the registry")
+			return nil
+		}
+		for i, p := range profiles {
+			cmd.Printf("%d. %s\n", i+1, p.Name)
+			if p.Description != "" {
+				cmd.Println(p.Description)
+			}
+			for _, tool := range slices.Sorted(maps.Keys(p.Rules)) {
+				cmd.Printf("  %s: %s\n", tool, p.Rules[tool])
+			}
+
+			if i < len(profiles)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
 }
 
-func runListUsers(cmd *cobra.Command, args []string) error {
-	users, err := apiClient.ListUsers()
+func runListInvocations(cmd *cobra.Command, args []string) error {
+	invocations, err := apiClient.ListInvocations(listInvocationsCmdTool, listInvocationsCmdLimit)
 	if err != nil {
-		return fmt.Errorf("failed to list users: %w", err)
+		return fmt.Errorf("failed to list invocations: %w", err)
 	}
 
-	if len(users) == 0 {
-		cmd.Println("There are no users in the registry")
+	return renderOutput(cmd, invocations, func() error {
+		if len(invocations) == 0 {
+			cmd.Println("There are no tool invocations to show")
+			return nil
+		}
+		for _, inv := range invocations {
+			status := "OK"
+			if inv.IsError {
+				status = "ERROR"
+			}
+			caller := inv.ClientName
+			if caller == "" {
+				caller = inv.Username
+			}
+			if caller == "" {
+				caller = "-"
+			}
+			if inv.ImpersonatedBy != "" {
+				caller += " (impersonated by " + inv.ImpersonatedBy + ")"
+			}
+			cmd.Printf("%s  %-5s  %s  caller=%s  cost=%g  attempts=%d\n",
+				inv.CreatedAt.Format(time.RFC3339), status, inv.ToolName, caller, inv.Cost, inv.Attempts)
+			if inv.Arguments != nil {
+				b, _ := json.Marshal(inv.Arguments)
+				cmd.Printf("  arguments: %s\n", b)
+			}
+			if inv.Result != nil {
+				b, _ := json.Marshal(inv.Result)
+				cmd.Printf("  result: %s\n", b)
+			}
+		}
 		return nil
+	})
+}
+
+func runListFeatures(cmd *cobra.Command, args []string) error {
+	features, err := apiClient.ListFeatures()
+	if err != nil {
+		return fmt.Errorf("failed to list features: %w", err)
 	}
-	for i, u := range users {
-		if u.Role == string(types.UserRoleAdmin) {
-			cmd.Printf("%d. %s  [ADMIN]\n", i+1, u.Username)
-		} else {
-			cmd.Printf("%d. %s\n", i+1, u.Username)
+
+	return renderOutput(cmd, features, func() error {
+		for _, f := range features {
+			state := "off"
+			if f.Enabled {
+				state = "on"
+			}
+			cmd.Printf("%-20s %-3s (%s)  %s\n", f.Name, state, f.Source, f.Description)
 		}
+		return nil
+	})
+}
+
+func runListNotifications(cmd *cobra.Command, args []string) error {
+	notifications, err := apiClient.ListNotifications(listNotificationsCmdUnread, listNotificationsCmdLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list notifications: %w", err)
+	}
 
-		if i < len(users)-1 {
+	err = renderOutput(cmd, notifications, func() error {
+		if len(notifications) == 0 {
+			cmd.Println("There are no notifications to show")
+			return nil
+		}
+		for _, n := range notifications {
+			state := "NEW "
+			if n.ReadAt != nil {
+				state = "read"
+			}
+			cmd.Printf("[%d] %s  %s  %s: %s", n.ID, n.UpdatedAt.Format(time.RFC3339), state, n.Kind, n.Message)
+			if n.Count > 1 {
+				cmd.Printf(" (%d times)", n.Count)
+			}
 			cmd.Println()
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	if listNotificationsCmdMarkRead && len(notifications) > 0 {
+		ids := make([]uint, 0, len(notifications))
+		for _, n := range notifications {
+			ids = append(ids, n.ID)
+		}
+		if err := apiClient.MarkNotificationsRead(ids...); err != nil {
+			return fmt.Errorf("failed to mark notifications as read: %w", err)
+		}
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+
 	"github.com/mcpjungle/mcpjungle/cmd/config"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
@@ -9,11 +10,15 @@ import (
 
 var loginCmd = &cobra.Command{
 	Use:   "login [access_token]",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	Short: "Log in to MCPJungle (Production mode)",
 	Long: "Log in to your MCPJungle account with your access token.\n" +
-		"This will store the access token in your local configuration file, allowing you to make authenticated requests to the MCPJungle API server.\n" +
-		"If you're a standard user, your access token must be generated by an administrator.",
+		"If the token is not supplied as an argument, you will be prompted for it.\n" +
+		"The token is stored in your OS keychain (or a credentials file only readable by you if no keychain is available),\n" +
+		"keyed by the client context and registry URL, allowing you to make authenticated requests to the MCPJungle API server.\n" +
+		"Use --encrypt to encrypt the token with a passphrase when it is stored in the credentials file.\n" +
+		"If you're a standard user, your access token must be generated by an administrator.\n" +
+		"MCPJungle users don't have passwords, so there is no username/password login: the access token is the credential.",
 	Annotations: map[string]string{
 		"group": string(subCommandGroupAdvanced),
 		"order": "6",
@@ -21,12 +26,51 @@ var loginCmd = &cobra.Command{
 	RunE: runLogin,
 }
 
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Args:  cobra.NoArgs,
+	Short: "Log out of MCPJungle (Production mode)",
+	Long:  "Wipe the access token stored for the current registry from your OS keychain and client configuration.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "6",
+	},
+	RunE: runLogout,
+}
+
+var loginCmdEncrypt bool
+
 func init() {
+	loginCmd.Flags().BoolVar(
+		&loginCmdEncrypt,
+		"encrypt",
+		false,
+		"Encrypt the access token with a passphrase if it is stored in the credentials file.\n"+
+			"You are prompted for the passphrase unless the "+config.CredentialsPassphraseEnvVar+" environment variable is set.",
+	)
 	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
-	accessToken := args[0]
+	var accessToken string
+	if len(args) == 1 {
+		accessToken = args[0]
+	} else {
+		var err error
+		accessToken, err = promptSecret("Access token: ")
+		if err != nil {
+			return err
+		}
+	}
+	if accessToken == "" {
+		return fmt.Errorf("access token must not be empty")
+	}
+	if loginCmdEncrypt && !config.HasPassphrase() {
+		if err := promptNewPassphrase(); err != nil {
+			return err
+		}
+	}
 
 	user, err := apiClient.Whoami(accessToken)
 	if err != nil {
@@ -41,18 +85,81 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		cmd.Println("You are an administrator of MCPJungle")
 	}
 
-	cfg := &config.ClientConfig{
-		AccessToken: accessToken,
+	storage, err := storeAccessToken(accessToken)
+	if err != nil {
+		return err
 	}
-	if err := config.Save(cfg); err != nil {
-		return fmt.Errorf("failed to create client configuration: %w", err)
+	if storage == config.CredentialStorageFile && config.HasPassphrase() {
+		storage += " (encrypted with your passphrase)"
+	}
+	fmt.Printf("Your access token for %s has been saved to your %s\n", activeRegistryURL, storage)
+
+	return nil
+}
+
+func runLogout(cmd *cobra.Command, args []string) error {
+	// also wipe the token stored for the registry before tokens were scoped to client contexts
+	for _, key := range []string{activeCredentialKey, activeRegistryURL} {
+		if err := config.DeleteCredential(key); err != nil {
+			return fmt.Errorf("failed to delete stored credentials: %w", err)
+		}
+	}
+	if err := clearConfigAccessToken(); err != nil {
+		return err
 	}
+	cmd.Printf("Logged out of %s\n", activeRegistryURL)
+	return nil
+}
 
-	cfgPath, err := config.AbsPath()
+// promptNewPassphrase prompts the user for the passphrase that encrypts their stored access tokens.
+func promptNewPassphrase() error {
+	p, err := promptSecret("Passphrase to encrypt your access token: ")
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		return fmt.Errorf("passphrase must not be empty")
+	}
+	confirm, err := promptSecret("Repeat the passphrase: ")
 	if err != nil {
-		return fmt.Errorf("failed to get client configuration path: %w", err)
+		return err
 	}
-	fmt.Println("Your access token has been saved to", cfgPath)
+	if confirm != p {
+		return fmt.Errorf("passphrases don't match")
+	}
+	config.SetPassphrase(p)
+	return nil
+}
 
+// storeAccessToken saves the token for the active registry (scoped to the active client context) in the credential store.
+// Any token previously pasted into the client configuration is removed so that it doesn't shadow the new one.
+func storeAccessToken(token string) (config.CredentialStorage, error) {
+	storage, err := config.SaveCredential(activeCredentialKey, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to store access token: %w", err)
+	}
+	if err := clearConfigAccessToken(); err != nil {
+		return "", err
+	}
+	return storage, nil
+}
+
+// clearConfigAccessToken removes the access token from the client configuration (in the active context, if any).
+func clearConfigAccessToken() error {
+	cfg := config.Load()
+	ctx, err := cfg.GetContext(cmdContextName)
+	if err != nil {
+		return err
+	}
+	if (ctx == nil && cfg.AccessToken == "") || (ctx != nil && ctx.AccessToken == "") {
+		// nothing to clear, avoid needlessly re-writing the config file
+		return nil
+	}
+	if err := cfg.SetAccessToken(cmdContextName, ""); err != nil {
+		return err
+	}
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to update client configuration: %w", err)
+	}
 	return nil
 }
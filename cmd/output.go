@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat is the format in which commands render the resources they fetch.
+type outputFormat string
+
+const (
+	// outputFormatTable is the default, human-friendly format
+	outputFormatTable outputFormat = "table"
+	outputFormatJSON  outputFormat = "json"
+	outputFormatYAML  outputFormat = "yaml"
+)
+
+// cmdOutputFormat holds the value of the global --output flag
+var cmdOutputFormat string
+
+// validateOutputFormat returns an error if the supplied output format is not supported.
+func validateOutputFormat(f string) error {
+	switch outputFormat(f) {
+	case outputFormatTable, outputFormatJSON, outputFormatYAML:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unsupported output format '%s' (acceptable values: '%s', '%s', '%s')",
+			f, outputFormatTable, outputFormatJSON, outputFormatYAML,
+		)
+	}
+}
+
+// isMachineReadableOutput returns true if the user asked for json or yaml output.
+// Commands can use this to suppress hints and other decorative messages.
+func isMachineReadableOutput() bool {
+	return outputFormat(cmdOutputFormat) != outputFormatTable
+}
+
+// renderOutput is the shared rendering layer for all commands that display resources.
+// If the user asked for json or yaml, v is serialized in that format and written to stdout.
+// Otherwise, printTable is called to display v in the default human-friendly format.
+func renderOutput(cmd *cobra.Command, v any, printTable func() error) error {
+	switch outputFormat(cmdOutputFormat) {
+	case outputFormatJSON:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to render output as json: %w", err)
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(b))
+		return err
+	case outputFormatYAML:
+		// Round-trip through JSON so that the yaml keys are the same as the json field names
+		// declared in the API types, rather than yaml's default lower-cased Go field names.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to render output as yaml: %w", err)
+		}
+		var generic any
+		if err := json.Unmarshal(b, &generic); err != nil {
+			return fmt.Errorf("failed to render output as yaml: %w", err)
+		}
+		encoder := yaml.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(generic)
+	default:
+		return printTable()
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRenderOutput(t *testing.T) {
+	originalFormat := cmdOutputFormat
+	defer func() { cmdOutputFormat = originalFormat }()
+
+	type item struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+	}
+	data := []item{{Name: "calc__add", Enabled: true}}
+
+	testCases := []struct {
+		name        string
+		format      outputFormat
+		expect      string
+		expectTable bool
+	}{
+		{
+			name:   "json",
+			format: outputFormatJSON,
+			expect: "[\n  {\n    \"name\": \"calc__add\",\n    \"enabled\": true\n  }\n]\n",
+		},
+		{
+			name:   "yaml uses json field names",
+			format: outputFormatYAML,
+			expect: "- enabled: true\n  name: calc__add\n",
+		},
+		{
+			name:        "table delegates to the printer",
+			format:      outputFormatTable,
+			expect:      "",
+			expectTable: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmdOutputFormat = string(tc.format)
+
+			buf := &bytes.Buffer{}
+			c := &cobra.Command{}
+			c.SetOut(buf)
+
+			tableCalled := false
+			err := renderOutput(c, data, func() error {
+				tableCalled = true
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("renderOutput() returned error: %v", err)
+			}
+			if tableCalled != tc.expectTable {
+				t.Fatalf("table printer called = %v, want %v", tableCalled, tc.expectTable)
+			}
+			if buf.String() != tc.expect {
+				t.Fatalf("renderOutput() wrote %q, want %q", buf.String(), tc.expect)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, f := range []string{"table", "json", "yaml"} {
+		if err := validateOutputFormat(f); err != nil {
+			t.Errorf("validateOutputFormat(%q) returned unexpected error: %v", f, err)
+		}
+	}
+	for _, f := range []string{"", "xml", "JSON"} {
+		if err := validateOutputFormat(f); err == nil {
+			t.Errorf("validateOutputFormat(%q) expected an error, got nil", f)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var pinServerCmdMode string
+
+var pinServerCmd = &cobra.Command{
+	Use:   "pin-server [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Pin the identity currently reported by an MCP server",
+	Long: "mcpjungle records the implementation name & version that an MCP server reports when it is registered.\n" +
+		"With identity pinning, a server that later reports another identity (eg- because it was swapped or\n" +
+		"tampered with) triggers a notification (alert mode) or is refused until it is re-pinned (block mode).\n" +
+		"This command connects to the server and pins the identity it reports now, eg- after an expected upgrade.\n" +
+		"\neg- mcpjungle pin-server github --mode block",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "29",
+	},
+	RunE: runPinServer,
+}
+
+func init() {
+	pinServerCmd.Flags().StringVar(
+		&pinServerCmdMode, "mode", "", "Change the pinning mode of the server: off, alert or block (unchanged by default)",
+	)
+	rootCmd.AddCommand(pinServerCmd)
+}
+
+func runPinServer(cmd *cobra.Command, args []string) error {
+	s, err := apiClient.PinServer(args[0], pinServerCmdMode)
+	if err != nil {
+		return fmt.Errorf("failed to pin MCP server %s: %w", args[0], err)
+	}
+	return renderOutput(cmd, s, func() error {
+		if s.ServerInfo == nil {
+			cmd.Printf("MCP server %s did not report its identity, there is nothing to pin\n", s.Name)
+			return nil
+		}
+		cmd.Printf("Pinned MCP server %s to %s %s (mode: %s)\n", s.Name, s.ServerInfo.Name, s.ServerInfo.Version, s.IdentityPinning)
+		return nil
+	})
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdinReader is shared by all prompts so that buffered input is not lost between them
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// promptLine prints the prompt and returns the (trimmed) line entered by the user.
+func promptLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptSecret prints the prompt and reads a secret value without echoing it to the terminal.
+// If stdin is not a terminal (eg- input is piped), the value is read as a regular line.
+func promptSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return promptLine(prompt)
+	}
+	fmt.Print(prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	proposalCreateCmdConfigFilePath string
+	proposalListCmdStatus           string
+	proposalReviewCmdComment        string
+)
+
+var proposalCmd = &cobra.Command{
+	Use:   "proposal",
+	Short: "Propose MCP server registrations and review them",
+	Long: "When the server_proposals feature is turned on, users who aren't admins can propose the registration\n" +
+		"of an MCP server. An admin reviews the proposal, test-connects to the server to see the tools and capabilities\n" +
+		"it provides, then approves the proposal (which registers the server) or rejects it.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "25",
+	},
+}
+
+var proposalCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Propose the registration of an MCP server",
+	Long: "Propose the registration of an MCP server described by a JSON configuration file,\n" +
+		"in the same format as for 'mcpjungle register'.\n" +
+		"\neg- mcpjungle proposal create -c ./github.json",
+	RunE: runProposalCreate,
+}
+
+var proposalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List proposed server registrations",
+	Long:  "List proposed server registrations, newest first. Admins see all proposals, other users only their own.",
+	RunE:  runProposalList,
+}
+
+var proposalPreflightCmd = &cobra.Command{
+	Use:   "preflight <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Test-connect to the MCP server of a proposal and show its tools (admin only)",
+	Long: "Test-connect to the MCP server of a pending proposal and record the tools, capabilities and protocol version\n" +
+		"it provides in the proposal. This starts the command of a stdio server, so review it first.",
+	RunE: runProposalPreflight,
+}
+
+var proposalApproveCmd = &cobra.Command{
+	Use:   "approve <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Approve a proposal and register its MCP server (admin only)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProposalReview(cmd, args, true)
+	},
+}
+
+var proposalRejectCmd = &cobra.Command{
+	Use:   "reject <id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Reject a proposal (admin only)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProposalReview(cmd, args, false)
+	},
+}
+
+func init() {
+	proposalCreateCmd.Flags().StringVarP(
+		&proposalCreateCmdConfigFilePath, "conf", "c", "", "Path to the JSON configuration file of the MCP server",
+	)
+	_ = proposalCreateCmd.MarkFlagRequired("conf")
+
+	proposalListCmd.Flags().StringVar(
+		&proposalListCmdStatus, "status", "", "Only list the proposals in this status (pending, approved or rejected)",
+	)
+
+	for _, c := range []*cobra.Command{proposalApproveCmd, proposalRejectCmd} {
+		c.Flags().StringVar(&proposalReviewCmdComment, "comment", "", "Comment explaining the decision to the proposer")
+	}
+
+	proposalCmd.AddCommand(proposalCreateCmd)
+	proposalCmd.AddCommand(proposalListCmd)
+	proposalCmd.AddCommand(proposalPreflightCmd)
+	proposalCmd.AddCommand(proposalApproveCmd)
+	proposalCmd.AddCommand(proposalRejectCmd)
+	rootCmd.AddCommand(proposalCmd)
+}
+
+func runProposalCreate(cmd *cobra.Command, args []string) error {
+	input, err := readMcpServerConfig(proposalCreateCmdConfigFilePath)
+	if err != nil {
+		return err
+	}
+	p, err := apiClient.ProposeServer(&input)
+	if err != nil {
+		return fmt.Errorf("failed to propose server: %w", err)
+	}
+	cmd.Printf("Registration of MCP server %s proposed (id %d), it awaits the approval of an admin\n", p.Name, p.ID)
+	return nil
+}
+
+func runProposalPreflight(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid proposal id: %s", args[0])
+	}
+	p, err := apiClient.PreflightServerProposal(uint(id))
+	if err != nil {
+		return fmt.Errorf("failed to preflight proposal: %w", err)
+	}
+	return renderOutput(cmd, p, func() error {
+		printProposalPreflight(cmd, p)
+		return nil
+	})
+}
+
+// printProposalPreflight prints what was discovered while test-connecting to the server of a proposal.
+func printProposalPreflight(cmd *cobra.Command, p *types.ServerProposal) {
+	if !p.Preflighted {
+		cmd.Println("Not test-connected yet, see 'mcpjungle proposal preflight'")
+		return
+	}
+	if p.Preflight.ServerInfo != nil {
+		cmd.Printf("Server: %s %s\n", p.Preflight.ServerInfo.Name, p.Preflight.ServerInfo.Version)
+	}
+	cmd.Printf("Protocol version: %s\n", p.Preflight.ProtocolVersion)
+	cmd.Printf("Tools (%d):\n", len(p.Preflight.Tools))
+	for _, t := range p.Preflight.Tools {
+		cmd.Printf("- %s\n", t.Name)
+	}
+}
+
+func runProposalList(cmd *cobra.Command, args []string) error {
+	proposals, err := apiClient.ListServerProposals(types.ServerProposalStatus(proposalListCmdStatus))
+	if err != nil {
+		return fmt.Errorf("failed to list proposals: %w", err)
+	}
+
+	return renderOutput(cmd, proposals, func() error {
+		if len(proposals) == 0 {
+			cmd.Println("There are no proposals to show")
+			return nil
+		}
+		for i, p := range proposals {
+			cmd.Printf("[%d] %s (%s) %s\n", p.ID, p.Name, p.Transport, p.Status)
+			cmd.Printf("Target: %s\n", p.Target)
+			if p.ProposedBy != "" {
+				cmd.Printf("Proposed by: %s\n", p.ProposedBy)
+			}
+			printProposalPreflight(cmd, p)
+			if p.ReviewedBy != "" || p.ReviewComment != "" {
+				cmd.Printf("Reviewed by %s: %s\n", p.ReviewedBy, p.ReviewComment)
+			}
+			if i < len(proposals)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
+}
+
+func runProposalReview(cmd *cobra.Command, args []string, approve bool) error {
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid proposal id: %s", args[0])
+	}
+	if approve {
+		p, err := apiClient.ApproveServerProposal(uint(id), proposalReviewCmdComment)
+		if err != nil {
+			return fmt.Errorf("failed to approve proposal: %w", err)
+		}
+		cmd.Printf("Proposal %d approved, MCP server %s registered successfully!\n", p.ID, p.Name)
+		return nil
+	}
+	p, err := apiClient.RejectServerProposal(uint(id), proposalReviewCmdComment)
+	if err != nil {
+		return fmt.Errorf("failed to reject proposal: %w", err)
+	}
+	cmd.Printf("Proposal %d for MCP server %s rejected\n", p.ID, p.Name)
+	return nil
+}
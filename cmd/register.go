@@ -13,8 +13,14 @@ var (
 	registerCmdServerURL   string
 	registerCmdServerDesc  string
 	registerCmdBearerToken string
+	registerCmdRegion      string
+	registerCmdReplicaOf   string
+	registerCmdGroup       string
+	registerCmdSpiffeID    string
 
 	registerCmdServerConfigFilePath string
+
+	registerCmdInteractive bool
 )
 
 var registerMCPServerCmd = &cobra.Command{
@@ -24,10 +30,11 @@ var registerMCPServerCmd = &cobra.Command{
 		"The recommended way is to specify the json configuration file for your server.\n" +
 		"A config file is required if you want to register an stdio-based mcp server.\n" +
 		"The flags only allow you to register a streamable http server.\n" +
+		"Use --interactive to be guided through the registration step by step.\n" +
 		"\nNOTE: A server's name is unique across mcpjungle and must not contain\nany whitespaces, special characters or multiple consecutive underscores '__'.",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip flag validation if config file is provided
-		if registerCmdServerConfigFilePath != "" {
+		// Skip flag validation if config file is provided or the server details will be prompted for
+		if registerCmdServerConfigFilePath != "" || registerCmdInteractive {
 			return nil
 		}
 		// Otherwise, validate required flags
@@ -72,6 +79,32 @@ func init() {
 		"If provided, MCPJungle will use this token to authenticate with the http MCP server for all requests."+
 			" This is useful if the MCP server requires static tokens (eg- your API token) for authentication.",
 	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdRegion,
+		"region",
+		"",
+		"Region tag of the server (eg- eu-west), used to enforce data residency policies",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdReplicaOf,
+		"replica-of",
+		"",
+		"Register the server as a replica of an already registered server, deployed in another region (requires --region).\n"+
+			"Calls to the tools of that server are routed to the replica closest to the caller.",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdGroup,
+		"group",
+		"",
+		"Server group (eg- analytics), whose servers can be stopped & started together with 'mcpjungle servers'",
+	)
+	registerMCPServerCmd.Flags().StringVar(
+		&registerCmdSpiffeID,
+		"spiffe-id",
+		"",
+		"SPIFFE ID of the MCP server's workload (eg- spiffe://example.org/ns/prod/sa/github-mcp).\n"+
+			"If provided, MCPJungle connects to the server with mTLS using its own SPIFFE SVID.",
+	)
 	registerMCPServerCmd.Flags().StringVarP(
 		&registerCmdServerConfigFilePath,
 		"conf",
@@ -81,6 +114,14 @@ func init() {
 			"If provided, the mcp server will be registered using the configuration in the file.\n"+
 			"All other flags will be ignored.",
 	)
+	registerMCPServerCmd.Flags().BoolVarP(
+		&registerCmdInteractive,
+		"interactive",
+		"i",
+		false,
+		"Interactively prompt for the server's details, test the connection and choose which tools to enable.\n"+
+			"All other flags are ignored.",
+	)
 
 	rootCmd.AddCommand(registerMCPServerCmd)
 }
@@ -90,7 +131,7 @@ func readMcpServerConfig(filePath string) (types.RegisterServerInput, error) {
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return input, fmt.Errorf("failed to read config file %s: %w", registerCmdServerConfigFilePath, err)
+		return input, fmt.Errorf("failed to read config file %s: %w", filePath, err)
 	}
 	// Parse JSON config
 	if err := json.Unmarshal(data, &input); err != nil {
@@ -101,6 +142,10 @@ func readMcpServerConfig(filePath string) (types.RegisterServerInput, error) {
 }
 
 func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
+	if registerCmdInteractive {
+		return runRegisterWizard()
+	}
+
 	var input types.RegisterServerInput
 
 	if registerCmdServerConfigFilePath == "" {
@@ -111,6 +156,10 @@ func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
 			URL:         registerCmdServerURL,
 			Description: registerCmdServerDesc,
 			BearerToken: registerCmdBearerToken,
+			Region:      registerCmdRegion,
+			ReplicaOf:   registerCmdReplicaOf,
+			Group:       registerCmdGroup,
+			SpiffeID:    registerCmdSpiffeID,
 		}
 	} else {
 		// If a config file is provided, read the configuration from the file
@@ -126,6 +175,10 @@ func runRegisterMCPServer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to register server: %w", err)
 	}
 	fmt.Printf("Server %s registered successfully!\n", s.Name)
+	if s.ReplicaOf != "" {
+		fmt.Printf("Calls to the tools of %s from region '%s' will be served by this replica.\n", s.ReplicaOf, s.Region)
+		return nil
+	}
 
 	tools, err := apiClient.ListTools(s.Name)
 	if err != nil {
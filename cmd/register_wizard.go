@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// runRegisterWizard guides the user through registering an MCP server interactively.
+// It test-connects to the server before registering it so that the user can choose which tools to enable.
+func runRegisterWizard() error {
+	fmt.Println("This wizard will guide you through registering an MCP server with MCPJungle.")
+	fmt.Println()
+
+	input, err := promptServerInput()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Connecting to server %s...\n", input.Name)
+	preflight, err := apiClient.PreflightServer(input)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the MCP server: %w", err)
+	}
+
+	enabled := make(map[string]bool, len(preflight.Tools))
+	if len(preflight.Tools) == 0 {
+		fmt.Println("The server does not provide any tools.")
+	} else {
+		fmt.Println("The server provides the following tools:")
+		for i, t := range preflight.Tools {
+			fmt.Printf("  %d. %s: %s\n", i+1, t.Name, t.Description)
+		}
+		fmt.Println()
+		selection, err := promptLine("Which tools do you want to enable? (all, none or comma-separated numbers) [all]: ")
+		if err != nil {
+			return err
+		}
+		enabled, err = parseToolSelection(selection, preflight.Tools)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	confirm, err := promptLine(fmt.Sprintf("Register server %s with %d of %d tools enabled? [Y/n]: ",
+		input.Name, len(enabled), len(preflight.Tools)))
+	if err != nil {
+		return err
+	}
+	if confirm != "" && !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		fmt.Println("Aborted, the server was not registered.")
+		return nil
+	}
+
+	s, err := apiClient.RegisterServer(input)
+	if err != nil {
+		return fmt.Errorf("failed to register server: %w", err)
+	}
+	fmt.Printf("Server %s registered successfully!\n", s.Name)
+
+	// all tools are enabled upon registration, so disable the ones the user did not select
+	for _, t := range preflight.Tools {
+		if enabled[t.Name] {
+			continue
+		}
+		if _, err := apiClient.DisableTools(t.Name, 0); err != nil {
+			return fmt.Errorf("failed to disable tool %s: %w", t.Name, err)
+		}
+		fmt.Printf("Disabled tool %s\n", t.Name)
+	}
+	return nil
+}
+
+// promptServerInput asks the user for all the details required to register a server.
+func promptServerInput() (*types.RegisterServerInput, error) {
+	input := &types.RegisterServerInput{}
+
+	var err error
+	if input.Name, err = promptRequired("Server name: "); err != nil {
+		return nil, err
+	}
+	if input.Description, err = promptLine("Description (optional): "); err != nil {
+		return nil, err
+	}
+	if input.Region, err = promptLine("Region, used for data residency policies (optional): "); err != nil {
+		return nil, err
+	}
+
+	transport, err := promptLine("Transport (streamable_http or stdio) [streamable_http]: ")
+	if err != nil {
+		return nil, err
+	}
+	if transport == "" {
+		transport = string(types.TransportStreamableHTTP)
+	}
+	t, err := types.ValidateTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+	input.Transport = string(t)
+
+	if t == types.TransportStreamableHTTP {
+		if input.URL, err = promptRequired("Server URL (eg- http://localhost:8000/mcp): "); err != nil {
+			return nil, err
+		}
+		if input.BearerToken, err = promptSecret("Bearer token (optional, input is hidden): "); err != nil {
+			return nil, err
+		}
+		return input, nil
+	}
+
+	if input.Command, err = promptRequired("Command (eg- npx): "); err != nil {
+		return nil, err
+	}
+	args, err := promptLine("Arguments (space-separated, optional): ")
+	if err != nil {
+		return nil, err
+	}
+	input.Args = strings.Fields(args)
+
+	fmt.Println("Enter environment variables for the server, one per prompt. Leave the name empty when done.")
+	for {
+		name, err := promptLine("  Variable name: ")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			break
+		}
+		value, err := promptSecret("  Value (input is hidden): ")
+		if err != nil {
+			return nil, err
+		}
+		if input.Env == nil {
+			input.Env = make(map[string]string)
+		}
+		input.Env[name] = value
+	}
+	return input, nil
+}
+
+// promptRequired keeps prompting the user until a non-empty value is entered.
+func promptRequired(prompt string) (string, error) {
+	for {
+		v, err := promptLine(prompt)
+		if err != nil {
+			return "", err
+		}
+		if v != "" {
+			return v, nil
+		}
+		fmt.Println("This value is required.")
+	}
+}
+
+// parseToolSelection parses the user's tool selection and returns the set of tool names to enable.
+// The selection is either "all" (the default), "none" or a comma-separated list of 1-based tool numbers.
+func parseToolSelection(selection string, tools []*types.Tool) (map[string]bool, error) {
+	enabled := make(map[string]bool, len(tools))
+	switch strings.ToLower(strings.TrimSpace(selection)) {
+	case "", "all":
+		for _, t := range tools {
+			enabled[t.Name] = true
+		}
+		return enabled, nil
+	case "none":
+		return enabled, nil
+	}
+	for _, s := range strings.Split(selection, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 1 || n > len(tools) {
+			return nil, fmt.Errorf("invalid tool selection '%s': expected a number between 1 and %d", s, len(tools))
+		}
+		enabled[tools[n-1].Name] = true
+	}
+	return enabled, nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayRecordCmdServer     string
+	replayRecordCmdSavedCalls []string
+
+	replayRunCmdServer       string
+	replayRunCmdFailOnChange bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Regression test MCP servers by replaying recorded tool calls",
+	Long: "A replay suite records the results of a server's saved calls. Replaying it later, eg- after the server\n" +
+		"is upgraded, reports which results changed, how the latency of every call changed and which calls started failing.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "22",
+	},
+}
+
+var replayRecordCmd = &cobra.Command{
+	Use:   "record <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Record a replay suite",
+	Long: "Call the saved calls of a server's tools and record their results in a replay suite.\n" +
+		"All saved calls of the server are recorded, unless specific ones are given with --saved-call.\n" +
+		"\neg- mcpjungle replay record search-baseline --server search",
+	RunE: runReplayRecord,
+}
+
+var replayListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List replay suites",
+	RunE:  runReplayList,
+}
+
+var replayRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Replay a replay suite and show what changed",
+	Long: "Replay the calls of a replay suite and compare their results, latency and errors with the recorded ones.\n" +
+		"Use --server to replay the calls against another server, eg- a new version registered side by side with the current one.\n" +
+		"\neg- mcpjungle replay run search-baseline --server search-v2 --fail-on-change",
+	RunE: runReplayRun,
+}
+
+var replayDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a replay suite",
+	RunE:  runReplayDelete,
+}
+
+func init() {
+	replayRecordCmd.Flags().StringVar(&replayRecordCmdServer, "server", "", "Name of the MCP server to record")
+	replayRecordCmd.Flags().StringArrayVar(
+		&replayRecordCmdSavedCalls, "saved-call", nil, "Name of a saved call to record, can be repeated",
+	)
+	_ = replayRecordCmd.MarkFlagRequired("server")
+
+	replayRunCmd.Flags().StringVar(
+		&replayRunCmdServer, "server", "", "MCP server to replay the calls against (defaults to the recorded server)",
+	)
+	replayRunCmd.Flags().BoolVar(
+		&replayRunCmdFailOnChange, "fail-on-change", false, "Exit with an error if any result changed or any call started failing",
+	)
+
+	replayCmd.AddCommand(replayRecordCmd)
+	replayCmd.AddCommand(replayListCmd)
+	replayCmd.AddCommand(replayRunCmd)
+	replayCmd.AddCommand(replayDeleteCmd)
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplayRecord(cmd *cobra.Command, args []string) error {
+	suite, err := apiClient.CreateReplaySuite(&types.CreateReplaySuiteInput{
+		Name:       args[0],
+		Server:     replayRecordCmdServer,
+		SavedCalls: replayRecordCmdSavedCalls,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record replay suite: %w", err)
+	}
+	failed := 0
+	for _, c := range suite.Calls {
+		if c.Failed() {
+			failed++
+		}
+	}
+	cmd.Printf("Recorded %d calls (%d failed) in replay suite %s, replay them with 'mcpjungle replay run %s'\n",
+		len(suite.Calls), failed, suite.Name, suite.Name)
+	return nil
+}
+
+func runReplayList(cmd *cobra.Command, args []string) error {
+	suites, err := apiClient.ListReplaySuites()
+	if err != nil {
+		return fmt.Errorf("failed to list replay suites: %w", err)
+	}
+
+	return renderOutput(cmd, suites, func() error {
+		if len(suites) == 0 {
+			cmd.Println("There are no replay suites in the registry")
+			return nil
+		}
+		for i, s := range suites {
+			cmd.Printf("%d. %s -> %s (%d calls)\n", i+1, s.Name, s.Server, len(s.Calls))
+		}
+		return nil
+	})
+}
+
+func runReplayRun(cmd *cobra.Command, args []string) error {
+	report, err := apiClient.RunReplaySuite(args[0], replayRunCmdServer)
+	if err != nil {
+		return fmt.Errorf("failed to run replay suite: %w", err)
+	}
+
+	err = renderOutput(cmd, report, func() error {
+		for _, c := range report.Calls {
+			b, _ := json.Marshal(c.Args)
+			cmd.Printf("[%s] %s %s (%dms -> %dms, %+dms)\n",
+				c.Status, c.Tool, b, c.RecordedMs, c.DurationMs, c.LatencyDeltaMs)
+			for _, change := range c.Changes {
+				before, _ := json.Marshal(change.Before)
+				after, _ := json.Marshal(change.After)
+				cmd.Printf("  %s: %s -> %s\n", change.Path, before, after)
+			}
+			if c.Status == types.ReplayNewError || c.Status == types.ReplayFailing {
+				cmd.Printf("  error: %s\n", c.Error)
+			}
+		}
+		cmd.Println()
+		cmd.Printf("Replayed %d calls against %s: %d unchanged, %d changed, %d new errors, %d fixed, %d still failing\n",
+			len(report.Calls), report.Server,
+			report.Summary[types.ReplayUnchanged], report.Summary[types.ReplayChanged],
+			report.Summary[types.ReplayNewError], report.Summary[types.ReplayFixed], report.Summary[types.ReplayFailing])
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if replayRunCmdFailOnChange && report.HasRegressions() {
+		return fmt.Errorf("replay suite %s found regressions", args[0])
+	}
+	return nil
+}
+
+func runReplayDelete(cmd *cobra.Command, args []string) error {
+	if err := apiClient.DeleteReplaySuite(args[0]); err != nil {
+		return fmt.Errorf("failed to delete replay suite: %w", err)
+	}
+	cmd.Printf("Replay suite '%s' deleted successfully (if it existed)\n", args[0])
+	return nil
+}
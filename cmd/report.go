@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report on the usage of tools",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "13",
+	},
+}
+
+var (
+	reportTopToolsCmdSince string
+	reportTopToolsCmdLimit int
+)
+
+var reportTopToolsCmd = &cobra.Command{
+	Use:   "top-tools",
+	Short: "List the most called tools",
+	Long: "List the tools that agents call the most, most called first.\n" +
+		"\neg- mcpjungle report top-tools --since 7d --limit 10",
+	RunE: runReportTopTools,
+}
+
+var reportUnusedToolsCmdSince string
+
+var reportUnusedToolsCmd = &cobra.Command{
+	Use:   "unused-tools",
+	Short: "List the tools that haven't been called recently",
+	Long: "List the tools that haven't been called within a period, so that you can prune tools that aren't needed.\n" +
+		"Tools registered within the period are not reported.\n" +
+		"\neg- mcpjungle report unused-tools --since 30d",
+	RunE: runReportUnusedTools,
+}
+
+var reportSummaryCmdSince string
+
+var reportSummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize calls by MCP server, tool group and MCP client",
+	Long: "Show the number of calls, error rate and p95 latency of the calls made within a period,\n" +
+		"for the whole registry and for every MCP server, tool group and MCP client.\n" +
+		"Calls made to the tools of a tool group are counted for the group regardless of the endpoint they were made on.\n" +
+		"\neg- mcpjungle report summary --since 7d",
+	RunE: runReportSummary,
+}
+
+var reportClientsCmdSince string
+
+var reportClientsCmd = &cobra.Command{
+	Use:   "clients",
+	Short: "Break down MCP proxy sessions by client application, protocol version and capability",
+	Long: "Show which client applications (as identified by the clientInfo they send when initializing a session)\n" +
+		"connect to the MCP proxy, the MCP protocol versions they speak and the capabilities they advertise,\n" +
+		"so that you know which agent stacks you must keep supporting.\n" +
+		"\neg- mcpjungle report clients --since 30d",
+	RunE: runReportClients,
+}
+
+func init() {
+	reportTopToolsCmd.Flags().StringVar(
+		&reportTopToolsCmdSince, "since", "7d", "Period to report on (eg- 30d, 12h)",
+	)
+	reportTopToolsCmd.Flags().IntVar(&reportTopToolsCmdLimit, "limit", 10, "Maximum number of tools to list")
+	reportUnusedToolsCmd.Flags().StringVar(
+		&reportUnusedToolsCmdSince, "since", "30d", "Report tools that haven't been called within this period (eg- 30d)",
+	)
+
+	reportSummaryCmd.Flags().StringVar(
+		&reportSummaryCmdSince, "since", "1d", "Period to report on (eg- 30d, 12h)",
+	)
+
+	reportCmd.AddCommand(reportTopToolsCmd)
+	reportCmd.AddCommand(reportUnusedToolsCmd)
+	reportClientsCmd.Flags().StringVar(
+		&reportClientsCmdSince, "since", "30d", "Period to report on, in days (eg- 30d)",
+	)
+
+	reportCmd.AddCommand(reportSummaryCmd)
+	reportCmd.AddCommand(reportClientsCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReportTopTools(cmd *cobra.Command, args []string) error {
+	if _, err := types.ParseLookback(reportTopToolsCmdSince); err != nil {
+		return err
+	}
+	top, err := apiClient.TopTools(reportTopToolsCmdSince, reportTopToolsCmdLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get tool usage: %w", err)
+	}
+
+	return renderOutput(cmd, top, func() error {
+		if len(top) == 0 {
+			cmd.Printf("No tools were called in the last %s\n", reportTopToolsCmdSince)
+			return nil
+		}
+		for i, t := range top {
+			cmd.Printf("%d. %s  calls=%d  errors=%d  cost=%g  last called %s\n",
+				i+1, t.Tool, t.Calls, t.Errors, t.Cost, t.LastCalledAt.Format(time.RFC3339))
+		}
+		return nil
+	})
+}
+
+func runReportUnusedTools(cmd *cobra.Command, args []string) error {
+	if _, err := types.ParseLookback(reportUnusedToolsCmdSince); err != nil {
+		return err
+	}
+	unused, err := apiClient.UnusedTools(reportUnusedToolsCmdSince)
+	if err != nil {
+		return fmt.Errorf("failed to get unused tools: %w", err)
+	}
+
+	return renderOutput(cmd, unused, func() error {
+		if len(unused) == 0 {
+			cmd.Printf("All tools were called in the last %s\n", reportUnusedToolsCmdSince)
+			return nil
+		}
+		for _, t := range unused {
+			lastCalled := "never called"
+			if t.LastCalledAt != nil {
+				lastCalled = "last called " + t.LastCalledAt.Format(time.RFC3339)
+			}
+			state := ""
+			if !t.Enabled {
+				state = " (disabled)"
+			}
+			cmd.Printf("%s%s  %s\n", t.Name, state, lastCalled)
+		}
+		cmd.Printf("\n%d tools haven't been called in the last %s\n", len(unused), reportUnusedToolsCmdSince)
+		return nil
+	})
+}
+
+func runReportSummary(cmd *cobra.Command, args []string) error {
+	if _, err := types.ParseLookback(reportSummaryCmdSince); err != nil {
+		return err
+	}
+	summary, err := apiClient.MetricsSummary(reportSummaryCmdSince)
+	if err != nil {
+		return fmt.Errorf("failed to get the metrics summary: %w", err)
+	}
+
+	return renderOutput(cmd, summary, func() error {
+		printRollup := func(r *types.UsageRollup) {
+			cmd.Printf("  %s  calls=%d  errors=%d (%.1f%%)  p95=%dms\n",
+				r.Name, r.Calls, r.Errors, r.ErrorRate, r.P95LatencyMs)
+		}
+		cmd.Printf("Calls in the last %s:\n", reportSummaryCmdSince)
+		printRollup(summary.Total)
+		sections := []struct {
+			title   string
+			rollups []*types.UsageRollup
+		}{
+			{"MCP servers", summary.Servers},
+			{"Tool groups", summary.ToolGroups},
+			{"MCP clients", summary.Clients},
+		}
+		for _, s := range sections {
+			if len(s.rollups) == 0 {
+				continue
+			}
+			cmd.Printf("\n%s:\n", s.title)
+			for _, r := range s.rollups {
+				printRollup(r)
+			}
+		}
+		return nil
+	})
+}
+
+func runReportClients(cmd *cobra.Command, args []string) error {
+	if _, err := types.ParseLookback(reportClientsCmdSince); err != nil {
+		return err
+	}
+	breakdown, err := apiClient.ClientBreakdown(reportClientsCmdSince)
+	if err != nil {
+		return fmt.Errorf("failed to get the client breakdown: %w", err)
+	}
+
+	return renderOutput(cmd, breakdown, func() error {
+		if breakdown.Sessions == 0 {
+			cmd.Printf("No sessions were initialized in the last %s\n", reportClientsCmdSince)
+			return nil
+		}
+		cmd.Printf("Sessions in the last %s: %d\n", reportClientsCmdSince, breakdown.Sessions)
+		sections := []struct {
+			title  string
+			counts map[string]int64
+		}{
+			{"Applications", breakdown.Applications},
+			{"Protocol versions", breakdown.ProtocolVersions},
+			{"Capabilities", breakdown.Capabilities},
+		}
+		for _, s := range sections {
+			if len(s.counts) == 0 {
+				continue
+			}
+			cmd.Printf("\n%s:\n", s.title)
+			names := slices.Collect(maps.Keys(s.counts))
+			slices.SortFunc(names, func(a, b string) int {
+				if c := cmp.Compare(s.counts[b], s.counts[a]); c != 0 {
+					return c
+				}
+				return strings.Compare(a, b)
+			})
+			for _, name := range names {
+				cmd.Printf("  %s  sessions=%d (%.1f%%)\n",
+					name, s.counts[name], 100*float64(s.counts[name])/float64(breakdown.Sessions))
+			}
+		}
+
+		cmd.Println("\nClients:")
+		for _, c := range breakdown.Clients {
+			version := c.Version
+			if version == "" {
+				version = "?"
+			}
+			capabilities := strings.Join(c.Capabilities, ",")
+			if capabilities == "" {
+				capabilities = "none"
+			}
+			cmd.Printf("  %s %s  protocol=%s  capabilities=%s  sessions=%d  last seen %s\n",
+				c.Name, version, c.ProtocolVersion, capabilities, c.Sessions, c.LastSeenAt.Format(time.RFC3339))
+		}
+		return nil
+	})
+}
@@ -21,10 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
 	"github.com/mcpjungle/mcpjungle/client"
 	"github.com/mcpjungle/mcpjungle/cmd/config"
@@ -61,6 +64,20 @@ var SilentErr = errors.New("SilentErr")
 
 var registryServerURL string
 
+// cmdContextName is the name of the client context supplied via the --context flag
+var cmdContextName string
+
+// activeRegistryURL is the URL of the registry that commands talk to, resolved from the
+// --registry flag and the client context in use.
+var activeRegistryURL string
+
+// activeCredentialKey is the key of the access token stored for the active registry, see config.CredentialKey
+var activeCredentialKey string
+
+// accessTokenRejected is set when the registry responds to a request with 401 Unauthorized,
+// eg- because the stored access token was revoked
+var accessTokenRejected bool
+
 // apiClient is the global API client used by command handlers to interact with the MCPJungle registry server.
 // It is not the best choice to rely on a global variable, but cobra doesn't seem to provide any neat way to
 // pass an object down the command tree.
@@ -107,14 +124,159 @@ func Execute() error {
 		"http://127.0.0.1:"+BindPortDefault,
 		"Base URL of the MCPJungle registry server",
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&cmdOutputFormat,
+		"output",
+		"o",
+		string(outputFormatTable),
+		fmt.Sprintf(
+			"Output format of commands that display resources ('%s' | '%s' | '%s')",
+			outputFormatTable, outputFormatJSON, outputFormatYAML,
+		),
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&cmdContextName,
+		"context",
+		"",
+		"Name of the client context to use (overrides the current context set via 'config use-context')",
+	)
 
 	// Initialize the API client with the registry server URL & client configuration (if any)
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(cmdOutputFormat); err != nil {
+			return err
+		}
 		cfg := config.Load()
-		apiClient = client.NewClient(registryServerURL, cfg.AccessToken, http.DefaultClient)
+		overrideContext := cmd.Flags().Changed("registry")
+		c, registryURL, err := newContextClient(cfg, cmdContextName, registryServerURL, overrideContext)
+		if err != nil {
+			return err
+		}
+		activeRegistryURL = registryURL
+		activeCredentialKey = credentialKey(cfg, cmdContextName, registryURL, overrideContext)
+		apiClient = c
+		return nil
+	}
+
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && accessTokenRejected && cmd != loginCmd && cmd != logoutCmd {
+		offerLogin(cmd)
+	}
+	return err
+}
+
+// newContextClient creates an API client for the registry of the named client context (the current one if empty).
+// registryURL is used when no context is in use, or instead of the context's registry if overrideContext is true.
+// It returns the client and the URL of the registry it talks to.
+func newContextClient(
+	cfg *config.ClientConfig, contextName, registryURL string, overrideContext bool,
+) (*client.Client, string, error) {
+	ctx, err := cfg.GetContext(contextName)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return rootCmd.Execute()
+	var opts []client.Option
+	accessToken := cfg.AccessToken
+	if ctx != nil {
+		accessToken = ""
+		// an explicitly supplied --registry flag always takes precedence over the context.
+		// The context's token is only ever sent to the context's registry, the registry of the flag
+		// is only sent the token stored for its URL.
+		if !overrideContext {
+			accessToken = ctx.AccessToken
+			registryURL = ctx.RegistryURL
+		}
+	}
+	if accessToken == "" {
+		// tokens obtained via the login command are kept in the OS keychain (or credentials file).
+		// They are only loaded by the commands that call the API, so that a token which can't be loaded
+		// (eg- because its passphrase is wrong) doesn't prevent the others from running, like login & logout.
+		key, url := credentialKey(cfg, contextName, registryURL, overrideContext), registryURL
+		opts = append(opts, client.WithAccessTokenLoader(func() (string, error) {
+			token, err := loadStoredAccessToken(key, url)
+			if err != nil {
+				return "", fmt.Errorf("failed to load stored credentials: %w", err)
+			}
+			return token, nil
+		}))
+	}
+	observer := func(s client.RequestStats) {
+		if s.StatusCode == http.StatusUnauthorized {
+			accessTokenRejected = true
+		}
+	}
+	opts = append(opts, client.WithRequestObserver(observer))
+	return client.NewClient(registryURL, accessToken, http.DefaultClient, opts...), registryURL, nil
+}
+
+// credentialKey returns the key of the access token stored for the registry at registryURL.
+// The token is scoped to the client context in use, unless the --registry flag overrides the context's registry.
+func credentialKey(cfg *config.ClientConfig, contextName, registryURL string, overrideContext bool) string {
+	if contextName == "" {
+		contextName = cfg.CurrentContext
+	}
+	if _, ok := cfg.Contexts[contextName]; !ok || overrideContext {
+		return config.CredentialKey("", registryURL)
+	}
+	return config.CredentialKey(contextName, registryURL)
+}
+
+// loadStoredAccessToken loads the access token stored under the given key, prompting for the passphrase
+// that encrypts it if needed.
+// Tokens stored before they were scoped to client contexts are keyed by registryURL. Such a token is moved
+// under the key of the first context that uses it, once, so that it isn't shared by every context of the registry.
+func loadStoredAccessToken(key, registryURL string) (string, error) {
+	token, err := loadCredential(key)
+	if err != nil || token != "" || key == registryURL {
+		return token, err
+	}
+	token, err = loadCredential(registryURL)
+	if err != nil || token == "" {
+		return "", err
+	}
+	if _, err := config.SaveCredential(key, token); err != nil {
+		return "", fmt.Errorf("failed to move the access token stored for %s to the client context: %w", registryURL, err)
+	}
+	if err := config.DeleteCredential(registryURL); err != nil {
+		return "", fmt.Errorf("failed to move the access token stored for %s to the client context: %w", registryURL, err)
+	}
+	fmt.Fprintf(os.Stderr, "Moved the access token stored for %s to the client context (%s)\n", registryURL, key)
+	return token, nil
+}
+
+// loadCredential loads the access token stored under the given key, prompting for the passphrase that encrypts it if needed.
+func loadCredential(key string) (string, error) {
+	token, err := config.LoadCredential(key)
+	if errors.Is(err, config.ErrPassphraseRequired) && term.IsTerminal(int(os.Stdin.Fd())) {
+		p, perr := promptSecret("Passphrase of your stored access token: ")
+		if perr != nil {
+			return "", perr
+		}
+		config.SetPassphrase(p)
+		token, err = config.LoadCredential(key)
+	}
+	return token, err
+}
+
+// offerLogin is called when a command failed because the registry rejected the access token, eg- because
+// it was revoked or the user never logged in. In a terminal, it offers to log in again right away.
+func offerLogin(cmd *cobra.Command) {
+	cmd.PrintErrf("The registry at %s rejected the request: you are not logged in or your access token was revoked.\n", activeRegistryURL)
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		cmd.PrintErrln("Run 'mcpjungle login' to log in again.")
+		return
+	}
+	answer, err := promptLine("Log in again now? [y/N]: ")
+	if err != nil || !strings.EqualFold(answer, "y") {
+		return
+	}
+	if err := runLogin(cmd, nil); err != nil {
+		cmd.PrintErrln(err)
+		return
+	}
+	cmd.PrintErrln("Run your command again.")
 }
 
 // displayRootCmdHelpMsg displays custom help message for the root command, ie,
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+
+	"github.com/mcpjungle/mcpjungle/cmd/config"
+)
+
+func TestUndecryptableStoredTokenOnlyFailsAPICommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	keyring.MockInitWithError(errors.New("no keychain available"))
+	t.Cleanup(func() { config.SetPassphrase("") })
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/api/v0/users/whoami" && r.Header.Get("Authorization") == "Bearer mcpj_new" {
+			_, _ = w.Write([]byte(`{"username":"alice","role":"user"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	// an enc:v1: entry, read back with a wrong passphrase
+	config.SetPassphrase("correct horse")
+	if _, err := config.SaveCredential(srv.URL, "mcpj_old"); err != nil {
+		t.Fatalf("failed to store access token: %v", err)
+	}
+	config.SetPassphrase("wrong")
+
+	c, registryURL, err := newContextClient(&config.ClientConfig{}, "", srv.URL, false)
+	if err != nil {
+		t.Fatalf("newContextClient() error = %v, want the stored token to only be loaded by API calls", err)
+	}
+	if _, err := c.ListServers(); err == nil || !strings.Contains(err.Error(), "passphrase is wrong") {
+		t.Errorf("ListServers() error = %v, want the stored token's decryption error", err)
+	}
+	if requests != 0 {
+		t.Errorf("%d requests were sent with an undecryptable token, want none", requests)
+	}
+
+	oldClient, oldURL, oldKey := apiClient, activeRegistryURL, activeCredentialKey
+	t.Cleanup(func() { apiClient, activeRegistryURL, activeCredentialKey = oldClient, oldURL, oldKey })
+	apiClient, activeRegistryURL, activeCredentialKey = c, registryURL, srv.URL
+	cmd := &cobra.Command{}
+	cmd.SetOut(&strings.Builder{})
+
+	// login verifies the new token without loading the stored one
+	if user, err := apiClient.Whoami("mcpj_new"); err != nil || user.Username != "alice" {
+		t.Errorf("Whoami() = %v, %v, want alice", user, err)
+	}
+
+	if err := runLogout(cmd, nil); err != nil {
+		t.Fatalf("runLogout() error = %v", err)
+	}
+	if config.HasCredential(srv.URL) {
+		t.Error("the undecryptable access token is still stored after logout")
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	savedCallCreateCmdTool        string
+	savedCallCreateCmdArgs        string
+	savedCallCreateCmdDescription string
+
+	savedCallRunCmdArgs string
+	savedCallRunCmdRaw  bool
+)
+
+var savedCallCmd = &cobra.Command{
+	Use:   "saved-call",
+	Short: "Manage saved tool calls",
+	Long: "A saved call is a named set of arguments for a tool. Saved calls are shared by all users of the registry\n" +
+		"and can be replayed to invoke the tool the same way every time, which standardizes common operations.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "19",
+	},
+}
+
+var savedCallCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Save a set of arguments for a tool",
+	Long: "Save a set of arguments for a tool under a name.\n" +
+		"\neg- mcpjungle saved-call create deploy-prod --tool ci__deploy --args '{\"env\": \"prod\", \"branch\": \"main\"}'",
+	RunE: runSavedCallCreate,
+}
+
+var savedCallListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved calls",
+	RunE:  runSavedCallList,
+}
+
+var savedCallRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Invoke the tool of a saved call",
+	Long: "Invoke the tool of a saved call with its saved arguments.\n" +
+		"Arguments passed with --args are merged into the saved ones and take precedence over them.\n" +
+		"\neg- mcpjungle saved-call run deploy-prod --args '{\"branch\": \"hotfix\"}'",
+	RunE: runSavedCallRun,
+}
+
+var savedCallDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a saved call",
+	RunE:  runSavedCallDelete,
+}
+
+func init() {
+	savedCallCreateCmd.Flags().StringVar(&savedCallCreateCmdTool, "tool", "", "Name of the tool to invoke")
+	savedCallCreateCmd.Flags().StringVar(&savedCallCreateCmdArgs, "args", "{}", "Arguments of the tool as a JSON object")
+	savedCallCreateCmd.Flags().StringVar(
+		&savedCallCreateCmdDescription, "description", "", "Description of what the saved call does",
+	)
+	_ = savedCallCreateCmd.MarkFlagRequired("tool")
+
+	savedCallRunCmd.Flags().StringVar(
+		&savedCallRunCmdArgs, "args", "{}", "Arguments as a JSON object, these override the saved arguments",
+	)
+	savedCallRunCmd.Flags().BoolVar(
+		&savedCallRunCmdRaw, "raw", false, "Print the result as JSON as is, instead of rendering it",
+	)
+
+	savedCallCmd.AddCommand(savedCallCreateCmd)
+	savedCallCmd.AddCommand(savedCallListCmd)
+	savedCallCmd.AddCommand(savedCallRunCmd)
+	savedCallCmd.AddCommand(savedCallDeleteCmd)
+	rootCmd.AddCommand(savedCallCmd)
+}
+
+func runSavedCallCreate(cmd *cobra.Command, args []string) error {
+	var toolArgs map[string]any
+	if err := json.Unmarshal([]byte(savedCallCreateCmdArgs), &toolArgs); err != nil {
+		return fmt.Errorf("invalid args, must be a JSON object: %w", err)
+	}
+	call, err := apiClient.CreateSavedCall(&types.SavedCall{
+		Name:        args[0],
+		Description: savedCallCreateCmdDescription,
+		Tool:        savedCallCreateCmdTool,
+		Args:        toolArgs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create saved call: %w", err)
+	}
+	cmd.Printf("Saved call %s created, run it with 'mcpjungle saved-call run %s'\n", call.Name, call.Name)
+	return nil
+}
+
+func runSavedCallList(cmd *cobra.Command, args []string) error {
+	calls, err := apiClient.ListSavedCalls()
+	if err != nil {
+		return fmt.Errorf("failed to list saved calls: %w", err)
+	}
+
+	return renderOutput(cmd, calls, func() error {
+		if len(calls) == 0 {
+			cmd.Println("There are no saved calls in the registry")
+			return nil
+		}
+		for i, c := range calls {
+			cmd.Printf("%d. %s -> %s\n", i+1, c.Name, c.Tool)
+			if c.Description != "" {
+				cmd.Println(c.Description)
+			}
+			if len(c.Args) > 0 {
+				b, _ := json.Marshal(c.Args)
+				cmd.Printf("Args: %s\n", b)
+			}
+			if i < len(calls)-1 {
+				cmd.Println()
+			}
+		}
+		return nil
+	})
+}
+
+func runSavedCallRun(cmd *cobra.Command, args []string) error {
+	var overrides map[string]any
+	if err := json.Unmarshal([]byte(savedCallRunCmdArgs), &overrides); err != nil {
+		return fmt.Errorf("invalid args, must be a JSON object: %w", err)
+	}
+	result, err := apiClient.InvokeSavedCall(args[0], overrides)
+	if err != nil {
+		return fmt.Errorf("failed to run saved call: %w", err)
+	}
+	return printToolInvokeResult(result, savedCallRunCmdRaw)
+}
+
+func runSavedCallDelete(cmd *cobra.Command, args []string) error {
+	if err := apiClient.DeleteSavedCall(args[0]); err != nil {
+		return fmt.Errorf("failed to delete saved call: %w", err)
+	}
+	cmd.Printf("Saved call '%s' deleted successfully (if it existed)\n", args[0])
+	return nil
+}
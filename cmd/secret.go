@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var secretSetCmdFromStdin bool
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage the secrets that MCP servers use",
+	Long: "Secrets are stored in the registry and referred to by name in the environment of stdio MCP servers,\n" +
+		"eg- \"env\": {\"OPENAI_API_KEY\": \"${secret:OPENAI_KEY}\"}, so that credentials don't end up in server configs.\n" +
+		"References are resolved every time a server is started: rotating a secret restarts the servers kept warm\n" +
+		"that use it, and other servers pick up the new value on their next call.\n" +
+		"Set SECRETS_ENCRYPTION_KEY on the server to encrypt secrets in the database.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "32",
+	},
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set [name] [value]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Create a secret or change its value",
+	Long: "Create a secret or change its value. Prefer --from-stdin, which keeps the value out of your shell history.\n" +
+		"\neg- mcpjungle secret set OPENAI_KEY --from-stdin < openai.key",
+	RunE: runSecretSet,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the secrets, without their values",
+	RunE:  runSecretList,
+}
+
+var secretDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a secret that no MCP server refers to",
+	RunE:  runSecretDelete,
+}
+
+func init() {
+	secretSetCmd.Flags().BoolVar(&secretSetCmdFromStdin, "from-stdin", false, "Read the value of the secret from stdin")
+
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretDeleteCmd)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	var value string
+	switch {
+	case secretSetCmdFromStdin && len(args) == 2:
+		return errors.New("the value of the secret must be passed either as an argument or with --from-stdin, not both")
+	case secretSetCmdFromStdin:
+		b, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read the secret from stdin: %w", err)
+		}
+		value = strings.TrimRight(string(b), "\r\n")
+	case len(args) == 2:
+		value = args[1]
+	default:
+		return errors.New("the value of the secret is missing, pass it as an argument or with --from-stdin")
+	}
+
+	result, err := apiClient.SetSecret(&types.SetSecretInput{Name: args[0], Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to set secret: %w", err)
+	}
+	return renderOutput(cmd, result, func() error {
+		cmd.Printf("Secret %s set (version %d)\n", result.Name, result.Version)
+		if len(result.Servers) > 0 {
+			cmd.Printf("Used by MCP servers: %s\n", strings.Join(result.Servers, ", "))
+		}
+		return nil
+	})
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	secrets, err := apiClient.ListSecrets()
+	if err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return renderOutput(cmd, secrets, func() error {
+		if len(secrets) == 0 {
+			cmd.Println("There are no secrets")
+			return nil
+		}
+		for _, s := range secrets {
+			cmd.Printf("%s (version %d, updated %s)\n", s.Name, s.Version, s.UpdatedAt.Local().Format(time.DateTime))
+		}
+		return nil
+	})
+}
+
+func runSecretDelete(cmd *cobra.Command, args []string) error {
+	if err := apiClient.DeleteSecret(args[0]); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	cmd.Printf("Secret %s deleted\n", args[0])
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var serversCmdGroup string
+
+var serversCmd = &cobra.Command{
+	Use:   "servers",
+	Short: "Stop & start groups of MCP servers",
+	Long: "MCP servers registered with a group (eg- 'mcpjungle register --group analytics') can be stopped & started\n" +
+		"together, eg- during the scheduled maintenance of a whole subsystem.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "35",
+	},
+}
+
+var serversStopCmd = &cobra.Command{
+	Use:   "stop",
+	Args:  cobra.NoArgs,
+	Short: "Stop all the MCP servers of a group",
+	Long: "Disable the tools of all the MCP servers of a group and close the connections to them\n" +
+		"(the processes of stdio servers are terminated). mcpjungle doesn't connect to the servers again\n" +
+		"until the group is started.\n" +
+		"\neg- mcpjungle servers stop --group analytics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServerGroupLifecycle(cmd, false)
+	},
+}
+
+var serversStartCmd = &cobra.Command{
+	Use:   "start",
+	Args:  cobra.NoArgs,
+	Short: "Start the stopped MCP servers of a group",
+	Long: "Start the stopped MCP servers of a group and re-enable the tools that were enabled when they were stopped.\n" +
+		"\neg- mcpjungle servers start --group analytics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServerGroupLifecycle(cmd, true)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{serversStopCmd, serversStartCmd} {
+		c.Flags().StringVar(&serversCmdGroup, "group", "", "Name of the server group")
+		_ = c.MarkFlagRequired("group")
+		serversCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(serversCmd)
+}
+
+func runServerGroupLifecycle(cmd *cobra.Command, start bool) error {
+	var (
+		result *types.ServerGroupLifecycleResult
+		err    error
+	)
+	if start {
+		result, err = apiClient.StartServerGroup(serversCmdGroup)
+	} else {
+		result, err = apiClient.StopServerGroup(serversCmdGroup)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to change the state of server group %s: %w", serversCmdGroup, err)
+	}
+
+	return renderOutput(cmd, result, func() error {
+		state, toolsState := "stopped", "disabled"
+		if start {
+			state, toolsState = "started", "enabled"
+		}
+		if len(result.Servers) == 0 {
+			cmd.Printf("All the MCP servers of group %s are already %s\n", result.Group, state)
+			return nil
+		}
+		cmd.Printf("MCP servers %s: %s\n", state, strings.Join(result.Servers, ", "))
+		if len(result.Tools) > 0 {
+			cmd.Printf("Tools %s: %s\n", toolsState, strings.Join(result.Tools, ", "))
+		}
+		return nil
+	})
+}
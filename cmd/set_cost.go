@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setCostCmdPerCall float64
+	setCostCmdPerUnit float64
+	setCostCmdUnitArg string
+	setCostCmdVersion int
+)
+
+var setCostCmd = &cobra.Command{
+	Use:   "set-cost <tool name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Set the cost of calling a tool",
+	Long: "Set the pricing of a tool. The cost of every call to the tool is recorded against the calling MCP client or user\n" +
+		"and counts towards their budgets (see 'mcpjungle create budget').\n" +
+		"A call costs --per-call, plus --per-unit for every unit requested via the numeric input argument named by --unit-arg.\n" +
+		"\neg- A search tool that costs 0.01 per call plus 0.001 per result requested via its 'num_results' argument:\n" +
+		"    mcpjungle set-cost search__query --per-call 0.01 --per-unit 0.001 --unit-arg num_results",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "8",
+	},
+	RunE: runSetCost,
+}
+
+func init() {
+	setCostCmd.Flags().Float64Var(&setCostCmdPerCall, "per-call", 0, "Cost charged for every call to the tool")
+	setCostCmd.Flags().Float64Var(&setCostCmdPerUnit, "per-unit", 0, "Cost charged per unit requested by a call")
+	setCostCmd.Flags().StringVar(
+		&setCostCmdUnitArg,
+		"unit-arg",
+		"",
+		"Name of the tool's numeric input argument that holds the number of units requested by a call",
+	)
+	setCostCmd.Flags().IntVar(&setCostCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
+	rootCmd.AddCommand(setCostCmd)
+}
+
+func runSetCost(cmd *cobra.Command, args []string) error {
+	input := &types.SetToolCostInput{
+		Name:        args[0],
+		CostPerCall: setCostCmdPerCall,
+		CostPerUnit: setCostCmdPerUnit,
+		CostUnitArg: setCostCmdUnitArg,
+		Version:     setCostCmdVersion,
+	}
+	if err := apiClient.SetToolCost(input); err != nil {
+		return fmt.Errorf("failed to set cost of tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Cost of tool %s updated successfully\n", args[0])
+	return nil
+}
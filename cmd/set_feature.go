@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var setFeatureCmd = &cobra.Command{
+	Use:   "set-feature <feature name> <on|off>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Turn an experimental feature on or off",
+	Long: "Turn an experimental feature of mcpjungle on or off for the whole deployment.\n" +
+		"Features configured in the server's FEATURES environment variable can't be changed.\n" +
+		"Use 'mcpjungle list features' to view all features.\n" +
+		"\neg- mcpjungle set-feature hedging off",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "12",
+	},
+	RunE: runSetFeature,
+}
+
+func init() {
+	rootCmd.AddCommand(setFeatureCmd)
+}
+
+func runSetFeature(cmd *cobra.Command, args []string) error {
+	if args[1] != "on" && args[1] != "off" {
+		return fmt.Errorf("invalid state '%s', must be 'on' or 'off'", args[1])
+	}
+	input := &types.SetFeatureInput{Name: args[0], Enabled: args[1] == "on"}
+	if err := apiClient.SetFeature(input); err != nil {
+		return fmt.Errorf("failed to set feature %s: %w", args[0], err)
+	}
+	cmd.Printf("Feature %s turned %s\n", args[0], args[1])
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setHedgingCmdDelay   string
+	setHedgingCmdVersion int
+)
+
+var setHedgingCmd = &cobra.Command{
+	Use:   "set-hedging <tool name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Send hedged requests for slow calls to a tool",
+	Long: "Enable hedged requests for a latency-sensitive tool.\n" +
+		"If a call to the tool has not completed after --delay, a second request is sent to an equivalent MCP server\n" +
+		"(one that provides a tool with the same name) and the first successful response is used.\n" +
+		"Only tools annotated as idempotent or read-only can be hedged. Use '--delay 0' to disable hedging.\n" +
+		"\neg- mcpjungle set-hedging search-eu__query --delay 300ms",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "10",
+	},
+	RunE: runSetHedging,
+}
+
+func init() {
+	setHedgingCmd.Flags().StringVar(
+		&setHedgingCmdDelay, "delay", "", "Time after which a hedged request is sent (eg- 300ms), 0 disables hedging",
+	)
+	_ = setHedgingCmd.MarkFlagRequired("delay")
+	setHedgingCmd.Flags().IntVar(&setHedgingCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
+	rootCmd.AddCommand(setHedgingCmd)
+}
+
+func runSetHedging(cmd *cobra.Command, args []string) error {
+	input := &types.SetToolHedgingInput{Name: args[0], Delay: setHedgingCmdDelay, Version: setHedgingCmdVersion}
+	if err := apiClient.SetToolHedging(input); err != nil {
+		return fmt.Errorf("failed to set hedging of tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Hedging of tool %s updated successfully\n", args[0])
+	return nil
+}
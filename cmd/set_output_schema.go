@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setOutputSchemaCmdFile    string
+	setOutputSchemaCmdClear   bool
+	setOutputSchemaCmdVersion int
+)
+
+var setOutputSchemaCmd = &cobra.Command{
+	Use:   "set-output-schema <tool name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Declare the structure of a tool's results",
+	Long: "Set the JSON schema (of type object) that describes the results of a tool.\n" +
+		"If the upstream MCP server only returns its results as JSON text, MCPJungle also returns them as\n" +
+		"structured content to tool calls made via the API, so that consumers relying on 'structuredContent'\n" +
+		"work with older MCP servers.\n" +
+		"\neg- mcpjungle set-output-schema weather__get_forecast --file forecast-schema.json",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "9",
+	},
+	RunE: runSetOutputSchema,
+}
+
+func init() {
+	setOutputSchemaCmd.Flags().StringVarP(
+		&setOutputSchemaCmdFile, "file", "f", "", "Path to a file containing the JSON schema of the tool's results",
+	)
+	setOutputSchemaCmd.Flags().BoolVar(&setOutputSchemaCmdClear, "clear", false, "Remove the tool's output schema")
+	setOutputSchemaCmd.MarkFlagsOneRequired("file", "clear")
+	setOutputSchemaCmd.MarkFlagsMutuallyExclusive("file", "clear")
+	setOutputSchemaCmd.Flags().IntVar(&setOutputSchemaCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
+	rootCmd.AddCommand(setOutputSchemaCmd)
+}
+
+func runSetOutputSchema(cmd *cobra.Command, args []string) error {
+	input := &types.SetToolOutputSchemaInput{Name: args[0], Version: setOutputSchemaCmdVersion}
+	if !setOutputSchemaCmdClear {
+		data, err := os.ReadFile(setOutputSchemaCmdFile)
+		if err != nil {
+			return fmt.Errorf("failed to read output schema file %s: %w", setOutputSchemaCmdFile, err)
+		}
+		input.OutputSchema = data
+	}
+	if err := apiClient.SetToolOutputSchema(input); err != nil {
+		return fmt.Errorf("failed to set output schema of tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Output schema of tool %s updated successfully\n", args[0])
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setPayloadLoggingCmdSampleRate float64
+	setPayloadLoggingCmdRedact     []string
+	setPayloadLoggingCmdVersion    int
+)
+
+var setPayloadLoggingCmd = &cobra.Command{
+	Use:   "set-payload-logging <tool name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Log the arguments & results of a sample of the calls to a tool",
+	Long: "Store the arguments & results of a fraction of the calls to a tool in the invocation history,\n" +
+		"to debug the behaviour of agents without logging every payload.\n" +
+		"Fields whose name suggests a secret (eg- password, api_key, access_token) are always removed.\n" +
+		"Remove other fields with --redact: a field name matches the field at any depth, a dot-separated path\n" +
+		"(eg- user.email or *.ssn) matches from the root of the payload. Use '--sample-rate 0' to stop logging.\n" +
+		"\neg- mcpjungle set-payload-logging crm__find_customer --sample-rate 0.1 --redact email,address.street",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "26",
+	},
+	RunE: runSetPayloadLogging,
+}
+
+func init() {
+	setPayloadLoggingCmd.Flags().Float64Var(
+		&setPayloadLoggingCmdSampleRate, "sample-rate", 0, "Fraction of the calls whose payloads are logged, between 0 and 1",
+	)
+	_ = setPayloadLoggingCmd.MarkFlagRequired("sample-rate")
+	setPayloadLoggingCmd.Flags().StringSliceVar(
+		&setPayloadLoggingCmdRedact, "redact", nil, "Comma-separated paths of the fields to remove from the logged payloads",
+	)
+	setPayloadLoggingCmd.Flags().IntVar(&setPayloadLoggingCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
+	rootCmd.AddCommand(setPayloadLoggingCmd)
+}
+
+func runSetPayloadLogging(cmd *cobra.Command, args []string) error {
+	input := &types.SetToolPayloadLoggingInput{
+		Name:       args[0],
+		SampleRate: setPayloadLoggingCmdSampleRate,
+		Redact:     setPayloadLoggingCmdRedact,
+		Version:    setPayloadLoggingCmdVersion,
+	}
+	if err := apiClient.SetToolPayloadLogging(input); err != nil {
+		return fmt.Errorf("failed to set payload logging of tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Payload logging of tool %s updated successfully\n", args[0])
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setPromptOverrideCmdClient   string
+	setPromptOverrideCmdTemplate string
+	setPromptOverrideCmdFile     string
+)
+
+var setPromptOverrideCmd = &cobra.Command{
+	Use:   "set-prompt-override <prompt name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Customize a prompt template for a specific MCP client",
+	Long: "Set the template of a prompt that is served to a specific MCP client instead of the prompt's regular template.\n" +
+		"The override can use the same {{argument}} and {{client.<attribute>}} placeholders as the regular template.\n" +
+		"Use \"--template ''\" to remove the override.\n" +
+		"\neg- mcpjungle set-prompt-override code-review --client ci-bot --template 'Review {{code}}, only report blocking issues'",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "16",
+	},
+	RunE: runSetPromptOverride,
+}
+
+func init() {
+	setPromptOverrideCmd.Flags().StringVar(
+		&setPromptOverrideCmdClient, "client", "", "Name of the MCP client that the override applies to",
+	)
+	setPromptOverrideCmd.Flags().StringVar(
+		&setPromptOverrideCmdTemplate, "template", "", "Template used for the client, empty to remove the override",
+	)
+	setPromptOverrideCmd.Flags().StringVarP(
+		&setPromptOverrideCmdFile, "file", "f", "", "Path to a file containing the template used for the client",
+	)
+	_ = setPromptOverrideCmd.MarkFlagRequired("client")
+	setPromptOverrideCmd.MarkFlagsOneRequired("template", "file")
+	setPromptOverrideCmd.MarkFlagsMutuallyExclusive("template", "file")
+	rootCmd.AddCommand(setPromptOverrideCmd)
+}
+
+func runSetPromptOverride(cmd *cobra.Command, args []string) error {
+	template := setPromptOverrideCmdTemplate
+	if setPromptOverrideCmdFile != "" {
+		data, err := os.ReadFile(setPromptOverrideCmdFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template file %s: %w", setPromptOverrideCmdFile, err)
+		}
+		template = string(data)
+	}
+	input := &types.SetPromptOverrideInput{Prompt: args[0], Client: setPromptOverrideCmdClient, Template: template}
+	if err := apiClient.SetPromptOverride(input); err != nil {
+		return fmt.Errorf("failed to set override of prompt %s: %w", args[0], err)
+	}
+	if template == "" {
+		cmd.Printf("Override of prompt %s for client %s removed\n", args[0], setPromptOverrideCmdClient)
+	} else {
+		cmd.Printf("Override of prompt %s for client %s set successfully\n", args[0], setPromptOverrideCmdClient)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var setReadOnlyCmd = &cobra.Command{
+	Use:       "set-read-only [on|off]",
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"on", "off"},
+	Short:     "Turn the read-only mode of the registry on or off",
+	Long: "In read-only mode, all management operations that modify the registry (eg- registering servers,\n" +
+		"enabling tools, creating clients) are rejected, while tools can still be listed and called.\n" +
+		"This is useful during migrations or incident freezes.\n" +
+		"Without an argument, the current mode is printed.\n" +
+		"\neg- mcpjungle set-read-only on",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "11",
+	},
+	RunE: runSetReadOnly,
+}
+
+func init() {
+	rootCmd.AddCommand(setReadOnlyCmd)
+}
+
+func runSetReadOnly(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		status, err := apiClient.GetReadOnly()
+		if err != nil {
+			return fmt.Errorf("failed to get read-only mode: %w", err)
+		}
+		switch {
+		case status.Forced:
+			cmd.Println("Read-only mode is on (forced when the server was started)")
+		case status.ReadOnly:
+			cmd.Println("Read-only mode is on")
+		default:
+			cmd.Println("Read-only mode is off")
+		}
+		return nil
+	}
+
+	enabled := args[0] == "on"
+	if err := apiClient.SetReadOnly(enabled); err != nil {
+		return fmt.Errorf("failed to set read-only mode: %w", err)
+	}
+	cmd.Printf("Read-only mode turned %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setToolDocsCmdDescription string
+	setToolDocsCmdAppend      string
+	setToolDocsCmdExamples    []string
+	setToolDocsCmdClear       bool
+	setToolDocsCmdVersion     int
+)
+
+var setToolDocsCmd = &cobra.Command{
+	Use:   "set-tool-docs <tool name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Improve the description of a tool",
+	Long: "Enrich the description of a tool that MCP clients see, which helps agents pick the right tool\n" +
+		"when the upstream MCP server documents its tools poorly.\n" +
+		"--description replaces the upstream description, --append adds text after it and --example adds usage examples.\n" +
+		"Every run replaces the documentation previously added to the tool. Use --clear to go back to the upstream description.\n" +
+		"\neg- mcpjungle set-tool-docs github__search_code --append 'Prefer this over web search for code questions' " +
+		"--example '{\"q\": \"repo:mcpjungle/MCPJungle RegisterMcpServer\"}'",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "17",
+	},
+	RunE: runSetToolDocs,
+}
+
+func init() {
+	setToolDocsCmd.Flags().StringVar(
+		&setToolDocsCmdDescription, "description", "", "Description that replaces the one provided by the MCP server",
+	)
+	setToolDocsCmd.Flags().StringVar(&setToolDocsCmdAppend, "append", "", "Text added after the tool's description")
+	setToolDocsCmd.Flags().StringArrayVar(
+		&setToolDocsCmdExamples, "example", nil, "Usage example listed in the tool's description, can be repeated",
+	)
+	setToolDocsCmd.Flags().BoolVar(&setToolDocsCmdClear, "clear", false, "Remove all documentation added to the tool")
+	setToolDocsCmd.Flags().IntVar(&setToolDocsCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
+	setToolDocsCmd.MarkFlagsOneRequired("description", "append", "example", "clear")
+	setToolDocsCmd.MarkFlagsMutuallyExclusive("description", "clear")
+	setToolDocsCmd.MarkFlagsMutuallyExclusive("append", "clear")
+	setToolDocsCmd.MarkFlagsMutuallyExclusive("example", "clear")
+	rootCmd.AddCommand(setToolDocsCmd)
+}
+
+func runSetToolDocs(cmd *cobra.Command, args []string) error {
+	input := &types.SetToolDocsInput{
+		Name:        args[0],
+		Description: setToolDocsCmdDescription,
+		Append:      setToolDocsCmdAppend,
+		Examples:    setToolDocsCmdExamples,
+		Version:     setToolDocsCmdVersion,
+	}
+	if err := apiClient.SetToolDocs(input); err != nil {
+		return fmt.Errorf("failed to set documentation of tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Documentation of tool %s updated successfully\n", args[0])
+	return nil
+}
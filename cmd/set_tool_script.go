@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setToolScriptCmdArgs    string
+	setToolScriptCmdResult  string
+	setToolScriptCmdClear   bool
+	setToolScriptCmdVersion int
+)
+
+var setToolScriptCmd = &cobra.Command{
+	Use:   "set-tool-script <tool name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Transform the arguments and results of a tool with CEL scripts",
+	Long: "Attach small CEL (https://cel.dev) scripts to a tool, a lightweight alternative to WASM plugins.\n" +
+		"The --args script runs before every call with the variables client, user & args, " +
+		"the --result script runs after every successful call with the additional variable result.\n" +
+		"Both return an object whose fields are merged into the arguments or the result, fields set to null are removed.\n" +
+		"Every run replaces the scripts previously attached to the tool. Use --clear to remove them.\n" +
+		"\neg- mcpjungle set-tool-script search__query --args '{\"limit\": has(args.limit) ? args.limit : 10}' " +
+		"--result '{\"content\": result.content.filter(c, c.type == \"text\")}'",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "21",
+	},
+	RunE: runSetToolScript,
+}
+
+func init() {
+	setToolScriptCmd.Flags().StringVar(&setToolScriptCmdArgs, "args", "", "Script that transforms the arguments of every call")
+	setToolScriptCmd.Flags().StringVar(&setToolScriptCmdResult, "result", "", "Script that transforms the result of every successful call")
+	setToolScriptCmd.Flags().BoolVar(&setToolScriptCmdClear, "clear", false, "Remove all scripts attached to the tool")
+	setToolScriptCmd.Flags().IntVar(&setToolScriptCmdVersion, "if-version", 0, "Reject the change if the tool was modified since this version (see 'mcpjungle describe tool')")
+	setToolScriptCmd.MarkFlagsOneRequired("args", "result", "clear")
+	setToolScriptCmd.MarkFlagsMutuallyExclusive("args", "clear")
+	setToolScriptCmd.MarkFlagsMutuallyExclusive("result", "clear")
+	rootCmd.AddCommand(setToolScriptCmd)
+}
+
+func runSetToolScript(cmd *cobra.Command, args []string) error {
+	input := &types.SetToolScriptsInput{
+		Name:    args[0],
+		Args:    setToolScriptCmdArgs,
+		Result:  setToolScriptCmdResult,
+		Version: setToolScriptCmdVersion,
+	}
+	if err := apiClient.SetToolScripts(input); err != nil {
+		return fmt.Errorf("failed to set scripts of tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Scripts of tool %s updated successfully\n", args[0])
+	return nil
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/toolbox"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// smokeTestStartTimeout is the maximum time allowed for a local gateway started by the smoke test to become healthy
+const smokeTestStartTimeout = 30 * time.Second
+
+var (
+	smokeTestCmdLocal    bool
+	smokeTestCmdEchoAddr string
+	smokeTestCmdEchoURL  string
+)
+
+var smokeTestCmd = &cobra.Command{
+	Use:   "smoke-test",
+	Short: "Verify that a gateway works end-to-end",
+	Long: "Run an end-to-end check against the registry (or a throwaway local gateway with --local):\n" +
+		"a built-in echo MCP server is registered, its tools are listed and invoked, and the gateway's metrics are checked.\n" +
+		"The echo server is deregistered afterwards. The command fails if any check fails,\n" +
+		"so it can be used to verify a deployment.\n\n" +
+		"The echo server runs inside this command, so the gateway must be able to reach it.\n" +
+		"For a remote gateway, bind it to a reachable address with --echo-addr and, if needed,\n" +
+		"tell the gateway how to reach it with --echo-url.",
+	RunE: runSmokeTest,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "14",
+	},
+}
+
+func init() {
+	smokeTestCmd.Flags().BoolVar(
+		&smokeTestCmdLocal, "local", false, "Start a throwaway in-memory gateway and test it instead of the registry",
+	)
+	smokeTestCmd.Flags().StringVar(
+		&smokeTestCmdEchoAddr, "echo-addr", "127.0.0.1:0", "Address to run the echo MCP server on",
+	)
+	smokeTestCmd.Flags().StringVar(
+		&smokeTestCmdEchoURL,
+		"echo-url",
+		"",
+		"URL of the echo MCP server as seen by the gateway (defaults to the address it runs on)",
+	)
+	rootCmd.AddCommand(smokeTestCmd)
+}
+
+func runSmokeTest(cmd *cobra.Command, args []string) error {
+	registryURL, c := activeRegistryURL, apiClient
+	if smokeTestCmdLocal {
+		url, stop, err := startLocalGateway()
+		if err != nil {
+			return err
+		}
+		defer stop()
+		registryURL, c = url, client.NewClient(url, "", http.DefaultClient)
+	}
+
+	echoURL, stopEcho, err := startEchoServer(smokeTestCmdEchoAddr)
+	if err != nil {
+		return err
+	}
+	defer stopEcho()
+	if smokeTestCmdEchoURL != "" {
+		echoURL = smokeTestCmdEchoURL
+	}
+
+	// a unique name avoids clashing with servers already registered in a real deployment
+	serverName := "smoke_test_" + strconv.FormatInt(time.Now().Unix(), 36)
+	toolName := serverName + "__echo"
+	registered := false
+	defer func() {
+		if registered {
+			if err := c.DeregisterServer(serverName, 0); err != nil {
+				cmd.Printf("WARN: failed to deregister MCP server %s: %v\n", serverName, err)
+			}
+		}
+	}()
+
+	checks := []struct {
+		name string
+		run  func() error
+	}{
+		{"gateway is healthy", func() error {
+			_, err := httpGet(registryURL + "/health")
+			return err
+		}},
+		{"register echo MCP server", func() error {
+			_, err := c.RegisterServer(&types.RegisterServerInput{
+				Name:        serverName,
+				Transport:   string(types.TransportStreamableHTTP),
+				URL:         echoURL,
+				Description: "Temporary server registered by 'mcpjungle smoke-test'",
+			})
+			registered = err == nil
+			return err
+		}},
+		{"list tools", func() error {
+			tools, err := c.ListTools(serverName)
+			if err != nil {
+				return err
+			}
+			for _, t := range tools {
+				if t.Name == toolName {
+					return nil
+				}
+			}
+			return fmt.Errorf("tool %s is not listed", toolName)
+		}},
+		{"invoke tool", func() error {
+			res, err := c.InvokeTool(toolName, map[string]any{"message": "ping"})
+			if err != nil {
+				return err
+			}
+			if res.IsError || len(res.Content) == 0 || res.Content[0]["text"] != "ping" {
+				return fmt.Errorf("unexpected result: %+v", res)
+			}
+			return nil
+		}},
+		{"metrics are exported", func() error {
+			body, err := httpGet(registryURL + "/metrics")
+			if err != nil {
+				return err
+			}
+			if !strings.Contains(body, "mcpjungle_proxy_sessions_active") {
+				return errors.New("mcpjungle metrics are missing from /metrics")
+			}
+			return nil
+		}},
+	}
+
+	for _, check := range checks {
+		if err := check.run(); err != nil {
+			cmd.Printf("FAIL  %s: %v\n", check.name, err)
+			return errors.New("smoke test failed")
+		}
+		cmd.Printf("PASS  %s\n", check.name)
+	}
+	cmd.Println("\nSmoke test passed")
+	return nil
+}
+
+// startEchoServer runs the toolbox MCP server, which provides an "echo" tool, on addr.
+// It returns the URL of the server's MCP endpoint and a function to stop it.
+func startEchoServer(addr string) (string, func(), error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to start echo MCP server: %w", err)
+	}
+	s := toolbox.NewServer("mcpjungle-smoke-test", getVersion(), toolbox.Options{})
+	httpServer := &http.Server{Handler: server.NewStreamableHTTPServer(s)}
+	go func() {
+		_ = httpServer.Serve(l)
+	}()
+	return "http://" + l.Addr().String() + "/mcp", func() { _ = httpServer.Close() }, nil
+}
+
+// startLocalGateway starts an ephemeral gateway in a child process and waits for it to become healthy.
+// It returns the gateway's URL and a function to stop it.
+func startLocalGateway() (string, func(), error) {
+	// find a free port for the gateway
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	_ = l.Close()
+
+	self, err := os.Executable()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to locate the mcpjungle binary: %w", err)
+	}
+	gateway := exec.Command(self, "start", "--ephemeral", "--port", port)
+	if err := gateway.Start(); err != nil {
+		return "", nil, fmt.Errorf("failed to start local gateway: %w", err)
+	}
+	stop := func() {
+		_ = gateway.Process.Kill()
+		_ = gateway.Wait()
+	}
+
+	url := "http://127.0.0.1:" + port
+	deadline := time.Now().Add(smokeTestStartTimeout)
+	for {
+		if _, err := httpGet(url + "/health"); err == nil {
+			return url, stop, nil
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, fmt.Errorf("local gateway did not become healthy within %s", smokeTestStartTimeout)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// httpGet fetches a URL and returns the response body, failing on non-200 responses.
+func httpGet(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s responded with status %d", url, resp.StatusCode)
+	}
+	return string(body), nil
+}
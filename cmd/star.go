@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var starCmd = &cobra.Command{
+	Use:   "star [tool name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Add a tool to your favorites (Production mode)",
+	Long: "Star a tool to find it quickly in big catalogs. List your favorites with 'mcpjungle list favorites' and\n" +
+		"only the tools you starred with 'mcpjungle list tools --view favorites'.\n" +
+		"MCP sessions can list your favorites first, see the README.\n" +
+		"\neg- mcpjungle star github__search_code",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "33",
+	},
+	RunE: runStar,
+}
+
+var unstarCmd = &cobra.Command{
+	Use:   "unstar [tool name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Remove a tool from your favorites (Production mode)",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "34",
+	},
+	RunE: runUnstar,
+}
+
+func init() {
+	rootCmd.AddCommand(starCmd)
+	rootCmd.AddCommand(unstarCmd)
+}
+
+func runStar(cmd *cobra.Command, args []string) error {
+	if err := apiClient.StarTool(args[0]); err != nil {
+		return fmt.Errorf("failed to star tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Tool %s added to your favorites\n", args[0])
+	return nil
+}
+
+func runUnstar(cmd *cobra.Command, args []string) error {
+	if err := apiClient.UnstarTool(args[0]); err != nil {
+		return fmt.Errorf("failed to unstar tool %s: %w", args[0], err)
+	}
+	cmd.Printf("Tool %s removed from your favorites\n", args[0])
+	return nil
+}
@@ -1,22 +1,58 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"net/netip"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 
 	"github.com/mcpjungle/mcpjungle/internal/api"
 	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/license"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/migrations"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
+	"github.com/mcpjungle/mcpjungle/internal/service/authorizer"
+	"github.com/mcpjungle/mcpjungle/internal/service/catalog"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/retention"
+	"github.com/mcpjungle/mcpjungle/internal/service/secret"
+	"github.com/mcpjungle/mcpjungle/internal/service/telemetry"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
+	"github.com/mcpjungle/mcpjungle/internal/support"
+	"github.com/mcpjungle/mcpjungle/internal/toolbox"
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+	"github.com/mcpjungle/mcpjungle/internal/wasm/engine"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/translation"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 const (
@@ -24,13 +60,219 @@ const (
 	BindPortDefault = "8080"
 
 	DBUrlEnvVar = "DATABASE_URL"
+	// DBReadReplicaUrlEnvVar is the DSN of a read replica of the PostgreSQL database, which serves
+	// the listings & usage reports
+	DBReadReplicaUrlEnvVar = "DATABASE_READ_REPLICA_URL"
+
+	// DBMaxOpenConnsEnvVar, DBMaxIdleConnsEnvVar, DBConnMaxLifetimeEnvVar and DBConnMaxIdleTimeEnvVar
+	// tune the pool of connections to the database. Unset values keep the defaults of database/sql.
+	DBMaxOpenConnsEnvVar    = "DB_MAX_OPEN_CONNS"
+	DBMaxIdleConnsEnvVar    = "DB_MAX_IDLE_CONNS"
+	DBConnMaxLifetimeEnvVar = "DB_CONN_MAX_LIFETIME"
+	DBConnMaxIdleTimeEnvVar = "DB_CONN_MAX_IDLE_TIME"
+	// DBSlowQueryThresholdEnvVar is a duration (eg- "200ms"), queries taking longer are logged
+	DBSlowQueryThresholdEnvVar = "DB_SLOW_QUERY_THRESHOLD"
 
 	ServerModeEnvVar = "SERVER_MODE"
+
+	// TrustedProxiesEnvVar contains a comma-separated list of CIDRs of reverse proxies in front of mcpjungle
+	TrustedProxiesEnvVar = "TRUSTED_PROXIES"
+
+	// SessionIdleTimeoutEnvVar and SessionMaxLifetimeEnvVar limit the lifetime of MCP proxy sessions.
+	// Their values are durations (eg- "30m"), "0" disables the limit.
+	SessionIdleTimeoutEnvVar  = "SESSION_IDLE_TIMEOUT"
+	SessionIdleTimeoutDefault = time.Hour
+	SessionMaxLifetimeEnvVar  = "SESSION_MAX_LIFETIME"
+
+	// WarmupParallelismEnvVar and WarmupTimeoutEnvVar control how MCP servers marked keep_warm are
+	// connected to at startup. The server reports itself ready on /ready once warm-up has completed.
+	WarmupParallelismEnvVar  = "WARMUP_PARALLELISM"
+	WarmupParallelismDefault = 4
+	WarmupTimeoutEnvVar      = "WARMUP_TIMEOUT"
+	WarmupTimeoutDefault     = 30 * time.Second
+
+	// HeartbeatIntervalEnvVar is how often the connections to the MCP servers marked keep_warm are pinged,
+	// dead connections are replaced. "0" turns the heartbeat off.
+	HeartbeatIntervalEnvVar  = "HEARTBEAT_INTERVAL"
+	HeartbeatIntervalDefault = 30 * time.Second
+
+	// DeadlineOverheadEnvVar is the time reserved for the gateway out of the timeout that the caller of a tool
+	// sets, the upstream MCP server is given the rest
+	DeadlineOverheadEnvVar  = "DEADLINE_OVERHEAD"
+	DeadlineOverheadDefault = 50 * time.Millisecond
+
+	// StdioPidFileEnvVar is the file that the PIDs of the stdio MCP server processes are recorded in,
+	// to terminate the processes orphaned by a crash on the next start (Linux only).
+	// Its directory must be private to the user that mcpjungle runs as, it is created with mode 0700 if needed.
+	// Defaults to mcpjungle/mcpjungle-<port>.pids in the user's cache directory (eg- ~/.cache on Linux).
+	StdioPidFileEnvVar = "STDIO_PIDFILE"
+
+	// DeregisterDrainTimeoutEnvVar is how long deregistering an MCP server waits for the tool calls in flight
+	// to it to complete before tearing it down. New calls are rejected while waiting.
+	DeregisterDrainTimeoutEnvVar = "DEREGISTER_DRAIN_TIMEOUT"
+
+	// RegionNetworksEnvVar maps the networks that callers connect from to the regions they are in, eg-
+	// "10.1.0.0/16=eu-west,10.2.0.0/16=us-east". Calls to the tools of MCP servers with replicas are routed to the
+	// replica closest to the caller's region. The region attribute of an MCP client takes precedence.
+	RegionNetworksEnvVar = "REGION_NETWORKS"
+
+	// SchemaDriftCheckIntervalEnvVar is how often the input schemas of the registered tools are compared with the ones
+	// served by their MCP servers, "0" turns the check off.
+	// If SchemaDriftAutoDisableEnvVar is "true", tools whose schema changed incompatibly are disabled.
+	SchemaDriftCheckIntervalEnvVar  = "SCHEMA_DRIFT_CHECK_INTERVAL"
+	SchemaDriftCheckIntervalDefault = time.Hour
+	SchemaDriftAutoDisableEnvVar    = "SCHEMA_DRIFT_AUTO_DISABLE"
+
+	// FeaturesEnvVar contains a comma-separated list of experimental features to turn on for this instance,
+	// features prefixed with "-" are turned off (eg- "tool_groups,-hedging").
+	// It takes precedence over the features configured via the API.
+	FeaturesEnvVar = "FEATURES"
+
+	// SLOEvalIntervalEnvVar controls how often the compliance of SLOs is recomputed for the
+	// mcpjungle_slo_compliant metric, "0" disables the metric.
+	SLOEvalIntervalEnvVar  = "SLO_EVAL_INTERVAL"
+	SLOEvalIntervalDefault = time.Minute
+
+	// FilesystemRootsEnvVar contains a comma-separated list of directories.
+	// If set, mcpjungle provides the built-in "filesystem" MCP server with read-only access to them.
+	FilesystemRootsEnvVar = "FILESYSTEM_ROOTS"
+
+	// filesystemServerName is the name of the built-in filesystem MCP server
+	filesystemServerName = "filesystem"
+
+	// ToolLintEnforceEnvVar contains a comma-separated list of tool lint rules (eg- "missing_description").
+	// Tools failing any of them are not exposed by the MCP proxy.
+	ToolLintEnforceEnvVar = "TOOL_LINT_ENFORCE"
+
+	// ResultSigningKeyFileEnvVar is the path to a PEM-encoded Ed25519 private key (PKCS #8).
+	// If set, mcpjungle signs the results of all tool calls with it.
+	ResultSigningKeyFileEnvVar = "RESULT_SIGNING_KEY_FILE"
+
+	// SecretsEncryptionKeyEnvVar is the base64 encoding of 32 random bytes (eg- `openssl rand -base64 32`).
+	// If set, the values of the secrets stored in the registry are encrypted with it.
+	SecretsEncryptionKeyEnvVar = "SECRETS_ENCRYPTION_KEY"
+
+	// LicenseFileEnvVar is the path to an mcpjungle enterprise license, which unlocks the enterprise features
+	// in the release builds that check licenses
+	LicenseFileEnvVar = "LICENSE_FILE"
+
+	// AuditSinksEnvVar contains a comma-separated list of URLs of the sinks that receive the audit trail,
+	// eg- "file:///var/log/mcpjungle/audit.log,syslog:,https://splunk:8088/services/collector?format=splunk".
+	AuditSinksEnvVar = "AUDIT_SINKS"
+	// AuditHTTPAuthorizationEnvVar is sent as the Authorization header to HTTP audit sinks
+	AuditHTTPAuthorizationEnvVar = "AUDIT_HTTP_AUTHORIZATION"
+	// AuditBufferSizeEnvVar is the number of audit events buffered per sink while they're waiting to be delivered
+	AuditBufferSizeEnvVar = "AUDIT_BUFFER_SIZE"
+
+	// InvocationRetentionEnvVar and NotificationRetentionEnvVar are the age (eg- "90d") beyond which
+	// tool invocation records and read notifications are deleted. The *MaxRowsEnvVar variables limit
+	// the number of rows kept instead, on top of the invocations of the current month which are always kept.
+	// The history is pruned every PruneInterval.
+	InvocationRetentionEnvVar          = "INVOCATION_RETENTION"
+	InvocationRetentionMaxRowsEnvVar   = "INVOCATION_RETENTION_MAX_ROWS"
+	NotificationRetentionEnvVar        = "NOTIFICATION_RETENTION"
+	NotificationRetentionMaxRowsEnvVar = "NOTIFICATION_RETENTION_MAX_ROWS"
+	PruneIntervalEnvVar                = "PRUNE_INTERVAL"
+	PruneIntervalDefault               = time.Hour
+
+	// WasmPluginsEnvVar contains a comma-separated list of paths to WebAssembly plugins that run in the
+	// pipeline of every tool call, in the given order. WasmPluginTimeoutEnvVar limits the time each hook may take.
+	WasmPluginsEnvVar       = "WASM_PLUGINS"
+	WasmPluginTimeoutEnvVar = "WASM_PLUGIN_TIMEOUT"
+
+	// AuthorizerURLEnvVar is the URL of an external authorizer. If set, every tool call is POSTed to it
+	// for a decision. The other AUTHORIZER_* variables configure the requests and the caching of decisions,
+	// AuthorizerFailOpenEnvVar ("true") allows calls when the authorizer cannot be reached.
+	AuthorizerURLEnvVar           = "AUTHORIZER_URL"
+	AuthorizerAuthorizationEnvVar = "AUTHORIZER_AUTHORIZATION"
+	AuthorizerTimeoutEnvVar       = "AUTHORIZER_TIMEOUT"
+	AuthorizerCacheTTLEnvVar      = "AUTHORIZER_CACHE_TTL"
+	AuthorizerFailOpenEnvVar      = "AUTHORIZER_FAIL_OPEN"
+
+	// TranslationProviderEnvVar enables the translation of tool results into the language of MCP clients
+	// that have one, using the named provider (eg- "libretranslate" or "deepl").
+	// TranslationURLEnvVar & TranslationAPIKeyEnvVar configure the provider's API.
+	TranslationProviderEnvVar = "TRANSLATION_PROVIDER"
+	TranslationURLEnvVar      = "TRANSLATION_URL"
+	TranslationAPIKeyEnvVar   = "TRANSLATION_API_KEY"
+
+	// ReadOnlyEnvVar forces this instance into read-only mode if set to "true"
+	ReadOnlyEnvVar = "READ_ONLY"
+
+	// MaxConcurrentToolCallsEnvVar & MaxConcurrentAPIRequestsEnvVar limit the number of requests served at a time
+	// by the MCP proxies & tool invoke APIs, and by the rest of the API (including admin operations). Unlimited by default.
+	MaxConcurrentToolCallsEnvVar   = "MAX_CONCURRENT_TOOL_CALLS"
+	MaxConcurrentAPIRequestsEnvVar = "MAX_CONCURRENT_API_REQUESTS"
+
+	// ToolCallQueueTimeoutEnvVar is how long tool calls beyond MaxConcurrentToolCallsEnvVar wait for a slot,
+	// served by weighted fair queuing across callers, before being rejected. They're rejected right away by default.
+	// ToolCallWeightsEnvVar gives callers (MCP clients or users) a larger or smaller share of the slots while queuing,
+	// eg- "cursor=3,batch-agent=0.5", or "client:cursor=3,user:alice=2" to tell apart a client & a user with the same name.
+	// Callers that aren't listed have a weight of 1.
+	ToolCallQueueTimeoutEnvVar = "TOOL_CALL_QUEUE_TIMEOUT"
+	ToolCallWeightsEnvVar      = "TOOL_CALL_WEIGHTS"
+
+	// ToolCallRateLimitEnvVar & ToolCallDailyQuotaEnvVar limit the number of tool calls of every caller (MCP client or user)
+	// per minute and per day (UTC). Unlimited by default.
+	// The counters are kept in memory, or in the Redis server at RateLimitRedisURLEnvVar (redis[s]://...) to share
+	// the limits between the replicas of mcpjungle.
+	ToolCallRateLimitEnvVar  = "TOOL_CALL_RATE_LIMIT"
+	ToolCallDailyQuotaEnvVar = "TOOL_CALL_DAILY_QUOTA"
+	RateLimitRedisURLEnvVar  = "RATE_LIMIT_REDIS_URL"
+
+	// ProfilingEnvVar mounts the pprof endpoints on /debug/pprof (for admins only) if set to "true"
+	ProfilingEnvVar = "PROFILING"
+
+	// MetricsRemoteWriteURLEnvVar is the URL of a Prometheus remote-write endpoint that the metrics are pushed to,
+	// for deployments that can't be scraped. The metrics are pushed every MetricsRemoteWriteIntervalEnvVar,
+	// in requests of at most MetricsRemoteWriteBatchSizeEnvVar time series, authenticated with the bearer token
+	// or the username & password if set.
+	MetricsRemoteWriteURLEnvVar         = "METRICS_REMOTE_WRITE_URL"
+	MetricsRemoteWriteIntervalEnvVar    = "METRICS_REMOTE_WRITE_INTERVAL"
+	MetricsRemoteWriteBatchSizeEnvVar   = "METRICS_REMOTE_WRITE_BATCH_SIZE"
+	MetricsRemoteWriteBearerTokenEnvVar = "METRICS_REMOTE_WRITE_BEARER_TOKEN"
+	MetricsRemoteWriteUsernameEnvVar    = "METRICS_REMOTE_WRITE_USERNAME"
+	MetricsRemoteWritePasswordEnvVar    = "METRICS_REMOTE_WRITE_PASSWORD"
+
+	// StatsdAddrEnvVar is the address of a DogStatsD agent (host:port or unix:///path/to/socket) that the metrics
+	// are sent to every StatsdIntervalEnvVar, tagged with the comma-separated tags of StatsdTagsEnvVar (eg- "env:prod").
+	StatsdAddrEnvVar     = "STATSD_ADDR"
+	StatsdIntervalEnvVar = "STATSD_INTERVAL"
+	StatsdTagsEnvVar     = "STATSD_TAGS"
+
+	// SpiffeSVIDCertFileEnvVar, SpiffeSVIDKeyFileEnvVar & SpiffeBundleFileEnvVar are the PEM files of the X.509 SVID of
+	// mcpjungle, its private key and the trust bundle, as written by the SPIRE agent's spiffe-helper.
+	// If set, the server serves HTTPS with the SVID, MCP clients can authenticate with their SVIDs and mcpjungle connects
+	// with mTLS to the MCP servers registered with a SPIFFE ID. The files are re-read every SpiffeRefreshIntervalEnvVar.
+	SpiffeSVIDCertFileEnvVar    = "SPIFFE_SVID_CERT_FILE"
+	SpiffeSVIDKeyFileEnvVar     = "SPIFFE_SVID_KEY_FILE"
+	SpiffeBundleFileEnvVar      = "SPIFFE_BUNDLE_FILE"
+	SpiffeRefreshIntervalEnvVar = "SPIFFE_REFRESH_INTERVAL"
+
+	// TelemetryEnvVar opts into sending an anonymous usage report (version, counts of servers & tools,
+	// features in use) to the maintainers once a day if set to "on". It is off by default.
+	// TelemetryURLEnvVar overrides the endpoint that receives the reports.
+	TelemetryEnvVar    = "TELEMETRY"
+	TelemetryURLEnvVar = "TELEMETRY_URL"
+
+	// CatalogPublishURLEnvVar is where a snapshot of the enabled tools is published every CatalogPublishIntervalEnvVar,
+	// along with its signature: a local path, s3://<bucket>/<key> or gs://<bucket>/<object>. Snapshots are signed with
+	// the key of ResultSigningKeyFileEnvVar, which is required. Uploads are authenticated with the standard
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY & AWS_SESSION_TOKEN variables (an HMAC key for GCS) and AWS_REGION.
+	// CatalogPublishEndpointEnvVar overrides the endpoint of the object store (eg- MinIO) and
+	// CatalogPublishRegistryURLEnvVar is the public URL of this gateway recorded in the snapshots.
+	CatalogPublishURLEnvVar         = "CATALOG_PUBLISH_URL"
+	CatalogPublishIntervalEnvVar    = "CATALOG_PUBLISH_INTERVAL"
+	CatalogPublishEndpointEnvVar    = "CATALOG_PUBLISH_ENDPOINT"
+	CatalogPublishRegistryURLEnvVar = "CATALOG_PUBLISH_REGISTRY_URL"
 )
 
 var (
 	startServerCmdBindPort    string
 	startServerCmdProdEnabled bool
+	startServerCmdReadOnly    bool
+	startServerCmdEphemeral   bool
+	startServerCmdPreload     string
 )
 
 var startServerCmd = &cobra.Command{
@@ -63,18 +305,91 @@ func init() {
 		),
 	)
 
+	startServerCmd.Flags().BoolVar(
+		&startServerCmdReadOnly,
+		"read-only",
+		false,
+		fmt.Sprintf(
+			"Start the server in read-only mode, which can't be turned off via the API."+
+				" Alternatively, set the %s environment variable to 'true'",
+			ReadOnlyEnvVar,
+		),
+	)
+
+	startServerCmd.Flags().BoolVar(
+		&startServerCmdEphemeral,
+		"ephemeral",
+		false,
+		"Run the server entirely in memory in Development mode, all data is lost when it exits."+
+			" Useful for demos and CI pipelines",
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdPreload,
+		"preload",
+		"",
+		"Path to a JSON file with the configuration of an MCP server (or an array of them) to register at startup."+
+			" Only supported with --ephemeral",
+	)
+	startServerCmd.MarkFlagsMutuallyExclusive("ephemeral", "prod")
+
 	rootCmd.AddCommand(startServerCmd)
 }
 
+// configEnvVars are the environment variables that configure the server, reported in support bundles
+var configEnvVars = []string{
+	BindPortEnvVar, DBUrlEnvVar, DBReadReplicaUrlEnvVar, DBMaxOpenConnsEnvVar, DBMaxIdleConnsEnvVar,
+	DBConnMaxLifetimeEnvVar, DBConnMaxIdleTimeEnvVar, DBSlowQueryThresholdEnvVar, ServerModeEnvVar, TrustedProxiesEnvVar,
+	SessionIdleTimeoutEnvVar, SessionMaxLifetimeEnvVar, WarmupParallelismEnvVar, WarmupTimeoutEnvVar,
+	HeartbeatIntervalEnvVar, DeadlineOverheadEnvVar, DeregisterDrainTimeoutEnvVar, RegionNetworksEnvVar, StdioPidFileEnvVar, SchemaDriftCheckIntervalEnvVar, SchemaDriftAutoDisableEnvVar,
+	FeaturesEnvVar, SLOEvalIntervalEnvVar, FilesystemRootsEnvVar, ToolLintEnforceEnvVar, ResultSigningKeyFileEnvVar,
+	SecretsEncryptionKeyEnvVar, LicenseFileEnvVar, AuditSinksEnvVar, AuditHTTPAuthorizationEnvVar, AuditBufferSizeEnvVar, InvocationRetentionEnvVar,
+	InvocationRetentionMaxRowsEnvVar, NotificationRetentionEnvVar, NotificationRetentionMaxRowsEnvVar,
+	PruneIntervalEnvVar, WasmPluginsEnvVar, WasmPluginTimeoutEnvVar, AuthorizerURLEnvVar,
+	AuthorizerAuthorizationEnvVar, AuthorizerTimeoutEnvVar, AuthorizerCacheTTLEnvVar, AuthorizerFailOpenEnvVar,
+	TranslationProviderEnvVar, TranslationURLEnvVar, TranslationAPIKeyEnvVar, ReadOnlyEnvVar, MaxConcurrentToolCallsEnvVar,
+	MaxConcurrentAPIRequestsEnvVar, ToolCallQueueTimeoutEnvVar, ToolCallWeightsEnvVar,
+	ToolCallRateLimitEnvVar, ToolCallDailyQuotaEnvVar, RateLimitRedisURLEnvVar, ProfilingEnvVar, MetricsRemoteWriteURLEnvVar, MetricsRemoteWriteIntervalEnvVar,
+	MetricsRemoteWriteBatchSizeEnvVar, MetricsRemoteWriteBearerTokenEnvVar, MetricsRemoteWriteUsernameEnvVar,
+	MetricsRemoteWritePasswordEnvVar, StatsdAddrEnvVar, StatsdIntervalEnvVar, StatsdTagsEnvVar, SpiffeSVIDCertFileEnvVar,
+	SpiffeSVIDKeyFileEnvVar, SpiffeBundleFileEnvVar, SpiffeRefreshIntervalEnvVar, TelemetryEnvVar, TelemetryURLEnvVar,
+	CatalogPublishURLEnvVar, CatalogPublishIntervalEnvVar, CatalogPublishEndpointEnvVar, CatalogPublishRegistryURLEnvVar,
+}
+
 func runStartServer(cmd *cobra.Command, args []string) error {
 	_ = godotenv.Load()
 
+	// keep the recent logs for support bundles
+	log.SetOutput(io.MultiWriter(os.Stderr, support.Logs))
+
+	if startServerCmdPreload != "" && !startServerCmdEphemeral {
+		return fmt.Errorf("--preload is only supported with --ephemeral")
+	}
+	var preload []types.RegisterServerInput
+	if startServerCmdPreload != "" {
+		var err error
+		if preload, err = readPreloadConfig(startServerCmdPreload); err != nil {
+			return err
+		}
+	}
+
 	// connect to the DB and run migrations
-	dsn := os.Getenv(DBUrlEnvVar)
-	dbConn, err := db.NewDBConnection(dsn)
+	var dbConn *gorm.DB
+	var err error
+	if startServerCmdEphemeral {
+		dbConn, err = db.NewInMemoryDBConnection()
+	} else {
+		var dbOpts db.Options
+		if dbOpts, err = dbOptionsFromEnv(); err != nil {
+			return err
+		}
+		dbConn, err = db.NewDBConnection(os.Getenv(DBUrlEnvVar), dbOpts)
+	}
 	if err != nil {
 		return err
 	}
+	if sqlDB, err := dbConn.DB(); err == nil {
+		metrics.RegisterDBStats(sqlDB)
+	}
 	// Migrations should ideally be decoupled from both the server and the startup phase
 	// (should be run as a separate command).
 	// However, for the user's convenience, we run them as part of startup command for now.
@@ -92,30 +407,393 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 	}
 
 	// create the MCP proxy server
+	proxyHooks := &server.Hooks{}
 	mcpProxyServer := server.NewMCPServer(
 		"MCPJungle Proxy MCP Server",
 		"0.0.1",
 		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+		server.WithHooks(proxyHooks),
 	)
 
-	mcpService, err := mcp.NewMCPService(dbConn, mcpProxyServer)
+	// expire abandoned MCP proxy sessions
+	idleTimeout, err := durationFromEnv(SessionIdleTimeoutEnvVar, SessionIdleTimeoutDefault)
+	if err != nil {
+		return err
+	}
+	maxLifetime, err := durationFromEnv(SessionMaxLifetimeEnvVar, 0)
+	if err != nil {
+		return err
+	}
+	sessionManager := mcp.NewSessionManager(idleTimeout, maxLifetime, mcpProxyServer)
+	sessionManager.RegisterHooks(proxyHooks)
+	go sessionManager.Run(context.Background())
+
+	webhookService := webhook.NewWebhookService(dbConn)
+
+	notificationService := notification.NewNotificationService(dbConn)
+
+	usageService := usage.NewUsageService(dbConn, webhookService, notificationService)
+	usageService.RegisterHooks(proxyHooks)
+	sloEvalInterval, err := durationFromEnv(SLOEvalIntervalEnvVar, SLOEvalIntervalDefault)
+	if err != nil {
+		return err
+	}
+	if sloEvalInterval > 0 {
+		go usageService.RunSLOMetrics(context.Background(), sloEvalInterval)
+	}
+
+	policyService := policy.NewPolicyService(dbConn)
+
+	retentionPolicies := make(map[string]types.RetentionPolicy)
+	for table, envVars := range map[string][2]string{
+		types.RetentionTableInvocations:   {InvocationRetentionEnvVar, InvocationRetentionMaxRowsEnvVar},
+		types.RetentionTableNotifications: {NotificationRetentionEnvVar, NotificationRetentionMaxRowsEnvVar},
+	} {
+		p := types.RetentionPolicy{MaxAge: os.Getenv(envVars[0])}
+		if v := os.Getenv(envVars[1]); v != "" {
+			if p.MaxRows, err = strconv.Atoi(v); err != nil || p.MaxRows <= 0 {
+				return fmt.Errorf("invalid value for %s environment variable: '%s', must be a positive number", envVars[1], v)
+			}
+		}
+		if p.MaxAge != "" || p.MaxRows > 0 {
+			retentionPolicies[table] = p
+		}
+	}
+	retentionService, err := retention.NewRetentionService(dbConn, retentionPolicies)
+	if err != nil {
+		return fmt.Errorf("invalid history retention: %v", err)
+	}
+	pruneInterval, err := durationFromEnv(PruneIntervalEnvVar, PruneIntervalDefault)
+	if err != nil {
+		return err
+	}
+	if len(retentionPolicies) > 0 && pruneInterval > 0 {
+		go retentionService.Run(context.Background(), pruneInterval)
+	}
+
+	featureService, err := feature.NewFeatureService(dbConn, os.Getenv(FeaturesEnvVar))
+	if err != nil {
+		return fmt.Errorf("invalid value for %s environment variable: %v", FeaturesEnvVar, err)
+	}
+
+	entitlements, err := license.Load(os.Getenv(LicenseFileEnvVar))
+	if err != nil {
+		return fmt.Errorf("invalid value for %s environment variable: %v", LicenseFileEnvVar, err)
+	}
+	if l := entitlements.License(); l != nil {
+		if entitlements.Expired() {
+			log.Printf("[WARN] the enterprise license of %s expired on %s", l.Licensee, l.ExpiresAt.Format(time.DateOnly))
+		} else {
+			log.Printf("[INFO] enterprise license of %s, features: %s", l.Licensee, strings.Join(l.Features, ", "))
+		}
+	}
+
+	auditSinks, err := audit.ParseSinks(os.Getenv(AuditSinksEnvVar), os.Getenv(AuditHTTPAuthorizationEnvVar))
+	if err != nil {
+		return fmt.Errorf("invalid value for %s environment variable: %v", AuditSinksEnvVar, err)
+	}
+	if audit.Exports(auditSinks) {
+		if err := entitlements.Require(license.AuditExport); err != nil {
+			return fmt.Errorf("cannot send the audit trail to HTTP sinks: %v (file and syslog sinks are free)", err)
+		}
+	}
+	var auditService *audit.AuditService
+	if len(auditSinks) > 0 {
+		bufferSize := 0
+		if v := os.Getenv(AuditBufferSizeEnvVar); v != "" {
+			bufferSize, err = strconv.Atoi(v)
+			if err != nil || bufferSize <= 0 {
+				return fmt.Errorf("invalid value for %s environment variable: '%s', must be a positive number", AuditBufferSizeEnvVar, v)
+			}
+		}
+		auditService = audit.NewAuditService(auditSinks, bufferSize)
+		defer auditService.Close()
+	}
+
+	mcpService, err := mcp.NewMCPService(
+		dbConn, mcpProxyServer, proxyHooks, usageService, policyService, featureService, notificationService,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP service: %v", err)
 	}
+	mcpService.SetAuditService(auditService)
+	retentionService.SetAuditService(auditService)
+	mcpService.SetSessionManager(sessionManager)
+	// sessions of the MCP proxy can select a view of a user on the tools, eg- their favorites
+	server.WithToolFilter(mcpService.ArrangeProxyTools)(mcpProxyServer)
+
+	secretService, err := secret.NewSecretService(dbConn, os.Getenv(SecretsEncryptionKeyEnvVar))
+	if err != nil {
+		return fmt.Errorf("invalid value for %s environment variable: %v", SecretsEncryptionKeyEnvVar, err)
+	}
+	mcpService.SetSecretService(secretService)
+
+	if v := os.Getenv(AuthorizerURLEnvVar); v != "" {
+		opts := authorizer.Options{
+			URL:           v,
+			Authorization: os.Getenv(AuthorizerAuthorizationEnvVar),
+			FailOpen:      strings.EqualFold(os.Getenv(AuthorizerFailOpenEnvVar), "true"),
+		}
+		if opts.Timeout, err = durationFromEnv(AuthorizerTimeoutEnvVar, authorizer.DefaultTimeout); err != nil {
+			return err
+		}
+		if opts.CacheTTL, err = durationFromEnv(AuthorizerCacheTTLEnvVar, 0); err != nil {
+			return err
+		}
+		a, err := authorizer.NewAuthorizer(opts, webhookService)
+		if err != nil {
+			return err
+		}
+		mcpService.SetAuthorizer(a)
+	}
+
+	if v := os.Getenv(WasmPluginsEnvVar); v != "" {
+		timeout, err := durationFromEnv(WasmPluginTimeoutEnvVar, wasm.DefaultTimeout)
+		if err != nil {
+			return err
+		}
+		var paths []string
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				paths = append(paths, p)
+			}
+		}
+		wasmPlugins, err := engine.Load(context.Background(), paths, timeout)
+		if err != nil {
+			return err
+		}
+		defer wasmPlugins.Close(context.Background())
+		mcpService.SetWasmPlugins(wasmPlugins)
+		fmt.Printf("WASM plugins: %s\n", strings.Join(wasmPlugins.Names(), ", "))
+	}
+
+	if name := os.Getenv(TranslationProviderEnvVar); name != "" {
+		translator, err := translation.New(name, translation.Config{
+			URL:    os.Getenv(TranslationURLEnvVar),
+			APIKey: os.Getenv(TranslationAPIKeyEnvVar),
+		})
+		if err != nil {
+			return err
+		}
+		mcpService.SetTranslator(translator)
+		fmt.Printf("Tool results are translated for MCP clients with a language, using %s\n", name)
+	}
+
+	// let the plugins compiled into this binary hook into the gateway's events
+	if plugins := events.Plugins(); len(plugins) > 0 {
+		bus := events.NewBus()
+		if err := events.InitPlugins(bus); err != nil {
+			return err
+		}
+		mcpService.SetEventBus(bus)
+		names := make([]string, len(plugins))
+		for i, p := range plugins {
+			names[i] = p.Name()
+		}
+		fmt.Printf("Plugins: %s\n", strings.Join(names, ", "))
+	}
+
+	// pre-connect to the MCP servers marked keep_warm in the background
+	warmupParallelism := WarmupParallelismDefault
+	if v := os.Getenv(WarmupParallelismEnvVar); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value for %s environment variable: '%s', must be a positive number", WarmupParallelismEnvVar, v)
+		}
+		warmupParallelism = n
+	}
+	warmupTimeout, err := durationFromEnv(WarmupTimeoutEnvVar, WarmupTimeoutDefault)
+	if err != nil {
+		return err
+	}
+	// terminate the stdio servers orphaned by a crash before starting new ones, then reconcile the registry
+	// with the MCP servers once they're warmed up
+	report := &mcp.ReconciliationReport{}
+	pidFile := os.Getenv(StdioPidFileEnvVar)
+	if pidFile == "" {
+		// the pidfile must be private, so it is never kept in the shared temporary directory
+		if dir, err := os.UserCacheDir(); err == nil {
+			pidFile = filepath.Join(dir, "mcpjungle", "mcpjungle-"+port+".pids")
+		} else {
+			log.Printf("[WARN] orphaned stdio server processes won't be detected, set %s: %v", StdioPidFileEnvVar, err)
+		}
+	}
+	if pidFile != "" {
+		if report.OrphanedProcesses, err = mcp.TrackStdioProcesses(pidFile); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), warmupTimeout)
+		defer cancel()
+		if err := mcpService.WarmUp(ctx, warmupParallelism); err != nil {
+			log.Printf("[WARN] warm-up of MCP servers did not complete: %v", err)
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), warmupTimeout)
+		defer cancel()
+		mcpService.Reconcile(ctx, report)
+		report.Log()
+	}()
+
+	deadlineOverhead, err := durationFromEnv(DeadlineOverheadEnvVar, DeadlineOverheadDefault)
+	if err != nil {
+		return err
+	}
+	mcpService.SetDeadlineOverhead(deadlineOverhead)
+
+	drainTimeout, err := durationFromEnv(DeregisterDrainTimeoutEnvVar, mcp.DefaultDrainTimeout)
+	if err != nil {
+		return err
+	}
+	mcpService.SetDrainTimeout(drainTimeout)
+
+	regionNetworks, err := parseRegionNetworks(os.Getenv(RegionNetworksEnvVar))
+	if err != nil {
+		return err
+	}
+	mcpService.SetRegionNetworks(regionNetworks)
+
+	// abandon the restarts of crashed stdio servers once the server stops
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	mcpService.SetShutdownContext(shutdownCtx)
+
+	heartbeatInterval, err := durationFromEnv(HeartbeatIntervalEnvVar, HeartbeatIntervalDefault)
+	if err != nil {
+		return err
+	}
+	if heartbeatInterval > 0 {
+		go mcpService.RunHeartbeat(context.Background(), heartbeatInterval)
+	}
+
+	driftInterval, err := durationFromEnv(SchemaDriftCheckIntervalEnvVar, SchemaDriftCheckIntervalDefault)
+	if err != nil {
+		return err
+	}
+	if driftInterval > 0 {
+		autoDisable := strings.EqualFold(os.Getenv(SchemaDriftAutoDisableEnvVar), "true")
+		go mcpService.RunSchemaDriftCheck(context.Background(), driftInterval, autoDisable)
+	}
+
+	if keyFile := os.Getenv(ResultSigningKeyFileEnvVar); keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read result signing key: %v", err)
+		}
+		if err := mcpService.EnableResultSigning(key); err != nil {
+			return fmt.Errorf("invalid result signing key in %s: %v", keyFile, err)
+		}
+	}
+
+	var lintRules []string
+	for _, r := range strings.Split(os.Getenv(ToolLintEnforceEnvVar), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			lintRules = append(lintRules, r)
+		}
+	}
+	if len(lintRules) > 0 {
+		if err := mcpService.EnforceToolLintRules(lintRules); err != nil {
+			return fmt.Errorf("invalid value for %s environment variable: %v", ToolLintEnforceEnvVar, err)
+		}
+	}
+
+	var fsRoots []string
+	for _, r := range strings.Split(os.Getenv(FilesystemRootsEnvVar), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			fsRoots = append(fsRoots, r)
+		}
+	}
+	if len(fsRoots) > 0 {
+		fsServer, err := toolbox.NewFilesystemServer("mcpjungle-filesystem", getVersion(), fsRoots)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s environment variable: %v", FilesystemRootsEnvVar, err)
+		}
+		err = mcpService.RegisterBuiltinServer(
+			context.Background(),
+			filesystemServerName,
+			"Built-in read-only access to "+strings.Join(fsRoots, ", "),
+			fsServer,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to enable the built-in filesystem MCP server: %v", err)
+		}
+	}
+
+	spiffeSource, err := spiffeSourceFromEnv()
+	if err != nil {
+		return err
+	}
+	var tlsConfig *tls.Config
+	if spiffeSource != nil {
+		mcpService.SetSpiffeSource(spiffeSource)
+		tlsConfig = spiffeSource.ServerTLSConfig()
+	}
 
 	mcpClientService := mcp_client.NewMCPClientService(dbConn)
 
 	configService := config.NewServerConfigService(dbConn)
+	if startServerCmdReadOnly || strings.EqualFold(os.Getenv(ReadOnlyEnvVar), "true") {
+		configService.ForceReadOnly()
+	}
 	userService := user.NewUserService(dbConn)
 
+	var trustedProxies []string
+	for _, p := range strings.Split(os.Getenv(TrustedProxiesEnvVar), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			trustedProxies = append(trustedProxies, p)
+		}
+	}
+
 	// create the API server
+	concurrencyLimits := make(map[string]int, 2)
+	for _, envVar := range []string{MaxConcurrentToolCallsEnvVar, MaxConcurrentAPIRequestsEnvVar} {
+		if v := os.Getenv(envVar); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid value for %s environment variable: '%s', must be a non-negative number", envVar, v)
+			}
+			concurrencyLimits[envVar] = n
+		}
+	}
+
+	var toolCallQueue api.FairQueueOptions
+	if toolCallQueue.MaxWait, err = durationFromEnv(ToolCallQueueTimeoutEnvVar, 0); err != nil {
+		return err
+	}
+	if toolCallQueue.Weights, err = parseToolCallWeights(os.Getenv(ToolCallWeightsEnvVar)); err != nil {
+		return err
+	}
+	toolCallRateLimiter, err := toolCallRateLimiterFromEnv()
+	if err != nil {
+		return err
+	}
+
 	opts := &api.ServerOptions{
-		Port:             port,
-		MCPProxyServer:   mcpProxyServer,
-		MCPService:       mcpService,
-		MCPClientService: mcpClientService,
-		ConfigService:    configService,
-		UserService:      userService,
+		Port:                     port,
+		TrustedProxies:           trustedProxies,
+		MCPProxyServer:           mcpProxyServer,
+		SessionManager:           sessionManager,
+		MCPService:               mcpService,
+		MCPClientService:         mcpClientService,
+		ConfigService:            configService,
+		UserService:              userService,
+		UsageService:             usageService,
+		PolicyService:            policyService,
+		WebhookService:           webhookService,
+		FeatureService:           featureService,
+		NotificationService:      notificationService,
+		AuditService:             auditService,
+		RetentionService:         retentionService,
+		SecretService:            secretService,
+		MaxConcurrentToolCalls:   concurrencyLimits[MaxConcurrentToolCallsEnvVar],
+		MaxConcurrentAPIRequests: concurrencyLimits[MaxConcurrentAPIRequestsEnvVar],
+		ToolCallQueue:            toolCallQueue,
+		ToolCallRateLimiter:      toolCallRateLimiter,
+		TLSConfig:                tlsConfig,
+		EnableProfiling:          strings.EqualFold(os.Getenv(ProfilingEnvVar), "true"),
+		Version:                  getVersion(),
+		Config:                   support.EnvConfig(configEnvVars...),
 	}
 	s, err := api.NewServer(opts)
 	if err != nil {
@@ -125,7 +803,7 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 	// determine the server mode
 	desiredMode := model.ModeDev
 	envMode := os.Getenv(ServerModeEnvVar)
-	if envMode != "" {
+	if envMode != "" && !startServerCmdEphemeral {
 		// the value of the environment variable is allowed to be case-insensitive
 		envMode = strings.ToLower(envMode)
 
@@ -178,12 +856,352 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(preload) > 0 {
+		if err := s.RegisterServers(context.Background(), preload); err != nil {
+			return err
+		}
+	}
+
+	var remoteWriter *metrics.RemoteWriter
+	if v := os.Getenv(MetricsRemoteWriteURLEnvVar); v != "" {
+		cfg := metrics.RemoteWriteConfig{
+			URL:         v,
+			BearerToken: os.Getenv(MetricsRemoteWriteBearerTokenEnvVar),
+			Username:    os.Getenv(MetricsRemoteWriteUsernameEnvVar),
+			Password:    os.Getenv(MetricsRemoteWritePasswordEnvVar),
+		}
+		cfg.Interval, err = durationFromEnv(MetricsRemoteWriteIntervalEnvVar, metrics.RemoteWriteDefaultInterval)
+		if err != nil {
+			return err
+		}
+		if v := os.Getenv(MetricsRemoteWriteBatchSizeEnvVar); v != "" {
+			cfg.BatchSize, err = strconv.Atoi(v)
+			if err != nil || cfg.BatchSize <= 0 {
+				return fmt.Errorf(
+					"invalid value for %s environment variable: '%s', must be a positive number",
+					MetricsRemoteWriteBatchSizeEnvVar, v,
+				)
+			}
+		}
+		remoteWriter, err = metrics.NewRemoteWriter(cfg, metrics.Registry)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s environment variable: %v", MetricsRemoteWriteURLEnvVar, err)
+		}
+		go remoteWriter.Run(context.Background())
+	}
+
+	var statsdExporter *metrics.StatsdExporter
+	if v := os.Getenv(StatsdAddrEnvVar); v != "" {
+		cfg := metrics.StatsdConfig{Addr: v, Tags: splitCommaList(os.Getenv(StatsdTagsEnvVar))}
+		cfg.Interval, err = durationFromEnv(StatsdIntervalEnvVar, metrics.StatsdDefaultInterval)
+		if err != nil {
+			return err
+		}
+		statsdExporter, err = metrics.NewStatsdExporter(cfg, metrics.Registry)
+		if err != nil {
+			return fmt.Errorf("invalid DogStatsD configuration: %v", err)
+		}
+		go statsdExporter.Run(context.Background())
+	}
+
+	var telemetryService *telemetry.TelemetryService
+	if telemetry.Enabled(os.Getenv(TelemetryEnvVar)) {
+		telemetryService = telemetry.NewTelemetryService(
+			dbConn, featureService, os.Getenv(TelemetryURLEnvVar), getVersion(), usedSubsystems(),
+		)
+		go telemetryService.Run(context.Background(), telemetry.DefaultInterval)
+	}
+
+	var catalogPublisher *catalog.Publisher
+	if v := os.Getenv(CatalogPublishURLEnvVar); v != "" {
+		interval, err := durationFromEnv(CatalogPublishIntervalEnvVar, catalog.DefaultInterval)
+		if err != nil {
+			return err
+		}
+		dest, err := catalog.NewDestination(v, catalog.ObjectStoreCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Region:          os.Getenv("AWS_REGION"),
+			Endpoint:        os.Getenv(CatalogPublishEndpointEnvVar),
+		})
+		if err != nil {
+			return fmt.Errorf("invalid value for %s environment variable: %v", CatalogPublishURLEnvVar, err)
+		}
+		catalogPublisher, err = catalog.NewPublisher(mcpService, dest, os.Getenv(CatalogPublishRegistryURLEnvVar))
+		if err != nil {
+			return fmt.Errorf("%v, set %s", err, ResultSigningKeyFileEnvVar)
+		}
+		if interval == 0 {
+			interval = catalog.DefaultInterval
+		}
+		go catalogPublisher.Run(context.Background(), interval)
+	}
+
 	// Display startup banner when the server is started
 	fmt.Print(asciiArt)
-	fmt.Printf("MCPJungle HTTP server listening on :%s\n\n", port)
-	if err := s.Start(); err != nil {
+	printStartupInfo(desiredMode, configService, featureService)
+	if remoteWriter != nil {
+		fmt.Printf("Metrics: pushed to %s\n", remoteWriter.URL())
+	}
+	if statsdExporter != nil {
+		fmt.Printf("Metrics: sent to DogStatsD agent %s\n", statsdExporter.Addr())
+	}
+	if telemetryService != nil {
+		fmt.Printf(
+			"Telemetry: on, an anonymous usage report is sent to %s once a day (set %s=off to disable)\n",
+			telemetryService.URL(), TelemetryEnvVar,
+		)
+	}
+	if catalogPublisher != nil {
+		fmt.Printf("Catalog: a signed snapshot is published to %s\n", catalogPublisher.Destination())
+	}
+	if spiffeSource != nil {
+		fmt.Printf("SPIFFE ID: %s, MCP clients can authenticate with their SVIDs\n", spiffeSource.ID())
+		fmt.Printf("MCPJungle HTTPS server listening on :%s\n\n", port)
+	} else {
+		fmt.Printf("MCPJungle HTTP server listening on :%s\n\n", port)
+	}
+
+	// shut down gracefully on SIGINT/SIGTERM, so that in-flight requests complete and the
+	// deferred cleanups (eg- delivery of the buffered audit events) run before the process exits
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := s.Start(ctx); err != nil {
 		return fmt.Errorf("failed to run the server: %v\n", err)
 	}
+	log.Println("[INFO] server stopped, flushing buffered events")
 
 	return nil
 }
+
+// printStartupInfo prints the version of the server and the configuration it runs with as part of the startup banner.
+func printStartupInfo(mode model.ServerMode, configService *config.ServerConfigService, featureService *feature.FeatureService) {
+	fmt.Printf("Version: %s\n", getVersion())
+	fmt.Printf("Mode: %s\n", mode)
+	if startServerCmdEphemeral {
+		fmt.Println("Ephemeral: yes, all data is lost when the server exits")
+	}
+	if readOnly, _, err := configService.ReadOnly(); err == nil && readOnly {
+		fmt.Println("Read-only: yes")
+	}
+
+	var enabled []string
+	for _, f := range featureService.ListFeatures() {
+		if f.Enabled {
+			enabled = append(enabled, f.Name)
+		}
+	}
+	if len(enabled) == 0 {
+		fmt.Println("Features: none")
+	} else {
+		fmt.Printf("Features: %s\n", strings.Join(enabled, ", "))
+	}
+}
+
+// usedSubsystems returns the names of the optional subsystems configured via the environment, for usage reports.
+func usedSubsystems() []string {
+	var uses []string
+	for name, envVar := range map[string]string{
+		"audit":           AuditSinksEnvVar,
+		"authorizer":      AuthorizerURLEnvVar,
+		"wasm_plugins":    WasmPluginsEnvVar,
+		"translation":     TranslationProviderEnvVar,
+		"result_signing":  ResultSigningKeyFileEnvVar,
+		"filesystem":      FilesystemRootsEnvVar,
+		"tool_lint":       ToolLintEnforceEnvVar,
+		"retention":       InvocationRetentionEnvVar,
+		"remote_write":    MetricsRemoteWriteURLEnvVar,
+		"statsd":          StatsdAddrEnvVar,
+		"catalog_publish": CatalogPublishURLEnvVar,
+	} {
+		if os.Getenv(envVar) != "" {
+			uses = append(uses, name)
+		}
+	}
+	if len(events.Plugins()) > 0 {
+		uses = append(uses, "plugins")
+	}
+	if startServerCmdEphemeral {
+		uses = append(uses, "ephemeral")
+	}
+	slices.Sort(uses)
+	return uses
+}
+
+// durationFromEnv reads a duration (eg- "30m") from an environment variable.
+// It returns the default value if the variable is not set.
+func durationFromEnv(envVar string, defaultValue time.Duration) (time.Duration, error) {
+	v := os.Getenv(envVar)
+	if v == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid value for %s environment variable: '%s', must be a duration like 30m", envVar, v)
+	}
+	return d, nil
+}
+
+// parseToolCallWeights parses the weights of callers for the fair queuing of tool calls, eg- "cursor=3,batch=0.5".
+func parseToolCallWeights(v string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, entry := range splitCommaList(v) {
+		caller, w, ok := strings.Cut(entry, "=")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(w), 64)
+		if !ok || caller == "" || err != nil || weight <= 0 {
+			return nil, fmt.Errorf(
+				"invalid value for %s environment variable: '%s', must be a list of caller=weight with positive weights",
+				ToolCallWeightsEnvVar, entry,
+			)
+		}
+		weights[strings.TrimSpace(caller)] = weight
+	}
+	return weights, nil
+}
+
+// parseRegionNetworks parses the value of RegionNetworksEnvVar, a comma-separated list of network=region.
+// Networks are CIDRs or single IP addresses.
+func parseRegionNetworks(v string) ([]mcp.RegionNetwork, error) {
+	var networks []mcp.RegionNetwork
+	for _, entry := range splitCommaList(v) {
+		network, region, ok := strings.Cut(entry, "=")
+		network, region = strings.TrimSpace(network), strings.TrimSpace(region)
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			var addr netip.Addr
+			if addr, err = netip.ParseAddr(network); err == nil {
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+		}
+		if !ok || region == "" || err != nil {
+			return nil, fmt.Errorf(
+				"invalid value for %s environment variable: '%s', must be a list of network=region", RegionNetworksEnvVar, entry,
+			)
+		}
+		networks = append(networks, mcp.RegionNetwork{Prefix: prefix.Masked(), Region: region})
+	}
+	return networks, nil
+}
+
+// toolCallRateLimiterFromEnv creates the limiter of the tool calls per caller from the environment variables.
+// It returns nil if no limit is set.
+func toolCallRateLimiterFromEnv() (*ratelimit.Limiter, error) {
+	var limits []ratelimit.Limit
+	for _, l := range []struct {
+		envVar string
+		limit  ratelimit.Limit
+	}{
+		{ToolCallRateLimitEnvVar, ratelimit.Limit{Name: "rate limit", Window: time.Minute}},
+		{ToolCallDailyQuotaEnvVar, ratelimit.Limit{Name: "daily quota", Window: 24 * time.Hour}},
+	} {
+		v := os.Getenv(l.envVar)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid value for %s environment variable: '%s', must be a positive number", l.envVar, v)
+		}
+		l.limit.Calls = n
+		limits = append(limits, l.limit)
+	}
+	redisURL := os.Getenv(RateLimitRedisURLEnvVar)
+	if len(limits) == 0 {
+		if redisURL != "" {
+			log.Printf("[WARN] %s is set but no tool call limit is, it is ignored", RateLimitRedisURLEnvVar)
+		}
+		return nil, nil
+	}
+	if redisURL == "" {
+		return ratelimit.NewLimiter(nil, limits...), nil
+	}
+	store, err := ratelimit.NewRedisStore(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s environment variable: %w", RateLimitRedisURLEnvVar, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := store.Ping(ctx); err != nil {
+		log.Printf("[WARN] rate limit store is unreachable, enforcing the limits per instance until it is up: %v", err)
+	}
+	return ratelimit.NewLimiter(store, limits...), nil
+}
+
+// spiffeSourceFromEnv loads the SPIFFE SVID of mcpjungle from the files in the environment variables and keeps
+// it refreshed in the background. It returns nil if no SVID is configured.
+func spiffeSourceFromEnv() (*spiffe.Source, error) {
+	cfg := spiffe.Config{
+		CertFile:   os.Getenv(SpiffeSVIDCertFileEnvVar),
+		KeyFile:    os.Getenv(SpiffeSVIDKeyFileEnvVar),
+		BundleFile: os.Getenv(SpiffeBundleFileEnvVar),
+	}
+	if cfg.CertFile == "" && cfg.KeyFile == "" && cfg.BundleFile == "" {
+		return nil, nil
+	}
+	refreshInterval, err := durationFromEnv(SpiffeRefreshIntervalEnvVar, spiffe.DefaultRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+	source, err := spiffe.NewSource(cfg)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"invalid SPIFFE configuration (%s, %s, %s): %w",
+			SpiffeSVIDCertFileEnvVar, SpiffeSVIDKeyFileEnvVar, SpiffeBundleFileEnvVar, err,
+		)
+	}
+	if refreshInterval > 0 {
+		go source.Run(context.Background(), refreshInterval)
+	}
+	return source, nil
+}
+
+// dbOptionsFromEnv reads the tuning of the database connection from the environment variables.
+func dbOptionsFromEnv() (db.Options, error) {
+	var opts db.Options
+	for envVar, dst := range map[string]*int{
+		DBMaxOpenConnsEnvVar: &opts.MaxOpenConns,
+		DBMaxIdleConnsEnvVar: &opts.MaxIdleConns,
+	} {
+		if v := os.Getenv(envVar); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return opts, fmt.Errorf("invalid value for %s environment variable: '%s', must be a positive number", envVar, v)
+			}
+			*dst = n
+		}
+	}
+	var err error
+	if opts.ConnMaxLifetime, err = durationFromEnv(DBConnMaxLifetimeEnvVar, 0); err != nil {
+		return opts, err
+	}
+	if opts.ConnMaxIdleTime, err = durationFromEnv(DBConnMaxIdleTimeEnvVar, 0); err != nil {
+		return opts, err
+	}
+	if opts.SlowQueryThreshold, err = durationFromEnv(DBSlowQueryThresholdEnvVar, 0); err != nil {
+		return opts, err
+	}
+	opts.ReadReplicaDSN = os.Getenv(DBReadReplicaUrlEnvVar)
+	return opts, nil
+}
+
+// readPreloadConfig reads the configurations of the MCP servers to register at startup.
+// The file contains either a single server configuration (same as 'mcpjungle register --conf') or an array of them.
+func readPreloadConfig(filePath string) ([]types.RegisterServerInput, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preload file %s: %w", filePath, err)
+	}
+	var inputs []types.RegisterServerInput
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &inputs)
+	} else {
+		var input types.RegisterServerInput
+		err = json.Unmarshal(data, &input)
+		inputs = append(inputs, input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse preload file %s: %w", filePath, err)
+	}
+	return inputs, nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/support"
+	"github.com/spf13/cobra"
+)
+
+var supportBundleCmdFile string
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collect diagnostic information for a bug report",
+	Long: "Collect diagnostic information into a tarball that can be attached to bug reports:\n" +
+		"the versions of the CLI and the registry, the registry's configuration, its MCP servers, their health,\n" +
+		"its recent logs and a snapshot of its metrics. Collecting the registry's information requires admin access.\n\n" +
+		"Secrets (tokens, passwords, API keys, credentials in URLs and the environment of STDIO servers) are redacted\n" +
+		"from every file. Still, review the bundle before sharing it.",
+	RunE: runSupportBundle,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "24",
+	},
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVar(
+		&supportBundleCmdFile,
+		"file",
+		"",
+		"Path of the tarball to write (defaults to mcpjungle-support-<timestamp>.tar.gz in the current directory)",
+	)
+	rootCmd.AddCommand(supportBundleCmd)
+}
+
+func runSupportBundle(cmd *cobra.Command, args []string) error {
+	path := supportBundleCmdFile
+	if path == "" {
+		path = fmt.Sprintf("mcpjungle-support-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	// a bundle is still useful without the registry's information (eg- if the registry can't be reached),
+	// the error is included instead
+	var server bytes.Buffer
+	serverErr := apiClient.GetSupportBundle(&server)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create support bundle: %w", err)
+	}
+	defer f.Close()
+
+	b := support.NewBundle(f)
+	err = b.AddJSON("cli.json", map[string]any{
+		"version":      getVersion(),
+		"go_version":   runtime.Version(),
+		"os":           runtime.GOOS,
+		"arch":         runtime.GOARCH,
+		"registry_url": activeRegistryURL,
+		"context":      cmdContextName,
+	})
+	if err != nil {
+		return err
+	}
+	if serverErr != nil {
+		if err := b.Add("registry-error.txt", []byte(serverErr.Error()+"\n")); err != nil {
+			return err
+		}
+	} else if err := b.Copy(&server); err != nil {
+		return err
+	}
+	if err := b.Close(); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+
+	if serverErr != nil {
+		cmd.Printf("Warning: could not collect the registry's information: %v\n", serverErr)
+	}
+	cmd.Printf("Support bundle written to %s\n", path)
+	cmd.Println("Secrets have been redacted, but please review the bundle before sharing it.")
+	return nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/schema"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testServerCmdDryRun   bool
+	testServerCmdAllTools bool
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test resources in the registry",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "23",
+	},
+}
+
+var testServerCmd = &cobra.Command{
+	Use:   "server <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Run contract tests generated from the tool schemas of an MCP server",
+	Long: "Generate test invocations from the input schema of every tool of an MCP server and check how the server handles them:\n" +
+		"valid cases (required properties only, all properties and boundary values) must succeed and return results that\n" +
+		"match the tool's output schema, invalid cases (out of bounds, wrong types, missing properties) must be rejected.\n" +
+		"The command fails if the server violates any of its schemas, so it can be used in CI.\n\n" +
+		"The invocations are sent to the real server. Only tools annotated as read-only are tested unless --all-tools is set,\n" +
+		"use --dry-run to list the generated cases without invoking any tool.\n" +
+		"\neg- mcpjungle test server search",
+	RunE: runTestServer,
+}
+
+func init() {
+	testServerCmd.Flags().BoolVar(&testServerCmdDryRun, "dry-run", false, "List the generated cases without invoking the tools")
+	testServerCmd.Flags().BoolVar(
+		&testServerCmdAllTools, "all-tools", false, "Also test the tools that are not annotated as read-only",
+	)
+	testCmd.AddCommand(testServerCmd)
+	rootCmd.AddCommand(testCmd)
+}
+
+// contractTestResult is the outcome of a contract test case
+type contractTestResult struct {
+	Tool string `json:"tool"`
+	schema.ContractCase
+
+	// Violation describes how the server violated the tool's schemas, it is empty if the case passed
+	Violation string `json:"violation,omitempty"`
+}
+
+// contractTestReport is the outcome of the contract tests of an MCP server
+type contractTestReport struct {
+	Server       string               `json:"server"`
+	Results      []contractTestResult `json:"results"`
+	SkippedTools []string             `json:"skipped_tools,omitempty"`
+	Violations   int                  `json:"violations"`
+}
+
+func runTestServer(cmd *cobra.Command, args []string) error {
+	tools, err := apiClient.ListTools(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to list tools of server %s: %w", args[0], err)
+	}
+	if len(tools) == 0 {
+		return fmt.Errorf("server %s has no tools to test", args[0])
+	}
+
+	report := &contractTestReport{Server: args[0]}
+	for _, t := range tools {
+		readOnly := t.Annotations != nil && t.Annotations.ReadOnlyHint != nil && *t.Annotations.ReadOnlyHint
+		if !t.Enabled || (!readOnly && !testServerCmdAllTools && !testServerCmdDryRun) {
+			report.SkippedTools = append(report.SkippedTools, t.Name)
+			continue
+		}
+		var inputSchema map[string]any
+		b, _ := json.Marshal(t.InputSchema)
+		_ = json.Unmarshal(b, &inputSchema)
+
+		for _, c := range schema.ContractCases(inputSchema) {
+			r := contractTestResult{Tool: t.Name, ContractCase: c}
+			if !testServerCmdDryRun {
+				r.Violation = runContractCase(t, c)
+			}
+			if r.Violation != "" {
+				report.Violations++
+			}
+			report.Results = append(report.Results, r)
+		}
+	}
+
+	err = renderOutput(cmd, report, func() error {
+		for _, r := range report.Results {
+			b, _ := json.Marshal(r.Args)
+			status := "PASS"
+			switch {
+			case testServerCmdDryRun && r.Valid:
+				status = "VALID"
+			case testServerCmdDryRun:
+				status = "INVALID"
+			case r.Violation != "":
+				status = "FAIL"
+			}
+			cmd.Printf("[%s] %s: %s %s\n", status, r.Tool, r.Name, b)
+			if r.Violation != "" {
+				cmd.Printf("  %s\n", r.Violation)
+			}
+		}
+		if len(report.SkippedTools) > 0 {
+			cmd.Printf("\nSkipped tools (disabled or not annotated as read-only, see --all-tools): %s\n",
+				strings.Join(report.SkippedTools, ", "))
+		}
+		if !testServerCmdDryRun {
+			cmd.Printf("\n%d cases run, %d schema violations found\n", len(report.Results), report.Violations)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if report.Violations > 0 {
+		return fmt.Errorf("server %s violated its tool schemas in %d cases", args[0], report.Violations)
+	}
+	return nil
+}
+
+// runContractCase invokes a tool with the arguments of a contract test case and returns the violation of
+// the tool's schemas, if any.
+func runContractCase(t *types.Tool, c schema.ContractCase) string {
+	res, err := apiClient.InvokeTool(t.Name, c.Args)
+	rejected := err != nil || res.IsError
+	if !c.Valid {
+		if !rejected {
+			return "the server accepted arguments that don't match the input schema"
+		}
+		return ""
+	}
+	if err != nil {
+		return "the server rejected valid arguments: " + err.Error()
+	}
+	if res.IsError {
+		return "the server returned an error for valid arguments: " + resultText(res)
+	}
+	if len(t.OutputSchema) == 0 {
+		return ""
+	}
+	if res.StructuredContent == nil {
+		return "the result has no structured content, but the tool declares an output schema"
+	}
+	var structured any
+	b, _ := json.Marshal(res.StructuredContent)
+	_ = json.Unmarshal(b, &structured)
+	if violations := schema.Validate(t.OutputSchema, structured); len(violations) > 0 {
+		return "the result doesn't match the output schema: " + strings.Join(violations, "; ")
+	}
+	return ""
+}
+
+// resultText returns the text content of a tool result
+func resultText(res *types.ToolInvokeResult) string {
+	var texts []string
+	for _, item := range res.Content {
+		if text, ok := item["text"].(string); ok {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/toolbox"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	toolboxServeCmdTransport  string
+	toolboxServeCmdAddr       string
+	toolboxServeCmdFetchAllow string
+)
+
+var toolboxCmd = &cobra.Command{
+	Use:   "toolbox",
+	Short: "Built-in MCP server with utility tools",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "15",
+	},
+}
+
+var toolboxServeCmd = &cobra.Command{
+	Use:   "serve",
+	Args:  cobra.NoArgs,
+	Short: "Run an MCP server with built-in utility tools",
+	Long: "Run an MCP server that provides a handful of utility tools, useful for demos and smoke tests:\n" +
+		"  echo          returns the message it receives\n" +
+		"  current_time  returns the current time in a time zone\n" +
+		"  calculate     evaluates an arithmetic expression\n" +
+		"  fetch         fetches a URL from an allowed host (only provided with --fetch-allow)\n\n" +
+		"Register it in mcpjungle like any other MCP server, eg- with stdio:\n" +
+		"  mcpjungle register -c toolbox.json  # {\"name\": \"toolbox\", \"transport\": \"stdio\", \"command\": \"mcpjungle\", \"args\": [\"toolbox\", \"serve\"]}",
+	RunE: runToolboxServe,
+}
+
+func init() {
+	toolboxServeCmd.Flags().StringVar(
+		&toolboxServeCmdTransport,
+		"transport",
+		string(types.TransportStdio),
+		fmt.Sprintf("Transport to serve the tools over ('%s' | '%s')", types.TransportStdio, types.TransportStreamableHTTP),
+	)
+	toolboxServeCmd.Flags().StringVar(
+		&toolboxServeCmdAddr,
+		"addr",
+		":8090",
+		"Address to listen on with the streamable_http transport, the MCP endpoint is served on /mcp",
+	)
+	toolboxServeCmd.Flags().StringVar(
+		&toolboxServeCmdFetchAllow,
+		"fetch-allow",
+		"",
+		"Comma-separated list of hosts (and their subdomains) that the fetch tool may retrieve URLs from",
+	)
+
+	toolboxCmd.AddCommand(toolboxServeCmd)
+	rootCmd.AddCommand(toolboxCmd)
+}
+
+func runToolboxServe(cmd *cobra.Command, args []string) error {
+	var allowed []string
+	for _, h := range strings.Split(toolboxServeCmdFetchAllow, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			allowed = append(allowed, h)
+		}
+	}
+	s := toolbox.NewServer("mcpjungle-toolbox", getVersion(), toolbox.Options{FetchAllowedHosts: allowed})
+
+	switch types.McpServerTransport(toolboxServeCmdTransport) {
+	case types.TransportStdio:
+		return server.ServeStdio(s)
+	case types.TransportStreamableHTTP:
+		cmd.Printf("Toolbox MCP server listening on %s/mcp\n", toolboxServeCmdAddr)
+		return server.NewStreamableHTTPServer(s).Start(toolboxServeCmdAddr)
+	}
+	return fmt.Errorf("unsupported transport '%s'", toolboxServeCmdTransport)
+}
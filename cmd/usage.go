@@ -29,38 +29,39 @@ func runGetToolUsage(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get tool '%s': %w", args[0], err)
 	}
 
-	fmt.Println(t.Name)
-	fmt.Println(t.Description)
+	return renderOutput(cmd, t, func() error {
+		fmt.Println(t.Name)
+		fmt.Println(t.Description)
 
-	if len(t.InputSchema.Properties) == 0 {
-		fmt.Println("This tool does not require any input parameters.")
-		return nil
-	}
-
-	fmt.Println()
-	fmt.Println("Input Parameters:")
-	for k, v := range t.InputSchema.Properties {
-		requiredOrOptional := "optional"
-		if slices.Contains(t.InputSchema.Required, k) {
-			requiredOrOptional = "required"
+		if len(t.InputSchema.Properties) == 0 {
+			fmt.Println("This tool does not require any input parameters.")
+			return nil
 		}
 
-		boundary := strings.Repeat("=", len(k)+len(requiredOrOptional)+20)
+		fmt.Println()
+		fmt.Println("Input Parameters:")
+		for k, v := range t.InputSchema.Properties {
+			requiredOrOptional := "optional"
+			if slices.Contains(t.InputSchema.Required, k) {
+				requiredOrOptional = "required"
+			}
 
-		fmt.Println(boundary)
-		fmt.Printf("%s (%s)\n", k, requiredOrOptional)
+			boundary := strings.Repeat("=", len(k)+len(requiredOrOptional)+20)
 
-		j, err := json.MarshalIndent(v, "", "  ")
-		if err != nil {
-			// Simply print the raw object if we fail to marshal it
-			fmt.Println(v)
-		} else {
-			fmt.Println(string(j))
-		}
-		fmt.Println(boundary)
+			fmt.Println(boundary)
+			fmt.Printf("%s (%s)\n", k, requiredOrOptional)
 
-		fmt.Println()
-	}
+			j, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				// Simply print the raw object if we fail to marshal it
+				fmt.Println(v)
+			} else {
+				fmt.Println(string(j))
+			}
+			fmt.Println(boundary)
 
-	return nil
+			fmt.Println()
+		}
+		return nil
+	})
 }
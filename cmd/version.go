@@ -48,6 +48,16 @@ var versionCmd = &cobra.Command{
 		// We want the extra newline for proper formatting
 		fmt.Print(asciiArt) //nolint:staticcheck
 		fmt.Printf("MCPJungle %s\n", getVersion())
+
+		// the registry server is optional here, the CLI's version is printed even if it can't be reached
+		meta, err := apiClient.GetMetadata()
+		if err != nil {
+			return
+		}
+		fmt.Printf("Registry server at %s: MCPJungle %s\n", activeRegistryURL, meta.Version)
+		if meta.Version != getVersion() && meta.Version != defaultVersion && getVersion() != defaultVersion {
+			fmt.Println("Warning: the CLI and the registry server run different versions, some commands may not work as expected")
+		}
 	},
 	Annotations: map[string]string{
 		"group": string(subCommandGroupBasic),
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func listAccessGrantsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		grants, err := mcpService.ListAccessGrants()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, grants)
+	}
+}
+
+// createAccessGrantHandler grants an MCP client temporary access to the tools of a tool group.
+func createAccessGrantHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.CreateAccessGrantInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		duration, err := time.ParseDuration(input.Duration)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duration: " + err.Error()})
+			return
+		}
+		var grantedBy string
+		if u := currentUser(c); u != nil {
+			grantedBy = u.Username
+		}
+		g, err := mcpService.GrantAccess(input.Client, input.ToolGroup, duration, grantedBy, input.Reason)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, g)
+	}
+}
+
+func revokeAccessGrantHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid access grant ID"})
+			return
+		}
+		if err := mcpService.RevokeAccessGrant(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+)
+
+func listBudgetsHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		budgets, err := usageService.ListBudgets()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, budgets)
+	}
+}
+
+func createBudgetHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b model.Budget
+		if err := c.ShouldBindJSON(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if b.Period == "" {
+			b.Period = model.BudgetPeriodMonthly
+		}
+		if err := usageService.CreateBudget(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, b)
+	}
+}
+
+func deleteBudgetHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := usageService.DeleteBudget(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func listInvocationsHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 100
+		if l := c.Query("limit"); l != "" {
+			v, err := strconv.Atoi(l)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'limit' query parameter must be a number"})
+				return
+			}
+			limit = v
+		}
+		invocations, err := usageService.ListInvocations(c.Query("tool"), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, invocations)
+	}
+}
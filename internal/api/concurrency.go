@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// Concurrency pools of the HTTP requests. Tool calls (on the MCP proxies and the invoke APIs) and the other
+// API requests have separate pools, so that a flood of tool calls can't starve admin operations.
+const (
+	poolToolCalls = "tool_calls"
+	poolAPI       = "api"
+)
+
+// limitConcurrency is middleware that serves at most limit requests of the pool at a time, if limit is positive.
+// Requests beyond the limit are rejected with 503 Service Unavailable rather than queued, so that clients back off.
+// Requests to the skipped routes (as registered, eg- /api/v0/saved-calls/:name/invoke) are not counted.
+// Tool calls are limited by limitToolCallsFairly instead.
+func limitConcurrency(pool string, limit int, skip ...string) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	slots := make(chan struct{}, limit)
+	inFlight := metrics.HTTPRequestsInFlight.WithLabelValues(pool)
+	rejected := metrics.HTTPRequestsRejected.WithLabelValues(pool)
+	return func(c *gin.Context) {
+		if slices.Contains(skip, c.FullPath()) {
+			c.Next()
+			return
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			rejected.Inc()
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(
+				http.StatusServiceUnavailable,
+				gin.H{"error": "too many concurrent requests, try again later"},
+			)
+			return
+		}
+		inFlight.Inc()
+		defer func() {
+			inFlight.Dec()
+			<-slots
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// emergencyStatus converts the emergency stop into its API representation, stop may be nil.
+func emergencyStatus(stop *model.EmergencyStop) *types.EmergencyStatus {
+	if stop == nil {
+		return &types.EmergencyStatus{}
+	}
+	return &types.EmergencyStatus{
+		Active:       true,
+		AllowedTools: stop.GetAllowedTools(),
+		Reason:       stop.Reason,
+		ActivatedBy:  stop.ActivatedBy,
+		ActivatedAt:  &stop.CreatedAt,
+	}
+}
+
+func getEmergencyHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stop, err := mcpService.GetEmergencyStop()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, emergencyStatus(stop))
+	}
+}
+
+// emergencyDisableAllHandler disables all tool calls, except those to the tools & servers in the optional allow list.
+func emergencyDisableAllHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.EmergencyDisableInput
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		var activatedBy string
+		if u := currentUser(c); u != nil {
+			activatedBy = u.Username
+		}
+		stop, err := mcpService.EmergencyDisableAll(input.AllowedTools, input.Reason, activatedBy)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, emergencyStatus(stop))
+	}
+}
+
+// emergencyEnableAllHandler turns the emergency stop off.
+func emergencyEnableAllHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var enabledBy string
+		if u := currentUser(c); u != nil {
+			enabledBy = u.Username
+		}
+		if _, err := mcpService.EmergencyEnableAll(enabledBy); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, emergencyStatus(nil))
+	}
+}
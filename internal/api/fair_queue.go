@@ -0,0 +1,271 @@
+package api
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+var errToolCallQueueFull = errors.New("too many concurrent requests, try again later")
+
+// FairQueueOptions configures the queuing of tool calls when their concurrency pool is full.
+type FairQueueOptions struct {
+	// MaxWait is how long a tool call waits for a slot before being rejected.
+	// If zero, calls beyond the concurrency limit are rejected right away.
+	MaxWait time.Duration
+
+	// Weights are the relative shares of the pool's capacity given to callers (MCP clients, or users for the API)
+	// while it is saturated, keyed by the name of the caller, or by its key to tell apart a client & a user
+	// with the same name (eg- client:cursor). Callers that aren't listed have a weight of 1.
+	Weights map[string]float64
+}
+
+// queuedCall is a tool call waiting for a slot in a fairQueue
+type queuedCall struct {
+	caller string
+	// tag is the virtual finish time of the call, calls with the lowest tag are served first
+	tag   float64
+	seq   uint64
+	ready chan struct{}
+	// index is the position of the call in the heap, -1 once it was granted a slot or removed
+	index int
+}
+
+type callHeap []*queuedCall
+
+func (h callHeap) Len() int { return len(h) }
+func (h callHeap) Less(i, j int) bool {
+	if h[i].tag != h[j].tag {
+		return h[i].tag < h[j].tag
+	}
+	return h[i].seq < h[j].seq
+}
+func (h callHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *callHeap) Push(x any) {
+	q := x.(*queuedCall)
+	q.index = len(*h)
+	*h = append(*h, q)
+}
+func (h *callHeap) Pop() any {
+	old := *h
+	q := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	q.index = -1
+	return q
+}
+
+// fairQueue limits the number of tool calls served at a time. Once the limit is reached, calls wait in
+// per-caller queues that are served by weighted fair queuing (start-time fair queuing on virtual finish tags),
+// so that a greedy caller can't monopolize the upstream capacity: every caller with queued calls gets slots
+// in proportion to its weight, however many calls it has queued.
+type fairQueue struct {
+	limit   int
+	maxWait time.Duration
+	weights map[string]float64
+
+	mu       sync.Mutex
+	inFlight int
+	queue    callHeap
+	seq      uint64
+	// virtualTime is the tag of the last call granted a slot
+	virtualTime float64
+	// finish is the tag of the last call queued by every caller
+	finish map[string]float64
+	// queued is the number of calls queued by every caller
+	queued map[string]int
+	// queuedByLabel is the number of calls queued by the callers of every metric label, see callerMetricLabel
+	queuedByLabel map[string]int
+}
+
+func newFairQueue(limit int, opts FairQueueOptions) *fairQueue {
+	return &fairQueue{
+		limit:   limit,
+		maxWait: opts.MaxWait,
+		weights: opts.Weights,
+		finish:  make(map[string]float64),
+		queued:  make(map[string]int),
+
+		queuedByLabel: make(map[string]int),
+	}
+}
+
+// weight returns the weight of a caller, looked up by its key (eg- client:cursor) or by its name (eg- cursor).
+func (q *fairQueue) weight(caller string) float64 {
+	if w, ok := q.weights[caller]; ok && w > 0 {
+		return w
+	}
+	if _, name, ok := strings.Cut(caller, ":"); ok && !strings.HasPrefix(caller, callerIPPrefix) {
+		if w, ok := q.weights[name]; ok && w > 0 {
+			return w
+		}
+	}
+	return 1
+}
+
+// acquire waits for a slot to serve a call of the given caller.
+// It returns errToolCallQueueFull if no slot was available in time, or the context's error if it is done first.
+// release must be called once the call is served.
+func (q *fairQueue) acquire(ctx context.Context, caller string) error {
+	q.mu.Lock()
+	if q.inFlight < q.limit && len(q.queue) == 0 {
+		q.inFlight++
+		q.mu.Unlock()
+		return nil
+	}
+	if q.maxWait <= 0 {
+		q.mu.Unlock()
+		return errToolCallQueueFull
+	}
+	call := &queuedCall{
+		caller: caller,
+		tag:    max(q.virtualTime, q.finish[caller]) + 1/q.weight(caller),
+		seq:    q.seq,
+		ready:  make(chan struct{}),
+	}
+	q.seq++
+	q.finish[caller] = call.tag
+	q.queued[caller]++
+	q.queuedByLabel[callerMetricLabel(caller)]++
+	heap.Push(&q.queue, call)
+	metrics.ToolCallsQueued.WithLabelValues(callerMetricLabel(caller)).Inc()
+	q.mu.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(q.maxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-call.ready:
+		metrics.ToolCallQueueWait.WithLabelValues(callerMetricLabel(caller)).Observe(time.Since(start).Seconds())
+		return nil
+	case <-timer.C:
+		err = errToolCallQueueFull
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	q.mu.Lock()
+	if call.index >= 0 {
+		heap.Remove(&q.queue, call.index)
+		q.dequeued(call)
+		q.mu.Unlock()
+		return err
+	}
+	q.mu.Unlock()
+	// the call was granted a slot while giving up, hand it over to the next one
+	q.release()
+	return err
+}
+
+// release frees the slot of a served call, granting it to the queued call with the lowest tag, if any.
+func (q *fairQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.queue) == 0 {
+		q.inFlight--
+		return
+	}
+	call := heap.Pop(&q.queue).(*queuedCall)
+	q.virtualTime = call.tag
+	q.dequeued(call)
+	close(call.ready)
+}
+
+// dequeued updates the bookkeeping of a call that left the queue. q.mu must be held.
+func (q *fairQueue) dequeued(call *queuedCall) {
+	label := callerMetricLabel(call.caller)
+	if q.queuedByLabel[label]--; q.queuedByLabel[label] == 0 {
+		// the series of idle callers are dropped, so that they don't accumulate as callers come and go
+		delete(q.queuedByLabel, label)
+		metrics.ToolCallsQueued.DeleteLabelValues(label)
+	} else {
+		metrics.ToolCallsQueued.WithLabelValues(label).Dec()
+	}
+	if q.queued[call.caller]--; q.queued[call.caller] == 0 {
+		delete(q.queued, call.caller)
+		// an idle caller doesn't accumulate credit, its next call is tagged from the virtual time
+		delete(q.finish, call.caller)
+	}
+}
+
+// prefixes of the keys of the callers of tool calls, so that an MCP client, a user & an IP address
+// that have the same name are different callers
+const (
+	callerClientPrefix = "client:"
+	callerUserPrefix   = "user:"
+	callerIPPrefix     = "ip:"
+)
+
+// callerAnonymousLabel is the metric label of the callers identified by their IP address
+const callerAnonymousLabel = "anonymous"
+
+// callerMetricLabel returns the label of a caller in the queue metrics: its key, except for the callers identified
+// by their IP address which share a label, so that the number of series is bounded by the number of clients & users.
+func callerMetricLabel(caller string) string {
+	if strings.HasPrefix(caller, callerIPPrefix) {
+		return callerAnonymousLabel
+	}
+	return caller
+}
+
+// toolCallCaller returns the key of the caller of a tool call that fair queuing shares capacity between
+// and that rate limits are counted for: the MCP client, the user for API calls, or the client's IP address
+// if the caller is anonymous (development mode), eg- client:cursor, user:alice or ip:10.0.0.1.
+// It assumes that the caller was authenticated by the previous middleware.
+func toolCallCaller(c *gin.Context) string {
+	if client, ok := c.Request.Context().Value("client").(*model.McpClient); ok && client != nil {
+		return callerClientPrefix + client.Name
+	}
+	if u, ok := c.Get("user"); ok {
+		if user, _ := u.(*model.User); user != nil {
+			return callerUserPrefix + user.Username
+		}
+	}
+	return callerIPPrefix + c.ClientIP()
+}
+
+// limitToolCallsFairly is middleware that serves at most limit tool calls at a time, if limit is positive.
+// Calls beyond the limit are queued for up to opts.MaxWait and served by weighted fair queuing across callers,
+// then rejected with 503 Service Unavailable if they didn't get a slot in time.
+// GET requests on the MCP proxies, which open long-lived streams rather than make calls, are not counted.
+func limitToolCallsFairly(limit int, opts FairQueueOptions) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	q := newFairQueue(limit, opts)
+	inFlight := metrics.HTTPRequestsInFlight.WithLabelValues(poolToolCalls)
+	rejected := metrics.HTTPRequestsRejected.WithLabelValues(poolToolCalls)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet {
+			c.Next()
+			return
+		}
+		if err := q.acquire(c.Request.Context(), toolCallCaller(c)); err != nil {
+			rejected.Inc()
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": errToolCallQueueFull.Error()})
+			return
+		}
+		inFlight.Inc()
+		defer func() {
+			inFlight.Dec()
+			q.release()
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// waitQueued waits until n calls are queued in q
+func waitQueued(t *testing.T, q *fairQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		q.mu.Lock()
+		queued := len(q.queue)
+		q.mu.Unlock()
+		if queued == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d queued calls, got %d", n, queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFairQueueSharesCapacityByWeight(t *testing.T) {
+	q := newFairQueue(1, FairQueueOptions{MaxWait: time.Minute, Weights: map[string]float64{"greedy": 3}})
+	if err := q.acquire(context.Background(), "client:holder"); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	for _, caller := range []string{"client:greedy", "client:other"} {
+		for range 40 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := q.acquire(context.Background(), caller); err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				served = append(served, caller)
+				mu.Unlock()
+				q.release()
+			}()
+		}
+	}
+	waitQueued(t, q, 80)
+	q.release()
+	wg.Wait()
+
+	// while both callers have calls queued, greedy gets 3 slots for every slot of other
+	greedy := 0
+	for _, caller := range served[:40] {
+		if caller == "client:greedy" {
+			greedy++
+		}
+	}
+	if greedy != 30 {
+		t.Errorf("expected greedy to be served 30 of the first 40 calls, got %d: %v", greedy, served[:40])
+	}
+	if q.inFlight != 0 || len(q.queued) != 0 || len(q.finish) != 0 {
+		t.Errorf("expected the queue to be idle, got %d in flight, queued %v, finish tags %v", q.inFlight, q.queued, q.finish)
+	}
+}
+
+func TestFairQueueCancelWhileQueued(t *testing.T) {
+	q := newFairQueue(1, FairQueueOptions{MaxWait: time.Minute})
+	if err := q.acquire(context.Background(), "client:holder"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- q.acquire(ctx, "client:cancelled") }()
+	waitQueued(t, q, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the queued call to be cancelled, got %v", err)
+	}
+	if len(q.queue) != 0 || len(q.queued) != 0 || len(q.finish) != 0 {
+		t.Fatalf("expected the cancelled call to leave the queue, got %d queued calls", len(q.queue))
+	}
+	if metrics.ToolCallsQueued.DeleteLabelValues("client:cancelled") {
+		t.Error("expected the queue metric of the idle caller to be deleted")
+	}
+
+	// the slot of the holder isn't granted to the cancelled call
+	q.release()
+	if q.inFlight != 0 {
+		t.Fatalf("expected no call in flight, got %d", q.inFlight)
+	}
+	if err := q.acquire(context.Background(), "client:next"); err != nil {
+		t.Fatalf("expected a free slot, got %v", err)
+	}
+}
+
+func TestFairQueueCapacityLimit(t *testing.T) {
+	q := newFairQueue(2, FairQueueOptions{})
+	for range 2 {
+		if err := q.acquire(context.Background(), "client:a"); err != nil {
+			t.Fatalf("expected a slot within the limit, got %v", err)
+		}
+	}
+	// without a wait, calls beyond the limit are rejected right away
+	if err := q.acquire(context.Background(), "client:b"); !errors.Is(err, errToolCallQueueFull) {
+		t.Fatalf("expected a call beyond the limit to be rejected, got %v", err)
+	}
+
+	q.maxWait = 20 * time.Millisecond
+	start := time.Now()
+	if err := q.acquire(context.Background(), "ip:10.0.0.1"); !errors.Is(err, errToolCallQueueFull) {
+		t.Fatalf("expected a call beyond the limit to time out, got %v", err)
+	}
+	if time.Since(start) < q.maxWait {
+		t.Errorf("expected the call to wait for %s before being rejected", q.maxWait)
+	}
+	if metrics.ToolCallsQueued.DeleteLabelValues(callerAnonymousLabel) {
+		t.Error("expected the queue metric of the idle anonymous callers to be deleted")
+	}
+
+	q.release()
+	if err := q.acquire(context.Background(), "client:b"); err != nil {
+		t.Fatalf("expected the released slot to be available, got %v", err)
+	}
+}
+
+func TestCallerMetricLabel(t *testing.T) {
+	for caller, label := range map[string]string{
+		"client:cursor": "client:cursor",
+		"user:alice":    "user:alice",
+		"ip:10.0.0.1":   callerAnonymousLabel,
+		"ip:10.0.0.2":   callerAnonymousLabel,
+	} {
+		if got := callerMetricLabel(caller); got != label {
+			t.Errorf("callerMetricLabel(%s) = %s, want %s", caller, got, label)
+		}
+	}
+}
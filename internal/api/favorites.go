@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func listFavoriteToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tools, err := mcpService.ListFavoriteTools(currentUser(c).Username)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if tools == nil {
+			tools = []string{}
+		}
+		c.JSON(http.StatusOK, tools)
+	}
+}
+
+func starToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := mcpService.StarTool(currentUser(c).Username, c.Param("tool")); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func unstarToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := mcpService.UnstarTool(currentUser(c).Username, c.Param("tool")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func toToolView(v *model.ToolView) types.ToolView {
+	return types.ToolView{Name: v.Name, Tools: v.GetTools()}
+}
+
+func listToolViewsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		views, err := mcpService.ListToolViews(currentUser(c).Username)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		resp := make([]types.ToolView, len(views))
+		for i, v := range views {
+			resp[i] = toToolView(v)
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func setToolViewHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.ToolView
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		v, err := mcpService.SetToolView(currentUser(c).Username, input.Name, input.Tools)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, toToolView(v))
+	}
+}
+
+func deleteToolViewHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := mcpService.DeleteToolView(currentUser(c).Username, c.Param("name")); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// selectToolView is middleware for the MCP proxy that applies the view of a user selected with the
+// X-MCPJungle-Tool-View header to the tools listed by the session, see MCPService.ArrangeProxyTools.
+// The user is authenticated with their access token, sent in the X-MCPJungle-User-Token header.
+// Views only exist in production mode, the headers are ignored in development mode.
+func selectToolView(userService *user.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		view := c.GetHeader(types.ToolViewHeader)
+		if view == "" || c.Request.Context().Value("mode") != model.ModeProd {
+			c.Next()
+			return
+		}
+		token := c.GetHeader(types.UserTokenHeader)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "the access token of the user whose view is selected is missing from the " +
+					types.UserTokenHeader + " header",
+			})
+			return
+		}
+		u, err := userService.GetUserByAccessToken(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user access token: " + err.Error()})
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), "tool_view_user", u.Username)
+		ctx = context.WithValue(ctx, "tool_view", view)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
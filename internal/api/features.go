@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func listFeaturesHandler(featureService *feature.FeatureService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, featureService.ListFeatures())
+	}
+}
+
+func setFeatureHandler(featureService *feature.FeatureService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetFeatureInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if err := featureService.SetFeature(input.Name, input.Enabled); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, feature.ErrOverridden) {
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// requireFeature is middleware that rejects requests to the endpoints of a feature that is turned off.
+func requireFeature(featureService *feature.FeatureService, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !featureService.Enabled(name) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "feature " + name + " is not enabled"})
+			return
+		}
+		c.Next()
+	}
+}
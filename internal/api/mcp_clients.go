@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
 	"net/http"
 )
@@ -18,7 +19,7 @@ func listMcpClientsHandler(mcpClientService *mcp_client.McpClientService) gin.Ha
 	}
 }
 
-func createMcpClientHandler(mcpClientService *mcp_client.McpClientService) gin.HandlerFunc {
+func createMcpClientHandler(mcpClientService *mcp_client.McpClientService, mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req model.McpClient
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -29,6 +30,12 @@ func createMcpClientHandler(mcpClientService *mcp_client.McpClientService) gin.H
 			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
 			return
 		}
+		if req.ToolGroup != "" {
+			if _, err := mcpService.GetToolGroup(req.ToolGroup); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "tool group " + req.ToolGroup + " not found"})
+				return
+			}
+		}
 		// TODO: if allow list in the request is null, convert it to an empty JSON array
 		client, err := mcpClientService.CreateClient(req)
 		if err != nil {
@@ -46,7 +53,15 @@ func deleteMcpClientHandler(mcpClientService *mcp_client.McpClientService) gin.H
 			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
 			return
 		}
-		if err := mcpClientService.DeleteClient(name); err != nil {
+		version, ok := versionQuery(c)
+		if !ok {
+			return
+		}
+		err := mcpClientService.DeleteClient(name, version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
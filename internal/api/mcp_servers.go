@@ -1,14 +1,71 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"io"
 	"net/http"
 )
 
+// newServerModelFromInput validates the server registration input and converts it into a server model.
+func newServerModelFromInput(input *types.RegisterServerInput) (*model.McpServer, error) {
+	transport, err := types.ValidateTransport(input.Transport)
+	if err != nil {
+		return nil, err
+	}
+	var server *model.McpServer
+	if transport == types.TransportStreamableHTTP {
+		server, err = model.NewStreamableHTTPServer(
+			input.Name,
+			input.Description,
+			input.URL,
+			input.BearerToken,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating streamable http server: %w", err)
+		}
+		if input.SpiffeID != "" {
+			if err := spiffe.ValidateID(input.SpiffeID); err != nil {
+				return nil, err
+			}
+			if err := server.SetSpiffeID(input.SpiffeID); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		server, err = model.NewStdioServer(input.Name, input.Description, model.StdioConfig{
+			Command: input.Command,
+			Args:    input.Args,
+			Env:     input.Env,
+			Framing: types.StdioFraming(input.Framing),
+			Cwd:     input.Cwd,
+			RunAs:   input.RunAs,
+
+			Integrity: input.Integrity,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating stdio server: %w", err)
+		}
+	}
+	server.Region = input.Region
+	server.ReplicaOf = input.ReplicaOf
+	server.Group = input.Group
+	server.KeepWarm = input.KeepWarm
+	if server.IdentityPinning, err = types.ValidateIdentityPinning(input.IdentityPinning); err != nil {
+		return nil, err
+	}
+	if err := server.SetRetryPolicy(input.RetryPolicy); err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %w", err)
+	}
+	return server, nil
+}
+
 func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var input types.RegisterServerInput
@@ -16,45 +73,12 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-
-		transport, err := types.ValidateTransport(input.Transport)
+		server, err := newServerModelFromInput(&input)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		var server *model.McpServer
-		if transport == types.TransportStreamableHTTP {
-			server, err = model.NewStreamableHTTPServer(
-				input.Name,
-				input.Description,
-				input.URL,
-				input.BearerToken,
-			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating streamable http server: %v", err)},
-				)
-				return
-			}
-		} else {
-			server, err = model.NewStdioServer(
-				input.Name,
-				input.Description,
-				input.Command,
-				input.Args,
-				input.Env,
-			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating stdio server: %v", err)},
-				)
-				return
-			}
-		}
-
 		if err := mcpService.RegisterMcpServer(c, server); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -63,10 +87,42 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	}
 }
 
+// preflightServerHandler test-connects to an MCP server without registering it and
+// returns the tools that the server provides.
+func preflightServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.RegisterServerInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		server, err := newServerModelFromInput(&input)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tools, err := mcpService.PreflightMcpServer(c, server)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"tools": tools})
+	}
+}
+
 func deregisterServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		name := c.Param("name")
-		if err := mcpService.DeregisterMcpServer(name); err != nil {
+		version, ok := versionQuery(c)
+		if !ok {
+			return
+		}
+		err := mcpService.DeregisterMcpServer(name, version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -87,6 +143,19 @@ func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 				Name:        record.Name,
 				Transport:   string(record.Transport),
 				Description: record.Description,
+				Region:      record.Region,
+				ReplicaOf:   record.ReplicaOf,
+				KeepWarm:    record.KeepWarm,
+				Group:       record.Group,
+				Stopped:     record.Stopped,
+				Version:     record.Version,
+
+				ServerInfo:      record.GetServerInfo(),
+				IdentityPinning: string(record.IdentityPinning),
+			}
+			servers[i].Status, servers[i].Restarts = mcpService.ServerStatus(&records[i])
+			if rp, err := record.GetRetryPolicy(); err == nil {
+				servers[i].RetryPolicy = rp
 			}
 			if record.Transport == types.TransportStreamableHTTP {
 				conf, err := record.GetStreamableHTTPConfig()
@@ -100,7 +169,7 @@ func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 					return
 				}
 				servers[i].URL = conf.URL
-			} else {
+			} else if record.Transport == types.TransportStdio {
 				conf, err := record.GetStdioConfig()
 				if err != nil {
 					c.JSON(
@@ -119,3 +188,120 @@ func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, servers)
 	}
 }
+
+// getServerCapabilitiesHandler returns the protocol version & capabilities negotiated with an MCP server.
+// Pass the query param refresh=true to re-negotiate them with the server.
+func getServerCapabilitiesHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		refresh := c.Query("refresh") == "true"
+		caps, err := mcpService.GetMcpServerCapabilities(c, name, refresh)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "MCP server not found: " + name})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get server capabilities: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, caps)
+	}
+}
+
+// getServerHealthHandler returns the health of an MCP server.
+// The status code is 503 if the server is unhealthy, so that uptime monitors don't need to parse the response.
+func getServerHealthHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		refresh := c.Query("refresh") == "true"
+		h, err := mcpService.GetServerHealth(c, name, refresh)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "MCP server not found: " + name})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get server health: " + err.Error()})
+			return
+		}
+		status := http.StatusOK
+		if h.Status == types.ServerUnhealthy {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, h)
+	}
+}
+
+// getServersHealthHandler returns the health of all the MCP servers.
+// The status code is 503 if all the servers that were checked are unhealthy.
+func getServersHealthHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h, err := mcpService.GetServersHealth()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get servers health: " + err.Error()})
+			return
+		}
+		status := http.StatusOK
+		if h.Status == types.ServerUnhealthy {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, h)
+	}
+}
+
+// pinServerHandler records the identity currently reported by an MCP server as its pinned identity,
+// optionally changing the server's pinning mode.
+func pinServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		var input types.PinServerInput
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if _, err := types.ValidateIdentityPinning(input.IdentityPinning); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		s, err := mcpService.PinMcpServerIdentity(c, name, input.IdentityPinning)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "MCP server not found: " + name})
+			return
+		}
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to pin server identity: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, &types.McpServer{
+			Name:            s.Name,
+			Transport:       string(s.Transport),
+			Description:     s.Description,
+			ServerInfo:      s.GetServerInfo(),
+			IdentityPinning: string(s.IdentityPinning),
+			Version:         s.Version,
+		})
+	}
+}
+
+// serverGroupLifecycleHandler stops or starts all the MCP servers of a server group.
+func serverGroupLifecycleHandler(mcpService *mcp.MCPService, start bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group := c.Param("group")
+		lifecycle, action := mcpService.StopServerGroup, "stop"
+		if start {
+			lifecycle, action = mcpService.StartServerGroup, "start"
+		}
+		result, err := lifecycle(group)
+		if errors.Is(err, mcp.ErrServerGroupNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to " + action + " server group: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -1,9 +1,23 @@
 package api
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/authorizer"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
@@ -12,11 +26,20 @@ import (
 func listToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		server := c.Query("server")
+		view := c.Query("view")
 		var (
 			tools []model.Tool
 			err   error
 		)
-		if server == "" {
+		if view != "" {
+			// list the tools in a view of the current user, eg- their favorites
+			u := currentUser(c)
+			if u == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "views of tools are only available in production mode"})
+				return
+			}
+			tools, err = mcpService.ListToolsInView(u.Username, view)
+		} else if server == "" {
 			// no server specified, list all tools
 			tools, err = mcpService.ListTools()
 		} else {
@@ -59,12 +82,224 @@ func invokeToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 
 		resp, err := mcpService.InvokeTool(c, name, args)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invoke tool: " + err.Error()})
+			c.JSON(invokeErrorStatus(err), gin.H{"error": "failed to invoke tool: " + err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, resp)
+		if c.GetHeader("Accept") == types.ToolInvokeStreamContentType {
+			streamToolResult(c, resp)
+			return
+		}
+		writeToolResult(c, resp)
+	}
+}
+
+// streamChunkSize is the maximum size of the text carried by a chunk of a streamed tool call result
+const streamChunkSize = 32 << 10
+
+// streamToolResult writes the result of a tool call as newline-delimited types.ToolInvokeChunk objects,
+// flushing each of them so that the caller can forward the content progressively.
+func streamToolResult(c *gin.Context, result *types.ToolInvokeResult) {
+	c.Header("Content-Type", types.ToolInvokeStreamContentType)
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	write := func(chunk *types.ToolInvokeChunk) bool {
+		if err := enc.Encode(chunk); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	for i, content := range result.Content {
+		text, isText := content["text"].(string)
+		if content["type"] != "text" || !isText || len(text) <= streamChunkSize {
+			if !write(&types.ToolInvokeChunk{Index: i, Content: content}) {
+				return
+			}
+			continue
+		}
+		for len(text) > 0 {
+			n := min(streamChunkSize, len(text))
+			// don't split a multibyte character across chunks
+			for n < len(text) && !utf8.RuneStart(text[n]) {
+				n--
+			}
+			part := make(map[string]any, len(content))
+			for k, v := range content {
+				part[k] = v
+			}
+			part["text"] = text[:n]
+			text = text[n:]
+			if !write(&types.ToolInvokeChunk{Index: i, Content: part}) {
+				return
+			}
+		}
+	}
+
+	rest := *result
+	rest.Content = nil
+	write(&types.ToolInvokeChunk{Index: len(result.Content), Result: &rest})
+}
+
+// writeToolResult responds with the result of a tool call, as c.JSON would, but encodes its content
+// item by item straight into the response instead of marshaling the whole result into memory first.
+// Large strings (eg- the text of a big file) are escaped in pieces of streamChunkSize.
+func writeToolResult(c *gin.Context, result *types.ToolInvokeResult) {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := bufio.NewWriterSize(c.Writer, streamChunkSize)
+	if err := encodeToolResult(w, result); err != nil {
+		log.Printf("[ERROR] failed to write the result of a tool call: %v", err)
+		return
+	}
+	if err := w.Flush(); err != nil {
+		log.Printf("[ERROR] failed to write the result of a tool call: %v", err)
+	}
+}
+
+// encodeToolResult writes the JSON encoding of a tool call result, with the same fields as json.Marshal.
+func encodeToolResult(w *bufio.Writer, result *types.ToolInvokeResult) error {
+	head, err := json.Marshal(struct {
+		Meta    map[string]any `json:"_meta,omitempty"`
+		IsError bool           `json:"isError,omitempty"`
+	}{result.Meta, result.IsError})
+	if err != nil {
+		return err
+	}
+	// reopen the object to append the content after the other fields
+	w.Write(head[:len(head)-1])
+	if len(head) > 2 {
+		w.WriteByte(',')
+	}
+
+	if result.Content == nil {
+		w.WriteString(`"content":null`)
+	} else {
+		w.WriteString(`"content":[`)
+		for i, item := range result.Content {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if err := encodeContentItem(w, item); err != nil {
+				return err
+			}
+		}
+		w.WriteByte(']')
+	}
+
+	if result.StructuredContent != nil {
+		sc, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return err
+		}
+		w.WriteString(`,"structuredContent":`)
+		w.Write(sc)
+	}
+	return w.WriteByte('}')
+}
+
+// encodeContentItem writes a content item of a tool call result as a JSON object with sorted keys.
+func encodeContentItem(w *bufio.Writer, item map[string]any) error {
+	if item == nil {
+		_, err := w.WriteString("null")
+		return err
+	}
+	keys := make([]string, 0, len(item))
+	for k := range item {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		w.Write(key)
+		w.WriteByte(':')
+
+		s, ok := item[k].(string)
+		if !ok || len(s) <= streamChunkSize {
+			v, err := json.Marshal(item[k])
+			if err != nil {
+				return err
+			}
+			w.Write(v)
+			continue
+		}
+		w.WriteByte('"')
+		for len(s) > 0 {
+			n := min(streamChunkSize, len(s))
+			// escaping a piece that ends within a multibyte character would mangle it
+			for n < len(s) && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			piece, err := json.Marshal(s[:n])
+			if err != nil {
+				return err
+			}
+			// strip the quotes of the piece
+			if _, err := w.Write(piece[1 : len(piece)-1]); err != nil {
+				return err
+			}
+			s = s[n:]
+		}
+		w.WriteByte('"')
 	}
+	return w.WriteByte('}')
+}
+
+// invokeErrorStatus returns the HTTP status code to respond with when a tool invocation fails.
+func invokeErrorStatus(err error) int {
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		return http.StatusPaymentRequired
+	}
+	if errors.Is(err, authorizer.ErrUnavailable) || errors.Is(err, mcp.ErrEmergencyStop) ||
+		errors.Is(err, mcp.ErrServerStopped) || errors.Is(err, mcp.ErrServerDraining) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, mcp.ErrDeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	if errors.Is(err, mcp.ErrResidencyViolation) || errors.Is(err, policy.ErrPolicyDenied) ||
+		errors.Is(err, mcp.ErrAccessDenied) || errors.Is(err, events.ErrRejected) || errors.Is(err, wasm.ErrDenied) ||
+		errors.Is(err, authorizer.ErrDenied) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// respondVersionConflict responds with 409 Conflict and the details of the conflict if err is
+// a *types.VersionConflict. It returns false if err is not a conflict.
+func respondVersionConflict(c *gin.Context, err error) bool {
+	var conflict *types.VersionConflict
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	resp := *conflict
+	resp.Message = err.Error()
+	c.JSON(http.StatusConflict, resp)
+	return true
+}
+
+// versionQuery returns the version that a change is based on, from the optional 'version' query parameter.
+// It responds with 400 Bad Request and returns false if the parameter is not a number.
+func versionQuery(c *gin.Context) (int, bool) {
+	v := c.Query("version")
+	if v == "" {
+		return 0, true
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'version' query parameter must be a number"})
+		return 0, false
+	}
+	return version, true
 }
 
 // getToolHandler returns the tool with the given name.
@@ -95,7 +330,14 @@ func enableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
 			return
 		}
-		enabledTools, err := mcpService.EnableTools(entity)
+		version, ok := versionQuery(c)
+		if !ok {
+			return
+		}
+		enabledTools, err := mcpService.EnableTools(entity, version)
+		if respondVersionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enable tool(s): " + err.Error()})
 			return
@@ -112,7 +354,14 @@ func disableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'entity' query parameter"})
 			return
 		}
-		disabledTools, err := mcpService.DisableTools(entity)
+		version, ok := versionQuery(c)
+		if !ok {
+			return
+		}
+		disabledTools, err := mcpService.DisableTools(entity, version)
+		if respondVersionConflict(c, err) {
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable tool(s): " + err.Error()})
 			return
@@ -120,3 +369,167 @@ func disableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, disabledTools)
 	}
 }
+
+// setToolCostHandler sets the pricing of a tool.
+func setToolCostHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetToolCostInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		err := mcpService.SetToolCost(input.Name, input.CostPerCall, input.CostPerUnit, input.CostUnitArg, input.Version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set tool cost: " + err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// setToolOutputSchemaHandler sets the output schema of a tool.
+func setToolOutputSchemaHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetToolOutputSchemaInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		err := mcpService.SetToolOutputSchema(input.Name, input.OutputSchema, input.Version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set tool output schema: " + err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// setToolDocsHandler enriches the description of a tool.
+func setToolDocsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetToolDocsInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		err := mcpService.SetToolDocs(input.Name, input.Description, input.Append, input.Examples, input.Version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set tool documentation: " + err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// lintToolsHandler reports quality issues in the definitions of the enabled tools.
+func lintToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		issues, err := mcpService.LintTools()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, issues)
+	}
+}
+
+// setToolHedgingHandler configures hedged requests for a tool.
+func setToolPayloadLoggingHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetToolPayloadLoggingInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		err := mcpService.SetToolPayloadLogging(input.Name, input.SampleRate, input.Redact, input.Version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set tool payload logging: " + err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func setToolHedgingHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetToolHedgingInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		var delay time.Duration
+		if input.Delay != "" {
+			d, err := time.ParseDuration(input.Delay)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hedging delay: " + err.Error()})
+				return
+			}
+			delay = d
+		}
+		err := mcpService.SetToolHedging(input.Name, delay, input.Version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set tool hedging: " + err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// setToolScriptsHandler attaches CEL scripts to a tool.
+func setToolScriptsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetToolScriptsInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'name' field in request body"})
+			return
+		}
+		err := mcpService.SetToolScripts(input.Name, input.Args, input.Result, input.Version)
+		if respondVersionConflict(c, err) {
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to set tool scripts: " + err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -4,13 +4,21 @@ import (
 	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"log"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // requireInitialized is middleware to reject requests to certain routes if the server is not initialized
@@ -27,6 +35,33 @@ func requireInitialized(configService *config.ServerConfigService) gin.HandlerFu
 	}
 }
 
+// rejectWritesInReadOnlyMode is middleware that rejects requests which modify the registry while it is in read-only mode.
+// Requests that only read data are always allowed, as are the exempted routes (eg- the one to turn read-only mode off).
+func rejectWritesInReadOnlyMode(configService *config.ServerConfigService, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		for _, route := range exempt {
+			if c.FullPath() == route {
+				c.Next()
+				return
+			}
+		}
+		readOnly, _, err := configService.ReadOnly()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if readOnly {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "the registry is in read-only mode, changes are not allowed"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // verifyUserAuthForAPIAccess is middleware that checks for a valid user token if the server is in production mode.
 // this middleware doesn't care about the role of the user, it just verifies that they're authenticated.
 func verifyUserAuthForAPIAccess(userService *user.UserService) gin.HandlerFunc {
@@ -130,6 +165,7 @@ func requireServerMode(m model.ServerMode) gin.HandlerFunc {
 
 // checkAuthForMcpProxyAccess is middleware for MCP proxy that checks for a valid MCP client token
 // if the server is in production mode.
+// Clients without a token are authenticated by the SPIFFE ID of the SVID they presented, if any.
 // In development mode, mcp clients do not require auth to access the MCP proxy.
 func checkAuthForMcpProxyAccess(mcpClientService *mcp_client.McpClientService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -157,13 +193,32 @@ func checkAuthForMcpProxyAccess(mcpClientService *mcp_client.McpClientService) g
 
 		authHeader := c.GetHeader("Authorization")
 		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
+		var client *model.McpClient
+		var err error
+		if token != "" {
+			client, err = mcpClientService.GetClientByToken(token)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid MCP client token"})
+				return
+			}
+		} else if spiffeID, ok := spiffe.PeerID(c.Request.TLS); ok {
+			// the client presented an SVID, already verified against the trust bundle during the TLS handshake
+			client, err = mcpClientService.GetClientBySpiffeID(spiffeID)
+			if err != nil {
+				c.AbortWithStatusJSON(
+					http.StatusUnauthorized, gin.H{"error": "SPIFFE ID " + spiffeID + " is not mapped to an MCP client"},
+				)
+				return
+			}
+		} else {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing MCP client access token"})
 			return
 		}
-		client, err := mcpClientService.GetClientByToken(token)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid MCP client token"})
+
+		// a leaked token must not be usable from outside the client's expected networks
+		ip, err := netip.ParseAddr(c.ClientIP())
+		if err != nil || !client.CheckIPAllowed(ip) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "MCP client is not allowed to connect from this IP address"})
 			return
 		}
 
@@ -174,3 +229,146 @@ func checkAuthForMcpProxyAccess(mcpClientService *mcp_client.McpClientService) g
 		c.Next()
 	}
 }
+
+// impersonateCaller is middleware that lets an admin make a request on behalf of an MCP client or user,
+// identified by the X-Impersonate-Client or X-Impersonate-User header.
+// The impersonated caller replaces the admin in context, so the request is subject to the same access control,
+// policies and budgets as if the caller had made it. The admin is recorded for auditing.
+// It assumes that verifyUserAuthForAPIAccess middleware has already run and set the user in context.
+func impersonateCaller(
+	mcpClientService *mcp_client.McpClientService, userService *user.UserService, auditService *audit.AuditService,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientName := c.GetHeader(types.ImpersonateClientHeader)
+		username := c.GetHeader(types.ImpersonateUserHeader)
+		if clientName == "" && username == "" {
+			c.Next()
+			return
+		}
+		if clientName != "" && username != "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "only one client or user can be impersonated"})
+			return
+		}
+		if mode, _ := c.Get("mode"); mode != model.ModeProd {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "impersonation is only available in production mode"})
+			return
+		}
+		admin, ok := c.Get("user")
+		u, _ := admin.(*model.User)
+		if !ok || u == nil || u.Role != types.UserRoleAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only an admin can impersonate other callers"})
+			return
+		}
+
+		if clientName != "" {
+			client, err := mcpClientService.GetClient(clientName)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "cannot impersonate client " + clientName + ": " + err.Error()})
+				return
+			}
+			c.Set("client", client)
+			c.Set("user", (*model.User)(nil))
+			// the tool call limits identify the caller by the client in the underlying request's context
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "client", client))
+			log.Printf("[AUDIT] admin %s is impersonating MCP client %s: %s %s", u.Username, clientName, c.Request.Method, c.Request.URL.Path)
+			auditService.Record(audit.Event{
+				Type:           audit.EventImpersonation,
+				Client:         clientName,
+				ImpersonatedBy: u.Username,
+				Method:         c.Request.Method,
+				Path:           c.Request.URL.Path,
+			})
+		} else {
+			target, err := userService.GetUser(username)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "cannot impersonate user " + username + ": " + err.Error()})
+				return
+			}
+			c.Set("user", target)
+			log.Printf("[AUDIT] admin %s is impersonating user %s: %s %s", u.Username, username, c.Request.Method, c.Request.URL.Path)
+			auditService.Record(audit.Event{
+				Type:           audit.EventImpersonation,
+				User:           username,
+				ImpersonatedBy: u.Username,
+				Method:         c.Request.Method,
+				Path:           c.Request.URL.Path,
+			})
+		}
+		c.Set("impersonated_by", u.Username)
+		c.Next()
+	}
+}
+
+// auditAdminRequests is middleware that records every admin request that may change the registry
+// (ie, all except GET requests) in the audit trail, along with the response status.
+func auditAdminRequests(auditService *audit.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if auditService == nil || c.Request.Method == http.MethodGet {
+			return
+		}
+		e := audit.Event{
+			Type:   audit.EventAdminRequest,
+			Method: c.Request.Method,
+			Path:   c.Request.URL.Path,
+			Status: c.Writer.Status(),
+		}
+		if u, ok := c.Get("user"); ok {
+			if user, _ := u.(*model.User); user != nil {
+				e.User = user.Username
+			}
+		}
+		auditService.Record(e)
+	}
+}
+
+// locateCaller is middleware that stores the IP address of the caller in context, to route its tool calls
+// to the closest replicas of MCP servers. The address is also set in the underlying request's context,
+// which is the one passed down to the MCP proxy.
+func locateCaller() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		c.Set("client_ip", ip)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "client_ip", ip))
+		c.Next()
+	}
+}
+
+// acceptCallTimeout is middleware that reads the timeout of a tool call from the X-MCPJungle-Timeout-Ms header
+// and stores the resulting deadline in context, for the MCP service to bound the upstream call by it.
+// The deadline is also set in the underlying request's context, which is the one passed down to the MCP proxy.
+func acceptCallTimeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v := c.GetHeader(types.TimeoutHeader)
+		if v == "" {
+			c.Next()
+			return
+		}
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ms <= 0 {
+			c.AbortWithStatusJSON(
+				http.StatusBadRequest,
+				gin.H{"error": types.TimeoutHeader + " header must be a positive number of milliseconds"},
+			)
+			return
+		}
+		deadline := time.Now().Add(time.Duration(ms) * time.Millisecond)
+		c.Set("deadline", deadline)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "deadline", deadline))
+		c.Next()
+	}
+}
+
+// forgetTerminatedSessions is middleware that lets the session manager forget the proxy sessions that their clients
+// terminate, when it doesn't issue the sessions itself (see mcp.SessionManager.Enforced).
+func forgetTerminatedSessions(sessionManager *mcp.SessionManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if sessionManager == nil || sessionManager.Enforced() || c.Request.Method != http.MethodDelete {
+			return
+		}
+		if id := c.GetHeader(server.HeaderKeySessionID); id != "" && c.Writer.Status() == http.StatusOK {
+			_, _ = sessionManager.Terminate(id)
+		}
+	}
+}
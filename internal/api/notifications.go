@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func listNotificationsHandler(notificationService *notification.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 100
+		if l := c.Query("limit"); l != "" {
+			v, err := strconv.Atoi(l)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'limit' query parameter must be a number"})
+				return
+			}
+			limit = v
+		}
+		notifications, err := notificationService.ListNotifications(c.Query("unread") == "true", limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, notifications)
+	}
+}
+
+func markNotificationsReadHandler(notificationService *notification.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.MarkNotificationsReadInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if err := notificationService.MarkRead(input.IDs...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+)
+
+func listPoliciesHandler(policyService *policy.PolicyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies, err := policyService.ListPolicies()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, policies)
+	}
+}
+
+func createPolicyHandler(policyService *policy.PolicyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var p model.Policy
+		if err := c.ShouldBindJSON(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if p.Language == "" {
+			p.Language = model.PolicyLanguageCEL
+		}
+		if err := policyService.CreatePolicy(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, p)
+	}
+}
+
+func deletePolicyHandler(policyService *policy.PolicyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := policyService.DeletePolicy(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
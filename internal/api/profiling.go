@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+)
+
+// registerProfilingHandlers mounts the pprof endpoints on /debug/pprof, to diagnose memory & goroutine leaks
+// in a running gateway (eg- `curl -H "Authorization: Bearer <token>" <url>/debug/pprof/heap > heap.out`).
+// Like the admin API, they are only accessible to admins in production mode and to anyone in development mode.
+func registerProfilingHandlers(
+	r *gin.Engine, configService *config.ServerConfigService, userService *user.UserService,
+) {
+	g := r.Group(
+		"/debug/pprof",
+		requireInitialized(configService),
+		verifyUserAuthForAPIAccess(userService),
+		requireAdminUser(),
+	)
+	g.GET("/", gin.WrapF(pprof.Index))
+	g.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	g.GET("/profile", gin.WrapF(pprof.Profile))
+	g.GET("/symbol", gin.WrapF(pprof.Symbol))
+	g.POST("/symbol", gin.WrapF(pprof.Symbol))
+	g.GET("/trace", gin.WrapF(pprof.Trace))
+	// the named profiles: heap, goroutine, allocs, block, mutex & threadcreate
+	g.GET("/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
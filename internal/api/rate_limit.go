@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/ratelimit"
+)
+
+// limitToolCallRate is middleware that rejects the tool calls of callers that exceeded one of the limits of limiter
+// (eg- calls per minute or per day) with 429 Too Many Requests, if limiter is not nil.
+// Every request it handles is counted as a tool call, it is meant for the tool invoke APIs.
+// It assumes that the caller was authenticated by the previous middleware.
+func limitToolCallRate(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return rateLimitMiddleware(limiter, func(*gin.Context) int { return 1 })
+}
+
+// limitMcpToolCallRate is the equivalent of limitToolCallRate for the MCP proxies.
+// Only the tools/call requests are counted: the other MCP messages (eg- initialize, tools/list, ping & notifications)
+// and the GET requests, which open long-lived streams, don't use the callers' quota.
+func limitMcpToolCallRate(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return rateLimitMiddleware(limiter, mcpToolCalls)
+}
+
+// rateLimitMiddleware counts the calls made by a request, as returned by calls, against the limits of limiter.
+func rateLimitMiddleware(limiter *ratelimit.Limiter, calls func(c *gin.Context) int) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	return func(c *gin.Context) {
+		caller := toolCallCaller(c)
+		for n := calls(c); n > 0; n-- {
+			err := limiter.Allow(c.Request.Context(), caller)
+			var exceeded *ratelimit.ExceededError
+			if errors.As(err, &exceeded) {
+				metrics.ToolCallsRateLimited.WithLabelValues(exceeded.Limit.Name).Inc()
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(exceeded.RetryAfter.Seconds()))))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// mcpToolCalls returns the number of tools/call requests in the JSON-RPC message, or batch of messages,
+// sent to an MCP proxy. The body of the request is restored for the proxy to read.
+func mcpToolCalls(c *gin.Context) int {
+	if c.Request.Method != http.MethodPost || c.Request.Body == nil {
+		return 0
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil {
+		return 0
+	}
+
+	type message struct {
+		Method string `json:"method"`
+	}
+	var messages []message
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return 0
+		}
+	} else {
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return 0
+		}
+		messages = append(messages, msg)
+	}
+	n := 0
+	for _, msg := range messages {
+		if msg.Method == string(mcp.MethodToolsCall) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMcpToolCallRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := ratelimit.NewLimiter(nil, ratelimit.Limit{Name: "daily quota", Calls: 2, Window: 24 * time.Hour})
+	r := gin.New()
+	r.POST("/mcp", limitMcpToolCallRate(limiter), func(c *gin.Context) {
+		// the proxy still reads the whole message
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	send := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+		return w
+	}
+
+	for _, body := range []string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "ping"}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "echo"}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "tools/list"}`,
+	} {
+		if w := send(body); w.Code != http.StatusOK || w.Body.String() != body {
+			t.Fatalf("expected %s to be proxied, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	// a batch uses the quota once per tool call
+	batch := `[{"jsonrpc": "2.0", "id": 6, "method": "tools/call", "params": {"name": "echo"}},
+		{"jsonrpc": "2.0", "id": 7, "method": "tools/call", "params": {"name": "echo"}}]`
+	if w := send(batch); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the batch to exceed the quota, got %d", w.Code)
+	}
+	if w := send(`{"jsonrpc": "2.0", "id": 8, "method": "tools/list"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected tools/list to be allowed once the quota is used, got %d", w.Code)
+	}
+	if w := send(`{"jsonrpc": "2.0", "id": 9, "method": "tools/call", "params": {"name": "echo"}}`); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected tools/call to be rejected once the quota is used, got %d", w.Code)
+	}
+}
+
+func TestImpersonatedCallerUsesItsQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.McpClient{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"billing", "support"} {
+		if err := db.Create(&model.McpClient{Name: name, AccessToken: name, AllowList: []byte("[]")}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	limiter := ratelimit.NewLimiter(nil, ratelimit.Limit{Name: "daily quota", Calls: 1, Window: 24 * time.Hour})
+	admin := &model.User{Username: "admin", Role: types.UserRoleAdmin}
+	r := gin.New()
+	// same order as the tool invoke routes
+	r.POST(
+		"/tools/invoke",
+		func(c *gin.Context) {
+			c.Set("mode", model.ModeProd)
+			c.Set("user", admin)
+		},
+		impersonateCaller(mcp_client.NewMCPClientService(db), nil, nil),
+		limitToolCallRate(limiter),
+		func(c *gin.Context) { c.Status(http.StatusOK) },
+	)
+	invoke := func(impersonated string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/tools/invoke", strings.NewReader(`{"name": "echo"}`))
+		if impersonated != "" {
+			req.Header.Set(types.ImpersonateClientHeader, impersonated)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := invoke("billing"); code != http.StatusOK {
+		t.Fatalf("expected the first call on behalf of billing to be allowed, got %d", code)
+	}
+	if code := invoke("billing"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second call on behalf of billing to exceed its quota, got %d", code)
+	}
+	// the quotas of the other callers are untouched
+	if code := invoke("support"); code != http.StatusOK {
+		t.Fatalf("expected a call on behalf of support to be allowed, got %d", code)
+	}
+	if code := invoke(""); code != http.StatusOK {
+		t.Fatalf("expected the admin's own call to be allowed, got %d", code)
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+func listReplaySuitesHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suites, err := mcpService.ListReplaySuites()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, suites)
+	}
+}
+
+// createReplaySuiteHandler records a new replay suite by calling the tools of an MCP server.
+func createReplaySuiteHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.CreateReplaySuiteInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		suite, err := mcpService.CreateReplaySuite(c, &input)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, suite)
+	}
+}
+
+func deleteReplaySuiteHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := mcpService.DeleteReplaySuite(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// runReplaySuiteHandler replays the calls of a replay suite and responds with the diff report.
+// The request body optionally contains the MCP server to replay the calls against.
+func runReplaySuiteHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.RunReplaySuiteInput
+		if err := json.NewDecoder(c.Request.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(
+				http.StatusBadRequest,
+				gin.H{"error": "failed to decode request body: " + err.Error()},
+			)
+			return
+		}
+
+		report, err := mcpService.RunReplaySuite(c, c.Param("name"), input.Server)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
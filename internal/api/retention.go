@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/retention"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// pruneHandler deletes old rows from the history tables, according to the configured retention policies
+// or to the policies given in the request body.
+func pruneHandler(retentionService *retention.RetentionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.PruneInput
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		results, err := retentionService.Prune(input.Policies)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, results)
+	}
+}
+
+// purgeSubjectHandler deletes the stored data associated with an MCP client or user
+// and responds with the verification report.
+func purgeSubjectHandler(retentionService *retention.RetentionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var subject types.DataSubject
+		if err := c.ShouldBindJSON(&subject); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if err := subject.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err := retentionService.PurgeSubject(subject)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"gorm.io/gorm"
+)
+
+func listSavedCallsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		calls, err := mcpService.ListSavedCalls()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, calls)
+	}
+}
+
+func createSavedCallHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var s model.SavedCall
+		if err := c.ShouldBindJSON(&s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if err := mcpService.CreateSavedCall(&s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, s)
+	}
+}
+
+func deleteSavedCallHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := mcpService.DeleteSavedCall(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// invokeSavedCallHandler invokes the tool of a saved call.
+// The request body optionally contains arguments that take precedence over the saved ones.
+func invokeSavedCallHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var args map[string]any
+		if err := json.NewDecoder(c.Request.Body).Decode(&args); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(
+				http.StatusBadRequest,
+				gin.H{"error": "failed to decode request body: " + err.Error()},
+			)
+			return
+		}
+
+		name := c.Param("name")
+		resp, err := mcpService.InvokeSavedCall(c, name, args)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(invokeErrorStatus(err), gin.H{"error": "failed to invoke saved call: " + err.Error()})
+			return
+		}
+		writeToolResult(c, resp)
+	}
+}
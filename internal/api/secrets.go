@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/secret"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func toSecret(s *model.Secret) types.Secret {
+	return types.Secret{Name: s.Name, Version: s.Version, UpdatedAt: s.UpdatedAt}
+}
+
+func listSecretsHandler(secretService *secret.SecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secrets, err := secretService.List()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		resp := make([]types.Secret, len(secrets))
+		for i, s := range secrets {
+			resp[i] = toSecret(s)
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// setSecretHandler creates a secret or rotates its value.
+// The MCP servers kept warm that use the secret are restarted so that they pick up its new value.
+func setSecretHandler(secretService *secret.SecretService, mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetSecretInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		s, err := secretService.Set(input.Name, input.Value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		servers, err := mcpService.ReconnectServersUsingSecret(s.Name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, types.SetSecretResult{Secret: toSecret(s), Servers: servers})
+	}
+}
+
+// deleteSecretHandler deletes a secret, unless MCP servers still refer to it.
+func deleteSecretHandler(secretService *secret.SecretService, mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		servers, err := mcpService.ServersUsingSecret(name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(servers) > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "secret " + name + " is used by MCP server " + servers[0].Name + ", update its configuration first",
+			})
+			return
+		}
+		if err := secretService.Delete(name); err != nil {
+			if errors.Is(err, secret.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
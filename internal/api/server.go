@@ -1,14 +1,30 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/retention"
+	"github.com/mcpjungle/mcpjungle/internal/service/secret"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
 )
 
 const V0PathPrefix = "/api/v0"
@@ -17,17 +33,58 @@ type ServerOptions struct {
 	// Port is the HTTP ports to bind the server to
 	Port string
 
+	// TrustedProxies contains the networks of reverse proxies whose X-Forwarded-For headers are trusted
+	// to determine the IP address of the caller. If empty, the IP of the direct peer is used.
+	TrustedProxies []string
+
 	MCPProxyServer   *server.MCPServer
+	SessionManager   *mcp.SessionManager
 	MCPService       *mcp.MCPService
 	MCPClientService *mcp_client.McpClientService
 	ConfigService    *config.ServerConfigService
 	UserService      *user.UserService
+	UsageService     *usage.UsageService
+	PolicyService    *policy.PolicyService
+	WebhookService   *webhook.WebhookService
+	FeatureService   *feature.FeatureService
+
+	NotificationService *notification.NotificationService
+
+	// AuditService receives the audit trail of admin requests & impersonation, it can be nil
+	AuditService *audit.AuditService
+
+	RetentionService *retention.RetentionService
+	SecretService    *secret.SecretService
+
+	// MaxConcurrentToolCalls & MaxConcurrentAPIRequests limit the number of requests served at a time by the
+	// MCP proxies & tool invoke APIs, and by the rest of the API. 0 means no limit.
+	MaxConcurrentToolCalls   int
+	MaxConcurrentAPIRequests int
+
+	// ToolCallQueue configures the fair queuing of the tool calls beyond MaxConcurrentToolCalls
+	ToolCallQueue FairQueueOptions
+
+	// ToolCallRateLimiter limits the rate of the tool calls of every caller, nil means no limit
+	ToolCallRateLimiter *ratelimit.Limiter
+
+	// TLSConfig makes the server serve HTTPS with this configuration, to authenticate MCP clients with their
+	// SPIFFE SVIDs. The server serves plain HTTP if it is nil.
+	TLSConfig *tls.Config
+
+	// EnableProfiling mounts the pprof endpoints on /debug/pprof, they are only accessible to admins
+	EnableProfiling bool
+
+	// Version is the version of mcpjungle and Config contains the environment variables that configure the server,
+	// both are reported in support bundles
+	Version string
+	Config  map[string]string
 }
 
 // Server represents the MCPJungle registry server that handles MCP proxy and API requests
 type Server struct {
 	port   string
 	router *gin.Engine
+	tls    *tls.Config
 
 	mcpProxyServer   *server.MCPServer
 	mcpService       *mcp.MCPService
@@ -46,6 +103,7 @@ func NewServer(opts *ServerOptions) (*Server, error) {
 	s := &Server{
 		port:             opts.Port,
 		router:           r,
+		tls:              opts.TLSConfig,
 		mcpProxyServer:   opts.MCPProxyServer,
 		mcpService:       opts.MCPService,
 		mcpClientService: opts.MCPClientService,
@@ -90,10 +148,54 @@ func (s *Server) InitDev() error {
 	return nil
 }
 
-// Start runs the Gin server (blocking call)
-func (s *Server) Start() error {
-	if err := s.router.Run(":" + s.port); err != nil {
+// RegisterServers registers the given MCP servers along with their tools.
+// It stops at the first server that fails to register.
+func (s *Server) RegisterServers(ctx context.Context, inputs []types.RegisterServerInput) error {
+	for _, input := range inputs {
+		server, err := newServerModelFromInput(&input)
+		if err != nil {
+			return fmt.Errorf("invalid configuration of MCP server %s: %w", input.Name, err)
+		}
+		if err := s.mcpService.RegisterMcpServer(ctx, server); err != nil {
+			return fmt.Errorf("failed to register MCP server %s: %w", input.Name, err)
+		}
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler that serves the API and the MCP proxy, eg- to serve it from a test server.
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// ShutdownTimeout is the time that in-flight requests are given to complete when the server shuts down
+const ShutdownTimeout = 10 * time.Second
+
+// Start runs the Gin server until ctx is cancelled (blocking call).
+// It then shuts the server down gracefully: it stops accepting connections and waits up to
+// ShutdownTimeout for the in-flight requests to complete.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{Addr: ":" + s.port, Handler: s.router, TLSConfig: s.tls}
+	errCh := make(chan error, 1)
+	go func() {
+		if s.tls != nil {
+			// the certificate is provided by the TLS configuration
+			errCh <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
 		return fmt.Errorf("failed to run the server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the server gracefully: %w", err)
 	}
 	return nil
 }
@@ -102,6 +204,9 @@ func (s *Server) Start() error {
 func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 
 	r.GET(
 		"/health",
@@ -110,22 +215,98 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		},
 	)
 
+	// readiness endpoint, reports ready once MCP servers marked keep_warm have been connected to
+	r.GET(
+		"/ready",
+		func(c *gin.Context) {
+			if !opts.MCPService.Ready() {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"status": "warming up"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "ready"})
+		},
+	)
+
+	r.GET("/metrics", gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+
+	// public keys that verify the signatures of tool results, see MCPService.EnableResultSigning
+	r.GET(
+		"/.well-known/jwks.json",
+		func(c *gin.Context) {
+			c.JSON(http.StatusOK, opts.MCPService.ResultSigningKeys())
+		},
+	)
+
+	if opts.EnableProfiling {
+		registerProfilingHandlers(r, opts.ConfigService, opts.UserService)
+	}
+
 	r.POST("/init", registerInitServerHandler(opts.ConfigService, opts.UserService))
 
+	// metadata of the server, served without authentication so that clients can adapt to the server's version
+	r.GET(
+		V0PathPrefix+"/meta",
+		getMetadataHandler(opts.Version, opts.ConfigService, opts.FeatureService, opts.TLSConfig != nil),
+	)
+
 	requireProdMode := requireServerMode(model.ModeProd)
 
+	// tool calls & the other API requests are limited separately, so that admins can still operate during a flood of calls
+	// tool calls are limited once their caller is authenticated (and impersonated, if an admin calls on their behalf),
+	// to share the capacity fairly between callers and use the quota of the caller that the call is made for
+	// callers over their rate limit are rejected before they take a slot
+	rateLimitToolCalls := limitToolCallRate(opts.ToolCallRateLimiter)
+	rateLimitMcpToolCalls := limitMcpToolCallRate(opts.ToolCallRateLimiter)
+	limitToolCalls := limitToolCallsFairly(opts.MaxConcurrentToolCalls, opts.ToolCallQueue)
+	limitAPIRequests := limitConcurrency(
+		poolAPI,
+		opts.MaxConcurrentAPIRequests,
+		V0PathPrefix+"/tools/invoke",
+		V0PathPrefix+"/saved-calls/:name/invoke",
+	)
+
 	// Set up the MCP proxy server on /mcp
-	streamableHttpServer := server.NewStreamableHTTPServer(opts.MCPProxyServer)
+	var streamableOpts []server.StreamableHTTPOption
+	// sessions are only issued by the session manager if it has limits to enforce, otherwise any replica
+	// of a deployment can serve them
+	if opts.SessionManager != nil && opts.SessionManager.Enforced() {
+		streamableOpts = append(streamableOpts, server.WithSessionIdManager(opts.SessionManager))
+	}
+	streamableHttpServer := server.NewStreamableHTTPServer(opts.MCPProxyServer, streamableOpts...)
+	toolGroupProxies := newToolGroupProxies(opts.MCPService, streamableOpts)
 	r.Any(
 		"/mcp",
 		requireInitialized(opts.ConfigService),
 		checkAuthForMcpProxyAccess(opts.MCPClientService),
-		gin.WrapH(streamableHttpServer),
+		forgetTerminatedSessions(opts.SessionManager),
+		rateLimitMcpToolCalls,
+		limitToolCalls,
+		locateCaller(),
+		acceptCallTimeout(),
+		selectToolView(opts.UserService),
+		mcpProxyHandler(streamableHttpServer, toolGroupProxies, opts.FeatureService),
+	)
+
+	// Set up the MCP proxies of tool groups on /v0/groups/:name/mcp
+	requireToolGroups := requireFeature(opts.FeatureService, feature.ToolGroups)
+	requireServerProposals := requireFeature(opts.FeatureService, feature.ServerProposals)
+	r.Any(
+		"/v0/groups/:name/mcp",
+		requireInitialized(opts.ConfigService),
+		requireToolGroups,
+		checkAuthForMcpProxyAccess(opts.MCPClientService),
+		forgetTerminatedSessions(opts.SessionManager),
+		rateLimitMcpToolCalls,
+		limitToolCalls,
+		locateCaller(),
+		acceptCallTimeout(),
+		toolGroupMcpProxyHandler(toolGroupProxies),
 	)
 
 	// Setup /v0 API endpoints
 	apiV0 := r.Group(
 		V0PathPrefix,
+		limitAPIRequests,
 		requireInitialized(opts.ConfigService),
 		verifyUserAuthForAPIAccess(opts.UserService),
 	)
@@ -134,22 +315,180 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 	userAPI := apiV0.Group("/")
 	{
 		userAPI.GET("/servers", listServersHandler(opts.MCPService))
+		userAPI.GET("/servers/:name/capabilities", getServerCapabilitiesHandler(opts.MCPService))
+		userAPI.GET("/servers/:name/health", getServerHealthHandler(opts.MCPService))
+		userAPI.GET("/health/servers", getServersHealthHandler(opts.MCPService))
 
 		userAPI.GET("/tools", listToolsHandler(opts.MCPService))
-		userAPI.POST("/tools/invoke", invokeToolHandler(opts.MCPService))
+		userAPI.GET("/tools/lint", lintToolsHandler(opts.MCPService))
+		userAPI.POST(
+			"/tools/invoke",
+			impersonateCaller(opts.MCPClientService, opts.UserService, opts.AuditService),
+			rateLimitToolCalls,
+			limitToolCalls,
+			locateCaller(),
+			acceptCallTimeout(),
+			invokeToolHandler(opts.MCPService),
+		)
 		userAPI.GET("/tool", getToolHandler(opts.MCPService))
 
+		userAPI.GET("/prompts", listPromptsHandler(opts.MCPService))
+
+		userAPI.GET("/saved-calls", listSavedCallsHandler(opts.MCPService))
+		userAPI.POST(
+			"/saved-calls/:name/invoke",
+			impersonateCaller(opts.MCPClientService, opts.UserService, opts.AuditService),
+			rateLimitToolCalls,
+			limitToolCalls,
+			locateCaller(),
+			acceptCallTimeout(),
+			invokeSavedCallHandler(opts.MCPService),
+		)
+
 		userAPI.GET("/users/whoami", requireProdMode, whoAmIHandler())
+
+		// users star tools and define personal views on them (production mode only)
+		userAPI.GET("/users/me/favorites", requireProdMode, listFavoriteToolsHandler(opts.MCPService))
+		userAPI.PUT("/users/me/favorites/:tool", requireProdMode, starToolHandler(opts.MCPService))
+		userAPI.DELETE("/users/me/favorites/:tool", requireProdMode, unstarToolHandler(opts.MCPService))
+		userAPI.GET("/users/me/views", requireProdMode, listToolViewsHandler(opts.MCPService))
+		userAPI.PUT("/users/me/views", requireProdMode, setToolViewHandler(opts.MCPService))
+		userAPI.DELETE("/users/me/views/:name", requireProdMode, deleteToolViewHandler(opts.MCPService))
+
+		// users propose server registrations which admins approve or reject
+		userAPI.GET("/server-proposals", requireServerProposals, listServerProposalsHandler(opts.MCPService))
+		userAPI.POST(
+			"/server-proposals",
+			requireServerProposals,
+			rejectWritesInReadOnlyMode(opts.ConfigService),
+			proposeServerHandler(opts.MCPService, opts.AuditService),
+		)
+
+		userAPI.GET("/read-only", getReadOnlyHandler(opts.ConfigService))
+		userAPI.GET("/features", listFeaturesHandler(opts.FeatureService))
 	}
 
 	// endpoints only accessible by an admin user in production mode or anyone in development mode
-	adminAPI := apiV0.Group("/", requireAdminUser())
+	// all changes made through these endpoints are rejected while the registry is in read-only mode
+	adminAPI := apiV0.Group(
+		"/",
+		requireAdminUser(),
+		auditAdminRequests(opts.AuditService),
+		rejectWritesInReadOnlyMode(
+			opts.ConfigService,
+			// preflight checks don't modify the registry
+			V0PathPrefix+"/servers/preflight",
+			V0PathPrefix+"/read-only",
+			// reading notifications doesn't change the registry
+			V0PathPrefix+"/notifications/read",
+			// the break-glass switch must work during incident freezes
+			V0PathPrefix+"/emergency/disable-all",
+			V0PathPrefix+"/emergency/enable-all",
+		),
+	)
 	{
+		adminAPI.POST("/read-only", setReadOnlyHandler(opts.ConfigService))
+
+		adminAPI.GET("/emergency", getEmergencyHandler(opts.MCPService))
+		adminAPI.POST("/emergency/disable-all", emergencyDisableAllHandler(opts.MCPService))
+		adminAPI.POST("/emergency/enable-all", emergencyEnableAllHandler(opts.MCPService))
+		adminAPI.POST("/features", setFeatureHandler(opts.FeatureService))
+
 		adminAPI.POST("/servers", registerServerHandler(opts.MCPService))
+		adminAPI.POST("/servers/preflight", preflightServerHandler(opts.MCPService))
 		adminAPI.DELETE("/servers/:name", deregisterServerHandler(opts.MCPService))
+		adminAPI.POST("/servers/:name/pin", pinServerHandler(opts.MCPService))
+		adminAPI.POST("/server-groups/:group/stop", serverGroupLifecycleHandler(opts.MCPService, false))
+		adminAPI.POST("/server-groups/:group/start", serverGroupLifecycleHandler(opts.MCPService, true))
+		adminAPI.POST(
+			"/server-proposals/:id/preflight",
+			requireServerProposals,
+			preflightServerProposalHandler(opts.MCPService),
+		)
+		adminAPI.POST(
+			"/server-proposals/:id/approve",
+			requireServerProposals,
+			reviewServerProposalHandler(opts.MCPService, true),
+		)
+		adminAPI.POST(
+			"/server-proposals/:id/reject",
+			requireServerProposals,
+			reviewServerProposalHandler(opts.MCPService, false),
+		)
 
 		adminAPI.POST("/tools/enable", enableToolsHandler(opts.MCPService))
 		adminAPI.POST("/tools/disable", disableToolsHandler(opts.MCPService))
+		adminAPI.POST("/tools/cost", setToolCostHandler(opts.MCPService))
+		adminAPI.POST("/tools/output-schema", setToolOutputSchemaHandler(opts.MCPService))
+		adminAPI.POST("/tools/hedging", setToolHedgingHandler(opts.MCPService))
+		adminAPI.POST("/tools/payload-logging", setToolPayloadLoggingHandler(opts.MCPService))
+		adminAPI.POST("/tools/scripts", setToolScriptsHandler(opts.MCPService))
+		adminAPI.POST("/tools/docs", setToolDocsHandler(opts.MCPService))
+
+		adminAPI.GET("/budgets", listBudgetsHandler(opts.UsageService))
+		adminAPI.POST("/budgets", createBudgetHandler(opts.UsageService))
+		adminAPI.DELETE("/budgets/:name", deleteBudgetHandler(opts.UsageService))
+		adminAPI.GET("/invocations", listInvocationsHandler(opts.UsageService))
+		adminAPI.GET("/usage/tools/top", topToolsHandler(opts.UsageService))
+		adminAPI.GET("/usage/tools/unused", unusedToolsHandler(opts.MCPService, opts.UsageService))
+		adminAPI.GET("/usage/clients", clientBreakdownHandler(opts.UsageService))
+		adminAPI.GET("/metrics/summary", metricsSummaryHandler(opts.MCPService, opts.UsageService))
+
+		adminAPI.GET("/slos", listSLOsHandler(opts.UsageService))
+		adminAPI.POST("/slos", createSLOHandler(opts.UsageService))
+		adminAPI.DELETE("/slos/:name", deleteSLOHandler(opts.UsageService))
+
+		adminAPI.GET("/residency-policies", listResidencyPoliciesHandler(opts.MCPService))
+		adminAPI.POST("/residency-policies", createResidencyPolicyHandler(opts.MCPService))
+		adminAPI.DELETE("/residency-policies/:name", deleteResidencyPolicyHandler(opts.MCPService))
+
+		adminAPI.GET("/access-grants", listAccessGrantsHandler(opts.MCPService))
+		adminAPI.POST("/access-grants", createAccessGrantHandler(opts.MCPService))
+		adminAPI.DELETE("/access-grants/:id", revokeAccessGrantHandler(opts.MCPService))
+
+		adminAPI.GET("/secrets", listSecretsHandler(opts.SecretService))
+		adminAPI.PUT("/secrets", setSecretHandler(opts.SecretService, opts.MCPService))
+		adminAPI.DELETE("/secrets/:name", deleteSecretHandler(opts.SecretService, opts.MCPService))
+
+		adminAPI.GET("/faults", listFaultRulesHandler(opts.MCPService))
+		adminAPI.POST("/faults", createFaultRuleHandler(opts.MCPService))
+		adminAPI.DELETE("/faults/:name", deleteFaultRuleHandler(opts.MCPService))
+
+		adminAPI.POST("/prompts", createPromptHandler(opts.MCPService))
+		adminAPI.POST("/prompts/override", setPromptOverrideHandler(opts.MCPService))
+		adminAPI.DELETE("/prompts/:name", deletePromptHandler(opts.MCPService))
+
+		adminAPI.POST("/prune", pruneHandler(opts.RetentionService))
+		adminAPI.POST("/purge-subject", purgeSubjectHandler(opts.RetentionService))
+		adminAPI.GET("/support-bundle", supportBundleHandler(opts))
+
+		adminAPI.POST("/saved-calls", createSavedCallHandler(opts.MCPService))
+		adminAPI.DELETE("/saved-calls/:name", deleteSavedCallHandler(opts.MCPService))
+
+		adminAPI.GET("/replay-suites", listReplaySuitesHandler(opts.MCPService))
+		adminAPI.POST("/replay-suites", createReplaySuiteHandler(opts.MCPService))
+		adminAPI.POST("/replay-suites/:name/run", runReplaySuiteHandler(opts.MCPService))
+		adminAPI.DELETE("/replay-suites/:name", deleteReplaySuiteHandler(opts.MCPService))
+
+		adminAPI.GET("/policies", listPoliciesHandler(opts.PolicyService))
+		adminAPI.POST("/policies", createPolicyHandler(opts.PolicyService))
+		adminAPI.DELETE("/policies/:name", deletePolicyHandler(opts.PolicyService))
+
+		adminAPI.GET("/tool-groups", requireToolGroups, listToolGroupsHandler(opts.MCPService))
+		adminAPI.POST("/tool-groups", requireToolGroups, createToolGroupHandler(opts.MCPService))
+		adminAPI.DELETE("/tool-groups/:name", requireToolGroups, deleteToolGroupHandler(opts.MCPService, toolGroupProxies))
+
+		adminAPI.GET("/extraction-profiles", requireToolGroups, listExtractionProfilesHandler(opts.MCPService))
+		adminAPI.POST("/extraction-profiles", requireToolGroups, createExtractionProfileHandler(opts.MCPService))
+		adminAPI.DELETE(
+			"/extraction-profiles/:name", requireToolGroups, deleteExtractionProfileHandler(opts.MCPService),
+		)
+
+		adminAPI.GET("/notifications", listNotificationsHandler(opts.NotificationService))
+		adminAPI.POST("/notifications/read", markNotificationsReadHandler(opts.NotificationService))
+
+		adminAPI.GET("/webhook-keys", listWebhookKeysHandler(opts.WebhookService))
+		adminAPI.POST("/webhook-keys", rotateWebhookKeyHandler(opts.WebhookService))
 
 		// endpoints for managing MCP clients (production mode only)
 		adminAPI.GET(
@@ -160,7 +499,7 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		adminAPI.POST(
 			"/clients",
 			requireProdMode,
-			createMcpClientHandler(opts.MCPClientService),
+			createMcpClientHandler(opts.MCPClientService, opts.MCPService),
 		)
 		adminAPI.DELETE(
 			"/clients/:name",
@@ -2,9 +2,13 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"net/http"
 )
 
 func registerInitServerHandler(configService *config.ServerConfigService, userService *user.UserService) gin.HandlerFunc {
@@ -47,3 +51,68 @@ func registerInitServerHandler(configService *config.ServerConfigService, userSe
 		c.JSON(200, payload)
 	}
 }
+
+func getReadOnlyHandler(configService *config.ServerConfigService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled, forced, err := configService.ReadOnly()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, types.ReadOnlyStatus{ReadOnly: enabled, Forced: forced})
+	}
+}
+
+func setReadOnlyHandler(configService *config.ServerConfigService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SetReadOnlyInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+		if err := configService.SetReadOnly(input.Enabled); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, types.ReadOnlyStatus{ReadOnly: input.Enabled})
+	}
+}
+
+// getMetadataHandler describes the server to clients. It doesn't require authentication nor initialization,
+// so that clients can check their compatibility with the server before anything else.
+// spiffeEnabled is true if the server serves HTTPS with its SPIFFE SVID, so that MCP clients can authenticate with theirs.
+func getMetadataHandler(
+	version string, configService *config.ServerConfigService, featureService *feature.FeatureService, spiffeEnabled bool,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meta := types.ServerMetadata{
+			Version:          version,
+			APIVersions:      []string{"v0"},
+			ProtocolVersions: mcp.ValidProtocolVersions,
+			Features:         []string{},
+		}
+		for _, f := range featureService.ListFeatures() {
+			if f.Enabled {
+				meta.Features = append(meta.Features, f.Name)
+			}
+		}
+		cfg, err := configService.GetConfig()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if cfg.Initialized {
+			meta.Initialized = true
+			meta.Mode = string(cfg.Mode)
+			if cfg.Mode == model.ModeProd {
+				meta.AuthModes = []string{types.AuthModeUserToken, types.AuthModeMcpClientToken}
+				if spiffeEnabled {
+					meta.AuthModes = append(meta.AuthModes, types.AuthModeSpiffeSVID)
+				}
+			} else {
+				meta.AuthModes = []string{types.AuthModeNone}
+			}
+		}
+		c.JSON(http.StatusOK, meta)
+	}
+}
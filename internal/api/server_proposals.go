@@ -0,0 +1,164 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// currentUser returns the authenticated user, nil in development mode.
+func currentUser(c *gin.Context) *model.User {
+	u, _ := c.Get("user")
+	user, _ := u.(*model.User)
+	return user
+}
+
+// proposeServerHandler records a server registration for an admin to approve.
+// The proposal is recorded in the audit trail.
+func proposeServerHandler(mcpService *mcp.MCPService, auditService *audit.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var proposedBy string
+		if u := currentUser(c); u != nil {
+			proposedBy = u.Username
+		}
+		defer func() {
+			auditService.Record(audit.Event{
+				Type:   audit.EventServerProposal,
+				User:   proposedBy,
+				Method: c.Request.Method,
+				Path:   c.Request.URL.Path,
+				Status: c.Writer.Status(),
+			})
+		}()
+
+		var input types.RegisterServerInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		server, err := newServerModelFromInput(&input)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		p, err := mcpService.ProposeMcpServer(server, proposedBy)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondServerProposal(c, http.StatusCreated, p)
+	}
+}
+
+// listServerProposalsHandler lists the proposals, optionally filtered by status.
+// Admins see all proposals, other users only see their own.
+func listServerProposalsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var proposedBy string
+		if u := currentUser(c); u != nil && u.Role != types.UserRoleAdmin {
+			proposedBy = u.Username
+		}
+		records, err := mcpService.ListServerProposals(types.ServerProposalStatus(c.Query("status")), proposedBy)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		proposals := make([]*types.ServerProposal, 0, len(records))
+		for _, r := range records {
+			p, err := mcp.DescribeServerProposal(r)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			proposals = append(proposals, p)
+		}
+		c.JSON(http.StatusOK, proposals)
+	}
+}
+
+// preflightServerProposalHandler test-connects to the server of a pending proposal and records what it provides.
+func preflightServerProposalHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid proposal id: " + c.Param("id")})
+			return
+		}
+		p, err := mcpService.PreflightServerProposal(c, uint(id))
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, mcp.ErrProposalNotPending):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		respondServerProposal(c, http.StatusOK, p)
+	}
+}
+
+// reviewServerProposalHandler approves or rejects a pending proposal.
+func reviewServerProposalHandler(mcpService *mcp.MCPService, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid proposal id: " + c.Param("id")})
+			return
+		}
+		var input types.ReviewServerProposalInput
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+				return
+			}
+		}
+		var reviewer string
+		if u := currentUser(c); u != nil {
+			reviewer = u.Username
+		}
+
+		if approve {
+			_, err = mcpService.ApproveServerProposal(c, uint(id), reviewer, input.Comment)
+		} else {
+			err = mcpService.RejectServerProposal(uint(id), reviewer, input.Comment)
+		}
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, mcp.ErrProposalNotPending):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+
+		p, err := mcpService.GetServerProposal(uint(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		respondServerProposal(c, http.StatusOK, p)
+	}
+}
+
+func respondServerProposal(c *gin.Context, status int, p *model.ServerProposal) {
+	resp, err := mcp.DescribeServerProposal(p)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(status, resp)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+)
+
+func listSLOsHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slos, err := usageService.ListSLOs()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, slos)
+	}
+}
+
+func createSLOHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var s model.SLO
+		if err := c.ShouldBindJSON(&s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if s.LatencyPercentile == 0 {
+			s.LatencyPercentile = 95
+		}
+		if s.Window == "" {
+			s.Window = "1d"
+		}
+		if err := usageService.CreateSLO(&s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, s)
+	}
+}
+
+func deleteSLOHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := usageService.DeleteSLO(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
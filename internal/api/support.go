@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/support"
+	"github.com/prometheus/common/expfmt"
+)
+
+// supportBundleHandler responds with a support bundle (a gzipped tarball) describing the state of the server.
+// Secrets are redacted from every file of the bundle.
+func supportBundleHandler(opts *ServerOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var buf bytes.Buffer
+		if err := writeSupportBundle(&buf, opts); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create support bundle: " + err.Error()})
+			return
+		}
+		name := fmt.Sprintf("mcpjungle-support-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+		c.Header("Content-Disposition", "attachment; filename="+name)
+		c.Data(http.StatusOK, "application/gzip", buf.Bytes())
+	}
+}
+
+func writeSupportBundle(buf *bytes.Buffer, opts *ServerOptions) error {
+	b := support.NewBundle(buf)
+
+	if err := b.AddJSON("version.json", gin.H{
+		"version":    opts.Version,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}); err != nil {
+		return err
+	}
+
+	serverConfig, err := opts.ConfigService.GetConfig()
+	if err != nil {
+		return err
+	}
+	readOnly, forced, err := opts.ConfigService.ReadOnly()
+	if err != nil {
+		return err
+	}
+	err = b.AddJSON("config.json", gin.H{
+		"mode":             serverConfig.Mode,
+		"initialized":      serverConfig.Initialized,
+		"read_only":        readOnly,
+		"read_only_forced": forced,
+		"features":         opts.FeatureService.ListFeatures(),
+		"environment":      opts.Config,
+		"trusted_proxies":  opts.TrustedProxies,
+		"port":             opts.Port,
+	})
+	if err != nil {
+		return err
+	}
+
+	servers, err := opts.MCPService.ListMcpServers()
+	if err != nil {
+		return err
+	}
+	sanitized := make([]map[string]any, 0, len(servers))
+	for _, s := range servers {
+		var cfg map[string]any
+		_ = json.Unmarshal(s.Config, &cfg)
+		// the environment of STDIO servers is mostly made of secrets, whatever the names of the variables
+		if env, ok := cfg["env"].(map[string]any); ok {
+			for k := range env {
+				env[k] = support.Redacted
+			}
+		}
+		sanitized = append(sanitized, map[string]any{
+			"name":             s.Name,
+			"transport":        s.Transport,
+			"description":      s.Description,
+			"config":           cfg,
+			"protocol_version": s.ProtocolVersion,
+		})
+	}
+	if err := b.AddJSON("servers.json", sanitized); err != nil {
+		return err
+	}
+
+	err = b.AddJSON("health.json", gin.H{
+		"ready":             opts.MCPService.Ready(),
+		"unhealthy_servers": opts.MCPService.UnhealthyServers(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := b.Add("logs.txt", []byte(support.Logs.String())); err != nil {
+		return err
+	}
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return err
+	}
+	var m bytes.Buffer
+	for _, f := range families {
+		if _, err := expfmt.MetricFamilyToText(&m, f); err != nil {
+			return err
+		}
+	}
+	if err := b.Add("metrics.txt", m.Bytes()); err != nil {
+		return err
+	}
+
+	return b.Close()
+}
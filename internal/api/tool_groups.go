@@ -0,0 +1,166 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"net/http"
+	"sync"
+)
+
+func listToolGroupsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groups, err := mcpService.ListToolGroups()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, groups)
+	}
+}
+
+func createToolGroupHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var g model.ToolGroup
+		if err := c.ShouldBindJSON(&g); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if err := mcpService.CreateToolGroup(&g); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, g)
+	}
+}
+
+func deleteToolGroupHandler(mcpService *mcp.MCPService, proxies *toolGroupProxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := mcpService.DeleteToolGroup(name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		proxies.forget(name)
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func listExtractionProfilesHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profiles, err := mcpService.ListExtractionProfiles()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, profiles)
+	}
+}
+
+func createExtractionProfileHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var p model.ExtractionProfile
+		if err := c.ShouldBindJSON(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if err := mcpService.CreateExtractionProfile(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, p)
+	}
+}
+
+func deleteExtractionProfileHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if err := mcpService.DeleteExtractionProfile(name); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// toolGroupProxies serves the MCP proxies of tool groups over streamable HTTP.
+// The streamable HTTP transport of each group's proxy is created on first use and re-used afterward,
+// until the group's proxy is dropped.
+type toolGroupProxies struct {
+	mcpService *mcp.MCPService
+	opts       []server.StreamableHTTPOption
+
+	mu sync.Mutex
+	// transports is keyed by the name of the group
+	transports map[string]toolGroupTransport
+}
+
+// toolGroupTransport is the streamable HTTP transport of the MCP proxy server of a tool group
+type toolGroupTransport struct {
+	mcpServer *server.MCPServer
+	transport *server.StreamableHTTPServer
+}
+
+func newToolGroupProxies(mcpService *mcp.MCPService, opts []server.StreamableHTTPOption) *toolGroupProxies {
+	return &toolGroupProxies{
+		mcpService: mcpService,
+		opts:       opts,
+		transports: make(map[string]toolGroupTransport),
+	}
+}
+
+// serve handles an MCP request to the proxy of the given tool group.
+func (p *toolGroupProxies) serve(c *gin.Context, name string) {
+	s, ok := p.mcpService.GetToolGroupProxy(name)
+	if !ok {
+		p.forget(name)
+		c.JSON(http.StatusNotFound, gin.H{"error": "tool group " + name + " not found"})
+		return
+	}
+
+	p.mu.Lock()
+	t, ok := p.transports[name]
+	if !ok || t.mcpServer != s {
+		// the group was re-created since its transport was, which still serves the dropped proxy server
+		t = toolGroupTransport{mcpServer: s, transport: server.NewStreamableHTTPServer(s, p.opts...)}
+		p.transports[name] = t
+	}
+	p.mu.Unlock()
+
+	t.transport.ServeHTTP(c.Writer, c.Request)
+}
+
+// forget drops the transport of a tool group whose proxy server was dropped, eg- because the group was deleted.
+func (p *toolGroupProxies) forget(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.transports, name)
+}
+
+// toolGroupMcpProxyHandler serves the MCP proxy of the tool group named in the request path.
+func toolGroupMcpProxyHandler(proxies *toolGroupProxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		proxies.serve(c, c.Param("name"))
+	}
+}
+
+// mcpProxyHandler serves the MCP proxy on /mcp. MCP clients bound to a tool group are served the proxy of
+// their group instead, so that they don't need to know the group's URL.
+func mcpProxyHandler(
+	transport http.Handler, proxies *toolGroupProxies, featureService *feature.FeatureService,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, ok := c.Request.Context().Value("client").(*model.McpClient)
+		if !ok || client.ToolGroup == "" {
+			transport.ServeHTTP(c.Writer, c.Request)
+			return
+		}
+		if !featureService.Enabled(feature.ToolGroups) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "feature " + feature.ToolGroups + " is not enabled"})
+			return
+		}
+		proxies.serve(c, client.ToolGroup)
+	}
+}
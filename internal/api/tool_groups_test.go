@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestToolGroupProxyTransportsArePruned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection to an in-memory sqlite database gets a new database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	mcpService, err := mcp.NewMCPService(
+		db, server.NewMCPServer("proxy", "0.0.1", server.WithToolCapabilities(true)), nil, nil, nil, nil, nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := model.NewStreamableHTTPServer("warehouse", "", "http://127.0.0.1:1/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&model.Tool{ServerID: s.ID, Name: "query", InputSchema: []byte(`{"type": "object"}`)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	createGroup := func() {
+		g := &model.ToolGroup{Name: "analytics", IncludedTools: []byte(`["warehouse__query"]`)}
+		if err := mcpService.CreateToolGroup(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createGroup()
+
+	proxies := newToolGroupProxies(mcpService, nil)
+	r := gin.New()
+	r.POST("/v0/groups/:name/mcp", toolGroupMcpProxyHandler(proxies))
+	r.DELETE("/tool-groups/:name", deleteToolGroupHandler(mcpService, proxies))
+	initialize := func() int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v0/groups/analytics/mcp", strings.NewReader(
+			`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`,
+		))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	transport := func() (toolGroupTransport, bool) {
+		proxies.mu.Lock()
+		defer proxies.mu.Unlock()
+		t, ok := proxies.transports["analytics"]
+		return t, ok
+	}
+
+	if code := initialize(); code != http.StatusOK {
+		t.Fatalf("expected the group's proxy to be served, got %d", code)
+	}
+	first, ok := transport()
+	if !ok {
+		t.Fatal("expected the transport of the group's proxy to be kept")
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tool-groups/analytics", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected the group to be deleted, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, ok := transport(); ok {
+		t.Fatal("expected the transport of the deleted group's proxy to be dropped")
+	}
+	if code := initialize(); code != http.StatusNotFound {
+		t.Fatalf("expected the deleted group's proxy not to be served, got %d", code)
+	}
+
+	// a re-created group is served by a new transport, for its new proxy server
+	createGroup()
+	if code := initialize(); code != http.StatusOK {
+		t.Fatalf("expected the re-created group's proxy to be served, got %d", code)
+	}
+	if second, ok := transport(); !ok || second.mcpServer == first.mcpServer {
+		t.Fatal("expected the re-created group to be served by its new proxy server")
+	}
+}
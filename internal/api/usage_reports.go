@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// lookbackStart returns the start of the period given in the 'since' query parameter (eg- "30d"),
+// or the start of the default period if the parameter is not set.
+func lookbackStart(c *gin.Context, defaultPeriod string) (time.Time, bool) {
+	since := c.DefaultQuery("since", defaultPeriod)
+	d, err := types.ParseLookback(since)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'since' query parameter: " + err.Error()})
+		return time.Time{}, false
+	}
+	return time.Now().Add(-d), true
+}
+
+func topToolsHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since, ok := lookbackStart(c, "7d")
+		if !ok {
+			return
+		}
+		limit := 10
+		if l := c.Query("limit"); l != "" {
+			v, err := strconv.Atoi(l)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'limit' query parameter must be a number"})
+				return
+			}
+			limit = v
+		}
+		top, err := usageService.TopTools(since, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, top)
+	}
+}
+
+func unusedToolsHandler(mcpService *mcp.MCPService, usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since, ok := lookbackStart(c, "30d")
+		if !ok {
+			return
+		}
+		tools, err := mcpService.ListTools()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		unused, err := usageService.UnusedTools(tools, since)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, unused)
+	}
+}
+
+// clientBreakdownHandler breaks the MCP proxy sessions down by client application, protocol version & capability
+func clientBreakdownHandler(usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since, ok := lookbackStart(c, "30d")
+		if !ok {
+			return
+		}
+		breakdown, err := usageService.ClientBreakdown(since)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, breakdown)
+	}
+}
+
+func metricsSummaryHandler(mcpService *mcp.MCPService, usageService *usage.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since, ok := lookbackStart(c, "1d")
+		if !ok {
+			return
+		}
+		groups, err := mcpService.ListToolGroups()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		summary, err := usageService.MetricsSummary(since, groups)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, summary)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func listWebhookKeysHandler(webhookService *webhook.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys, err := webhookService.ListKeys()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, keys)
+	}
+}
+
+func rotateWebhookKeyHandler(webhookService *webhook.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.RotateWebhookKeyInput
+		// the request body is optional
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+				return
+			}
+		}
+		var grace time.Duration
+		if input.GracePeriod != "" {
+			d, err := time.ParseDuration(input.GracePeriod)
+			if err != nil || d < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid grace period: " + input.GracePeriod})
+				return
+			}
+			grace = d
+		}
+		key, err := webhookService.RotateKey(grace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, key)
+	}
+}
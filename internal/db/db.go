@@ -1,21 +1,45 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"gorm.io/gorm/logger"
 	"log"
+	"time"
 
 	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // TODO: Turn this into a singleton class.
 // Only one database connection should be created and used throughout the application.
 
+// Options tunes the connection pool of a database connection.
+// Zero values keep the defaults of database/sql.
+type Options struct {
+	// MaxOpenConns is the maximum number of open connections to the database
+	MaxOpenConns int
+	// MaxIdleConns is the maximum number of connections kept open while idle
+	MaxIdleConns int
+	// ConnMaxLifetime is the maximum time a connection may be reused
+	ConnMaxLifetime time.Duration
+	// ConnMaxIdleTime is the maximum time a connection may be idle before it is closed
+	ConnMaxIdleTime time.Duration
+
+	// SlowQueryThreshold is the duration beyond which queries are logged as slow, 0 disables the log
+	SlowQueryThreshold time.Duration
+
+	// ReadReplicaDSN is the DSN of a read replica of the (PostgreSQL) database, which serves the queries
+	// marked with ReadReplica. The same connection pool settings apply to it.
+	ReadReplicaDSN string
+}
+
 // NewDBConnection creates a new database connection based on the provided DSN.
 // If the DSN is empty, it falls back to an embedded SQLite database at "./mcp.db".
-func NewDBConnection(dsn string) (*gorm.DB, error) {
+func NewDBConnection(dsn string, opts Options) (*gorm.DB, error) {
 	var dialector gorm.Dialector
 	if dsn == "" {
 		log.Println("[db] DATABASE_URL not set – falling back to embedded SQLite ./mcp.db")
@@ -25,11 +49,101 @@ func NewDBConnection(dsn string) (*gorm.DB, error) {
 	}
 
 	c := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger: newLogger(opts.SlowQueryThreshold),
 	}
 	db, err := gorm.Open(dialector, c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	configurePool(sqlDB, opts)
+
+	if opts.ReadReplicaDSN != "" {
+		if dsn == "" {
+			return nil, fmt.Errorf("a read replica is only supported with a PostgreSQL database")
+		}
+		// don't fail the startup if the replica is down, its queries fall back to the primary until it is up
+		replica, err := gorm.Open(postgres.Open(opts.ReadReplicaDSN), &gorm.Config{DisableAutomaticPing: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		replicaDB, err := replica.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		configurePool(replicaDB, opts)
+		if _, err := useReadReplica(context.Background(), db, replicaDB); err != nil {
+			return nil, fmt.Errorf("failed to set up read replica: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// configurePool applies the connection pool settings of opts to a database handle.
+func configurePool(sqlDB *sql.DB, opts Options) {
+	if opts.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if opts.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
+}
+
+// NewInMemoryDBConnection creates a connection to an embedded SQLite database that lives in memory only.
+// Its contents are lost when the process exits.
+func NewInMemoryDBConnection() (*gorm.DB, error) {
+	c := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	}
+	db, err := gorm.Open(sqlite.Open(":memory:"), c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-memory database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-memory database: %w", err)
+	}
+	// every connection to ":memory:" opens a separate database, so all queries must share a single connection
+	sqlDB.SetMaxOpenConns(1)
 	return db, nil
 }
+
+// newLogger returns the gorm logger of a connection.
+// Queries are only logged if they take longer than slowThreshold, nothing is logged if it is 0.
+func newLogger(slowThreshold time.Duration) logger.Interface {
+	silent := logger.Default.LogMode(logger.Silent)
+	if slowThreshold <= 0 {
+		return silent
+	}
+	return &slowQueryLogger{Interface: silent, threshold: slowThreshold}
+}
+
+// slowQueryLogger logs the queries that exceed a threshold and counts them in the
+// mcpjungle_db_slow_queries_total metric. Everything else is discarded.
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+}
+
+func (l *slowQueryLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	if elapsed < l.threshold {
+		return
+	}
+	metrics.DBSlowQueries.Inc()
+	sql, rows := fc()
+	log.Printf("[db] slow query took %s (%d rows): %s", elapsed.Round(time.Millisecond), rows, sql)
+}
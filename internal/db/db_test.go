@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gorm.io/gorm"
+)
+
+func TestSlowQueryLogger(t *testing.T) {
+	l := newLogger(100 * time.Millisecond)
+	before := testutil.ToFloat64(metrics.DBSlowQueries)
+
+	fc := func() (string, int64) { return "SELECT 1", 1 }
+	l.Trace(context.Background(), time.Now(), fc, nil)
+	if got := testutil.ToFloat64(metrics.DBSlowQueries) - before; got != 0 {
+		t.Fatalf("expected fast query not to be counted, got %v", got)
+	}
+	l.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+	if got := testutil.ToFloat64(metrics.DBSlowQueries) - before; got != 1 {
+		t.Fatalf("expected 1 slow query, got %v", got)
+	}
+
+	if _, ok := newLogger(0).(*slowQueryLogger); ok {
+		t.Fatal("expected slow query log to be disabled with a zero threshold")
+	}
+}
+
+type replicatedItem struct {
+	ID   uint
+	Name string
+}
+
+// openItemsDB opens a SQLite database in dir containing a single item with the given name
+func openItemsDB(t *testing.T, dir, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(dir, name+".db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open %s database: %v", name, err)
+	}
+	if err := db.AutoMigrate(&replicatedItem{}); err != nil {
+		t.Fatalf("failed to migrate %s database: %v", name, err)
+	}
+	if err := db.Create(&replicatedItem{Name: name}).Error; err != nil {
+		t.Fatalf("failed to create item in %s database: %v", name, err)
+	}
+	return db
+}
+
+func TestReadReplica(t *testing.T) {
+	dir := t.TempDir()
+	primary := openItemsDB(t, dir, "primary")
+	replicaDB, err := openItemsDB(t, dir, "replica").DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := useReadReplica(ctx, primary, replicaDB)
+	if err != nil {
+		t.Fatalf("failed to use read replica: %v", err)
+	}
+	if testutil.ToFloat64(metrics.DBReadReplicaUp) != 1 {
+		t.Fatal("expected read replica to be reported up")
+	}
+
+	readName := func(tx *gorm.DB) string {
+		t.Helper()
+		var item replicatedItem
+		if err := tx.First(&item).Error; err != nil {
+			t.Fatalf("failed to read item: %v", err)
+		}
+		return item.Name
+	}
+	if got := readName(ReadReplica(primary)); got != "replica" {
+		t.Fatalf("expected marked query to be served by the replica, got %s", got)
+	}
+	if got := readName(primary); got != "primary" {
+		t.Fatalf("expected unmarked query to be served by the primary, got %s", got)
+	}
+	var count int64
+	if err := ReadReplica(primary).Model(&replicatedItem{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expected count from the replica to be 1, got %d (%v)", count, err)
+	}
+	err = primary.Transaction(func(tx *gorm.DB) error {
+		if got := readName(ReadReplica(tx)); got != "primary" {
+			t.Errorf("expected query in a transaction to be served by the primary, got %s", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// once the replica is down, the marked queries fall back to the primary
+	_ = replicaDB.Close()
+	if err := r.check(ctx); err == nil {
+		t.Fatal("expected health check of the closed replica to fail")
+	}
+	if testutil.ToFloat64(metrics.DBReadReplicaUp) != 0 {
+		t.Fatal("expected read replica to be reported down")
+	}
+	if got := readName(ReadReplica(primary)); got != "primary" {
+		t.Fatalf("expected marked query to fall back to the primary, got %s", got)
+	}
+}
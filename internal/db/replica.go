@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// readReplicaKey marks the queries that may be served by the read replica, see ReadReplica
+const readReplicaKey = "mcpjungle:read_replica"
+
+// replicaHealthCheckInterval is how often the read replica is pinged to detect that it is down or back up
+const replicaHealthCheckInterval = 10 * time.Second
+
+// ReadReplica returns tx with its queries routed to the read replica of the database, if one is configured
+// and reachable. Otherwise, the queries go to the primary database.
+// It must only be used for reads that tolerate replication lag, eg- listings & usage reports,
+// never for reads that a write depends on.
+func ReadReplica(tx *gorm.DB) *gorm.DB {
+	return tx.Set(readReplicaKey, true)
+}
+
+// readReplica is a gorm plugin that routes the queries marked with ReadReplica to a replica of the database.
+// Queries made within a transaction always go to the primary.
+// While the replica is unreachable, the queries fall back to the primary.
+type readReplica struct {
+	primary gorm.ConnPool
+	replica *sql.DB
+	healthy atomic.Bool
+}
+
+func (r *readReplica) Name() string {
+	return "mcpjungle:read_replica"
+}
+
+func (r *readReplica) Initialize(db *gorm.DB) error {
+	r.primary = db.ConnPool
+	queries := db.Callback().Query()
+	if err := queries.Before("gorm:query").Register("mcpjungle:route_to_replica", r.route); err != nil {
+		return err
+	}
+	if err := queries.After("gorm:query").Register("mcpjungle:replica_fallback", r.fallback(callbacks.Query)); err != nil {
+		return err
+	}
+	rows := db.Callback().Row()
+	if err := rows.Before("gorm:row").Register("mcpjungle:route_to_replica", r.route); err != nil {
+		return err
+	}
+	return rows.After("gorm:row").Register("mcpjungle:replica_fallback", r.fallback(callbacks.RowQuery))
+}
+
+// route sends the query to the replica if it was marked with ReadReplica and the replica is healthy.
+func (r *readReplica) route(db *gorm.DB) {
+	if v, ok := db.Get(readReplicaKey); !ok || v != true || !r.healthy.Load() {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	db.Statement.ConnPool = r.replica
+}
+
+// fallback runs a query that failed to reach the replica again on the primary, and marks the replica as down
+// until the next health check succeeds.
+func (r *readReplica) fallback(query func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error == nil || db.Statement.ConnPool != r.replica || !isConnectionError(db.Error) {
+			return
+		}
+		r.setHealthy(false, db.Error)
+		db.Error = nil
+		db.Statement.ConnPool = r.primary
+		query(db)
+	}
+}
+
+// isConnectionError returns true if err means that the database couldn't be reached.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
+}
+
+func (r *readReplica) setHealthy(healthy bool, err error) {
+	if r.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		log.Printf("[db] read replica is reachable, routing read queries to it")
+		metrics.DBReadReplicaUp.Set(1)
+	} else {
+		log.Printf("[db] read replica is unreachable, routing read queries to the primary database: %v", err)
+		metrics.DBReadReplicaUp.Set(0)
+	}
+}
+
+// check pings the replica and updates its health.
+func (r *readReplica) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, replicaHealthCheckInterval/2)
+	defer cancel()
+	err := r.replica.PingContext(ctx)
+	r.setHealthy(err == nil, err)
+	return err
+}
+
+// run checks the health of the replica periodically until ctx is done.
+func (r *readReplica) run(ctx context.Context) {
+	ticker := time.NewTicker(replicaHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = r.check(ctx)
+		}
+	}
+}
+
+// useReadReplica routes the queries of db marked with ReadReplica to the given replica.
+// The health of the replica is checked right away, then periodically until ctx is done.
+func useReadReplica(ctx context.Context, db *gorm.DB, replica *sql.DB) (*readReplica, error) {
+	r := &readReplica{replica: replica}
+	if err := db.Use(r); err != nil {
+		return nil, err
+	}
+	metrics.DBReadReplicaUp.Set(0)
+	if err := r.check(ctx); err != nil {
+		log.Printf("[db] read replica is unreachable, routing read queries to the primary database: %v", err)
+	}
+	go r.run(ctx)
+	return r, nil
+}
@@ -0,0 +1,180 @@
+// Package license checks the entitlements granted by an mcpjungle enterprise license.
+// All features of mcpjungle work without a license, except for a few enterprise add-ons listed below,
+// and only in the builds that a public key is compiled into. Builds without one don't restrict any feature.
+//
+// A license is a JSON file signed with the Ed25519 key of the mcpjungle maintainers:
+//
+//	{
+//	  "licensee": "Acme Corp",
+//	  "features": ["audit_export"],
+//	  "expires_at": "2027-01-01T00:00:00Z",
+//	  "signature": "<base64 Ed25519 signature of the file without its signature field>"
+//	}
+//
+// The public key that verifies licenses is compiled into release builds via
+// -ldflags "-X github.com/mcpjungle/mcpjungle/internal/license.PublicKey=<base64 key>".
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Names of the enterprise features that require a license
+const (
+	// AuditExport is the delivery of the audit trail to external collectors over HTTP (eg- Splunk, Elasticsearch)
+	AuditExport = "audit_export"
+)
+
+// Features contains the names of all enterprise features
+var Features = []string{AuditExport}
+
+// PublicKey is the base64-encoded Ed25519 public key that verifies licenses, set at build time.
+// Builds without it don't accept any license, nor require one for the enterprise features.
+var PublicKey = ""
+
+// ErrNotEntitled is returned when an enterprise feature is used without a license that includes it
+var ErrNotEntitled = errors.New("not entitled")
+
+// License describes the entitlements granted to a licensee.
+type License struct {
+	Licensee  string    `json:"licensee"`
+	Features  []string  `json:"features"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Signature string    `json:"signature,omitempty"`
+}
+
+// signingPayload returns the bytes covered by the signature of the license.
+func (l *License) signingPayload() ([]byte, error) {
+	unsigned := *l
+	unsigned.Signature = ""
+	return json.Marshal(unsigned)
+}
+
+// Sign sets the signature of the license. It is used by the maintainers to issue licenses, and in tests.
+func (l *License) Sign(key ed25519.PrivateKey) error {
+	payload, err := l.signingPayload()
+	if err != nil {
+		return err
+	}
+	l.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload))
+	return nil
+}
+
+// Parse decodes a license and verifies its signature with the given public key.
+// An expired license is returned without an error, it simply doesn't grant any entitlement.
+func Parse(data []byte, publicKey ed25519.PublicKey) (*License, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, errors.New("this build of mcpjungle does not accept licenses")
+	}
+	var l License
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid license: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(l.Signature)
+	if err != nil || l.Signature == "" {
+		return nil, errors.New("invalid license: missing or malformed signature")
+	}
+	payload, err := l.signingPayload()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license: %w", err)
+	}
+	if !ed25519.Verify(publicKey, payload, sig) {
+		return nil, errors.New("invalid license: signature verification failed")
+	}
+	for _, f := range l.Features {
+		if !slices.Contains(Features, f) {
+			return nil, fmt.Errorf("invalid license: unknown feature %s", f)
+		}
+	}
+	return &l, nil
+}
+
+// Load reads the license file at path and verifies it with PublicKey.
+// An empty path stands for no license, in which case the returned Entitlements grant nothing.
+// If no public key is compiled into the build, the license file is ignored and the Entitlements grant every feature.
+func Load(path string) (*Entitlements, error) {
+	if PublicKey == "" {
+		if path != "" {
+			log.Printf("[WARN] this build of mcpjungle does not check licenses, the license file %s is ignored", path)
+		}
+		return &Entitlements{unrestricted: true}, nil
+	}
+	if path == "" {
+		return &Entitlements{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license file: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid license public key in this build: %w", err)
+	}
+	l, err := Parse(data, key)
+	if err != nil {
+		return nil, err
+	}
+	return &Entitlements{license: l}, nil
+}
+
+// Entitlements determines which enterprise features can be used.
+// A nil or empty Entitlements grants none of them.
+type Entitlements struct {
+	license *License
+	// unrestricted grants every feature without a license, in builds that don't check licenses
+	unrestricted bool
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// License returns the license that grants the entitlements, nil if there is none.
+func (e *Entitlements) License() *License {
+	if e == nil {
+		return nil
+	}
+	return e.license
+}
+
+// Expired returns true if there is a license and it has expired.
+func (e *Entitlements) Expired() bool {
+	if e == nil || e.license == nil || e.license.ExpiresAt.IsZero() {
+		return false
+	}
+	now := time.Now
+	if e.now != nil {
+		now = e.now
+	}
+	return now().After(e.license.ExpiresAt)
+}
+
+// Allows returns true if the license includes the given enterprise feature and hasn't expired.
+func (e *Entitlements) Allows(feature string) bool {
+	if e != nil && e.unrestricted {
+		return true
+	}
+	if e == nil || e.license == nil || e.Expired() {
+		return false
+	}
+	return slices.Contains(e.license.Features, feature)
+}
+
+// Require returns an error wrapping ErrNotEntitled unless the enterprise feature is allowed.
+func (e *Entitlements) Require(feature string) error {
+	if e.Allows(feature) {
+		return nil
+	}
+	reason := "it requires an mcpjungle enterprise license that includes it"
+	if e.Expired() {
+		reason = "the license expired on " + e.license.ExpiresAt.Format(time.DateOnly)
+	}
+	return fmt.Errorf("%w: %s is an enterprise feature, %s", ErrNotEntitled, strings.ReplaceAll(feature, "_", " "), reason)
+}
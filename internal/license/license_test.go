@@ -0,0 +1,100 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &License{Licensee: "Acme", Features: []string{AuditExport}, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := l.Sign(priv); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(l)
+
+	parsed, err := Parse(data, pub)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.Licensee != "Acme" {
+		t.Errorf("Parse() licensee = %q, want Acme", parsed.Licensee)
+	}
+
+	// changing the licensee invalidates the signature
+	l.Licensee = "Evil Corp"
+	tampered, _ := json.Marshal(l)
+	if _, err := Parse(tampered, pub); err == nil {
+		t.Error("Parse() accepted a tampered license")
+	}
+	if _, err := Parse(data, nil); err == nil {
+		t.Error("Parse() accepted a license without a public key")
+	}
+}
+
+func TestEntitlements(t *testing.T) {
+	var none *Entitlements
+	if err := none.Require(AuditExport); !errors.Is(err, ErrNotEntitled) {
+		t.Errorf("Require() without a license error = %v, want ErrNotEntitled", err)
+	}
+
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := &Entitlements{license: &License{Licensee: "Acme", Features: []string{AuditExport}, ExpiresAt: expiry}}
+	e.now = func() time.Time { return expiry.Add(-time.Hour) }
+	if err := e.Require(AuditExport); err != nil {
+		t.Errorf("Require(audit_export) error = %v", err)
+	}
+	if (&Entitlements{license: &License{Licensee: "Acme"}}).Allows(AuditExport) {
+		t.Error("Allows(audit_export) = true for a license without it")
+	}
+
+	e.now = func() time.Time { return expiry.Add(time.Hour) }
+	if !e.Expired() || e.Allows(AuditExport) {
+		t.Error("an expired license still grants its features")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(key string) { PublicKey = key }(PublicKey)
+	PublicKey = base64.StdEncoding.EncodeToString(pub)
+
+	l := &License{Licensee: "Acme", Features: []string{AuditExport}}
+	if err := l.Sign(priv); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(l)
+	path := filepath.Join(t.TempDir(), "license.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !e.Allows(AuditExport) {
+		t.Error("Allows(audit_export) = false for a license without expiry that includes it")
+	}
+	if e, err := Load(""); err != nil || e.License() != nil || e.Allows(AuditExport) {
+		t.Errorf("Load(\"\") = %v, %v, want no license", e, err)
+	}
+
+	// builds without a public key don't restrict any feature
+	PublicKey = ""
+	if e, err := Load(path); err != nil || e.License() != nil || !e.Allows(AuditExport) {
+		t.Errorf("Load() without a public key = %v, %v, want every feature allowed", e, err)
+	}
+}
@@ -0,0 +1,299 @@
+// Package metrics defines the Prometheus metrics exported by mcpjungle on the /metrics endpoint.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "mcpjungle"
+
+// Registry contains all metrics exported by mcpjungle
+var Registry = prometheus.NewRegistry()
+
+var (
+	// ProxySessionsActive is the number of MCP proxy sessions that are currently open
+	ProxySessionsActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "sessions_active",
+		Help:      "Number of open MCP proxy sessions.",
+	})
+
+	// ProxySessionsExpired counts MCP proxy sessions closed by the gateway, by reason (idle_timeout | max_lifetime)
+	ProxySessionsExpired = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "sessions_expired_total",
+		Help:      "Number of MCP proxy sessions expired by the gateway.",
+	}, []string{"reason"})
+
+	// ProxySessionsTerminated counts MCP proxy sessions explicitly terminated by their clients
+	ProxySessionsTerminated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "sessions_terminated_total",
+		Help:      "Number of MCP proxy sessions terminated by clients.",
+	})
+
+	// ProxySessionsInitialized counts initialized MCP proxy sessions by the protocol version requested by the client
+	// and the version negotiated by the proxy. It shows which protocol revisions are still in use.
+	ProxySessionsInitialized = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "sessions_initialized_total",
+		Help:      "Number of initialized MCP proxy sessions by requested and negotiated protocol version.",
+	}, []string{"requested_version", "negotiated_version"})
+
+	// ProxyToolCalls counts tool calls made via the MCP proxy by the protocol version of the calling session
+	ProxyToolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "tool_calls_total",
+		Help:      "Number of tool calls made via the MCP proxy by protocol version.",
+	}, []string{"protocol_version"})
+
+	// UpstreamToolCallRetries counts tool calls retried because of a transient failure,
+	// by MCP server and class of failure (connection | timeout | tool_error)
+	UpstreamToolCallRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "tool_call_retries_total",
+		Help:      "Number of tool call retries by MCP server and class of failure.",
+	}, []string{"server", "reason"})
+
+	// UpstreamHedgedCalls counts tool calls for which a hedged request was sent, by tool and by the request
+	// whose response was used (primary | hedge | none if both failed).
+	// The share of calls won by the hedge shows how effective hedging is at cutting tail latency.
+	UpstreamHedgedCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "hedged_calls_total",
+		Help:      "Number of tool calls for which a hedged request was sent, by tool and winning request.",
+	}, []string{"tool", "winner"})
+
+	// UpstreamStdioFramingErrors counts output of stdio MCP servers that is not a well-formed JSON-RPC message,
+	// by MCP server and reason (eg- noise, partial, bom, invalid_utf8).
+	UpstreamStdioFramingErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "stdio_framing_errors_total",
+		Help:      "Number of malformed outputs of stdio MCP servers by MCP server and reason.",
+	}, []string{"server", "reason"})
+
+	// UpstreamHeartbeatFailures counts failed pings of warm connections by MCP server.
+	// Every failure makes mcpjungle replace the connection.
+	UpstreamHeartbeatFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "heartbeat_failures_total",
+		Help:      "Number of failed pings of warm connections to MCP servers.",
+	}, []string{"server"})
+
+	// UpstreamStdioRestarts counts the attempts at restarting the process of a warm stdio MCP server after it crashed,
+	// by server and result (succeeded or failed).
+	UpstreamStdioRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "stdio_restarts_total",
+		Help:      "Number of attempts at restarting crashed stdio MCP servers.",
+	}, []string{"server", "result"})
+
+	// UpstreamReplicaCalls counts the tool calls routed to a replica of their MCP server because it is closer
+	// to the caller, by server and region of the replica.
+	UpstreamReplicaCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "replica_calls_total",
+		Help:      "Number of tool calls routed to the replica of an MCP server closest to the caller.",
+	}, []string{"server", "region"})
+
+	// ToolSchemaDrifts counts the incompatible changes of the input schemas of tools by their MCP servers, by server
+	ToolSchemaDrifts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "tool_schema_drifts_total",
+		Help:      "Number of tools whose input schema was changed incompatibly by their MCP server.",
+	}, []string{"server"})
+
+	// UpstreamIdentityChanges counts connections to MCP servers that reported a different identity
+	// than the one pinned, by server and pinning mode (alert or block).
+	UpstreamIdentityChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "identity_changes_total",
+		Help:      "Number of connections to MCP servers that reported a different identity than the one pinned.",
+	}, []string{"server", "mode"})
+
+	// UpstreamIntegrityFailures counts the refused starts of stdio MCP servers whose pinned file
+	// didn't match its checksum or signature.
+	UpstreamIntegrityFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "integrity_failures_total",
+		Help:      "Number of starts of stdio MCP servers refused because of a failed integrity check.",
+	}, []string{"server"})
+
+	// SLOCompliant is 1 if all objectives of an SLO are met over its current window and 0 otherwise.
+	// It is meant for alerting on SLO violations.
+	SLOCompliant = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "slo",
+		Name:      "compliant",
+		Help:      "Whether all objectives of an SLO are met over its current window (1) or not (0).",
+	}, []string{"slo"})
+
+	// AuditEventsDropped counts audit events that were not delivered to a sink,
+	// by sink and reason (buffer_full | delivery_failed)
+	AuditEventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "audit",
+		Name:      "events_dropped_total",
+		Help:      "Number of audit events not delivered to a sink, by sink and reason.",
+	}, []string{"sink", "reason"})
+
+	// DBSlowQueries counts the database queries that exceeded the slow query threshold
+	DBSlowQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "slow_queries_total",
+		Help:      "Number of database queries slower than the slow query threshold.",
+	})
+
+	// DBReadReplicaUp is 1 while the read replica of the database is reachable and serves read queries, 0 otherwise
+	DBReadReplicaUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "read_replica_up",
+		Help:      "Whether the read replica of the database is reachable (1) or read queries fall back to the primary (0).",
+	})
+
+	// AuthorizerDecisions counts the decisions about tool calls made by the external authorizer,
+	// by decision (allow | deny | error)
+	AuthorizerDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "authorizer",
+		Name:      "decisions_total",
+		Help:      "Number of tool calls authorized by the external authorizer, by decision.",
+	}, []string{"decision"})
+
+	// EmergencyRejectedCalls counts the tool calls rejected while the break-glass switch is on, by tool
+	EmergencyRejectedCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "emergency",
+		Name:      "rejected_tool_calls_total",
+		Help:      "Number of tool calls rejected while all tool calls are disabled, by tool.",
+	}, []string{"tool"})
+
+	// HTTPRequestsInFlight is the number of HTTP requests being served, by concurrency pool (tool_calls | api)
+	HTTPRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests being served, by concurrency pool.",
+	}, []string{"pool"})
+
+	// HTTPRequestsRejected counts the HTTP requests rejected because their concurrency pool was full
+	HTTPRequestsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_rejected_total",
+		Help:      "Number of HTTP requests rejected because the concurrency limit of their pool was reached.",
+	}, []string{"pool"})
+
+	// ToolCallsQueued is the number of tool calls waiting for a slot of the tool calls pool, by caller
+	ToolCallsQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "tool_calls_queued",
+		Help:      "Number of tool calls waiting for a slot of the tool calls pool, by MCP client, user or \"anonymous\".",
+	}, []string{"caller"})
+
+	// ToolCallQueueWait is the time that queued tool calls waited before being served, by caller
+	ToolCallQueueWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "tool_call_queue_wait_seconds",
+		Help:      "Time that queued tool calls waited for a slot of the tool calls pool, by MCP client, user or \"anonymous\".",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"caller"})
+
+	// ToolCallsRateLimited counts the tool calls rejected because their caller exceeded a rate limit, by limit
+	ToolCallsRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "tool_calls_rate_limited_total",
+		Help:      "Number of tool calls rejected because their caller exceeded a rate limit or quota, by limit.",
+	}, []string{"limit"})
+
+	// RateLimitStoreErrors counts the failed updates of the rate limit counters in their shared store (eg- Redis)
+	RateLimitStoreErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "store_errors_total",
+		Help:      "Number of failed updates of the rate limit counters in their store, counted in memory instead.",
+	})
+
+	// RemoteWriteFailures counts the pushes of the metrics to the remote-write endpoint that failed
+	RemoteWriteFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "remote_write",
+		Name:      "failures_total",
+		Help:      "Number of failed pushes of the metrics to the remote-write endpoint.",
+	})
+
+	// CatalogPublishes counts the publications of the catalog snapshot, by result (published | unchanged | failed)
+	CatalogPublishes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "catalog",
+		Name:      "publishes_total",
+		Help:      "Number of publications of the catalog snapshot to its destination, by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		// besides the default go_goroutines & go_memstats_* metrics, export the GC pause & scheduler latency
+		// histograms of the runtime to diagnose leaks & stalls in long-running gateways
+		collectors.NewGoCollector(
+			collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsGC, collectors.MetricsScheduler),
+		),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ProxySessionsActive,
+		ProxySessionsExpired,
+		ProxySessionsTerminated,
+		ProxySessionsInitialized,
+		ProxyToolCalls,
+		UpstreamToolCallRetries,
+		UpstreamHedgedCalls,
+		UpstreamStdioFramingErrors,
+		UpstreamHeartbeatFailures,
+		UpstreamStdioRestarts,
+		UpstreamReplicaCalls,
+		UpstreamIdentityChanges,
+		UpstreamIntegrityFailures,
+		SLOCompliant,
+		AuditEventsDropped,
+		DBSlowQueries,
+		DBReadReplicaUp,
+		AuthorizerDecisions,
+		EmergencyRejectedCalls,
+		ToolSchemaDrifts,
+		HTTPRequestsInFlight,
+		HTTPRequestsRejected,
+		ToolCallsQueued,
+		ToolCallQueueWait,
+		ToolCallsRateLimited,
+		RateLimitStoreErrors,
+		RemoteWriteFailures,
+		CatalogPublishes,
+	)
+}
+
+// RegisterDBStats exports the connection pool statistics of the database (go_sql_* metrics).
+// It must be called at most once.
+func RegisterDBStats(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
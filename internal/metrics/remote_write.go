@@ -0,0 +1,289 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWriteDefaultInterval & RemoteWriteDefaultBatchSize are the defaults of RemoteWriteConfig
+const (
+	RemoteWriteDefaultInterval  = 30 * time.Second
+	RemoteWriteDefaultBatchSize = 500
+)
+
+const (
+	remoteWriteTimeout     = 30 * time.Second
+	remoteWriteMaxAttempts = 3
+)
+
+// RemoteWriteConfig configures the push of the metrics to an endpoint implementing the Prometheus remote-write
+// protocol (eg- Prometheus, Mimir, Thanos receive or a managed Prometheus service).
+type RemoteWriteConfig struct {
+	URL string
+
+	// Interval is how often the metrics are collected & pushed
+	Interval time.Duration
+
+	// BatchSize is the maximum number of time series per request
+	BatchSize int
+
+	// BearerToken, or Username & Password, authenticate the requests
+	BearerToken string
+	Username    string
+	Password    string
+}
+
+// RemoteWriter periodically pushes the metrics of a registry to a remote-write endpoint, for deployments where
+// no Prometheus server can scrape the /metrics endpoint (eg- serverless or locked-down networks).
+type RemoteWriter struct {
+	cfg        RemoteWriteConfig
+	gatherer   prometheus.Gatherer
+	httpClient *http.Client
+
+	// labels added to every time series to identify this instance, as a scraper would
+	labels []label
+}
+
+// NewRemoteWriter creates a RemoteWriter that pushes the metrics gathered from gatherer.
+func NewRemoteWriter(cfg RemoteWriteConfig, gatherer prometheus.Gatherer) (*RemoteWriter, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote-write URL '%s', must be an http(s) URL", cfg.URL)
+	}
+	if cfg.BearerToken != "" && cfg.Username != "" {
+		return nil, errors.New("either a bearer token or a username & password can be used for remote-write, not both")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = RemoteWriteDefaultInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = RemoteWriteDefaultBatchSize
+	}
+	instance, _ := os.Hostname()
+	return &RemoteWriter{
+		cfg:        cfg,
+		gatherer:   gatherer,
+		httpClient: &http.Client{Timeout: remoteWriteTimeout},
+		labels:     []label{{"instance", instance}, {"job", namespace}},
+	}, nil
+}
+
+// URL returns the endpoint that the metrics are pushed to.
+func (w *RemoteWriter) URL() string {
+	return w.cfg.URL
+}
+
+// Run pushes the metrics periodically until ctx is cancelled.
+// Failures are logged and counted in RemoteWriteFailures, the samples of a failed push are dropped.
+func (w *RemoteWriter) Run(ctx context.Context) {
+	tick := time.NewTicker(w.cfg.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+		if err := w.Push(ctx); err != nil {
+			RemoteWriteFailures.Inc()
+			log.Printf("[WARN] failed to push metrics to %s: %v", w.cfg.URL, err)
+		}
+	}
+}
+
+// Push gathers the metrics and sends them in batches of at most BatchSize time series.
+func (w *RemoteWriter) Push(ctx context.Context) error {
+	families, err := w.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	series := toTimeSeries(families, time.Now().UnixMilli(), w.labels)
+	for start := 0; start < len(series); start += w.cfg.BatchSize {
+		batch := series[start:min(start+w.cfg.BatchSize, len(series))]
+		if err := w.send(ctx, snappyEncode(encodeWriteRequest(batch))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send POSTs a compressed write request, retrying with a backoff if the endpoint is temporarily unavailable.
+func (w *RemoteWriter) send(ctx context.Context, body []byte) error {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil || !retry || attempt == remoteWriteMaxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a write request once. It returns whether the request can be retried if it failed.
+func (w *RemoteWriter) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "mcpjungle")
+	switch {
+	case w.cfg.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+w.cfg.BearerToken)
+	case w.cfg.Username != "":
+		req.SetBasicAuth(w.cfg.Username, w.cfg.Password)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	// as per the protocol, only server errors & throttling are retried, the other requests are invalid
+	return resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+type label struct {
+	name, value string
+}
+
+type timeSeries struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// toTimeSeries converts metric families into time series the way Prometheus does when it scrapes them:
+// histograms & summaries are split into their _bucket (or quantile), _sum and _count series.
+func toTimeSeries(families []*dto.MetricFamily, timestamp int64, extra []label) []timeSeries {
+	var series []timeSeries
+	for _, f := range families {
+		name := f.GetName()
+		for _, m := range f.GetMetric() {
+			ts := timestamp
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			add := func(name string, value float64, more ...label) {
+				labels := make([]label, 0, len(m.GetLabel())+len(extra)+len(more)+1)
+				labels = append(labels, label{"__name__", name})
+				for _, l := range m.GetLabel() {
+					labels = append(labels, label{l.GetName(), l.GetValue()})
+				}
+				labels = append(labels, more...)
+				for _, l := range extra {
+					if l.value != "" && !hasLabel(labels, l.name) {
+						labels = append(labels, l)
+					}
+				}
+				sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+				series = append(series, timeSeries{labels: labels, value: value, timestamp: ts})
+			}
+
+			switch f.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add(name, q.GetValue(), label{"quantile", formatFloat(q.GetQuantile())})
+				}
+				add(name+"_sum", s.GetSampleSum())
+				add(name+"_count", float64(s.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				h := m.GetHistogram()
+				infSeen := false
+				for _, b := range h.GetBucket() {
+					if math.IsInf(b.GetUpperBound(), 1) {
+						infSeen = true
+					}
+					add(name+"_bucket", float64(b.GetCumulativeCount()), label{"le", formatFloat(b.GetUpperBound())})
+				}
+				if !infSeen {
+					add(name+"_bucket", float64(h.GetSampleCount()), label{"le", "+Inf"})
+				}
+				add(name+"_sum", h.GetSampleSum())
+				add(name+"_count", float64(h.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func hasLabel(labels []label, name string) bool {
+	for _, l := range labels {
+		if l.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// encodeWriteRequest encodes time series as a remote-write WriteRequest protobuf message:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(series []timeSeries) []byte {
+	var buf, ts, msg []byte
+	for _, s := range series {
+		ts = ts[:0]
+		for _, l := range s.labels {
+			msg = protowire.AppendTag(msg[:0], 1, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.name)
+			msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, msg)
+		}
+		msg = protowire.AppendTag(msg[:0], 1, protowire.Fixed64Type)
+		msg = protowire.AppendFixed64(msg, math.Float64bits(s.value))
+		msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(s.timestamp))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, msg)
+
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, ts)
+	}
+	return buf
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// snappyDecode decodes a snappy block, only supporting the elements produced by snappyEncode.
+func snappyDecode(t *testing.T, src []byte) []byte {
+	n, k := binary.Uvarint(src)
+	src = src[k:]
+	var dst []byte
+	for len(src) > 0 {
+		tag := src[0]
+		switch tag & 3 {
+		case 0:
+			length, hdr := int(tag>>2)+1, 1
+			switch tag >> 2 {
+			case 60:
+				length, hdr = int(src[1])+1, 2
+			case 61:
+				length, hdr = int(binary.LittleEndian.Uint16(src[1:]))+1, 3
+			}
+			dst = append(dst, src[hdr:hdr+length]...)
+			src = src[hdr+length:]
+		case 2:
+			length, offset := int(tag>>2)+1, int(binary.LittleEndian.Uint16(src[1:]))
+			for i := 0; i < length; i++ {
+				dst = append(dst, dst[len(dst)-offset])
+			}
+			src = src[3:]
+		default:
+			t.Fatalf("unexpected snappy element type %d", tag&3)
+		}
+	}
+	if uint64(len(dst)) != n {
+		t.Fatalf("decoded %d bytes, header says %d", len(dst), n)
+	}
+	return dst
+}
+
+func TestSnappyEncode(t *testing.T) {
+	for _, src := range [][]byte{
+		nil,
+		[]byte("abc"),
+		[]byte(strings.Repeat(`mcpjungle_proxy_tool_calls_total{protocol_version="2025-03-26"} `, 500)),
+		bytes.Repeat([]byte{0}, 200000),
+	} {
+		enc := snappyEncode(src)
+		if got := snappyDecode(t, enc); !bytes.Equal(got, src) {
+			t.Fatalf("round trip of %d bytes returned different %d bytes", len(src), len(got))
+		}
+		if len(src) > 1000 && len(enc) > len(src)/4 {
+			t.Errorf("%d repetitive bytes compressed to %d bytes", len(src), len(enc))
+		}
+	}
+}
+
+func TestRemoteWriterPush(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	calls := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "calls_total", Help: "calls"}, []string{"tool"})
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency_seconds", Help: "latency", Buckets: []float64{1}})
+	reg.MustRegister(calls, latency)
+	calls.WithLabelValues("a").Inc()
+	calls.WithLabelValues("b").Add(2)
+	latency.Observe(0.5)
+
+	var requests atomic.Int32
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("Authorization") != "Bearer s3cret" {
+			http.Error(w, "bad headers", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, snappyDecode(t, body))
+	}))
+	defer srv.Close()
+
+	w, err := NewRemoteWriter(RemoteWriteConfig{URL: srv.URL, BatchSize: 2, BearerToken: "s3cret"}, reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Push(context.Background()); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	// 2 counter series + 2 buckets (1 & +Inf), _sum & _count of the histogram, 2 series per request
+	if len(bodies) != 3 {
+		t.Fatalf("Push() sent %d requests, want 3", len(bodies))
+	}
+	all := bytes.Join(bodies, nil)
+	for _, want := range []string{"calls_total", "latency_seconds_bucket", "+Inf", "latency_seconds_count", "instance", "job"} {
+		if !bytes.Contains(all, []byte(want)) {
+			t.Errorf("pushed write requests don't contain %q", want)
+		}
+	}
+
+	// invalid requests are not retried
+	requests.Store(0)
+	w.cfg.BearerToken = "wrong"
+	if err := w.Push(context.Background()); err == nil {
+		t.Error("Push() with a rejected token succeeded, want error")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("a rejected push was sent %d times, want 1", requests.Load())
+	}
+}
+
+func TestToTimeSeries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "sessions", Help: "sessions"}, []string{"zone"})
+	reg.MustRegister(g)
+	g.WithLabelValues("eu").Set(3)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	series := toTimeSeries(families, 42, []label{{"instance", "host-1"}, {"job", "mcpjungle"}})
+	if len(series) != 1 {
+		t.Fatalf("toTimeSeries() returned %d series, want 1", len(series))
+	}
+	want := []label{{"__name__", "sessions"}, {"instance", "host-1"}, {"job", "mcpjungle"}, {"zone", "eu"}}
+	s := series[0]
+	if s.value != 3 || s.timestamp != 42 || len(s.labels) != len(want) {
+		t.Fatalf("toTimeSeries() = %+v", s)
+	}
+	for i := range want {
+		if s.labels[i] != want[i] {
+			t.Errorf("label %d = %v, want %v", i, s.labels[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"encoding/binary"
+)
+
+// snappyEncode compresses src in the snappy block format (https://github.com/google/snappy/blob/main/format_description.txt),
+// which the Prometheus remote-write protocol requires. It is a simple greedy compressor: it finds repeated sequences
+// of 4 bytes within the last 64KiB with a hash table, which is enough for the highly repetitive metric labels.
+func snappyEncode(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)/2+16), uint64(len(src)))
+
+	const (
+		tableBits  = 14
+		maxOffset  = 1 << 16
+		minMatchAt = 4
+	)
+	// positions of the last occurrences of 4-byte sequences, +1 so that 0 means none
+	var table [1 << tableBits]int32
+	hash := func(u uint32) uint32 { return (u * 0x1e35a7bd) >> (32 - tableBits) }
+
+	lit := 0
+	for i := 0; i+minMatchAt <= len(src); {
+		u := binary.LittleEndian.Uint32(src[i:])
+		h := hash(u)
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if cand < 0 || i-cand >= maxOffset || binary.LittleEndian.Uint32(src[cand:]) != u {
+			i++
+			continue
+		}
+		n := minMatchAt
+		for i+n < len(src) && src[cand+n] == src[i+n] {
+			n++
+		}
+		dst = snappyAppendLiteral(dst, src[lit:i])
+		dst = snappyAppendCopy(dst, i-cand, n)
+		i += n
+		lit = i
+	}
+	return snappyAppendLiteral(dst, src[lit:])
+}
+
+func snappyAppendLiteral(dst, lit []byte) []byte {
+	for len(lit) > 0 {
+		chunk := lit[:min(len(lit), 1<<16)]
+		lit = lit[len(chunk):]
+		switch n := len(chunk) - 1; {
+		case n < 60:
+			dst = append(dst, byte(n)<<2)
+		case n < 1<<8:
+			dst = append(dst, 60<<2, byte(n))
+		default:
+			dst = append(dst, 61<<2, byte(n), byte(n>>8))
+		}
+		dst = append(dst, chunk...)
+	}
+	return dst
+}
+
+// snappyAppendCopy appends copies with 2-byte offsets (offset < 64KiB) of at most 64 bytes each.
+func snappyAppendCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		n := min(length, 64)
+		length -= n
+		dst = append(dst, byte(n-1)<<2|2, byte(offset), byte(offset>>8))
+	}
+	return dst
+}
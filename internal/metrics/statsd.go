@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// StatsdDefaultInterval is how often the metrics are sent to the DogStatsD agent by default
+const StatsdDefaultInterval = 10 * time.Second
+
+const (
+	// statsdMaxUDPPacket is the maximum size of a UDP datagram recommended by Datadog to avoid fragmentation
+	statsdMaxUDPPacket = 1432
+	// statsdMaxUDSPacket is the default maximum size of a datagram sent over a unix socket to the agent
+	statsdMaxUDSPacket = 8192
+)
+
+// StatsdConfig configures the export of the metrics to a DogStatsD agent.
+type StatsdConfig struct {
+	// Addr is the address of the agent, host:port for UDP (eg- 127.0.0.1:8125) or unix:///path/to/socket
+	Addr string
+
+	// Interval is how often the metrics are sent
+	Interval time.Duration
+
+	// Tags are added to all the metrics, eg- "env:prod"
+	Tags []string
+}
+
+// StatsdExporter periodically sends the metrics of a registry to a DogStatsD agent, for teams that collect
+// their metrics with Datadog agents instead of Prometheus.
+//
+// Counters are sent as counts of their increase since the last export, gauges as gauges. Histograms & summaries are
+// sent the way Datadog's OpenMetrics integration maps them: <name>.count & <name>.sum counts, <name>.bucket counts
+// tagged with upper_bound (histograms) and <name>.quantile gauges tagged with quantile (summaries).
+// Prometheus labels become tags.
+type StatsdExporter struct {
+	cfg       StatsdConfig
+	gatherer  prometheus.Gatherer
+	network   string
+	address   string
+	maxPacket int
+
+	// last values of the counters, to send their increase
+	counters map[string]float64
+}
+
+// NewStatsdExporter creates a StatsdExporter that sends the metrics gathered from gatherer.
+func NewStatsdExporter(cfg StatsdConfig, gatherer prometheus.Gatherer) (*StatsdExporter, error) {
+	e := &StatsdExporter{
+		cfg:       cfg,
+		gatherer:  gatherer,
+		network:   "udp",
+		address:   cfg.Addr,
+		maxPacket: statsdMaxUDPPacket,
+		counters:  make(map[string]float64),
+	}
+	if path, ok := strings.CutPrefix(cfg.Addr, "unix://"); ok {
+		e.network, e.address, e.maxPacket = "unixgram", path, statsdMaxUDSPacket
+	} else if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return nil, fmt.Errorf("invalid DogStatsD address '%s', must be host:port or unix:///path/to/socket", cfg.Addr)
+	}
+	for _, t := range cfg.Tags {
+		if t == "" || strings.ContainsAny(t, ",|#\n") {
+			return nil, fmt.Errorf("invalid DogStatsD tag '%s'", t)
+		}
+	}
+	if e.cfg.Interval <= 0 {
+		e.cfg.Interval = StatsdDefaultInterval
+	}
+	return e, nil
+}
+
+// Addr returns the address of the DogStatsD agent.
+func (e *StatsdExporter) Addr() string {
+	return e.cfg.Addr
+}
+
+// Run sends the metrics periodically until ctx is cancelled. Failures are only logged.
+func (e *StatsdExporter) Run(ctx context.Context) {
+	tick := time.NewTicker(e.cfg.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+		if err := e.Push(); err != nil {
+			log.Printf("[WARN] failed to send metrics to DogStatsD agent %s: %v", e.cfg.Addr, err)
+		}
+	}
+}
+
+// Push gathers the metrics and sends them to the agent, in as few datagrams as possible.
+func (e *StatsdExporter) Push() error {
+	families, err := e.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	lines := e.lines(families)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	conn, err := net.Dial(e.network, e.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var packet []byte
+	for _, line := range lines {
+		if len(packet) > 0 && len(packet)+1+len(line) > e.maxPacket {
+			if _, err := conn.Write(packet); err != nil {
+				return err
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	_, err = conn.Write(packet)
+	return err
+}
+
+// lines converts metric families into DogStatsD datagram lines.
+func (e *StatsdExporter) lines(families []*dto.MetricFamily) []string {
+	var lines []string
+	for _, f := range families {
+		name := f.GetName()
+		for _, m := range f.GetMetric() {
+			tags := make([]string, 0, len(m.GetLabel())+len(e.cfg.Tags)+1)
+			for _, l := range m.GetLabel() {
+				tags = append(tags, statsdTag(l.GetName(), l.GetValue()))
+			}
+			tags = append(tags, e.cfg.Tags...)
+			sort.Strings(tags)
+
+			gauge := func(name string, v float64, more ...string) {
+				lines = append(lines, statsdLine(name, v, "g", tags, more))
+			}
+			count := func(name string, v float64, more ...string) {
+				if d := e.increase(name, v, tags, more); d != 0 {
+					lines = append(lines, statsdLine(name, d, "c", tags, more))
+				}
+			}
+
+			switch f.GetType() {
+			case dto.MetricType_COUNTER:
+				count(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				gauge(name, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				gauge(name, m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					gauge(name+".quantile", q.GetValue(), statsdTag("quantile", formatFloat(q.GetQuantile())))
+				}
+				count(name+".count", float64(s.GetSampleCount()))
+				count(name+".sum", s.GetSampleSum())
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.GetBucket() {
+					count(name+".bucket", float64(b.GetCumulativeCount()), statsdTag("upper_bound", formatFloat(b.GetUpperBound())))
+				}
+				count(name+".count", float64(h.GetSampleCount()))
+				count(name+".sum", h.GetSampleSum())
+			}
+		}
+	}
+	return lines
+}
+
+// increase returns the increase of a counter since the last export, or its value if it was reset.
+func (e *StatsdExporter) increase(name string, v float64, tags, more []string) float64 {
+	key := name + "|" + strings.Join(tags, ",") + "|" + strings.Join(more, ",")
+	last, seen := e.counters[key]
+	e.counters[key] = v
+	if !seen || v < last {
+		return v
+	}
+	return v - last
+}
+
+func statsdLine(name string, v float64, typ string, tags, more []string) string {
+	line := name + ":" + strconv.FormatFloat(v, 'g', -1, 64) + "|" + typ
+	if all := append(append([]string{}, tags...), more...); len(all) > 0 {
+		line += "|#" + strings.Join(all, ",")
+	}
+	return line
+}
+
+// statsdTag formats a tag, replacing the characters that are reserved by the DogStatsD protocol.
+func statsdTag(name, value string) string {
+	return name + ":" + strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_").Replace(value)
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestStatsdExporterPush(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+
+	reg := prometheus.NewRegistry()
+	calls := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "calls_total", Help: "calls"}, []string{"tool"})
+	sessions := prometheus.NewGauge(prometheus.GaugeOpts{Name: "sessions", Help: "sessions"})
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency_seconds", Help: "latency", Buckets: []float64{1}})
+	reg.MustRegister(calls, sessions, latency)
+	calls.WithLabelValues("fs|read").Add(3)
+	sessions.Set(2)
+	latency.Observe(0.5)
+
+	e, err := NewStatsdExporter(StatsdConfig{Addr: agent.LocalAddr().String(), Tags: []string{"env:prod"}}, reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive := func() []string {
+		if err := e.Push(); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+		buf := make([]byte, statsdMaxUDPPacket)
+		_ = agent.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := agent.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("failed to receive metrics: %v", err)
+		}
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	lines := receive()
+	for _, want := range []string{
+		"calls_total:3|c|#env:prod,tool:fs_read",
+		"sessions:2|g|#env:prod",
+		"latency_seconds.bucket:1|c|#env:prod,upper_bound:1",
+		"latency_seconds.count:1|c|#env:prod",
+		"latency_seconds.sum:0.5|c|#env:prod",
+	} {
+		if !contains(lines, want) {
+			t.Errorf("sent metrics %q don't contain %q", lines, want)
+		}
+	}
+
+	// counters are sent as their increase since the last export, unchanged counters are not sent
+	calls.WithLabelValues("fs|read").Add(2)
+	lines = receive()
+	if !contains(lines, "calls_total:2|c|#env:prod,tool:fs_read") || contains(lines, "latency_seconds.count:1|c|#env:prod") {
+		t.Errorf("second export sent %q, want the increase of the counters only", lines)
+	}
+}
+
+func TestNewStatsdExporterInvalid(t *testing.T) {
+	if _, err := NewStatsdExporter(StatsdConfig{Addr: "localhost"}, prometheus.NewRegistry()); err == nil {
+		t.Error("NewStatsdExporter() without a port succeeded, want error")
+	}
+	if _, err := NewStatsdExporter(StatsdConfig{Addr: "localhost:8125", Tags: []string{"a|b"}}, prometheus.NewRegistry()); err == nil {
+		t.Error("NewStatsdExporter() with an invalid tag succeeded, want error")
+	}
+}
+
+func contains(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
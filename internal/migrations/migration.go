@@ -23,5 +23,71 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&model.McpClient{}); err != nil {
 		return fmt.Errorf("auto‑migration failed for McpClient model: %v", err)
 	}
+	if err := db.AutoMigrate(&model.ToolInvocation{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ToolInvocation model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Budget{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Budget model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ResidencyPolicy{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ResidencyPolicy model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Policy{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Policy model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.WebhookKey{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for WebhookKey model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ExtractionProfile{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ExtractionProfile model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolGroup{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ToolGroup model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.FeatureFlag{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for FeatureFlag model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Notification{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Notification model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.SLO{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for SLO model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.FaultRule{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for FaultRule model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Prompt{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Prompt model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.SavedCall{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for SavedCall model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ReplaySuite{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ReplaySuite model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ServerProposal{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ServerProposal model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AccessGrant{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for AccessGrant model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.EmergencyStop{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for EmergencyStop model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Secret{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for Secret model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolFavorite{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ToolFavorite model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolView{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ToolView model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ClientSessionStat{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for ClientSessionStat model: %v", err)
+	}
+	if err := db.AutoMigrate(&model.PrunedSpend{}); err != nil {
+		return fmt.Errorf("auto‑migration failed for PrunedSpend model: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccessGrant temporarily allows an MCP client to call the tools of a tool group,
+// on top of the MCP servers in its allow list. It stops granting access once it expires or is revoked.
+type AccessGrant struct {
+	gorm.Model
+
+	ClientName string    `json:"client" gorm:"index;not null"`
+	ToolGroup  string    `json:"tool_group" gorm:"not null"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"index;not null"`
+
+	// GrantedBy is the name of the admin who created the grant, Reason is the optional justification given by them
+	GrantedBy string `json:"granted_by,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
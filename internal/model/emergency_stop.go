@@ -0,0 +1,33 @@
+package model
+
+import (
+	"encoding/json"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// EmergencyStop is the break-glass switch that disables all tool calls during an incident.
+// There is at most one, all tool calls are rejected while it exists, except those to the tools in AllowedTools.
+type EmergencyStop struct {
+	gorm.Model
+
+	// AllowedTools contains the canonical names of the tools, or the names of the MCP servers,
+	// that can still be called. It is stored as a JSON array.
+	AllowedTools datatypes.JSON `json:"allowed_tools" gorm:"type:jsonb"`
+
+	Reason      string `json:"reason,omitempty"`
+	ActivatedBy string `json:"activated_by,omitempty"`
+}
+
+// GetAllowedTools returns the names of the tools and MCP servers that can still be called.
+func (e *EmergencyStop) GetAllowedTools() []string {
+	var allowed []string
+	if len(e.AllowedTools) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(e.AllowedTools, &allowed); err != nil {
+		return nil
+	}
+	return allowed
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ExtractionProfile slims down tool results for clients that don't need all of their data.
+// It contains JSONPath expressions that select the parts of a tool's JSON result to return, eg- $.items[*].title
+// Profiles are reusable and are applied by attaching them to tool groups.
+type ExtractionProfile struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	// Rules maps canonical tool names to the JSONPath expression applied to their results.
+	// It is stored as a JSON object.
+	Rules datatypes.JSON `json:"rules" gorm:"type:jsonb;not null"`
+}
+
+// GetRules returns the JSONPath expression of every tool covered by this profile.
+func (p *ExtractionProfile) GetRules() map[string]string {
+	var rules map[string]string
+	if err := json.Unmarshal(p.Rules, &rules); err != nil {
+		return nil
+	}
+	return rules
+}
+
+func (p *ExtractionProfile) BeforeSave(tx *gorm.DB) (err error) {
+	if len(p.GetRules()) == 0 {
+		return fmt.Errorf("extraction profile %s must contain at least one rule", p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type FaultType string
+
+const (
+	// FaultLatency delays calls by a fixed amount before sending them to the upstream server
+	FaultLatency FaultType = "latency"
+
+	// FaultError fails calls with a tool error without sending them to the upstream server
+	FaultError FaultType = "error"
+
+	// FaultDrop sends calls to the upstream server but discards the response, so the call times out.
+	// It simulates the worst case for retries: the tool ran but the caller doesn't know it.
+	FaultDrop FaultType = "drop"
+)
+
+// FaultRule injects a fault into a share of the calls made to a tool or to all tools of an MCP server.
+// Rules are only applied while the fault_injection feature is enabled.
+type FaultRule struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	// Exactly one of ToolName & ServerName must be set.
+	// It determines which calls the fault is injected into.
+	ToolName   string `json:"tool,omitempty"`
+	ServerName string `json:"server,omitempty" gorm:"index"`
+
+	Fault FaultType `json:"fault" gorm:"type:varchar(10);not null"`
+
+	// Rate is the percentage of calls that the fault is injected into
+	Rate float64 `json:"rate" gorm:"not null"`
+
+	// LatencyMs is the delay added to calls by a latency fault
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+}
+
+func (f *FaultRule) BeforeSave(tx *gorm.DB) (err error) {
+	if (f.ToolName == "") == (f.ServerName == "") {
+		return fmt.Errorf("fault rule %s must apply to exactly one of a tool or an MCP server", f.Name)
+	}
+	if f.Fault != FaultLatency && f.Fault != FaultError && f.Fault != FaultDrop {
+		return fmt.Errorf("invalid fault type: %s", f.Fault)
+	}
+	if f.Rate <= 0 || f.Rate > 100 {
+		return fmt.Errorf("rate of fault rule %s must be a percentage between 0 and 100", f.Name)
+	}
+	if f.Fault == FaultLatency && f.LatencyMs <= 0 {
+		return fmt.Errorf("latency fault rule %s must add a positive latency", f.Name)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package model
+
+import "gorm.io/gorm"
+
+// FeatureFlag turns an experimental feature on or off for the whole deployment.
+// Only flags that have been explicitly set are stored, all other features use their default state.
+type FeatureFlag struct {
+	gorm.Model
+
+	Name    string `json:"name" gorm:"uniqueIndex;not null"`
+	Enabled bool   `json:"enabled" gorm:"not null"`
+}
@@ -2,6 +2,10 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strings"
+
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -13,12 +17,51 @@ type McpClient struct {
 	Name        string `json:"name" gorm:"uniqueIndex;not null"`
 	Description string `json:"description"`
 
+	// Version is incremented every time the record is modified, to detect concurrent modifications
+	Version int `json:"version" gorm:"not null;default:1"`
+
 	AccessToken string `json:"access_token" gorm:"unique; not null"`
 
+	// SpiffeID is the SPIFFE ID of the client's workload (eg- spiffe://example.org/ns/prod/sa/agent).
+	// The client can authenticate with an SVID with this ID instead of its access token.
+	SpiffeID string `json:"spiffe_id,omitempty" gorm:"index"`
+
 	// AllowList contains a list of MCP Server names that this client is allowed to view and call
 	// storing the list of server names as a JSON array is a convenient way for now.
 	// In the future, this will be removed in favor of a separate table for ACLs.
 	AllowList datatypes.JSON `json:"allow_list" gorm:"type:jsonb; not null"`
+
+	// IPAllowList optionally restricts the networks (CIDRs or single IP addresses) that this client
+	// can connect from. If empty, the client can connect from anywhere not listed in IPDenyList.
+	IPAllowList datatypes.JSON `json:"ip_allow_list,omitempty" gorm:"type:jsonb"`
+
+	// IPDenyList contains the networks (CIDRs or single IP addresses) that this client can never connect from.
+	// It takes precedence over IPAllowList.
+	IPDenyList datatypes.JSON `json:"ip_deny_list,omitempty" gorm:"type:jsonb"`
+
+	// Attributes contains key-value pairs that describe the client (eg- its team or environment),
+	// stored as a JSON object. Prompt templates can refer to them as {{client.<key>}}.
+	Attributes datatypes.JSON `json:"attributes,omitempty" gorm:"type:jsonb"`
+
+	// Language is the language (eg- "fr") that the text results of tool calls are translated into for this client,
+	// if translation is enabled. Results are not translated if it is empty.
+	Language string `json:"language,omitempty"`
+
+	// ToolGroup is the tool group that this client is bound to. If set, the client is served the MCP proxy
+	// of the group on /mcp, as if it connected to the group's URL.
+	ToolGroup string `json:"tool_group,omitempty"`
+}
+
+// GetAttributes returns the attributes of this client.
+func (c *McpClient) GetAttributes() map[string]string {
+	var attrs map[string]string
+	if len(c.Attributes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(c.Attributes, &attrs); err != nil {
+		return nil
+	}
+	return attrs
 }
 
 // CheckHasServerAccess returns true if this client has access to the specified MCP server.
@@ -38,3 +81,71 @@ func (c *McpClient) CheckHasServerAccess(serverName string) bool {
 	}
 	return false
 }
+
+// CheckIPAllowed returns true if this client is allowed to connect from the given IP address.
+func (c *McpClient) CheckIPAllowed(addr netip.Addr) bool {
+	deny, err := parseNetworks(c.IPDenyList)
+	if err != nil {
+		// fail closed if the lists are corrupt
+		return false
+	}
+	allow, err := parseNetworks(c.IPAllowList)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range deny {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, p := range allow {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *McpClient) BeforeSave(tx *gorm.DB) (err error) {
+	if _, err := parseNetworks(c.IPAllowList); err != nil {
+		return fmt.Errorf("invalid IP allow list for MCP client %s: %w", c.Name, err)
+	}
+	if _, err := parseNetworks(c.IPDenyList); err != nil {
+		return fmt.Errorf("invalid IP deny list for MCP client %s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// parseNetworks parses a JSON array of CIDRs or single IP addresses.
+func parseNetworks(list datatypes.JSON) ([]netip.Prefix, error) {
+	if len(list) == 0 || string(list) == "null" {
+		return nil, nil
+	}
+	var entries []string
+	if err := json.Unmarshal(list, &entries); err != nil {
+		return nil, err
+	}
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR '%s'", e)
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		a, err := netip.ParseAddr(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address '%s'", e)
+		}
+		a = a.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(a, a.BitLen()))
+	}
+	return prefixes, nil
+}
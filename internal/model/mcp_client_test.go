@@ -0,0 +1,41 @@
+package model
+
+import (
+	"net/netip"
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+func TestMcpClientCheckIPAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow string
+		deny  string
+		ip    string
+		want  bool
+	}{
+		{name: "no lists", ip: "203.0.113.7", want: true},
+		{name: "in allowed cidr", allow: `["10.0.0.0/8"]`, ip: "10.1.2.3", want: true},
+		{name: "outside allowed cidr", allow: `["10.0.0.0/8"]`, ip: "192.168.1.1", want: false},
+		{name: "single allowed ip", allow: `["192.168.1.1"]`, ip: "192.168.1.1", want: true},
+		{name: "ipv4-mapped ipv6", allow: `["10.0.0.0/8"]`, ip: "::ffff:10.0.0.1", want: true},
+		{name: "deny takes precedence", allow: `["10.0.0.0/8"]`, deny: `["10.0.0.0/24"]`, ip: "10.0.0.5", want: false},
+		{name: "denied without allow list", deny: `["2001:db8::/32"]`, ip: "2001:db8::1", want: false},
+		{name: "corrupt list fails closed", allow: `["not-an-ip"]`, ip: "10.0.0.1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &McpClient{}
+			if tt.allow != "" {
+				c.IPAllowList = datatypes.JSON(tt.allow)
+			}
+			if tt.deny != "" {
+				c.IPDenyList = datatypes.JSON(tt.deny)
+			}
+			if got := c.CheckIPAllowed(netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Errorf("CheckIPAllowed(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package model
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"path/filepath"
+
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -16,6 +19,10 @@ type StreamableHTTPConfig struct {
 	// BearerToken is an optional token used for authenticating requests to the MCP server.
 	// If present, it will be used to set the Authorization header in all requests to this MCP server.
 	BearerToken string `json:"bearer_token,omitempty"`
+
+	// SpiffeID is the SPIFFE ID of the MCP server's workload. If set, mcpjungle connects to the server with mTLS,
+	// presenting its own SVID and only trusting an SVID with this ID.
+	SpiffeID string `json:"spiffe_id,omitempty"`
 }
 
 type StdioConfig struct {
@@ -27,6 +34,18 @@ type StdioConfig struct {
 
 	// Env describes the environment variables to pass to the MCP server
 	Env map[string]string `json:"env,omitempty"`
+
+	// Framing determines how output of the server that is not a JSON-RPC message is handled
+	Framing types.StdioFraming `json:"framing,omitempty"`
+
+	// Cwd is the absolute path of the working directory of the server's process
+	Cwd string `json:"cwd,omitempty"`
+
+	// RunAs is the user ("user" or "user:group") that the server's process runs as (Unix only)
+	RunAs string `json:"run_as,omitempty"`
+
+	// Integrity pins the checksum or signature of a file of the server, verified before its process is started
+	Integrity *types.StdioIntegrity `json:"integrity,omitempty"`
 }
 
 // McpServer represents a MCP server registered in mcpjungle
@@ -36,11 +55,53 @@ type McpServer struct {
 	Name      string                   `json:"name" gorm:"uniqueIndex;not null"`
 	Transport types.McpServerTransport `json:"transport" gorm:"type:varchar(30);not null"`
 
+	// Version is incremented every time the record is modified, to detect concurrent modifications
+	Version int `json:"version" gorm:"not null;default:1"`
+
 	Description string `json:"description"`
 
+	// Region is an optional tag describing where the MCP server runs (eg- eu-west).
+	// It is used to enforce data residency policies.
+	Region string `json:"region,omitempty" gorm:"index"`
+
+	// ReplicaOf is the name of the MCP server that this server is a replica of, deployed in another region.
+	// A replica has no tools of its own: the calls to the tools of its primary server are routed to the replica
+	// closest to the caller.
+	ReplicaOf string `json:"replica_of,omitempty" gorm:"index"`
+
 	// Config describes the transport-specific configuration for the MCP server.
 	// It contains the JSON representation of either StreamableHTTPConfig or StdioConfig.
 	Config datatypes.JSON `json:"config" gorm:"type:jsonb;not null"`
+
+	// ProtocolVersion is the MCP protocol version negotiated with the server during initialization
+	ProtocolVersion string `json:"protocol_version,omitempty"`
+
+	// Capabilities contains the capabilities that the server advertised during initialization,
+	// in the JSON format defined by the MCP specification.
+	Capabilities datatypes.JSON `json:"capabilities,omitempty" gorm:"type:jsonb"`
+
+	// ServerInfo contains the name and version of the server implementation, as reported by the server itself
+	// at registration or when it was last pinned.
+	ServerInfo datatypes.JSON `json:"server_info,omitempty" gorm:"type:jsonb"`
+
+	// IdentityPinning determines what happens when the server reports a different identity than ServerInfo
+	IdentityPinning types.IdentityPinning `json:"identity_pinning,omitempty" gorm:"type:varchar(10)"`
+
+	// KeepWarm makes mcpjungle connect to the server at startup and re-use that connection for all tool calls,
+	// so that calls are not slowed down by connecting to the server (or starting its process) every time.
+	KeepWarm bool `json:"keep_warm"`
+
+	// RetryPolicy contains the JSON representation of the server's types.RetryPolicy, if any
+	RetryPolicy datatypes.JSON `json:"retry_policy,omitempty" gorm:"type:jsonb"`
+
+	// Group is an optional name of the subsystem the server belongs to (eg- analytics),
+	// whose servers can be stopped & started together
+	Group string `json:"group,omitempty" gorm:"index"`
+
+	// Stopped is true while the server is stopped: its tools are disabled and mcpjungle doesn't connect to it.
+	// StoppedTools contains the JSON list of the tools that were enabled when it was stopped, to re-enable on start.
+	Stopped      bool           `json:"stopped,omitempty"`
+	StoppedTools datatypes.JSON `json:"stopped_tools,omitempty" gorm:"type:jsonb"`
 }
 
 // NewStreamableHTTPServer creates a new MCP server with streamable HTTP transport configuration.
@@ -65,15 +126,27 @@ func NewStreamableHTTPServer(name, description, url, bearerToken string) (*McpSe
 }
 
 // NewStdioServer creates a new MCP server with stdio transport configuration.
-func NewStdioServer(name, description, command string, args []string, env map[string]string) (*McpServer, error) {
-	if command == "" {
+func NewStdioServer(name, description string, config StdioConfig) (*McpServer, error) {
+	if config.Command == "" {
 		return nil, errors.New("command is required for stdio transport")
 	}
-	config := StdioConfig{
-		Command: command,
-		Args:    args,
-		Env:     env,
+	if config.Cwd != "" && !filepath.IsAbs(config.Cwd) {
+		return nil, fmt.Errorf("cwd must be an absolute path: %s", config.Cwd)
 	}
+	if config.Integrity != nil {
+		if err := config.Integrity.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	framing, err := types.ValidateStdioFraming(string(config.Framing))
+	if err != nil {
+		return nil, err
+	}
+	if framing == types.StdioFramingLenient {
+		// the default is not stored, so that the configuration of existing servers is unchanged
+		framing = ""
+	}
+	config.Framing = framing
 	configJSON, err := json.Marshal(config)
 	if err != nil {
 		return nil, err
@@ -87,6 +160,17 @@ func NewStdioServer(name, description, command string, args []string, env map[st
 	}, nil
 }
 
+// NewBuiltinServer creates a new MCP server that runs inside mcpjungle.
+// Built-in servers have no transport-specific configuration.
+func NewBuiltinServer(name, description string) *McpServer {
+	return &McpServer{
+		Name:        name,
+		Description: description,
+		Transport:   types.TransportBuiltin,
+		Config:      datatypes.JSON("{}"),
+	}
+}
+
 // GetStreamableHTTPConfig returns the configuration if this is a streamable HTTP server
 func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	if s.Transport != types.TransportStreamableHTTP {
@@ -99,6 +183,21 @@ func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	return &config, nil
 }
 
+// SetSpiffeID sets the SPIFFE ID of a streamable HTTP server, which makes mcpjungle connect to it with mTLS.
+func (s *McpServer) SetSpiffeID(id string) error {
+	config, err := s.GetStreamableHTTPConfig()
+	if err != nil {
+		return err
+	}
+	config.SpiffeID = id
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	s.Config = data
+	return nil
+}
+
 // GetStdioConfig returns the configuration if this is a stdio server
 func (s *McpServer) GetStdioConfig() (*StdioConfig, error) {
 	if s.Transport != types.TransportStdio {
@@ -110,3 +209,49 @@ func (s *McpServer) GetStdioConfig() (*StdioConfig, error) {
 	}
 	return &config, nil
 }
+
+// SetRetryPolicy validates and sets the retry policy of the server. A nil policy disables retries.
+func (s *McpServer) SetRetryPolicy(p *types.RetryPolicy) error {
+	if p == nil {
+		s.RetryPolicy = nil
+		return nil
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	s.RetryPolicy = data
+	return nil
+}
+
+// GetRetryPolicy returns the retry policy of the server, or nil if failed tool calls must not be retried.
+func (s *McpServer) GetRetryPolicy() (*types.RetryPolicy, error) {
+	if len(s.RetryPolicy) == 0 {
+		return nil, nil
+	}
+	var p types.RetryPolicy
+	if err := json.Unmarshal(s.RetryPolicy, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetServerInfo returns the implementation name & version recorded for the server, or nil if it's unknown.
+func (s *McpServer) GetServerInfo() *types.ServerInfo {
+	if len(s.ServerInfo) == 0 {
+		return nil
+	}
+	var info types.ServerInfo
+	if err := json.Unmarshal(s.ServerInfo, &info); err != nil || info.Name == "" {
+		return nil
+	}
+	return &info
+}
+
+// PinsIdentity returns true if changes of the server's identity are alerted on or blocked.
+func (s *McpServer) PinsIdentity() bool {
+	return s.IdentityPinning == types.IdentityPinningAlert || s.IdentityPinning == types.IdentityPinningBlock
+}
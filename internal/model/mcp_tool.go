@@ -1,6 +1,9 @@
 package model
 
 import (
+	"encoding/json"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -13,7 +16,10 @@ type Tool struct {
 	// A tool name is unique only within the context of a server.
 	// This means that two tools in mcpjungle DB CAN have the same name because
 	// they belong to different servers, identified by server ID.
-	Name string `json:"name" gorm:"not null"`
+	Name string `json:"name" gorm:"not null;index:idx_tools_name_server_id,priority:1"`
+
+	// Version is incremented every time the record is modified, to detect concurrent modifications
+	Version int `json:"version" gorm:"not null;default:1"`
 
 	// Enabled indicates whether the tool is enabled or not.
 	// If a tool is disabled, it cannot be viewed or called from the MCP proxy.
@@ -24,7 +30,92 @@ type Tool struct {
 	// InputSchema is a JSON schema that describes the input parameters for the tool.
 	InputSchema datatypes.JSON `json:"input_schema" gorm:"type:jsonb"`
 
+	// Annotations contains the optional hints (title, read-only, destructive, etc.) that the
+	// upstream server provides to describe the tool's behaviour.
+	Annotations datatypes.JSON `json:"annotations,omitempty" gorm:"type:jsonb"`
+
+	// OutputSchema is an optional JSON schema (of type object) that describes the tool's results.
+	// It is declared to MCP clients and used to convert JSON text results into structured content
+	// for upstream servers that don't return structured content themselves.
+	OutputSchema datatypes.JSON `json:"output_schema,omitempty" gorm:"type:jsonb"`
+
+	// CostPerCall is the (arbitrary unit) cost charged every time the tool is called.
+	CostPerCall float64 `json:"cost_per_call"`
+
+	// CostPerUnit is charged for every unit of usage requested by a call, on top of CostPerCall.
+	// The number of units is read from the numeric input argument named by CostUnitArg
+	// (eg- the number of results requested from a search API).
+	CostPerUnit float64 `json:"cost_per_unit"`
+	CostUnitArg string  `json:"cost_unit_arg,omitempty"`
+
+	// HedgeDelayMs enables hedged requests for the tool if positive.
+	// If a call has not completed after this many milliseconds, a second request is sent to an equivalent
+	// server (one that provides a tool with the same name) and the first successful response is used.
+	// Only idempotent tools are hedged.
+	HedgeDelayMs int `json:"hedge_delay_ms,omitempty"`
+
+	// DescriptionOverride replaces the description provided by the upstream server, if set.
+	// DescriptionAppend is added after the description and Examples (a JSON array of strings) are listed at the end.
+	// They let admins improve the documentation of poorly documented tools, see EnrichedDescription.
+	DescriptionOverride string         `json:"description_override,omitempty"`
+	DescriptionAppend   string         `json:"description_append,omitempty"`
+	Examples            datatypes.JSON `json:"examples,omitempty" gorm:"type:jsonb"`
+
+	// ArgsScript & ResultScript are optional CEL scripts that transform the arguments of every call to the tool
+	// and the results of every successful call, see the script package.
+	ArgsScript   string `json:"args_script,omitempty"`
+	ResultScript string `json:"result_script,omitempty"`
+
+	// PayloadSampleRate is the fraction (0 to 1) of the calls to the tool whose arguments & results are stored
+	// in the invocation history, after removing the fields listed in PayloadRedactions (a JSON array of paths).
+	// 0 turns payload logging off.
+	PayloadSampleRate float64        `json:"payload_sample_rate,omitempty"`
+	PayloadRedactions datatypes.JSON `json:"payload_redactions,omitempty" gorm:"type:jsonb"`
+
 	// ServerID is the ID of the MCP server that provides this tool.
-	ServerID uint      `json:"-" gorm:"not null"`
+	// It is indexed on its own for listing the tools of a server and along with the tool's name for looking up a tool.
+	ServerID uint      `json:"-" gorm:"not null;index;index:idx_tools_name_server_id,priority:2"`
 	Server   McpServer `json:"-" gorm:"foreignKey:ServerID;references:ID"`
 }
+
+// CallCost returns the cost of calling this tool with the given input arguments.
+func (t *Tool) CallCost(args map[string]any) float64 {
+	cost := t.CostPerCall
+	if t.CostUnitArg == "" || t.CostPerUnit == 0 {
+		return cost
+	}
+	if units, ok := args[t.CostUnitArg].(float64); ok && units > 0 {
+		cost += units * t.CostPerUnit
+	}
+	return cost
+}
+
+// GetExamples returns the usage examples of this tool.
+func (t *Tool) GetExamples() []string {
+	var examples []string
+	if len(t.Examples) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(t.Examples, &examples); err != nil {
+		return nil
+	}
+	return examples
+}
+
+// GetPayloadRedactions returns the paths of the fields removed from the logged payloads of this tool.
+func (t *Tool) GetPayloadRedactions() []string {
+	var paths []string
+	if len(t.PayloadRedactions) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(t.PayloadRedactions, &paths); err != nil {
+		return nil
+	}
+	return paths
+}
+
+// EnrichedDescription returns the description of this tool that is served to MCP clients,
+// ie, the upstream description enriched with the documentation added by admins.
+func (t *Tool) EnrichedDescription() string {
+	return types.EnrichToolDescription(t.Description, t.DescriptionOverride, t.DescriptionAppend, t.GetExamples())
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification records an operational event that needs the attention of an admin,
+// eg- an MCP server that can't be reached or a budget that has been exceeded.
+type Notification struct {
+	gorm.Model
+
+	// Kind is the type of event, see the Notification* constants in the types package
+	Kind string `json:"kind" gorm:"index;not null"`
+
+	// Subject is the name of the entity that the event is about (eg- the name of the MCP server)
+	Subject string `json:"subject" gorm:"index;not null"`
+
+	Message string `json:"message"`
+
+	// Username is the name of the user whose action raised the event, if any (eg- the user who proposed an MCP server).
+	// It identifies the notifications to delete when purging the data of the user.
+	Username string `json:"username,omitempty" gorm:"index"`
+
+	// Count is the number of times the event occurred while the notification was unread
+	Count int `json:"count" gorm:"not null;default:1"`
+
+	// ReadAt is set once an admin has marked the notification as read
+	ReadAt *time.Time `json:"read_at,omitempty" gorm:"index"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type PolicyLanguage string
+
+const (
+	// PolicyLanguageCEL is the Common Expression Language (https://cel.dev)
+	PolicyLanguageCEL PolicyLanguage = "cel"
+
+	// PolicyLanguageArgs is a declarative JSON document restricting the argument values sent to tools
+	PolicyLanguageArgs PolicyLanguage = "args"
+
+	// PolicyLanguageEgress is a declarative JSON document allowlisting the domains & file paths that
+	// the arguments sent to tools may point to
+	PolicyLanguageEgress PolicyLanguage = "egress"
+)
+
+// Policy is evaluated before every tool invocation to decide whether the call is allowed, denied or
+// allowed with transformed arguments.
+type Policy struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	Language   PolicyLanguage `json:"language" gorm:"type:varchar(10);not null"`
+	Expression string         `json:"expression" gorm:"not null"`
+
+	// Priority determines the order in which policies are evaluated, lowest first.
+	// Policies with the same priority are evaluated in order of their names.
+	Priority int `json:"priority"`
+}
+
+func (p *Policy) BeforeSave(tx *gorm.DB) (err error) {
+	if p.Language != PolicyLanguageCEL && p.Language != PolicyLanguageArgs && p.Language != PolicyLanguageEgress {
+		return fmt.Errorf("unsupported policy language: %s", p.Language)
+	}
+	if p.Expression == "" {
+		return fmt.Errorf("policy %s must have an expression", p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// promptPlaceholderRegex matches the {{argument}} and {{client.<attribute>}} placeholders in a prompt template
+var promptPlaceholderRegex = regexp.MustCompile(`{{\s*([a-zA-Z0-9_.-]+)\s*}}`)
+
+// promptClientVarPrefix is the prefix of the placeholders that refer to the MCP client getting the prompt
+const promptClientVarPrefix = "client."
+
+// Prompt is a prompt template managed by mcpjungle.
+// It is served to MCP clients by the MCP proxy, alongside the tools of the registered MCP servers.
+type Prompt struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	// Arguments contains the JSON array of the prompt's types.PromptArgument
+	Arguments datatypes.JSON `json:"arguments" gorm:"type:jsonb"`
+
+	// Template is the text of the prompt. Occurrences of {{argument}} are replaced by the argument's value.
+	// {{client.name}} and {{client.<attribute>}} are replaced by the name & attributes of the MCP client.
+	Template string `json:"template" gorm:"not null"`
+
+	// Overrides contains a JSON object that maps the names of MCP clients to the templates used
+	// instead of Template for them.
+	Overrides datatypes.JSON `json:"overrides,omitempty" gorm:"type:jsonb"`
+}
+
+// GetArguments returns the arguments of the prompt.
+func (p *Prompt) GetArguments() []types.PromptArgument {
+	var args []types.PromptArgument
+	if len(p.Arguments) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(p.Arguments, &args); err != nil {
+		return nil
+	}
+	return args
+}
+
+// GetOverrides returns the per-client templates of the prompt, keyed by client name.
+func (p *Prompt) GetOverrides() map[string]string {
+	var overrides map[string]string
+	if len(p.Overrides) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(p.Overrides, &overrides); err != nil {
+		return nil
+	}
+	return overrides
+}
+
+// Render returns the prompt's template with its placeholders replaced by the values of the arguments
+// and by the name & attributes of the MCP client, if any. The client's override is used if it has one.
+// Placeholders of optional arguments and attributes that aren't set are replaced by an empty string.
+func (p *Prompt) Render(values map[string]string, client *McpClient) (string, error) {
+	for _, a := range p.GetArguments() {
+		if _, ok := values[a.Name]; a.Required && !ok {
+			return "", fmt.Errorf("missing required argument: %s", a.Name)
+		}
+	}
+
+	template := p.Template
+	vars := make(map[string]string, len(values))
+	for k, v := range values {
+		vars[k] = v
+	}
+	if client != nil {
+		if override, ok := p.GetOverrides()[client.Name]; ok {
+			template = override
+		}
+		for k, v := range client.GetAttributes() {
+			vars[promptClientVarPrefix+k] = v
+		}
+		vars[promptClientVarPrefix+"name"] = client.Name
+	}
+
+	return promptPlaceholderRegex.ReplaceAllStringFunc(template, func(m string) string {
+		return vars[promptPlaceholderRegex.FindStringSubmatch(m)[1]]
+	}), nil
+}
+
+func (p *Prompt) BeforeSave(tx *gorm.DB) (err error) {
+	if p.Template == "" {
+		return fmt.Errorf("template of prompt %s must not be empty", p.Name)
+	}
+	declared := make(map[string]bool)
+	if len(p.Arguments) > 0 {
+		var args []types.PromptArgument
+		if err := json.Unmarshal(p.Arguments, &args); err != nil {
+			return fmt.Errorf("invalid arguments of prompt %s: %w", p.Name, err)
+		}
+		for _, a := range args {
+			if a.Name == "" || strings.HasPrefix(a.Name, promptClientVarPrefix) {
+				return fmt.Errorf("invalid argument name '%s' in prompt %s", a.Name, p.Name)
+			}
+			if declared[a.Name] {
+				return fmt.Errorf("argument %s of prompt %s is declared more than once", a.Name, p.Name)
+			}
+			declared[a.Name] = true
+		}
+	}
+
+	templates := map[string]string{"template": p.Template}
+	if len(p.Overrides) > 0 {
+		var overrides map[string]string
+		if err := json.Unmarshal(p.Overrides, &overrides); err != nil {
+			return fmt.Errorf("invalid overrides of prompt %s: %w", p.Name, err)
+		}
+		for client, t := range overrides {
+			templates["override for client "+client] = t
+		}
+	}
+	for what, t := range templates {
+		for _, m := range promptPlaceholderRegex.FindAllStringSubmatch(t, -1) {
+			if !declared[m[1]] && !strings.HasPrefix(m[1], promptClientVarPrefix) {
+				return fmt.Errorf("%s of prompt %s uses undeclared argument %s", what, p.Name, m[1])
+			}
+		}
+	}
+	return nil
+}
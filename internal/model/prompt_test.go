@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+func TestPromptRender(t *testing.T) {
+	p := &Prompt{
+		Template:  "Hi {{client.name}} from {{client.team}}, review {{code}}{{client.missing}}",
+		Arguments: datatypes.JSON(`[{"name":"code","required":true}]`),
+		Overrides: datatypes.JSON(`{"ci-bot":"[{{client.env}}] {{code}}"}`),
+	}
+	values := map[string]string{"code": "x := 1"}
+
+	tests := []struct {
+		name   string
+		client *McpClient
+		want   string
+	}{
+		{name: "no client", want: "Hi  from , review x := 1"},
+		{
+			name:   "client attributes",
+			client: &McpClient{Name: "cursor", Attributes: datatypes.JSON(`{"team":"payments"}`)},
+			want:   "Hi cursor from payments, review x := 1",
+		},
+		{
+			name:   "client override",
+			client: &McpClient{Name: "ci-bot", Attributes: datatypes.JSON(`{"env":"staging"}`)},
+			want:   "[staging] x := 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Render(values, tt.client)
+			if err != nil || got != tt.want {
+				t.Errorf("Render() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := p.Render(nil, nil); err == nil {
+		t.Error("Render() without a required argument succeeded, want error")
+	}
+	p.Overrides = datatypes.JSON(`{"ci-bot":"{{undeclared}}"}`)
+	if err := p.BeforeSave(nil); err == nil {
+		t.Error("BeforeSave() with an undeclared argument in an override succeeded, want error")
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ReplaySuite is a set of tool calls recorded against an MCP server along with their outcomes.
+// Replaying the calls after the server is upgraded shows how its results, latency and errors changed.
+type ReplaySuite struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	// Server is the name of the MCP server that the calls were recorded against
+	Server string `json:"server" gorm:"not null"`
+
+	// Calls contains the JSON array of the recorded calls (see types.RecordedCall)
+	Calls datatypes.JSON `json:"calls" gorm:"type:jsonb"`
+}
+
+// GetCalls returns the recorded calls.
+func (s *ReplaySuite) GetCalls() []types.RecordedCall {
+	var calls []types.RecordedCall
+	if len(s.Calls) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(s.Calls, &calls); err != nil {
+		return nil
+	}
+	return calls
+}
+
+func (s *ReplaySuite) BeforeSave(tx *gorm.DB) (err error) {
+	if s.Server == "" {
+		return fmt.Errorf("server of replay suite %s must not be empty", s.Name)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type ResidencyAction string
+
+const (
+	// ResidencyActionReject rejects tool calls to servers outside the allowed regions
+	ResidencyActionReject ResidencyAction = "reject"
+
+	// ResidencyActionReroute sends tool calls to servers outside the allowed regions to an equivalent
+	// server (one that provides a tool with the same name) in an allowed region instead.
+	// If no such server exists, the call is rejected.
+	ResidencyActionReroute ResidencyAction = "reroute"
+)
+
+// ResidencyPolicy restricts the tool calls of an MCP client or a user to MCP servers in specific regions.
+type ResidencyPolicy struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	// Exactly one of ClientName & Username must be set.
+	// It determines whose tool calls are restricted by this policy.
+	ClientName string `json:"client,omitempty"`
+	Username   string `json:"user,omitempty"`
+
+	// AllowedRegions contains the regions that the client or user's tool calls may be served from.
+	// It is stored as a JSON array of region names.
+	AllowedRegions datatypes.JSON `json:"allowed_regions" gorm:"type:jsonb;not null"`
+
+	Action ResidencyAction `json:"action" gorm:"type:varchar(10);not null"`
+}
+
+// GetAllowedRegions returns the list of regions allowed by this policy.
+func (p *ResidencyPolicy) GetAllowedRegions() []string {
+	var regions []string
+	if err := json.Unmarshal(p.AllowedRegions, &regions); err != nil {
+		return nil
+	}
+	return regions
+}
+
+// AllowsRegion returns true if the policy allows tool calls to be served from the given region.
+// A server without a region never satisfies a residency policy.
+func (p *ResidencyPolicy) AllowsRegion(region string) bool {
+	return region != "" && slices.Contains(p.GetAllowedRegions(), region)
+}
+
+func (p *ResidencyPolicy) BeforeSave(tx *gorm.DB) (err error) {
+	if (p.ClientName == "") == (p.Username == "") {
+		return fmt.Errorf("residency policy %s must apply to exactly one of an MCP client or a user", p.Name)
+	}
+	if p.Action != ResidencyActionReject && p.Action != ResidencyActionReroute {
+		return fmt.Errorf("invalid residency policy action: %s", p.Action)
+	}
+	if len(p.GetAllowedRegions()) == 0 {
+		return fmt.Errorf("residency policy %s must allow at least one region", p.Name)
+	}
+	return nil
+}
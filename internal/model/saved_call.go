@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// SavedCall is a named preset of arguments for a tool.
+// Saved calls are shared by all users of the registry, so they can be used to standardize common operations.
+type SavedCall struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	// Tool is the canonical name of the tool that the saved call invokes
+	Tool string `json:"tool" gorm:"not null"`
+
+	// Args contains the JSON object of the arguments passed to the tool
+	Args datatypes.JSON `json:"args" gorm:"type:jsonb"`
+}
+
+// GetArgs returns the arguments passed to the tool.
+func (s *SavedCall) GetArgs() map[string]any {
+	var args map[string]any
+	if len(s.Args) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(s.Args, &args); err != nil {
+		return nil
+	}
+	return args
+}
+
+func (s *SavedCall) BeforeSave(tx *gorm.DB) (err error) {
+	if s.Tool == "" {
+		return fmt.Errorf("tool of saved call %s must not be empty", s.Name)
+	}
+	if len(s.Args) > 0 {
+		var args map[string]any
+		if err := json.Unmarshal(s.Args, &args); err != nil {
+			return fmt.Errorf("arguments of saved call %s must be a JSON object: %w", s.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package model
+
+import "gorm.io/gorm"
+
+// Secret is a named value managed by the registry, that the configuration of MCP servers can refer to
+// instead of containing the value itself. Its value is never returned by the API.
+type Secret struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	// Value is encrypted if the registry has a secrets encryption key, see secret.SecretService
+	Value string `json:"-" gorm:"not null"`
+
+	// Version is incremented every time the value is changed
+	Version int `json:"version" gorm:"not null;default:1"`
+}
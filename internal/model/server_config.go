@@ -25,6 +25,11 @@ type ServerConfig struct {
 	// Initialized indicates whether the server has been initialized.
 	// If this is set to false, the server is not yet ready for use and all requests to it should be rejected.
 	Initialized bool `gorm:"not null;default:false"`
+
+	// ReadOnly indicates whether the registry is in read-only mode.
+	// In read-only mode, all management operations that modify the registry are rejected,
+	// but tools can still be listed and called.
+	ReadOnly bool `gorm:"not null;default:false"`
 }
 
 func (c *ServerConfig) BeforeSave(tx *gorm.DB) (err error) {
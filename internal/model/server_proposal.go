@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ServerProposal is a server registration proposed by a user, that only takes effect once an admin approves it.
+type ServerProposal struct {
+	gorm.Model
+
+	// Name is the name of the proposed server. Several proposals may exist for a name, but only one pending.
+	Name string `json:"name" gorm:"index;not null"`
+
+	// Server contains the JSON representation of the McpServer that is registered when the proposal is approved,
+	// including the capabilities it advertised during the preflight check, once an admin ran it.
+	Server datatypes.JSON `json:"server" gorm:"type:jsonb;not null"`
+
+	// Tools contains the JSON array of the tools that the server provided during the preflight check,
+	// it is empty until an admin runs the check
+	Tools datatypes.JSON `json:"tools" gorm:"type:jsonb"`
+
+	ProposedBy string                     `json:"proposed_by"`
+	Status     types.ServerProposalStatus `json:"status" gorm:"type:varchar(12);not null;index"`
+
+	ReviewedBy    string `json:"reviewed_by"`
+	ReviewComment string `json:"review_comment"`
+}
+
+// GetServer returns the proposed server.
+func (p *ServerProposal) GetServer() (*McpServer, error) {
+	var s McpServer
+	if err := json.Unmarshal(p.Server, &s); err != nil {
+		return nil, fmt.Errorf("invalid server in proposal %d: %w", p.ID, err)
+	}
+	return &s, nil
+}
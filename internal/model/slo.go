@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// SLO is a service level objective for the calls made to a tool or to all tools of an MCP server.
+// Its compliance is computed over a rolling window of invocation history.
+type SLO struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	// Exactly one of ToolName & ServerName must be set.
+	// It determines which calls are tracked by this SLO.
+	ToolName   string `json:"tool,omitempty"`
+	ServerName string `json:"server,omitempty"`
+
+	// LatencyPercentile & LatencyTargetMs declare the latency objective, eg- p95 of at most 2000ms.
+	// The latency objective is not tracked if LatencyTargetMs is 0.
+	LatencyPercentile float64 `json:"latency_percentile"`
+	LatencyTargetMs   int64   `json:"latency_target_ms"`
+
+	// MaxErrorRate is the highest percentage of failed calls allowed.
+	// The error rate objective is not tracked if it is 0.
+	MaxErrorRate float64 `json:"max_error_rate"`
+
+	// Window is the rolling period over which compliance is computed, eg- "1d" or "12h"
+	Window string `json:"window" gorm:"type:varchar(20);not null"`
+
+	// Status is the compliance of the SLO in the current window. It is computed from usage records, not stored.
+	Status *types.SLOStatus `json:"status,omitempty" gorm:"-"`
+}
+
+func (s *SLO) BeforeSave(tx *gorm.DB) (err error) {
+	if (s.ToolName == "") == (s.ServerName == "") {
+		return fmt.Errorf("SLO %s must apply to exactly one of a tool or an MCP server", s.Name)
+	}
+	if s.LatencyTargetMs < 0 || s.MaxErrorRate < 0 {
+		return fmt.Errorf("objectives of SLO %s must not be negative", s.Name)
+	}
+	if s.LatencyTargetMs == 0 && s.MaxErrorRate == 0 {
+		return fmt.Errorf("SLO %s must declare a latency target or a max error rate", s.Name)
+	}
+	if s.LatencyPercentile <= 0 || s.LatencyPercentile > 100 {
+		return fmt.Errorf("latency percentile of SLO %s must be between 0 and 100", s.Name)
+	}
+	if s.MaxErrorRate > 100 {
+		return fmt.Errorf("max error rate of SLO %s must be a percentage between 0 and 100", s.Name)
+	}
+	if _, err := types.ParseLookback(s.Window); err != nil {
+		return fmt.Errorf("invalid window of SLO %s: %w", s.Name, err)
+	}
+	return nil
+}
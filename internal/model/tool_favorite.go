@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ToolFavorite is a tool starred by a user.
+type ToolFavorite struct {
+	gorm.Model
+
+	Username string `json:"username" gorm:"not null;uniqueIndex:idx_tool_favorites_username_tool"`
+
+	// Tool is the canonical name of the starred tool
+	Tool string `json:"tool" gorm:"not null;uniqueIndex:idx_tool_favorites_username_tool"`
+}
+
+// ToolView is a personal view of a user on the tools in the registry, eg- the tools relevant to a project.
+type ToolView struct {
+	gorm.Model
+
+	Username string `json:"username" gorm:"not null;uniqueIndex:idx_tool_views_username_name"`
+	Name     string `json:"name" gorm:"not null;uniqueIndex:idx_tool_views_username_name"`
+
+	// Tools contains the canonical names of the tools and the names of the MCP servers (all their tools)
+	// in the view, stored as a JSON array.
+	Tools datatypes.JSON `json:"tools" gorm:"type:jsonb;not null"`
+}
+
+// GetTools returns the names of the tools & MCP servers in the view.
+func (v *ToolView) GetTools() []string {
+	var tools []string
+	if err := json.Unmarshal(v.Tools, &tools); err != nil {
+		return nil
+	}
+	return tools
+}
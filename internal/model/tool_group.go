@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ToolGroup is a named subset of the tools in the registry.
+// Every group is exposed to MCP clients on its own MCP proxy endpoint, which only lists & calls the group's tools.
+type ToolGroup struct {
+	gorm.Model
+
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+
+	// IncludedTools contains the canonical names of the tools in this group, stored as a JSON array.
+	IncludedTools datatypes.JSON `json:"included_tools" gorm:"type:jsonb;not null"`
+
+	// ExtractionProfile is the optional name of the extraction profile applied to the results of
+	// tool calls made via this group's endpoint.
+	ExtractionProfile string `json:"extraction_profile,omitempty"`
+}
+
+// GetIncludedTools returns the canonical names of the tools in this group.
+func (g *ToolGroup) GetIncludedTools() []string {
+	var tools []string
+	if err := json.Unmarshal(g.IncludedTools, &tools); err != nil {
+		return nil
+	}
+	return tools
+}
+
+// HasTool returns true if the tool with the given canonical name is part of this group.
+func (g *ToolGroup) HasTool(name string) bool {
+	return slices.Contains(g.GetIncludedTools(), name)
+}
+
+func (g *ToolGroup) BeforeSave(tx *gorm.DB) (err error) {
+	if len(g.GetIncludedTools()) == 0 {
+		return fmt.Errorf("tool group %s must include at least one tool", g.Name)
+	}
+	return nil
+}
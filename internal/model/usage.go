@@ -0,0 +1,147 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ToolInvocation is a usage record, created every time a tool is called via mcpjungle.
+// It is used to keep track of spend per MCP client & user.
+type ToolInvocation struct {
+	gorm.Model
+
+	// ToolName is the canonical name of the tool that was called
+	ToolName string `json:"tool_name" gorm:"index;not null"`
+
+	// ClientName is the name of the MCP client that called the tool via the MCP proxy (production mode only)
+	ClientName string `json:"client_name,omitempty" gorm:"index"`
+
+	// Username is the name of the user that called the tool via the API (production mode only)
+	Username string `json:"username,omitempty" gorm:"index"`
+
+	Cost    float64 `json:"cost"`
+	IsError bool    `json:"is_error"`
+
+	// Attempts is the number of times the call was attempted, retries included
+	Attempts int `json:"attempts"`
+
+	// DurationMs is the time taken by the call in milliseconds, retries included
+	DurationMs int64 `json:"duration_ms"`
+
+	// ImpersonatedBy is the username of the admin who made the call on behalf of the client or user, if any.
+	// It keeps an audit trail of calls made while troubleshooting.
+	ImpersonatedBy string `json:"impersonated_by,omitempty" gorm:"index"`
+
+	// Arguments & Result contain the redacted JSON payloads of the call, only for the calls sampled
+	// for payload logging (see Tool.PayloadSampleRate)
+	Arguments datatypes.JSON `json:"arguments,omitempty" gorm:"type:jsonb"`
+	Result    datatypes.JSON `json:"result,omitempty" gorm:"type:jsonb"`
+}
+
+// ClientSessionStat counts the MCP proxy sessions initialized in a day by a version of a client application,
+// as described by the clientInfo, protocol version & capabilities it sent at initialize.
+// There is a single row per day & distinct client, so the table stays small however many sessions are opened.
+type ClientSessionStat struct {
+	ID uint `gorm:"primaryKey"`
+
+	// Day is the UTC midnight of the day the sessions were initialized
+	Day time.Time `gorm:"not null;uniqueIndex:idx_client_session_stats_key,priority:1"`
+
+	ClientName      string `gorm:"not null;uniqueIndex:idx_client_session_stats_key,priority:2"`
+	ClientVersion   string `gorm:"not null;uniqueIndex:idx_client_session_stats_key,priority:3"`
+	ProtocolVersion string `gorm:"not null;uniqueIndex:idx_client_session_stats_key,priority:4"`
+
+	// Capabilities is the sorted, comma-separated list of the capabilities advertised by the client
+	// (eg- "roots,roots.listChanged,sampling")
+	Capabilities string `gorm:"not null;uniqueIndex:idx_client_session_stats_key,priority:5"`
+
+	Sessions   int64     `gorm:"not null;default:0"`
+	LastSeenAt time.Time `gorm:"not null"`
+}
+
+// PrunedSpend is the spend of the tool invocations of an MCP client or user that were deleted by the history retention.
+// Budgets over the total period add it to the spend of the invocations that are kept, so that pruning the history
+// doesn't refund their spend. There is a single row per client & user.
+type PrunedSpend struct {
+	ID uint `gorm:"primaryKey"`
+
+	ClientName string `gorm:"not null;default:'';uniqueIndex:idx_pruned_spends_key,priority:1"`
+	Username   string `gorm:"not null;default:'';uniqueIndex:idx_pruned_spends_key,priority:2"`
+
+	Cost float64 `gorm:"not null;default:0"`
+}
+
+type BudgetPeriod string
+
+const (
+	BudgetPeriodDaily   BudgetPeriod = "daily"
+	BudgetPeriodMonthly BudgetPeriod = "monthly"
+
+	// BudgetPeriodTotal means the budget applies to all spend, it is never reset
+	BudgetPeriodTotal BudgetPeriod = "total"
+)
+
+// Start returns the time at which the budget period containing t started.
+// All periods are calculated in UTC.
+func (p BudgetPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case BudgetPeriodDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case BudgetPeriodMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Time{}
+}
+
+// Budget limits how much an MCP client or a user can spend on tool calls within a period.
+type Budget struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;not null"`
+
+	// Exactly one of ClientName & Username must be set.
+	// It determines whose spend is tracked by this budget.
+	ClientName string `json:"client,omitempty"`
+	Username   string `json:"user,omitempty"`
+
+	MaxSpend float64      `json:"max_spend" gorm:"not null"`
+	Period   BudgetPeriod `json:"period" gorm:"type:varchar(10);not null"`
+
+	// AlertThreshold is the percentage of MaxSpend at which an alert is raised.
+	AlertThreshold float64 `json:"alert_threshold"`
+
+	// WebhookURL, if set, receives a POST request for every alert raised for this budget.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// HardStop rejects tool calls that would take the spend beyond MaxSpend.
+	// If false, the budget only raises alerts.
+	HardStop bool `json:"hard_stop"`
+
+	// Spend is the amount spent in the current period. It is computed from usage records, not stored.
+	Spend float64 `json:"spend" gorm:"-"`
+
+	// ThresholdAlertedAt & ExceededAlertedAt record when the alerts were last raised,
+	// so that each alert is raised at most once per period.
+	ThresholdAlertedAt *time.Time `json:"-"`
+	ExceededAlertedAt  *time.Time `json:"-"`
+}
+
+func (b *Budget) BeforeSave(tx *gorm.DB) (err error) {
+	if (b.ClientName == "") == (b.Username == "") {
+		return fmt.Errorf("budget %s must apply to exactly one of an MCP client or a user", b.Name)
+	}
+	if b.Period != BudgetPeriodDaily && b.Period != BudgetPeriodMonthly && b.Period != BudgetPeriodTotal {
+		return fmt.Errorf("invalid budget period: %s", b.Period)
+	}
+	if b.MaxSpend <= 0 {
+		return fmt.Errorf("max spend of budget %s must be greater than 0", b.Name)
+	}
+	if b.AlertThreshold < 0 || b.AlertThreshold > 100 {
+		return fmt.Errorf("alert threshold of budget %s must be a percentage between 0 and 100", b.Name)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// UpdateVersioned applies updates to the record of type T with the given ID, as long as its version is
+// still the expected one, and increments its version.
+// If the record was modified in the meantime, nothing is updated and a *types.VersionConflict is returned
+// with the current values of the updated fields.
+func UpdateVersioned[T any](db *gorm.DB, kind, name string, id uint, expected int, updates map[string]any) error {
+	values := make(map[string]any, len(updates)+1)
+	for k, v := range updates {
+		values[k] = v
+	}
+	values["version"] = gorm.Expr("version + 1")
+
+	res := db.Model(new(T)).Where("id = ? AND version = ?", id, expected).Updates(values)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+
+	var current T
+	if err := db.First(&current, id).Error; err != nil {
+		return fmt.Errorf("failed to get current version of %s %s: %w", kind, name, err)
+	}
+	fields := jsonFields(current)
+	conflict := &types.VersionConflict{
+		Kind:            kind,
+		Name:            name,
+		ExpectedVersion: expected,
+		Diff:            make(map[string]types.FieldDiff, len(updates)),
+	}
+	if v, ok := fields["version"].(float64); ok {
+		conflict.CurrentVersion = int(v)
+	}
+	for k, v := range updates {
+		// round-trip the requested value through JSON, so it is reported in the same form as the current one
+		var requested any
+		if b, err := json.Marshal(v); err == nil {
+			_ = json.Unmarshal(b, &requested)
+		}
+		conflict.Diff[k] = types.FieldDiff{Current: fields[k], Requested: requested}
+	}
+	return conflict
+}
+
+// jsonFields returns the fields of a record keyed by their JSON names, which match their column names.
+func jsonFields(record any) map[string]any {
+	var fields map[string]any
+	if b, err := json.Marshal(record); err == nil {
+		_ = json.Unmarshal(b, &fields)
+	}
+	return fields
+}
+
+// DeleteVersioned permanently deletes the record of type T with the given ID, as long as its version is
+// still the expected one.
+// If the record was modified in the meantime, nothing is deleted and a *types.VersionConflict is returned.
+func DeleteVersioned[T any](db *gorm.DB, kind, name string, id uint, expected int) error {
+	res := db.Unscoped().Where("id = ? AND version = ?", id, expected).Delete(new(T))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+
+	var current T
+	if err := db.First(&current, id).Error; err != nil {
+		return fmt.Errorf("failed to get current version of %s %s: %w", kind, name, err)
+	}
+	conflict := &types.VersionConflict{Kind: kind, Name: name, ExpectedVersion: expected}
+	if v, ok := jsonFields(current)["version"].(float64); ok {
+		conflict.CurrentVersion = int(v)
+	}
+	return conflict
+}
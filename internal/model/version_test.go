@@ -0,0 +1,90 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestUpdateVersioned(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&Tool{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	tool := Tool{Name: "query", ServerID: 1}
+	if err := db.Create(&tool).Error; err != nil {
+		t.Fatal(err)
+	}
+	if tool.Version != 1 {
+		t.Fatalf("new tool is at version %d, want 1", tool.Version)
+	}
+
+	// the first admin's change is based on the current version
+	if err := UpdateVersioned[Tool](db, "tool", "search__query", tool.ID, 1, map[string]any{"cost_per_call": 0.5}); err != nil {
+		t.Fatalf("UpdateVersioned() error = %v", err)
+	}
+
+	// the second admin's change is based on the version that the first one replaced
+	err = UpdateVersioned[Tool](db, "tool", "search__query", tool.ID, 1, map[string]any{"cost_per_call": 2.0})
+	var conflict *types.VersionConflict
+	if !errors.As(err, &conflict) {
+		t.Fatalf("UpdateVersioned() error = %v, want a version conflict", err)
+	}
+	if conflict.ExpectedVersion != 1 || conflict.CurrentVersion != 2 {
+		t.Errorf("conflict versions = %d/%d, want 1/2", conflict.ExpectedVersion, conflict.CurrentVersion)
+	}
+	if d := conflict.Diff["cost_per_call"]; d.Current != 0.5 || d.Requested != 2.0 {
+		t.Errorf("conflict diff = %+v, want current 0.5 and requested 2", d)
+	}
+
+	var stored Tool
+	if err := db.First(&stored, tool.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.CostPerCall != 0.5 || stored.Version != 2 {
+		t.Errorf("stored tool cost = %g at version %d, want 0.5 at version 2", stored.CostPerCall, stored.Version)
+	}
+}
+
+func TestDeleteVersioned(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&McpClient{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	client := McpClient{Name: "cursor", AccessToken: "token", AllowList: []byte("[]")}
+	if err := db.Create(&client).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&client).Update("version", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// the deletion is based on a version that was replaced in the meantime
+	err = DeleteVersioned[McpClient](db, "client", "cursor", client.ID, 1)
+	var conflict *types.VersionConflict
+	if !errors.As(err, &conflict) {
+		t.Fatalf("DeleteVersioned() error = %v, want a version conflict", err)
+	}
+	if conflict.ExpectedVersion != 1 || conflict.CurrentVersion != 2 {
+		t.Errorf("conflict versions = %d/%d, want 1/2", conflict.ExpectedVersion, conflict.CurrentVersion)
+	}
+
+	if err := DeleteVersioned[McpClient](db, "client", "cursor", client.ID, 2); err != nil {
+		t.Fatalf("DeleteVersioned() error = %v", err)
+	}
+	var count int64
+	db.Unscoped().Model(&McpClient{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d clients left after deletion, want 0", count)
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookKey is a secret used to sign the payloads of outbound webhooks.
+// When keys are rotated, the previous keys remain valid until they expire so that receivers
+// have time to switch over to the new key.
+type WebhookKey struct {
+	gorm.Model
+
+	KeyID string `json:"key_id" gorm:"uniqueIndex;not null"`
+
+	// Secret is only ever returned to the admin when the key is created
+	Secret string `json:"secret,omitempty" gorm:"not null"`
+
+	// ExpiresAt is set when the key is superseded by a newer key.
+	// A nil value means that this is the current key.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// IsExpired returns true if the key can no longer be used to sign payloads.
+func (k *WebhookKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
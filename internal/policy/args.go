@@ -0,0 +1,191 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// argsPolicy is a declarative policy that restricts the argument values that callers may send to tools,
+// and removes (redacts) arguments before the call is forwarded. Its source is a JSON document:
+//
+//	{
+//	  "clients": ["analytics-bot"],
+//	  "tools": ["db__query"],
+//	  "args": {
+//	    "database": {"allowed": ["analytics"], "required": true},
+//	    "limit": {"max": 1000}
+//	  },
+//	  "redact": ["debug_token"]
+//	}
+//
+// The policy only applies to calls of the given tools (glob patterns) made by the given clients or users.
+// Without clients and users, it applies to all callers.
+type argsPolicy struct {
+	Clients []string                  `json:"clients,omitempty"`
+	Users   []string                  `json:"users,omitempty"`
+	Tools   []string                  `json:"tools"`
+	Args    map[string]*argConstraint `json:"args,omitempty"`
+	Redact  []string                  `json:"redact,omitempty"`
+}
+
+// argConstraint restricts the value of an argument. Constraints on an argument that is absent from a call
+// are not checked, unless the argument is required.
+type argConstraint struct {
+	Required bool `json:"required,omitempty"`
+
+	// Allowed & Denied list the values that the argument may or may not have
+	Allowed []any `json:"allowed,omitempty"`
+	Denied  []any `json:"denied,omitempty"`
+
+	// Pattern is a regular expression that string values must match
+	Pattern string `json:"pattern,omitempty"`
+
+	// Min & Max bound numeric values
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+func compileArgs(source string) (Evaluator, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(source)))
+	dec.DisallowUnknownFields()
+	var p argsPolicy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid args policy: %w", err)
+	}
+	if len(p.Tools) == 0 {
+		return nil, errors.New("invalid args policy: at least one tool is required")
+	}
+	for _, t := range p.Tools {
+		if _, err := path.Match(t, ""); err != nil {
+			return nil, fmt.Errorf("invalid args policy: invalid tool pattern %s", t)
+		}
+	}
+	if len(p.Args) == 0 && len(p.Redact) == 0 {
+		return nil, errors.New("invalid args policy: it must constrain or redact at least one argument")
+	}
+	for name, c := range p.Args {
+		if c == nil {
+			return nil, fmt.Errorf("invalid args policy: missing constraint of argument %s", name)
+		}
+		if c.Pattern != "" {
+			re, err := regexp.Compile(c.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid args policy: invalid pattern of argument %s: %w", name, err)
+			}
+			c.pattern = re
+		}
+	}
+	return &p, nil
+}
+
+func (p *argsPolicy) Evaluate(in *Input) (*Decision, error) {
+	if !p.applies(in) {
+		return &Decision{Action: ActionAllow}, nil
+	}
+
+	// check the arguments in a stable order, so that the reported violation is deterministic
+	names := make([]string, 0, len(p.Args))
+	for name := range p.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if reason := p.Args[name].check(name, in.Args); reason != "" {
+			return &Decision{Action: ActionDeny, Reason: reason}, nil
+		}
+	}
+
+	redacted := false
+	args := make(map[string]any, len(in.Args))
+	for k, v := range in.Args {
+		if slices.Contains(p.Redact, k) {
+			redacted = true
+			continue
+		}
+		args[k] = v
+	}
+	if redacted {
+		return &Decision{Action: ActionTransform, Args: args}, nil
+	}
+	return &Decision{Action: ActionAllow}, nil
+}
+
+// applies returns true if the call is made to one of the policy's tools by one of its callers.
+func (p *argsPolicy) applies(in *Input) bool {
+	name, _ := in.Tool["name"].(string)
+	if !slices.ContainsFunc(p.Tools, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}) {
+		return false
+	}
+	if len(p.Clients) == 0 && len(p.Users) == 0 {
+		return true
+	}
+	return (in.Client != "" && slices.Contains(p.Clients, in.Client)) || (in.User != "" && slices.Contains(p.Users, in.User))
+}
+
+// check returns the reason why the value of the argument violates the constraint, or an empty string.
+func (c *argConstraint) check(name string, args map[string]any) string {
+	v, ok := args[name]
+	if !ok {
+		if c.Required {
+			return fmt.Sprintf("argument %s is required", name)
+		}
+		return ""
+	}
+	if len(c.Allowed) > 0 && !slices.ContainsFunc(c.Allowed, func(a any) bool { return jsonEqual(a, v) }) {
+		return fmt.Sprintf("argument %s=%s is not allowed, it must be one of %s", name, jsonString(v), jsonString(c.Allowed))
+	}
+	if slices.ContainsFunc(c.Denied, func(d any) bool { return jsonEqual(d, v) }) {
+		return fmt.Sprintf("argument %s=%s is not allowed", name, jsonString(v))
+	}
+	if c.pattern != nil {
+		s, isString := v.(string)
+		if !isString || !c.pattern.MatchString(s) {
+			return fmt.Sprintf("argument %s=%s does not match the pattern %s", name, jsonString(v), c.Pattern)
+		}
+	}
+	if c.Min != nil || c.Max != nil {
+		n, isNumber := v.(float64)
+		if !isNumber {
+			return fmt.Sprintf("argument %s=%s must be a number", name, jsonString(v))
+		}
+		if (c.Min != nil && n < *c.Min) || (c.Max != nil && n > *c.Max) {
+			return fmt.Sprintf("argument %s=%s is out of the allowed range %s", name, jsonString(v), c.rangeString())
+		}
+	}
+	return ""
+}
+
+func (c *argConstraint) rangeString() string {
+	lo, hi := "-inf", "+inf"
+	if c.Min != nil {
+		lo = fmt.Sprint(*c.Min)
+	}
+	if c.Max != nil {
+		hi = fmt.Sprint(*c.Max)
+	}
+	return fmt.Sprintf("[%s, %s]", lo, hi)
+}
+
+// jsonEqual compares two JSON values, so that eg- the number 1 in a policy matches 1.0 in the arguments.
+func jsonEqual(a, b any) bool {
+	return jsonString(a) == jsonString(b)
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// celEvaluator evaluates policies written in the Common Expression Language (https://cel.dev)
+type celEvaluator struct {
+	program cel.Program
+}
+
+// celEnv declares the variables available to CEL policies
+var celEnv, celEnvErr = cel.NewEnv(
+	cel.Variable("client", cel.StringType),
+	cel.Variable("user", cel.StringType),
+	cel.Variable("tool", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("args", cel.MapType(cel.StringType, cel.DynType)),
+)
+
+// jsonValueType is used to convert the result of a CEL expression into plain (JSON-compatible) Go values
+var jsonValueType = reflect.TypeOf(&structpb.Value{})
+
+func compileCEL(source string) (Evaluator, error) {
+	if celEnvErr != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", celEnvErr)
+	}
+	ast, issues := celEnv.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid CEL expression: %w", issues.Err())
+	}
+	program, err := celEnv.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CEL program: %w", err)
+	}
+	return &celEvaluator{program: program}, nil
+}
+
+func (e *celEvaluator) Evaluate(in *Input) (*Decision, error) {
+	args := in.Args
+	if args == nil {
+		args = map[string]any{}
+	}
+	out, _, err := e.program.Eval(map[string]any{
+		"client": in.Client,
+		"user":   in.User,
+		"tool":   in.Tool,
+		"args":   args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate CEL expression: %w", err)
+	}
+
+	result, err := out.ConvertToNative(jsonValueType)
+	if err != nil {
+		return nil, fmt.Errorf("policy must return a boolean or an object, got %s", out.Type().TypeName())
+	}
+	return decisionFromResult(result.(*structpb.Value).AsInterface())
+}
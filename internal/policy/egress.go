@@ -0,0 +1,330 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// egressPolicy is a deny-by-default filter of the URLs and file paths found in the arguments of tool calls.
+// It blocks exfiltration-style calls, where a prompt injection points a tool at attacker infrastructure.
+// Its source is a JSON document:
+//
+//	{
+//	  "tools": ["*"],
+//	  "domains": ["*.example.com"],
+//	  "paths": ["/srv/shared"],
+//	  "per_tool": {
+//	    "fetch__*": {"domains": ["docs.python.org"]},
+//	    "filesystem__*": {"paths": ["/srv/data"]}
+//	  }
+//	}
+//
+// Every call to the given tools (glob patterns) made by the given clients or users (all callers if none are given)
+// is denied if one of its arguments contains a URL or a bare host whose host is not in the allowed domains,
+// or a file path that is not within one of the allowed directories.
+// The allowlists of per_tool extend the common ones for the matching tools.
+//
+// The filter is best-effort: hosts are only recognized without a scheme when they are the whole value (see egressBareHost),
+// and a destination assembled by the tool from several arguments can't be recognized.
+type egressPolicy struct {
+	Clients []string `json:"clients,omitempty"`
+	Users   []string `json:"users,omitempty"`
+	Tools   []string `json:"tools"`
+
+	egressAllowlist
+
+	PerTool map[string]*egressAllowlist `json:"per_tool,omitempty"`
+}
+
+// egressAllowlist lists the destinations that arguments may point to
+type egressAllowlist struct {
+	// Domains are host names, eg- "api.github.com", or wildcards matching all subdomains of a domain, eg- "*.github.com"
+	Domains []string `json:"domains,omitempty"`
+
+	// Paths are directories, arguments may contain the paths of the files within them
+	Paths []string `json:"paths,omitempty"`
+}
+
+// egressURL matches the URLs contained in strings, eg- "see https://example.com/x"
+var egressURL = regexp.MustCompile(`(?i)\b[a-z][a-z0-9+.\-]*://[^\s"'<>()\[\]{}]*`)
+
+// egressWindowsPath matches absolute Windows paths, eg- C:\Users
+var egressWindowsPath = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// egressHostname matches host names, eg- api.github.com or localhost
+var egressHostname = regexp.MustCompile(`(?i)^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*\.?$`)
+
+// egressHostArgs are the suffixes of the names of the arguments that hold a host, eg- host, api_url or serverAddress.
+// Names are compared in lower case without '_' and '-'.
+var egressHostArgs = []string{"host", "hostname", "domain", "url", "uri", "endpoint", "server", "address", "addr"}
+
+func compileEgress(source string) (Evaluator, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(source)))
+	dec.DisallowUnknownFields()
+	var p egressPolicy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid egress policy: %w", err)
+	}
+	if len(p.Tools) == 0 {
+		return nil, errors.New("invalid egress policy: at least one tool is required")
+	}
+	patterns := slices.Clone(p.Tools)
+	for pattern, allowlist := range p.PerTool {
+		if allowlist == nil {
+			return nil, fmt.Errorf("invalid egress policy: missing allowlist of tools %s", pattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+	for _, t := range patterns {
+		if _, err := path.Match(t, ""); err != nil {
+			return nil, fmt.Errorf("invalid egress policy: invalid tool pattern %s", t)
+		}
+	}
+
+	if err := p.egressAllowlist.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid egress policy: %w", err)
+	}
+	for _, allowlist := range p.PerTool {
+		if err := allowlist.normalize(); err != nil {
+			return nil, fmt.Errorf("invalid egress policy: %w", err)
+		}
+	}
+	return &p, nil
+}
+
+// normalize validates the allowlist and puts its entries in the form they are compared in
+func (a *egressAllowlist) normalize() error {
+	for i, d := range a.Domains {
+		d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d == "" || d == "*" || d == "*." || strings.ContainsAny(d, "/:") || strings.Contains(strings.TrimPrefix(d, "*."), "*") {
+			return fmt.Errorf("invalid domain '%s', must be a host name or *.<domain>", a.Domains[i])
+		}
+		a.Domains[i] = d
+	}
+	for i, p := range a.Paths {
+		if strings.TrimSpace(p) == "" {
+			return errors.New("paths must not be empty")
+		}
+		a.Paths[i] = cleanEgressPath(p)
+	}
+	return nil
+}
+
+func (p *egressPolicy) Evaluate(in *Input) (*Decision, error) {
+	name, _ := in.Tool["name"].(string)
+	if !p.applies(name, in) {
+		return &Decision{Action: ActionAllow}, nil
+	}
+
+	allowlist := egressAllowlist{Domains: p.Domains, Paths: p.Paths}
+	// the per-tool allowlists are merged in a stable order, for the messages to be deterministic
+	patterns := make([]string, 0, len(p.PerTool))
+	for pattern := range p.PerTool {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			allowlist.Domains = append(allowlist.Domains, p.PerTool[pattern].Domains...)
+			allowlist.Paths = append(allowlist.Paths, p.PerTool[pattern].Paths...)
+		}
+	}
+
+	names := make([]string, 0, len(in.Args))
+	for k := range in.Args {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if reason := allowlist.check(k, in.Args[k]); reason != "" {
+			return &Decision{Action: ActionDeny, Reason: reason}, nil
+		}
+	}
+	return &Decision{Action: ActionAllow}, nil
+}
+
+// applies returns true if the call is made to one of the policy's tools by one of its callers.
+func (p *egressPolicy) applies(name string, in *Input) bool {
+	if !slices.ContainsFunc(p.Tools, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}) {
+		return false
+	}
+	if len(p.Clients) == 0 && len(p.Users) == 0 {
+		return true
+	}
+	return (in.Client != "" && slices.Contains(p.Clients, in.Client)) || (in.User != "" && slices.Contains(p.Users, in.User))
+}
+
+// check returns the reason why an argument (or a value nested in it, at the given location) points to
+// a destination that isn't allowed, or an empty string.
+func (a *egressAllowlist) check(location string, v any) string {
+	switch v := v.(type) {
+	case string:
+		return a.checkString(location, v)
+	case []any:
+		for i, item := range v {
+			if reason := a.check(fmt.Sprintf("%s[%d]", location, i), item); reason != "" {
+				return reason
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if reason := a.check(location+"."+k, v[k]); reason != "" {
+				return reason
+			}
+		}
+	}
+	return ""
+}
+
+func (a *egressAllowlist) checkString(location, s string) string {
+	// URLs are looked for anywhere in the value, eg- in a prompt or a message
+	for _, raw := range egressURL.FindAllString(s, -1) {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Sprintf("argument %s contains an invalid URL %s", location, jsonString(raw))
+		}
+		if strings.EqualFold(u.Scheme, "file") {
+			if !a.allowsPath(u.Path) {
+				return fmt.Sprintf("argument %s points to the file %s, which is not within an allowed path", location, jsonString(u.Path))
+			}
+			continue
+		}
+		if !a.allowsHost(u.Hostname()) {
+			return fmt.Sprintf("argument %s points to %s, which is not an allowed domain", location, jsonString(u.Hostname()))
+		}
+	}
+
+	// file paths are only recognized when they are the whole value, so that slashes in prose aren't taken for paths
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, `\\`) {
+		// protocol-relative URL or UNC path, the first segment is a host
+		host, _, _ := strings.Cut(strings.TrimLeft(strings.ReplaceAll(trimmed, `\`, "/"), "/"), "/")
+		if !a.allowsHost(host) {
+			return fmt.Sprintf("argument %s points to %s, which is not an allowed domain", location, jsonString(host))
+		}
+		return ""
+	}
+	if isEgressPath(trimmed) {
+		if !a.allowsPath(trimmed) {
+			return fmt.Sprintf("argument %s=%s is not within an allowed path", location, jsonString(s))
+		}
+		return ""
+	}
+	if host, ok := egressBareHost(location, trimmed); ok && !a.allowsHost(host) {
+		return fmt.Sprintf("argument %s points to %s, which is not an allowed domain", location, jsonString(host))
+	}
+	return ""
+}
+
+// egressBareHost returns the host of a value that is entirely a destination without a scheme, eg- "evil.test",
+// "10.0.0.1", "evil.test:8080" or "evil.test/upload". To tell them apart from the values that only look like
+// host names (eg- "report.pdf"), a host name without a port or path is only recognized in an argument whose
+// name suggests a host, eg- "host" or "endpoint". IP addresses are always recognized.
+func egressBareHost(location, s string) (string, bool) {
+	if s == "" || strings.ContainsAny(s, " \t\n\r") {
+		return "", false
+	}
+	hostPort, _, hasPath := strings.Cut(s, "/")
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host, port = hostPort, ""
+	} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", false
+	}
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return ip.String(), true
+	}
+	if !egressHostname.MatchString(host) {
+		return "", false
+	}
+	if port != "" || (hasPath && strings.Contains(strings.TrimSuffix(host, "."), ".")) {
+		return host, true
+	}
+	return host, isEgressHostArg(location)
+}
+
+// isEgressHostArg returns true if the name of the argument at location suggests that it holds a host
+func isEgressHostArg(location string) bool {
+	// the name of the argument is the last segment of its location, eg- url for requests[0].url
+	for strings.HasSuffix(location, "]") {
+		i := strings.LastIndex(location, "[")
+		if i < 0 {
+			break
+		}
+		location = location[:i]
+	}
+	if i := strings.LastIndex(location, "."); i >= 0 {
+		location = location[i+1:]
+	}
+	name := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(location))
+	return slices.ContainsFunc(egressHostArgs, func(suffix string) bool {
+		return strings.HasSuffix(name, suffix)
+	})
+}
+
+// allowsHost returns true if the host matches one of the allowed domains
+func (a *egressAllowlist) allowsHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return false
+	}
+	for _, d := range a.Domains {
+		if suffix, ok := strings.CutPrefix(d, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == d {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPath returns true if the path is within one of the allowed directories, once ".." segments are resolved
+func (a *egressAllowlist) allowsPath(p string) bool {
+	p = cleanEgressPath(p)
+	for _, dir := range a.Paths {
+		if p == dir || dir == "/" || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isEgressPath returns true if a value looks like a file path: absolute, relative to the current
+// or parent directory, relative to the home directory, or an absolute Windows path.
+func isEgressPath(s string) bool {
+	if s == "" || strings.ContainsAny(s, "\n\r") {
+		return false
+	}
+	return strings.HasPrefix(s, "/") || s == "~" || strings.HasPrefix(s, "~/") ||
+		s == "." || s == ".." || strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") ||
+		strings.HasPrefix(s, `.\`) || strings.HasPrefix(s, `..\`) || egressWindowsPath.MatchString(s)
+}
+
+// cleanEgressPath resolves the ".." segments of a path and uses forward slashes as separators
+func cleanEgressPath(p string) string {
+	p = strings.TrimSpace(p)
+	if egressWindowsPath.MatchString(p) || strings.HasPrefix(p, `.\`) || strings.HasPrefix(p, `..\`) {
+		p = strings.ReplaceAll(p, `\`, "/")
+	}
+	return path.Clean(p)
+}
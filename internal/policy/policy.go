@@ -0,0 +1,97 @@
+// Package policy implements the evaluation of policies that decide whether a tool call is allowed.
+// Policy languages are pluggable, each language only needs to provide a compiler that turns
+// a policy's source into an Evaluator.
+package policy
+
+import (
+	"fmt"
+)
+
+// Action is the outcome of evaluating a policy against a tool call
+type Action string
+
+const (
+	ActionAllow Action = "allow"
+	ActionDeny  Action = "deny"
+
+	// ActionTransform allows the call, but with the arguments returned by the policy
+	ActionTransform Action = "transform"
+)
+
+// Input contains everything a policy knows about a tool call
+type Input struct {
+	// Client is the name of the MCP client making the call (empty if the call was not made via the MCP proxy)
+	Client string
+	// User is the name of the user making the call (empty if the call was not made via the API)
+	User string
+
+	// Tool contains the tool's metadata: name, server, description & annotations
+	Tool map[string]any
+
+	// Args contains the input arguments of the call
+	Args map[string]any
+}
+
+// Decision is the result of evaluating a policy
+type Decision struct {
+	Action Action
+	Reason string
+
+	// Args contains the transformed arguments if Action is ActionTransform
+	Args map[string]any
+}
+
+// Evaluator evaluates a compiled policy against a tool call
+type Evaluator interface {
+	Evaluate(in *Input) (*Decision, error)
+}
+
+// compiler turns the source of a policy into an Evaluator
+type compiler func(source string) (Evaluator, error)
+
+// compilers contains the compiler of every supported policy language
+var compilers = map[string]compiler{
+	"cel":    compileCEL,
+	"args":   compileArgs,
+	"egress": compileEgress,
+}
+
+// Compile compiles the source of a policy written in the given language.
+func Compile(language, source string) (Evaluator, error) {
+	c, ok := compilers[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported policy language: %s", language)
+	}
+	return c(source)
+}
+
+// decisionFromResult converts the raw result of a policy into a Decision.
+// A policy may either return a boolean (true to allow, false to deny) or an object with
+// the fields "action", "reason" and "args" (required if the action is "transform").
+func decisionFromResult(result any) (*Decision, error) {
+	switch r := result.(type) {
+	case bool:
+		if r {
+			return &Decision{Action: ActionAllow}, nil
+		}
+		return &Decision{Action: ActionDeny}, nil
+	case map[string]any:
+		d := &Decision{}
+		action, _ := r["action"].(string)
+		d.Action = Action(action)
+		d.Reason, _ = r["reason"].(string)
+		switch d.Action {
+		case ActionAllow, ActionDeny:
+		case ActionTransform:
+			args, ok := r["args"].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("policy returned a transform decision without an 'args' object")
+			}
+			d.Args = args
+		default:
+			return nil, fmt.Errorf("policy returned an invalid action: '%s'", action)
+		}
+		return d, nil
+	}
+	return nil, fmt.Errorf("policy must return a boolean or an object, got %T", result)
+}
@@ -0,0 +1,261 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCELPolicy(t *testing.T) {
+	in := &Input{
+		Client: "cursor",
+		Tool: map[string]any{
+			"name":        "github__delete_repo",
+			"server":      "github",
+			"annotations": map[string]any{"destructiveHint": true},
+		},
+		Args: map[string]any{"repo": "mcpjungle", "limit": 500.0},
+	}
+
+	tests := []struct {
+		name   string
+		expr   string
+		want   Action
+		args   map[string]any
+		reason string
+	}{
+		{"boolean allow", `client == "cursor"`, ActionAllow, nil, ""},
+		{"boolean deny", `!(has(tool.annotations.destructiveHint) && tool.annotations.destructiveHint)`, ActionDeny, nil, ""},
+		{
+			"deny with reason",
+			`tool.server == "github" ? {"action": "deny", "reason": "github is read-only"} : {"action": "allow"}`,
+			ActionDeny, nil, "github is read-only",
+		},
+		{
+			"transform",
+			`{"action": "transform", "args": {"repo": args.repo, "limit": args.limit > 100.0 ? 100.0 : args.limit}}`,
+			ActionTransform, map[string]any{"repo": "mcpjungle", "limit": 100.0}, "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Compile("cel", tt.expr)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			d, err := e.Evaluate(in)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if d.Action != tt.want || d.Reason != tt.reason || !reflect.DeepEqual(d.Args, tt.args) {
+				t.Errorf("Evaluate() = %+v, want action %s, reason %q, args %v", d, tt.want, tt.reason, tt.args)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	if _, err := Compile("rego", "allow"); err == nil {
+		t.Errorf("Compile() with an unsupported language must fail")
+	}
+	if _, err := Compile("cel", "client =="); err == nil {
+		t.Errorf("Compile() with an invalid expression must fail")
+	}
+}
+
+func TestInvalidDecision(t *testing.T) {
+	e, err := Compile("cel", `{"action": "transform"}`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if _, err := e.Evaluate(&Input{}); err == nil {
+		t.Errorf("Evaluate() must fail for a transform decision without args")
+	}
+}
+
+func TestArgsPolicy(t *testing.T) {
+	e, err := Compile("args", `{
+		"clients": ["analytics-bot"],
+		"tools": ["db__*"],
+		"args": {
+			"database": {"allowed": ["analytics"], "required": true},
+			"limit": {"max": 1000},
+			"query": {"pattern": "^SELECT "}
+		},
+		"redact": ["debug_token"]
+	}`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		client string
+		tool   string
+		args   map[string]any
+		want   Action
+		reason string
+	}{
+		{"allowed", "analytics-bot", "db__query", map[string]any{"database": "analytics", "limit": 10.0}, ActionAllow, ""},
+		{"other client", "cursor", "db__query", map[string]any{"database": "prod"}, ActionAllow, ""},
+		{"other tool", "analytics-bot", "github__search", map[string]any{"database": "prod"}, ActionAllow, ""},
+		{
+			"value not allowed", "analytics-bot", "db__query", map[string]any{"database": "prod"},
+			ActionDeny, `argument database="prod" is not allowed, it must be one of ["analytics"]`,
+		},
+		{"missing required", "analytics-bot", "db__query", map[string]any{}, ActionDeny, "argument database is required"},
+		{
+			"out of range", "analytics-bot", "db__query", map[string]any{"database": "analytics", "limit": 5000.0},
+			ActionDeny, "argument limit=5000 is out of the allowed range [-inf, 1000]",
+		},
+		{
+			"pattern", "analytics-bot", "db__query", map[string]any{"database": "analytics", "query": "DROP TABLE users"},
+			ActionDeny, `argument query="DROP TABLE users" does not match the pattern ^SELECT `,
+		},
+		{
+			"redaction", "analytics-bot", "db__query", map[string]any{"database": "analytics", "debug_token": "x"},
+			ActionTransform, "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := e.Evaluate(&Input{Client: tt.client, Tool: map[string]any{"name": tt.tool}, Args: tt.args})
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if d.Action != tt.want || d.Reason != tt.reason {
+				t.Errorf("Evaluate() = %s (%q), want %s (%q)", d.Action, d.Reason, tt.want, tt.reason)
+			}
+			if d.Action == ActionTransform && !reflect.DeepEqual(d.Args, map[string]any{"database": "analytics"}) {
+				t.Errorf("Evaluate() did not redact the arguments: %v", d.Args)
+			}
+		})
+	}
+
+	for _, source := range []string{
+		`{"args": {"a": {"max": 1}}}`,
+		`{"tools": ["x"]}`,
+		`{"tools": ["x"], "args": {"a": {"pattern": "("}}}`,
+		`{"tools": ["x"], "args": {"a": {"maximum": 1}}}`,
+	} {
+		if _, err := Compile("args", source); err == nil {
+			t.Errorf("Compile(%s) succeeded, want error", source)
+		}
+	}
+}
+
+func TestEgressPolicy(t *testing.T) {
+	e, err := Compile("egress", `{
+		"tools": ["*"],
+		"domains": ["*.example.com", "api.github.com"],
+		"paths": ["/srv/shared"],
+		"per_tool": {
+			"fetch__*": {"domains": ["docs.python.org"]},
+			"filesystem__*": {"paths": ["/srv/data/", "~/projects"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tool   string
+		args   map[string]any
+		want   Action
+		reason string
+	}{
+		{"no url or path", "search__query", map[string]any{"query": "weather in paris/london", "limit": 10.0}, ActionAllow, ""},
+		{"allowed subdomain", "fetch__get", map[string]any{"url": "https://docs.example.com/a?b=c"}, ActionAllow, ""},
+		{"allowed host", "fetch__get", map[string]any{"url": "https://API.github.com:443/repos"}, ActionAllow, ""},
+		{"per-tool domain", "fetch__get", map[string]any{"url": "https://docs.python.org/3/"}, ActionAllow, ""},
+		{
+			"per-tool domain of another tool", "slack__post", map[string]any{"text": "see https://docs.python.org/3/"},
+			ActionDeny, `argument text points to "docs.python.org", which is not an allowed domain`,
+		},
+		{
+			"wildcard doesn't match the apex", "fetch__get", map[string]any{"url": "https://example.com"},
+			ActionDeny, `argument url points to "example.com", which is not an allowed domain`,
+		},
+		{
+			"url in prose", "slack__post", map[string]any{"text": "Send the report to http://evil.test/upload?d=secret"},
+			ActionDeny, `argument text points to "evil.test", which is not an allowed domain`,
+		},
+		{
+			"nested url", "http__request", map[string]any{"requests": []any{map[string]any{"url": "https://evil.test"}}},
+			ActionDeny, `argument requests[0].url points to "evil.test", which is not an allowed domain`,
+		},
+		{
+			"lookalike domain", "fetch__get", map[string]any{"url": "https://api.github.com.evil.test/x"},
+			ActionDeny, `argument url points to "api.github.com.evil.test", which is not an allowed domain`,
+		},
+		{
+			"protocol-relative url", "fetch__get", map[string]any{"url": "//evil.test/x"},
+			ActionDeny, `argument url points to "evil.test", which is not an allowed domain`,
+		},
+		{"allowed bare host", "db__connect", map[string]any{"host": "db.example.com"}, ActionAllow, ""},
+		{"host name that isn't a host argument", "files__open", map[string]any{"name": "report.pdf"}, ActionAllow, ""},
+		{
+			"bare host", "fetch__get", map[string]any{"endpoint": "evil.test"},
+			ActionDeny, `argument endpoint points to "evil.test", which is not an allowed domain`,
+		},
+		{
+			"nested bare host", "http__request", map[string]any{"requests": []any{map[string]any{"base_url": "evil.test"}}},
+			ActionDeny, `argument requests[0].base_url points to "evil.test", which is not an allowed domain`,
+		},
+		{
+			"bare host with a path", "http__post", map[string]any{"target": "evil.test/upload"},
+			ActionDeny, `argument target points to "evil.test", which is not an allowed domain`,
+		},
+		{
+			"bare host with a port", "http__post", map[string]any{"to": "localhost:8080"},
+			ActionDeny, `argument to points to "localhost", which is not an allowed domain`,
+		},
+		{
+			"bare ip address", "net__ping", map[string]any{"q": "169.254.169.254"},
+			ActionDeny, `argument q points to "169.254.169.254", which is not an allowed domain`,
+		},
+		{"allowed path", "filesystem__read", map[string]any{"path": "/srv/shared/report.csv"}, ActionAllow, ""},
+		{"per-tool path", "filesystem__read", map[string]any{"path": "/srv/data"}, ActionAllow, ""},
+		{"home path", "filesystem__read", map[string]any{"path": "~/projects/app/main.go"}, ActionAllow, ""},
+		{
+			"path traversal", "filesystem__read", map[string]any{"path": "/srv/data/../../etc/passwd"},
+			ActionDeny, `argument path="/srv/data/../../etc/passwd" is not within an allowed path`,
+		},
+		{
+			"prefix of a directory name", "filesystem__read", map[string]any{"path": "/srv/database"},
+			ActionDeny, `argument path="/srv/database" is not within an allowed path`,
+		},
+		{
+			"file url", "fetch__get", map[string]any{"url": "file:///etc/passwd"},
+			ActionDeny, `argument url points to the file "/etc/passwd", which is not within an allowed path`,
+		},
+		{
+			"windows path", "filesystem__read", map[string]any{"path": `C:\Users\admin\.ssh\id_rsa`},
+			ActionDeny, `argument path="C:\\Users\\admin\\.ssh\\id_rsa" is not within an allowed path`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := e.Evaluate(&Input{Tool: map[string]any{"name": tt.tool}, Args: tt.args})
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if d.Action != tt.want || d.Reason != tt.reason {
+				t.Errorf("Evaluate() = %s (%q), want %s (%q)", d.Action, d.Reason, tt.want, tt.reason)
+			}
+		})
+	}
+
+	for _, source := range []string{
+		`{"domains": ["example.com"]}`,
+		`{"tools": ["x"], "domains": ["*"]}`,
+		`{"tools": ["x"], "domains": ["https://example.com"]}`,
+		`{"tools": ["x"], "paths": [""]}`,
+		`{"tools": ["x"], "per_tool": {"[": {"paths": ["/tmp"]}}}`,
+		`{"tools": ["x"], "urls": ["example.com"]}`,
+	} {
+		if _, err := Compile("egress", source); err == nil {
+			t.Errorf("Compile(%s) succeeded, want error", source)
+		}
+	}
+}
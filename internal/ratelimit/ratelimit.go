@@ -0,0 +1,134 @@
+// Package ratelimit limits the rate of tool calls of every caller.
+// The call counters are kept in memory for a single instance of mcpjungle, or in Redis to share the limits
+// between all the replicas of a deployment.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// Limit allows a caller at most Calls calls per Window.
+// Windows are aligned on multiples of their duration since the Unix epoch, eg- a daily window starts at 00:00 UTC.
+type Limit struct {
+	// Name identifies the limit in the counters' keys, the metrics & the errors
+	Name   string
+	Calls  int64
+	Window time.Duration
+}
+
+// ExceededError is returned for a call that exceeds a limit.
+type ExceededError struct {
+	Limit Limit
+	// RetryAfter is the time until the current window of the limit ends
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s of %d calls exceeded, try again in %s", e.Limit.Name, e.Limit.Calls, e.RetryAfter.Round(time.Second))
+}
+
+// Store keeps the call counters of the limits.
+type Store interface {
+	// Incr increments the counter of key, creating it with the given time to live if needed, and returns its new value.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// Limiter enforces limits on the calls of every caller.
+// If its store fails (eg- Redis is unreachable), the counters are kept in memory until the store recovers,
+// so that the limits are still enforced, per instance.
+type Limiter struct {
+	limits   []Limit
+	store    Store
+	fallback *MemoryStore
+	failing  atomic.Bool
+	now      func() time.Time
+}
+
+// NewLimiter creates a Limiter that enforces the given limits with the counters kept in store.
+// If store is nil, the counters are kept in memory.
+func NewLimiter(store Store, limits ...Limit) *Limiter {
+	fallback := NewMemoryStore()
+	if store == nil {
+		store = fallback
+	}
+	return &Limiter{limits: limits, store: store, fallback: fallback, now: time.Now}
+}
+
+// Allow counts a call of the caller against every limit.
+// It returns an ExceededError for the first limit that the call exceeds, the call isn't counted by the next limits.
+func (l *Limiter) Allow(ctx context.Context, caller string) error {
+	now := l.now()
+	for _, limit := range l.limits {
+		window := now.UnixNano() / int64(limit.Window)
+		end := time.Unix(0, (window+1)*int64(limit.Window))
+		key := fmt.Sprintf("mcpjungle:ratelimit:%s:%s:%d", limit.Name, caller, window)
+		if l.incr(ctx, key, end.Sub(now)) > limit.Calls {
+			return &ExceededError{Limit: limit, RetryAfter: end.Sub(now)}
+		}
+	}
+	return nil
+}
+
+func (l *Limiter) incr(ctx context.Context, key string, ttl time.Duration) int64 {
+	n, err := l.store.Incr(ctx, key, ttl)
+	if err == nil {
+		if l.failing.Swap(false) {
+			log.Printf("[INFO] rate limit store recovered, limits are shared again")
+		}
+		return n
+	}
+	metrics.RateLimitStoreErrors.Inc()
+	if !l.failing.Swap(true) {
+		log.Printf("[WARN] rate limit store failed, enforcing the limits per instance until it recovers: %v", err)
+	}
+	n, _ = l.fallback.Incr(ctx, key, ttl)
+	return n
+}
+
+// memorySweepInterval is how often the expired counters are removed from a MemoryStore
+const memorySweepInterval = time.Minute
+
+type counter struct {
+	n       int64
+	expires time.Time
+}
+
+// MemoryStore keeps the counters in memory, for a single instance of mcpjungle.
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: make(map[string]*counter), lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// sweep the counters of the past windows, so that the counters of idle callers don't accumulate
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		for k, c := range s.counters {
+			if !now.Before(c.expires) {
+				delete(s.counters, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expires) {
+		c = &counter{expires: now.Add(ttl)}
+		s.counters[key] = c
+	}
+	c.n++
+	return c.n, nil
+}
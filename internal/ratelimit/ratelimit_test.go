@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingStore struct{}
+
+func (failingStore) Incr(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("store unreachable")
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)
+	l := NewLimiter(nil,
+		Limit{Name: "rate limit", Calls: 2, Window: time.Minute},
+		Limit{Name: "daily quota", Calls: 3, Window: 24 * time.Hour},
+	)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if err := l.Allow(ctx, "alice"); err != nil {
+			t.Fatalf("expected call %d to be allowed, got %v", i+1, err)
+		}
+	}
+	var exceeded *ExceededError
+	if err := l.Allow(ctx, "alice"); !errors.As(err, &exceeded) || exceeded.Limit.Name != "rate limit" {
+		t.Fatalf("expected third call to exceed the rate limit, got %v", err)
+	}
+	if exceeded.RetryAfter != 30*time.Second {
+		t.Fatalf("expected to retry at the end of the minute, got %s", exceeded.RetryAfter)
+	}
+	if err := l.Allow(ctx, "bob"); err != nil {
+		t.Fatalf("expected other caller to be allowed, got %v", err)
+	}
+
+	// the next minute, the rate limit allows calls again until the daily quota is exhausted
+	// (the call rejected by the rate limit wasn't counted by the quota)
+	now = now.Add(time.Minute)
+	if err := l.Allow(ctx, "alice"); err != nil {
+		t.Fatalf("expected call in the next window to be allowed, got %v", err)
+	}
+	if err := l.Allow(ctx, "alice"); !errors.As(err, &exceeded) || exceeded.Limit.Name != "daily quota" {
+		t.Fatalf("expected call to exceed the daily quota, got %v", err)
+	}
+	if want := 14*time.Hour - 90*time.Second; exceeded.RetryAfter != want {
+		t.Fatalf("expected to retry at midnight UTC in %s, got %s", want, exceeded.RetryAfter)
+	}
+}
+
+func TestLimiterFallsBackToMemory(t *testing.T) {
+	l := NewLimiter(failingStore{}, Limit{Name: "rate limit", Calls: 1, Window: time.Minute})
+	if err := l.Allow(context.Background(), "alice"); err != nil {
+		t.Fatalf("expected first call to be allowed, got %v", err)
+	}
+	if err := l.Allow(context.Background(), "alice"); err == nil {
+		t.Fatal("expected limit to be enforced in memory while the store fails")
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	if n, _ := s.Incr(ctx, "k", time.Millisecond); n != 1 {
+		t.Fatalf("expected 1, got %d", n)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if n, _ := s.Incr(ctx, "k", time.Minute); n != 1 {
+		t.Fatalf("expected expired counter to restart at 1, got %d", n)
+	}
+	if n, _ := s.Incr(ctx, "k", time.Minute); n != 2 {
+		t.Fatalf("expected 2, got %d", n)
+	}
+}
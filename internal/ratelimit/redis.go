@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds every command sent to Redis (including connecting), so that tool calls aren't held up
+// by an unresponsive Redis
+const redisTimeout = time.Second
+
+// redisIncrScript increments a counter and sets its expiry when it is created, atomically
+var redisIncrScript = redis.NewScript(`local n = redis.call('INCR', KEYS[1])
+if n == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return n`)
+
+// RedisStore keeps the counters in Redis, so that the limits are shared by all the replicas of mcpjungle using it.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a RedisStore from a URL of the form redis[s]://[[username]:password@]host[:port][/db].
+// It doesn't connect to Redis until the first command.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL, must be of the form redis[s]://[[username]:password@]host[:port][/db]: %w", err)
+	}
+	opts.DialTimeout = redisTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+	// limits are enforced per instance while Redis fails, retrying would only delay the tool calls
+	opts.MaxRetries = -1
+	return &RedisStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	// a zero expiry would delete the counter right away
+	ms := max(ttl.Milliseconds(), 1)
+	n, err := redisIncrScript.Run(ctx, s.client, []string{key}, ms).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis: failed to increment counter: %w", err)
+	}
+	return n, nil
+}
+
+// Ping checks that Redis is reachable.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close closes the connections to Redis.
+func (s *RedisStore) Close() {
+	_ = s.client.Close()
+}
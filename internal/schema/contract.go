@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// ContractCase is an invocation of a tool generated from its input schema to test how the tool
+// handles valid and invalid input.
+type ContractCase struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
+
+	// Valid is true if the arguments conform to the input schema, ie, the tool must accept them
+	Valid bool `json:"valid"`
+}
+
+// ContractCases generates the test invocations of a tool from its input schema:
+// the required properties only, all properties, the boundary values of every constrained property
+// and invalid cases (values out of bounds, of the wrong type or missing required properties).
+func ContractCases(inputSchema map[string]any) []ContractCase {
+	properties, _ := inputSchema["properties"].(map[string]any)
+	required := requiredNames(inputSchema)
+
+	requiredOnly := make(map[string]any, len(required))
+	for _, name := range required {
+		ps, _ := properties[name].(map[string]any)
+		requiredOnly[name] = ExampleValue(ps)
+	}
+	all := ExampleObject(properties)
+
+	cases := []ContractCase{
+		{Name: "required properties only", Args: requiredOnly, Valid: true},
+	}
+	if len(all) > len(requiredOnly) {
+		cases = append(cases, ContractCase{Name: "all properties", Args: all, Valid: true})
+	}
+	with := func(name string, value any) map[string]any {
+		args := maps.Clone(all)
+		args[name] = value
+		return args
+	}
+
+	for _, name := range SortedPropertyNames(properties, required) {
+		ps, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		t := SchemaType(ps)
+		switch t {
+		case "integer", "number":
+			step := 1.0
+			if t == "number" {
+				step = 0.5
+			}
+			if n, ok := number(ps["minimum"]); ok {
+				cases = append(cases,
+					ContractCase{Name: fmt.Sprintf("%s at its minimum", name), Args: with(name, n), Valid: true},
+					ContractCase{Name: fmt.Sprintf("%s below its minimum", name), Args: with(name, n-step)},
+				)
+			}
+			if n, ok := number(ps["maximum"]); ok {
+				cases = append(cases,
+					ContractCase{Name: fmt.Sprintf("%s at its maximum", name), Args: with(name, n), Valid: true},
+					ContractCase{Name: fmt.Sprintf("%s above its maximum", name), Args: with(name, n+step)},
+				)
+			}
+		case "string":
+			// strings of a given length would not match a format or pattern, so their bounds are not tested
+			if ps["format"] != nil || ps["pattern"] != nil || ps["enum"] != nil {
+				break
+			}
+			if n, ok := number(ps["minLength"]); ok && n > 0 {
+				cases = append(cases,
+					ContractCase{Name: fmt.Sprintf("%s at its minimum length", name), Args: with(name, strings.Repeat("a", int(n))), Valid: true},
+					ContractCase{Name: fmt.Sprintf("%s below its minimum length", name), Args: with(name, strings.Repeat("a", int(n)-1))},
+				)
+			}
+			if n, ok := number(ps["maxLength"]); ok {
+				cases = append(cases,
+					ContractCase{Name: fmt.Sprintf("%s at its maximum length", name), Args: with(name, strings.Repeat("a", int(n))), Valid: true},
+					ContractCase{Name: fmt.Sprintf("%s above its maximum length", name), Args: with(name, strings.Repeat("a", int(n)+1))},
+				)
+			}
+		}
+		if enum, ok := ps["enum"].([]any); ok && len(enum) > 0 && t == "string" {
+			cases = append(cases, ContractCase{Name: fmt.Sprintf("%s not in its enum", name), Args: with(name, "not-in-enum")})
+		}
+		if wrong, ok := wrongTypeValue(t); ok {
+			cases = append(cases, ContractCase{Name: fmt.Sprintf("%s of the wrong type", name), Args: with(name, wrong)})
+		}
+	}
+
+	for _, name := range required {
+		args := maps.Clone(all)
+		delete(args, name)
+		cases = append(cases, ContractCase{Name: fmt.Sprintf("missing required %s", name), Args: args})
+	}
+	return cases
+}
+
+// wrongTypeValue returns a value that doesn't conform to the given type
+func wrongTypeValue(t string) (any, bool) {
+	switch t {
+	case "string":
+		return 12345, true
+	case "integer", "number", "boolean", "array", "object":
+		return "not-a-" + t, true
+	}
+	return nil, false
+}
@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestContractCases(t *testing.T) {
+	var inputSchema map[string]any
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "minLength": 2},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 50},
+			"sort": {"type": "string", "enum": ["relevance", "date"]}
+		},
+		"required": ["query"],
+		"additionalProperties": false
+	}`), &inputSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := ContractCases(inputSchema)
+	names := map[string]bool{}
+	for _, c := range cases {
+		names[c.Name] = true
+		// the generated cases must be classified the same way the validator classifies them
+		b, _ := json.Marshal(c.Args)
+		var args any
+		_ = json.Unmarshal(b, &args)
+		if violations := Validate(inputSchema, args); (len(violations) == 0) != c.Valid {
+			t.Errorf("case %q (valid = %v) has violations %v", c.Name, c.Valid, violations)
+		}
+	}
+	for _, want := range []string{
+		"required properties only",
+		"all properties",
+		"limit at its maximum",
+		"limit below its minimum",
+		"query below its minimum length",
+		"sort not in its enum",
+		"limit of the wrong type",
+		"missing required query",
+	} {
+		if !names[want] {
+			t.Errorf("ContractCases() has no case %q", want)
+		}
+	}
+
+	violations := Validate(inputSchema, map[string]any{"query": "mcp", "extra": true})
+	if len(violations) != 1 || violations[0] != "extra: property is not allowed" {
+		t.Errorf("Validate() = %v, want the additional property to be reported", violations)
+	}
+}
@@ -0,0 +1,149 @@
+// Package schema provides helpers for working with the JSON schemas that describe the inputs of MCP tools.
+package schema
+
+import "sort"
+
+// maxExampleDepth limits how deep nested objects & arrays are expanded while generating examples.
+// This protects against recursive schemas.
+const maxExampleDepth = 5
+
+// ExampleObject generates an example JSON object for a tool's input schema, given its properties.
+// All properties are included in the example so that the user can see the full shape of the input,
+// not just the required fields.
+func ExampleObject(properties map[string]any) map[string]any {
+	return exampleObject(properties, 0)
+}
+
+// ExampleValue generates a sample value that conforms to the given JSON schema.
+// It prefers values supplied by the schema author (examples, default, const, enum)
+// and otherwise falls back to a placeholder value for the schema's type.
+func ExampleValue(schema map[string]any) any {
+	return exampleValue(schema, 0)
+}
+
+func exampleObject(properties map[string]any, depth int) map[string]any {
+	obj := make(map[string]any, len(properties))
+	for name, p := range properties {
+		ps, ok := p.(map[string]any)
+		if !ok {
+			obj[name] = nil
+			continue
+		}
+		obj[name] = exampleValue(ps, depth+1)
+	}
+	return obj
+}
+
+func exampleValue(schema map[string]any, depth int) any {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if v, ok := schema["default"]; ok {
+		return v
+	}
+	if v, ok := schema["const"]; ok {
+		return v
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if variants, ok := schema[key].([]any); ok && len(variants) > 0 {
+			if v, ok := variants[0].(map[string]any); ok {
+				return exampleValue(v, depth)
+			}
+		}
+	}
+
+	switch SchemaType(schema) {
+	case "string":
+		return exampleString(schema)
+	case "integer":
+		if m, ok := schema["minimum"].(float64); ok {
+			return int64(m)
+		}
+		return 0
+	case "number":
+		if m, ok := schema["minimum"].(float64); ok {
+			return m
+		}
+		return 0.0
+	case "boolean":
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return []any{}
+		}
+		return []any{exampleValue(items, depth+1)}
+	case "object":
+		props, _ := schema["properties"].(map[string]any)
+		return exampleObject(props, depth)
+	case "null":
+		return nil
+	}
+	return nil
+}
+
+// exampleString returns a sample string, taking the schema's format into account.
+func exampleString(schema map[string]any) string {
+	switch schema["format"] {
+	case "date-time":
+		return "2025-01-01T00:00:00Z"
+	case "date":
+		return "2025-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	}
+	return "string"
+}
+
+// SchemaType returns the type declared by a JSON schema.
+// If the schema declares multiple types, the first non-null type is returned.
+// An empty string is returned if the type cannot be determined.
+func SchemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// SortedPropertyNames returns the names of the given schema properties in a stable order,
+// with required properties listed first.
+func SortedPropertyNames(properties map[string]any, required []string) []string {
+	isRequired := make(map[string]bool, len(required))
+	for _, r := range required {
+		isRequired[r] = true
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if isRequired[names[i]] != isRequired[names[j]] {
+			return isRequired[names[i]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExampleValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]any
+		want   any
+	}{
+		{"examples take precedence", map[string]any{"type": "string", "examples": []any{"hello"}, "default": "x"}, "hello"},
+		{"default", map[string]any{"type": "integer", "default": float64(42)}, float64(42)},
+		{"enum", map[string]any{"type": "string", "enum": []any{"asc", "desc"}}, "asc"},
+		{"const", map[string]any{"const": "fixed"}, "fixed"},
+		{"plain string", map[string]any{"type": "string"}, "string"},
+		{"email string", map[string]any{"type": "string", "format": "email"}, "user@example.com"},
+		{"integer with minimum", map[string]any{"type": "integer", "minimum": float64(5)}, int64(5)},
+		{"integer", map[string]any{"type": "integer"}, 0},
+		{"number", map[string]any{"type": "number"}, 0.0},
+		{"boolean", map[string]any{"type": "boolean"}, true},
+		{"nullable type list", map[string]any{"type": []any{"null", "boolean"}}, true},
+		{"array", map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, []any{"string"}},
+		{"array without items", map[string]any{"type": "array"}, []any{}},
+		{"anyOf picks the first variant", map[string]any{"anyOf": []any{map[string]any{"type": "boolean"}}}, true},
+		{
+			"nested object",
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"a": map[string]any{"type": "integer"},
+					"b": map[string]any{"type": "string"},
+				},
+			},
+			map[string]any{"a": 0, "b": "string"},
+		},
+		{"unknown", map[string]any{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExampleValue(tt.schema)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExampleValue(%v) = %#v, want %#v", tt.schema, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExampleValueRecursiveSchema(t *testing.T) {
+	// a self-referencing schema must not cause infinite recursion
+	node := map[string]any{"type": "object"}
+	node["properties"] = map[string]any{"child": node}
+
+	if got := ExampleValue(node); got == nil {
+		t.Fatalf("ExampleValue() returned nil for a recursive schema")
+	}
+}
+
+func TestSortedPropertyNames(t *testing.T) {
+	props := map[string]any{"c": nil, "a": nil, "b": nil, "d": nil}
+	got := SortedPropertyNames(props, []string{"d", "b"})
+	want := []string{"b", "d", "a", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedPropertyNames() = %v, want %v", got, want)
+	}
+}
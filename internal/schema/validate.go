@@ -0,0 +1,257 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// Validate checks a value against a JSON schema and returns the violations found.
+// Every violation is prefixed with the path of the offending value, eg- "items[0].name: expected string".
+// Only the commonly used keywords are supported: type, enum, const, required, properties,
+// additionalProperties, items, anyOf, oneOf, allOf and the numeric, string & array bounds.
+func Validate(schema map[string]any, v any) []string {
+	var violations []string
+	validate("", schema, v, 0, &violations)
+	return violations
+}
+
+func validate(path string, schema map[string]any, v any, depth int, violations *[]string) {
+	if schema == nil || depth > maxExampleDepth*4 {
+		return
+	}
+	report := func(format string, args ...any) {
+		p := path
+		if p == "" {
+			p = "(root)"
+		}
+		*violations = append(*violations, p+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return hasType(v, t) }) {
+		report("expected %s, got %s", joinTypes(types), jsonTypeOf(v))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, v) }) {
+		report("value %v is not one of %v", v, enum)
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, v) {
+		report("value %v is not %v", v, c)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		variants, ok := schema[key].([]any)
+		if !ok || len(variants) == 0 {
+			continue
+		}
+		matched := slices.ContainsFunc(variants, func(variant any) bool {
+			vs, _ := variant.(map[string]any)
+			var vv []string
+			validate(path, vs, v, depth+1, &vv)
+			return len(vv) == 0
+		})
+		if !matched {
+			report("value does not match any of the schemas in %s", key)
+		}
+	}
+	if variants, ok := schema["allOf"].([]any); ok {
+		for _, variant := range variants {
+			vs, _ := variant.(map[string]any)
+			validate(path, vs, v, depth+1, violations)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		validateObject(path, schema, val, depth, violations)
+	case []any:
+		if n, ok := number(schema["minItems"]); ok && float64(len(val)) < n {
+			report("expected at least %v items, got %d", n, len(val))
+		}
+		if n, ok := number(schema["maxItems"]); ok && float64(len(val)) > n {
+			report("expected at most %v items, got %d", n, len(val))
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				validate(fmt.Sprintf("%s[%d]", path, i), items, item, depth+1, violations)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(val)
+		if n, ok := number(schema["minLength"]); ok && float64(length) < n {
+			report("expected at least %v characters, got %d", n, length)
+		}
+		if n, ok := number(schema["maxLength"]); ok && float64(length) > n {
+			report("expected at most %v characters, got %d", n, length)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(val) {
+				report("value %q does not match pattern %s", val, pattern)
+			}
+		}
+	default:
+		f, ok := number(v)
+		if !ok {
+			return
+		}
+		if n, ok := number(schema["minimum"]); ok && f < n {
+			report("value %v is less than the minimum %v", f, n)
+		}
+		if n, ok := number(schema["maximum"]); ok && f > n {
+			report("value %v is greater than the maximum %v", f, n)
+		}
+		if n, ok := number(schema["exclusiveMinimum"]); ok && f <= n {
+			report("value %v is not greater than %v", f, n)
+		}
+		if n, ok := number(schema["exclusiveMaximum"]); ok && f >= n {
+			report("value %v is not less than %v", f, n)
+		}
+	}
+}
+
+func validateObject(path string, schema map[string]any, obj map[string]any, depth int, violations *[]string) {
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	for _, name := range requiredNames(schema) {
+		if _, ok := obj[name]; !ok {
+			*violations = append(*violations, join(name)+": required property is missing")
+		}
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	for _, name := range SortedPropertyNames(obj, nil) {
+		if ps, ok := properties[name].(map[string]any); ok {
+			validate(join(name), ps, obj[name], depth+1, violations)
+			continue
+		}
+		if _, declared := properties[name]; declared {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*violations = append(*violations, join(name)+": property is not allowed")
+			}
+		case map[string]any:
+			validate(join(name), additional, obj[name], depth+1, violations)
+		}
+	}
+}
+
+// requiredNames returns the names of the required properties of an object schema
+func requiredNames(schema map[string]any) []string {
+	switch r := schema["required"].(type) {
+	case []string:
+		return r
+	case []any:
+		names := make([]string, 0, len(r))
+		for _, v := range r {
+			if s, ok := v.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// schemaTypes returns the types declared by a JSON schema, nil if it declares none
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func hasType(v any, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := number(v)
+		return ok
+	case "integer":
+		f, ok := number(v)
+		return ok && f == float64(int64(f))
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+func jsonTypeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	if _, ok := number(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return fmt.Sprintf("one of %v", types)
+}
+
+// number returns the value of a JSON number, which may have been decoded or built as any Go numeric type
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func jsonEqual(a, b any) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,169 @@
+// Package script evaluates the small CEL (https://cel.dev) expressions that admins attach to tools to
+// transform their calls without writing a WASM plugin.
+//
+// An argument script is evaluated before a tool is called, with the variables client, user & args.
+// A result script is evaluated after a successful call, with the same variables plus result
+// (the MCP result of the call, eg- result.content & result.structuredContent).
+// Both must return an object, whose fields are merged into the arguments or the result:
+// fields set to null are removed and all other fields are added or replaced.
+//
+//	{"limit": has(args.limit) ? args.limit : 10, "query": args.query.trim()}
+//	{"content": result.content.filter(c, c.type == "text")}
+//
+// Scripts are sandboxed: they can't access anything but their variables and their evaluation is
+// aborted once it exceeds CostLimit or Timeout.
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Kind is the stage of a tool call at which a script is evaluated
+type Kind string
+
+const (
+	Args   Kind = "args"
+	Result Kind = "result"
+)
+
+const (
+	// CostLimit bounds the CPU spent evaluating a script, in CEL cost units (roughly, operations)
+	CostLimit = 100_000
+
+	// Timeout is the maximum wall time a script can run for
+	Timeout = 100 * time.Millisecond
+)
+
+// Vars are the variables available to a script
+type Vars struct {
+	Client string
+	User   string
+	Args   map[string]any
+	Result map[string]any
+}
+
+// Script is a compiled script, safe for concurrent use
+type Script struct {
+	kind    Kind
+	program cel.Program
+}
+
+var argsEnv, argsEnvErr = cel.NewEnv(
+	ext.Strings(),
+	ext.Math(),
+	ext.Lists(),
+	cel.Variable("client", cel.StringType),
+	cel.Variable("user", cel.StringType),
+	cel.Variable("args", cel.MapType(cel.StringType, cel.DynType)),
+)
+
+var resultEnv, resultEnvErr = cel.NewEnv(
+	ext.Strings(),
+	ext.Math(),
+	ext.Lists(),
+	cel.Variable("client", cel.StringType),
+	cel.Variable("user", cel.StringType),
+	cel.Variable("args", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("result", cel.MapType(cel.StringType, cel.DynType)),
+)
+
+// jsonValueType is used to convert the output of a script into plain (JSON-compatible) Go values
+var jsonValueType = reflect.TypeOf(&structpb.Value{})
+
+// Compile parses and type-checks the source of a script of the given kind.
+func Compile(kind Kind, source string) (*Script, error) {
+	var env *cel.Env
+	switch kind {
+	case Args:
+		env = argsEnv
+		if argsEnvErr != nil {
+			return nil, fmt.Errorf("failed to create CEL environment: %w", argsEnvErr)
+		}
+	case Result:
+		env = resultEnv
+		if resultEnvErr != nil {
+			return nil, fmt.Errorf("failed to create CEL environment: %w", resultEnvErr)
+		}
+	default:
+		return nil, fmt.Errorf("unknown script kind %q", kind)
+	}
+
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid %s script: %w", kind, issues.Err())
+	}
+	// the output type is only known to be dyn if the script returns a variable's field, it is checked once evaluated
+	if t := ast.OutputType(); t.Kind() != types.MapKind && t.Kind() != types.DynKind {
+		return nil, fmt.Errorf("%s script must return an object, not %s", kind, t)
+	}
+	program, err := env.Program(ast, cel.CostLimit(CostLimit), cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s script: %w", kind, err)
+	}
+	return &Script{kind: kind, program: program}, nil
+}
+
+// Eval evaluates the script and returns the fields to merge, see Merge.
+func (s *Script) Eval(ctx context.Context, vars Vars) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	in := map[string]any{
+		"client": vars.Client,
+		"user":   vars.User,
+		"args":   orEmpty(vars.Args),
+	}
+	if s.kind == Result {
+		in["result"] = orEmpty(vars.Result)
+	}
+	out, _, err := s.program.ContextEval(ctx, in)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s script timed out after %s", s.kind, Timeout)
+		}
+		return nil, fmt.Errorf("failed to evaluate %s script: %w", s.kind, err)
+	}
+
+	v, err := out.ConvertToNative(jsonValueType)
+	if err != nil {
+		return nil, fmt.Errorf("%s script must return an object, got %s", s.kind, out.Type().TypeName())
+	}
+	fields, ok := v.(*structpb.Value).AsInterface().(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s script must return an object, got %s", s.kind, out.Type().TypeName())
+	}
+	return fields, nil
+}
+
+// Merge returns a copy of base with the fields returned by a script merged into it.
+// Fields set to nil are removed.
+func Merge(base, fields map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(fields))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+func orEmpty(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
@@ -0,0 +1,57 @@
+package script
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScripts(t *testing.T) {
+	defaults, err := Compile(Args, `{"limit": has(args.limit) ? args.limit : 10, "q": args.q.lowerAscii(), "debug": null}`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	args := map[string]any{"q": "MCP Jungle", "debug": true}
+	fields, err := defaults.Eval(context.Background(), Vars{Args: args})
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	want := map[string]any{"q": "mcp jungle", "limit": float64(10)}
+	if got := Merge(args, fields); !reflect.DeepEqual(got, want) {
+		t.Errorf("merged args = %v, want %v", got, want)
+	}
+
+	filter, err := Compile(Result, `{"content": result.content.filter(c, c.type == "text" && user == "alice")}`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	result := map[string]any{"content": []any{
+		map[string]any{"type": "text", "text": "hello"},
+		map[string]any{"type": "image", "data": "..."},
+	}}
+	fields, err = filter.Eval(context.Background(), Vars{User: "alice", Result: result})
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if content := fields["content"].([]any); len(content) != 1 {
+		t.Errorf("filtered content = %v, want only the text item", content)
+	}
+
+	if _, err := Compile(Args, `args.q.size() > 3`); err == nil {
+		t.Error("Compile() accepted a script that doesn't return an object")
+	}
+	if _, err := Compile(Args, `{"r": result}`); err == nil {
+		t.Error("Compile() accepted an argument script that uses the result")
+	}
+
+	// scripts that exceed the cost limit are aborted
+	expensive, err := Compile(Args, `{"n": [1,2,3,4,5,6,7,8,9,10].map(a, [1,2,3,4,5,6,7,8,9,10].map(b,
+		[1,2,3,4,5,6,7,8,9,10].map(c, [1,2,3,4,5,6,7,8,9,10].map(d, [1,2,3,4,5,6,7,8,9,10].map(e, a*b*c*d*e)))))}`)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if _, err := expensive.Eval(context.Background(), Vars{}); err == nil || !strings.Contains(err.Error(), "cost") {
+		t.Errorf("Eval() error = %v, want the cost limit to be exceeded", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package audit exports an audit trail of the activity in mcpjungle (tool calls, admin changes, impersonation)
+// to external sinks like files, syslog or SIEM systems.
+package audit
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// EventType is the kind of activity recorded by an audit event
+type EventType string
+
+const (
+	// EventToolCall is recorded for every tool call, via the MCP proxy or the API
+	EventToolCall EventType = "tool_call"
+
+	// EventAdminRequest is recorded for every admin API request that changes the registry
+	EventAdminRequest EventType = "admin_request"
+
+	// EventImpersonation is recorded when an admin makes a request on behalf of an MCP client or user
+	EventImpersonation EventType = "impersonation"
+
+	// EventServerProposal is recorded when a user proposes the registration of an MCP server
+	EventServerProposal EventType = "server_proposal"
+
+	// EventPolicyViolation is recorded when a policy denies a tool call
+	EventPolicyViolation EventType = "policy_violation"
+)
+
+const (
+	// DefaultBufferSize is the default number of events buffered per sink while they're waiting to be delivered
+	DefaultBufferSize = 1024
+
+	// maxBatchSize is the maximum number of events delivered to a sink at once
+	maxBatchSize = 100
+
+	// maxDeliveryAttempts is the number of times delivery of a batch is attempted before it is dropped
+	maxDeliveryAttempts = 3
+)
+
+// Event is an entry of the audit trail
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+
+	// Client and User identify the caller (production mode only)
+	Client string `json:"client,omitempty"`
+	User   string `json:"user,omitempty"`
+
+	// ImpersonatedBy is the username of the admin who acted on behalf of the client or user, if any
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+
+	// Tool, IsError, DurationMs & Cost describe a tool call
+	Tool       string  `json:"tool,omitempty"`
+	IsError    bool    `json:"is_error,omitempty"`
+	DurationMs int64   `json:"duration_ms,omitempty"`
+	Cost       float64 `json:"cost,omitempty"`
+
+	// Reason explains why a policy denied a tool call
+	Reason string `json:"reason,omitempty"`
+
+	// Method, Path & Status describe an API request
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+// AuditService delivers audit events to the configured sinks.
+// Every sink has its own buffer and delivery goroutine, so a slow or failing sink doesn't hold up the others.
+// Recording an event never blocks: when a sink's buffer is full, the event is dropped for that sink
+// and counted in the mcpjungle_audit_events_dropped_total metric.
+// All methods are safe to call on a nil *AuditService, which records nothing.
+type AuditService struct {
+	workers []*sinkWorker
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type sinkWorker struct {
+	sink   Sink
+	events chan Event
+}
+
+// NewAuditService starts delivering audit events to the given sinks.
+// bufferSize is the number of events buffered per sink, 0 means DefaultBufferSize.
+func NewAuditService(sinks []Sink, bufferSize int) *AuditService {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	a := &AuditService{}
+	for _, s := range sinks {
+		w := &sinkWorker{sink: s, events: make(chan Event, bufferSize)}
+		a.workers = append(a.workers, w)
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			w.run()
+		}()
+	}
+	return a
+}
+
+// Record queues an event for delivery to all sinks.
+// The event's time is set to the current time if it is zero.
+func (a *AuditService) Record(e Event) {
+	if a == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return
+	}
+	for _, w := range a.workers {
+		select {
+		case w.events <- e:
+		default:
+			metrics.AuditEventsDropped.WithLabelValues(w.sink.Name(), "buffer_full").Inc()
+		}
+	}
+}
+
+// Close delivers the buffered events and closes all sinks.
+// Events recorded after Close are discarded.
+func (a *AuditService) Close() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	for _, w := range a.workers {
+		close(w.events)
+	}
+	a.mu.Unlock()
+
+	a.wg.Wait()
+	for _, w := range a.workers {
+		if err := w.sink.Close(); err != nil {
+			log.Printf("[ERROR] failed to close audit sink %s: %v", w.sink.Name(), err)
+		}
+	}
+}
+
+// PurgeResult is the outcome of purging the events of a sink.
+type PurgeResult struct {
+	Sink string
+	// Purgeable is false for sinks that deliver events to systems outside of mcpjungle (syslog, HTTP),
+	// where they have to be purged separately
+	Purgeable bool
+	Deleted   int64
+	Remaining int64
+	Err       error
+}
+
+// Purge deletes the events for which match returns true from all sinks that store them locally.
+// Events that are still buffered for delivery when Purge is called are not affected.
+func (a *AuditService) Purge(match func(Event) bool) []PurgeResult {
+	if a == nil {
+		return nil
+	}
+	results := make([]PurgeResult, 0, len(a.workers))
+	for _, w := range a.workers {
+		r := PurgeResult{Sink: w.sink.Name()}
+		if p, ok := w.sink.(Purger); ok {
+			r.Purgeable = true
+			r.Deleted, r.Remaining, r.Err = p.Purge(match)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// run delivers events to the sink in batches until the events channel is closed.
+func (w *sinkWorker) run() {
+	batch := make([]Event, 0, maxBatchSize)
+	for e := range w.events {
+		batch = append(batch[:0], e)
+		// deliver whatever else is already buffered along with this event
+	drain:
+		for len(batch) < maxBatchSize {
+			select {
+			case e, ok := <-w.events:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, e)
+			default:
+				break drain
+			}
+		}
+		w.deliver(batch)
+	}
+}
+
+// deliver writes a batch of events to the sink, retrying with a backoff if it fails.
+func (w *sinkWorker) deliver(batch []Event) {
+	var err error
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		if err = w.sink.Write(batch); err == nil {
+			return
+		}
+		if attempt < maxDeliveryAttempts {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+	log.Printf("[ERROR] failed to deliver %d audit events to sink %s: %v", len(batch), w.sink.Name(), err)
+	metrics.AuditEventsDropped.WithLabelValues(w.sink.Name(), "delivery_failed").Add(float64(len(batch)))
+}
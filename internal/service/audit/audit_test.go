@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sinks, err := ParseSinks("file://"+path+"?max_size_mb=1&max_backups=1", "")
+	if err != nil {
+		t.Fatalf("ParseSinks() error = %v", err)
+	}
+	a := NewAuditService(sinks, 10000)
+
+	// ~1.5MB of events, so that the file is rotated once
+	tool := strings.Repeat("x", 1000)
+	for i := 0; i < 1500; i++ {
+		a.Record(Event{Type: EventToolCall, Tool: tool})
+	}
+	a.Close()
+
+	countLines := func(p string) int {
+		f, err := os.Open(p)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", p, err)
+		}
+		defer f.Close()
+		n := 0
+		for s := bufio.NewScanner(f); s.Scan(); n++ {
+			var e Event
+			if err := json.Unmarshal(s.Bytes(), &e); err != nil || e.Tool != tool || e.Time.IsZero() {
+				t.Fatalf("invalid audit log line in %s: %s", p, s.Text())
+			}
+		}
+		return n
+	}
+	if n := countLines(path) + countLines(path+".1"); n != 1500 {
+		t.Errorf("audit log contains %d events, want 1500", n)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("more backups than max_backups were kept")
+	}
+}
+
+func TestFileSinkPurge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sinks, err := ParseSinks("file://"+path+"?max_backups=1", "")
+	if err != nil {
+		t.Fatalf("ParseSinks() error = %v", err)
+	}
+	s := sinks[0].(*fileSink)
+	defer s.Close()
+	if err := os.WriteFile(path+".1", []byte(`{"type":"tool_call","user":"alice"}`+"\nnot an event\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write([]Event{{Type: EventToolCall, User: "alice"}, {Type: EventToolCall, User: "bob"}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	deleted, remaining, err := s.Purge(func(e Event) bool { return e.User == "alice" })
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if deleted != 2 || remaining != 0 {
+		t.Errorf("Purge() = %d deleted, %d remaining, want 2, 0", deleted, remaining)
+	}
+	if err := s.Write([]Event{{Type: EventToolCall, User: "carol"}}); err != nil {
+		t.Fatalf("Write() after Purge() error = %v", err)
+	}
+	current, _ := os.ReadFile(path)
+	if strings.Contains(string(current), "alice") || !strings.Contains(string(current), "bob") ||
+		!strings.Contains(string(current), "carol") {
+		t.Errorf("unexpected audit log after purge: %s", current)
+	}
+	if backup, _ := os.ReadFile(path + ".1"); string(backup) != "not an event\n" {
+		t.Errorf("unexpected rotated audit log after purge: %q", backup)
+	}
+}
+
+func TestHTTPSinkSplunkFormat(t *testing.T) {
+	var auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Query().Has("format") {
+			t.Errorf("format parameter was sent to the endpoint: %s", r.URL)
+		}
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	sinks, err := ParseSinks(srv.URL+"/services/collector?format=splunk", "Splunk token")
+	if err != nil {
+		t.Fatalf("ParseSinks() error = %v", err)
+	}
+	err = sinks[0].Write([]Event{
+		{Type: EventAdminRequest, User: "alice", Method: http.MethodPost, Path: "/api/v0/servers", Status: 201},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if auth != "Splunk token" {
+		t.Errorf("Authorization = %q, want %q", auth, "Splunk token")
+	}
+	var got struct {
+		Event Event `json:"event"`
+	}
+	if err := json.Unmarshal([]byte(body), &got); err != nil || got.Event.User != "alice" {
+		t.Errorf("body = %s, want a Splunk HEC event", body)
+	}
+
+	if _, err := ParseSinks("ftp://example.com", ""); err == nil {
+		t.Error("ParseSinks() with an unsupported scheme succeeded, want error")
+	}
+}
+
+// slowSink blocks every write until it is released
+type slowSink struct {
+	release chan struct{}
+	events  []Event
+	closed  bool
+}
+
+func (s *slowSink) Name() string { return "slow" }
+
+func (s *slowSink) Write(events []Event) error {
+	<-s.release
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *slowSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestCloseDeliversQueuedEvents(t *testing.T) {
+	sink := &slowSink{release: make(chan struct{})}
+	a := NewAuditService([]Sink{sink}, 0)
+	for i := 0; i < 250; i++ {
+		a.Record(Event{Type: EventToolCall, Tool: "github__search"})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		a.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Close() returned while events were still queued")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(sink.release)
+	<-done
+
+	if len(sink.events) != 250 {
+		t.Errorf("sink received %d events, want 250", len(sink.events))
+	}
+	if !sink.closed {
+		t.Error("sink was not closed")
+	}
+	a.Record(Event{Type: EventToolCall})
+	if len(sink.events) != 250 {
+		t.Error("event recorded after Close() was delivered")
+	}
+}
@@ -0,0 +1,373 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultFileMaxSizeMB is the size at which an audit log file is rotated, unless configured otherwise
+	defaultFileMaxSizeMB = 100
+
+	// defaultFileMaxBackups is the number of rotated audit log files kept, unless configured otherwise
+	defaultFileMaxBackups = 5
+
+	// httpSinkTimeout is the maximum time allowed for an HTTP sink to accept a batch of events
+	httpSinkTimeout = 10 * time.Second
+)
+
+// Sink is a destination of audit events
+type Sink interface {
+	// Name identifies the sink in logs and metrics
+	Name() string
+
+	// Write delivers a batch of events. It is never called concurrently.
+	Write(events []Event) error
+
+	Close() error
+}
+
+// Purger is implemented by the sinks that store events where mcpjungle can delete them.
+type Purger interface {
+	// Purge deletes the stored events for which match returns true. It returns the number of events deleted
+	// and the number of matching events still found afterwards, which verifies the deletion.
+	Purge(match func(Event) bool) (deleted, remaining int64, err error)
+}
+
+// ParseSinks creates the sinks described by a comma-separated list of URLs:
+//   - file:///var/log/mcpjungle/audit.log?max_size_mb=100&max_backups=5 writes JSON lines to a file,
+//     rotating it when it reaches max_size_mb
+//   - syslog: sends events to the local syslog daemon, syslog://host:514?network=tcp to a remote one
+//     (network defaults to udp). The tag parameter sets the syslog tag (default "mcpjungle").
+//   - http(s)://... POSTs batches of events to an endpoint. The format parameter selects the payload:
+//     ndjson (default, JSON lines), splunk (Splunk HTTP Event Collector) or elastic (Elasticsearch bulk API).
+//     The parameter is removed from the URL before sending. authorization, if not empty,
+//     is sent as the Authorization header.
+func ParseSinks(spec, authorization string) ([]Sink, error) {
+	var sinks []Sink
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit sink %s: %w", raw, err)
+		}
+		var s Sink
+		switch u.Scheme {
+		case "file":
+			s, err = newFileSink(u)
+		case "syslog":
+			s, err = newSyslogSink(u)
+		case "http", "https":
+			s, err = newHTTPSink(u, authorization)
+		default:
+			err = fmt.Errorf("unsupported scheme '%s', must be one of file, syslog, http or https", u.Scheme)
+		}
+		if err != nil {
+			for _, s := range sinks {
+				_ = s.Close()
+			}
+			return nil, fmt.Errorf("invalid audit sink %s: %w", raw, err)
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+// Exports returns true if any of the sinks delivers events to an external collector over HTTP.
+func Exports(sinks []Sink) bool {
+	for _, s := range sinks {
+		if _, ok := s.(*httpSink); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fileSink writes events as JSON lines to a file and rotates it when it grows too large.
+// Rotated files are renamed to <path>.1, <path>.2, etc, <path>.1 being the most recent.
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	// mu serializes writes with purges, which rewrite the files
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func newFileSink(u *url.URL) (*fileSink, error) {
+	path := u.Path
+	if path == "" {
+		// relative paths are parsed as opaque, eg- file:audit.log
+		path = u.Opaque
+	}
+	if path == "" {
+		return nil, fmt.Errorf("missing file path")
+	}
+	s := &fileSink{
+		path:       filepath.Clean(path),
+		maxSize:    defaultFileMaxSizeMB << 20,
+		maxBackups: defaultFileMaxBackups,
+	}
+	q := u.Query()
+	if v := q.Get("max_size_mb"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("max_size_mb must be a positive number")
+		}
+		s.maxSize = int64(n) << 20
+	}
+	if v := q.Get("max_backups"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("max_backups must be a number >= 0")
+		}
+		s.maxBackups = n
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) Name() string {
+	return "file:" + s.path
+}
+
+func (s *fileSink) Write(events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		// a previous rotation failed to reopen the file
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+		if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+			if err := s.rotate(); err != nil {
+				return fmt.Errorf("failed to rotate audit log: %w", err)
+			}
+		}
+		n, err := s.f.Write(line)
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	return s.f.Close()
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f, s.size = f, info.Size()
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	s.f = nil
+	if s.maxBackups == 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+	// shift the backups, the oldest one is overwritten
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+// Purge rewrites the current and rotated files without the matching events.
+// Lines that aren't valid events are kept as they are.
+func (s *fileSink) Purge(match func(Event) bool) (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := []string{s.path}
+	for i := 1; i <= s.maxBackups; i++ {
+		paths = append(paths, fmt.Sprintf("%s.%d", s.path, i))
+	}
+
+	// the current file is replaced, so it's reopened afterwards
+	if s.f != nil {
+		if err := s.f.Close(); err != nil {
+			return 0, 0, err
+		}
+		s.f = nil
+	}
+	var deleted, remaining int64
+	for _, p := range paths {
+		n, err := purgeFile(p, match, true)
+		deleted += n
+		if err != nil {
+			return deleted, 0, fmt.Errorf("failed to purge %s: %w", p, err)
+		}
+	}
+	if err := s.open(); err != nil {
+		return deleted, 0, err
+	}
+	for _, p := range paths {
+		n, err := purgeFile(p, match, false)
+		if err != nil {
+			return deleted, 0, fmt.Errorf("failed to verify %s: %w", p, err)
+		}
+		remaining += n
+	}
+	return deleted, remaining, nil
+}
+
+// purgeFile counts the matching events in a file of JSON lines and, if rewrite is true,
+// replaces the file with a copy that doesn't contain them. A missing file contains no events.
+func purgeFile(path string, match func(Event) bool, rewrite bool) (int64, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var kept bytes.Buffer
+	var matched int64
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		var e Event
+		if json.Unmarshal(line, &e) == nil && match(e) {
+			matched++
+			continue
+		}
+		kept.Write(line)
+	}
+	if !rewrite || matched == 0 {
+		return matched, nil
+	}
+	tmp := path + ".purge"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0o600); err != nil {
+		return 0, err
+	}
+	return matched, os.Rename(tmp, path)
+}
+
+// httpSink POSTs batches of events to an HTTP endpoint, eg- of a SIEM system
+type httpSink struct {
+	url           string
+	format        string
+	authorization string
+	client        *http.Client
+}
+
+func newHTTPSink(u *url.URL, authorization string) (*httpSink, error) {
+	q := u.Query()
+	format := q.Get("format")
+	switch format {
+	case "":
+		format = "ndjson"
+	case "ndjson", "splunk", "elastic":
+	default:
+		return nil, fmt.Errorf("unsupported format '%s', must be one of ndjson, splunk or elastic", format)
+	}
+	q.Del("format")
+	u.RawQuery = q.Encode()
+	return &httpSink{
+		url:           u.String(),
+		format:        format,
+		authorization: authorization,
+		client:        &http.Client{Timeout: httpSinkTimeout},
+	}, nil
+}
+
+func (s *httpSink) Name() string {
+	return "http:" + s.url
+}
+
+func (s *httpSink) Write(events []Event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, e := range events {
+		var err error
+		switch s.format {
+		case "splunk":
+			err = enc.Encode(map[string]any{
+				"time":       float64(e.Time.UnixMilli()) / 1000,
+				"sourcetype": "mcpjungle:audit",
+				"event":      e,
+			})
+		case "elastic":
+			if err = enc.Encode(map[string]any{"create": map[string]any{}}); err == nil {
+				err = enc.Encode(e)
+			}
+		default:
+			err = enc.Encode(e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("endpoint responded with status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+	"net/url"
+)
+
+// syslogSink sends every event as a JSON message to a syslog daemon
+type syslogSink struct {
+	name string
+	w    *syslog.Writer
+}
+
+func newSyslogSink(u *url.URL) (*syslogSink, error) {
+	q := u.Query()
+	tag := q.Get("tag")
+	if tag == "" {
+		tag = "mcpjungle"
+	}
+	network := ""
+	if u.Host != "" {
+		network = q.Get("network")
+		if network == "" {
+			network = "udp"
+		}
+	}
+	w, err := syslog.Dial(network, u.Host, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, err
+	}
+	name := "syslog"
+	if u.Host != "" {
+		name += ":" + u.Host
+	}
+	return &syslogSink{name: name, w: w}, nil
+}
+
+func (s *syslogSink) Name() string {
+	return s.name
+}
+
+func (s *syslogSink) Write(events []Event) error {
+	for _, e := range events {
+		msg, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if err := s.w.Info(string(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
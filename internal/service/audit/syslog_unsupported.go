@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package audit
+
+import (
+	"errors"
+	"net/url"
+)
+
+func newSyslogSink(u *url.URL) (Sink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,195 @@
+// Package authorizer delegates the authorization of tool calls to an external HTTP endpoint.
+package authorizer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const (
+	// DefaultTimeout is the maximum time the authorizer has to respond if no other timeout is configured
+	DefaultTimeout = 2 * time.Second
+
+	// maxCachedDecisions limits the number of decisions kept in the cache
+	maxCachedDecisions = 10000
+)
+
+var (
+	// ErrDenied is returned (wrapped) when the authorizer denies a tool call
+	ErrDenied = errors.New("denied by external authorizer")
+
+	// ErrUnavailable is returned (wrapped) when the authorizer cannot be reached and calls fail closed
+	ErrUnavailable = errors.New("external authorizer unavailable")
+)
+
+// requestSigner signs the payloads of outbound webhook requests
+type requestSigner interface {
+	SignRequest(req *http.Request, body []byte) error
+}
+
+// Options configures an Authorizer.
+type Options struct {
+	// URL is the endpoint that authorization requests are POSTed to
+	URL string
+
+	// Authorization is sent as the Authorization header, if set
+	Authorization string
+
+	// Timeout limits the time the endpoint has to respond, DefaultTimeout is used if it is 0
+	Timeout time.Duration
+
+	// CacheTTL is how long decisions are cached for identical requests, 0 disables caching
+	CacheTTL time.Duration
+
+	// FailOpen allows calls if the endpoint cannot be reached or responds with an error.
+	// By default, such calls are rejected.
+	FailOpen bool
+}
+
+// Authorizer asks an external HTTP endpoint whether tool calls are allowed.
+// A nil *Authorizer allows all calls.
+type Authorizer struct {
+	opts       Options
+	httpClient *http.Client
+	signer     requestSigner
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]decision
+}
+
+type decision struct {
+	resp    types.AuthorizationResponse
+	expires time.Time
+}
+
+// NewAuthorizer creates an Authorizer.
+// signer is used to sign the requests with the webhook keys, it may be nil in which case requests are sent unsigned.
+func NewAuthorizer(opts Options, signer requestSigner) (*Authorizer, error) {
+	if opts.URL == "" {
+		return nil, errors.New("authorizer URL is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return &Authorizer{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		signer:     signer,
+		cache:      make(map[[sha256.Size]byte]decision),
+	}, nil
+}
+
+// Authorize returns nil if the tool call described by the request is allowed.
+// It returns an error wrapping ErrDenied if the authorizer denies it, or ErrUnavailable if the authorizer
+// cannot be reached and calls fail closed.
+func (a *Authorizer) Authorize(ctx context.Context, req *types.AuthorizationRequest) error {
+	if a == nil {
+		return nil
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to serialize authorization request: %w", err)
+	}
+	key := sha256.Sum256(body)
+
+	resp, cached := a.cached(key)
+	if !cached {
+		resp, err = a.ask(ctx, body)
+		if err != nil {
+			metrics.AuthorizerDecisions.WithLabelValues("error").Inc()
+			if a.opts.FailOpen {
+				log.Printf("[WARN] allowing call to tool %s, external authorizer failed: %v", req.Tool, err)
+				return nil
+			}
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		a.store(key, resp)
+	}
+
+	if !resp.Allow {
+		metrics.AuthorizerDecisions.WithLabelValues("deny").Inc()
+		if resp.Reason != "" {
+			return fmt.Errorf("%w: %s", ErrDenied, resp.Reason)
+		}
+		return ErrDenied
+	}
+	metrics.AuthorizerDecisions.WithLabelValues("allow").Inc()
+	return nil
+}
+
+// ask POSTs an authorization request to the endpoint and returns its decision.
+func (a *Authorizer) ask(ctx context.Context, body []byte) (*types.AuthorizationResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.opts.Authorization != "" {
+		req.Header.Set("Authorization", a.opts.Authorization)
+	}
+	if a.signer != nil {
+		if err := a.signer.SignRequest(req, body); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("authorizer responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	var decision types.AuthorizationResponse
+	if err := json.Unmarshal(respBody, &decision); err != nil {
+		return nil, fmt.Errorf("invalid response from authorizer: %w", err)
+	}
+	return &decision, nil
+}
+
+func (a *Authorizer) cached(key [sha256.Size]byte) (*types.AuthorizationResponse, bool) {
+	if a.opts.CacheTTL <= 0 {
+		return nil, false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d, ok := a.cache[key]
+	if !ok || time.Now().After(d.expires) {
+		return nil, false
+	}
+	return &d.resp, true
+}
+
+func (a *Authorizer) store(key [sha256.Size]byte, resp *types.AuthorizationResponse) {
+	if a.opts.CacheTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.cache) >= maxCachedDecisions {
+		for k, d := range a.cache {
+			if now.After(d.expires) {
+				delete(a.cache, k)
+			}
+		}
+		if len(a.cache) >= maxCachedDecisions {
+			// every decision is still fresh, start over rather than growing without bound
+			a.cache = make(map[[sha256.Size]byte]decision)
+		}
+	}
+	a.cache[key] = decision{resp: *resp, expires: now.Add(a.opts.CacheTTL)}
+}
@@ -0,0 +1,63 @@
+package authorizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestAuthorize(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req types.AuthorizationRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := types.AuthorizationResponse{Allow: req.User == "alice"}
+		if !resp.Allow {
+			resp.Reason = "only alice may deploy"
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	a, err := NewAuthorizer(Options{URL: srv.URL, Authorization: "Bearer s3cret", CacheTTL: time.Minute}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &types.AuthorizationRequest{Tool: "ci__deploy", Server: "ci", User: "alice", Args: map[string]any{"env": "prod"}}
+	for range 2 {
+		if err := a.Authorize(ctx, alice); err != nil {
+			t.Fatalf("Authorize() error = %v", err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("authorizer received %d requests, want 1 thanks to the cache", n)
+	}
+	bob := &types.AuthorizationRequest{Tool: "ci__deploy", Server: "ci", User: "bob"}
+	if err := a.Authorize(ctx, bob); !errors.Is(err, ErrDenied) {
+		t.Errorf("Authorize() error = %v, want the call to be denied", err)
+	}
+
+	// the authorizer is unreachable
+	srv.Close()
+	closed, _ := NewAuthorizer(Options{URL: srv.URL}, nil)
+	if err := closed.Authorize(ctx, bob); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Authorize() error = %v, want the call to fail closed", err)
+	}
+	open, _ := NewAuthorizer(Options{URL: srv.URL, FailOpen: true}, nil)
+	if err := open.Authorize(ctx, bob); err != nil {
+		t.Errorf("Authorize() error = %v, want the call to fail open", err)
+	}
+}
@@ -0,0 +1,253 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const putTimeout = 30 * time.Second
+
+// Destination is where the catalog snapshot is published.
+type Destination interface {
+	// Put stores data under the destination's location followed by suffix, replacing any previous content
+	Put(ctx context.Context, suffix string, data []byte, contentType string) error
+
+	// String describes the destination for humans
+	String() string
+}
+
+// ObjectStoreCredentials are the HMAC credentials used to sign the uploads to S3 and to GCS's
+// S3-compatible XML API (with an HMAC key of a service account).
+type ObjectStoreCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Region is the region of the S3 bucket, us-east-1 by default. It is ignored for GCS.
+	Region string
+
+	// Endpoint overrides the endpoint of the object store, eg- to publish to MinIO.
+	// Objects are then addressed with path-style URLs (<endpoint>/<bucket>/<key>).
+	Endpoint string
+}
+
+// NewDestination parses the URL of a destination:
+// a local path (or file:// URL), s3://<bucket>/<key> or gs://<bucket>/<object>.
+func NewDestination(rawURL string, creds ObjectStoreCredentials) (Destination, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog destination '%s': %w", rawURL, err)
+	}
+	switch u.Scheme {
+	case "":
+		return &fileDestination{path: rawURL}, nil
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid catalog destination '%s': the path is missing", rawURL)
+		}
+		return &fileDestination{path: u.Path}, nil
+	case "s3", "gs":
+		key := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || key == "" {
+			return nil, fmt.Errorf("invalid catalog destination '%s', must be %s://<bucket>/<key>", rawURL, u.Scheme)
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("credentials are required to publish the catalog to %s", rawURL)
+		}
+		d := &objectStoreDestination{
+			scheme:     u.Scheme,
+			bucket:     u.Host,
+			key:        key,
+			creds:      creds,
+			httpClient: &http.Client{Timeout: putTimeout},
+		}
+		if d.creds.Region == "" {
+			d.creds.Region = "us-east-1"
+		}
+		if u.Scheme == "gs" {
+			// GCS accepts SigV4 signatures made with HMAC keys for any region, "auto" is the conventional one
+			d.creds.Region = "auto"
+		}
+		if d.creds.Endpoint != "" {
+			e, err := url.Parse(d.creds.Endpoint)
+			if err != nil || (e.Scheme != "http" && e.Scheme != "https") || e.Host == "" {
+				return nil, fmt.Errorf("invalid object store endpoint '%s', must be an http(s) URL", d.creds.Endpoint)
+			}
+		}
+		return d, nil
+	default:
+		return nil, fmt.Errorf("unsupported catalog destination '%s', must be a path, an s3:// or a gs:// URL", rawURL)
+	}
+}
+
+// fileDestination writes the snapshot to a local file
+type fileDestination struct {
+	path string
+}
+
+func (d *fileDestination) String() string {
+	return d.path
+}
+
+// Put writes the file atomically, so that readers never see a partially written snapshot.
+func (d *fileDestination) Put(_ context.Context, suffix string, data []byte, _ string) error {
+	path := d.path + suffix
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory of %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// objectStoreDestination uploads the snapshot to an S3 bucket, or a GCS bucket through its S3-compatible API,
+// with requests signed with AWS Signature Version 4.
+type objectStoreDestination struct {
+	scheme     string
+	bucket     string
+	key        string
+	creds      ObjectStoreCredentials
+	httpClient *http.Client
+}
+
+func (d *objectStoreDestination) String() string {
+	return d.scheme + "://" + d.bucket + "/" + d.key
+}
+
+// objectURL returns the URL of the object with the given key
+func (d *objectStoreDestination) objectURL(key string) string {
+	path := "/" + uriEncode(key, false)
+	switch {
+	case d.creds.Endpoint != "":
+		return strings.TrimSuffix(d.creds.Endpoint, "/") + "/" + uriEncode(d.bucket, true) + path
+	case d.scheme == "gs":
+		return "https://storage.googleapis.com/" + uriEncode(d.bucket, true) + path
+	default:
+		return "https://" + d.bucket + ".s3." + d.creds.Region + ".amazonaws.com" + path
+	}
+}
+
+func (d *objectStoreDestination) Put(ctx context.Context, suffix string, data []byte, contentType string) error {
+	objectURL := d.objectURL(d.key + suffix)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	signV4(req, data, d.creds, time.Now())
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", objectURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to upload %s: status %s: %s", objectURL, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// signV4 adds the AWS Signature Version 4 headers of a request to the S3 API to req.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func signV4(req *http.Request, payload []byte, creds ObjectStoreCredentials, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, payloadHash, amzDate}
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+		headers = append(headers, "x-amz-security-token")
+		values = append(values, creds.SessionToken)
+	}
+
+	var canonicalHeaders strings.Builder
+	for i, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + values[i] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + creds.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, creds.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// uriEncode encodes s as required by SigV4: every byte but the unreserved characters is percent-encoded,
+// and so are slashes if encodeSlash is true.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
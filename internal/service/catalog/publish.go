@@ -0,0 +1,124 @@
+// Package catalog publishes a signed snapshot of the tool catalog of mcpjungle outside of the gateway
+// (a local file, an S3 or a GCS bucket), so that documentation portals and generators of agent configurations
+// can consume the catalog without access to the live API.
+//
+// The snapshot is published as JSON along with its signature, a JWS with a detached payload stored next to it
+// with the SignatureSuffix. The signature is verified with the public keys served at /.well-known/jwks.json.
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+)
+
+// DefaultInterval is how often the catalog snapshot is published by default
+const DefaultInterval = 15 * time.Minute
+
+// SignatureSuffix is appended to the location of the snapshot to get the location of its signature
+const SignatureSuffix = ".jws"
+
+// Publisher periodically publishes the catalog snapshot to a destination.
+type Publisher struct {
+	mcpService *mcp.MCPService
+	dest       Destination
+
+	// registry is the public URL of the gateway, recorded in the snapshots
+	registry string
+
+	mu sync.Mutex
+	// last is the content of the last published snapshot, without its timestamp.
+	// The snapshot is only published again once the catalog changes.
+	last []byte
+}
+
+// NewPublisher creates a publisher of the catalog of mcpService to dest.
+// Snapshots are signed with the result signing key of mcpService, which must be enabled.
+func NewPublisher(mcpService *mcp.MCPService, dest Destination, registry string) (*Publisher, error) {
+	if len(mcpService.ResultSigningKeys().Keys) == 0 {
+		return nil, fmt.Errorf("catalog snapshots can't be signed: %w", mcp.ErrSigningDisabled)
+	}
+	return &Publisher{mcpService: mcpService, dest: dest, registry: registry}, nil
+}
+
+// Destination returns where the snapshot is published.
+func (p *Publisher) Destination() Destination {
+	return p.dest
+}
+
+// Publish publishes the current catalog snapshot and its signature.
+// Nothing is published if the catalog didn't change since the last publication, unless force is true.
+// It returns true if the snapshot was published.
+func (p *Publisher) Publish(ctx context.Context, force bool) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot, err := p.mcpService.PublicCatalogSnapshot()
+	if err != nil {
+		return false, err
+	}
+	snapshot.Registry = p.registry
+
+	takenAt := snapshot.TakenAt
+	snapshot.TakenAt = time.Time{}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize catalog snapshot: %w", err)
+	}
+	if !force && bytes.Equal(content, p.last) {
+		return false, nil
+	}
+
+	snapshot.TakenAt = takenAt
+	payload, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize catalog snapshot: %w", err)
+	}
+	sig, err := p.mcpService.SignCatalogSnapshot(payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to sign catalog snapshot: %w", err)
+	}
+
+	// the snapshot is uploaded first, consumers that fetch it in between see a signature that doesn't match
+	// and retry rather than trusting a stale signature
+	if err := p.dest.Put(ctx, "", payload, "application/json"); err != nil {
+		return false, err
+	}
+	if err := p.dest.Put(ctx, SignatureSuffix, []byte(sig), "application/jose"); err != nil {
+		return false, err
+	}
+	p.last = content
+	return true, nil
+}
+
+// Run publishes the catalog snapshot every interval until ctx is cancelled (blocking call).
+// Failures are logged and retried at the next interval.
+func (p *Publisher) Run(ctx context.Context, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		published, err := p.Publish(ctx, false)
+		switch {
+		case err != nil:
+			metrics.CatalogPublishes.WithLabelValues("failed").Inc()
+			log.Printf("[WARN] failed to publish the catalog snapshot to %s: %v", p.dest, err)
+		case published:
+			metrics.CatalogPublishes.WithLabelValues("published").Inc()
+			log.Printf("[INFO] published the catalog snapshot to %s", p.dest)
+		default:
+			metrics.CatalogPublishes.WithLabelValues("unchanged").Inc()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package catalog
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+)
+
+func newTestMCPService(t *testing.T) *mcp.MCPService {
+	t.Helper()
+	dbConn, err := db.NewInMemoryDBConnection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrations.Migrate(dbConn); err != nil {
+		t.Fatal(err)
+	}
+	notificationService := notification.NewNotificationService(dbConn)
+	featureService, err := feature.NewFeatureService(dbConn, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := mcp.NewMCPService(
+		dbConn,
+		server.NewMCPServer("proxy", "0.0.1", server.WithToolCapabilities(true)),
+		&server.Hooks{},
+		usage.NewUsageService(dbConn, webhook.NewWebhookService(dbConn), notificationService),
+		policy.NewPolicyService(dbConn),
+		featureService,
+		notificationService,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestPublish(t *testing.T) {
+	m := newTestMCPService(t)
+
+	dest, err := NewDestination(filepath.Join(t.TempDir(), "catalog", "tools.json"), ObjectStoreCredentials{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPublisher(m, dest, ""); !errors.Is(err, mcp.ErrSigningDisabled) {
+		t.Fatalf("NewPublisher() without a signing key error = %v, want ErrSigningDisabled", err)
+	}
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	if err := m.EnableResultSigning(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})); err != nil {
+		t.Fatal(err)
+	}
+
+	upstreamServer := server.NewMCPServer("calc", "0.0.1", server.WithToolCapabilities(true))
+	for _, name := range []string{"add", "sub"} {
+		upstreamServer.AddTool(mcpgo.NewTool(name), func(context.Context, mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+			return mcpgo.NewToolResultText("ok"), nil
+		})
+	}
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+	s, err := model.NewStreamableHTTPServer("calc", "", upstream.URL+"/mcp", "secret-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPublisher(m, dest, "https://mcpjungle.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func() ([]byte, []byte) {
+		payload, err := os.ReadFile(dest.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := os.ReadFile(dest.String() + SignatureSuffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return payload, sig
+	}
+
+	if published, err := p.Publish(context.Background(), false); err != nil || !published {
+		t.Fatalf("Publish() = %v, %v, want published", published, err)
+	}
+	keys := m.ResultSigningKeys()
+	payload, sig := read()
+	snapshot, err := client.VerifyCatalogSnapshot(payload, sig, &keys)
+	if err != nil {
+		t.Fatalf("VerifyCatalogSnapshot() error = %v", err)
+	}
+	if snapshot.Registry != "https://mcpjungle.example.com" || len(snapshot.Servers) != 1 || len(snapshot.Tools) != 2 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if snapshot.Servers[0].URL != "" || strings.Contains(string(payload), "secret-token") {
+		t.Errorf("the snapshot exposes the configuration of the server: %s", payload)
+	}
+
+	tampered := []byte(strings.Replace(string(payload), "calc__add", "calc__rm", 1))
+	if _, err := client.VerifyCatalogSnapshot(tampered, sig, &keys); !errors.Is(err, client.ErrInvalidCatalogSignature) {
+		t.Errorf("VerifyCatalogSnapshot() of a modified snapshot error = %v, want ErrInvalidCatalogSignature", err)
+	}
+
+	// nothing is published until the catalog changes
+	if published, err := p.Publish(context.Background(), false); err != nil || published {
+		t.Fatalf("Publish() of an unchanged catalog = %v, %v, want not published", published, err)
+	}
+	if _, err := m.DisableTools("calc__sub", 0); err != nil {
+		t.Fatal(err)
+	}
+	if published, err := p.Publish(context.Background(), false); err != nil || !published {
+		t.Fatalf("Publish() = %v, %v, want published", published, err)
+	}
+	payload, sig = read()
+	snapshot, err = client.VerifyCatalogSnapshot(payload, sig, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Tools) != 1 || snapshot.Tools[0].Name != "calc__add" {
+		t.Errorf("expected only the enabled tool in the snapshot, got %+v", snapshot.Tools)
+	}
+}
+
+func TestObjectStoreDestination(t *testing.T) {
+	var gotPath, gotAuth, gotHash string
+	var gotBody []byte
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotAuth = r.Header.Get("Authorization")
+		gotHash = r.Header.Get("X-Amz-Content-Sha256")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer store.Close()
+
+	if _, err := NewDestination("s3://bucket/catalog.json", ObjectStoreCredentials{}); err == nil {
+		t.Error("expected an error for an object store destination without credentials")
+	}
+	if _, err := NewDestination("ftp://host/catalog.json", ObjectStoreCredentials{}); err == nil {
+		t.Error("expected an error for an unsupported destination")
+	}
+
+	dest, err := NewDestination("s3://tools/prod/catalog v1.json", ObjectStoreCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		Region:          "eu-west-1",
+		Endpoint:        store.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dest.Put(context.Background(), SignatureSuffix, []byte("sig"), "application/jose"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if gotPath != "/tools/prod/catalog%20v1.json.jws" {
+		t.Errorf("unexpected object path %s", gotPath)
+	}
+	sum := sha256.Sum256([]byte("sig"))
+	if gotHash != hex.EncodeToString(sum[:]) || string(gotBody) != "sig" {
+		t.Errorf("unexpected payload hash %s for body %q", gotHash, gotBody)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(gotAuth, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("unexpected Authorization header %s", gotAuth)
+	}
+}
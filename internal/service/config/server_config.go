@@ -10,6 +10,9 @@ import (
 // ServerConfigService provides methods to manage server configuration in the database.
 type ServerConfigService struct {
 	db *gorm.DB
+
+	// forceReadOnly keeps this instance in read-only mode regardless of the configuration in the database
+	forceReadOnly bool
 }
 
 func NewServerConfigService(db *gorm.DB) *ServerConfigService {
@@ -49,3 +52,40 @@ func (s *ServerConfigService) Init(mode model.ServerMode) (bool, error) {
 	}
 	return true, s.db.Create(&config).Error
 }
+
+// ForceReadOnly puts this instance of the server in read-only mode, regardless of the configuration in the database.
+// This is useful when pointing a replica at the database of a primary instance.
+func (s *ServerConfigService) ForceReadOnly() {
+	s.forceReadOnly = true
+}
+
+// ReadOnly returns whether the registry is in read-only mode and whether the mode is forced on this instance,
+// in which case it cannot be turned off.
+func (s *ServerConfigService) ReadOnly() (enabled bool, forced bool, err error) {
+	if s.forceReadOnly {
+		return true, true, nil
+	}
+	config, err := s.GetConfig()
+	if err != nil {
+		return false, false, err
+	}
+	return config.ReadOnly, false, nil
+}
+
+// SetReadOnly turns the read-only mode of the registry on or off.
+func (s *ServerConfigService) SetReadOnly(enabled bool) error {
+	if s.forceReadOnly && !enabled {
+		return fmt.Errorf("read-only mode is forced on this server instance and cannot be turned off")
+	}
+	config, err := s.GetConfig()
+	if err != nil {
+		return err
+	}
+	if !config.Initialized {
+		return fmt.Errorf("server is not initialized")
+	}
+	if err := s.db.Model(&config).Update("read_only", enabled).Error; err != nil {
+		return fmt.Errorf("failed to update read-only mode: %v", err)
+	}
+	return nil
+}
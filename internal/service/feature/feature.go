@@ -0,0 +1,178 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Names of the features that can be turned on or off
+const (
+	Hedging           = "hedging"
+	ToolGroups        = "tool_groups"
+	StructuredContent = "structured_content"
+	FaultInjection    = "fault_injection"
+	ServerProposals   = "server_proposals"
+)
+
+// definition describes a feature and whether it is enabled when it hasn't been configured
+type definition struct {
+	name        string
+	description string
+	enabled     bool
+}
+
+// definitions contains all known features.
+// New experimental subsystems should register a feature here and check it before doing any work.
+var definitions = []definition{
+	{Hedging, "Send hedged requests for slow calls to idempotent tools", true},
+	{ToolGroups, "Serve curated subsets of tools on their own MCP endpoints", true},
+	{StructuredContent, "Convert JSON text results of tools with an output schema into structured content", true},
+	{FaultInjection, "Inject latency, errors & dropped responses into upstream calls according to fault rules", false},
+	{ServerProposals, "Let users propose MCP server registrations for admins to approve", false},
+}
+
+// cacheTTL is the maximum time that the features configured in the database are cached for,
+// so that changes made by other instances sharing the database are picked up.
+const cacheTTL = 30 * time.Second
+
+// FeatureService determines which features are enabled in this deployment.
+// The state of a feature is taken from the server's environment if set there, otherwise from the database.
+// Features that are configured in neither use their default state.
+type FeatureService struct {
+	db *gorm.DB
+
+	// overrides contains the features turned on or off via the server's environment
+	overrides map[string]bool
+
+	mu       sync.Mutex
+	cached   map[string]bool
+	cachedAt time.Time
+}
+
+// NewFeatureService creates a new FeatureService.
+// overrides is a comma-separated list of features to turn on, features prefixed with "-" are turned off
+// (eg- "tool_groups,-hedging").
+func NewFeatureService(db *gorm.DB, overrides string) (*FeatureService, error) {
+	f := &FeatureService{db: db, overrides: make(map[string]bool)}
+	for _, name := range strings.Split(overrides, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		enabled := !strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if _, ok := lookup(name); !ok {
+			return nil, fmt.Errorf("unknown feature: %s", name)
+		}
+		f.overrides[name] = enabled
+	}
+	return f, nil
+}
+
+func lookup(name string) (definition, bool) {
+	for _, d := range definitions {
+		if d.name == name {
+			return d, true
+		}
+	}
+	return definition{}, false
+}
+
+// Enabled returns true if the feature is enabled.
+// A nil FeatureService reports the default state of all features.
+func (f *FeatureService) Enabled(name string) bool {
+	d, ok := lookup(name)
+	if !ok {
+		return false
+	}
+	if f == nil {
+		return d.enabled
+	}
+	enabled, _ := f.state(d)
+	return enabled
+}
+
+// state returns whether a feature is enabled along with the source of its state.
+func (f *FeatureService) state(d definition) (bool, string) {
+	if enabled, ok := f.overrides[d.name]; ok {
+		return enabled, types.FeatureSourceEnvironment
+	}
+	configured, err := f.configured()
+	if err != nil {
+		// fall back to the default state rather than failing the operation that depends on the feature
+		return d.enabled, types.FeatureSourceDefault
+	}
+	if enabled, ok := configured[d.name]; ok {
+		return enabled, types.FeatureSourceDatabase
+	}
+	return d.enabled, types.FeatureSourceDefault
+}
+
+// configured returns the features that are configured in the database.
+func (f *FeatureService) configured() (map[string]bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cached != nil && time.Since(f.cachedAt) < cacheTTL {
+		return f.cached, nil
+	}
+
+	var flags []model.FeatureFlag
+	if err := f.db.Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to list feature flags from DB: %w", err)
+	}
+	f.cached = make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		f.cached[flag.Name] = flag.Enabled
+	}
+	f.cachedAt = time.Now()
+	return f.cached, nil
+}
+
+// ListFeatures returns all known features along with their state.
+func (f *FeatureService) ListFeatures() []types.Feature {
+	features := make([]types.Feature, 0, len(definitions))
+	for _, d := range definitions {
+		enabled, source := f.state(d)
+		features = append(features, types.Feature{
+			Name:        d.name,
+			Description: d.description,
+			Enabled:     enabled,
+			Source:      source,
+		})
+	}
+	return features
+}
+
+// ErrOverridden is returned when turning on or off a feature whose state is set in the server's environment.
+var ErrOverridden = errors.New("feature is configured in the server's environment")
+
+// SetFeature turns a feature on or off for the whole deployment.
+func (f *FeatureService) SetFeature(name string, enabled bool) error {
+	if _, ok := lookup(name); !ok {
+		return fmt.Errorf("unknown feature: %s", name)
+	}
+	if _, ok := f.overrides[name]; ok {
+		return fmt.Errorf("cannot change feature %s: %w", name, ErrOverridden)
+	}
+	flag := &model.FeatureFlag{Name: name, Enabled: enabled}
+	err := f.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(flag).Error
+	if err != nil {
+		return fmt.Errorf("failed to set feature %s: %w", name, err)
+	}
+
+	f.mu.Lock()
+	f.cached = nil
+	f.mu.Unlock()
+	return nil
+}
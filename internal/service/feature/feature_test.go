@@ -0,0 +1,74 @@
+package feature
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestFeatureService(t *testing.T, overrides string) *FeatureService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.FeatureFlag{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	f, err := NewFeatureService(db, overrides)
+	if err != nil {
+		t.Fatalf("NewFeatureService() error = %v", err)
+	}
+	return f
+}
+
+func TestFeatureSources(t *testing.T) {
+	f := newTestFeatureService(t, "-hedging")
+
+	if !f.Enabled(ToolGroups) {
+		t.Errorf("tool groups must be enabled by default")
+	}
+	if f.Enabled(Hedging) {
+		t.Errorf("hedging must be disabled by the environment override")
+	}
+	if err := f.SetFeature(Hedging, true); !errors.Is(err, ErrOverridden) {
+		t.Errorf("SetFeature() of an overridden feature error = %v, want ErrOverridden", err)
+	}
+
+	for _, enabled := range []bool{false, true, false} {
+		if err := f.SetFeature(ToolGroups, enabled); err != nil {
+			t.Fatalf("SetFeature() error = %v", err)
+		}
+		if f.Enabled(ToolGroups) != enabled {
+			t.Errorf("Enabled() = %v after SetFeature(%v)", !enabled, enabled)
+		}
+	}
+
+	for _, feature := range f.ListFeatures() {
+		want := map[string]string{
+			Hedging:           types.FeatureSourceEnvironment,
+			ToolGroups:        types.FeatureSourceDatabase,
+			StructuredContent: types.FeatureSourceDefault,
+			FaultInjection:    types.FeatureSourceDefault,
+			ServerProposals:   types.FeatureSourceDefault,
+		}[feature.Name]
+		if feature.Source != want {
+			t.Errorf("source of feature %s = %s, want %s", feature.Name, feature.Source, want)
+		}
+	}
+}
+
+func TestUnknownFeature(t *testing.T) {
+	if _, err := NewFeatureService(nil, "teleportation"); err == nil {
+		t.Errorf("NewFeatureService() with an unknown feature must fail")
+	}
+	var f *FeatureService
+	if !f.Enabled(Hedging) || f.Enabled("teleportation") {
+		t.Errorf("nil FeatureService must report the default state of features")
+	}
+}
@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// MaxAccessGrantDuration is the longest duration of a temporary access grant.
+// Access needed for longer belongs in the allow list of the client.
+const MaxAccessGrantDuration = 7 * 24 * time.Hour
+
+// GrantAccess allows an MCP client to call the tools of a tool group for the given duration,
+// regardless of the MCP servers in its allow list.
+func (m *MCPService) GrantAccess(
+	clientName, group string, duration time.Duration, grantedBy, reason string,
+) (*model.AccessGrant, error) {
+	if duration <= 0 || duration > MaxAccessGrantDuration {
+		return nil, fmt.Errorf("grant duration must be positive and at most %s", MaxAccessGrantDuration)
+	}
+	var n int64
+	if err := m.db.Model(&model.McpClient{}).Where("name = ?", clientName).Count(&n).Error; err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("MCP client %s not found", clientName)
+	}
+	if _, err := m.GetToolGroup(group); err != nil {
+		return nil, fmt.Errorf("tool group %s not found: %w", group, err)
+	}
+
+	g := &model.AccessGrant{
+		ClientName: clientName,
+		ToolGroup:  group,
+		ExpiresAt:  time.Now().Add(duration),
+		GrantedBy:  grantedBy,
+		Reason:     reason,
+	}
+	if err := m.db.Create(g).Error; err != nil {
+		return nil, fmt.Errorf("failed to create access grant: %w", err)
+	}
+
+	msg := fmt.Sprintf(
+		"MCP client %s was granted access to tool group %s until %s", clientName, group, g.ExpiresAt.Format(time.RFC3339),
+	)
+	if grantedBy != "" {
+		msg += " by " + grantedBy
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	log.Printf("[INFO] %s", msg)
+	if m.notifications != nil {
+		m.notifications.Notify(types.NotificationAccessGranted, clientName, msg)
+	}
+	return g, nil
+}
+
+// ListAccessGrants returns the access grants that haven't expired yet.
+func (m *MCPService) ListAccessGrants() ([]*model.AccessGrant, error) {
+	var grants []*model.AccessGrant
+	if err := m.db.Where("expires_at > ?", time.Now()).Order("expires_at").Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// RevokeAccessGrant ends an access grant before it expires.
+func (m *MCPService) RevokeAccessGrant(id uint) error {
+	res := m.db.Unscoped().Delete(&model.AccessGrant{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("access grant not found")
+	}
+	log.Printf("[INFO] access grant %d was revoked", id)
+	return nil
+}
+
+// checkToolAccess returns true if the MCP client can call the tool with the given canonical name,
+// either because its server is in the client's allow list or because of an active grant of a group including it.
+func (m *MCPService) checkToolAccess(c *model.McpClient, serverName, name string) bool {
+	if c.CheckHasServerAccess(serverName) {
+		return true
+	}
+	var grants []*model.AccessGrant
+	err := m.db.Where("client_name = ? AND expires_at > ?", c.Name, time.Now()).Find(&grants).Error
+	if err != nil {
+		log.Printf("[ERROR] failed to look up the access grants of MCP client %s: %v", c.Name, err)
+		return false
+	}
+	for _, g := range grants {
+		group, err := m.GetToolGroup(g.ToolGroup)
+		if err == nil && group.HasTool(name) {
+			return true
+		}
+	}
+	return false
+}
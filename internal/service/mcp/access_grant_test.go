@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/datatypes"
+)
+
+func TestAccessGrants(t *testing.T) {
+	m := newTestMCPService(t, &model.McpClient{}, &model.ToolGroup{}, &model.AccessGrant{})
+	db := m.db
+
+	client := &model.McpClient{Name: "oncall", AccessToken: "token", AllowList: datatypes.JSON(`["github"]`)}
+	if err := db.Create(client).Error; err != nil {
+		t.Fatal(err)
+	}
+	group := &model.ToolGroup{Name: "k8s-admin", IncludedTools: datatypes.JSON(`["k8s__delete_pod"]`)}
+	if err := db.Create(group).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.checkToolAccess(client, "github", "github__search") {
+		t.Error("client cannot call a tool of a server in its allow list")
+	}
+	if m.checkToolAccess(client, "k8s", "k8s__delete_pod") {
+		t.Error("client can call a tool outside its allow list without a grant")
+	}
+
+	for _, d := range []time.Duration{0, -time.Hour, MaxAccessGrantDuration + time.Hour} {
+		if _, err := m.GrantAccess("oncall", "k8s-admin", d, "admin", ""); err == nil {
+			t.Errorf("GrantAccess() accepted duration %s", d)
+		}
+	}
+	if _, err := m.GrantAccess("ghost", "k8s-admin", time.Hour, "admin", ""); err == nil {
+		t.Error("GrantAccess() accepted an unknown client")
+	}
+	if _, err := m.GrantAccess("oncall", "ghost", time.Hour, "admin", ""); err == nil {
+		t.Error("GrantAccess() accepted an unknown tool group")
+	}
+
+	g, err := m.GrantAccess("oncall", "k8s-admin", 2*time.Hour, "admin", "INC-1234")
+	if err != nil {
+		t.Fatalf("GrantAccess() error = %v", err)
+	}
+	if !m.checkToolAccess(client, "k8s", "k8s__delete_pod") {
+		t.Error("client cannot call a tool of a group it was granted access to")
+	}
+	if m.checkToolAccess(client, "k8s", "k8s__drain_node") {
+		t.Error("a grant gives access to a tool outside of its group")
+	}
+	grants, err := m.ListAccessGrants()
+	if err != nil || len(grants) != 1 || grants[0].Reason != "INC-1234" {
+		t.Fatalf("ListAccessGrants() = %v, %v", grants, err)
+	}
+
+	if err := m.RevokeAccessGrant(g.ID); err != nil {
+		t.Fatalf("RevokeAccessGrant() error = %v", err)
+	}
+	if m.checkToolAccess(client, "k8s", "k8s__delete_pod") {
+		t.Error("a revoked grant still gives access")
+	}
+	if err := m.RevokeAccessGrant(g.ID); err == nil {
+		t.Error("RevokeAccessGrant() succeeded on a grant that doesn't exist")
+	}
+
+	// an expired grant neither gives access nor is listed
+	expired := &model.AccessGrant{ClientName: "oncall", ToolGroup: "k8s-admin", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := db.Create(expired).Error; err != nil {
+		t.Fatal(err)
+	}
+	if m.checkToolAccess(client, "k8s", "k8s__delete_pod") {
+		t.Error("an expired grant still gives access")
+	}
+	if grants, _ := m.ListAccessGrants(); len(grants) != 0 {
+		t.Errorf("ListAccessGrants() lists expired grants: %v", grants)
+	}
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// builtinServers holds the MCP servers that run inside this mcpjungle process, keyed by server name.
+// Tool calls to them go through an in-process client instead of a network connection or a sub-process.
+var builtinServers = struct {
+	sync.RWMutex
+	servers map[string]*server.MCPServer
+}{servers: make(map[string]*server.MCPServer)}
+
+// RegisterBuiltinServer makes an MCP server that runs inside mcpjungle available through the gateway.
+// The server is (re-)registered in the registry, so its tools are refreshed every time mcpjungle starts.
+// It fails if a regular MCP server with the same name is already registered.
+func (m *MCPService) RegisterBuiltinServer(ctx context.Context, name, description string, srv *server.MCPServer) error {
+	existing, err := m.GetMcpServer(name)
+	if err == nil {
+		if existing.Transport != types.TransportBuiltin {
+			return fmt.Errorf(
+				"cannot enable built-in MCP server %s, an MCP server with the same name is already registered", name,
+			)
+		}
+		if err := m.DeregisterMcpServer(name, 0); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+
+	builtinServers.Lock()
+	builtinServers.servers[name] = srv
+	builtinServers.Unlock()
+
+	return m.RegisterMcpServer(ctx, model.NewBuiltinServer(name, description))
+}
+
+// connectBuiltinServer creates a new in-process session with a built-in MCP server.
+// It returns the client along with the server's response to the initialization request.
+func connectBuiltinServer(ctx context.Context, s *model.McpServer) (*client.Client, *mcp.InitializeResult, error) {
+	builtinServers.RLock()
+	srv, ok := builtinServers.servers[s.Name]
+	builtinServers.RUnlock()
+	if !ok {
+		return nil, nil, errors.New("this server is not enabled in this mcpjungle instance, deregister it if it is no longer needed")
+	}
+
+	c, err := client.NewInProcessClient(srv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Start(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "mcpjungle mcp client for builtin",
+		Version: "0.1",
+	}
+	initRequest.Params.Capabilities = mcp.ClientCapabilities{}
+
+	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
+	defer cancel()
+
+	initResult, err := c.Initialize(initCtx, initRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+	}
+	return c, initResult, nil
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// recordServerCapabilities stores the outcome of the initialization handshake with an MCP server in its model.
+// It does not save the model to the DB.
+func recordServerCapabilities(s *model.McpServer, initResult *mcp.InitializeResult) {
+	if initResult == nil {
+		return
+	}
+	s.ProtocolVersion = initResult.ProtocolVersion
+	s.Capabilities, _ = json.Marshal(initResult.Capabilities)
+	s.ServerInfo, _ = json.Marshal(initResult.ServerInfo)
+}
+
+// GetMcpServerCapabilities returns the protocol version & capabilities negotiated with an MCP server.
+// These are recorded when the server is registered.
+// If they are unknown (eg- the server was registered by an older version of mcpjungle) or refresh is true,
+// mcpjungle connects to the server to negotiate them again and stores the result.
+func (m *MCPService) GetMcpServerCapabilities(ctx context.Context, name string, refresh bool) (*types.ServerCapabilities, error) {
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, err
+	}
+	if refresh || s.ProtocolVersion == "" {
+		mcpClient, initResult, err := m.connectMcpServer(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		_ = mcpClient.Close()
+		if err := m.checkServerIdentity(s, initResult); err != nil {
+			return nil, err
+		}
+
+		pinned := s.ServerInfo
+		recordServerCapabilities(s, initResult)
+		if s.PinsIdentity() && len(pinned) > 0 {
+			// the pinned identity only changes when the server is explicitly re-pinned
+			s.ServerInfo = pinned
+		}
+		err = model.UpdateVersioned[model.McpServer](m.db, "server", s.Name, s.ID, s.Version, map[string]any{
+			"protocol_version": s.ProtocolVersion,
+			"capabilities":     s.Capabilities,
+			"server_info":      s.ServerInfo,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save capabilities of MCP server %s: %w", s.Name, err)
+		}
+	}
+
+	return describeCapabilities(s)
+}
+
+// describeCapabilities returns the protocol version & capabilities recorded in the model of a server.
+func describeCapabilities(s *model.McpServer) (*types.ServerCapabilities, error) {
+	c := &types.ServerCapabilities{
+		Name:            s.Name,
+		ProtocolVersion: s.ProtocolVersion,
+		Capabilities:    map[string]any{},
+	}
+	if len(s.Capabilities) > 0 {
+		if err := json.Unmarshal(s.Capabilities, &c.Capabilities); err != nil {
+			return nil, fmt.Errorf("failed to decode capabilities of MCP server %s: %w", s.Name, err)
+		}
+	}
+	c.ServerInfo = s.GetServerInfo()
+	return c, nil
+}
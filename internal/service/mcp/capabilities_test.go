@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestRegisterServerWithoutToolsCapability(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	// an upstream server that only supports logging
+	upstream := server.NewTestStreamableHTTPServer(
+		server.NewMCPServer("logs-only", "1.2.3", server.WithLogging()),
+	)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("logs", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	caps, err := m.GetMcpServerCapabilities(context.Background(), "logs", false)
+	if err != nil {
+		t.Fatalf("GetMcpServerCapabilities() error = %v", err)
+	}
+	if caps.ProtocolVersion == "" {
+		t.Error("expected the negotiated protocol version to be recorded")
+	}
+	if caps.ServerInfo == nil || caps.ServerInfo.Name != "logs-only" || caps.ServerInfo.Version != "1.2.3" {
+		t.Errorf("ServerInfo = %+v, want logs-only 1.2.3", caps.ServerInfo)
+	}
+	if _, ok := caps.Capabilities["logging"]; !ok {
+		t.Errorf("expected logging capability, got %v", caps.Capabilities)
+	}
+	if _, ok := caps.Capabilities["tools"]; ok {
+		t.Errorf("did not expect tools capability, got %v", caps.Capabilities)
+	}
+}
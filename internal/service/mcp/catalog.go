@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"sync"
+	"time"
+)
+
+// catalogTTL is the maximum time the tool catalog is cached for.
+// Mutations made by this instance invalidate the cache immediately, the TTL makes sure that
+// changes made by other instances sharing the same database are eventually picked up as well.
+const catalogTTL = 30 * time.Second
+
+// toolCatalog is an in-memory cache of all tools in the registry, keyed by their canonical names.
+// Its zero value is an empty cache, ready to use.
+type toolCatalog struct {
+	mu       sync.RWMutex
+	tools    []model.Tool
+	byName   map[string]int
+	loadedAt time.Time
+
+	// generation is incremented on every invalidation so that a catalog loaded from the DB
+	// concurrently with a mutation is not cached.
+	generation uint64
+}
+
+// invalidate drops the cached catalog so that it is re-loaded from the DB on the next read.
+func (c *toolCatalog) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tools = nil
+	c.byName = nil
+	c.generation++
+}
+
+// cached returns the cached tools if the cache is fresh, along with the current generation.
+func (c *toolCatalog) cached() ([]model.Tool, map[string]int, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.byName == nil || time.Since(c.loadedAt) > catalogTTL {
+		return nil, nil, c.generation
+	}
+	return c.tools, c.byName, c.generation
+}
+
+// store caches the tools unless the catalog was invalidated since generation.
+func (c *toolCatalog) store(tools []model.Tool, generation uint64) map[string]int {
+	byName := make(map[string]int, len(tools))
+	for i := range tools {
+		byName[tools[i].Name] = i
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.tools = tools
+		c.byName = byName
+		c.loadedAt = time.Now()
+	}
+	return byName
+}
+
+// catalog returns all tools in the registry with their canonical names, served from the cache if possible.
+// The returned values are shared and must not be modified.
+func (m *MCPService) catalog() ([]model.Tool, map[string]int, error) {
+	tools, byName, generation := m.toolCatalog.cached()
+	if byName != nil {
+		return tools, byName, nil
+	}
+
+	// load the servers along with their tools to avoid querying the DB for the server of every tool
+	if err := m.db.Preload("Server").Find(&tools).Error; err != nil {
+		return nil, nil, err
+	}
+	for i := range tools {
+		if tools[i].Server.ID == 0 {
+			return nil, nil, fmt.Errorf("failed to get server for tool %s", tools[i].Name)
+		}
+		// prepend server name to tool names to ensure we only return the unique names of tools to user
+		tools[i].Name = mergeServerToolNames(tools[i].Server.Name, tools[i].Name)
+		tools[i].Server = model.McpServer{}
+	}
+	return tools, m.toolCatalog.store(tools, generation), nil
+}
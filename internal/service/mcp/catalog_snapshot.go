@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// PublicCatalogSnapshot returns the catalog of the tools that MCP clients can currently call, for publishing
+// outside the gateway: the enabled tools and the servers that provide them.
+// The servers are described without their URL, command, arguments or environment, and the tools without
+// their scripts and payload logging settings, which are details of the deployment that consumers don't need.
+func (m *MCPService) PublicCatalogSnapshot() (*types.CatalogSnapshot, error) {
+	records, err := m.ListMcpServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP servers: %w", err)
+	}
+	tools, err := m.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+
+	snapshot := &types.CatalogSnapshot{
+		TakenAt: time.Now().UTC(),
+		Servers: []*types.McpServer{},
+		Tools:   []*types.Tool{},
+	}
+	provides := make(map[string]bool)
+	for _, t := range tools {
+		if !t.Enabled {
+			continue
+		}
+		// the tool records serialize to the API representation of tools
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize tool %s: %w", t.Name, err)
+		}
+		var tool types.Tool
+		if err := json.Unmarshal(b, &tool); err != nil {
+			return nil, fmt.Errorf("failed to serialize tool %s: %w", t.Name, err)
+		}
+		tool.ArgsScript = ""
+		tool.ResultScript = ""
+		tool.PayloadSampleRate = 0
+		tool.PayloadRedactions = nil
+		snapshot.Tools = append(snapshot.Tools, &tool)
+
+		if serverName, _, ok := splitServerToolName(t.Name); ok {
+			provides[serverName] = true
+		}
+	}
+	for _, s := range records {
+		if !provides[s.Name] || s.Stopped {
+			continue
+		}
+		snapshot.Servers = append(snapshot.Servers, &types.McpServer{
+			Name:        s.Name,
+			Transport:   string(s.Transport),
+			Description: s.Description,
+			Region:      s.Region,
+			Group:       s.Group,
+			Version:     s.Version,
+			ServerInfo:  s.GetServerInfo(),
+		})
+	}
+
+	sort.Slice(snapshot.Servers, func(i, j int) bool { return snapshot.Servers[i].Name < snapshot.Servers[j].Name })
+	sort.Slice(snapshot.Tools, func(i, j int) bool { return snapshot.Tools[i].Name < snapshot.Tools[j].Name })
+	return snapshot, nil
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestToolCatalogInvalidation(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	upstreamServer := server.NewMCPServer("calc", "0.0.1", server.WithToolCapabilities(true))
+	for _, name := range []string{"add", "sub"} {
+		upstreamServer.AddTool(mcp.NewTool(name), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("calc", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	tools, err := m.ListTools()
+	if err != nil || len(tools) != 2 {
+		t.Fatalf("ListTools() = %d tools, error = %v, want 2 tools", len(tools), err)
+	}
+	// modifying the returned tools must not affect the cache
+	tools[0].Name = "modified"
+	if _, err := m.GetTool("calc__add"); err != nil {
+		t.Fatalf("GetTool() error = %v", err)
+	}
+
+	if _, err := m.DisableTools("calc__add", 0); err != nil {
+		t.Fatalf("DisableTools() error = %v", err)
+	}
+	tool, err := m.GetTool("calc__add")
+	if err != nil {
+		t.Fatalf("GetTool() error = %v", err)
+	}
+	if tool.Enabled {
+		t.Errorf("GetTool() returned a stale tool after it was disabled")
+	}
+
+	if err := m.DeregisterMcpServer("calc", 0); err != nil {
+		t.Fatalf("DeregisterMcpServer() error = %v", err)
+	}
+	if tools, _ := m.ListTools(); len(tools) != 0 {
+		t.Errorf("ListTools() = %d tools after the server was deregistered, want none", len(tools))
+	}
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrDeadlineExceeded is returned when the deadline set by the caller of a tool leaves no time to call its MCP server.
+var ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+// SetDeadlineOverhead sets the time that mcpjungle reserves for itself (processing & relaying the result) out of
+// the deadline of a tool call. The upstream MCP server is given the rest of the budget.
+func (m *MCPService) SetDeadlineOverhead(d time.Duration) {
+	m.deadlineOverhead = d
+}
+
+// callDeadline returns the deadline of a tool call: the earliest of the one derived from the timeout header
+// of the request (stored in ctx by the API) and the one derived from the timeout in the call's _meta.
+func callDeadline(ctx context.Context, meta *mcp.Meta) (time.Time, bool, error) {
+	deadline, ok := ctx.Value("deadline").(time.Time)
+	if meta == nil || meta.AdditionalFields == nil {
+		return deadline, ok, nil
+	}
+	v, found := meta.AdditionalFields[types.TimeoutMetaKey]
+	if !found {
+		return deadline, ok, nil
+	}
+	ms, isNumber := v.(float64)
+	if !isNumber || ms <= 0 {
+		return time.Time{}, false, fmt.Errorf("%s in _meta must be a positive number of milliseconds", types.TimeoutMetaKey)
+	}
+	d := time.Now().Add(time.Duration(ms * float64(time.Millisecond)))
+	if !ok || d.Before(deadline) {
+		deadline = d
+	}
+	return deadline, true, nil
+}
+
+// withCallBudget bounds a tool call by the deadline set by its caller, if any.
+// The returned context expires once the budget of the upstream call (the time left until the deadline,
+// minus the gateway's overhead) is spent, and the budget is passed on in the _meta of the request so that
+// upstream servers (eg- another mcpjungle) can enforce it as well.
+// It returns ErrDeadlineExceeded if no budget is left.
+func (m *MCPService) withCallBudget(
+	ctx context.Context, request *mcp.CallToolRequest,
+) (context.Context, context.CancelFunc, error) {
+	deadline, ok, err := callDeadline(ctx, request.Params.Meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return ctx, func() {}, nil
+	}
+	budget := time.Until(deadline) - m.deadlineOverhead
+	if budget < time.Millisecond {
+		return nil, nil, fmt.Errorf("no time left to call the MCP server: %w", ErrDeadlineExceeded)
+	}
+
+	if request.Params.Meta == nil {
+		request.Params.Meta = &mcp.Meta{}
+	}
+	fields := make(map[string]any, len(request.Params.Meta.AdditionalFields)+1)
+	for k, v := range request.Params.Meta.AdditionalFields {
+		fields[k] = v
+	}
+	fields[types.TimeoutMetaKey] = budget.Milliseconds()
+	request.Params.Meta = &mcp.Meta{ProgressToken: request.Params.Meta.ProgressToken, AdditionalFields: fields}
+
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	return ctx, cancel, nil
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestWithCallBudget(t *testing.T) {
+	m := &MCPService{deadlineOverhead: 100 * time.Millisecond}
+
+	// without a deadline, the call is not bounded
+	req := mcp.CallToolRequest{}
+	ctx, cancel, err := m.withCallBudget(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("withCallBudget() error = %v", err)
+	}
+	cancel()
+	if _, ok := ctx.Deadline(); ok || req.Params.Meta != nil {
+		t.Error("withCallBudget() bounded a call without deadline")
+	}
+
+	// the deadline from the header is reduced by the overhead and passed on upstream
+	deadline := time.Now().Add(2 * time.Second)
+	ctx, cancel, err = m.withCallBudget(context.WithValue(context.Background(), "deadline", deadline), &req)
+	if err != nil {
+		t.Fatalf("withCallBudget() error = %v", err)
+	}
+	defer cancel()
+	d, ok := ctx.Deadline()
+	if !ok || d.After(deadline.Add(-90*time.Millisecond)) {
+		t.Errorf("context deadline = %v, want about 100ms before %v", d, deadline)
+	}
+	budget, _ := req.Params.Meta.AdditionalFields[types.TimeoutMetaKey].(int64)
+	if budget <= 0 || budget > 1900 {
+		t.Errorf("budget passed upstream = %dms, want at most 1900ms", budget)
+	}
+
+	// the earliest of the deadlines from the header & _meta wins
+	req = mcp.CallToolRequest{}
+	req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{types.TimeoutMetaKey: float64(500), "trace": "abc"}}
+	_, cancel, err = m.withCallBudget(context.WithValue(context.Background(), "deadline", deadline), &req)
+	if err != nil {
+		t.Fatalf("withCallBudget() error = %v", err)
+	}
+	cancel()
+	budget, _ = req.Params.Meta.AdditionalFields[types.TimeoutMetaKey].(int64)
+	if budget <= 0 || budget > 400 {
+		t.Errorf("budget passed upstream = %dms, want at most 400ms", budget)
+	}
+	if req.Params.Meta.AdditionalFields["trace"] != "abc" {
+		t.Error("withCallBudget() dropped the other fields of _meta")
+	}
+
+	// no time left for the upstream call
+	req = mcp.CallToolRequest{}
+	req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{types.TimeoutMetaKey: float64(50)}}
+	if _, _, err = m.withCallBudget(context.Background(), &req); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("withCallBudget() error = %v, want ErrDeadlineExceeded", err)
+	}
+
+	req.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{types.TimeoutMetaKey: "soon"}}
+	if _, _, err = m.withCallBudget(context.Background(), &req); err == nil {
+		t.Error("withCallBudget() accepted an invalid timeout")
+	}
+}
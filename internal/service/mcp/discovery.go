@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+	"sync"
+)
+
+const (
+	// toolDiscoveryWorkers is the maximum number of pages of tools that are written to the DB concurrently
+	toolDiscoveryWorkers = 4
+
+	// toolInsertBatchSize is the maximum number of tools inserted into the DB with a single statement
+	toolInsertBatchSize = 100
+)
+
+// fetchServerTools fetches the tools provided by an MCP server, following the server's pagination.
+// Each page of tools is passed to onPage as soon as it has been fetched, so that it can be processed
+// while the next page is being fetched.
+// Servers that don't advertise the tools capability may not implement tools/list at all,
+// so failing to list their tools is not treated as an error.
+func fetchServerTools(ctx context.Context, s *model.McpServer, c *client.Client, onPage func([]mcp.Tool)) error {
+	req := mcp.ListToolsRequest{}
+	for page := 0; ; page++ {
+		resp, err := c.ListTools(ctx, req)
+		if err != nil {
+			if page == 0 && c.GetServerCapabilities().Tools == nil {
+				log.Printf("[WARN] MCP server %s does not support tools, no tools were registered: %v", s.Name, err)
+				return nil
+			}
+			return fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
+		}
+		if len(resp.Tools) > 0 {
+			onPage(resp.Tools)
+		}
+		if resp.NextCursor == "" || resp.NextCursor == req.Params.Cursor {
+			return nil
+		}
+		req.Params.Cursor = resp.NextCursor
+	}
+}
+
+// listServerTools fetches all tools provided by an MCP server.
+func listServerTools(ctx context.Context, s *model.McpServer, c *client.Client) ([]mcp.Tool, error) {
+	var tools []mcp.Tool
+	err := fetchServerTools(ctx, s, c, func(page []mcp.Tool) {
+		tools = append(tools, page...)
+	})
+	return tools, err
+}
+
+// newToolModel converts a tool fetched from an MCP server into a tool model to be stored in the DB.
+func newToolModel(s *model.McpServer, tool mcp.Tool) model.Tool {
+	// extracting json schema is currently on best-effort basis
+	jsonSchema, _ := json.Marshal(tool.InputSchema)
+	annotations, _ := json.Marshal(tool.Annotations)
+	return model.Tool{
+		ServerID:    s.ID,
+		Name:        tool.GetName(),
+		Description: tool.Description,
+		InputSchema: jsonSchema,
+		Annotations: annotations,
+	}
+}
+
+// registerServerTools fetches all tools from an MCP server and registers them in the DB.
+// Pages of tools are written to the DB in batches by a pool of workers while the next pages are still being fetched.
+// Registered tools are also added to the MCP proxy server.
+func (m *MCPService) registerServerTools(ctx context.Context, s *model.McpServer, c *client.Client) error {
+	pages := make(chan []mcp.Tool, toolDiscoveryWorkers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < toolDiscoveryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pages {
+				m.storeServerTools(s, page)
+			}
+		}()
+	}
+
+	err := fetchServerTools(ctx, s, c, func(page []mcp.Tool) {
+		pages <- page
+	})
+	close(pages)
+	wg.Wait()
+	return err
+}
+
+// storeServerTools inserts a page of tools provided by an MCP server into the DB and adds them to the MCP proxy server.
+func (m *MCPService) storeServerTools(s *model.McpServer, page []mcp.Tool) {
+	tools := make([]model.Tool, len(page))
+	for i, tool := range page {
+		tools[i] = newToolModel(s, tool)
+	}
+
+	defer m.toolCatalog.invalidate()
+
+	registered := page
+	if err := m.db.CreateInBatches(tools, toolInsertBatchSize).Error; err != nil {
+		// If registration of a tool fails, we should not fail the entire server registration.
+		// Instead, register the tools one by one so that only the offending ones are skipped.
+		registered = nil
+		for i := range tools {
+			canonicalToolName := mergeServerToolNames(s.Name, tools[i].Name)
+			tools[i].ID = 0
+			if err := m.db.Create(&tools[i]).Error; err != nil {
+				log.Printf("[ERROR] failed to register tool %s in DB: %v", canonicalToolName, err)
+				continue
+			}
+			registered = append(registered, page[i])
+		}
+	}
+
+	for _, tool := range registered {
+		// Set tool name to include the server name prefix to make it recognizable by MCPJungle
+		// then add the tool to the MCP proxy server
+		tool.Name = mergeServerToolNames(s.Name, tool.GetName())
+		m.addProxyTool(tool)
+	}
+}
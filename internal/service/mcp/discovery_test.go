@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestRegisterMcpServerPaginatedTools(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	const numTools = 250
+	upstreamServer := server.NewMCPServer(
+		"big", "0.0.1", server.WithToolCapabilities(true), server.WithPaginationLimit(40),
+	)
+	for i := 0; i < numTools; i++ {
+		upstreamServer.AddTool(mcp.NewTool(fmt.Sprintf("tool_%03d", i)), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("big", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	tools, err := m.ListToolsByServer("big")
+	if err != nil {
+		t.Fatalf("ListToolsByServer() error = %v", err)
+	}
+	if len(tools) != numTools {
+		t.Fatalf("registered %d tools, want %d", len(tools), numTools)
+	}
+	for _, tool := range tools {
+		if !tool.Enabled {
+			t.Fatalf("tool %s is disabled, want all tools enabled", tool.Name)
+		}
+	}
+}
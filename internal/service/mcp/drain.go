@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrServerDraining is returned when calling a tool of an MCP server that is being deregistered.
+var ErrServerDraining = errors.New("MCP server is being deregistered")
+
+// DefaultDrainTimeout is how long deregistering an MCP server waits for its in-flight tool calls by default
+const DefaultDrainTimeout = 30 * time.Second
+
+// callDrainer tracks the tool calls in flight to every MCP server, so that a server is only torn down
+// once the calls made to it have completed.
+type callDrainer struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	// draining contains the servers that don't accept new calls, their channel is closed once no call is in flight
+	draining map[string]chan struct{}
+}
+
+// begin records a call to the given server. It returns ErrServerDraining if the server is being deregistered.
+// The returned function must be called once the call has completed.
+func (d *callDrainer) begin(server string) (func(), error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.draining[server]; ok {
+		return nil, ErrServerDraining
+	}
+	if d.inFlight == nil {
+		d.inFlight = make(map[string]int)
+	}
+	d.inFlight[server]++
+
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.inFlight[server]--; d.inFlight[server] > 0 {
+			return
+		}
+		delete(d.inFlight, server)
+		if idle, ok := d.draining[server]; ok {
+			close(idle)
+		}
+	}, nil
+}
+
+// drain stops the given server from accepting new calls, then waits up to timeout for its in-flight calls
+// to complete. It returns the number of calls still in flight when the timeout expired.
+// done must be called once the server was torn down (or its deregistration failed) to accept calls again.
+func (d *callDrainer) drain(server string, timeout time.Duration) int {
+	d.mu.Lock()
+	idle, ok := d.draining[server]
+	if !ok {
+		if d.draining == nil {
+			d.draining = make(map[string]chan struct{})
+		}
+		idle = make(chan struct{})
+		d.draining[server] = idle
+		if d.inFlight[server] == 0 {
+			close(idle)
+		}
+	}
+	d.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return 0
+	case <-timer.C:
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inFlight[server]
+}
+
+// done lets the given server accept calls again after it was drained.
+func (d *callDrainer) done(server string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.draining, server)
+}
+
+// SetDrainTimeout sets how long deregistering an MCP server waits for its in-flight tool calls to complete
+// before tearing it down. New calls to the server are rejected while waiting.
+func (m *MCPService) SetDrainTimeout(d time.Duration) {
+	m.drainTimeout = d
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCallDrainerWaitsForInFlightCalls(t *testing.T) {
+	var d callDrainer
+	done, err := d.begin("git")
+	if err != nil {
+		t.Fatalf("begin() error = %v", err)
+	}
+
+	drained := make(chan int)
+	go func() {
+		drained <- d.drain("git", 5*time.Second)
+	}()
+	// new calls are rejected as soon as the server is draining
+	deadline := time.Now().Add(time.Second)
+	for {
+		release, err := d.begin("git")
+		if errors.Is(err, ErrServerDraining) {
+			break
+		}
+		if err == nil {
+			// the drain hasn't started yet
+			release()
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("begin() on a server being drained did not fail")
+		}
+	}
+	if release, err := d.begin("time"); err != nil {
+		t.Errorf("begin() on another server error = %v, want nil", err)
+	} else {
+		release()
+	}
+
+	select {
+	case <-drained:
+		t.Fatal("drain() returned while a call was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	done()
+	if remaining := <-drained; remaining != 0 {
+		t.Errorf("drain() = %d calls remaining, want 0", remaining)
+	}
+
+	d.done("git")
+	if _, err := d.begin("git"); err != nil {
+		t.Errorf("begin() after the drain is done error = %v, want nil", err)
+	}
+}
+
+func TestCallDrainerTimeout(t *testing.T) {
+	var d callDrainer
+	if _, err := d.begin("git"); err != nil {
+		t.Fatalf("begin() error = %v", err)
+	}
+	if remaining := d.drain("git", 10*time.Millisecond); remaining != 1 {
+		t.Errorf("drain() = %d calls remaining, want 1", remaining)
+	}
+	if remaining := d.drain("idle", time.Second); remaining != 0 {
+		t.Errorf("drain() of an idle server = %d calls remaining, want 0", remaining)
+	}
+}
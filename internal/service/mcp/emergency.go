@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErrEmergencyStop is returned when a tool call is rejected because all tool calls are disabled
+var ErrEmergencyStop = errors.New("all tool calls are disabled by an emergency stop")
+
+// EmergencyDisableAll disables all tool calls, except those to the given tools or MCP servers, until
+// EmergencyEnableAll is called. The switch is stored in the database, so it applies to all instances
+// of the registry immediately. Calling it again while the switch is on replaces the allow list and the reason.
+func (m *MCPService) EmergencyDisableAll(allowedTools []string, reason, activatedBy string) (*model.EmergencyStop, error) {
+	allowed, err := json.Marshal(allowedTools)
+	if err != nil {
+		return nil, err
+	}
+	stop := &model.EmergencyStop{AllowedTools: datatypes.JSON(allowed), Reason: reason, ActivatedBy: activatedBy}
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("1 = 1").Delete(&model.EmergencyStop{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(stop).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to disable tool calls: %w", err)
+	}
+
+	msg := "All tool calls were disabled"
+	if activatedBy != "" {
+		msg += " by " + activatedBy
+	}
+	if len(allowedTools) > 0 {
+		msg += ", except " + strings.Join(allowedTools, ", ")
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	log.Printf("[WARN] %s", msg)
+	if m.notifications != nil {
+		m.notifications.Notify(types.NotificationEmergencyStop, "all tools", msg)
+	}
+	return stop, nil
+}
+
+// EmergencyEnableAll turns the emergency stop off, tool calls are accepted again.
+// It returns false if the emergency stop was not on.
+func (m *MCPService) EmergencyEnableAll(enabledBy string) (bool, error) {
+	res := m.db.Unscoped().Where("1 = 1").Delete(&model.EmergencyStop{})
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to enable tool calls: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	msg := "Tool calls were enabled again"
+	if enabledBy != "" {
+		msg += " by " + enabledBy
+	}
+	log.Printf("[INFO] %s", msg)
+	if m.notifications != nil {
+		m.notifications.Notify(types.NotificationEmergencyStop, "all tools", msg)
+	}
+	return true, nil
+}
+
+// GetEmergencyStop returns the emergency stop, nil if tool calls are not disabled.
+func (m *MCPService) GetEmergencyStop() (*model.EmergencyStop, error) {
+	var stops []*model.EmergencyStop
+	if err := m.db.Limit(1).Find(&stops).Error; err != nil {
+		return nil, err
+	}
+	if len(stops) == 0 {
+		return nil, nil
+	}
+	return stops[0], nil
+}
+
+// checkEmergencyStop returns an error wrapping ErrEmergencyStop if the tool with the given canonical name
+// cannot be called because of an emergency stop.
+func (m *MCPService) checkEmergencyStop(name string) error {
+	stop, err := m.GetEmergencyStop()
+	if err != nil {
+		// fail closed, the switch exists to stop misbehaving agents
+		return fmt.Errorf("%w: failed to check the emergency stop: %v", ErrEmergencyStop, err)
+	}
+	if stop == nil {
+		return nil
+	}
+	serverName, _, _ := splitServerToolName(name)
+	allowed := stop.GetAllowedTools()
+	if slices.Contains(allowed, name) || slices.Contains(allowed, serverName) {
+		return nil
+	}
+	metrics.EmergencyRejectedCalls.WithLabelValues(name).Inc()
+	if stop.Reason != "" {
+		return fmt.Errorf("%w (%s), cannot call tool %s", ErrEmergencyStop, stop.Reason, name)
+	}
+	return fmt.Errorf("%w, cannot call tool %s", ErrEmergencyStop, name)
+}
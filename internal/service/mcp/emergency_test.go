@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestEmergencyStop(t *testing.T) {
+	m := newTestMCPService(t, &model.EmergencyStop{})
+	db := m.db
+
+	if err := m.checkEmergencyStop("github__search"); err != nil {
+		t.Errorf("checkEmergencyStop() error = %v without an emergency stop", err)
+	}
+	if enabled, err := m.EmergencyEnableAll("admin"); err != nil || enabled {
+		t.Errorf("EmergencyEnableAll() = %v, %v without an emergency stop, want false", enabled, err)
+	}
+
+	if _, err := m.EmergencyDisableAll([]string{"pagerduty", "slack__post_message"}, "rogue agent", "admin"); err != nil {
+		t.Fatalf("EmergencyDisableAll() error = %v", err)
+	}
+	for name, allowed := range map[string]bool{
+		"github__search":       false,
+		"slack__list_channels": false,
+		"slack__post_message":  true,
+		"pagerduty__page":      true,
+	} {
+		err := m.checkEmergencyStop(name)
+		if allowed && err != nil {
+			t.Errorf("checkEmergencyStop(%s) error = %v, want nil", name, err)
+		}
+		if !allowed && !errors.Is(err, ErrEmergencyStop) {
+			t.Errorf("checkEmergencyStop(%s) error = %v, want ErrEmergencyStop", name, err)
+		}
+	}
+
+	// disabling again replaces the allow list
+	if _, err := m.EmergencyDisableAll(nil, "", "admin"); err != nil {
+		t.Fatalf("EmergencyDisableAll() error = %v", err)
+	}
+	var n int64
+	db.Unscoped().Model(&model.EmergencyStop{}).Count(&n)
+	if n != 1 {
+		t.Errorf("%d emergency stops are stored, want 1", n)
+	}
+	if err := m.checkEmergencyStop("slack__post_message"); !errors.Is(err, ErrEmergencyStop) {
+		t.Errorf("checkEmergencyStop() error = %v, want ErrEmergencyStop", err)
+	}
+
+	if enabled, err := m.EmergencyEnableAll("admin"); err != nil || !enabled {
+		t.Fatalf("EmergencyEnableAll() = %v, %v, want true", enabled, err)
+	}
+	if err := m.checkEmergencyStop("github__search"); err != nil {
+		t.Errorf("checkEmergencyStop() error = %v after enabling tool calls", err)
+	}
+}
@@ -0,0 +1,11 @@
+package mcp
+
+import (
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+)
+
+// SetEventBus makes mcpjungle publish its events (server registrations, tool calls, policy denials and
+// changes in the health of servers) on the given bus, and lets the bus' guards reject tool calls.
+func (m *MCPService) SetEventBus(bus *events.Bus) {
+	m.eventBus = bus
+}
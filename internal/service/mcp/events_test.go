@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+)
+
+func TestEventBus(t *testing.T) {
+	m := newTestMCPService(t)
+	db := m.db
+	m.policyService = policy.NewPolicyService(db)
+	m.usageService = usage.NewUsageService(db, nil, nil)
+
+	bus := events.NewBus()
+	var received []events.Event
+	bus.Subscribe(func(ctx context.Context, e events.Event) {
+		received = append(received, e)
+	}, events.ServerRegistered, events.ToolInvoked)
+	bus.Subscribe(func(ctx context.Context, e events.Event) {
+		panic("a faulty plugin must not break tool calls")
+	})
+	bus.AddGuard(func(ctx context.Context, e events.Event) error {
+		if e.Args["env"] == "prod" {
+			return errors.New("deployments to prod are frozen")
+		}
+		return nil
+	})
+	m.SetEventBus(bus)
+
+	upstreamServer := server.NewMCPServer("ci", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(
+		mcp.NewTool("deploy", mcp.WithDescription("Deploys a branch")),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("deployed"), nil
+		},
+	)
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("ci", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	_, err = m.InvokeTool(context.Background(), "ci__deploy", map[string]any{"env": "prod"})
+	if !errors.Is(err, events.ErrRejected) {
+		t.Fatalf("InvokeTool() error = %v, want the call to be rejected by the guard", err)
+	}
+	if _, err := m.InvokeTool(context.Background(), "ci__deploy", map[string]any{"env": "staging"}); err != nil {
+		t.Fatalf("InvokeTool() error = %v", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("received %d events, want 2: %+v", len(received), received)
+	}
+	if e := received[0]; e.Type != events.ServerRegistered || e.Server != "ci" {
+		t.Errorf("first event = %+v, want ci to be registered", e)
+	}
+	if e := received[1]; e.Type != events.ToolInvoked || e.Tool != "ci__deploy" || e.Server != "ci" || e.IsError {
+		t.Errorf("second event = %+v, want a successful call to ci__deploy", e)
+	}
+}
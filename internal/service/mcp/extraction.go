@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// CreateExtractionProfile creates a new extraction profile.
+// All of its JSONPath expressions must be valid.
+func (m *MCPService) CreateExtractionProfile(p *model.ExtractionProfile) error {
+	for tool, expr := range p.GetRules() {
+		if _, err := compileJSONPath(expr); err != nil {
+			return fmt.Errorf("invalid rule for tool %s: %w", tool, err)
+		}
+	}
+	if err := m.db.Create(p).Error; err != nil {
+		return fmt.Errorf("failed to create extraction profile: %w", err)
+	}
+	return nil
+}
+
+// ListExtractionProfiles returns all extraction profiles.
+func (m *MCPService) ListExtractionProfiles() ([]*model.ExtractionProfile, error) {
+	var profiles []*model.ExtractionProfile
+	if err := m.db.Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// GetExtractionProfile fetches an extraction profile by name.
+func (m *MCPService) GetExtractionProfile(name string) (*model.ExtractionProfile, error) {
+	var p model.ExtractionProfile
+	if err := m.db.Where("name = ?", name).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeleteExtractionProfile deletes an extraction profile.
+// A profile cannot be deleted while it is attached to a tool group.
+func (m *MCPService) DeleteExtractionProfile(name string) error {
+	var count int64
+	if err := m.db.Model(&model.ToolGroup{}).Where("extraction_profile = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("extraction profile %s is used by %d tool group(s)", name, count)
+	}
+	return m.db.Unscoped().Where("name = ?", name).Delete(&model.ExtractionProfile{}).Error
+}
+
+// applyExtraction replaces every JSON text content of a tool result with the parts of it selected by
+// the JSONPath expression. If the expression selects a single node, it is returned as-is,
+// otherwise the selected nodes are returned as an array.
+// Text content that is not JSON is left unchanged.
+func applyExtraction(expr string, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+	path, err := compileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range result.Content {
+		text, ok := c.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var doc any
+		if err := json.Unmarshal([]byte(text.Text), &doc); err != nil {
+			continue
+		}
+
+		var extracted any
+		nodes := path.eval(doc)
+		if len(nodes) == 1 {
+			extracted = nodes[0]
+		} else {
+			extracted = nodes
+			if nodes == nil {
+				extracted = []any{}
+			}
+		}
+		b, err := json.Marshal(extracted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extracted result: %w", err)
+		}
+		text.Text = string(b)
+		result.Content[i] = text
+		// the structured content (if any) no longer matches the slimmed down result
+		result.StructuredContent = nil
+	}
+	return result, nil
+}
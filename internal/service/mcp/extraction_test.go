@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestApplyExtraction(t *testing.T) {
+	doc := `{"items": [{"title": "a", "meta": {"id": 1}}, {"title": "b", "meta": {"id": 2}}], "total": 2}`
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"$.items[*].title", `["a","b"]`},
+		{"$.items[-1].title", `"b"`},
+		{"$['total']", `2`},
+		{"$..id", `[1,2]`},
+		{"$.missing", `[]`},
+	}
+	for _, tt := range tests {
+		res := &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent(doc), mcp.NewTextContent("not json")}}
+		got, err := applyExtraction(tt.expr, res)
+		if err != nil {
+			t.Fatalf("applyExtraction(%s) error = %v", tt.expr, err)
+		}
+		if text := got.Content[0].(mcp.TextContent).Text; text != tt.want {
+			t.Errorf("applyExtraction(%s) = %s, want %s", tt.expr, text, tt.want)
+		}
+		if text := got.Content[1].(mcp.TextContent).Text; text != "not json" {
+			t.Errorf("applyExtraction(%s) modified non-JSON content: %s", tt.expr, text)
+		}
+	}
+}
+
+func TestCompileJSONPathRejectsUnsupportedSyntax(t *testing.T) {
+	for _, expr := range []string{"items", "$.items[?(@.id > 1)]", "$.items[0:2]", "$.items[0"} {
+		if _, err := compileJSONPath(expr); err == nil {
+			t.Errorf("compileJSONPath(%s) must fail", expr)
+		}
+	}
+}
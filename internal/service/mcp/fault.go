@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+)
+
+// errFaultDropped is the error of a call whose response was dropped by a fault rule.
+// It wraps context.DeadlineExceeded so that the call is treated as timed out.
+var errFaultDropped = fmt.Errorf("%w: response dropped by fault injection", context.DeadlineExceeded)
+
+// CreateFaultRule creates a new fault injection rule.
+func (m *MCPService) CreateFaultRule(f *model.FaultRule) error {
+	if f.Name == "" {
+		return errors.New("fault rule name is required")
+	}
+	return m.db.Create(f).Error
+}
+
+// ListFaultRules returns all fault injection rules.
+func (m *MCPService) ListFaultRules() ([]*model.FaultRule, error) {
+	var rules []*model.FaultRule
+	if err := m.db.Order("name").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// DeleteFaultRule deletes a fault injection rule by name.
+// It is an idempotent operation. Deleting a rule that does not exist will not return an error.
+func (m *MCPService) DeleteFaultRule(name string) error {
+	return m.db.Unscoped().Where("name = ?", name).Delete(&model.FaultRule{}).Error
+}
+
+// pickFault returns the fault rule to apply to an attempt at calling a tool of server s, if any.
+// toolName is the name of the tool without the server prefix.
+// Every matching rule is rolled against its rate, the first one that hits is returned.
+func (m *MCPService) pickFault(s *model.McpServer, toolName string) *model.FaultRule {
+	if !m.features.Enabled(feature.FaultInjection) {
+		return nil
+	}
+	var rules []*model.FaultRule
+	err := m.db.Where("tool_name = ? OR server_name = ?", mergeServerToolNames(s.Name, toolName), s.Name).
+		Order("name").
+		Find(&rules).Error
+	if err != nil {
+		log.Printf("[ERROR] failed to get fault rules for MCP server %s: %v", s.Name, err)
+		return nil
+	}
+	for _, r := range rules {
+		if rand.Float64()*100 < r.Rate {
+			log.Printf("[INFO] injecting %s fault into call to tool %s of MCP server %s (rule %s)",
+				r.Fault, toolName, s.Name, r.Name)
+			return r
+		}
+	}
+	return nil
+}
+
+// injectFault applies a fault that takes effect before the call is sent to the upstream server.
+// If it returns a non-nil result or error, the call must not be sent and they are its outcome.
+func injectFault(ctx context.Context, f *model.FaultRule) (*mcp.CallToolResult, error) {
+	switch f.Fault {
+	case model.FaultLatency:
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(f.LatencyMs) * time.Millisecond):
+		}
+	case model.FaultError:
+		return mcp.NewToolResultError(fmt.Sprintf("error injected by fault rule %s", f.Name)), nil
+	}
+	return nil, nil
+}
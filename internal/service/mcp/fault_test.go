@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+)
+
+func TestCallToolOnceInjectsFaults(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{}, &model.FaultRule{}, &model.FeatureFlag{})
+	db := m.db
+	features, err := feature.NewFeatureService(db, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.features = features
+
+	var calls atomic.Int32
+	upstreamServer := server.NewMCPServer("chaos", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return mcp.NewToolResultText("ok"), nil
+	})
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("chaos", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	call := func() (*mcp.CallToolResult, error) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "echo"
+		resp, _, _, err := m.callToolOnce(context.Background(), s, req)
+		return resp, err
+	}
+
+	rule := &model.FaultRule{Name: "errors", ServerName: "chaos", Fault: model.FaultError, Rate: 100}
+	if err := m.CreateFaultRule(rule); err != nil {
+		t.Fatalf("CreateFaultRule() error = %v", err)
+	}
+	// rules have no effect until fault injection is enabled
+	if resp, err := call(); err != nil || resp.IsError || calls.Load() != 1 {
+		t.Fatalf("call with fault injection off: resp = %v, err = %v, calls = %d", resp, err, calls.Load())
+	}
+
+	if err := features.SetFeature(feature.FaultInjection, true); err != nil {
+		t.Fatalf("SetFeature() error = %v", err)
+	}
+	if resp, err := call(); err != nil || !resp.IsError || calls.Load() != 1 {
+		t.Errorf("call with error fault: resp = %v, err = %v, calls = %d, want tool error without calling", resp, err, calls.Load())
+	}
+
+	if err := m.DeleteFaultRule("errors"); err != nil {
+		t.Fatalf("DeleteFaultRule() error = %v", err)
+	}
+	rule = &model.FaultRule{Name: "drops", ToolName: "chaos__echo", Fault: model.FaultDrop, Rate: 100}
+	if err := m.CreateFaultRule(rule); err != nil {
+		t.Fatalf("CreateFaultRule() error = %v", err)
+	}
+	// a dropped response looks like a timeout although the tool was called
+	if _, err := call(); !errors.Is(err, context.DeadlineExceeded) || calls.Load() != 2 {
+		t.Errorf("call with drop fault: err = %v, calls = %d, want timeout after calling", err, calls.Load())
+	}
+}
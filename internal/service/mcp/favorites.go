@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StarTool adds a tool to the favorites of a user. Starring a tool twice is not an error.
+func (m *MCPService) StarTool(username, name string) error {
+	if _, err := m.GetTool(name); err != nil {
+		return fmt.Errorf("tool %s not found: %w", name, err)
+	}
+	f := &model.ToolFavorite{Username: username, Tool: name}
+	return m.db.Clauses(clause.OnConflict{DoNothing: true}).Create(f).Error
+}
+
+// UnstarTool removes a tool from the favorites of a user. Unstarring a tool that isn't starred is not an error.
+func (m *MCPService) UnstarTool(username, name string) error {
+	return m.db.Unscoped().Where("username = ? AND tool = ?", username, name).Delete(&model.ToolFavorite{}).Error
+}
+
+// ListFavoriteTools returns the canonical names of the tools starred by a user.
+func (m *MCPService) ListFavoriteTools(username string) ([]string, error) {
+	var tools []string
+	err := m.db.Model(&model.ToolFavorite{}).Where("username = ?", username).Order("tool").Pluck("tool", &tools).Error
+	if err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
+
+// SetToolView creates a personal view of a user, or replaces it if the user already has a view with this name.
+func (m *MCPService) SetToolView(username, name string, tools []string) (*model.ToolView, error) {
+	if name == "" || name == types.ToolViewFavorites || name == types.ToolViewFavoritesFirst {
+		return nil, fmt.Errorf("invalid view name '%s'", name)
+	}
+	if len(tools) == 0 {
+		return nil, errors.New("a view must contain at least one tool or MCP server")
+	}
+	encoded, err := json.Marshal(tools)
+	if err != nil {
+		return nil, err
+	}
+	v := &model.ToolView{Username: username, Name: name, Tools: encoded}
+	err = m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tools", "updated_at"}),
+	}).Create(v).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save view %s: %w", name, err)
+	}
+	return v, nil
+}
+
+// ListToolViews returns the personal views of a user.
+func (m *MCPService) ListToolViews(username string) ([]*model.ToolView, error) {
+	var views []*model.ToolView
+	if err := m.db.Where("username = ?", username).Order("name").Find(&views).Error; err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// DeleteToolView deletes a personal view of a user.
+func (m *MCPService) DeleteToolView(username, name string) error {
+	res := m.db.Unscoped().Where("username = ? AND name = ?", username, name).Delete(&model.ToolView{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("view %s not found", name)
+	}
+	return nil
+}
+
+// ListToolsInView returns the tools in the registry as seen by a user through a view:
+// types.ToolViewFavorites, types.ToolViewFavoritesFirst or the name of one of the user's personal views.
+func (m *MCPService) ListToolsInView(username, view string) ([]model.Tool, error) {
+	tools, err := m.ListTools()
+	if err != nil {
+		return nil, err
+	}
+	sel, err := m.resolveToolView(username, view)
+	if err != nil {
+		return nil, err
+	}
+	return selectTools(sel, tools, func(t model.Tool) string { return t.Name }), nil
+}
+
+// ArrangeProxyTools is a tool filter of the MCP proxy server. If the session selected a view of a user
+// (see types.ToolViewHeader), the tools listed by tools/list are the ones in that view.
+// Selecting a view never grants access to more tools.
+// The tools of the stdio servers being restarted after a crash are not listed.
+func (m *MCPService) ArrangeProxyTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	tools = m.withoutRestartingServers(tools)
+	username, _ := ctx.Value("tool_view_user").(string)
+	view, _ := ctx.Value("tool_view").(string)
+	if username == "" || view == "" {
+		return tools
+	}
+	sel, err := m.resolveToolView(username, view)
+	if err != nil {
+		log.Printf("[WARN] failed to apply tool view %s of user %s: %v", view, username, err)
+		return tools
+	}
+	return selectTools(sel, tools, func(t mcp.Tool) string { return t.Name })
+}
+
+// toolViewSelection describes which tools a view contains and in which order
+type toolViewSelection struct {
+	favorites map[string]bool
+	// onlyFavorites keeps only the favorite tools
+	onlyFavorites bool
+	// entries are the tools & servers of a personal view, nil for the favorites views
+	entries []string
+}
+
+func (m *MCPService) resolveToolView(username, view string) (*toolViewSelection, error) {
+	favorites, err := m.ListFavoriteTools(username)
+	if err != nil {
+		return nil, err
+	}
+	sel := &toolViewSelection{favorites: make(map[string]bool, len(favorites))}
+	for _, f := range favorites {
+		sel.favorites[f] = true
+	}
+	switch view {
+	case types.ToolViewFavorites:
+		sel.onlyFavorites = true
+	case types.ToolViewFavoritesFirst:
+	default:
+		var v model.ToolView
+		err := m.db.Where("username = ? AND name = ?", username, view).First(&v).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("view %s not found", view)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sel.entries = v.GetTools()
+	}
+	return sel, nil
+}
+
+// selectTools returns the tools in the selection, favorites first. The order is otherwise preserved.
+func selectTools[T any](sel *toolViewSelection, tools []T, name func(T) string) []T {
+	selected := make([]T, 0, len(tools))
+	for _, t := range tools {
+		n := name(t)
+		if sel.onlyFavorites && !sel.favorites[n] {
+			continue
+		}
+		if sel.entries != nil && !inToolView(sel.entries, n) {
+			continue
+		}
+		selected = append(selected, t)
+	}
+	slices.SortStableFunc(selected, func(a, b T) int {
+		fa, fb := sel.favorites[name(a)], sel.favorites[name(b)]
+		switch {
+		case fa == fb:
+			return 0
+		case fa:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return selected
+}
+
+// inToolView returns true if the tool with the given canonical name is in a view, by name or through its server.
+func inToolView(entries []string, name string) bool {
+	serverName, _, _ := strings.Cut(name, serverToolNameSep)
+	return slices.Contains(entries, name) || slices.Contains(entries, serverName)
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestToolViews(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{}, &model.ToolFavorite{}, &model.ToolView{})
+	db := m.db
+	for server, tools := range map[string][]string{"github": {"issues", "search"}, "slack": {"post"}} {
+		s := &model.McpServer{Name: server, Transport: types.TransportStreamableHTTP, Config: []byte(`{}`)}
+		db.Create(s)
+		for _, name := range tools {
+			db.Create(&model.Tool{Name: name, ServerID: s.ID, Enabled: true, InputSchema: []byte(`{}`)})
+		}
+	}
+
+	if err := m.StarTool("alice", "slack__post"); err != nil {
+		t.Fatalf("StarTool() error = %v", err)
+	}
+	if err := m.StarTool("alice", "slack__post"); err != nil {
+		t.Errorf("StarTool() error = %v for a starred tool", err)
+	}
+	if err := m.StarTool("alice", "slack__missing"); err == nil {
+		t.Error("StarTool() succeeded for a tool that doesn't exist")
+	}
+	if err := m.StarTool("bob", "github__issues"); err != nil {
+		t.Fatalf("StarTool() error = %v", err)
+	}
+
+	names := func(tools []model.Tool) []string {
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	tests := []struct {
+		view string
+		want []string
+	}{
+		{types.ToolViewFavorites, []string{"slack__post"}},
+		{types.ToolViewFavoritesFirst, []string{"slack__post", "github__issues", "github__search"}},
+		{"triage", []string{"slack__post", "github__search"}},
+	}
+	if _, err := m.SetToolView("alice", "triage", []string{"github__search", "slack"}); err != nil {
+		t.Fatalf("SetToolView() error = %v", err)
+	}
+	if _, err := m.SetToolView("alice", types.ToolViewFavorites, []string{"slack"}); err == nil {
+		t.Error("SetToolView() accepted a reserved name")
+	}
+	for _, tt := range tests {
+		tools, err := m.ListToolsInView("alice", tt.view)
+		if err != nil {
+			t.Fatalf("ListToolsInView(%s) error = %v", tt.view, err)
+		}
+		if got := names(tools); !slices.Equal(got, tt.want) {
+			t.Errorf("ListToolsInView(%s) = %v, want %v", tt.view, got, tt.want)
+		}
+	}
+	if _, err := m.ListToolsInView("bob", "triage"); err == nil {
+		t.Error("ListToolsInView() succeeded with the view of another user")
+	}
+
+	ctx := context.WithValue(context.Background(), "tool_view_user", "alice")
+	ctx = context.WithValue(ctx, "tool_view", types.ToolViewFavorites)
+	proxied := m.ArrangeProxyTools(ctx, []mcp.Tool{mcp.NewTool("github__issues"), mcp.NewTool("slack__post")})
+	if len(proxied) != 1 || proxied[0].Name != "slack__post" {
+		t.Errorf("ArrangeProxyTools() = %v, want the favorites of the user", proxied)
+	}
+	if all := m.ArrangeProxyTools(context.Background(), proxied); len(all) != 1 {
+		t.Errorf("ArrangeProxyTools() changed the tools of a session without a view")
+	}
+
+	if err := m.UnstarTool("alice", "slack__post"); err != nil {
+		t.Fatalf("UnstarTool() error = %v", err)
+	}
+	if favorites, _ := m.ListFavoriteTools("alice"); len(favorites) != 0 {
+		t.Errorf("ListFavoriteTools() = %v after unstarring", favorites)
+	}
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// serverHealthRecords holds the health of the MCP servers by name.
+type serverHealthRecords struct {
+	mu      sync.Mutex
+	servers map[string]*types.ServerHealth
+}
+
+// get returns a copy of the health record of a server, or nil if there is none.
+func (r *serverHealthRecords) get(name string) *types.ServerHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.servers[name]
+	if !ok {
+		return nil
+	}
+	c := *h
+	return &c
+}
+
+func (r *serverHealthRecords) delete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.servers, name)
+}
+
+// record updates the health of a server. It returns true if the server became healthy or unhealthy.
+// Servers are assumed to be healthy until mcpjungle fails to connect to them.
+func (r *serverHealthRecords) record(name string, healthy bool, latency time.Duration, err error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.servers == nil {
+		r.servers = make(map[string]*types.ServerHealth)
+	}
+	h, ok := r.servers[name]
+	if !ok {
+		h = &types.ServerHealth{Name: name}
+		r.servers[name] = h
+	}
+	wasHealthy := h.Status != types.ServerUnhealthy
+
+	now := time.Now()
+	h.LastCheck = &now
+	if latency > 0 {
+		h.LatencyMs = float64(latency.Microseconds()) / 1000
+	}
+	if healthy {
+		h.Status = types.ServerHealthy
+		h.LastSuccess = &now
+		h.ConsecutiveFailures = 0
+		h.LastError = ""
+	} else {
+		h.Status = types.ServerUnhealthy
+		h.ConsecutiveFailures++
+		if err != nil {
+			h.LastError = err.Error()
+		}
+	}
+	return wasHealthy != healthy
+}
+
+// setServerHealth records whether mcpjungle can connect to an MCP server, and the latency of the connection if known.
+// A ServerHealthChanged event is published if the health of the server changed.
+func (m *MCPService) setServerHealth(name string, healthy bool, latency time.Duration, err error) {
+	if !m.health.record(name, healthy, latency, err) {
+		return
+	}
+	e := events.Event{Type: events.ServerHealthChanged, Server: name, Healthy: healthy}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	m.eventBus.Publish(context.Background(), e)
+}
+
+// UnhealthyServers returns the MCP servers that mcpjungle last failed to connect to, along with the error.
+func (m *MCPService) UnhealthyServers() map[string]string {
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	unhealthy := make(map[string]string)
+	for name, h := range m.health.servers {
+		if h.Status == types.ServerUnhealthy {
+			unhealthy[name] = h.LastError
+		}
+	}
+	return unhealthy
+}
+
+// GetServerHealth returns the health of an MCP server.
+// If check is true, mcpjungle connects to the server (or pings its warm connection) to check its health first.
+func (m *MCPService) GetServerHealth(ctx context.Context, name string, check bool) (*types.ServerHealth, error) {
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, err
+	}
+	if check && !s.Stopped {
+		m.checkServerHealth(ctx, s)
+	}
+	return m.serverHealth(s), nil
+}
+
+// GetServersHealth returns the health of all the MCP servers, along with their aggregate health.
+func (m *MCPService) GetServersHealth() (*types.ServersHealth, error) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	res := &types.ServersHealth{Servers: make([]*types.ServerHealth, 0, len(servers))}
+	for i := range servers {
+		h := m.serverHealth(&servers[i])
+		switch h.Status {
+		case types.ServerHealthy:
+			res.Healthy++
+		case types.ServerUnhealthy:
+			res.Unhealthy++
+		case types.ServerHealthStopped:
+			res.Stopped++
+		default:
+			res.Unknown++
+		}
+		res.Servers = append(res.Servers, h)
+	}
+	switch {
+	case res.Unhealthy == 0:
+		res.Status = types.ServerHealthy
+	case res.Healthy == 0:
+		res.Status = types.ServerUnhealthy
+	default:
+		res.Status = types.ServersDegraded
+	}
+	return res, nil
+}
+
+func (m *MCPService) serverHealth(s *model.McpServer) *types.ServerHealth {
+	h := m.health.get(s.Name)
+	if h == nil {
+		h = &types.ServerHealth{Name: s.Name, Status: types.ServerHealthUnknown}
+	}
+	if s.Stopped {
+		h.Status = types.ServerHealthStopped
+	}
+	return h
+}
+
+// checkServerHealth connects to an MCP server and pings it, recording its health.
+// Admins are not notified of failed checks, external monitors requesting them are expected to alert.
+func (m *MCPService) checkServerHealth(ctx context.Context, s *model.McpServer) {
+	c, release, err := m.upstreamSession(ctx, s)
+	if err != nil {
+		m.setServerHealth(s.Name, false, 0, err)
+		return
+	}
+	defer release()
+	start := time.Now()
+	if err := c.Ping(ctx); err != nil {
+		m.setServerHealth(s.Name, false, 0, fmt.Errorf("ping failed: %w", err))
+		return
+	}
+	m.setServerHealth(s.Name, true, time.Since(start), nil)
+}
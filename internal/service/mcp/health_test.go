@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestServerHealth(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{})
+	db := m.db
+	for _, name := range []string{"github", "slack", "jira", "billing"} {
+		s, err := model.NewStreamableHTTPServer(name, "", "http://127.0.0.1:1/mcp", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Stopped = name == "billing"
+		if err := db.Create(s).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m.setServerHealth("github", true, 20*time.Millisecond, nil)
+	m.setServerHealth("slack", false, 0, errors.New("connection refused"))
+	m.setServerHealth("slack", false, 0, errors.New("connection refused"))
+
+	h, err := m.GetServerHealth(context.Background(), "slack", false)
+	if err != nil {
+		t.Fatalf("GetServerHealth() error = %v", err)
+	}
+	if h.Status != types.ServerUnhealthy || h.ConsecutiveFailures != 2 || h.LastError != "connection refused" ||
+		h.LastCheck == nil || h.LastSuccess != nil {
+		t.Errorf("GetServerHealth() of an unreachable server = %+v", h)
+	}
+	if h, _ := m.GetServerHealth(context.Background(), "github", false); h.Status != types.ServerHealthy || h.LatencyMs != 20 {
+		t.Errorf("GetServerHealth() of a healthy server = %+v, want healthy with 20ms latency", h)
+	}
+	if h, _ := m.GetServerHealth(context.Background(), "jira", false); h.Status != types.ServerHealthUnknown {
+		t.Errorf("GetServerHealth() of a server never connected to = %+v, want unknown", h)
+	}
+	// a stopped server is not checked
+	if h, _ := m.GetServerHealth(context.Background(), "billing", true); h.Status != types.ServerHealthStopped || h.LastCheck != nil {
+		t.Errorf("GetServerHealth() of a stopped server = %+v, want stopped", h)
+	}
+
+	all, err := m.GetServersHealth()
+	if err != nil {
+		t.Fatalf("GetServersHealth() error = %v", err)
+	}
+	if all.Status != types.ServersDegraded || all.Healthy != 1 || all.Unhealthy != 1 || all.Unknown != 1 || all.Stopped != 1 {
+		t.Errorf("GetServersHealth() = %+v, want degraded with 1 server of each status", all)
+	}
+
+	// a successful connection resets the failures, the latency of the last measurement is kept
+	m.setServerHealth("slack", true, 0, nil)
+	m.setServerHealth("github", false, 0, errors.New("timeout"))
+	h, _ = m.GetServerHealth(context.Background(), "slack", false)
+	if h.Status != types.ServerHealthy || h.ConsecutiveFailures != 0 || h.LastError != "" || h.LastSuccess == nil {
+		t.Errorf("GetServerHealth() of a recovered server = %+v", h)
+	}
+	if unhealthy := m.UnhealthyServers(); len(unhealthy) != 1 || unhealthy["github"] != "timeout" {
+		t.Errorf("UnhealthyServers() = %v, want github only", unhealthy)
+	}
+}
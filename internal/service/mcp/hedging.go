@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"log"
+	"time"
+)
+
+// SetToolHedging configures hedged requests for a tool. A zero delay disables hedging.
+// Hedging only takes effect for idempotent tools that are provided by more than one MCP server.
+// If version is not 0, the change is rejected with a *types.VersionConflict if the tool is at another version.
+func (m *MCPService) SetToolHedging(name string, delay time.Duration, version int) error {
+	if delay < 0 {
+		return fmt.Errorf("hedging delay must not be negative")
+	}
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return err
+	}
+	if delay > 0 && !toolIsIdempotent(tool) {
+		return fmt.Errorf("tool %s is not annotated as idempotent or read-only, it cannot be hedged", name)
+	}
+	err = m.updateTool(tool, name, version, map[string]any{"hedge_delay_ms": delay.Milliseconds()})
+	if err != nil {
+		return fmt.Errorf("failed to set hedging delay of tool %s: %w", name, err)
+	}
+	return nil
+}
+
+// hedgeOutcome is the result of one of the requests made for a hedged tool call
+type hedgeOutcome struct {
+	resp     *mcp.CallToolResult
+	attempts int
+	err      error
+	hedge    bool
+}
+
+// callTool calls a tool on an MCP server.
+// If hedging is enabled for the tool and the call takes longer than the hedging delay, a second request is sent
+// to an equivalent server that the caller may use, and the first successful response is returned.
+// It returns the total number of attempts made across all servers.
+func (m *MCPService) callTool(
+	ctx context.Context, s *model.McpServer, name string, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, int, error) {
+	replica, delay := m.hedgeTarget(ctx, s, name)
+	if replica == nil {
+		return m.callUpstreamTool(ctx, s, name, request)
+	}
+
+	// the request that loses the race is cancelled once the other one succeeds
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeOutcome, 2)
+	send := func(target *model.McpServer, hedge bool) {
+		resp, attempts, err := m.callUpstreamTool(ctx, target, name, request)
+		results <- hedgeOutcome{resp: resp, attempts: attempts, err: err, hedge: hedge}
+	}
+	go send(s, false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	hedged, pending, attempts := false, 1, 0
+	var last hedgeOutcome
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			log.Printf("[INFO] call to tool %s on MCP server %s is taking longer than %s, sending hedged request to %s",
+				name, s.Name, delay, replica.Name)
+			hedged = true
+			pending++
+			go send(replica, true)
+
+		case o := <-results:
+			pending--
+			attempts += o.attempts
+			last = o
+			if !hedged {
+				// the call completed before the hedging delay
+				return o.resp, attempts, o.err
+			}
+			if o.err == nil {
+				winner := "primary"
+				if o.hedge {
+					winner = "hedge"
+				}
+				metrics.UpstreamHedgedCalls.WithLabelValues(name, winner).Inc()
+				return o.resp, attempts, nil
+			}
+		}
+	}
+	metrics.UpstreamHedgedCalls.WithLabelValues(name, "none").Inc()
+	return last.resp, attempts, last.err
+}
+
+// hedgeTarget returns the server to send a hedged request for a call of the tool to, along with the hedging delay.
+// It returns nil if the call must not be hedged.
+func (m *MCPService) hedgeTarget(ctx context.Context, s *model.McpServer, name string) (*model.McpServer, time.Duration) {
+	if !m.features.Enabled(feature.Hedging) {
+		return nil, 0
+	}
+	tool, err := m.GetTool(name)
+	if err != nil || tool.HedgeDelayMs <= 0 || !toolIsIdempotent(tool) {
+		return nil, 0
+	}
+	_, toolName, _ := splitServerToolName(name)
+	candidates, err := m.equivalentServers(s, toolName)
+	if err != nil {
+		log.Printf("[ERROR] failed to find servers to hedge calls to tool %s: %v", name, err)
+		return nil, 0
+	}
+	policies, err := m.callerResidencyPolicies(ctx)
+	if err != nil {
+		log.Printf("[ERROR] failed to find servers to hedge calls to tool %s: %v", name, err)
+		return nil, 0
+	}
+	c, _ := ctx.Value("client").(*model.McpClient)
+	for _, candidate := range candidates {
+		if !regionAllowed(policies, candidate.Region) {
+			continue
+		}
+		if c != nil && !c.CheckHasServerAccess(candidate.Name) {
+			continue
+		}
+		return candidate, time.Duration(tool.HedgeDelayMs) * time.Millisecond
+	}
+	return nil, 0
+}
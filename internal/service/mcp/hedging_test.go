@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestCallToolHedgesSlowCalls(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{}, &model.ResidencyPolicy{})
+
+	// registers a replica of the lookup tool that responds with its name after the given latency
+	register := func(name string, latency time.Duration) *model.McpServer {
+		t.Helper()
+		upstream := server.NewMCPServer(name, "0.0.1", server.WithToolCapabilities(true))
+		upstream.AddTool(
+			mcp.NewTool("lookup", mcp.WithReadOnlyHintAnnotation(true)),
+			func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				select {
+				case <-time.After(latency):
+				case <-ctx.Done():
+				}
+				return mcp.NewToolResultText(name), nil
+			},
+		)
+		ts := server.NewTestStreamableHTTPServer(upstream)
+		t.Cleanup(ts.Close)
+
+		s, err := model.NewStreamableHTTPServer(name, "", ts.URL+"/mcp", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+			t.Fatalf("RegisterMcpServer() error = %v", err)
+		}
+		return s
+	}
+	slow := register("slow", 2*time.Second)
+	register("fast", 0)
+
+	if err := m.SetToolHedging("slow__lookup", 50*time.Millisecond, 0); err != nil {
+		t.Fatalf("SetToolHedging() error = %v", err)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "lookup"
+	start := time.Now()
+	resp, attempts, err := m.callTool(context.Background(), slow, "slow__lookup", req)
+	if err != nil {
+		t.Fatalf("callTool() error = %v", err)
+	}
+	if text := resp.Content[0].(mcp.TextContent).Text; text != "fast" {
+		t.Errorf("callTool() returned the response of %s, want the hedged request's response", text)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("hedged call took %s", elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (the cancelled primary request is not counted)", attempts)
+	}
+}
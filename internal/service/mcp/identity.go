@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrServerIdentityChanged is returned when an MCP server whose identity is pinned in block mode
+// reports a different identity.
+var ErrServerIdentityChanged = errors.New("MCP server identity changed")
+
+// connectUpstream creates a new session with a registered MCP server to call its tools,
+// and checks the identity that the server reports against the pinned one.
+func (m *MCPService) connectUpstream(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+	if s.Stopped {
+		return nil, fmt.Errorf("MCP server %s belongs to group %s: %w", s.Name, s.Group, ErrServerStopped)
+	}
+	start := time.Now()
+	mcpClient, initResult, err := m.connectMcpServer(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	m.setServerHealth(s.Name, true, time.Since(start), nil)
+	if err := m.checkServerIdentity(s, initResult); err != nil {
+		_ = mcpClient.Close()
+		return nil, err
+	}
+	return mcpClient, nil
+}
+
+// checkServerIdentity compares the implementation name & version reported by a server during initialization
+// with the ones recorded at registration. If they differ and pinning is enabled, admins are notified and,
+// in block mode, an error wrapping ErrServerIdentityChanged is returned.
+// Servers whose identity was never recorded (eg- registered by an older version of mcpjungle) are not checked.
+func (m *MCPService) checkServerIdentity(s *model.McpServer, initResult *mcp.InitializeResult) error {
+	if !s.PinsIdentity() || initResult == nil {
+		return nil
+	}
+	pinned := s.GetServerInfo()
+	reported := initResult.ServerInfo
+	if pinned == nil || (pinned.Name == reported.Name && pinned.Version == reported.Version) {
+		return nil
+	}
+
+	metrics.UpstreamIdentityChanges.WithLabelValues(s.Name, string(s.IdentityPinning)).Inc()
+	msg := fmt.Sprintf(
+		"MCP server %s reported identity %s %s instead of the pinned %s %s",
+		s.Name, reported.Name, reported.Version, pinned.Name, pinned.Version,
+	)
+	log.Printf("[WARN] %s", msg)
+	if m.notifications != nil {
+		m.notifications.Notify(
+			types.NotificationServerIdentityChanged, s.Name,
+			fmt.Sprintf("%s, run 'mcpjungle pin-server %s' if the change is expected", msg, s.Name),
+		)
+	}
+	if s.IdentityPinning == types.IdentityPinningBlock {
+		return fmt.Errorf("%w: %s, the server is blocked until it is re-pinned", ErrServerIdentityChanged, msg)
+	}
+	return nil
+}
+
+// PinMcpServerIdentity connects to an MCP server and records the identity it reports as the pinned one,
+// along with its capabilities. If mode is not empty, it also changes the server's pinning mode.
+// This is the explicit action that accepts an expected change of identity, eg- after an upgrade of the server.
+func (m *MCPService) PinMcpServerIdentity(ctx context.Context, name string, mode string) (*model.McpServer, error) {
+	s, err := m.GetMcpServer(name)
+	if err != nil {
+		return nil, err
+	}
+	if mode != "" {
+		if s.IdentityPinning, err = types.ValidateIdentityPinning(mode); err != nil {
+			return nil, err
+		}
+	}
+
+	mcpClient, initResult, err := m.connectMcpServer(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	_ = mcpClient.Close()
+
+	recordServerCapabilities(s, initResult)
+	err = model.UpdateVersioned[model.McpServer](m.db, "server", s.Name, s.ID, s.Version, map[string]any{
+		"protocol_version": s.ProtocolVersion,
+		"capabilities":     s.Capabilities,
+		"server_info":      s.ServerInfo,
+		"identity_pinning": s.IdentityPinning,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin the identity of MCP server %s: %w", s.Name, err)
+	}
+	s.Version++
+	// a warm connection may have been established before the server changed, it's re-checked on reconnect
+	m.warm.discard(s.Name, nil)
+	return s, nil
+}
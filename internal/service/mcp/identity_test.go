@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestServerIdentityPinning(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+	db := m.db
+
+	newUpstream := func(version string) string {
+		s := server.NewMCPServer("github-mcp", version, server.WithToolCapabilities(true))
+		s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+		upstream := server.NewTestStreamableHTTPServer(s)
+		t.Cleanup(upstream.Close)
+		return upstream.URL + "/mcp"
+	}
+
+	s, err := model.NewStreamableHTTPServer("github", "", newUpstream("1.0.0"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.IdentityPinning = types.IdentityPinningBlock
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	if info := s.GetServerInfo(); info == nil || info.Name != "github-mcp" || info.Version != "1.0.0" {
+		t.Fatalf("server info recorded at registration = %+v", info)
+	}
+
+	// the server is swapped for another version behind the same name
+	swapped, _ := model.NewStreamableHTTPServer("github", "", newUpstream("2.0.0"), "")
+	db.Model(&model.McpServer{}).Where("name = ?", "github").Update("config", swapped.Config)
+
+	call := func() error {
+		s, err := m.GetMcpServer("github")
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "echo"
+		_, _, err = m.callUpstreamTool(context.Background(), s, "github__echo", req)
+		return err
+	}
+	if err := call(); !errors.Is(err, ErrServerIdentityChanged) {
+		t.Fatalf("call to a swapped server in block mode: error = %v, want ErrServerIdentityChanged", err)
+	}
+
+	db.Model(&model.McpServer{}).Where("name = ?", "github").Update("identity_pinning", types.IdentityPinningAlert)
+	if err := call(); err != nil {
+		t.Errorf("call to a swapped server in alert mode: error = %v", err)
+	}
+
+	pinned, err := m.PinMcpServerIdentity(context.Background(), "github", string(types.IdentityPinningBlock))
+	if err != nil {
+		t.Fatalf("PinMcpServerIdentity() error = %v", err)
+	}
+	if info := pinned.GetServerInfo(); info == nil || info.Version != "2.0.0" || pinned.IdentityPinning != types.IdentityPinningBlock {
+		t.Errorf("PinMcpServerIdentity() = %+v, %+v", pinned, info)
+	}
+	if err := call(); err != nil {
+		t.Errorf("call to a re-pinned server: error = %v", err)
+	}
+	if _, err := m.PinMcpServerIdentity(context.Background(), "github", "sometimes"); err == nil {
+		t.Error("PinMcpServerIdentity() with an invalid mode succeeded, want error")
+	}
+}
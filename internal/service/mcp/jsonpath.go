@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is a single step of a compiled JSONPath expression
+type jsonPathSegment struct {
+	// key is the name of the object member selected by this segment.
+	// It is empty if the segment selects an array index or a wildcard.
+	key string
+	// index is the array index selected by this segment. Negative indices count from the end of the array.
+	index int
+	// isIndex is true if this segment selects an array index
+	isIndex bool
+	// wildcard is true if this segment selects all members of an object or all elements of an array
+	wildcard bool
+	// recursive is true if this segment is applied to all descendants of the current nodes (..)
+	recursive bool
+}
+
+// jsonPath is a compiled JSONPath expression.
+// Only the commonly used subset of JSONPath is supported:
+// the root ($), child members (.name, ['name']), array indices ([0], [-1]),
+// wildcards (.*, [*]) and recursive descent (..name).
+// Filters, slices and unions are not supported.
+type jsonPath []jsonPathSegment
+
+// compileJSONPath parses a JSONPath expression.
+func compileJSONPath(expr string) (jsonPath, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath expression '%s': must start with $", expr)
+	}
+
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		var seg jsonPathSegment
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			seg.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				// eg- $..[0] is handled by the bracket case below
+				break
+			}
+			name, r := cutJSONPathName(rest)
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath expression '%s': missing member name after '..'", expr)
+			}
+			seg.key, seg.wildcard, rest = name, name == "*", r
+			path = append(path, seg)
+			continue
+		case strings.HasPrefix(rest, "."):
+			name, r := cutJSONPathName(rest[1:])
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath expression '%s': missing member name after '.'", expr)
+			}
+			seg.key, seg.wildcard, rest = name, name == "*", r
+			path = append(path, seg)
+			continue
+		}
+
+		if !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("invalid JSONPath expression '%s': unexpected '%s'", expr, rest)
+		}
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid JSONPath expression '%s': missing ']'", expr)
+		}
+		sel := strings.TrimSpace(rest[1:end])
+		rest = rest[end+1:]
+
+		switch {
+		case sel == "*":
+			seg.wildcard = true
+		case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+			seg.key = sel[1 : len(sel)-1]
+		default:
+			i, err := strconv.Atoi(sel)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath expression '%s': unsupported selector [%s]", expr, sel)
+			}
+			seg.index, seg.isIndex = i, true
+		}
+		path = append(path, seg)
+	}
+	return path, nil
+}
+
+// cutJSONPathName splits a dot-notation member name from the rest of the expression.
+func cutJSONPathName(s string) (string, string) {
+	i := strings.IndexAny(s, ".[")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+// eval returns all the nodes of a decoded JSON document selected by the path.
+func (p jsonPath) eval(doc any) []any {
+	nodes := []any{doc}
+	for _, seg := range p {
+		var next []any
+		for _, n := range nodes {
+			if seg.recursive {
+				for _, d := range jsonDescendants(n) {
+					next = append(next, seg.selectFrom(d)...)
+				}
+				continue
+			}
+			next = append(next, seg.selectFrom(n)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// selectFrom returns the children of a node selected by this segment.
+func (seg jsonPathSegment) selectFrom(n any) []any {
+	switch v := n.(type) {
+	case map[string]any:
+		if seg.wildcard {
+			out := make([]any, 0, len(v))
+			for _, k := range slices.Sorted(maps.Keys(v)) {
+				out = append(out, v[k])
+			}
+			return out
+		}
+		if c, ok := v[seg.key]; ok && !seg.isIndex {
+			return []any{c}
+		}
+	case []any:
+		if seg.wildcard {
+			return v
+		}
+		if seg.isIndex {
+			i := seg.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return []any{v[i]}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonDescendants returns a node along with all of its descendants.
+func jsonDescendants(n any) []any {
+	out := []any{n}
+	switch v := n.(type) {
+	case map[string]any:
+		// members are visited in a stable order so that results are deterministic
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			out = append(out, jsonDescendants(v[k])...)
+		}
+	case []any:
+		for _, c := range v {
+			out = append(out, jsonDescendants(c)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Tool lint rules
+const (
+	// LintRuleMissingDescription flags tools without a description
+	LintRuleMissingDescription = "missing_description"
+
+	// LintRuleShortDescription flags tools whose description is too short to tell agents what the tool does
+	LintRuleShortDescription = "short_description"
+
+	// LintRuleUntypedParameter flags tools with input parameters that don't declare their type
+	LintRuleUntypedParameter = "untyped_parameter"
+
+	// LintRuleNameCollision flags tools whose name (without the server prefix) is also used by another server,
+	// which makes it harder for agents to pick the right tool
+	LintRuleNameCollision = "name_collision"
+)
+
+// minToolDescriptionLength is the length below which a tool's description is considered too short
+const minToolDescriptionLength = 20
+
+// enforceableLintRules are the rules that can be enforced, ie, that depend on a tool's own definition
+var enforceableLintRules = []string{LintRuleMissingDescription, LintRuleShortDescription, LintRuleUntypedParameter}
+
+// EnforceToolLintRules stops the MCP proxy from exposing tools that fail any of the given lint rules.
+// Such tools remain in the registry and are exposed again once they pass the rules,
+// eg- after their description is improved with SetToolDocs.
+// The name_collision rule cannot be enforced because it depends on other tools.
+func (m *MCPService) EnforceToolLintRules(rules []string) error {
+	for _, r := range rules {
+		if !slices.Contains(enforceableLintRules, r) {
+			return fmt.Errorf(
+				"unknown or unenforceable lint rule '%s' (enforceable rules: %s)",
+				r, strings.Join(enforceableLintRules, ", "),
+			)
+		}
+	}
+	m.enforcedLintRules = rules
+
+	// re-add the enabled tools so that the ones failing the rules are removed from the proxies
+	tools, err := m.ListTools()
+	if err != nil {
+		return fmt.Errorf("failed to list tools from DB: %w", err)
+	}
+	for _, tm := range tools {
+		if !tm.Enabled {
+			continue
+		}
+		tool, err := convertToolModelToMcpObject(&tm)
+		if err != nil {
+			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tm.Name, err)
+		}
+		m.addProxyTool(tool)
+	}
+	return nil
+}
+
+// LintTools checks the definitions of all enabled tools against the lint rules and returns the issues found.
+// Descriptions are checked as served to MCP clients, ie, including the documentation added by admins.
+func (m *MCPService) LintTools() ([]types.ToolLintIssue, error) {
+	tools, err := m.ListTools()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools from DB: %w", err)
+	}
+
+	issues := make([]types.ToolLintIssue, 0)
+	servers := make(map[string][]string)
+	for _, tm := range tools {
+		if !tm.Enabled {
+			continue
+		}
+		tool, err := convertToolModelToMcpObject(&tm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tm.Name, err)
+		}
+		issues = append(issues, m.lintTool(tool)...)
+
+		serverName, toolName, _ := splitServerToolName(tm.Name)
+		key := strings.ToLower(toolName)
+		servers[key] = append(servers[key], serverName)
+	}
+
+	for _, tm := range tools {
+		if !tm.Enabled {
+			continue
+		}
+		serverName, toolName, _ := splitServerToolName(tm.Name)
+		others := slices.DeleteFunc(slices.Clone(servers[strings.ToLower(toolName)]), func(s string) bool {
+			return s == serverName
+		})
+		if len(others) > 0 {
+			issues = append(issues, types.ToolLintIssue{
+				Tool:    tm.Name,
+				Rule:    LintRuleNameCollision,
+				Message: fmt.Sprintf("tool name '%s' is also used by MCP servers: %s", toolName, strings.Join(others, ", ")),
+			})
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b types.ToolLintIssue) int {
+		return strings.Compare(a.Tool, b.Tool)
+	})
+	return issues, nil
+}
+
+// lintTool checks a tool against the rules that only depend on its own definition.
+func (m *MCPService) lintTool(tool mcp.Tool) []types.ToolLintIssue {
+	var issues []types.ToolLintIssue
+	add := func(rule, msg string) {
+		issues = append(issues, types.ToolLintIssue{
+			Tool:    tool.Name,
+			Rule:    rule,
+			Message: msg,
+			Blocked: slices.Contains(m.enforcedLintRules, rule),
+		})
+	}
+
+	description := strings.TrimSpace(tool.Description)
+	if description == "" {
+		add(LintRuleMissingDescription, "tool has no description")
+	} else if len(description) < minToolDescriptionLength {
+		add(
+			LintRuleShortDescription,
+			fmt.Sprintf("description is only %d characters long, at least %d are recommended",
+				len(description), minToolDescriptionLength),
+		)
+	}
+
+	params := make([]string, 0, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		params = append(params, name)
+	}
+	slices.Sort(params)
+	for _, name := range params {
+		if !parameterHasType(tool.InputSchema.Properties[name]) {
+			add(LintRuleUntypedParameter, fmt.Sprintf("parameter '%s' does not declare its type", name))
+		}
+	}
+	return issues
+}
+
+// parameterHasType returns true if the JSON schema of a parameter constrains its type in any way
+func parameterHasType(schema any) bool {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, k := range []string{"type", "enum", "const", "$ref", "anyOf", "oneOf", "allOf"} {
+		if _, ok := s[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lintBlocked returns true if the MCP proxy must not expose a tool because it fails an enforced lint rule.
+func (m *MCPService) lintBlocked(tool mcp.Tool) bool {
+	if len(m.enforcedLintRules) == 0 {
+		return false
+	}
+	for _, issue := range m.lintTool(tool) {
+		if issue.Blocked {
+			log.Printf("[WARN] tool %s is not exposed by the MCP proxy: %s (%s)", tool.Name, issue.Message, issue.Rule)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestLintTools(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	poor := mcp.NewTool("search", mcp.WithDescription("Searches"))
+	poor.InputSchema.Properties["q"] = map[string]any{"description": "the query"}
+	good := mcp.NewTool(
+		"search",
+		mcp.WithDescription("Searches the issue tracker for matching tickets"),
+		mcp.WithString("q", mcp.Description("the query")),
+	)
+
+	for name, tool := range map[string]mcp.Tool{"web": poor, "tracker": good} {
+		upstreamServer := server.NewMCPServer(name, "0.0.1", server.WithToolCapabilities(true))
+		upstreamServer.AddTool(tool, handler)
+		upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+		defer upstream.Close()
+
+		s, err := model.NewStreamableHTTPServer(name, "", upstream.URL+"/mcp", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+			t.Fatalf("RegisterMcpServer() error = %v", err)
+		}
+	}
+
+	if err := m.EnforceToolLintRules([]string{LintRuleShortDescription}); err != nil {
+		t.Fatalf("EnforceToolLintRules() error = %v", err)
+	}
+	if err := m.EnforceToolLintRules([]string{LintRuleNameCollision}); err == nil {
+		t.Error("EnforceToolLintRules() with name_collision should fail")
+	}
+
+	issues, err := m.LintTools()
+	if err != nil {
+		t.Fatalf("LintTools() error = %v", err)
+	}
+	want := []types.ToolLintIssue{
+		{Tool: "tracker__search", Rule: LintRuleNameCollision},
+		{Tool: "web__search", Rule: LintRuleShortDescription, Blocked: true},
+		{Tool: "web__search", Rule: LintRuleUntypedParameter},
+		{Tool: "web__search", Rule: LintRuleNameCollision},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("LintTools() = %+v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		if issues[i].Tool != w.Tool || issues[i].Rule != w.Rule || issues[i].Blocked != w.Blocked {
+			t.Errorf("issue %d = %+v, want %+v", i, issues[i], w)
+		}
+	}
+
+	// the tool failing the enforced rule must not be exposed by the proxy
+	c, err := client.NewInProcessClient(m.mcpProxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Tools) != 1 || res.Tools[0].Name != "tracker__search" {
+		t.Errorf("proxy tools = %+v, want only tracker__search", res.Tools)
+	}
+}
@@ -1,9 +1,23 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
+	"github.com/mcpjungle/mcpjungle/internal/service/authorizer"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/secret"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/translation"
 	"gorm.io/gorm"
+	"sync"
+	"time"
 )
 
 // MCPService coordinates operations amongst the registry database, mcp proxy server and upstream MCP servers.
@@ -11,14 +25,104 @@ import (
 type MCPService struct {
 	db             *gorm.DB
 	mcpProxyServer *server.MCPServer
+	usageService   *usage.UsageService
+	policyService  *policy.PolicyService
+	features       *feature.FeatureService
+	notifications  *notification.NotificationService
+
+	// proxyHooks are the hooks of the main MCP proxy server, they are shared with the proxies of tool groups
+	proxyHooks *server.Hooks
+
+	groupsMu   sync.RWMutex
+	toolGroups map[string]*toolGroupProxy
+
+	warm warmPool
+	// supervisor restarts the processes of the warm stdio servers that crash
+	supervisor stdioSupervisor
+	// shutdown is done when mcpjungle shuts down, it ends the restart loops of the supervisor. nil means never.
+	shutdown context.Context
+
+	toolCatalog toolCatalog
+
+	// enforcedLintRules are the lint rules that tools must pass to be exposed by the MCP proxy
+	enforcedLintRules []string
+
+	// resultSigner signs the results of tool calls, it is nil if result signing is not enabled
+	resultSigner *resultSigner
+
+	audit *audit.AuditService
+
+	// eventBus receives the events of the gateway, it is nil if no plugins are subscribed to them
+	eventBus *events.Bus
+
+	// wasmPlugins run in the pipeline of every tool call, it is nil if no WASM plugins are loaded
+	wasmPlugins wasm.Plugins
+
+	// authorizer is the external endpoint that authorizes every tool call, it is nil if there is none
+	authorizer *authorizer.Authorizer
+
+	// sessions tracks the protocol versions of the proxy's sessions, it is nil if no session manager is set
+	sessions *SessionManager
+
+	// translator translates the results of tool calls for MCP clients that prefer another language,
+	// it is nil if translation is disabled
+	translator translation.Provider
+
+	// health records the health of the MCP servers that mcpjungle connected to since it started
+	health serverHealthRecords
+
+	// missingTools contains the canonical names of the tools that their MCP servers no longer provide,
+	// so that the schema drift check reports them only once
+	missingTools sync.Map
+
+	// scripts caches the compiled tool scripts, keyed by their kind and source
+	scripts sync.Map
+
+	// budgetReservations holds the budget reservations of the tool calls in flight, keyed by their usage records,
+	// see beginToolCall
+	budgetReservations sync.Map
+
+	// secrets resolves the references to secrets in the configuration of MCP servers, it is nil if there is no secret store
+	secrets *secret.SecretService
+
+	// deadlineOverhead is reserved for the gateway out of the deadline of a tool call, see withCallBudget
+	deadlineOverhead time.Duration
+
+	// calls tracks the tool calls in flight to every MCP server, to drain them before a server is deregistered
+	calls callDrainer
+	// drainTimeout is how long deregistering an MCP server waits for its in-flight tool calls
+	drainTimeout time.Duration
+
+	// regionNetworks locate the callers of tools by the IP address they connect from, most specific networks first,
+	// see SetRegionNetworks
+	regionNetworks []RegionNetwork
+
+	// spiffe provides the SVID that authenticates mcpjungle to the MCP servers registered with a SPIFFE ID,
+	// it is nil if mcpjungle has no SPIFFE identity
+	spiffe *spiffe.Source
 }
 
 // NewMCPService creates a new instance of MCPService.
 // It initializes the MCP proxy server by loading all registered tools from the database.
-func NewMCPService(db *gorm.DB, mcpProxyServer *server.MCPServer) (*MCPService, error) {
+func NewMCPService(
+	db *gorm.DB,
+	mcpProxyServer *server.MCPServer,
+	proxyHooks *server.Hooks,
+	usageService *usage.UsageService,
+	policyService *policy.PolicyService,
+	features *feature.FeatureService,
+	notifications *notification.NotificationService,
+) (*MCPService, error) {
 	s := &MCPService{
 		db:             db,
 		mcpProxyServer: mcpProxyServer,
+		proxyHooks:     proxyHooks,
+		toolGroups:     make(map[string]*toolGroupProxy),
+		usageService:   usageService,
+		policyService:  policyService,
+		features:       features,
+		notifications:  notifications,
+		drainTimeout:   DefaultDrainTimeout,
 	}
 	if err := s.initMCPProxyServer(); err != nil {
 		return nil, fmt.Errorf("failed to initialize MCP proxy server: %w", err)
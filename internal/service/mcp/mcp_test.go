@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestMCPService returns an MCPService backed by an in-memory database in which the given models are migrated,
+// or the whole schema if there are none. Its MCP proxy server has the tools & prompts capabilities.
+// The database has a single connection, as every connection to an in-memory sqlite database gets a new database.
+func newTestMCPService(t *testing.T, models ...any) *MCPService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if len(models) == 0 {
+		err = migrations.Migrate(db)
+	} else {
+		err = db.AutoMigrate(models...)
+	}
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return &MCPService{
+		db: db,
+		mcpProxyServer: server.NewMCPServer(
+			"proxy", "0.0.1", server.WithToolCapabilities(true), server.WithPromptCapabilities(true),
+		),
+	}
+}
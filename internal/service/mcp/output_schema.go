@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"gorm.io/datatypes"
+	"log"
+)
+
+// SetToolOutputSchema sets the output schema of a tool. An empty schema removes it.
+// The schema must describe a JSON object, because MCP structured content is always an object.
+// If version is not 0, the change is rejected with a *types.VersionConflict if the tool is at another version.
+func (m *MCPService) SetToolOutputSchema(name string, outputSchema json.RawMessage, version int) error {
+	if len(outputSchema) > 0 {
+		var s map[string]any
+		if err := json.Unmarshal(outputSchema, &s); err != nil {
+			return fmt.Errorf("output schema must be a JSON object: %w", err)
+		}
+		if s["type"] != "object" {
+			return fmt.Errorf("output schema must be of type 'object'")
+		}
+	}
+
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return err
+	}
+	var value any
+	if len(outputSchema) > 0 {
+		value = datatypes.JSON(outputSchema)
+	}
+	if err := m.updateTool(tool, name, version, map[string]any{"output_schema": value}); err != nil {
+		return fmt.Errorf("failed to set output schema of tool %s: %w", name, err)
+	}
+	return nil
+}
+
+// addStructuredContent converts the JSON text result of a tool call made via the API into structured content,
+// as declared by the tool's output schema.
+// This lets clients that rely on structured content work with upstream servers that only return text.
+// Results that already contain structured content, errors and results that don't match the schema are not modified.
+func (m *MCPService) addStructuredContent(name string, result *mcp.CallToolResult) {
+	if result == nil || result.IsError || result.StructuredContent != nil {
+		return
+	}
+	if !m.features.Enabled(feature.StructuredContent) {
+		return
+	}
+	tool, err := m.GetTool(name)
+	if err != nil {
+		log.Printf("[ERROR] failed to get tool %s to convert its result: %v", name, err)
+		return
+	}
+	if len(tool.OutputSchema) == 0 {
+		return
+	}
+	if sc, ok := structuredContentFromText(tool.OutputSchema, result.Content); ok {
+		result.StructuredContent = sc
+	}
+}
+
+// structuredContentFromText returns the JSON object contained in the single text content of a tool result,
+// provided that it has all the properties required by the output schema.
+func structuredContentFromText(outputSchema []byte, content []mcp.Content) (map[string]any, bool) {
+	if len(content) != 1 {
+		return nil, false
+	}
+	text, ok := content[0].(mcp.TextContent)
+	if !ok {
+		return nil, false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(text.Text), &obj); err != nil {
+		return nil, false
+	}
+
+	var schema struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(outputSchema, &schema); err != nil {
+		return nil, false
+	}
+	for _, p := range schema.Required {
+		if _, ok := obj[p]; !ok {
+			return nil, false
+		}
+	}
+	return obj, true
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestStructuredContentFromText(t *testing.T) {
+	schema := []byte(`{"type": "object", "properties": {"temp": {"type": "number"}}, "required": ["temp"]}`)
+
+	tests := []struct {
+		name    string
+		content []mcp.Content
+		wantOK  bool
+	}{
+		{"json object", []mcp.Content{mcp.NewTextContent(`{"temp": 21.5}`)}, true},
+		{"missing required property", []mcp.Content{mcp.NewTextContent(`{"humidity": 40}`)}, false},
+		{"json array", []mcp.Content{mcp.NewTextContent(`[1, 2]`)}, false},
+		{"plain text", []mcp.Content{mcp.NewTextContent("sunny")}, false},
+		{"multiple contents", []mcp.Content{mcp.NewTextContent(`{"temp": 1}`), mcp.NewTextContent(`{"temp": 2}`)}, false},
+	}
+	for _, tt := range tests {
+		got, ok := structuredContentFromText(schema, tt.content)
+		if ok != tt.wantOK {
+			t.Errorf("%s: structuredContentFromText() ok = %v, want %v", tt.name, ok, tt.wantOK)
+		}
+		if ok && got["temp"] != 21.5 {
+			t.Errorf("%s: structuredContentFromText() = %v", tt.name, got)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// redactedValue replaces the value of the fields removed from logged payloads
+const redactedValue = "[REDACTED]"
+
+// maxLoggedPayloadSize is the maximum size of a payload stored in the invocation history.
+// Larger payloads are replaced by a placeholder that records their size.
+const maxLoggedPayloadSize = 64 << 10
+
+// secretFieldSuffixes are the endings of the names of fields that are always removed from logged payloads,
+// eg- "password", "access_token" and "apiKey". Names are compared in lower case without '_' and '-'.
+var secretFieldSuffixes = []string{
+	"password", "passwd", "secret", "token", "apikey", "authorization", "cookie", "credential", "credentials", "privatekey",
+}
+
+// isSecretField returns true if the name of a field suggests that it holds a secret.
+func isSecretField(name string) bool {
+	name = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	for _, suffix := range secretFieldSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetToolPayloadLogging makes mcpjungle store the arguments & results of a fraction of the calls to a tool
+// in the invocation history. The fields matching the redaction paths are removed first.
+// A redaction path is either a field name, which matches the field at any depth, or a dot-separated path
+// from the root of the payload, in which "*" matches any field. Arrays don't count as a level of the path.
+// If version is not 0, the change is rejected with a *types.VersionConflict if the tool is at another version.
+func (m *MCPService) SetToolPayloadLogging(name string, sampleRate float64, redact []string, version int) error {
+	if sampleRate < 0 || sampleRate > 1 {
+		return fmt.Errorf("sample rate must be between 0 and 1")
+	}
+	for _, path := range redact {
+		if path == "" || strings.Contains(path, "..") || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") {
+			return fmt.Errorf("invalid redaction path: '%s'", path)
+		}
+	}
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return err
+	}
+	var redactions []byte
+	if len(redact) > 0 {
+		redactions, _ = json.Marshal(redact)
+	}
+	updates := map[string]any{"payload_sample_rate": sampleRate, "payload_redactions": redactions}
+	if err := m.updateTool(tool, name, version, updates); err != nil {
+		return fmt.Errorf("failed to set payload logging of tool %s: %w", name, err)
+	}
+	return nil
+}
+
+// samplePayload returns true if the payloads of a call to the tool must be logged.
+func samplePayload(tool *model.Tool) bool {
+	return tool.PayloadSampleRate > 0 && rand.Float64() < tool.PayloadSampleRate
+}
+
+// loggedArguments returns the redacted JSON of the arguments of a call, for the invocation history.
+func loggedArguments(args map[string]any, redact []string) []byte {
+	v, err := jsonValue(args)
+	if err != nil {
+		return nil
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	return capPayload(redactValue(v, nil, redact))
+}
+
+// loggedResult returns the redacted JSON of the result of a call, for the invocation history.
+// The redaction paths apply to the structured content and to text content that contains JSON.
+// Other text content is stored as is.
+func loggedResult(resp *mcp.CallToolResult, redact []string) []byte {
+	v, err := jsonValue(resp)
+	if err != nil {
+		return nil
+	}
+	result, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if sc, ok := result["structuredContent"]; ok {
+		result["structuredContent"] = redactValue(sc, nil, redact)
+	}
+	content, _ := result["content"].([]any)
+	for _, c := range content {
+		item, ok := c.(map[string]any)
+		if !ok || item["type"] != "text" {
+			continue
+		}
+		text, _ := item["text"].(string)
+		var parsed any
+		if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+			continue
+		}
+		switch parsed.(type) {
+		case map[string]any, []any:
+			if b, err := json.Marshal(redactValue(parsed, nil, redact)); err == nil {
+				item["text"] = string(b)
+			}
+		}
+	}
+	return capPayload(result)
+}
+
+// jsonValue converts v into its generic JSON representation, which is a deep copy that can be modified.
+func jsonValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
+
+// redactValue replaces the fields of v that match a redaction path or have a secret-looking name.
+// path is the path of v from the root of the payload.
+func redactValue(v any, path []string, redact []string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			p := append(path[:len(path):len(path)], k)
+			if isSecretField(k) || matchesRedaction(p, redact) {
+				val[k] = redactedValue
+				continue
+			}
+			val[k] = redactValue(child, p, redact)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = redactValue(child, path, redact)
+		}
+	}
+	return v
+}
+
+func matchesRedaction(path []string, redact []string) bool {
+	for _, r := range redact {
+		segments := strings.Split(r, ".")
+		if len(segments) == 1 {
+			if segments[0] == path[len(path)-1] {
+				return true
+			}
+			continue
+		}
+		if len(segments) != len(path) {
+			continue
+		}
+		match := true
+		for i, s := range segments {
+			if s != "*" && s != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// capPayload serializes a payload, replacing it with a placeholder if it is too large to be logged.
+func capPayload(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	if len(b) > maxLoggedPayloadSize {
+		b, _ = json.Marshal(map[string]any{"truncated": true, "size": len(b)})
+	}
+	return b
+}
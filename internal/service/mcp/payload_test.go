@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+)
+
+func TestLoggedArguments(t *testing.T) {
+	args := map[string]any{
+		"query":      "acme",
+		"max_tokens": 100.0,
+		"apiKey":     "k-123",
+		"user":       map[string]any{"email": "jane@example.com", "name": "Jane"},
+		"contacts":   []any{map[string]any{"email": "joe@example.com", "phone": "555"}},
+		"headers":    map[string]any{"X-Access-Token": "t-456"},
+	}
+	got := string(loggedArguments(args, []string{"user.email", "phone"}))
+
+	for _, secret := range []string{"k-123", "jane@example.com", "555", "t-456"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("logged arguments contain %q: %s", secret, got)
+		}
+	}
+	for _, kept := range []string{"acme", "Jane", "joe@example.com", `"max_tokens":100`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("logged arguments don't contain %q: %s", kept, got)
+		}
+	}
+	if args["apiKey"] != "k-123" || args["user"].(map[string]any)["email"] != "jane@example.com" {
+		t.Error("redaction modified the arguments of the call")
+	}
+}
+
+func TestLoggedResult(t *testing.T) {
+	resp := mcp.NewToolResultText(`{"customer": {"ssn": "123-45-6789", "name": "Jane"}, "token": "abc"}`)
+	resp.Content = append(resp.Content, mcp.NewTextContent("plain text"))
+	resp.StructuredContent = map[string]any{"customer": map[string]any{"ssn": "123-45-6789"}}
+
+	got := string(loggedResult(resp, []string{"*.ssn"}))
+	if strings.Contains(got, "123-45-6789") || strings.Contains(got, "abc") {
+		t.Errorf("logged result contains redacted fields: %s", got)
+	}
+	if !strings.Contains(got, "Jane") || !strings.Contains(got, "plain text") {
+		t.Errorf("logged result lost unredacted content: %s", got)
+	}
+
+	large := mcp.NewToolResultText(strings.Repeat("x", maxLoggedPayloadSize))
+	var placeholder map[string]any
+	if err := json.Unmarshal(loggedResult(large, nil), &placeholder); err != nil || placeholder["truncated"] != true {
+		t.Errorf("large result was not replaced by a placeholder: %v %v", placeholder, err)
+	}
+}
+
+func TestPayloadLogging(t *testing.T) {
+	m := newTestMCPService(t)
+	db := m.db
+	usageService := usage.NewUsageService(db, nil, nil)
+	m.policyService = policy.NewPolicyService(db)
+	m.usageService = usageService
+
+	upstreamServer := server.NewMCPServer("crm", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("find"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"name": "Jane", "email": "jane@example.com"}`), nil
+	})
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("crm", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	if err := m.SetToolPayloadLogging("crm__find", 1.5, nil, 0); err == nil {
+		t.Error("SetToolPayloadLogging() accepted a sample rate above 1")
+	}
+
+	call := func() *model.ToolInvocation {
+		t.Helper()
+		if _, err := m.InvokeTool(context.Background(), "crm__find", map[string]any{"email": "jane@example.com"}); err != nil {
+			t.Fatalf("InvokeTool() error = %v", err)
+		}
+		invocations, err := usageService.ListInvocations("crm__find", 1)
+		if err != nil || len(invocations) != 1 {
+			t.Fatalf("ListInvocations() = %v, %v", invocations, err)
+		}
+		return invocations[0]
+	}
+
+	if inv := call(); inv.Arguments != nil || inv.Result != nil {
+		t.Errorf("payloads were logged without payload logging: %s %s", inv.Arguments, inv.Result)
+	}
+
+	if err := m.SetToolPayloadLogging("crm__find", 1, []string{"email"}, 0); err != nil {
+		t.Fatalf("SetToolPayloadLogging() error = %v", err)
+	}
+	inv := call()
+	if string(inv.Arguments) != `{"email":"[REDACTED]"}` {
+		t.Errorf("logged arguments = %s", inv.Arguments)
+	}
+	if !strings.Contains(string(inv.Result), "Jane") || strings.Contains(string(inv.Result), "jane@example.com") {
+		t.Errorf("logged result = %s", inv.Result)
+	}
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// CreatePrompt creates a new prompt template and serves it via the MCP proxy.
+func (m *MCPService) CreatePrompt(p *model.Prompt) error {
+	if err := validateServerName(p.Name); err != nil {
+		return fmt.Errorf("invalid prompt name: %w", err)
+	}
+	if err := m.db.Create(p).Error; err != nil {
+		return fmt.Errorf("failed to create prompt: %w", err)
+	}
+	m.addProxyPrompt(p)
+	return nil
+}
+
+// ListPrompts returns all prompt templates.
+func (m *MCPService) ListPrompts() ([]*model.Prompt, error) {
+	var prompts []*model.Prompt
+	if err := m.db.Order("name").Find(&prompts).Error; err != nil {
+		return nil, err
+	}
+	return prompts, nil
+}
+
+// GetPrompt returns a prompt template by name.
+func (m *MCPService) GetPrompt(name string) (*model.Prompt, error) {
+	var p model.Prompt
+	if err := m.db.Where("name = ?", name).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SetPromptOverride sets the template of a prompt used for a specific MCP client.
+// An empty template removes the client's override, so that it gets the prompt's regular template again.
+func (m *MCPService) SetPromptOverride(name, clientName, template string) error {
+	p, err := m.GetPrompt(name)
+	if err != nil {
+		return fmt.Errorf("prompt %s not found: %w", name, err)
+	}
+	overrides := p.GetOverrides()
+	if overrides == nil {
+		overrides = make(map[string]string)
+	}
+	if template == "" {
+		delete(overrides, clientName)
+	} else {
+		if err := m.db.Where("name = ?", clientName).First(&model.McpClient{}).Error; err != nil {
+			return fmt.Errorf("MCP client %s not found: %w", clientName, err)
+		}
+		overrides[clientName] = template
+	}
+	if p.Overrides, err = json.Marshal(overrides); err != nil {
+		return err
+	}
+	return m.db.Save(p).Error
+}
+
+// DeletePrompt deletes a prompt template by name and stops serving it via the MCP proxy.
+// It is an idempotent operation. Deleting a prompt that does not exist will not return an error.
+func (m *MCPService) DeletePrompt(name string) error {
+	if err := m.db.Unscoped().Where("name = ?", name).Delete(&model.Prompt{}).Error; err != nil {
+		return err
+	}
+	m.mcpProxyServer.DeletePrompts(name)
+	return nil
+}
+
+// addProxyPrompt adds a prompt template to the MCP proxy server.
+func (m *MCPService) addProxyPrompt(p *model.Prompt) {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(p.Description)}
+	for _, a := range p.GetArguments() {
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(a.Description)}
+		if a.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(a.Name, argOpts...))
+	}
+	m.mcpProxyServer.AddPrompt(mcp.NewPrompt(p.Name, opts...), m.mcpProxyPromptHandler)
+}
+
+// mcpProxyPromptHandler handles prompts/get requests for the MCP proxy server by rendering the prompt template.
+// In production mode, the template is customized for the MCP client making the request.
+func (m *MCPService) mcpProxyPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	p, err := m.GetPrompt(request.Params.Name)
+	if err != nil {
+		return nil, fmt.Errorf("prompt %s not found: %w", request.Params.Name, err)
+	}
+	c, _ := ctx.Value("client").(*model.McpClient)
+	text, err := p.Render(request.Params.Arguments, c)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewGetPromptResult(
+		p.Description,
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+	), nil
+}
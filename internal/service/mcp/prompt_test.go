@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/datatypes"
+)
+
+func TestProxyServesPrompts(t *testing.T) {
+	m := newTestMCPService(t, &model.Prompt{})
+
+	undeclared := &model.Prompt{Name: "bad", Template: "Hi {{name}}"}
+	if err := m.CreatePrompt(undeclared); err == nil {
+		t.Fatal("CreatePrompt() with an undeclared argument succeeded, want error")
+	}
+
+	p := &model.Prompt{
+		Name:      "review",
+		Template:  "Review this {{ language }} code{{focus}}: {{code}}",
+		Arguments: datatypes.JSON(`[{"name":"language","required":true},{"name":"code","required":true},{"name":"focus"}]`),
+	}
+	if err := m.CreatePrompt(p); err != nil {
+		t.Fatalf("CreatePrompt() error = %v", err)
+	}
+
+	c, err := client.NewInProcessClient(m.mcpProxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		t.Fatalf("ListPrompts() error = %v", err)
+	}
+	if len(list.Prompts) != 1 || len(list.Prompts[0].Arguments) != 3 || !list.Prompts[0].Arguments[0].Required {
+		t.Fatalf("ListPrompts() = %+v, want the review prompt with 3 arguments", list.Prompts)
+	}
+
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = "review"
+	req.Params.Arguments = map[string]string{"language": "Go", "code": "x := 1"}
+	res, err := c.GetPrompt(ctx, req)
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if got := res.Messages[0].Content.(mcp.TextContent).Text; got != "Review this Go code: x := 1" {
+		t.Errorf("GetPrompt() text = %q", got)
+	}
+
+	req.Params.Arguments = map[string]string{"language": "Go"}
+	if _, err := c.GetPrompt(ctx, req); err == nil {
+		t.Error("GetPrompt() without a required argument succeeded, want error")
+	}
+
+	if err := m.DeletePrompt("review"); err != nil {
+		t.Fatalf("DeletePrompt() error = %v", err)
+	}
+	if _, err := c.GetPrompt(ctx, req); err == nil {
+		t.Error("GetPrompt() of a deleted prompt succeeded, want error")
+	}
+}
@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrProposalNotPending is returned when reviewing a proposal that has already been approved or rejected
+var ErrProposalNotPending = errors.New("proposal is not pending")
+
+// ProposeMcpServer records a request to register an MCP server, to be approved or rejected by an admin.
+// The server is not connected to: proposals come from users who aren't allowed to run commands or reach
+// arbitrary URLs from the gateway, so only an admin can test-connect to it (see PreflightServerProposal).
+// Admins are notified of the new proposal.
+func (m *MCPService) ProposeMcpServer(s *model.McpServer, proposedBy string) (*model.ServerProposal, error) {
+	if err := validateServerName(s.Name); err != nil {
+		return nil, err
+	}
+	if _, err := m.GetMcpServer(s.Name); err == nil {
+		return nil, fmt.Errorf("MCP server %s already exists", s.Name)
+	}
+	var pending int64
+	err := m.db.Model(&model.ServerProposal{}).
+		Where("name = ? AND status = ?", s.Name, types.ServerProposalPending).
+		Count(&pending).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing proposals: %w", err)
+	}
+	if pending > 0 {
+		return nil, fmt.Errorf("a registration of MCP server %s is already pending approval", s.Name)
+	}
+
+	p := &model.ServerProposal{
+		Name:       s.Name,
+		ProposedBy: proposedBy,
+		Status:     types.ServerProposalPending,
+	}
+	if p.Server, err = json.Marshal(s); err != nil {
+		return nil, fmt.Errorf("failed to serialize proposed server: %w", err)
+	}
+	if err := m.db.Create(p).Error; err != nil {
+		return nil, fmt.Errorf("failed to record proposal: %w", err)
+	}
+
+	if m.notifications != nil {
+		m.notifications.NotifyByUser(
+			types.NotificationServerProposed,
+			s.Name,
+			proposedBy,
+			fmt.Sprintf("%s proposed to register MCP server %s, see proposal %d", proposedBy, s.Name, p.ID),
+		)
+	}
+	return p, nil
+}
+
+// PreflightServerProposal test-connects to the server of a pending proposal and records the tools,
+// capabilities and protocol version it provides in the proposal, for the admin to review them.
+// It must only be reachable by admins, since it runs the command of a stdio server.
+func (m *MCPService) PreflightServerProposal(ctx context.Context, id uint) (*model.ServerProposal, error) {
+	p, err := m.pendingServerProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	s, err := p.GetServer()
+	if err != nil {
+		return nil, err
+	}
+
+	mcpClient, initResult, err := m.connectMcpServer(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	defer mcpClient.Close()
+	recordServerCapabilities(s, initResult)
+
+	serverTools, err := listServerTools(ctx, s, mcpClient)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]model.Tool, 0, len(serverTools))
+	for _, tool := range serverTools {
+		t := newToolModel(s, tool)
+		t.Name = mergeServerToolNames(s.Name, tool.GetName())
+		t.Enabled = true
+		tools = append(tools, t)
+	}
+
+	if p.Server, err = json.Marshal(s); err != nil {
+		return nil, fmt.Errorf("failed to serialize proposed server: %w", err)
+	}
+	if p.Tools, err = json.Marshal(tools); err != nil {
+		return nil, fmt.Errorf("failed to serialize tools of proposed server: %w", err)
+	}
+	if err := m.db.Model(p).Select("Server", "Tools").Updates(p).Error; err != nil {
+		return nil, fmt.Errorf("failed to update proposal %d: %w", p.ID, err)
+	}
+	return p, nil
+}
+
+// ListServerProposals returns the proposals with the given status (all proposals if empty), newest first.
+// If proposedBy is not empty, only the proposals of that user are returned.
+func (m *MCPService) ListServerProposals(status types.ServerProposalStatus, proposedBy string) ([]*model.ServerProposal, error) {
+	q := m.db.Order("id DESC")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if proposedBy != "" {
+		q = q.Where("proposed_by = ?", proposedBy)
+	}
+	var proposals []*model.ServerProposal
+	if err := q.Find(&proposals).Error; err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// GetServerProposal returns a proposal by ID.
+func (m *MCPService) GetServerProposal(id uint) (*model.ServerProposal, error) {
+	var p model.ServerProposal
+	if err := m.db.First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ApproveServerProposal registers the server of a pending proposal.
+// If the registration fails (eg- because the server is no longer reachable), the proposal remains pending.
+func (m *MCPService) ApproveServerProposal(ctx context.Context, id uint, reviewer, comment string) (*model.McpServer, error) {
+	p, err := m.pendingServerProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	s, err := p.GetServer()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.RegisterMcpServer(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := m.reviewServerProposal(p, types.ServerProposalApproved, reviewer, comment); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// RejectServerProposal turns down a pending proposal.
+func (m *MCPService) RejectServerProposal(id uint, reviewer, comment string) error {
+	p, err := m.pendingServerProposal(id)
+	if err != nil {
+		return err
+	}
+	return m.reviewServerProposal(p, types.ServerProposalRejected, reviewer, comment)
+}
+
+func (m *MCPService) pendingServerProposal(id uint) (*model.ServerProposal, error) {
+	p, err := m.GetServerProposal(id)
+	if err != nil {
+		return nil, fmt.Errorf("proposal %d not found: %w", id, err)
+	}
+	if p.Status != types.ServerProposalPending {
+		return nil, fmt.Errorf("%w: proposal %d was already %s", ErrProposalNotPending, id, p.Status)
+	}
+	return p, nil
+}
+
+func (m *MCPService) reviewServerProposal(
+	p *model.ServerProposal, status types.ServerProposalStatus, reviewer, comment string,
+) error {
+	res := m.db.Model(&model.ServerProposal{}).
+		Where("id = ? AND status = ?", p.ID, types.ServerProposalPending).
+		Updates(map[string]any{"status": status, "reviewed_by": reviewer, "review_comment": comment})
+	if res.Error != nil {
+		return fmt.Errorf("failed to update proposal %d: %w", p.ID, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: proposal %d was reviewed concurrently", ErrProposalNotPending, p.ID)
+	}
+	p.Status, p.ReviewedBy, p.ReviewComment = status, reviewer, comment
+	return nil
+}
+
+// DescribeServerProposal converts a proposal into its API representation, without the server's credentials.
+func DescribeServerProposal(p *model.ServerProposal) (*types.ServerProposal, error) {
+	s, err := p.GetServer()
+	if err != nil {
+		return nil, err
+	}
+	d := &types.ServerProposal{
+		ID:            p.ID,
+		Name:          p.Name,
+		Transport:     string(s.Transport),
+		Description:   s.Description,
+		ProposedBy:    p.ProposedBy,
+		Status:        p.Status,
+		ReviewedBy:    p.ReviewedBy,
+		ReviewComment: p.ReviewComment,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+	if s.Transport == types.TransportStreamableHTTP {
+		conf, err := s.GetStreamableHTTPConfig()
+		if err != nil {
+			return nil, err
+		}
+		d.Target = conf.URL
+	} else {
+		conf, err := s.GetStdioConfig()
+		if err != nil {
+			return nil, err
+		}
+		d.Target = strings.Join(append([]string{conf.Command}, conf.Args...), " ")
+	}
+
+	c, err := describeCapabilities(s)
+	if err != nil {
+		return nil, err
+	}
+	d.Preflight.ProtocolVersion = c.ProtocolVersion
+	d.Preflight.ServerInfo = c.ServerInfo
+	d.Preflight.Capabilities = c.Capabilities
+	d.Preflight.Tools = []*types.Tool{}
+	if len(p.Tools) > 0 {
+		d.Preflighted = true
+		if err := json.Unmarshal(p.Tools, &d.Preflight.Tools); err != nil {
+			return nil, fmt.Errorf("invalid tools in proposal %d: %w", p.ID, err)
+		}
+	}
+	return d, nil
+}
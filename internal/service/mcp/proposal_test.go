@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestServerProposal(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{}, &model.ServerProposal{}, &model.Notification{})
+	db := m.db
+	notifications := notification.NewNotificationService(db)
+	m.notifications = notifications
+
+	upstreamServer := server.NewMCPServer("search", "1.2.3", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("query"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	// count the requests made to the upstream server
+	var dials atomic.Int32
+	upstream := httptest.NewServer(func() http.Handler {
+		h := server.NewStreamableHTTPServer(upstreamServer)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dials.Add(1)
+			h.ServeHTTP(w, r)
+		})
+	}())
+	defer upstream.Close()
+
+	newServer := func() *model.McpServer {
+		s, err := model.NewStreamableHTTPServer("search", "", upstream.URL+"/mcp", "secret-token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	p, err := m.ProposeMcpServer(newServer(), "alice")
+	if err != nil {
+		t.Fatalf("ProposeMcpServer() error = %v", err)
+	}
+	if _, err := m.ProposeMcpServer(newServer(), "bob"); err == nil {
+		t.Error("ProposeMcpServer() accepted a second pending proposal for the same server")
+	}
+	if _, err := m.GetMcpServer("search"); err == nil {
+		t.Fatal("proposed server was registered before its approval")
+	}
+
+	// proposing a server never connects to it, nor runs its command
+	marker := filepath.Join(t.TempDir(), "ran")
+	stdio, err := model.NewStdioServer("shell", "", model.StdioConfig{Command: "touch", Args: []string{marker}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ProposeMcpServer(stdio, "alice"); err != nil {
+		t.Fatalf("ProposeMcpServer() error = %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("proposing a stdio server ran its command")
+	}
+	if n := dials.Load(); n != 0 {
+		t.Errorf("proposing a server made %d requests to it, want 0", n)
+	}
+
+	d, err := DescribeServerProposal(p)
+	if err != nil {
+		t.Fatalf("DescribeServerProposal() error = %v", err)
+	}
+	if d.Status != types.ServerProposalPending || d.Target != upstream.URL+"/mcp" || d.ProposedBy != "alice" || d.Preflighted {
+		t.Errorf("unexpected proposal: %+v", d)
+	}
+
+	// an admin test-connects to the server to review what it provides
+	if p, err = m.PreflightServerProposal(context.Background(), p.ID); err != nil {
+		t.Fatalf("PreflightServerProposal() error = %v", err)
+	}
+	if d, err = DescribeServerProposal(p); err != nil {
+		t.Fatalf("DescribeServerProposal() error = %v", err)
+	}
+	if !d.Preflighted || len(d.Preflight.Tools) != 1 || d.Preflight.Tools[0].Name != "search__query" {
+		t.Errorf("preflight tools = %+v, want search__query", d.Preflight.Tools)
+	}
+	if d.Preflight.ServerInfo == nil || d.Preflight.ServerInfo.Version != "1.2.3" {
+		t.Errorf("preflight server info = %+v", d.Preflight.ServerInfo)
+	}
+
+	list, err := notifications.ListNotifications(true, 0)
+	if err != nil || len(list) != 2 || list[0].Kind != types.NotificationServerProposed {
+		t.Errorf("admins were not notified of the proposal: %v %v", list, err)
+	}
+
+	if _, err := m.ApproveServerProposal(context.Background(), p.ID, "admin", "lgtm"); err != nil {
+		t.Fatalf("ApproveServerProposal() error = %v", err)
+	}
+	s, err := m.GetMcpServer("search")
+	if err != nil {
+		t.Fatalf("approved server was not registered: %v", err)
+	}
+	conf, _ := s.GetStreamableHTTPConfig()
+	if conf.BearerToken != "secret-token" {
+		t.Errorf("bearer token of the registered server = %q, want the proposed one", conf.BearerToken)
+	}
+	if err := m.RejectServerProposal(p.ID, "admin", ""); !errors.Is(err, ErrProposalNotPending) {
+		t.Errorf("RejectServerProposal() on an approved proposal error = %v, want ErrProposalNotPending", err)
+	}
+
+	approved, err := m.ListServerProposals(types.ServerProposalApproved, "alice")
+	if err != nil || len(approved) != 1 || approved[0].ReviewedBy != "admin" || approved[0].ReviewComment != "lgtm" {
+		t.Errorf("ListServerProposals() = %v, %v", approved, err)
+	}
+	if others, _ := m.ListServerProposals("", "bob"); len(others) != 0 {
+		t.Errorf("ListServerProposals() returned %d proposals of another user", len(others))
+	}
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// protocolVersion20241105 is the oldest MCP protocol revision supported by the proxy.
+// It predates audio content in tool results.
+const protocolVersion20241105 = "2024-11-05"
+
+// RegisterHooks registers the hooks through which the session manager learns about the sessions of the proxy.
+// It records the protocol version negotiated by every session and counts tool calls per protocol version.
+func (s *SessionManager) RegisterHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(s.beforeAny)
+	hooks.AddAfterInitialize(s.afterInitialize)
+	hooks.AddAfterCallTool(s.afterCallTool)
+}
+
+// beforeAny records activity on the session of a request, for the sessions that the manager doesn't issue.
+func (s *SessionManager) beforeAny(ctx context.Context, _ any, _ mcp.MCPMethod, _ any) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || s.Enforced() {
+		return
+	}
+	s.mu.Lock()
+	if sess, ok := s.sessions[session.SessionID()]; ok {
+		sess.lastSeen = s.now()
+	}
+	s.mu.Unlock()
+}
+
+// ProtocolVersion returns the MCP protocol version negotiated by a session.
+// It returns an empty string if the session is unknown or hasn't been initialized yet.
+func (s *SessionManager) ProtocolVersion(sessionID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[sessionID]; ok {
+		return sess.protocolVersion
+	}
+	return ""
+}
+
+func (s *SessionManager) afterInitialize(ctx context.Context, _ any, req *mcp.InitializeRequest, result *mcp.InitializeResult) {
+	requested := req.Params.ProtocolVersion
+	if !slices.Contains(mcp.ValidProtocolVersions, requested) {
+		// don't let arbitrary client input create new label values
+		requested = "unsupported"
+	}
+	metrics.ProxySessionsInitialized.WithLabelValues(requested, result.ProtocolVersion).Inc()
+
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return
+	}
+	s.mu.Lock()
+	sess, ok := s.sessions[session.SessionID()]
+	if !ok && !s.Enforced() {
+		// the session was issued by the streamable HTTP server, it is tracked from its initialization
+		now := s.now()
+		sess = &proxySession{createdAt: now, lastSeen: now}
+		s.sessions[session.SessionID()] = sess
+		metrics.ProxySessionsActive.Inc()
+	}
+	if sess != nil {
+		sess.protocolVersion = result.ProtocolVersion
+	}
+	s.mu.Unlock()
+}
+
+func (s *SessionManager) afterCallTool(ctx context.Context, _ any, _ *mcp.CallToolRequest, _ *mcp.CallToolResult) {
+	version := s.sessionProtocolVersion(ctx)
+	if version == "" {
+		version = "unknown"
+	}
+	metrics.ProxyToolCalls.WithLabelValues(version).Inc()
+}
+
+// sessionProtocolVersion returns the MCP protocol version negotiated by the proxy session of a request.
+// It returns an empty string if the request doesn't belong to a known session.
+func (s *SessionManager) sessionProtocolVersion(ctx context.Context) string {
+	if s == nil {
+		return ""
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return s.ProtocolVersion(session.SessionID())
+	}
+	return ""
+}
+
+// SetSessionManager lets the proxy adapt tool results to the protocol version of the session they are sent to.
+func (m *MCPService) SetSessionManager(s *SessionManager) {
+	m.sessions = s
+}
+
+// adaptResultToSession adapts a tool result to the protocol version of the proxy session it is sent to.
+// It must run before the result is signed, so that clients receive a result that matches its signature.
+func (m *MCPService) adaptResultToSession(ctx context.Context, result *mcp.CallToolResult) {
+	if result != nil {
+		adaptToolResult(m.sessions.sessionProtocolVersion(ctx), result)
+	}
+}
+
+// adaptToolResult translates the content of a tool result into shapes that a client speaking
+// the given protocol version understands.
+// Upstream servers may speak a newer revision of the protocol than the downstream client.
+func adaptToolResult(protocolVersion string, result *mcp.CallToolResult) {
+	if protocolVersion != protocolVersion20241105 {
+		return
+	}
+	for i, c := range result.Content {
+		// audio content was introduced after 2024-11-05, send it as an embedded binary resource instead
+		if audio, ok := c.(mcp.AudioContent); ok {
+			result.Content[i] = mcp.EmbeddedResource{
+				Annotated: audio.Annotated,
+				Type:      "resource",
+				Resource: mcp.BlobResourceContents{
+					URI:      fmt.Sprintf("mcpjungle://tool-result/audio/%d", i),
+					MIMEType: audio.MIMEType,
+					Blob:     audio.Data,
+				},
+			}
+		}
+	}
+}
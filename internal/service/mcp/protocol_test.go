@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestProxyAdaptsToolResultsToProtocolVersion(t *testing.T) {
+	hooks := &server.Hooks{}
+	proxy := server.NewMCPServer("proxy", "0.0.1", server.WithToolCapabilities(true), server.WithHooks(hooks))
+	sm := NewSessionManager(time.Hour, 0, proxy)
+	sm.RegisterHooks(hooks)
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &MCPService{}
+	m.SetSessionManager(sm)
+	if err := m.EnableResultSigning(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})); err != nil {
+		t.Fatal(err)
+	}
+
+	// same order as the proxy's tool call handler
+	proxy.AddTool(mcp.NewTool("speak"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		res := &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.NewAudioContent("UklGRg==", "audio/wav")},
+		}
+		m.adaptResultToSession(ctx, res)
+		m.signToolResult("speak", res)
+		return res, nil
+	})
+	pub := priv.Public().(ed25519.PublicKey)
+	verify := func(res *mcp.CallToolResult) bool {
+		jws, _ := res.Meta[types.ToolResultSignatureMetaKey].(string)
+		header, sig, _ := strings.Cut(jws, "..")
+		c, err := types.NewToolResultSigningContent(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, err := types.ToolResultSigningPayload(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedSig, _ := base64.RawURLEncoding.DecodeString(sig)
+		return ed25519.Verify(pub, []byte(header+"."+base64.RawURLEncoding.EncodeToString(payload)), decodedSig)
+	}
+	ts := server.NewTestStreamableHTTPServer(proxy, server.WithSessionIdManager(sm))
+	defer ts.Close()
+
+	callSpeak := func(protocolVersion string) mcp.Content {
+		t.Helper()
+		c, err := client.NewStreamableHttpClient(ts.URL + "/mcp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		initReq := mcp.InitializeRequest{}
+		initReq.Params.ProtocolVersion = protocolVersion
+		initReq.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "1"}
+		res, err := c.Initialize(context.Background(), initReq)
+		if err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		if res.ProtocolVersion != protocolVersion {
+			t.Fatalf("negotiated protocol version = %s, want %s", res.ProtocolVersion, protocolVersion)
+		}
+
+		callReq := mcp.CallToolRequest{}
+		callReq.Params.Name = "speak"
+		result, err := c.CallTool(context.Background(), callReq)
+		if err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+		if len(result.Content) != 1 {
+			t.Fatalf("expected 1 content item, got %d", len(result.Content))
+		}
+		if !verify(result) {
+			t.Errorf("signature of the result received by a %s client does not verify", protocolVersion)
+		}
+		return result.Content[0]
+	}
+
+	if _, ok := callSpeak(mcp.LATEST_PROTOCOL_VERSION).(mcp.AudioContent); !ok {
+		t.Error("expected audio content to be relayed as-is to a client speaking the latest protocol version")
+	}
+
+	res, ok := callSpeak("2024-11-05").(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatal("expected audio content to be converted to an embedded resource for a 2024-11-05 client")
+	}
+	blob, ok := res.Resource.(mcp.BlobResourceContents)
+	if !ok || blob.Blob != "UklGRg==" || blob.MIMEType != "audio/wav" {
+		t.Errorf("unexpected embedded resource: %+v", res.Resource)
+	}
+}
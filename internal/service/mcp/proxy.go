@@ -7,9 +7,13 @@ import (
 	"github.com/mcpjungle/mcpjungle/internal/model"
 )
 
-// initMCPProxyServer initializes the MCP proxy server.
-// It loads all the registered MCP tools from the database into the proxy server.
+// initMCPProxyServer initializes the MCP proxy server and the proxies of all tool groups.
+// It loads all the registered MCP tools and prompt templates from the database into the proxy servers.
 func (m *MCPService) initMCPProxyServer() error {
+	if err := m.initToolGroupProxies(); err != nil {
+		return err
+	}
+
 	tools, err := m.ListTools()
 	if err != nil {
 		return fmt.Errorf("failed to list tools from DB: %w", err)
@@ -26,7 +30,15 @@ func (m *MCPService) initMCPProxyServer() error {
 			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tm.Name, err)
 		}
 
-		m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+		m.addProxyTool(tool)
+	}
+
+	prompts, err := m.ListPrompts()
+	if err != nil {
+		return fmt.Errorf("failed to list prompts from DB: %w", err)
+	}
+	for _, p := range prompts {
+		m.addProxyPrompt(p)
 	}
 	return nil
 }
@@ -43,16 +55,29 @@ func (m *MCPService) mcpProxyToolCallHandler(ctx context.Context, request mcp.Ca
 
 	serverMode := ctx.Value("mode").(model.ServerMode)
 	if serverMode == model.ModeProd {
-		// In production mode, we need to check whether the MCP client is authorized to access the MCP server.
-		// If not, return error Unauthorized.
+		// In production mode, we need to check whether the MCP client is authorized to access the MCP server,
+		// or was temporarily granted access to the tool. If not, return error Unauthorized.
 		c := ctx.Value("client").(*model.McpClient)
-		if !c.CheckHasServerAccess(serverName) {
+		if !m.checkToolAccess(c, serverName, name) {
 			return nil, fmt.Errorf(
 				"client %s is not authorized to access MCP server %s", c.Name, serverName,
 			)
 		}
 	}
 
+	ctx, cancel, err := m.withCallBudget(ctx, &request)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	inv, args, err := m.beginToolCall(ctx, name, request.GetArguments())
+	if err != nil {
+		return nil, err
+	}
+	defer m.releaseBudget(inv)
+	request.Params.Arguments = args
+
 	// get the MCP server details from the database
 	server, err := m.GetMcpServer(serverName)
 	if err != nil {
@@ -60,16 +85,29 @@ func (m *MCPService) mcpProxyToolCallHandler(ctx context.Context, request mcp.Ca
 			"failed to get details about MCP server %s from DB: %w", serverName, err,
 		)
 	}
-
-	mcpClient, err := newMcpServerSession(ctx, server)
-	if err != nil {
+	if server, err = m.resolveServerForCall(ctx, server, toolName); err != nil {
 		return nil, err
 	}
-	defer mcpClient.Close()
 
 	// Ensure the tool name is set correctly, ie, without the server name prefix
 	request.Params.Name = toolName
 
 	// forward the request to the upstream MCP server and relay the response back
-	return mcpClient.CallTool(ctx, request)
+	resp, attempts, err := m.callTool(ctx, server, name, request)
+	inv.Attempts = attempts
+	m.finishToolCall(inv, resp, err)
+	if err == nil {
+		if resp, err = m.applyResultScript(ctx, name, args, resp); err != nil {
+			return nil, err
+		}
+		if resp, err = m.applyResponsePlugins(ctx, name, args, resp); err != nil {
+			return nil, err
+		}
+		resp = m.translateResult(ctx, name, resp)
+		// mcp-go doesn't send the structured content of results to MCP clients, so it must not be signed either
+		resp.StructuredContent = nil
+		m.adaptResultToSession(ctx, resp)
+		m.signToolResult(name, resp)
+	}
+	return resp, err
 }
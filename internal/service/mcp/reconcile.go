@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ReconciliationReport lists the inconsistencies left by a previous run of mcpjungle (eg- after a crash)
+// that were found at startup, and how they were reconciled.
+type ReconciliationReport struct {
+	// OrphanedProcesses are the stdio server processes of the previous run that were still running,
+	// they were terminated
+	OrphanedProcesses []string
+
+	// OrphanedTools is the number of tools whose MCP server no longer exists, they were deleted
+	OrphanedTools int64
+
+	// IncompleteServers are the MCP servers that advertise tools but had none registered, eg- because mcpjungle
+	// stopped while registering them. Their tools were registered again.
+	IncompleteServers []string
+
+	// UnreachableServers are the MCP servers that mcpjungle couldn't connect to: the keep_warm servers that
+	// failed to warm up and the incomplete servers whose tools couldn't be registered again.
+	// They are marked unhealthy and connected to again on their next tool call.
+	UnreachableServers []string
+
+	Errors []string
+}
+
+// Log writes the report to the log, or a single line if there was nothing to reconcile.
+func (r *ReconciliationReport) Log() {
+	if len(r.OrphanedProcesses) == 0 && r.OrphanedTools == 0 && len(r.IncompleteServers) == 0 &&
+		len(r.UnreachableServers) == 0 && len(r.Errors) == 0 {
+		log.Printf("[INFO] startup reconciliation: no inconsistency found")
+		return
+	}
+	lines := []string{"[INFO] startup reconciliation report:"}
+	if len(r.OrphanedProcesses) > 0 {
+		lines = append(lines, "  terminated orphaned stdio processes: "+strings.Join(r.OrphanedProcesses, ", "))
+	}
+	if r.OrphanedTools > 0 {
+		lines = append(lines, fmt.Sprintf("  deleted %d tools of MCP servers that no longer exist", r.OrphanedTools))
+	}
+	if len(r.IncompleteServers) > 0 {
+		lines = append(lines, "  registered the missing tools of MCP servers: "+strings.Join(r.IncompleteServers, ", "))
+	}
+	if len(r.UnreachableServers) > 0 {
+		lines = append(lines, "  unreachable MCP servers, marked unhealthy: "+strings.Join(r.UnreachableServers, ", "))
+	}
+	for _, e := range r.Errors {
+		lines = append(lines, "  error: "+e)
+	}
+	log.Print(strings.Join(lines, "\n"))
+}
+
+// Reconcile detects the inconsistencies between the registry and the MCP servers left by a previous run
+// of mcpjungle and fixes them, adding its findings to the report.
+// It must run after WarmUp, so that the keep_warm servers that failed to warm up are reported.
+func (m *MCPService) Reconcile(ctx context.Context, report *ReconciliationReport) {
+	// tools left behind by a server whose deregistration didn't complete
+	res := m.db.Unscoped().
+		Where("server_id NOT IN (?)", m.db.Model(&model.McpServer{}).Select("id")).
+		Delete(&model.Tool{})
+	if res.Error != nil {
+		report.Errors = append(report.Errors, "failed to delete orphaned tools: "+res.Error.Error())
+	} else if res.RowsAffected > 0 {
+		report.OrphanedTools = res.RowsAffected
+		m.toolCatalog.invalidate()
+	}
+
+	var servers []*model.McpServer
+	if err := m.db.Where("stopped = ?", false).Order("name").Find(&servers).Error; err != nil {
+		report.Errors = append(report.Errors, "failed to list MCP servers: "+err.Error())
+		return
+	}
+	for _, s := range servers {
+		// built-in servers are registered again at every start
+		if s.Transport == types.TransportBuiltin {
+			continue
+		}
+		if s.KeepWarm && m.warm.get(s.Name) == nil {
+			report.UnreachableServers = append(report.UnreachableServers, s.Name)
+			continue
+		}
+		incomplete, err := m.lacksTools(s)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		if !incomplete {
+			continue
+		}
+		c, err := m.connectUpstream(ctx, s)
+		if err != nil {
+			report.UnreachableServers = append(report.UnreachableServers, s.Name)
+			continue
+		}
+		err = m.registerServerTools(ctx, s, c)
+		_ = c.Close()
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to register tools of MCP server %s: %v", s.Name, err))
+			continue
+		}
+		// the server may really provide no tools, then there was nothing to reconcile
+		if stillMissing, err := m.lacksTools(s); err == nil && !stillMissing {
+			report.IncompleteServers = append(report.IncompleteServers, s.Name)
+		}
+	}
+}
+
+// lacksTools returns true if an MCP server advertised the tools capability when it was registered
+// but has no tools registered. Replicas never lack tools, they serve the ones of their primary server.
+func (m *MCPService) lacksTools(s *model.McpServer) (bool, error) {
+	if s.ReplicaOf != "" {
+		return false, nil
+	}
+	var caps struct {
+		Tools *struct{} `json:"tools"`
+	}
+	if len(s.Capabilities) == 0 || json.Unmarshal(s.Capabilities, &caps) != nil || caps.Tools == nil {
+		return false, nil
+	}
+	var count int64
+	if err := m.db.Model(&model.Tool{}).Where("server_id = ?", s.ID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to count tools of MCP server %s: %w", s.Name, err)
+	}
+	return count == 0, nil
+}
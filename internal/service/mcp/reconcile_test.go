@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestReconcile(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+	db := m.db
+
+	upstreamServer := server.NewMCPServer("git", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("status"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("git", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	// a crash while registering the tools of a server leaves it without tools
+	if err := db.Unscoped().Where("server_id = ?", s.ID).Delete(&model.Tool{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	// and a crash while deregistering one leaves its tools behind
+	if err := db.Create(&model.Tool{Name: "ghost", ServerID: s.ID + 100}).Error; err != nil {
+		t.Fatal(err)
+	}
+	unreachable, err := model.NewStreamableHTTPServer("down", "", "http://127.0.0.1:1/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachable.KeepWarm = true
+	if err := db.Create(unreachable).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	report := &ReconciliationReport{}
+	m.Reconcile(context.Background(), report)
+	if report.OrphanedTools != 1 {
+		t.Errorf("OrphanedTools = %d, want 1", report.OrphanedTools)
+	}
+	if !slices.Equal(report.IncompleteServers, []string{"git"}) {
+		t.Errorf("IncompleteServers = %v, want [git]", report.IncompleteServers)
+	}
+	if !slices.Equal(report.UnreachableServers, []string{"down"}) {
+		t.Errorf("UnreachableServers = %v, want [down]", report.UnreachableServers)
+	}
+	if len(report.Errors) > 0 {
+		t.Errorf("Errors = %v, want none", report.Errors)
+	}
+	if _, err := m.GetTool("git__status"); err != nil {
+		t.Errorf("GetTool() of the re-registered tool error = %v", err)
+	}
+
+	// a second run finds nothing to reconcile but the unreachable server
+	report = &ReconciliationReport{}
+	m.Reconcile(context.Background(), report)
+	if report.OrphanedTools != 0 || len(report.IncompleteServers) != 0 {
+		t.Errorf("second Reconcile() = %+v, want nothing reconciled", report)
+	}
+}
@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+// maxResultChanges limits the number of differences reported for a single replayed call
+const maxResultChanges = 50
+
+// CreateReplaySuite calls the given saved calls (or all saved calls of the server's tools) and records their
+// results in a new replay suite, so that they can be compared with the results of the same calls later.
+// The calls are subject to the same checks as regular tool invocations by the caller.
+func (m *MCPService) CreateReplaySuite(ctx context.Context, input *types.CreateReplaySuiteInput) (*model.ReplaySuite, error) {
+	if err := validateServerName(input.Name); err != nil {
+		return nil, fmt.Errorf("invalid replay suite name: %w", err)
+	}
+	if _, err := m.GetMcpServer(input.Server); err != nil {
+		return nil, fmt.Errorf("MCP server %s not found: %w", input.Server, err)
+	}
+
+	var saved []*model.SavedCall
+	if len(input.SavedCalls) == 0 {
+		all, err := m.ListSavedCalls()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list saved calls: %w", err)
+		}
+		for _, s := range all {
+			if serverName, _, _ := splitServerToolName(s.Tool); serverName == input.Server {
+				saved = append(saved, s)
+			}
+		}
+	}
+	for _, name := range input.SavedCalls {
+		s, err := m.GetSavedCall(name)
+		if err != nil {
+			return nil, fmt.Errorf("saved call %s not found: %w", name, err)
+		}
+		if serverName, _, _ := splitServerToolName(s.Tool); serverName != input.Server {
+			return nil, fmt.Errorf("saved call %s calls tool %s, which is not provided by MCP server %s", name, s.Tool, input.Server)
+		}
+		saved = append(saved, s)
+	}
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("there are no saved calls for the tools of MCP server %s", input.Server)
+	}
+
+	calls := make([]types.RecordedCall, 0, len(saved))
+	for _, s := range saved {
+		calls = append(calls, m.recordCall(ctx, s.Tool, s.GetArgs()))
+	}
+	b, err := json.Marshal(calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize recorded calls: %w", err)
+	}
+	suite := &model.ReplaySuite{Name: input.Name, Server: input.Server, Calls: datatypes.JSON(b)}
+	if err := m.db.Create(suite).Error; err != nil {
+		return nil, fmt.Errorf("failed to create replay suite: %w", err)
+	}
+	return suite, nil
+}
+
+// ListReplaySuites returns all replay suites.
+func (m *MCPService) ListReplaySuites() ([]*model.ReplaySuite, error) {
+	var suites []*model.ReplaySuite
+	if err := m.db.Order("name").Find(&suites).Error; err != nil {
+		return nil, err
+	}
+	return suites, nil
+}
+
+// GetReplaySuite returns a replay suite by name.
+func (m *MCPService) GetReplaySuite(name string) (*model.ReplaySuite, error) {
+	var s model.ReplaySuite
+	if err := m.db.Where("name = ?", name).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// DeleteReplaySuite deletes a replay suite by name.
+// It is an idempotent operation. Deleting a replay suite that does not exist will not return an error.
+func (m *MCPService) DeleteReplaySuite(name string) error {
+	return m.db.Unscoped().Where("name = ?", name).Delete(&model.ReplaySuite{}).Error
+}
+
+// RunReplaySuite replays the calls of a replay suite and reports how their results, latency and errors
+// differ from the recorded ones.
+// If target is set, the calls are replayed against that MCP server instead of the one they were recorded
+// against, eg- a new version of the server registered side by side with the current one.
+func (m *MCPService) RunReplaySuite(ctx context.Context, name, target string) (*types.ReplayReport, error) {
+	suite, err := m.GetReplaySuite(name)
+	if err != nil {
+		return nil, fmt.Errorf("replay suite %s not found: %w", name, err)
+	}
+	if target == "" {
+		target = suite.Server
+	}
+	if _, err := m.GetMcpServer(target); err != nil {
+		return nil, fmt.Errorf("MCP server %s not found: %w", target, err)
+	}
+
+	report := &types.ReplayReport{Suite: suite.Name, Server: target, Summary: map[types.ReplayStatus]int{}}
+	for _, recorded := range suite.GetCalls() {
+		_, toolName, _ := splitServerToolName(recorded.Tool)
+		replayed := m.recordCall(ctx, mergeServerToolNames(target, toolName), recorded.Args)
+		c := compareCalls(&recorded, &replayed)
+		report.Calls = append(report.Calls, c)
+		report.Summary[c.Status]++
+	}
+	return report, nil
+}
+
+// recordCall invokes a tool and records the outcome of the call.
+func (m *MCPService) recordCall(ctx context.Context, tool string, args map[string]any) types.RecordedCall {
+	c := types.RecordedCall{Tool: tool, Args: args}
+	start := time.Now()
+	res, err := m.InvokeTool(ctx, tool, args)
+	c.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		c.Error = err.Error()
+		return c
+	}
+	// metadata (eg- signatures) differs from one call to another, it is not part of the result to compare
+	res.Meta = nil
+	c.Result = res
+	return c
+}
+
+// compareCalls compares a replayed call with the recorded one.
+func compareCalls(recorded, replayed *types.RecordedCall) types.ReplayedCall {
+	c := types.ReplayedCall{
+		Tool:           replayed.Tool,
+		Args:           replayed.Args,
+		RecordedMs:     recorded.DurationMs,
+		DurationMs:     replayed.DurationMs,
+		LatencyDeltaMs: replayed.DurationMs - recorded.DurationMs,
+	}
+	if recorded.Failed() {
+		c.RecordedError = callError(recorded)
+	}
+	if replayed.Failed() {
+		c.Error = callError(replayed)
+	}
+
+	switch {
+	case recorded.Failed() && replayed.Failed():
+		c.Status = types.ReplayFailing
+	case replayed.Failed():
+		c.Status = types.ReplayNewError
+	case recorded.Failed():
+		c.Status = types.ReplayFixed
+	default:
+		diffValues("", normalizeJSON(recorded.Result), normalizeJSON(replayed.Result), &c.Changes)
+		c.Status = types.ReplayUnchanged
+		if len(c.Changes) > 0 {
+			c.Status = types.ReplayChanged
+		}
+	}
+	return c
+}
+
+// callError returns the error of a failed call, or the text of the error result returned by the tool.
+func callError(c *types.RecordedCall) string {
+	if c.Error != "" {
+		return c.Error
+	}
+	var texts []string
+	for _, item := range c.Result.Content {
+		if text, ok := item["text"].(string); ok {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return "tool returned an error"
+	}
+	return strings.Join(texts, "\n")
+}
+
+// normalizeJSON converts a value into its generic JSON representation (maps, slices, float64, etc.)
+func normalizeJSON(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n any
+	if err := json.Unmarshal(b, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+// diffValues appends the differences between two JSON values to changes, located by their path.
+func diffValues(path string, before, after any, changes *[]types.ResultChange) {
+	if len(*changes) >= maxResultChanges {
+		return
+	}
+	switch b := before.(type) {
+	case map[string]any:
+		a, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(b)+len(a))
+		for k := range b {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := b[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffValues(p, b[k], a[k], changes)
+		}
+		return
+	case []any:
+		a, ok := after.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(b), len(a)) {
+			var bi, ai any
+			if i < len(b) {
+				bi = b[i]
+			}
+			if i < len(a) {
+				ai = a[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), bi, ai, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, types.ResultChange{Path: path, Before: before, After: after})
+	}
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+func TestReplaySuite(t *testing.T) {
+	m := newTestMCPService(t)
+	db := m.db
+	m.policyService = policy.NewPolicyService(db)
+	m.usageService = usage.NewUsageService(db, nil, nil)
+
+	// the "upgraded" version of the server returns a different greeting and fails to echo
+	var upgraded atomic.Bool
+	upstreamServer := server.NewMCPServer("greeter", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(
+		mcp.NewTool("greet"),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if upgraded.Load() {
+				return mcp.NewToolResultText("Hi " + req.GetString("name", "")), nil
+			}
+			return mcp.NewToolResultText("Hello " + req.GetString("name", "")), nil
+		},
+	)
+	upstreamServer.AddTool(
+		mcp.NewTool("echo"),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if upgraded.Load() {
+				return mcp.NewToolResultError("echo is broken"), nil
+			}
+			return mcp.NewToolResultText(req.GetString("text", "")), nil
+		},
+	)
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("greeter", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	for _, c := range []*model.SavedCall{
+		{Name: "greet-alice", Tool: "greeter__greet", Args: datatypes.JSON(`{"name":"alice"}`)},
+		{Name: "echo-hello", Tool: "greeter__echo", Args: datatypes.JSON(`{"text":"hello"}`)},
+	} {
+		if err := m.CreateSavedCall(c); err != nil {
+			t.Fatalf("CreateSavedCall() error = %v", err)
+		}
+	}
+
+	suite, err := m.CreateReplaySuite(context.Background(), &types.CreateReplaySuiteInput{Name: "baseline", Server: "greeter"})
+	if err != nil {
+		t.Fatalf("CreateReplaySuite() error = %v", err)
+	}
+	if calls := suite.GetCalls(); len(calls) != 2 || calls[0].Failed() || calls[1].Failed() {
+		t.Fatalf("recorded calls = %+v, want 2 successful calls", calls)
+	}
+
+	report, err := m.RunReplaySuite(context.Background(), "baseline", "")
+	if err != nil {
+		t.Fatalf("RunReplaySuite() error = %v", err)
+	}
+	if report.Summary[types.ReplayUnchanged] != 2 || report.HasRegressions() {
+		t.Errorf("replay before the upgrade = %+v, want all calls unchanged", report.Summary)
+	}
+
+	upgraded.Store(true)
+	report, err = m.RunReplaySuite(context.Background(), "baseline", "")
+	if err != nil {
+		t.Fatalf("RunReplaySuite() error = %v", err)
+	}
+	byTool := map[string]types.ReplayedCall{}
+	for _, c := range report.Calls {
+		byTool[c.Tool] = c
+	}
+	greet := byTool["greeter__greet"]
+	if greet.Status != types.ReplayChanged || len(greet.Changes) != 1 || greet.Changes[0].Path != "content[0].text" ||
+		greet.Changes[0].Before != "Hello alice" || greet.Changes[0].After != "Hi alice" {
+		t.Errorf("replayed greet call = %+v, want its text to have changed", greet)
+	}
+	if echo := byTool["greeter__echo"]; echo.Status != types.ReplayNewError || echo.Error != "echo is broken" {
+		t.Errorf("replayed echo call = %+v, want a new error", echo)
+	}
+}
@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// RegionNetwork maps the callers connecting from a network to the region they are in.
+type RegionNetwork struct {
+	Prefix netip.Prefix
+	Region string
+}
+
+// SetRegionNetworks sets the networks used to locate the callers whose MCP client has no region attribute,
+// by the IP address they connect from. The most specific network containing the address wins.
+func (m *MCPService) SetRegionNetworks(networks []RegionNetwork) {
+	networks = append([]RegionNetwork(nil), networks...)
+	sort.SliceStable(networks, func(i, j int) bool { return networks[i].Prefix.Bits() > networks[j].Prefix.Bits() })
+	m.regionNetworks = networks
+}
+
+// registerReplica registers an MCP server as a replica of another server, deployed in another region.
+// The replica's tools are not registered, but the ones of the primary server that it doesn't provide are logged,
+// since calls routed to the replica would fail.
+func (m *MCPService) registerReplica(ctx context.Context, s *model.McpServer) error {
+	primary, err := m.GetMcpServer(s.ReplicaOf)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("MCP server %s that %s is a replica of does not exist", s.ReplicaOf, s.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get MCP server %s from DB: %w", s.ReplicaOf, err)
+	}
+	if primary.ReplicaOf != "" {
+		return fmt.Errorf("MCP server %s is itself a replica of %s, replicas must refer to the primary server", primary.Name, primary.ReplicaOf)
+	}
+	if s.Region == "" {
+		return errors.New("a replica requires a region")
+	}
+	replicas, err := m.serverReplicas(primary.Name)
+	if err != nil {
+		return err
+	}
+	for _, r := range append(replicas, primary) {
+		if r.Region == s.Region {
+			return fmt.Errorf("MCP server %s already serves the tools of %s in region '%s'", r.Name, primary.Name, s.Region)
+		}
+	}
+
+	mcpClient, initResult, err := m.connectMcpServer(ctx, s)
+	if err != nil {
+		return err
+	}
+	defer mcpClient.Close()
+	recordServerCapabilities(s, initResult)
+
+	serverTools, err := listServerTools(ctx, s, mcpClient)
+	if err != nil {
+		return fmt.Errorf("failed to list the tools of MCP server %s: %w", s.Name, err)
+	}
+	provided := make(map[string]bool, len(serverTools))
+	for _, t := range serverTools {
+		provided[t.GetName()] = true
+	}
+	tools, err := m.ListToolsByServer(primary.Name)
+	if err != nil {
+		return err
+	}
+	for _, t := range tools {
+		if _, name, _ := splitServerToolName(t.Name); !provided[name] {
+			log.Printf("[WARN] replica %s of MCP server %s does not provide the tool %s", s.Name, primary.Name, name)
+		}
+	}
+
+	if err := m.db.Create(s).Error; err != nil {
+		return fmt.Errorf("failed to register mcp server: %w", err)
+	}
+	log.Printf("[INFO] registered MCP server %s as the replica of %s in region '%s'", s.Name, primary.Name, s.Region)
+	m.eventBus.Publish(ctx, events.Event{Type: events.ServerRegistered, Server: s.Name})
+	return nil
+}
+
+// serverReplicas returns the replicas of an MCP server, sorted by name.
+func (m *MCPService) serverReplicas(name string) ([]*model.McpServer, error) {
+	var replicas []*model.McpServer
+	if err := m.db.Where("replica_of = ?", name).Order("name").Find(&replicas).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the replicas of MCP server %s: %w", name, err)
+	}
+	return replicas, nil
+}
+
+// callerRegion returns the region of the caller of a tool: the region attribute of its MCP client if it has one,
+// otherwise the region of the network it connects from. It returns an empty string if the region is unknown.
+func (m *MCPService) callerRegion(ctx context.Context) string {
+	if c, ok := ctx.Value("client").(*model.McpClient); ok && c != nil {
+		if region := c.GetAttributes()[types.ClientRegionAttribute]; region != "" {
+			return region
+		}
+	}
+	ip, err := netip.ParseAddr(callerIP(ctx))
+	if err != nil {
+		return ""
+	}
+	ip = ip.Unmap()
+	for _, n := range m.regionNetworks {
+		if n.Prefix.Contains(ip) {
+			return n.Region
+		}
+	}
+	return ""
+}
+
+// callerIP returns the IP address that the caller of a tool connects from, if known.
+func callerIP(ctx context.Context) string {
+	ip, _ := ctx.Value("client_ip").(string)
+	return ip
+}
+
+// closestReplica returns the server that serves a call to a tool of server s: the one of s and its replicas
+// whose region is the closest to the caller's, amongst the ones in a region allowed by the caller's residency policies.
+// Replicas that are stopped, unhealthy or restarting are skipped. s itself is returned if it has no replicas,
+// if the caller's region is unknown, or if no replica is closer to the caller than s.
+func (m *MCPService) closestReplica(ctx context.Context, s *model.McpServer, allowed func(region string) bool) (*model.McpServer, error) {
+	replicas, err := m.serverReplicas(s.Name)
+	if err != nil || len(replicas) == 0 {
+		return s, err
+	}
+	region := m.callerRegion(ctx)
+	if region == "" {
+		return s, nil
+	}
+
+	best, bestAffinity := s, 0
+	if allowed(s.Region) {
+		bestAffinity = regionAffinity(region, s.Region)
+	}
+	for _, r := range replicas {
+		if r.Stopped || !allowed(r.Region) || m.checkServerAvailable(r.Name) != nil {
+			continue
+		}
+		if h := m.health.get(r.Name); h != nil && h.Status == types.ServerUnhealthy {
+			continue
+		}
+		if a := regionAffinity(region, r.Region); a > bestAffinity {
+			best, bestAffinity = r, a
+		}
+	}
+	if best != s {
+		metrics.UpstreamReplicaCalls.WithLabelValues(s.Name, best.Region).Inc()
+	}
+	return best, nil
+}
+
+// regionAffinity measures how close two regions are: the number of leading segments of their names they have
+// in common (eg- 2 for eu-west-1 and eu-west-2, 1 for eu-west and eu-central), plus one if they are the same region.
+// It is 0 for unrelated regions.
+func regionAffinity(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	as, bs := strings.Split(a, "-"), strings.Split(b, "-")
+	n := 0
+	for n < len(as) && n < len(bs) && as[n] == bs[n] {
+		n++
+	}
+	if a == b {
+		n++
+	}
+	return n
+}
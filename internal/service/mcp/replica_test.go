@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/datatypes"
+)
+
+func TestClosestReplica(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{}, &model.ResidencyPolicy{})
+	db := m.db
+	m.SetRegionNetworks([]RegionNetwork{
+		{Prefix: netip.MustParsePrefix("10.1.0.0/16"), Region: "eu-central"},
+		{Prefix: netip.MustParsePrefix("10.1.2.0/24"), Region: "ap-south"},
+	})
+
+	primary := &model.McpServer{Name: "search", Region: "us-east", Transport: "streamable_http", Config: datatypes.JSON(`{}`)}
+	if err := db.Create(primary).Error; err != nil {
+		t.Fatal(err)
+	}
+	for name, region := range map[string]string{"search-eu": "eu-west", "search-ap": "ap-south"} {
+		r := &model.McpServer{Name: name, Region: region, ReplicaOf: "search", Transport: "streamable_http", Config: datatypes.JSON(`{}`)}
+		if err := db.Create(r).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clientIn := func(region string) context.Context {
+		c := &model.McpClient{Name: "agent", AllowList: datatypes.JSON(`["search"]`), Attributes: datatypes.JSON(`{"region": "` + region + `"}`)}
+		return context.WithValue(context.Background(), "client", c)
+	}
+	connectingFrom := func(ip string) context.Context {
+		return context.WithValue(context.Background(), "client_ip", ip)
+	}
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"client region", clientIn("eu-west"), "search-eu"},
+		{"client region of the primary", clientIn("us-east"), "search"},
+		{"closest region", clientIn("eu-north"), "search-eu"},
+		{"unrelated region", clientIn("sa-east"), "search"},
+		{"most specific network", connectingFrom("10.1.2.3"), "search-ap"},
+		{"network", connectingFrom("10.1.9.9"), "search-eu"},
+		{"unknown network", connectingFrom("192.168.1.1"), "search"},
+		{"unknown caller", context.Background(), "search"},
+	}
+	for _, tc := range cases {
+		got, err := m.resolveServerForCall(tc.ctx, primary, "query")
+		if err != nil || got.Name != tc.want {
+			t.Errorf("%s: resolveServerForCall() = %v, %v; want %s", tc.name, got, err, tc.want)
+		}
+	}
+
+	// unhealthy replicas are skipped
+	m.setServerHealth("search-eu", false, 0, errors.New("connection refused"))
+	if got, err := m.resolveServerForCall(clientIn("eu-west"), primary, "query"); err != nil || got.Name != "search" {
+		t.Errorf("resolveServerForCall() with an unhealthy replica = %v, %v; want search", got, err)
+	}
+	m.setServerHealth("search-eu", true, 0, nil)
+
+	// replicas in regions that the caller's residency policies don't allow are skipped
+	err := m.CreateResidencyPolicy(&model.ResidencyPolicy{
+		Name:           "no-eu",
+		ClientName:     "agent",
+		AllowedRegions: datatypes.JSON(`["us-east", "ap-south"]`),
+		Action:         model.ResidencyActionReject,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.resolveServerForCall(clientIn("eu-west"), primary, "query"); err != nil || got.Name != "search" {
+		t.Errorf("resolveServerForCall() with a replica in a forbidden region = %v, %v; want search", got, err)
+	}
+
+	if err := m.DeregisterMcpServer("search", 0); err == nil {
+		t.Error("expected an error when deregistering a server that has replicas")
+	}
+	for _, s := range []*model.McpServer{
+		{Name: "search-us", Region: "us-west", ReplicaOf: "missing"},
+		{Name: "search-us", ReplicaOf: "search"},
+		{Name: "search-us", Region: "us-west", ReplicaOf: "search-eu"},
+		{Name: "search-us", Region: "ap-south", ReplicaOf: "search"},
+	} {
+		if err := m.RegisterMcpServer(context.Background(), s); err == nil {
+			t.Errorf("expected an error when registering %s in region '%s' as a replica of %s", s.Name, s.Region, s.ReplicaOf)
+		}
+	}
+}
+
+func TestRegionAffinity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"eu-west", "eu-west", 3},
+		{"eu-west-1", "eu-west-2", 2},
+		{"eu-west", "EU-Central", 1},
+		{"eu-west", "us-east", 0},
+		{"eu-west", "", 0},
+	}
+	for _, tc := range cases {
+		if got := regionAffinity(tc.a, tc.b); got != tc.want {
+			t.Errorf("regionAffinity(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// ErrResidencyViolation is returned when a tool call is rejected by a data residency policy
+var ErrResidencyViolation = errors.New("data residency policy violation")
+
+// CreateResidencyPolicy creates a new data residency policy.
+func (m *MCPService) CreateResidencyPolicy(p *model.ResidencyPolicy) error {
+	if p.Name == "" {
+		return errors.New("residency policy name is required")
+	}
+	return m.db.Create(p).Error
+}
+
+// ListResidencyPolicies returns all data residency policies.
+func (m *MCPService) ListResidencyPolicies() ([]*model.ResidencyPolicy, error) {
+	var policies []*model.ResidencyPolicy
+	if err := m.db.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// DeleteResidencyPolicy deletes a data residency policy by name.
+// It is an idempotent operation. Deleting a policy that does not exist will not return an error.
+func (m *MCPService) DeleteResidencyPolicy(name string) error {
+	return m.db.Unscoped().Where("name = ?", name).Delete(&model.ResidencyPolicy{}).Error
+}
+
+// resolveServerForCall applies the caller's data residency policies to a call of the given tool on server s.
+// It returns the server that must serve the call.
+// This is a different server than s if the call was routed to the replica of s closest to the caller,
+// or rerouted to an allowed region.
+func (m *MCPService) resolveServerForCall(ctx context.Context, s *model.McpServer, toolName string) (*model.McpServer, error) {
+	policies, err := m.callerResidencyPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed := func(region string) bool {
+		return regionAllowed(policies, region)
+	}
+	if s, err = m.closestReplica(ctx, s, allowed); err != nil {
+		return nil, err
+	}
+	if allowed(s.Region) {
+		return s, nil
+	}
+
+	// the server violates at least one policy, reject the call unless all violated policies permit rerouting
+	for _, p := range policies {
+		if !p.AllowsRegion(s.Region) && p.Action != model.ResidencyActionReroute {
+			return nil, fmt.Errorf(
+				"%w: policy %s does not allow calls to MCP server %s in region '%s'",
+				ErrResidencyViolation, p.Name, s.Name, s.Region,
+			)
+		}
+	}
+
+	// the replicas of s serve the same tools, they are the first candidates
+	candidates, err := m.serverReplicas(s.Name)
+	if err != nil {
+		return nil, err
+	}
+	equivalent, err := m.equivalentServers(s, toolName)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, equivalent...)
+	c, _ := ctx.Value("client").(*model.McpClient)
+	for _, candidate := range candidates {
+		if !allowed(candidate.Region) {
+			continue
+		}
+		// access to s grants access to its replicas
+		if c != nil && candidate.ReplicaOf != s.Name && !c.CheckHasServerAccess(candidate.Name) {
+			continue
+		}
+		log.Printf(
+			"[INFO] rerouting call to tool %s from MCP server %s (%s) to %s (%s) due to data residency policy",
+			toolName, s.Name, s.Region, candidate.Name, candidate.Region,
+		)
+		return candidate, nil
+	}
+	return nil, fmt.Errorf(
+		"%w: MCP server %s in region '%s' is not allowed and no equivalent server was found in an allowed region",
+		ErrResidencyViolation, s.Name, s.Region,
+	)
+}
+
+// regionAllowed returns true if all the given residency policies allow calls to servers in the region.
+func regionAllowed(policies []*model.ResidencyPolicy, region string) bool {
+	for _, p := range policies {
+		if !p.AllowsRegion(region) {
+			return false
+		}
+	}
+	return true
+}
+
+// callerResidencyPolicies returns the residency policies that apply to the MCP client or user making a tool call.
+func (m *MCPService) callerResidencyPolicies(ctx context.Context) ([]*model.ResidencyPolicy, error) {
+	clientName, username := callerIdentity(ctx)
+	if clientName == "" && username == "" {
+		return nil, nil
+	}
+	q := m.db
+	switch {
+	case clientName != "" && username != "":
+		q = q.Where("client_name = ? OR username = ?", clientName, username)
+	case clientName != "":
+		q = q.Where("client_name = ?", clientName)
+	default:
+		q = q.Where("username = ?", username)
+	}
+	var policies []*model.ResidencyPolicy
+	if err := q.Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to get residency policies: %w", err)
+	}
+	return policies, nil
+}
+
+// equivalentServers returns the servers other than s that provide an enabled tool with the given name.
+func (m *MCPService) equivalentServers(s *model.McpServer, toolName string) ([]*model.McpServer, error) {
+	var servers []*model.McpServer
+	err := m.db.
+		Joins("JOIN tools ON tools.server_id = mcp_servers.id AND tools.deleted_at IS NULL").
+		Where("tools.name = ? AND tools.enabled = ? AND mcp_servers.id <> ?", toolName, true, s.ID).
+		Order("mcp_servers.name").
+		Find(&servers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find servers equivalent to %s: %w", s.Name, err)
+	}
+	return servers, nil
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/datatypes"
+)
+
+func TestResolveServerForCall(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{}, &model.ResidencyPolicy{})
+	db := m.db
+
+	us := &model.McpServer{Name: "search-us", Region: "us-east", Transport: "streamable_http", Config: datatypes.JSON(`{}`)}
+	eu := &model.McpServer{Name: "search-eu", Region: "eu-west", Transport: "streamable_http", Config: datatypes.JSON(`{}`)}
+	for _, s := range []*model.McpServer{us, eu} {
+		if err := db.Create(s).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Create(&model.Tool{Name: "query", ServerID: s.ID, Enabled: true}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &model.McpClient{Name: "agent", AllowList: datatypes.JSON(`["search-us", "search-eu"]`)}
+	ctx := context.WithValue(context.Background(), "client", client)
+
+	// without any policy, the call is served by the requested server
+	got, err := m.resolveServerForCall(ctx, us, "query")
+	if err != nil || got.Name != "search-us" {
+		t.Fatalf("resolveServerForCall() = %v, %v; want search-us", got, err)
+	}
+
+	policy := &model.ResidencyPolicy{
+		Name:           "eu-only",
+		ClientName:     "agent",
+		AllowedRegions: datatypes.JSON(`["eu-west"]`),
+		Action:         model.ResidencyActionReject,
+	}
+	if err := m.CreateResidencyPolicy(policy); err != nil {
+		t.Fatalf("CreateResidencyPolicy() error = %v", err)
+	}
+	if _, err := m.resolveServerForCall(ctx, us, "query"); !errors.Is(err, ErrResidencyViolation) {
+		t.Errorf("resolveServerForCall() error = %v, want ErrResidencyViolation", err)
+	}
+
+	policy.Action = model.ResidencyActionReroute
+	if err := db.Save(policy).Error; err != nil {
+		t.Fatal(err)
+	}
+	got, err = m.resolveServerForCall(ctx, us, "query")
+	if err != nil || got.Name != "search-eu" {
+		t.Errorf("resolveServerForCall() = %v, %v; want call rerouted to search-eu", got, err)
+	}
+}
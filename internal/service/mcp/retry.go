@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"io"
+	"log"
+	"net"
+	"syscall"
+	"time"
+)
+
+// callUpstreamTool connects to an MCP server and calls one of its tools.
+// name is the canonical name of the tool, the request must contain the tool's name without the server prefix.
+// Failed calls are retried according to the server's retry policy, if it has one.
+// It returns the result of the last attempt along with the number of attempts made.
+func (m *MCPService) callUpstreamTool(
+	ctx context.Context, s *model.McpServer, name string, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, int, error) {
+	done, err := m.calls.begin(s.Name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot call tool %s: %w", name, err)
+	}
+	defer done()
+	if err := m.checkServerAvailable(s.Name); err != nil {
+		return nil, 0, fmt.Errorf("cannot call tool %s: %w", name, err)
+	}
+
+	policy, err := s.GetRetryPolicy()
+	if err != nil {
+		log.Printf("[ERROR] ignoring invalid retry policy of MCP server %s: %v", s.Name, err)
+		policy = nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, class, reached, err := m.callToolOnce(ctx, s, request)
+		if reached {
+			m.setServerHealth(s.Name, true, 0, nil)
+		}
+		if class == "" || policy == nil || attempt >= policy.MaxAttempts || !policy.Retries(class) || ctx.Err() != nil {
+			if class == types.RetryOnConnection && ctx.Err() == nil {
+				m.notifyServerUnhealthy(s, err)
+			}
+			return resp, attempt, err
+		}
+		// Once a call may have reached the tool, retrying it is only safe if the tool is idempotent
+		if reached && !policy.RetryNonIdempotent && !m.isIdempotentTool(name) {
+			return resp, attempt, err
+		}
+
+		metrics.UpstreamToolCallRetries.WithLabelValues(s.Name, class).Inc()
+		delay := policy.Delay(attempt)
+		log.Printf("[INFO] retrying call to tool %s in %s after %s failure (attempt %d of %d)",
+			name, delay, class, attempt, policy.MaxAttempts)
+
+		select {
+		case <-ctx.Done():
+			return resp, attempt, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// notifyServerUnhealthy notifies admins that mcpjungle failed to connect to an MCP server.
+func (m *MCPService) notifyServerUnhealthy(s *model.McpServer, err error) {
+	m.setServerHealth(s.Name, false, 0, err)
+	if m.notifications == nil {
+		return
+	}
+	m.notifications.Notify(
+		types.NotificationServerUnhealthy, s.Name, fmt.Sprintf("failed to connect to MCP server %s: %v", s.Name, err),
+	)
+}
+
+// callToolOnce makes a single attempt at calling a tool.
+// Faults are injected into the attempt according to the fault rules, if fault injection is enabled.
+// Along with the result, it returns the class of the failure (empty if the call succeeded or failed permanently)
+// and whether the request was sent to the tool, ie, whether the connection to the server was established.
+func (m *MCPService) callToolOnce(
+	ctx context.Context, s *model.McpServer, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, string, bool, error) {
+	fault := m.pickFault(s, request.Params.Name)
+	if fault != nil {
+		resp, err := injectFault(ctx, fault)
+		if err != nil {
+			return nil, classifyCallError(err), false, err
+		}
+		if resp != nil {
+			return resp, types.RetryOnToolError, true, nil
+		}
+	}
+
+	mcpClient, release, err := m.upstreamSession(ctx, s)
+	if errors.Is(err, ErrServerIdentityChanged) {
+		// retrying can't help, the server stays blocked until it is re-pinned
+		return nil, "", false, err
+	}
+	if err != nil {
+		return nil, types.RetryOnConnection, false, err
+	}
+	defer release()
+
+	resp, err := mcpClient.CallTool(ctx, request)
+	if err != nil {
+		class := classifyCallError(err)
+		if class == types.RetryOnConnection {
+			// a broken warm connection must be re-established by the next call
+			m.warm.discard(s.Name, mcpClient)
+		}
+		return nil, class, true, err
+	}
+	if fault != nil && fault.Fault == model.FaultDrop {
+		return nil, types.RetryOnTimeout, true, errFaultDropped
+	}
+	if resp.IsError {
+		return resp, types.RetryOnToolError, true, nil
+	}
+	return resp, "", true, nil
+}
+
+// classifyCallError returns the class of a transient tool call failure.
+// It returns an empty string for errors that are not transient, eg- a JSON-RPC error returned by the server.
+func classifyCallError(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return types.RetryOnTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, new(*net.OpError)) {
+		return types.RetryOnConnection
+	}
+	return ""
+}
+
+// isIdempotentTool returns true if the tool is annotated as idempotent or read-only by its MCP server.
+func (m *MCPService) isIdempotentTool(name string) bool {
+	t, err := m.GetTool(name)
+	if err != nil {
+		return false
+	}
+	return toolIsIdempotent(t)
+}
+
+// toolIsIdempotent returns true if calling the tool multiple times has the same effect as calling it once,
+// as indicated by its annotations.
+func toolIsIdempotent(t *model.Tool) bool {
+	if len(t.Annotations) == 0 {
+		return false
+	}
+	var a types.ToolAnnotations
+	if err := json.Unmarshal(t.Annotations, &a); err != nil {
+		return false
+	}
+	return (a.IdempotentHint != nil && *a.IdempotentHint) || (a.ReadOnlyHint != nil && *a.ReadOnlyHint)
+}
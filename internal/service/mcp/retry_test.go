@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestCallUpstreamToolRetriesIdempotentTools(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	// both tools fail on their first two calls
+	var lookups, charges atomic.Int32
+	flaky := func(counter *atomic.Int32) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if counter.Add(1) <= 2 {
+				return mcp.NewToolResultError("temporarily unavailable"), nil
+			}
+			return mcp.NewToolResultText("ok"), nil
+		}
+	}
+	upstreamServer := server.NewMCPServer("flaky", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("lookup", mcp.WithIdempotentHintAnnotation(true)), flaky(&lookups))
+	upstreamServer.AddTool(mcp.NewTool("charge", mcp.WithIdempotentHintAnnotation(false)), flaky(&charges))
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("flaky", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &types.RetryPolicy{MaxAttempts: 3, Backoff: "1ms", RetryOn: []string{types.RetryOnToolError}}
+	if err := s.SetRetryPolicy(policy); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	call := func(tool string) (*mcp.CallToolResult, int) {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		resp, attempts, err := m.callUpstreamTool(context.Background(), s, mergeServerToolNames("flaky", tool), req)
+		if err != nil {
+			t.Fatalf("callUpstreamTool(%s) error = %v", tool, err)
+		}
+		return resp, attempts
+	}
+
+	if resp, attempts := call("lookup"); resp.IsError || attempts != 3 {
+		t.Errorf("lookup: isError = %v, attempts = %d, want success after 3 attempts", resp.IsError, attempts)
+	}
+	// the non-idempotent tool must not be retried
+	if resp, attempts := call("charge"); !resp.IsError || attempts != 1 {
+		t.Errorf("charge: isError = %v, attempts = %d, want error after 1 attempt", resp.IsError, attempts)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := &types.RetryPolicy{MaxAttempts: 5, Backoff: "100ms", MaxBackoff: "300ms"}
+	want := []string{"100ms", "200ms", "300ms", "300ms"}
+	for i, w := range want {
+		if got := p.Delay(i + 1).String(); got != w {
+			t.Errorf("Delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// CreateSavedCall saves a named set of arguments for a tool in the registry.
+func (m *MCPService) CreateSavedCall(s *model.SavedCall) error {
+	if err := validateServerName(s.Name); err != nil {
+		return fmt.Errorf("invalid saved call name: %w", err)
+	}
+	if _, err := m.GetTool(s.Tool); err != nil {
+		return fmt.Errorf("tool %s not found: %w", s.Tool, err)
+	}
+	if err := m.db.Create(s).Error; err != nil {
+		return fmt.Errorf("failed to create saved call: %w", err)
+	}
+	return nil
+}
+
+// ListSavedCalls returns all saved calls.
+func (m *MCPService) ListSavedCalls() ([]*model.SavedCall, error) {
+	var calls []*model.SavedCall
+	if err := m.db.Order("name").Find(&calls).Error; err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// GetSavedCall returns a saved call by name.
+func (m *MCPService) GetSavedCall(name string) (*model.SavedCall, error) {
+	var s model.SavedCall
+	if err := m.db.Where("name = ?", name).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// DeleteSavedCall deletes a saved call by name.
+// It is an idempotent operation. Deleting a saved call that does not exist will not return an error.
+func (m *MCPService) DeleteSavedCall(name string) error {
+	return m.db.Unscoped().Where("name = ?", name).Delete(&model.SavedCall{}).Error
+}
+
+// InvokeSavedCall invokes the tool of a saved call with its saved arguments.
+// The given args are merged into the saved arguments, taking precedence over them.
+// The invocation is subject to the same checks as a regular tool invocation by the caller.
+func (m *MCPService) InvokeSavedCall(ctx context.Context, name string, args map[string]any) (*types.ToolInvokeResult, error) {
+	s, err := m.GetSavedCall(name)
+	if err != nil {
+		return nil, fmt.Errorf("saved call %s not found: %w", name, err)
+	}
+	merged := s.GetArgs()
+	if merged == nil {
+		merged = make(map[string]any, len(args))
+	}
+	maps.Copy(merged, args)
+	return m.InvokeTool(ctx, s.Tool, merged)
+}
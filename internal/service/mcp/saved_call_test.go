@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"gorm.io/datatypes"
+)
+
+func TestInvokeSavedCall(t *testing.T) {
+	m := newTestMCPService(t)
+	db := m.db
+	m.policyService = policy.NewPolicyService(db)
+	m.usageService = usage.NewUsageService(db, nil, nil)
+
+	upstreamServer := server.NewMCPServer("ci", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(
+		mcp.NewTool("deploy", mcp.WithDescription("Deploys a branch")),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			b, _ := json.Marshal(req.GetArguments())
+			return mcp.NewToolResultText(string(b)), nil
+		},
+	)
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("ci", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	missing := &model.SavedCall{Name: "typo", Tool: "ci__deplyo"}
+	if err := m.CreateSavedCall(missing); err == nil {
+		t.Error("CreateSavedCall() for an unknown tool succeeded, want error")
+	}
+	call := &model.SavedCall{
+		Name: "deploy-prod",
+		Tool: "ci__deploy",
+		Args: datatypes.JSON(`{"env":"prod","branch":"main"}`),
+	}
+	if err := m.CreateSavedCall(call); err != nil {
+		t.Fatalf("CreateSavedCall() error = %v", err)
+	}
+
+	res, err := m.InvokeSavedCall(context.Background(), "deploy-prod", map[string]any{"branch": "hotfix"})
+	if err != nil {
+		t.Fatalf("InvokeSavedCall() error = %v", err)
+	}
+	if res.IsError || len(res.Content) != 1 || res.Content[0]["text"] != `{"branch":"hotfix","env":"prod"}` {
+		t.Errorf("InvokeSavedCall() = %+v, want the saved arguments with branch overridden", res)
+	}
+
+	if _, err := m.InvokeSavedCall(context.Background(), "unknown", nil); err == nil {
+		t.Error("InvokeSavedCall() for an unknown saved call succeeded, want error")
+	}
+}
@@ -0,0 +1,211 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// schemaDriftTimeout is the maximum time spent fetching the tools of a single MCP server during a drift check
+const schemaDriftTimeout = 30 * time.Second
+
+// SchemaDrift describes the incompatible changes made by an upstream MCP server to the input schema of a tool.
+type SchemaDrift struct {
+	// Tool is the canonical name of the tool
+	Tool    string
+	Changes []string
+	// Disabled is true if the tool was disabled because of the drift
+	Disabled bool
+}
+
+// RunSchemaDriftCheck compares the input schemas of the registered tools with the ones currently served by
+// their MCP servers every interval until ctx is done, see CheckSchemaDrift.
+func (m *MCPService) RunSchemaDriftCheck(ctx context.Context, interval time.Duration, autoDisable bool) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			if _, err := m.CheckSchemaDrift(ctx, autoDisable); err != nil {
+				log.Printf("[WARN] tool schema drift check failed: %v", err)
+			}
+		}
+	}
+}
+
+// CheckSchemaDrift fetches the tools of all MCP servers and compares their input schemas with the stored ones.
+// The stored schemas are updated to the current ones, so that MCP clients are served accurate schemas.
+// Incompatible changes (a removed tool or argument, an argument that became required or changed type)
+// are reported to admins and, if autoDisable is true, the affected tools are disabled until an admin reviews them.
+// Servers that can't be reached are skipped.
+func (m *MCPService) CheckSchemaDrift(ctx context.Context, autoDisable bool) ([]SchemaDrift, error) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return nil, err
+	}
+	var drifts []SchemaDrift
+	for i := range servers {
+		// replicas have no tools of their own
+		if servers[i].Stopped || servers[i].ReplicaOf != "" {
+			continue
+		}
+		d, err := m.checkServerSchemaDrift(ctx, &servers[i], autoDisable)
+		if err != nil {
+			log.Printf("[WARN] skipping schema drift check of MCP server %s: %v", servers[i].Name, err)
+			continue
+		}
+		drifts = append(drifts, d...)
+	}
+	return drifts, nil
+}
+
+func (m *MCPService) checkServerSchemaDrift(ctx context.Context, s *model.McpServer, autoDisable bool) ([]SchemaDrift, error) {
+	ctx, cancel := context.WithTimeout(ctx, schemaDriftTimeout)
+	defer cancel()
+	c, err := m.connectUpstream(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	upstreamTools, err := listServerTools(ctx, s, c)
+	_ = c.Close()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]mcp.Tool, len(upstreamTools))
+	for _, t := range upstreamTools {
+		current[t.GetName()] = t
+	}
+
+	var stored []model.Tool
+	if err := m.db.Where("server_id = ?", s.ID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	var drifts []SchemaDrift
+	for i := range stored {
+		tool := &stored[i]
+		name := mergeServerToolNames(s.Name, tool.Name)
+		var changes []string
+		upstream, ok := current[tool.Name]
+		if !ok {
+			if _, reported := m.missingTools.LoadOrStore(name, true); reported {
+				continue
+			}
+			changes = []string{"the tool is no longer provided by its MCP server"}
+		} else {
+			m.missingTools.Delete(name)
+			schema := newToolModel(s, upstream).InputSchema
+			if sameJSON(tool.InputSchema, schema) {
+				continue
+			}
+			changes = incompatibleSchemaChanges(tool.InputSchema, schema)
+			if err := m.updateToolSchema(tool, name, schema); err != nil {
+				log.Printf("[WARN] failed to update the input schema of tool %s: %v", name, err)
+				continue
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		d := SchemaDrift{Tool: name, Changes: changes}
+		if autoDisable && tool.Enabled {
+			if _, err := m.DisableTools(name, 0); err != nil {
+				log.Printf("[WARN] failed to disable tool %s after its schema drifted: %v", name, err)
+			} else {
+				d.Disabled = true
+			}
+		}
+		m.reportSchemaDrift(s.Name, d)
+		drifts = append(drifts, d)
+	}
+	return drifts, nil
+}
+
+// updateToolSchema stores the new input schema of a tool and serves it to MCP clients.
+func (m *MCPService) updateToolSchema(tool *model.Tool, name string, schema []byte) error {
+	if err := m.updateTool(tool, name, 0, map[string]any{"input_schema": schema}); err != nil {
+		return err
+	}
+	tool.InputSchema = schema
+	if !tool.Enabled {
+		return nil
+	}
+	mcpTool, err := convertToolModelToMcpObject(tool)
+	if err != nil {
+		return err
+	}
+	mcpTool.Name = name
+	m.addProxyTool(mcpTool)
+	return nil
+}
+
+func (m *MCPService) reportSchemaDrift(serverName string, d SchemaDrift) {
+	metrics.ToolSchemaDrifts.WithLabelValues(serverName).Inc()
+	msg := fmt.Sprintf("the input schema of tool %s changed incompatibly: %s", d.Tool, strings.Join(d.Changes, "; "))
+	if d.Disabled {
+		msg += " (the tool was disabled)"
+	}
+	log.Printf("[WARN] %s", msg)
+	if m.notifications != nil {
+		m.notifications.Notify(types.NotificationToolSchemaDrift, d.Tool, msg)
+	}
+	m.eventBus.Publish(context.Background(), events.Event{
+		Type: events.ToolSchemaDrifted, Server: serverName, Tool: d.Tool, Error: strings.Join(d.Changes, "; "),
+	})
+}
+
+// jsonSchema is the part of a JSON schema that is relevant to compatibility checks
+type jsonSchema struct {
+	Properties map[string]struct {
+		Type any `json:"type"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+// incompatibleSchemaChanges returns the changes from the old to the new input schema of a tool that can make
+// calls built against the old schema fail: removed arguments, arguments that became required and arguments
+// whose type changed. Other changes (eg- new optional arguments or updated descriptions) are compatible.
+func incompatibleSchemaChanges(oldSchema, newSchema []byte) []string {
+	var before, after jsonSchema
+	if json.Unmarshal(oldSchema, &before) != nil || json.Unmarshal(newSchema, &after) != nil {
+		return []string{"the input schema is not a valid JSON schema"}
+	}
+	var changes []string
+	for name, p := range before.Properties {
+		q, ok := after.Properties[name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("argument %s was removed", name))
+		case p.Type != nil && q.Type != nil && !reflect.DeepEqual(p.Type, q.Type):
+			changes = append(changes, fmt.Sprintf("type of argument %s changed from %v to %v", name, p.Type, q.Type))
+		}
+	}
+	for _, name := range after.Required {
+		if !slices.Contains(before.Required, name) {
+			changes = append(changes, fmt.Sprintf("argument %s became required", name))
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
+
+// sameJSON returns true if a and b are the same JSON value, regardless of formatting and key order.
+func sameJSON(a, b []byte) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestIncompatibleSchemaChanges(t *testing.T) {
+	before := `{"type":"object","properties":{"q":{"type":"string"},"limit":{"type":"integer"},"lang":{"type":"string"}},"required":["q"]}`
+	tests := []struct {
+		name  string
+		after string
+		want  []string
+	}{
+		{
+			name:  "new optional argument and description",
+			after: `{"type":"object","properties":{"q":{"type":"string","description":"query"},"limit":{"type":"integer"},"lang":{"type":"string"},"page":{"type":"integer"}},"required":["q"]}`,
+		},
+		{
+			name:  "removed, retyped and required arguments",
+			after: `{"type":"object","properties":{"q":{"type":"string"},"limit":{"type":"string"}},"required":["q","limit"]}`,
+			want: []string{
+				"argument lang was removed",
+				"argument limit became required",
+				"type of argument limit changed from integer to string",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := incompatibleSchemaChanges([]byte(before), []byte(tt.after))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("incompatibleSchemaChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSchemaDrift(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+	db := m.db
+
+	newUpstream := func(tools ...mcp.Tool) string {
+		s := server.NewMCPServer("search-mcp", "1.0.0", server.WithToolCapabilities(true))
+		for _, tool := range tools {
+			s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			})
+		}
+		upstream := server.NewTestStreamableHTTPServer(s)
+		t.Cleanup(upstream.Close)
+		return upstream.URL + "/mcp"
+	}
+
+	s, err := model.NewStreamableHTTPServer("search", "", newUpstream(
+		mcp.NewTool("web", mcp.WithString("q", mcp.Required()), mcp.WithNumber("limit")),
+		mcp.NewTool("news", mcp.WithString("q")),
+	), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	if drifts, err := m.CheckSchemaDrift(context.Background(), true); err != nil || len(drifts) != 0 {
+		t.Fatalf("CheckSchemaDrift() = %+v, %v without changes", drifts, err)
+	}
+
+	// the server makes limit required and drops the news tool
+	changed, _ := model.NewStreamableHTTPServer("search", "", newUpstream(
+		mcp.NewTool("web", mcp.WithString("q", mcp.Required()), mcp.WithNumber("limit", mcp.Required())),
+	), "")
+	db.Model(&model.McpServer{}).Where("name = ?", "search").Update("config", changed.Config)
+
+	drifts, err := m.CheckSchemaDrift(context.Background(), true)
+	if err != nil {
+		t.Fatalf("CheckSchemaDrift() error = %v", err)
+	}
+	if len(drifts) != 2 || !drifts[0].Disabled || !drifts[1].Disabled {
+		t.Fatalf("CheckSchemaDrift() = %+v, want 2 drifts that disabled their tools", drifts)
+	}
+	tool, err := m.GetTool("search__web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema jsonSchema
+	_ = json.Unmarshal(tool.InputSchema, &schema)
+	if tool.Enabled || !slices.Contains(schema.Required, "limit") {
+		t.Errorf("tool after drift: enabled = %t, required = %v, want disabled with the new schema", tool.Enabled, schema.Required)
+	}
+
+	if drifts, _ := m.CheckSchemaDrift(context.Background(), true); len(drifts) != 0 {
+		t.Errorf("CheckSchemaDrift() = %+v, want drifts to be reported once", drifts)
+	}
+}
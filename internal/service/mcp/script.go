@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/script"
+)
+
+// SetToolScripts attaches CEL scripts to a tool, which transform its arguments before every call and
+// its results after every successful call (see the script package). Empty scripts are removed.
+// If version is not 0, the change is rejected with a *types.VersionConflict if the tool is at another version.
+func (m *MCPService) SetToolScripts(name, argsScript, resultScript string, version int) error {
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return err
+	}
+	if argsScript != "" {
+		if _, err := m.compiledScript(script.Args, argsScript); err != nil {
+			return err
+		}
+	}
+	if resultScript != "" {
+		if _, err := m.compiledScript(script.Result, resultScript); err != nil {
+			return err
+		}
+	}
+	err = m.updateTool(tool, name, version, map[string]any{
+		"args_script":   argsScript,
+		"result_script": resultScript,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set scripts of tool %s: %w", name, err)
+	}
+	return nil
+}
+
+// compiledScript returns the compiled form of a script, compiling it only the first time it is seen.
+func (m *MCPService) compiledScript(kind script.Kind, source string) (*script.Script, error) {
+	key := string(kind) + ":" + source
+	if s, ok := m.scripts.Load(key); ok {
+		return s.(*script.Script), nil
+	}
+	s, err := script.Compile(kind, source)
+	if err != nil {
+		return nil, err
+	}
+	m.scripts.Store(key, s)
+	return s, nil
+}
+
+// applyArgsScript returns the arguments of a call to the tool transformed by its argument script, if any.
+func (m *MCPService) applyArgsScript(ctx context.Context, tool *model.Tool, args map[string]any) (map[string]any, error) {
+	if tool.ArgsScript == "" {
+		return args, nil
+	}
+	s, err := m.compiledScript(script.Args, tool.ArgsScript)
+	if err != nil {
+		return nil, err
+	}
+	clientName, username := callerIdentity(ctx)
+	fields, err := s.Eval(ctx, script.Vars{Client: clientName, User: username, Args: args})
+	if err != nil {
+		return nil, err
+	}
+	return script.Merge(args, fields), nil
+}
+
+// applyResultScript returns the result of a successful call to the tool transformed by its result script, if any.
+func (m *MCPService) applyResultScript(
+	ctx context.Context, name string, args map[string]any, res *mcp.CallToolResult,
+) (*mcp.CallToolResult, error) {
+	if res == nil {
+		return res, nil
+	}
+	tool, err := m.GetTool(name)
+	if err != nil || tool.ResultScript == "" {
+		return res, nil
+	}
+	s, err := m.compiledScript(script.Result, tool.ResultScript)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize result of tool %s: %w", name, err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("failed to serialize result of tool %s: %w", name, err)
+	}
+	clientName, username := callerIdentity(ctx)
+	fields, err := s.Eval(ctx, script.Vars{Client: clientName, User: username, Args: args, Result: result})
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = json.Marshal(script.Merge(result, fields))
+	if err != nil {
+		return nil, fmt.Errorf("result script of tool %s returned an invalid result: %w", name, err)
+	}
+	var transformed mcp.CallToolResult
+	if err := json.Unmarshal(b, &transformed); err != nil {
+		return nil, fmt.Errorf("result script of tool %s returned an invalid result: %w", name, err)
+	}
+	return &transformed, nil
+}
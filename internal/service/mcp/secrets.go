@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/secret"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// SetSecretService makes mcpjungle resolve the references to secrets (${secret:NAME}) in the environment
+// of stdio MCP servers with the given secret store.
+func (m *MCPService) SetSecretService(s *secret.SecretService) {
+	m.secrets = s
+}
+
+// resolveSecrets returns the MCP server to connect to, with the references to secrets in its environment
+// replaced by their values. s itself is left untouched, so the values never end up in the database.
+func (m *MCPService) resolveSecrets(s *model.McpServer) (*model.McpServer, error) {
+	if s.Transport != types.TransportStdio {
+		return s, nil
+	}
+	conf, err := s.GetStdioConfig()
+	if err != nil || len(stdioSecretReferences(conf)) == 0 {
+		// an invalid config is reported when the server is started
+		return s, nil
+	}
+	if m.secrets == nil {
+		return nil, fmt.Errorf("the environment of MCP server %s refers to secrets but there is no secret store", s.Name)
+	}
+	env := make(map[string]string, len(conf.Env))
+	for k, v := range conf.Env {
+		if env[k], err = m.secrets.Resolve(v); err != nil {
+			return nil, fmt.Errorf("failed to resolve variable %s of MCP server %s: %w", k, s.Name, err)
+		}
+	}
+	conf.Env = env
+
+	resolved := *s
+	if resolved.Config, err = json.Marshal(conf); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// ServersUsingSecret returns the MCP servers whose environment refers to the given secret.
+func (m *MCPService) ServersUsingSecret(name string) ([]model.McpServer, error) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return nil, err
+	}
+	var using []model.McpServer
+	for _, s := range servers {
+		if s.Transport != types.TransportStdio {
+			continue
+		}
+		conf, err := s.GetStdioConfig()
+		if err == nil && slices.Contains(stdioSecretReferences(conf), name) {
+			using = append(using, s)
+		}
+	}
+	return using, nil
+}
+
+// ReconnectServersUsingSecret closes the warm connections to the MCP servers whose environment refers to
+// the given secret, so that their processes are restarted with its new value on their next call.
+// Other servers start a new process for every call, which always gets the current value.
+// It returns the names of all the servers using the secret.
+func (m *MCPService) ReconnectServersUsingSecret(name string) ([]string, error) {
+	servers, err := m.ServersUsingSecret(name)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(servers))
+	for _, s := range servers {
+		names = append(names, s.Name)
+		if s.KeepWarm {
+			log.Printf("[INFO] restarting MCP server %s to apply the new value of secret %s", s.Name, name)
+			m.warm.discard(s.Name, nil)
+		}
+	}
+	return names, nil
+}
+
+// stdioSecretReferences returns the names of the secrets referred to in the environment of a stdio server.
+func stdioSecretReferences(conf *model.StdioConfig) []string {
+	var names []string
+	for _, v := range conf.Env {
+		names = append(names, secret.References(v)...)
+	}
+	return names
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/secret"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	m := newTestMCPService(t, &model.Secret{})
+	db := m.db
+	server, err := model.NewStdioServer("openai", "", model.StdioConfig{
+		Command: "npx",
+		Env:     map[string]string{"OPENAI_API_KEY": "${secret:OPENAI_KEY}", "LOG_LEVEL": "debug"},
+	})
+	if err != nil {
+		t.Fatalf("NewStdioServer() error = %v", err)
+	}
+
+	if _, err := m.resolveSecrets(server); err == nil {
+		t.Error("resolveSecrets() succeeded without a secret store")
+	}
+
+	secrets, err := secret.NewSecretService(db, "")
+	if err != nil {
+		t.Fatalf("NewSecretService() error = %v", err)
+	}
+	m.SetSecretService(secrets)
+	if _, err := m.resolveSecrets(server); err == nil {
+		t.Error("resolveSecrets() succeeded with a missing secret")
+	}
+
+	if _, err := secrets.Set("OPENAI_KEY", "sk-1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	resolved, err := m.resolveSecrets(server)
+	if err != nil {
+		t.Fatalf("resolveSecrets() error = %v", err)
+	}
+	conf, _ := resolved.GetStdioConfig()
+	if conf.Env["OPENAI_API_KEY"] != "sk-1" || conf.Env["LOG_LEVEL"] != "debug" {
+		t.Errorf("resolved env = %v", conf.Env)
+	}
+	if conf, _ := server.GetStdioConfig(); conf.Env["OPENAI_API_KEY"] != "${secret:OPENAI_KEY}" {
+		t.Errorf("resolveSecrets() modified the server's config: %v", conf.Env)
+	}
+}
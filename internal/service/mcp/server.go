@@ -3,7 +3,12 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"log"
 )
 
 // RegisterMcpServer registers a new MCP server in the database.
@@ -14,12 +19,16 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 	if err := validateServerName(s.Name); err != nil {
 		return err
 	}
+	if s.ReplicaOf != "" {
+		return m.registerReplica(ctx, s)
+	}
 
-	mcpClient, err := newMcpServerSession(ctx, s)
+	mcpClient, initResult, err := m.connectMcpServer(ctx, s)
 	if err != nil {
 		return err
 	}
 	defer mcpClient.Close()
+	recordServerCapabilities(s, initResult)
 
 	// register the server in the DB
 	if err := m.db.Create(s).Error; err != nil {
@@ -29,35 +38,97 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 	if err = m.registerServerTools(ctx, s, mcpClient); err != nil {
 		return fmt.Errorf("failed to register tools for MCP server %s: %w", s.Name, err)
 	}
+	m.eventBus.Publish(ctx, events.Event{Type: events.ServerRegistered, Server: s.Name})
 	return nil
 }
 
+// PreflightMcpServer test-connects to an MCP server without registering it.
+// It returns the tools that would become available if the server was registered.
+// The returned tools are not stored anywhere, their names are in canonical form.
+func (m *MCPService) PreflightMcpServer(ctx context.Context, s *model.McpServer) ([]model.Tool, error) {
+	if err := validateServerName(s.Name); err != nil {
+		return nil, err
+	}
+	if _, err := m.GetMcpServer(s.Name); err == nil {
+		return nil, fmt.Errorf("MCP server %s already exists", s.Name)
+	}
+
+	mcpClient, err := m.newMcpServerSession(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	defer mcpClient.Close()
+
+	serverTools, err := listServerTools(ctx, s, mcpClient)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]model.Tool, 0, len(serverTools))
+	for _, tool := range serverTools {
+		t := newToolModel(s, tool)
+		t.Name = mergeServerToolNames(s.Name, tool.GetName())
+		t.Enabled = true
+		tools = append(tools, t)
+	}
+	return tools, nil
+}
+
 // DeregisterMcpServer deregisters an MCP server from the database.
 // It also deregisters all the tools registered by the server.
 // If even a singe tool fails to deregister, the server deregistration fails.
 // A deregistered tool is also removed from the MCP proxy server.
-func (m *MCPService) DeregisterMcpServer(name string) error {
+// If version is not 0, the server is only deregistered if it is still at that version,
+// otherwise a *types.VersionConflict is returned.
+// New tool calls to the server are rejected with ErrServerDraining right away, and the server is only torn down
+// once the calls in flight have completed, or the drain timeout expired.
+func (m *MCPService) DeregisterMcpServer(name string, version int) error {
 	s, err := m.GetMcpServer(name)
 	if err != nil {
 		return fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
 	}
-	if err := m.deregisterServerTools(s); err != nil {
-		return fmt.Errorf(
-			"failed to deregister tools for server %s, cannot proceed with server deregistration: %w",
-			name,
-			err,
+	if version == 0 {
+		version = s.Version
+	} else if version != s.Version {
+		// fail before draining the calls of a server that won't be deregistered
+		conflict := &types.VersionConflict{Kind: "server", Name: name, ExpectedVersion: version, CurrentVersion: s.Version}
+		return fmt.Errorf("failed to deregister server %s: %w", name, conflict)
+	}
+	replicas, err := m.serverReplicas(name)
+	if err != nil {
+		return err
+	}
+	if len(replicas) > 0 {
+		return fmt.Errorf("MCP server %s has %d replicas (eg- %s), deregister them first", name, len(replicas), replicas[0].Name)
+	}
+
+	defer m.calls.done(name)
+	if remaining := m.calls.drain(name, m.drainTimeout); remaining > 0 {
+		log.Printf(
+			"[WARN] %d tool calls to MCP server %s did not complete within %s, deregistering it anyway",
+			remaining, name, m.drainTimeout,
 		)
 	}
-	if err := m.db.Unscoped().Delete(s).Error; err != nil {
+	// the server is deleted first, so that its tools are kept if the server was modified concurrently
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := model.DeleteVersioned[model.McpServer](tx, "server", name, s.ID, version); err != nil {
+			return err
+		}
+		return m.deregisterServerTools(tx, s)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to deregister server %s: %w", name, err)
 	}
+	m.warm.discard(name, nil)
+	m.supervisor.forget(name)
+	m.health.delete(name)
+	m.eventBus.Publish(context.Background(), events.Event{Type: events.ServerDeregistered, Server: name})
 	return nil
 }
 
 // ListMcpServers returns all registered MCP servers.
 func (m *MCPService) ListMcpServers() ([]model.McpServer, error) {
 	var servers []model.McpServer
-	if err := m.db.Find(&servers).Error; err != nil {
+	if err := db.ReadReplica(m.db).Find(&servers).Error; err != nil {
 		return nil, err
 	}
 	return servers, nil
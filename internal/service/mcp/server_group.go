@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrServerStopped is returned when calling a tool of an MCP server that was stopped.
+var ErrServerStopped = errors.New("MCP server is stopped")
+
+// ErrServerGroupNotFound is returned when no MCP server belongs to a server group.
+var ErrServerGroupNotFound = errors.New("server group not found")
+
+// StopServerGroup stops all the MCP servers of a group, eg- for scheduled maintenance of a subsystem.
+// The tools of the stopped servers are disabled and their warm connections are torn down (which terminates
+// the processes of stdio servers). mcpjungle doesn't connect to them again until the group is started.
+func (m *MCPService) StopServerGroup(group string) (*types.ServerGroupLifecycleResult, error) {
+	servers, err := m.serverGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.ServerGroupLifecycleResult{Group: group, Servers: []string{}, Tools: []string{}}
+	for _, s := range servers {
+		if s.Stopped {
+			continue
+		}
+		tools, err := m.DisableTools(s.Name, 0)
+		if err != nil {
+			return result, fmt.Errorf("failed to disable the tools of MCP server %s: %w", s.Name, err)
+		}
+		stoppedTools, _ := json.Marshal(tools)
+		err = model.UpdateVersioned[model.McpServer](m.db, "server", s.Name, s.ID, s.Version, map[string]any{
+			"stopped":       true,
+			"stopped_tools": stoppedTools,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to stop MCP server %s: %w", s.Name, err)
+		}
+		m.warm.discard(s.Name, nil)
+
+		log.Printf("[INFO] MCP server %s of group %s was stopped, %d tools disabled", s.Name, group, len(tools))
+		result.Servers = append(result.Servers, s.Name)
+		result.Tools = append(result.Tools, tools...)
+	}
+	return result, nil
+}
+
+// StartServerGroup starts the stopped MCP servers of a group again.
+// The tools that were enabled when a server was stopped are re-enabled, the ones disabled before stay disabled.
+// Servers marked keep_warm are connected to on their first tool call.
+func (m *MCPService) StartServerGroup(group string) (*types.ServerGroupLifecycleResult, error) {
+	servers, err := m.serverGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.ServerGroupLifecycleResult{Group: group, Servers: []string{}, Tools: []string{}}
+	for _, s := range servers {
+		if !s.Stopped {
+			continue
+		}
+		err = model.UpdateVersioned[model.McpServer](m.db, "server", s.Name, s.ID, s.Version, map[string]any{
+			"stopped":       false,
+			"stopped_tools": nil,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to start MCP server %s: %w", s.Name, err)
+		}
+
+		var tools []string
+		if len(s.StoppedTools) > 0 {
+			if err := json.Unmarshal(s.StoppedTools, &tools); err != nil {
+				log.Printf("[WARN] failed to read the tools to re-enable of MCP server %s: %v", s.Name, err)
+			}
+		}
+		for _, name := range tools {
+			if _, err := m.EnableTools(name, 0); err != nil {
+				// the tool may no longer be provided by the server
+				log.Printf("[WARN] failed to re-enable tool %s: %v", name, err)
+				continue
+			}
+			result.Tools = append(result.Tools, name)
+		}
+
+		log.Printf("[INFO] MCP server %s of group %s was started", s.Name, group)
+		result.Servers = append(result.Servers, s.Name)
+	}
+	return result, nil
+}
+
+// serverGroup returns the MCP servers of a group, or an error if the group has none.
+func (m *MCPService) serverGroup(group string) ([]model.McpServer, error) {
+	if group == "" {
+		return nil, errors.New("server group must not be empty")
+	}
+	var servers []model.McpServer
+	if err := m.db.Where("\"group\" = ?", group).Order("name").Find(&servers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list the MCP servers of group %s: %w", group, err)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("%w: no MCP server belongs to group %s", ErrServerGroupNotFound, group)
+	}
+	return servers, nil
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestStopStartServerGroup(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	upstreamServer := server.NewMCPServer("analytics", "0.0.1", server.WithToolCapabilities(true))
+	for _, name := range []string{"query", "export"} {
+		upstreamServer.AddTool(mcp.NewTool(name), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	for _, name := range []string{"warehouse", "reports"} {
+		s, err := model.NewStreamableHTTPServer(name, "", upstream.URL+"/mcp", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Group = "analytics"
+		if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+			t.Fatalf("RegisterMcpServer() error = %v", err)
+		}
+	}
+	// a tool disabled before the group is stopped must stay disabled once it's started
+	if _, err := m.DisableTools("reports__export", 0); err != nil {
+		t.Fatalf("DisableTools() error = %v", err)
+	}
+
+	if _, err := m.StopServerGroup("billing"); !errors.Is(err, ErrServerGroupNotFound) {
+		t.Fatalf("StopServerGroup() of an unknown group error = %v, want ErrServerGroupNotFound", err)
+	}
+
+	res, err := m.StopServerGroup("analytics")
+	if err != nil {
+		t.Fatalf("StopServerGroup() error = %v", err)
+	}
+	if len(res.Servers) != 2 || len(res.Tools) != 3 {
+		t.Fatalf("StopServerGroup() = %+v, want 2 servers and 3 tools stopped", res)
+	}
+	for _, name := range []string{"warehouse__query", "warehouse__export", "reports__query"} {
+		if tool, err := m.GetTool(name); err != nil || tool.Enabled {
+			t.Errorf("tool %s is still enabled after its server was stopped (err = %v)", name, err)
+		}
+	}
+	s, err := m.GetMcpServer("warehouse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.connectUpstream(context.Background(), s); !errors.Is(err, ErrServerStopped) {
+		t.Errorf("connectUpstream() of a stopped server error = %v, want ErrServerStopped", err)
+	}
+
+	// stopping the group again is a no-op
+	if res, err := m.StopServerGroup("analytics"); err != nil || len(res.Servers) != 0 {
+		t.Errorf("StopServerGroup() of a stopped group = %+v, %v, want no server stopped", res, err)
+	}
+
+	res, err = m.StartServerGroup("analytics")
+	if err != nil {
+		t.Fatalf("StartServerGroup() error = %v", err)
+	}
+	if len(res.Servers) != 2 || len(res.Tools) != 3 {
+		t.Fatalf("StartServerGroup() = %+v, want 2 servers and 3 tools started", res)
+	}
+	want := map[string]bool{
+		"warehouse__query":  true,
+		"warehouse__export": true,
+		"reports__query":    true,
+		"reports__export":   false,
+	}
+	for name, enabled := range want {
+		tool, err := m.GetTool(name)
+		if err != nil {
+			t.Fatalf("GetTool() error = %v", err)
+		}
+		if tool.Enabled != enabled {
+			t.Errorf("after the group was started, tool %s enabled = %v, want %v", name, tool.Enabled, enabled)
+		}
+	}
+	if s, err := m.GetMcpServer("warehouse"); err != nil || s.Stopped {
+		t.Errorf("server is still stopped after its group was started (err = %v)", err)
+	}
+}
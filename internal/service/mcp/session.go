@@ -0,0 +1,211 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+const sessionIDPrefix = "mcpj-session-"
+
+// untrackedSessionTTL is how long a session is remembered after its last request when the manager doesn't enforce
+// any limit, so that the sessions that clients abandon without terminating them don't accumulate
+const untrackedSessionTTL = 24 * time.Hour
+
+// Reasons for which the gateway expires a proxy session
+const (
+	SessionExpiredIdle        = "idle_timeout"
+	SessionExpiredMaxLifetime = "max_lifetime"
+)
+
+// sessionNotifier sends notifications to the clients of proxy sessions
+type sessionNotifier interface {
+	SendNotificationToSpecificClient(sessionID string, method string, params map[string]any) error
+}
+
+type proxySession struct {
+	createdAt time.Time
+	lastSeen  time.Time
+
+	// protocolVersion is the MCP protocol version negotiated by the client during initialization
+	protocolVersion string
+}
+
+// SessionManager tracks the sessions of the MCP proxy server.
+// If it has a limit to enforce (see Enforced), it also issues the sessions, as the SessionIdManager of the
+// mcp-go streamable HTTP server, and expires the ones that have been idle for too long or that have exceeded
+// their maximum lifetime, so that abandoned agent sessions don't accumulate.
+// Clients of expired sessions receive a 404 on their next request, which tells them to re-initialize.
+// Sessions are only known to the instance of mcpjungle that issued them, so a deployment with several replicas
+// that enforces limits must route all the requests of a session to the same replica.
+// Without limits, sessions are learnt from their initialization and any replica can serve them.
+type SessionManager struct {
+	// idleTimeout is the maximum time between two requests of a session, 0 means no limit
+	idleTimeout time.Duration
+	// maxLifetime is the maximum age of a session regardless of activity, 0 means no limit
+	maxLifetime time.Duration
+
+	notifier sessionNotifier
+
+	mu       sync.Mutex
+	sessions map[string]*proxySession
+
+	now func() time.Time
+}
+
+// NewSessionManager creates a SessionManager with the given limits (0 disables a limit).
+// If notifier is not nil, it is used to notify clients of sessions that expire while they have
+// an open stream with the proxy.
+func NewSessionManager(idleTimeout, maxLifetime time.Duration, notifier sessionNotifier) *SessionManager {
+	return &SessionManager{
+		idleTimeout: idleTimeout,
+		maxLifetime: maxLifetime,
+		notifier:    notifier,
+		sessions:    make(map[string]*proxySession),
+		now:         time.Now,
+	}
+}
+
+// Enforced returns true if the manager has a limit to enforce, in which case it must issue the sessions of the proxy.
+func (s *SessionManager) Enforced() bool {
+	return s.idleTimeout > 0 || s.maxLifetime > 0
+}
+
+// Generate creates a new session.
+func (s *SessionManager) Generate() string {
+	id := sessionIDPrefix + uuid.New().String()
+	now := s.now()
+
+	s.mu.Lock()
+	s.sessions[id] = &proxySession{createdAt: now, lastSeen: now}
+	s.mu.Unlock()
+
+	metrics.ProxySessionsActive.Inc()
+	return id
+}
+
+// Validate checks that a session exists and hasn't expired, and records activity on it.
+// Unknown and expired sessions are reported as terminated.
+func (s *SessionManager) Validate(sessionID string) (isTerminated bool, err error) {
+	if !strings.HasPrefix(sessionID, sessionIDPrefix) {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return true, nil
+	}
+	if reason := s.expiryReason(sess, now); reason != "" {
+		delete(s.sessions, sessionID)
+		s.mu.Unlock()
+		s.expired(sessionID, reason)
+		return true, nil
+	}
+	sess.lastSeen = now
+	s.mu.Unlock()
+	return false, nil
+}
+
+// Terminate ends a session at the request of its client.
+func (s *SessionManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	s.mu.Lock()
+	_, ok := s.sessions[sessionID]
+	delete(s.sessions, sessionID)
+	s.mu.Unlock()
+
+	if ok {
+		metrics.ProxySessionsActive.Dec()
+		metrics.ProxySessionsTerminated.Inc()
+	}
+	return false, nil
+}
+
+// Run periodically expires sessions until ctx is cancelled.
+// Without it, expired sessions are only cleaned up when their client makes another request,
+// and the sessions tracked without limits are never forgotten.
+func (s *SessionManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.sweepInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+// sweep expires all sessions that are past one of their limits.
+// Without limits, it forgets the sessions that have been idle for longer than untrackedSessionTTL.
+func (s *SessionManager) sweep() {
+	now := s.now()
+	expired := make(map[string]string)
+	forgotten := 0
+
+	s.mu.Lock()
+	for id, sess := range s.sessions {
+		if reason := s.expiryReason(sess, now); reason != "" {
+			expired[id] = reason
+			delete(s.sessions, id)
+		} else if !s.Enforced() && now.Sub(sess.lastSeen) >= untrackedSessionTTL {
+			forgotten++
+			delete(s.sessions, id)
+		}
+	}
+	s.mu.Unlock()
+
+	metrics.ProxySessionsActive.Sub(float64(forgotten))
+
+	for id, reason := range expired {
+		s.expired(id, reason)
+	}
+}
+
+// sweepInterval returns how often sessions are checked for expiry.
+func (s *SessionManager) sweepInterval() time.Duration {
+	limit := s.idleTimeout
+	if s.maxLifetime > 0 && (limit == 0 || s.maxLifetime < limit) {
+		limit = s.maxLifetime
+	}
+	if limit == 0 {
+		return time.Minute
+	}
+	return min(max(limit/10, time.Second), time.Minute)
+}
+
+func (s *SessionManager) expiryReason(sess *proxySession, now time.Time) string {
+	if s.maxLifetime > 0 && now.Sub(sess.createdAt) >= s.maxLifetime {
+		return SessionExpiredMaxLifetime
+	}
+	if s.idleTimeout > 0 && now.Sub(sess.lastSeen) >= s.idleTimeout {
+		return SessionExpiredIdle
+	}
+	return ""
+}
+
+// expired records the expiry of a session and notifies its client, if it is still listening.
+func (s *SessionManager) expired(sessionID, reason string) {
+	metrics.ProxySessionsActive.Dec()
+	metrics.ProxySessionsExpired.WithLabelValues(reason).Inc()
+	log.Printf("[INFO] MCP proxy session %s expired (%s)", sessionID, reason)
+
+	if s.notifier == nil {
+		return
+	}
+	// best effort, the client only receives this if it has a stream open with the proxy
+	_ = s.notifier.SendNotificationToSpecificClient(sessionID, "notifications/message", map[string]any{
+		"level":  "warning",
+		"logger": "mcpjungle",
+		"data":   fmt.Sprintf("session expired (%s), re-initialize to continue", reason),
+	})
+}
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type fakeNotifier struct {
+	notified []string
+}
+
+func (f *fakeNotifier) SendNotificationToSpecificClient(sessionID string, method string, params map[string]any) error {
+	f.notified = append(f.notified, sessionID)
+	return nil
+}
+
+func TestSessionManagerIdleTimeout(t *testing.T) {
+	now := time.Now()
+	m := NewSessionManager(10*time.Minute, 0, nil)
+	m.now = func() time.Time { return now }
+
+	id := m.Generate()
+	now = now.Add(9 * time.Minute)
+	if terminated, err := m.Validate(id); err != nil || terminated {
+		t.Fatalf("Validate() = %v, %v, want active session", terminated, err)
+	}
+
+	// activity resets the idle timer
+	now = now.Add(9 * time.Minute)
+	if terminated, _ := m.Validate(id); terminated {
+		t.Fatal("expected session to still be active after recent activity")
+	}
+
+	now = now.Add(10 * time.Minute)
+	if terminated, _ := m.Validate(id); !terminated {
+		t.Error("expected idle session to be terminated")
+	}
+}
+
+func TestSessionManagerMaxLifetime(t *testing.T) {
+	now := time.Now()
+	n := &fakeNotifier{}
+	m := NewSessionManager(0, time.Hour, n)
+	m.now = func() time.Time { return now }
+
+	id := m.Generate()
+	for i := 0; i < 5; i++ {
+		now = now.Add(10 * time.Minute)
+		if terminated, _ := m.Validate(id); terminated {
+			t.Fatalf("expected session to be active after %d minutes", (i+1)*10)
+		}
+	}
+
+	now = now.Add(15 * time.Minute)
+	m.sweep()
+	if len(n.notified) != 1 || n.notified[0] != id {
+		t.Errorf("expected client of expired session to be notified, got %v", n.notified)
+	}
+	if terminated, _ := m.Validate(id); !terminated {
+		t.Error("expected session past its max lifetime to be terminated")
+	}
+}
+
+func TestSessionManagerUnknownSessions(t *testing.T) {
+	m := NewSessionManager(0, 0, nil)
+	if _, err := m.Validate("not-a-session"); err == nil {
+		t.Error("expected malformed session ID to be rejected")
+	}
+	if terminated, _ := m.Validate(sessionIDPrefix + "unknown"); !terminated {
+		t.Error("expected unknown session to be reported as terminated")
+	}
+
+	id := m.Generate()
+	if _, err := m.Terminate(id); err != nil {
+		t.Fatalf("Terminate() error = %v", err)
+	}
+	if terminated, _ := m.Validate(id); !terminated {
+		t.Error("expected terminated session to be reported as terminated")
+	}
+}
+
+func TestSessionManagerWithoutLimits(t *testing.T) {
+	now := time.Now()
+	hooks := &server.Hooks{}
+	proxy := server.NewMCPServer("proxy", "0.0.1", server.WithHooks(hooks))
+	m := NewSessionManager(0, 0, nil)
+	m.now = func() time.Time { return now }
+	m.RegisterHooks(hooks)
+	if m.Enforced() {
+		t.Fatal("expected a session manager without limits not to issue the sessions")
+	}
+
+	// the proxy issues its own sessions, the manager learns about them from their initialization
+	ts := server.NewTestStreamableHTTPServer(proxy)
+	defer ts.Close()
+	c, err := client.NewStreamableHttpClient(ts.URL + "/mcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	initReq := mcp.InitializeRequest{}
+	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := c.Initialize(context.Background(), initReq); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	id := c.GetSessionId()
+	if v := m.ProtocolVersion(id); v != mcp.LATEST_PROTOCOL_VERSION {
+		t.Fatalf("ProtocolVersion() = %q, want %s", v, mcp.LATEST_PROTOCOL_VERSION)
+	}
+
+	// activity keeps the session tracked
+	now = now.Add(untrackedSessionTTL / 2)
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(untrackedSessionTTL / 2)
+	m.sweep()
+	if m.ProtocolVersion(id) == "" {
+		t.Fatal("expected an active session to be tracked")
+	}
+
+	now = now.Add(untrackedSessionTTL)
+	m.sweep()
+	if m.ProtocolVersion(id) != "" {
+		t.Error("expected an abandoned session to be forgotten")
+	}
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// resultSigner signs tool results with the gateway's Ed25519 key
+type resultSigner struct {
+	key ed25519.PrivateKey
+
+	// kid is the JWK thumbprint (RFC 7638) of the public key
+	kid string
+}
+
+// resultSigningHeader is the protected header of the JWS of a tool result
+type resultSigningHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+
+	// Tool is the canonical name of the tool that produced the result
+	Tool string `json:"tool"`
+
+	// Iat is the time at which the result was signed, in seconds since the epoch
+	Iat int64 `json:"iat"`
+}
+
+// catalogSigningHeader is the protected header of the JWS of a published catalog snapshot
+type catalogSigningHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+	Iat int64  `json:"iat"`
+}
+
+// ErrSigningDisabled is returned when something must be signed but no signing key is configured
+var ErrSigningDisabled = errors.New("signing is not enabled, a signing key must be configured")
+
+// EnableResultSigning makes mcpjungle sign the results of all tool calls with the given key,
+// which must be a PEM-encoded Ed25519 private key in PKCS #8 format.
+// The signature is added to the _meta of the results, so that downstream consumers can verify
+// that a result passed through the gateway unmodified.
+func (m *MCPService) EnableResultSigning(pemKey []byte) error {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return errors.New("no PEM data found in the key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return errors.New("the key is not an Ed25519 private key")
+	}
+	m.resultSigner = &resultSigner{key: key, kid: ed25519Thumbprint(key.Public().(ed25519.PublicKey))}
+	return nil
+}
+
+// ResultSigningKeys returns the public keys that verify the signatures of tool results.
+// The set is empty if result signing is not enabled.
+func (m *MCPService) ResultSigningKeys() types.JWKS {
+	jwks := types.JWKS{Keys: []types.JWK{}}
+	if m.resultSigner == nil {
+		return jwks
+	}
+	jwks.Keys = append(jwks.Keys, types.JWK{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(m.resultSigner.key.Public().(ed25519.PublicKey)),
+		Kid: m.resultSigner.kid,
+		Alg: "EdDSA",
+		Use: "sig",
+	})
+	return jwks
+}
+
+// signToolResult adds the gateway's signature to the _meta of a tool result, if result signing is enabled.
+// The result is left unsigned if it cannot be signed.
+func (m *MCPService) signToolResult(name string, res *mcp.CallToolResult) {
+	if m.resultSigner == nil || res == nil {
+		return
+	}
+	content := types.ToolResultSigningContent{
+		Content:           make([]any, len(res.Content)),
+		IsError:           res.IsError,
+		StructuredContent: res.StructuredContent,
+	}
+	for i, c := range res.Content {
+		content.Content[i] = c
+	}
+	payload, err := types.ToolResultSigningPayload(content)
+	if err != nil {
+		log.Printf("[ERROR] failed to sign the result of tool %s: %v", name, err)
+		return
+	}
+	jws, err := m.resultSigner.signDetached(resultSigningHeader{
+		Alg:  "EdDSA",
+		Kid:  m.resultSigner.kid,
+		Tool: name,
+		Iat:  time.Now().Unix(),
+	}, payload)
+	if err != nil {
+		log.Printf("[ERROR] failed to sign the result of tool %s: %v", name, err)
+		return
+	}
+
+	if res.Meta == nil {
+		res.Meta = make(map[string]any)
+	}
+	res.Meta[types.ToolResultSignatureMetaKey] = jws
+}
+
+// signDetached returns the compact JWS of payload with the given protected header, without the payload
+// (detached payload, see RFC 7515 Appendix F).
+func (s *resultSigner) signDetached(header any, payload []byte) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWS header: %w", err)
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(h)
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sig := ed25519.Sign(s.key, []byte(signingInput))
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// ed25519Thumbprint returns the JWK thumbprint (RFC 7638) of an Ed25519 public key
+func ed25519Thumbprint(pub ed25519.PublicKey) string {
+	// the members must be in lexicographic order with no whitespace
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + base64.RawURLEncoding.EncodeToString(pub) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SignCatalogSnapshot returns the detached JWS of a serialized catalog snapshot, signed with the same key
+// as tool results so that it is verified with the keys of ResultSigningKeys.
+func (m *MCPService) SignCatalogSnapshot(payload []byte) (string, error) {
+	if m.resultSigner == nil {
+		return "", ErrSigningDisabled
+	}
+	return m.resultSigner.signDetached(catalogSigningHeader{
+		Alg: "EdDSA",
+		Kid: m.resultSigner.kid,
+		Typ: types.CatalogSnapshotJWSType,
+		Iat: time.Now().Unix(),
+	}, payload)
+}
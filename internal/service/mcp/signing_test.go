@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestSignToolResult(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &MCPService{}
+	if err := m.EnableResultSigning(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})); err != nil {
+		t.Fatalf("EnableResultSigning() error = %v", err)
+	}
+	if err := (&MCPService{}).EnableResultSigning([]byte("not a key")); err == nil {
+		t.Error("EnableResultSigning() with an invalid key succeeded, want error")
+	}
+
+	res := mcp.NewToolResultText(`{"price": 1e3, "note": "<b>&</b>"}`)
+	res.StructuredContent = map[string]any{"price": 1000, "note": "<b>&</b>"}
+	m.signToolResult("shop__quote", res)
+
+	// consumers of the API receive the result as a ToolInvokeResult, which contains the structured content
+	content := make([]map[string]any, len(res.Content))
+	for i, c := range res.Content {
+		b, _ := json.Marshal(c)
+		_ = json.Unmarshal(b, &content[i])
+	}
+	raw, err := json.Marshal(types.ToolInvokeResult{
+		Meta:              res.Meta,
+		IsError:           res.IsError,
+		Content:           content,
+		StructuredContent: res.StructuredContent,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received types.ToolInvokeResult
+	if err := json.Unmarshal(raw, &received); err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := m.ResultSigningKeys()
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("ResultSigningKeys() = %+v, want 1 key", jwks)
+	}
+	pub, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+	verify := func(r types.ToolInvokeResult) bool {
+		jws, _ := r.Meta[types.ToolResultSignatureMetaKey].(string)
+		header, sig, ok := strings.Cut(jws, "..")
+		if !ok {
+			t.Fatalf("signature %q is not a detached JWS", jws)
+		}
+		c, err := types.NewToolResultSigningContent(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, err := types.ToolResultSigningPayload(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodedSig, _ := base64.RawURLEncoding.DecodeString(sig)
+		input := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+		return ed25519.Verify(pub, []byte(input), decodedSig)
+	}
+
+	if !verify(received) {
+		t.Error("signature of the received result does not verify")
+	}
+	received.StructuredContent.(map[string]any)["price"] = 1
+	if verify(received) {
+		t.Error("signature of a result with tampered structured content verifies")
+	}
+	received.StructuredContent.(map[string]any)["price"] = 1000
+	received.Content[0]["text"] = "tampered"
+	if verify(received) {
+		t.Error("signature of a tampered result verifies")
+	}
+}
@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
+)
+
+// ErrNoSpiffeIdentity is returned when connecting to an MCP server that requires mTLS with SPIFFE
+// while mcpjungle was started without an SVID.
+var ErrNoSpiffeIdentity = errors.New("mcpjungle has no SPIFFE SVID")
+
+// SetSpiffeSource sets the SPIFFE SVID & trust bundle used to connect with mTLS to the MCP servers
+// that are registered with a SPIFFE ID.
+func (m *MCPService) SetSpiffeSource(source *spiffe.Source) {
+	m.spiffe = source
+}
+
+// spiffeTransportOption returns the option of the streamable HTTP transport that authenticates both mcpjungle and
+// the MCP server with their SVIDs, the server must present an SVID with the given SPIFFE ID.
+func spiffeTransportOption(source *spiffe.Source, spiffeID string) (transport.StreamableHTTPCOption, error) {
+	if source == nil {
+		return nil, fmt.Errorf("the MCP server requires mTLS with SPIFFE ID %s: %w", spiffeID, ErrNoSpiffeIdentity)
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = source.ClientTLSConfig(spiffeID)
+	return transport.WithHTTPBasicClient(&http.Client{Transport: t}), nil
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"unicode/utf8"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// maxPendingFrame is the maximum size of a message split across several lines of output.
+// Beyond it, the lines are dropped as an incomplete message.
+const maxPendingFrame = 4 << 20
+
+// maxFramingPreview is the number of bytes of malformed output shown in the logs
+const maxFramingPreview = 200
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// framingIssues describes the reasons why output of a stdio server is not a well-formed message
+var framingIssues = map[string]string{
+	"bom":          "stripped a byte order mark",
+	"invalid_utf8": "replaced invalid UTF-8",
+	"noise":        "dropped output that is not a JSON-RPC message",
+	"noise_prefix": "dropped output preceding a JSON-RPC message",
+	"multiline":    "joined a JSON-RPC message split across lines",
+	"partial":      "dropped an incomplete JSON-RPC message",
+}
+
+// stdioFramer turns the stdout of a stdio MCP server into newline-delimited JSON-RPC messages.
+// Servers often print log lines or banners to stdout, or emit byte order marks and pretty-printed JSON.
+// In lenient mode, the framer works around these, in strict mode it fails on the first of them.
+// Every issue is logged and counted in the mcpjungle_upstream_stdio_framing_errors_total metric.
+type stdioFramer struct {
+	server  string
+	strict  bool
+	pending []byte
+}
+
+// copy reads the output of the server from src and writes the messages it contains to dst, one per line,
+// until src is exhausted.
+func (f *stdioFramer) copy(dst io.Writer, src io.Reader) error {
+	r := bufio.NewReader(src)
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			msg, err := f.frame(line)
+			if err != nil {
+				return err
+			}
+			if msg != nil {
+				if _, err := dst.Write(append(msg, '\n')); err != nil {
+					return err
+				}
+			}
+		}
+		if readErr != nil {
+			if len(f.pending) > 0 {
+				if err := f.report("partial", f.pending); err != nil {
+					return err
+				}
+				f.pending = nil
+			}
+			if readErr == io.EOF {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
+
+// frame processes a line of output. It returns the message that the line contains or completes, if any.
+func (f *stdioFramer) frame(line []byte) ([]byte, error) {
+	line = bytes.TrimRight(line, "\r\n")
+	if bytes.HasPrefix(line, utf8BOM) {
+		line = line[len(utf8BOM):]
+		if err := f.report("bom", nil); err != nil {
+			return nil, err
+		}
+	}
+	if !utf8.Valid(line) {
+		if err := f.report("invalid_utf8", line); err != nil {
+			return nil, err
+		}
+		line = bytes.ToValidUTF8(line, []byte("�"))
+	}
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if len(f.pending) > 0 {
+		if isJSONObject(trimmed) {
+			// a complete message on its own, so the pending one was never completed
+			if err := f.report("partial", f.pending); err != nil {
+				return nil, err
+			}
+			f.pending = nil
+			return trimmed, nil
+		}
+		f.pending = append(append(f.pending, '\n'), line...)
+		if json.Valid(f.pending) {
+			var msg bytes.Buffer
+			_ = json.Compact(&msg, f.pending)
+			f.pending = nil
+			return msg.Bytes(), f.report("multiline", nil)
+		}
+		if len(f.pending) > maxPendingFrame {
+			err := f.report("partial", f.pending)
+			f.pending = nil
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if isJSONObject(trimmed) {
+		return trimmed, nil
+	}
+	if f.strict {
+		return nil, f.report("noise", line)
+	}
+	if i := bytes.IndexByte(trimmed, '{'); i > 0 && isJSONObject(trimmed[i:]) {
+		return trimmed[i:], f.report("noise_prefix", trimmed[:i])
+	}
+	if trimmed[0] == '{' {
+		// possibly the first line of a message split across lines
+		f.pending = append([]byte(nil), trimmed...)
+		return nil, nil
+	}
+	return nil, f.report("noise", line)
+}
+
+// report logs and counts an issue with the output of the server.
+// In strict mode, it returns an error describing the issue.
+func (f *stdioFramer) report(reason string, output []byte) error {
+	metrics.UpstreamStdioFramingErrors.WithLabelValues(f.server, reason).Inc()
+	preview := output
+	if len(preview) > maxFramingPreview {
+		preview = preview[:maxFramingPreview]
+	}
+	if f.strict {
+		return fmt.Errorf("MCP server %s violated the stdio framing: %s: %q", f.server, framingIssues[reason], preview)
+	}
+	if output != nil {
+		log.Printf("['%s' MCP STDOUT] [WARN] %s: %q", f.server, framingIssues[reason], preview)
+	} else {
+		log.Printf("['%s' MCP STDOUT] [WARN] %s", f.server, framingIssues[reason])
+	}
+	return nil
+}
+
+func isJSONObject(b []byte) bool {
+	return len(b) > 0 && b[0] == '{' && json.Valid(b)
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStdioFramer(t *testing.T) {
+	output := "\xef\xbb\xbf{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\r\n" +
+		"Server listening on stdio\n" +
+		"\n" +
+		"[info] {\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}\n" +
+		"{\n  \"jsonrpc\": \"2.0\",\n  \"id\": 3,\n  \"result\": {}\n}\n" +
+		"{\"jsonrpc\":\"2.0\",\n" +
+		"{\"jsonrpc\":\"2.0\",\"id\":4,\"result\":{\"text\":\"caf\xe9\"}}\n" +
+		"{\"jsonrpc\":\"2.0\",\"id\":5"
+
+	var dst bytes.Buffer
+	f := &stdioFramer{server: "test"}
+	if err := f.copy(&dst, strings.NewReader(output)); err != nil {
+		t.Fatalf("copy() error = %v", err)
+	}
+	want := `{"jsonrpc":"2.0","id":1,"result":{}}
+{"jsonrpc":"2.0","id":2,"result":{}}
+{"jsonrpc":"2.0","id":3,"result":{}}
+{"jsonrpc":"2.0","id":4,"result":{"text":"caf` + "�" + `"}}
+`
+	if dst.String() != want {
+		t.Errorf("copy() wrote\n%s\nwant\n%s", dst.String(), want)
+	}
+}
+
+func TestStdioFramerStrict(t *testing.T) {
+	output := "{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\nServer listening on stdio\n{\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}\n"
+
+	var dst bytes.Buffer
+	f := &stdioFramer{server: "test", strict: true}
+	err := f.copy(&dst, strings.NewReader(output))
+	if err == nil || !strings.Contains(err.Error(), "Server listening on stdio") {
+		t.Fatalf("copy() error = %v, want a framing error", err)
+	}
+	if dst.String() != "{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n" {
+		t.Errorf("copy() wrote %q before the error, want only the first message", dst.String())
+	}
+}
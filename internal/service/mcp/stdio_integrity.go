@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// ErrIntegrityCheckFailed is returned when a file of a stdio server doesn't match its pinned checksum or signature
+var ErrIntegrityCheckFailed = errors.New("integrity check failed")
+
+// verifyStdioIntegrity verifies the file of a stdio server pinned by its integrity settings, if any,
+// so that a tampered installation of the server is never started.
+func verifyStdioIntegrity(name string, conf *model.StdioConfig) error {
+	i := conf.Integrity
+	if i == nil {
+		return nil
+	}
+	path, err := integrityPath(conf)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrIntegrityCheckFailed, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read %s: %v", ErrIntegrityCheckFailed, path, err)
+	}
+
+	if i.Checksum != "" {
+		sum := sha256.Sum256(data)
+		if actual := "sha256:" + hex.EncodeToString(sum[:]); !strings.EqualFold(actual, i.Checksum) {
+			metrics.UpstreamIntegrityFailures.WithLabelValues(name).Inc()
+			return fmt.Errorf("%w: checksum of %s is %s, expected %s", ErrIntegrityCheckFailed, path, actual, i.Checksum)
+		}
+	}
+	if i.Signature != "" {
+		sig, _ := base64.StdEncoding.DecodeString(i.Signature)
+		key, _ := base64.StdEncoding.DecodeString(i.PublicKey)
+		if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, data, sig) {
+			metrics.UpstreamIntegrityFailures.WithLabelValues(name).Inc()
+			return fmt.Errorf("%w: signature of %s is not valid", ErrIntegrityCheckFailed, path)
+		}
+	}
+	return nil
+}
+
+// integrityPath returns the path of the file pinned by the integrity settings of a stdio server.
+// It defaults to the server's command, resolved the same way as when the process is started.
+func integrityPath(conf *model.StdioConfig) (string, error) {
+	path := conf.Integrity.Path
+	if path == "" {
+		path = conf.Command
+		if !strings.ContainsRune(path, filepath.Separator) && !strings.ContainsRune(path, '/') {
+			resolved, err := exec.LookPath(path)
+			if err != nil {
+				return "", fmt.Errorf("failed to find command %s: %w", path, err)
+			}
+			return resolved, nil
+		}
+	}
+	if !filepath.IsAbs(path) && conf.Cwd != "" {
+		path = filepath.Join(conf.Cwd, path)
+	}
+	return path, nil
+}
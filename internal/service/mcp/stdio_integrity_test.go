@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestVerifyStdioIntegrity(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("#!/bin/sh\necho server\n")
+	if err := os.WriteFile(filepath.Join(dir, "server.sh"), content, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, content))
+	publicKey := base64.StdEncoding.EncodeToString(pub)
+
+	tests := []struct {
+		name      string
+		integrity *types.StdioIntegrity
+		wantErr   bool
+	}{
+		{"no integrity", nil, false},
+		{"matching checksum of the command", &types.StdioIntegrity{Checksum: checksum}, false},
+		{"valid signature", &types.StdioIntegrity{Signature: signature, PublicKey: publicKey}, false},
+		{"other file", &types.StdioIntegrity{Path: "package.tgz", Checksum: checksum}, true},
+		{"tampered file", &types.StdioIntegrity{Checksum: "sha256:" + hex.EncodeToString(make([]byte, 32))}, true},
+		{"signature by another key", &types.StdioIntegrity{
+			Signature: signature, PublicKey: base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize)),
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &model.StdioConfig{Command: "./server.sh", Cwd: dir, Integrity: tt.integrity}
+			err := verifyStdioIntegrity("test", conf)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("verifyStdioIntegrity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrIntegrityCheckFailed) {
+				t.Errorf("verifyStdioIntegrity() error = %v, want ErrIntegrityCheckFailed", err)
+			}
+		})
+	}
+
+	if _, err := model.NewStdioServer("test", "", model.StdioConfig{
+		Command: "npx", Integrity: &types.StdioIntegrity{Checksum: "md5:abc"},
+	}); err == nil {
+		t.Error("NewStdioServer() with an invalid checksum succeeded, want error")
+	}
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// stdioPidEntry is a stdio MCP server process recorded in the pidfile
+type stdioPidEntry struct {
+	Server string `json:"server"`
+	// Cmdline & StartTime identify the process (its command line and its start time since boot, as read from /proc),
+	// to tell it apart from an unrelated process that reused its PID
+	Cmdline   string `json:"cmdline"`
+	StartTime uint64 `json:"start_time"`
+}
+
+// stdioPidFile records the PIDs of the stdio MCP server processes started by mcpjungle in a file,
+// so that the processes orphaned by a crash of mcpjungle can be terminated on the next start.
+type stdioPidFile struct {
+	mu   sync.Mutex
+	path string
+	pids map[int]stdioPidEntry
+}
+
+// stdioPids tracks the processes of all stdio MCP servers, it does nothing until a path is set
+var stdioPids stdioPidFile
+
+func (f *stdioPidFile) add(pid int, server string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.path == "" {
+		return
+	}
+	cmdline, startTime, ok := processIdentity(pid)
+	if !ok {
+		// the process couldn't be told apart from another one reusing its PID, so it would never be terminated
+		return
+	}
+	f.pids[pid] = stdioPidEntry{Server: server, Cmdline: cmdline, StartTime: startTime}
+	f.save()
+}
+
+func (f *stdioPidFile) remove(pid int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.pids[pid]; !ok {
+		return
+	}
+	delete(f.pids, pid)
+	f.save()
+}
+
+// save writes the pidfile atomically. f.mu must be held.
+// The temporary file is created exclusively and without following symlinks, in the private directory of the pidfile.
+func (f *stdioPidFile) save() {
+	data, err := json.Marshal(f.pids)
+	if err == nil {
+		tmp := f.path + ".tmp"
+		_ = os.Remove(tmp)
+		var tf *os.File
+		if tf, err = os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL|openNoFollow, 0o600); err == nil {
+			_, err = tf.Write(data)
+			if cerr := tf.Close(); err == nil {
+				err = cerr
+			}
+			if err == nil {
+				err = os.Rename(tmp, f.path)
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("[WARN] failed to update the stdio pidfile %s: %v", f.path, err)
+	}
+}
+
+// TrackStdioProcesses records the processes of the stdio MCP servers started from now on in the pidfile at path.
+// The processes recorded in the pidfile by a previous run of mcpjungle that are still running were orphaned
+// by a crash, they are terminated first. It returns a description of every terminated process.
+// The directory of the pidfile is created with mode 0700 if needed. It must be private to the current user,
+// and so must the pidfile: otherwise another user could make mcpjungle kill processes of their choice.
+// Orphaned processes are only detected on Linux, where their identity can be read from /proc.
+func TrackStdioProcesses(path string) ([]string, error) {
+	stdioPids.mu.Lock()
+	defer stdioPids.mu.Unlock()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create directory of stdio pidfile: %w", err)
+	}
+	if err := checkPrivateDir(dir); err != nil {
+		return nil, err
+	}
+	previous, err := readStdioPidFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var reaped []string
+	for pid, e := range previous {
+		if pid == os.Getpid() || !orphanedProcessRunning(pid, e) {
+			continue
+		}
+		desc := fmt.Sprintf("pid %d (%s) of MCP server %s", pid, e.Cmdline, e.Server)
+		if err := terminateOrphanedProcess(pid); err != nil {
+			log.Printf("[WARN] failed to terminate orphaned stdio process %s: %v", desc, err)
+			continue
+		}
+		reaped = append(reaped, desc)
+	}
+
+	stdioPids.path = path
+	stdioPids.pids = make(map[int]stdioPidEntry)
+	stdioPids.save()
+	return reaped, nil
+}
+
+// checkPrivateDir returns an error if dir is a symlink or isn't owned by the current user.
+// Its permissions are restricted to the current user.
+func checkPrivateDir(dir string) error {
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check directory of stdio pidfile: %w", err)
+	}
+	if !fi.IsDir() || !fileOwnedByCurrentUser(fi) {
+		return fmt.Errorf("directory of stdio pidfile %s must be a directory owned by the current user", dir)
+	}
+	if fi.Mode().Perm()&0o077 != 0 {
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to restrict permissions of directory of stdio pidfile: %w", err)
+		}
+	}
+	return nil
+}
+
+// readStdioPidFile returns the processes recorded in a pidfile, none if it doesn't exist.
+// The pidfile is refused if it is a symlink or isn't owned by the current user.
+func readStdioPidFile(path string) (map[int]stdioPidEntry, error) {
+	previous := make(map[int]stdioPidEntry)
+	f, err := os.OpenFile(path, os.O_RDONLY|openNoFollow, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return previous, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdio pidfile: %w", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stdio pidfile: %w", err)
+	}
+	if !fi.Mode().IsRegular() || !fileOwnedByCurrentUser(fi) {
+		return nil, fmt.Errorf("stdio pidfile %s must be a regular file owned by the current user", path)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdio pidfile: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &previous); err != nil {
+			log.Printf("[WARN] ignoring invalid stdio pidfile %s: %v", path, err)
+		}
+	}
+	return previous, nil
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// stdioExitTimeout is the time a stdio server is given to exit once its session is closed, before it is killed
+const stdioExitTimeout = 5 * time.Second
+
+// startStdioTransport starts the process of a stdio MCP server and returns a started transport that
+// communicates with it. The output of the process goes through a stdioFramer before reaching the transport.
+// The file pinned by the integrity settings of the server, if any, is verified before the process is started.
+func startStdioTransport(name string, conf *model.StdioConfig) (*transport.Stdio, error) {
+	if err := verifyStdioIntegrity(name, conf); err != nil {
+		return nil, err
+	}
+	cmd, err := stdioCommand(context.Background(), conf)
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		if conf.RunAs != "" && errors.Is(err, os.ErrPermission) {
+			return nil, fmt.Errorf("failed to start command as %s, mcpjungle must run as root to do so: %w", conf.RunAs, err)
+		}
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+	superviseStdioProcess(name, cmd)
+	stdioPids.add(cmd.Process.Pid, name)
+
+	messages, messagesWriter := io.Pipe()
+	p := &stdioProcess{name: name, cmd: cmd, stdin: stdin, messages: messagesWriter, exited: make(chan struct{})}
+	framer := &stdioFramer{server: name, strict: conf.Framing == types.StdioFramingStrict}
+	t := transport.NewIO(messages, p, stderrPipe{stderr})
+	stdioProcesses.Store(t, p)
+	go func() {
+		defer close(p.exited)
+		defer stdioProcesses.Delete(t)
+		err := framer.copy(messagesWriter, stdout)
+		if err != nil && err != io.ErrClosedPipe {
+			log.Printf("['%s' MCP server] [ERROR] terminating the server: %v", name, err)
+			_ = cmd.Process.Kill()
+		}
+		_ = messagesWriter.CloseWithError(err)
+		// keep draining the output until the process exits, so that it never blocks on a full pipe
+		_, _ = io.Copy(io.Discard, stdout)
+		_ = cmd.Wait()
+		stdioPids.remove(cmd.Process.Pid)
+	}()
+
+	if err := t.Start(context.Background()); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// stderrPipe is the stderr of a stdio server's process, which is closed by cmd.Wait when the process exits.
+// Closing it again, as the transport does when the session ends, is not an error.
+type stderrPipe struct {
+	io.ReadCloser
+}
+
+func (p stderrPipe) Close() error {
+	if err := p.ReadCloser.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// stdioProcesses holds the running processes of stdio MCP servers by the transport that communicates with them
+var stdioProcesses sync.Map
+
+// stdioProcessOf returns the process of the stdio MCP server that c is connected to.
+// It returns nil if c is not connected to a stdio server, or if the server's process already exited.
+func stdioProcessOf(c *client.Client) *stdioProcess {
+	t, ok := c.GetTransport().(*transport.Stdio)
+	if !ok {
+		return nil
+	}
+	p, ok := stdioProcesses.Load(t)
+	if !ok {
+		return nil
+	}
+	return p.(*stdioProcess)
+}
+
+// stdioProcess is the input of a stdio MCP server's process.
+// Closing it ends the session: the process is expected to exit when its input is closed.
+type stdioProcess struct {
+	name     string
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	messages *io.PipeWriter
+	exited   chan struct{}
+
+	// closed is set once mcpjungle ended the session, the process exiting before that means it crashed
+	closed atomic.Bool
+}
+
+// crashed returns true if the process exited without its session being ended by mcpjungle.
+// It must only be called once the process exited.
+func (p *stdioProcess) crashed() bool {
+	return !p.closed.Load()
+}
+
+func (p *stdioProcess) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+// Close closes the input of the process and waits for it to exit.
+// A process that doesn't exit within stdioExitTimeout is killed.
+func (p *stdioProcess) Close() error {
+	p.closed.Store(true)
+	err := p.stdin.Close()
+	// the transport stops reading messages once it is closed, unblock the framer
+	_ = p.messages.Close()
+	select {
+	case <-p.exited:
+	case <-time.After(stdioExitTimeout):
+		log.Printf("['%s' MCP server] [WARN] server did not exit within %s of its session ending, killing it", p.name, stdioExitTimeout)
+		_ = p.cmd.Process.Kill()
+		<-p.exited
+	}
+	return err
+}
+
+// stdioCommand creates the command that runs a stdio MCP server, set up for the current platform.
+func stdioCommand(ctx context.Context, conf *model.StdioConfig) (*exec.Cmd, error) {
+	cmd, err := platformCommand(ctx, conf.Command, conf.Args)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Env = os.Environ()
+	for k, v := range conf.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd.Dir = conf.Cwd
+	if conf.RunAs != "" {
+		if err := setRunAs(cmd, conf.RunAs); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+// cmdMetaChars are the characters that cmd.exe interprets unless they are escaped with a caret
+var cmdMetaChars = regexp.MustCompile(`([()\][%!^"` + "`" + `<>&|;, *?])`)
+
+// batchCommandLine returns the command line that runs a batch file (.cmd or .bat, eg- npx.cmd) with
+// cmd.exe, quoting and escaping the arguments so they reach the script unchanged.
+// cmd.exe does not follow the quoting rules of other Windows programs, so arguments containing
+// spaces or characters like & and | would otherwise be split or interpreted as commands.
+func batchCommandLine(shell, script string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, cmdMetaChars.ReplaceAllString(script, "^$1"))
+	for _, arg := range args {
+		parts = append(parts, escapeBatchArg(arg))
+	}
+	return fmt.Sprintf(`"%s" /d /s /c "%s"`, shell, strings.Join(parts, " "))
+}
+
+// escapeBatchArg quotes an argument following the rules of the C runtime, then escapes the
+// characters that cmd.exe would interpret.
+func escapeBatchArg(arg string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// backslashes preceding a quote are doubled, and the quote is escaped
+			b.WriteString(strings.Repeat(`\`, 2*backslashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		b.WriteRune(r)
+		backslashes = 0
+	}
+	// backslashes preceding the closing quote are doubled
+	b.WriteString(strings.Repeat(`\`, 2*backslashes))
+	b.WriteByte('"')
+	return cmdMetaChars.ReplaceAllString(b.String(), "^$1")
+}
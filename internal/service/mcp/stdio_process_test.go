@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// stdioServerEnvVar makes the test binary act as a stdio MCP server that writes noise to stdout
+const stdioServerEnvVar = "MCPJUNGLE_TEST_NOISY_STDIO_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(stdioServerEnvVar) == "1" {
+		runNoisyStdioServer()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// noisyWriter writes a log line and a byte order mark before every message
+type noisyWriter struct{ w io.Writer }
+
+func (n noisyWriter) Write(b []byte) (int, error) {
+	if _, err := n.w.Write([]byte("handling request\n\xef\xbb\xbf")); err != nil {
+		return 0, err
+	}
+	return n.w.Write(b)
+}
+
+func runNoisyStdioServer() {
+	s := server.NewMCPServer("noisy", "0.0.1", server.WithToolCapabilities(true))
+	s.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	_ = server.NewStdioServer(s).Listen(context.Background(), os.Stdin, noisyWriter{os.Stdout})
+}
+
+func TestRunStdioServerWithNoisyOutput(t *testing.T) {
+	s, err := model.NewStdioServer("noisy", "", model.StdioConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{stdioServerEnvVar: "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, initResult, err := runStdioServer(context.Background(), s)
+	if err != nil {
+		t.Fatalf("runStdioServer() error = %v", err)
+	}
+	if initResult.ServerInfo.Name != "noisy" {
+		t.Errorf("server name = %q, want noisy", initResult.ServerInfo.Name)
+	}
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil || len(tools.Tools) != 1 {
+		t.Fatalf("ListTools() = %v, %v, want 1 tool", tools, err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestBatchCommandLine(t *testing.T) {
+	got := batchCommandLine(
+		`C:\Windows\system32\cmd.exe`,
+		`C:\Program Files\nodejs\npx.cmd`,
+		[]string{"-y", "@modelcontextprotocol/server-filesystem", `C:\My Files\`, `a&b`, `say "hi"`},
+	)
+	want := `"C:\Windows\system32\cmd.exe" /d /s /c "C:\Program^ Files\nodejs\npx.cmd ` +
+		`^"-y^" ^"@modelcontextprotocol/server-filesystem^" ^"C:\My^ Files\\^" ^"a^&b^" ^"say^ \^"hi\^"^""`
+	if got != want {
+		t.Errorf("batchCommandLine() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func platformCommand(ctx context.Context, command string, args []string) (*exec.Cmd, error) {
+	return exec.CommandContext(ctx, command, args...), nil
+}
+
+// superviseStdioProcess is a no-op outside Windows: stdio servers stay in the process group of mcpjungle,
+// so they receive the same signals.
+func superviseStdioProcess(name string, cmd *exec.Cmd) {}
+
+// setRunAs makes the command run as the given user ("user" or "user:group", names or numeric IDs).
+// Without a group, the process gets the primary and supplementary groups of the user, like a login would.
+// Numeric IDs that are not in the user database (common in containers) are used as they are.
+func setRunAs(cmd *exec.Cmd, runAs string) error {
+	userName, groupName, hasGroup := strings.Cut(runAs, ":")
+	cred := &syscall.Credential{Groups: []uint32{}}
+	if u, err := lookupUser(userName); err == nil {
+		cred.Uid, cred.Gid = parseID(u.Uid), parseID(u.Gid)
+		if groupIDs, err := u.GroupIds(); err == nil && !hasGroup {
+			for _, id := range groupIDs {
+				cred.Groups = append(cred.Groups, parseID(id))
+			}
+		}
+	} else if id, numErr := strconv.ParseUint(userName, 10, 32); numErr == nil {
+		cred.Uid, cred.Gid = uint32(id), uint32(id)
+	} else {
+		return fmt.Errorf("invalid run_as user %s: %w", userName, err)
+	}
+	if hasGroup {
+		if g, err := lookupGroup(groupName); err == nil {
+			cred.Gid = parseID(g.Gid)
+		} else if id, numErr := strconv.ParseUint(groupName, 10, 32); numErr == nil {
+			cred.Gid = uint32(id)
+		} else {
+			return fmt.Errorf("invalid run_as group %s: %w", groupName, err)
+		}
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = cred
+	return nil
+}
+
+func lookupUser(name string) (*user.User, error) {
+	if _, err := strconv.Atoi(name); err == nil {
+		return user.LookupId(name)
+	}
+	return user.Lookup(name)
+}
+
+func lookupGroup(name string) (*user.Group, error) {
+	if _, err := strconv.Atoi(name); err == nil {
+		return user.LookupGroupId(name)
+	}
+	return user.LookupGroup(name)
+}
+
+// parseID parses a user or group ID from the user database, which are always numeric on Unix.
+func parseID(id string) uint32 {
+	n, _ := strconv.ParseUint(id, 10, 32)
+	return uint32(n)
+}
+
+// openNoFollow makes opening a file fail if it is a symlink
+const openNoFollow = syscall.O_NOFOLLOW
+
+// fileOwnedByCurrentUser returns true if the file is owned by the user that mcpjungle runs as.
+func fileOwnedByCurrentUser(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid()
+}
+
+// processIdentity returns the command line of a process and its start time (in clock ticks since boot),
+// which together tell it apart from a process that reuses its PID later. They are read from /proc,
+// so ok is false where it isn't available (ie- outside Linux).
+func processIdentity(pid int) (cmdline string, startTime uint64, ok bool) {
+	raw, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil || len(raw) == 0 {
+		return "", 0, false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", 0, false
+	}
+	// the command name (field 2) is in parentheses and may contain spaces, the start time is field 22
+	i := strings.LastIndexByte(string(stat), ')')
+	if i < 0 {
+		return "", 0, false
+	}
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 20 {
+		return "", 0, false
+	}
+	startTime, err = strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.Join(strings.Split(strings.TrimRight(string(raw), "\x00"), "\x00"), " "), startTime, true
+}
+
+// orphanedProcessRunning returns true if the process with the given PID is still the recorded one:
+// it has the same command line and was started at the same time.
+// It always returns false outside Linux, since a PID alone can't tell an orphaned stdio server
+// from an unrelated process that reused it.
+func orphanedProcessRunning(pid int, e stdioPidEntry) bool {
+	if e.StartTime == 0 {
+		return false
+	}
+	cmdline, startTime, ok := processIdentity(pid)
+	return ok && cmdline == e.Cmdline && startTime == e.StartTime
+}
+
+// terminateOrphanedProcess kills an orphaned stdio server.
+func terminateOrphanedProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
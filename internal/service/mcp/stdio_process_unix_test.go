@@ -0,0 +1,142 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestSetRunAs(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("failed to get current user: %v", err)
+	}
+
+	cmd := exec.Command("true")
+	if err := setRunAs(cmd, current.Username); err != nil {
+		t.Fatalf("setRunAs(%s) error = %v", current.Username, err)
+	}
+	cred := cmd.SysProcAttr.Credential
+	if strconv.Itoa(int(cred.Uid)) != current.Uid || strconv.Itoa(int(cred.Gid)) != current.Gid {
+		t.Errorf("credential = %d:%d, want %s:%s", cred.Uid, cred.Gid, current.Uid, current.Gid)
+	}
+
+	// numeric IDs unknown to the user database are used as they are
+	if err := setRunAs(cmd, "4242:4343"); err != nil {
+		t.Fatalf("setRunAs(4242:4343) error = %v", err)
+	}
+	if cred := cmd.SysProcAttr.Credential; cred.Uid != 4242 || cred.Gid != 4343 || len(cred.Groups) != 0 {
+		t.Errorf("credential = %+v, want 4242:4343 without supplementary groups", cred)
+	}
+
+	if err := setRunAs(cmd, "no-such-user-mcpjungle"); err == nil {
+		t.Error("setRunAs() accepted an unknown user")
+	}
+}
+
+func TestStdioCommandCwd(t *testing.T) {
+	dir := t.TempDir()
+	cmd, err := stdioCommand(context.Background(), &model.StdioConfig{Command: "pwd", Cwd: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Skipf("failed to run pwd: %v", err)
+	}
+	// the temporary directory may be behind a symlink (eg- on macOS)
+	want, _ := filepath.EvalSymlinks(dir)
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != want {
+		t.Errorf("server ran in %s, want %s", got, want)
+	}
+}
+
+func TestTrackStdioProcessesTerminatesOrphans(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("/proc is not available")
+	}
+	defer func() { stdioPids.path = "" }()
+	path := filepath.Join(t.TempDir(), "state", "stdio.pids")
+
+	// stdio servers started by a previous run that crashed
+	start := func() (*exec.Cmd, chan struct{}) {
+		cmd := exec.Command("sleep", "60")
+		if err := cmd.Start(); err != nil {
+			t.Skipf("failed to start sleep: %v", err)
+		}
+		exited := make(chan struct{})
+		go func() {
+			_ = cmd.Wait()
+			close(exited)
+		}()
+		return cmd, exited
+	}
+	orphan, exited := start()
+	reused, reusedExited := start()
+	defer func() { _ = reused.Process.Kill() }()
+
+	if _, err := TrackStdioProcesses(path); err != nil {
+		t.Fatalf("TrackStdioProcesses() error = %v", err)
+	}
+	if fi, err := os.Stat(filepath.Dir(path)); err != nil || fi.Mode().Perm() != 0o700 {
+		t.Errorf("directory of the pidfile = %v, %v, want mode 0700", fi, err)
+	}
+	stdioPids.add(orphan.Process.Pid, "sleeper")
+	// a recorded PID now used by another process with the same command line must not be killed
+	stdioPids.add(reused.Process.Pid, "reused")
+	e := stdioPids.pids[reused.Process.Pid]
+	e.StartTime++
+	stdioPids.pids[reused.Process.Pid] = e
+	stdioPids.save()
+
+	reaped, err := TrackStdioProcesses(path)
+	if err != nil {
+		t.Fatalf("TrackStdioProcesses() error = %v", err)
+	}
+	if len(reaped) != 1 || !strings.Contains(reaped[0], "MCP server sleeper") || !strings.Contains(reaped[0], "sleep 60") {
+		t.Errorf("TrackStdioProcesses() = %v, want the sleeper process", reaped)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = orphan.Process.Kill()
+		t.Fatal("the orphaned process was not terminated")
+	}
+	select {
+	case <-reusedExited:
+		t.Error("a process that reused the PID of a recorded one was terminated")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if len(stdioPids.pids) != 0 {
+		t.Errorf("pidfile entries = %v, want none after the restart", stdioPids.pids)
+	}
+}
+
+func TestTrackStdioProcessesRefusesSymlinks(t *testing.T) {
+	defer func() { stdioPids.path = "" }()
+	dir := t.TempDir()
+	target := filepath.Join(dir, "victim")
+	if err := os.WriteFile(target, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "stdio.pids")
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TrackStdioProcesses(path); err == nil {
+		t.Error("TrackStdioProcesses() accepted a pidfile that is a symlink")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "keep" {
+		t.Errorf("the target of the symlink was overwritten with %q", data)
+	}
+}
@@ -0,0 +1,117 @@
+//go:build windows
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// platformCommand runs batch files (eg- npx.cmd, which is how most node-based MCP servers are started)
+// through cmd.exe with an explicitly escaped command line, and any other program directly.
+func platformCommand(ctx context.Context, command string, args []string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find command %s: %w", command, err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".cmd" && ext != ".bat" {
+		return exec.CommandContext(ctx, path, args...), nil
+	}
+	shell := os.Getenv("ComSpec")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+	cmd := exec.CommandContext(ctx, shell)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: batchCommandLine(shell, path, args)}
+	return cmd, nil
+}
+
+// setRunAs fails, running a stdio server as another user is only supported on Unix.
+func setRunAs(cmd *exec.Cmd, runAs string) error {
+	return fmt.Errorf("run_as is not supported on Windows")
+}
+
+// superviseStdioProcess puts a started stdio server in a job object that is closed when the server exits
+// or when mcpjungle exits, which kills all processes the server started (eg- the node process started
+// by npx.cmd). Windows has no process groups that are killed with their leader.
+// Processes started by the server before it is assigned to the job are not covered.
+func superviseStdioProcess(name string, cmd *exec.Cmd) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		log.Printf("[WARN] failed to create job object for MCP server %s: %v", name, err)
+		return
+	}
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	_, err = windows.SetInformationJobObject(
+		job, windows.JobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)),
+	)
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		log.Printf("[WARN] failed to configure job object for MCP server %s: %v", name, err)
+		return
+	}
+	proc, err := windows.OpenProcess(
+		windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE|windows.SYNCHRONIZE, false, uint32(cmd.Process.Pid),
+	)
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		log.Printf("[WARN] failed to open process of MCP server %s: %v", name, err)
+		return
+	}
+	if err := windows.AssignProcessToJobObject(job, proc); err != nil {
+		_ = windows.CloseHandle(proc)
+		_ = windows.CloseHandle(job)
+		log.Printf("[WARN] failed to assign MCP server %s to a job object: %v", name, err)
+		return
+	}
+	go func() {
+		defer windows.CloseHandle(proc)
+		_, _ = windows.WaitForSingleObject(proc, windows.INFINITE)
+		// closing the only handle of the job kills the processes that the server left behind
+		_ = windows.CloseHandle(job)
+	}()
+}
+
+// openNoFollow is a no-op on Windows, where creating symlinks requires elevated privileges
+const openNoFollow = 0
+
+// fileOwnedByCurrentUser always returns true on Windows, the pidfile is protected by the ACL of its directory.
+func fileOwnedByCurrentUser(fi os.FileInfo) bool {
+	return true
+}
+
+// processIdentity is not implemented on Windows: stdio servers are never orphaned there, see orphanedProcessRunning.
+func processIdentity(pid int) (cmdline string, startTime uint64, ok bool) {
+	return "", 0, false
+}
+
+// orphanedProcessRunning always returns false on Windows: stdio servers are killed along with their job object
+// when mcpjungle exits, even if it crashes, so they are never orphaned.
+func orphanedProcessRunning(pid int, e stdioPidEntry) bool {
+	return false
+}
+
+func terminateOrphanedProcess(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestPlatformCommandBatchFile(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "echo args.cmd")
+	if err := os.WriteFile(script, []byte("@echo off\r\necho %~1^|%~2\r\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := platformCommand(context.Background(), script, []string{"a & b", "x,y"})
+	if err != nil {
+		t.Fatalf("platformCommand() error = %v", err)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("failed to run batch file: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "a & b|x,y" {
+		t.Errorf("batch file received %q, want %q", got, "a & b|x,y")
+	}
+}
+
+func TestSuperviseStdioProcess(t *testing.T) {
+	cmd, err := stdioCommand(context.Background(), &model.StdioConfig{Command: "cmd.exe", Args: []string{"/c", "exit 0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	superviseStdioProcess("test", cmd)
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("supervised process failed: %v", err)
+	}
+}
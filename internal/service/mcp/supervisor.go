@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrServerRestarting is returned when calling a tool of a stdio MCP server whose process crashed
+// and is being restarted.
+var ErrServerRestarting = errors.New("MCP server is restarting")
+
+var errStdioProcessCrashed = errors.New("the process of the stdio server exited unexpectedly")
+
+const (
+	// stdioRestartMinBackoff is the delay before the first attempt at restarting a crashed stdio server.
+	// It doubles after every failed attempt and every crash shortly after a restart, up to stdioRestartMaxBackoff.
+	stdioRestartMinBackoff = time.Second
+	stdioRestartMaxBackoff = 5 * time.Minute
+
+	// stdioStableRunTime is how long a restarted process must run for its backoff to be reset
+	stdioStableRunTime = time.Minute
+)
+
+// stdioSupervisor tracks the processes of the stdio MCP servers marked keep_warm.
+// When such a process crashes, mcpjungle restarts it with exponential backoff (see restartStdioServer).
+type stdioSupervisor struct {
+	mu      sync.Mutex
+	servers map[string]*supervisedProcess
+
+	// minBackoff & maxBackoff override the restart backoff bounds, they are only set by tests
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// supervisedProcess is the supervision state of the process of a stdio server
+type supervisedProcess struct {
+	restarting bool
+	// restarts is the number of times the process was restarted since mcpjungle started
+	restarts int
+	backoff  time.Duration
+	// startedAt is when the current process was started
+	startedAt time.Time
+}
+
+func (sv *stdioSupervisor) bounds() (time.Duration, time.Duration) {
+	lo, hi := stdioRestartMinBackoff, stdioRestartMaxBackoff
+	if sv.minBackoff > 0 {
+		lo = sv.minBackoff
+	}
+	if sv.maxBackoff > 0 {
+		hi = sv.maxBackoff
+	}
+	return lo, hi
+}
+
+// running records that the process of a server was started and is up.
+func (sv *stdioSupervisor) running(name string) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.servers == nil {
+		sv.servers = make(map[string]*supervisedProcess)
+	}
+	p, ok := sv.servers[name]
+	if !ok {
+		p = &supervisedProcess{}
+		sv.servers[name] = p
+	}
+	p.restarting = false
+	p.startedAt = time.Now()
+}
+
+// crashed records that the process of a server crashed. It returns the supervision state of the server,
+// which identifies the restart loop, along with the delay before the first restart attempt.
+// A process that crashes again soon after being restarted waits longer than the previous one.
+func (sv *stdioSupervisor) crashed(name string) (*supervisedProcess, time.Duration) {
+	lo, hi := sv.bounds()
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.servers == nil {
+		sv.servers = make(map[string]*supervisedProcess)
+	}
+	p, ok := sv.servers[name]
+	if !ok {
+		p = &supervisedProcess{}
+		sv.servers[name] = p
+	}
+	p.restarting = true
+	if p.backoff == 0 || time.Since(p.startedAt) >= stdioStableRunTime {
+		p.backoff = lo
+	} else {
+		p.backoff = min(2*p.backoff, hi)
+	}
+	return p, p.backoff
+}
+
+// failed records a failed attempt at restarting a server and returns the delay before the next one.
+func (sv *stdioSupervisor) failed(p *supervisedProcess) time.Duration {
+	_, hi := sv.bounds()
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	p.backoff = min(2*p.backoff, hi)
+	return p.backoff
+}
+
+// restarted records that the process of a server was restarted.
+func (sv *stdioSupervisor) restarted(p *supervisedProcess) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	p.restarting = false
+	p.restarts++
+}
+
+// active returns false if a restart loop must stop because the server was deregistered in the meantime,
+// or because its restart is handled by another loop.
+func (sv *stdioSupervisor) active(name string, p *supervisedProcess) bool {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.servers[name] == p && p.restarting
+}
+
+// status returns whether the process of a server is being restarted, along with the number of restarts.
+func (sv *stdioSupervisor) status(name string) (bool, int) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	p, ok := sv.servers[name]
+	if !ok {
+		return false, 0
+	}
+	return p.restarting, p.restarts
+}
+
+// restarting returns the set of servers whose process is being restarted, nil if there are none.
+func (sv *stdioSupervisor) restarting() map[string]bool {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	var names map[string]bool
+	for name, p := range sv.servers {
+		if p.restarting {
+			if names == nil {
+				names = make(map[string]bool)
+			}
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// forget stops supervising a server, ending its restart loop if there is one.
+func (sv *stdioSupervisor) forget(name string) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	delete(sv.servers, name)
+}
+
+// SetShutdownContext sets the context that is done when mcpjungle shuts down.
+// The restarts of crashed stdio servers in progress are abandoned once it is done.
+func (m *MCPService) SetShutdownContext(ctx context.Context) {
+	m.shutdown = ctx
+}
+
+// keepWarm adds a connection to a server marked keep_warm to the warm pool and returns the pooled connection.
+// The process of a stdio server is supervised from then on: it is restarted if it crashes.
+func (m *MCPService) keepWarm(s *model.McpServer, c *client.Client) *client.Client {
+	pooled := m.warm.put(s.Name, c)
+	if pooled != c {
+		return pooled
+	}
+	// processes that already exited are not supervised, the heartbeat or the next tool call replaces their connection
+	if p := stdioProcessOf(c); p != nil {
+		m.supervisor.running(s.Name)
+		go m.superviseStdioProcess(s.Name, c, p)
+	}
+	return c
+}
+
+// superviseStdioProcess waits for the process of a warm stdio server to exit and restarts it if it crashed.
+func (m *MCPService) superviseStdioProcess(name string, c *client.Client, p *stdioProcess) {
+	<-p.exited
+	if !p.crashed() {
+		// mcpjungle ended the session, eg- the server was stopped, deregistered or its connection recycled
+		return
+	}
+	m.warm.discard(name, c)
+	if m.warm.get(name) != nil {
+		// a tool call already connected to the server again
+		return
+	}
+	m.restartStdioServer(name)
+}
+
+// restartStdioServer restarts the process of a crashed stdio server, with exponential backoff between attempts.
+// The tools of the server are unavailable until it is restarted. The restart is abandoned if the server is
+// deregistered, stopped or no longer kept warm in the meantime, or if mcpjungle shuts down.
+func (m *MCPService) restartStdioServer(name string) {
+	ctx := m.shutdown
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p, delay := m.supervisor.crashed(name)
+	m.setServerHealth(name, false, 0, errStdioProcessCrashed)
+	log.Printf("[WARN] the process of MCP server %s exited unexpectedly, restarting it in %s", name, delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if !m.supervisor.active(name, p) {
+			return
+		}
+		s, err := m.GetMcpServer(name)
+		if err != nil || s.Stopped || !s.KeepWarm {
+			m.supervisor.forget(name)
+			return
+		}
+		c, err := m.connectUpstream(ctx, s)
+		if err == nil {
+			metrics.UpstreamStdioRestarts.WithLabelValues(name, "succeeded").Inc()
+			log.Printf("[INFO] restarted the process of MCP server %s", name)
+			m.supervisor.restarted(p)
+			m.keepWarm(s, c)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		metrics.UpstreamStdioRestarts.WithLabelValues(name, "failed").Inc()
+		delay = m.supervisor.failed(p)
+		log.Printf("[WARN] failed to restart MCP server %s, retrying in %s: %v", name, delay, err)
+		m.notifyServerUnhealthy(s, err)
+		timer.Reset(delay)
+	}
+}
+
+// checkServerAvailable returns ErrServerRestarting if the process of a server is being restarted.
+func (m *MCPService) checkServerAvailable(name string) error {
+	if restarting, _ := m.supervisor.status(name); restarting {
+		return ErrServerRestarting
+	}
+	return nil
+}
+
+// ServerStatus returns the state of the long-lived connection to an MCP server,
+// along with the number of times its process was restarted after crashing.
+func (m *MCPService) ServerStatus(s *model.McpServer) (types.ServerStatus, int) {
+	restarting, restarts := m.supervisor.status(s.Name)
+	switch {
+	case s.Stopped:
+		return types.ServerStatusStopped, restarts
+	case restarting:
+		return types.ServerStatusRestarting, restarts
+	case m.warm.get(s.Name) != nil:
+		return types.ServerStatusRunning, restarts
+	default:
+		return types.ServerStatusIdle, restarts
+	}
+}
+
+// withoutRestartingServers removes the tools of the servers being restarted from a list of proxy tools.
+func (m *MCPService) withoutRestartingServers(tools []mcp.Tool) []mcp.Tool {
+	restarting := m.supervisor.restarting()
+	if restarting == nil {
+		return tools
+	}
+	available := make([]mcp.Tool, 0, len(tools))
+	for _, t := range tools {
+		if serverName, _, ok := splitServerToolName(t.Name); ok && restarting[serverName] {
+			continue
+		}
+		available = append(available, t)
+	}
+	return available
+}
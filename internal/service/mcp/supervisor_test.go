@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestCrashedStdioServerIsRestarted(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+	m.supervisor.minBackoff = 500 * time.Millisecond
+
+	s, err := model.NewStdioServer("noisy", "", model.StdioConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{stdioServerEnvVar: "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.KeepWarm = true
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	if err := m.WarmUp(context.Background(), 1); err != nil {
+		t.Fatalf("WarmUp() error = %v", err)
+	}
+	defer m.warm.discard("noisy", nil)
+
+	status := func() (types.ServerStatus, int) {
+		s, err := m.GetMcpServer("noisy")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m.ServerStatus(s)
+	}
+	waitForStatus := func(want types.ServerStatus, restarts int) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			got, n := status()
+			if got == want && n == restarts {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("status = %s with %d restarts, want %s with %d restarts", got, n, want, restarts)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	call := func() error {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "echo"
+		_, _, err := m.callUpstreamTool(context.Background(), s, "noisy__echo", req)
+		return err
+	}
+	waitForStatus(types.ServerStatusRunning, 0)
+
+	// the process is killed behind mcpjungle's back
+	p := stdioProcessOf(m.warm.get("noisy"))
+	if p == nil {
+		t.Fatal("the process of the warm stdio server is not tracked")
+	}
+	if err := p.cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	waitForStatus(types.ServerStatusRestarting, 0)
+	if err := call(); !errors.Is(err, ErrServerRestarting) {
+		t.Errorf("callUpstreamTool() while restarting error = %v, want ErrServerRestarting", err)
+	}
+	if tools := m.ArrangeProxyTools(context.Background(), []mcp.Tool{{Name: "noisy__echo"}, {Name: "other__echo"}}); len(tools) != 1 || tools[0].Name != "other__echo" {
+		t.Errorf("ArrangeProxyTools() while restarting = %v, want only the tools of other servers", tools)
+	}
+
+	waitForStatus(types.ServerStatusRunning, 1)
+	if err := call(); err != nil {
+		t.Errorf("callUpstreamTool() after the restart error = %v", err)
+	}
+
+	// sessions ended by mcpjungle are not restarted
+	p = stdioProcessOf(m.warm.get("noisy"))
+	m.warm.discard("noisy", nil)
+	<-p.exited
+	time.Sleep(100 * time.Millisecond)
+	if got, n := status(); got != types.ServerStatusIdle || n != 1 {
+		t.Errorf("status after the warm connection was closed = %s with %d restarts, want idle", got, n)
+	}
+}
+
+func TestRestartStdioServerStopsOnShutdown(t *testing.T) {
+	m := &MCPService{}
+	m.supervisor.minBackoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	m.SetShutdownContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		m.restartStdioServer("noisy")
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the restart loop is still waiting after the shutdown")
+	}
+}
@@ -3,29 +3,32 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/db"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	policyengine "github.com/mcpjungle/mcpjungle/internal/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
+	"github.com/mcpjungle/mcpjungle/internal/service/authorizer"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+	"github.com/mcpjungle/mcpjungle/pkg/events"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
 	"log"
+	"time"
 )
 
 // ListTools returns all tools registered in the registry.
 func (m *MCPService) ListTools() ([]model.Tool, error) {
-	var tools []model.Tool
-	if err := m.db.Find(&tools).Error; err != nil {
+	tools, _, err := m.catalog()
+	if err != nil {
 		return nil, err
 	}
-	// prepend server name to tool names to ensure we only return the unique names of tools to user
-	for i := range tools {
-		var s model.McpServer
-		if err := m.db.First(&s, "id = ?", tools[i].ServerID).Error; err != nil {
-			return nil, fmt.Errorf("failed to get server for tool %s: %w", tools[i].Name, err)
-		}
-		tools[i].Name = mergeServerToolNames(s.Name, tools[i].Name)
-	}
-	return tools, nil
+	// return a copy so that callers can't modify the cached catalog
+	return append([]model.Tool(nil), tools...), nil
 }
 
 // ListToolsByServer fetches tools provided by an MCP server from the registry.
@@ -40,7 +43,7 @@ func (m *MCPService) ListToolsByServer(name string) ([]model.Tool, error) {
 	}
 
 	var tools []model.Tool
-	if err := m.db.Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
+	if err := db.ReadReplica(m.db).Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
 		return nil, fmt.Errorf("failed to get tools for server %s from DB: %w", name, err)
 	}
 
@@ -52,12 +55,20 @@ func (m *MCPService) ListToolsByServer(name string) ([]model.Tool, error) {
 	return tools, nil
 }
 
+// GetTool returns a tool by its canonical name.
 func (m *MCPService) GetTool(name string) (*model.Tool, error) {
 	serverName, toolName, ok := splitServerToolName(name)
 	if !ok {
 		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
 	}
 
+	if tools, byName, err := m.catalog(); err == nil {
+		if i, ok := byName[name]; ok {
+			tool := tools[i]
+			return &tool, nil
+		}
+	}
+
 	s, err := m.GetMcpServer(serverName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get MCP server %s from DB: %w", serverName, err)
@@ -72,12 +83,34 @@ func (m *MCPService) GetTool(name string) (*model.Tool, error) {
 	return &tool, nil
 }
 
+// ErrAccessDenied is returned when the caller is not allowed to access the MCP server that provides a tool.
+var ErrAccessDenied = errors.New("access denied")
+
 // InvokeTool invokes a tool from a registered MCP server and returns its response.
 func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[string]any) (*types.ToolInvokeResult, error) {
 	serverName, toolName, ok := splitServerToolName(name)
 	if !ok {
 		return nil, fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
 	}
+	// tools are invoked via the API by users, unless an admin impersonates an MCP client
+	if c, ok := ctx.Value("client").(*model.McpClient); ok && c != nil && !m.checkToolAccess(c, serverName, name) {
+		return nil, fmt.Errorf("client %s cannot access MCP server %s: %w", c.Name, serverName, ErrAccessDenied)
+	}
+
+	callToolReq := mcp.CallToolRequest{}
+	callToolReq.Params.Name = toolName
+	ctx, cancel, err := m.withCallBudget(ctx, &callToolReq)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	inv, args, err := m.beginToolCall(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	defer m.releaseBudget(inv)
+
 	serverModel, err := m.GetMcpServer(serverName)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -86,21 +119,27 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 			err,
 		)
 	}
-
-	mcpClient, err := newMcpServerSession(ctx, serverModel)
-	if err != nil {
+	if serverModel, err = m.resolveServerForCall(ctx, serverModel, toolName); err != nil {
 		return nil, err
 	}
-	defer mcpClient.Close()
 
-	callToolReq := mcp.CallToolRequest{}
-	callToolReq.Params.Name = toolName
 	callToolReq.Params.Arguments = args
 
-	callToolResp, err := mcpClient.CallTool(ctx, callToolReq)
+	callToolResp, attempts, err := m.callTool(ctx, serverModel, name, callToolReq)
+	inv.Attempts = attempts
+	m.finishToolCall(inv, callToolResp, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", toolName, serverName, err)
 	}
+	if callToolResp, err = m.applyResultScript(ctx, name, args, callToolResp); err != nil {
+		return nil, err
+	}
+	if callToolResp, err = m.applyResponsePlugins(ctx, name, args, callToolResp); err != nil {
+		return nil, err
+	}
+	callToolResp = m.translateResult(ctx, name, callToolResp)
+	m.addStructuredContent(name, callToolResp)
+	m.signToolResult(name, callToolResp)
 
 	// NOTE: callToolResp.Content is a list of Content objects.
 	// If the tool returns a list as its result, it gets converted to a list of Content objects.
@@ -114,23 +153,19 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 	// Text, Image, etc.
 	contentList := make([]map[string]any, 0, len(callToolResp.Content))
 	for _, item := range callToolResp.Content {
-		var m map[string]any
-		serialized, err := json.Marshal(item)
+		m, err := contentToMap(item)
 		if err != nil {
 			// TODO
 			continue
 		}
-		if err = json.Unmarshal(serialized, &m); err != nil {
-			// TODO
-			continue
-		}
 		contentList = append(contentList, m)
 	}
 
 	result := &types.ToolInvokeResult{
-		Meta:    callToolResp.Meta,
-		IsError: callToolResp.IsError,
-		Content: contentList,
+		Meta:              callToolResp.Meta,
+		IsError:           callToolResp.IsError,
+		Content:           contentList,
+		StructuredContent: callToolResp.StructuredContent,
 	}
 	return result, nil
 }
@@ -141,8 +176,10 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 // The function returns a list of enabled tool names.
 // If the tool or server does not exist, it returns an error.
 // If the tool is already enabled, it returns the tool name without an error.
-func (m *MCPService) EnableTools(entity string) ([]string, error) {
-	return m.setToolsEnabled(entity, true)
+// If version is not 0, the entity must be a tool and the change is rejected with a *types.VersionConflict
+// if the tool is at another version.
+func (m *MCPService) EnableTools(entity string, version int) ([]string, error) {
+	return m.setToolsEnabled(entity, true, version)
 }
 
 // DisableTools disables one or more tools.
@@ -151,12 +188,13 @@ func (m *MCPService) EnableTools(entity string) ([]string, error) {
 // The function returns a list of disabled tool names.
 // If the tool or server does not exist, it returns an error.
 // If the tool is already disabled, it returns the tool name without an error.
-func (m *MCPService) DisableTools(entity string) ([]string, error) {
-	return m.setToolsEnabled(entity, false)
+// If version is not 0, the entity must be a tool, see EnableTools.
+func (m *MCPService) DisableTools(entity string, version int) ([]string, error) {
+	return m.setToolsEnabled(entity, false, version)
 }
 
 // setToolsEnabled does the heavy lifting of enabling or disabling one or more tools.
-func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, error) {
+func (m *MCPService) setToolsEnabled(entity string, enabled bool, version int) ([]string, error) {
 	serverName, toolName, ok := splitServerToolName(entity)
 	if ok {
 		// splitting was successful, so the entity is a tool name
@@ -171,14 +209,14 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 			return nil, fmt.Errorf("failed to get tool %s: %w", entity, err)
 		}
 
-		if tool.Enabled == enabled {
+		if tool.Enabled == enabled && (version == 0 || version == tool.Version) {
 			return []string{entity}, nil // no change needed
 		}
 
-		tool.Enabled = enabled
-		if err := m.db.Save(&tool).Error; err != nil {
+		if err := m.updateTool(&tool, entity, version, map[string]any{"enabled": enabled}); err != nil {
 			return nil, fmt.Errorf("failed to set tool %s enabled=%t: %w", entity, enabled, err)
 		}
+		tool.Enabled = enabled
 
 		if enabled {
 			// if the tool was enabled, add it back to the MCP proxy server
@@ -188,10 +226,10 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 			}
 			// set the tool name to its canonical form in the proxy
 			mcpTool.Name = entity
-			m.mcpProxyServer.AddTool(mcpTool, m.mcpProxyToolCallHandler)
+			m.addProxyTool(mcpTool)
 		} else {
 			// if the tool was disabled, remove it from the MCP proxy server
-			m.mcpProxyServer.DeleteTools(entity)
+			m.deleteProxyTools(entity)
 		}
 
 		return []string{entity}, nil
@@ -199,6 +237,9 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 
 	// splitting was unsuccessful, so the entity is a server name
 	// all tools of this server need to be enabled/disabled
+	if version != 0 {
+		return nil, fmt.Errorf("a version can only be given when enabling or disabling a single tool")
+	}
 	s, err := m.GetMcpServer(entity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get MCP server %s: %w", serverName, err)
@@ -214,11 +255,11 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		if tools[i].Enabled == enabled {
 			continue // no change needed
 		}
-		tools[i].Enabled = enabled
-		if err := m.db.Save(&tools[i]).Error; err != nil {
+		canonicalToolName := mergeServerToolNames(s.Name, tools[i].Name)
+		if err := m.updateTool(&tools[i], canonicalToolName, 0, map[string]any{"enabled": enabled}); err != nil {
 			return nil, fmt.Errorf("failed to set tool %s enabled=%t: %w", tools[i].Name, enabled, err)
 		}
-		canonicalToolName := mergeServerToolNames(s.Name, tools[i].Name)
+		tools[i].Enabled = enabled
 
 		if enabled {
 			mcpTool, err := convertToolModelToMcpObject(&tools[i])
@@ -228,9 +269,9 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 			// set the tool name to its canonical form in the proxy
 			mcpTool.Name = canonicalToolName
 
-			m.mcpProxyServer.AddTool(mcpTool, m.mcpProxyToolCallHandler)
+			m.addProxyTool(mcpTool)
 		} else {
-			m.mcpProxyServer.DeleteTools(canonicalToolName)
+			m.deleteProxyTools(canonicalToolName)
 		}
 
 		changedToolNames = append(changedToolNames, canonicalToolName)
@@ -239,61 +280,292 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 	return changedToolNames, nil
 }
 
-// registerServerTools fetches all tools from an MCP server and registers them in the DB.
-func (m *MCPService) registerServerTools(ctx context.Context, s *model.McpServer, c *client.Client) error {
-	// fetch all tools from the server so they can be added to the DB
-	resp, err := c.ListTools(ctx, mcp.ListToolsRequest{})
-	if err != nil {
-		return fmt.Errorf("failed to fetch tools from MCP server %s: %w", s.Name, err)
-	}
-	for _, tool := range resp.Tools {
-		canonicalToolName := mergeServerToolNames(s.Name, tool.GetName())
-
-		// extracting json schema is currently on best-effort basis
-		// if it fails, we log the error and continue with the next tool
-		jsonSchema, _ := json.Marshal(tool.InputSchema)
-
-		t := &model.Tool{
-			ServerID:    s.ID,
-			Name:        tool.GetName(),
-			Description: tool.Description,
-			InputSchema: jsonSchema,
-		}
-		if err := m.db.Create(t).Error; err != nil {
-			// If registration of a tool fails, we should not fail the entire server registration.
-			// Instead, continue with the next tool.
-			log.Printf("[ERROR] failed to register tool %s in DB: %v", canonicalToolName, err)
-		} else {
-			// Set tool name to include the server name prefix to make it recognizable by MCPJungle
-			// then add the tool to the MCP proxy server
-			tool.Name = canonicalToolName
-			m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
-		}
-	}
-	return nil
-}
-
-// deregisterServerTools deletes all tools that belong to an MCP server from the DB.
+// deregisterServerTools deletes all tools that belong to an MCP server from the DB, using the given transaction.
 // It also removes the tools from the MCP proxy server.
-func (m *MCPService) deregisterServerTools(s *model.McpServer) error {
+func (m *MCPService) deregisterServerTools(tx *gorm.DB, s *model.McpServer) error {
 	// load all tools for the server from the DB so we can delete them from the MCP proxy
-	tools, err := m.ListToolsByServer(s.Name)
-	if err != nil {
+	var tools []model.Tool
+	if err := tx.Where("server_id = ?", s.ID).Find(&tools).Error; err != nil {
 		return fmt.Errorf("failed to list tools for server %s: %w", s.Name, err)
 	}
 
 	// now it's safe to delete the server's tools from the DB
-	result := m.db.Unscoped().Where("server_id = ?", s.ID).Delete(&model.Tool{})
+	result := tx.Unscoped().Where("server_id = ?", s.ID).Delete(&model.Tool{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete tools for server %s: %w", s.Name, result.Error)
 	}
+	m.toolCatalog.invalidate()
 
 	// delete tools from MCP proxy server
 	toolNames := make([]string, len(tools), len(tools))
 	for i, tool := range tools {
-		toolNames[i] = tool.Name
+		toolNames[i] = mergeServerToolNames(s.Name, tool.Name)
+	}
+	m.deleteProxyTools(toolNames...)
+
+	return nil
+}
+
+// SetToolCost sets the pricing of a tool, which is used to compute the cost of every call to it.
+// If version is not 0, the change is rejected with a *types.VersionConflict if the tool is at another version.
+func (m *MCPService) SetToolCost(name string, costPerCall, costPerUnit float64, costUnitArg string, version int) error {
+	if costPerCall < 0 || costPerUnit < 0 {
+		return fmt.Errorf("tool costs must not be negative")
 	}
-	m.mcpProxyServer.DeleteTools(toolNames...)
+	if costPerUnit > 0 && costUnitArg == "" {
+		return fmt.Errorf("the input argument that holds the number of units must be specified with a cost per unit")
+	}
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return err
+	}
+	updates := map[string]any{
+		"cost_per_call": costPerCall,
+		"cost_per_unit": costPerUnit,
+		"cost_unit_arg": costUnitArg,
+	}
+	if err := m.updateTool(tool, name, version, updates); err != nil {
+		return fmt.Errorf("failed to set cost of tool %s: %w", name, err)
+	}
+	return nil
+}
 
+// updateTool updates columns of a tool and increments its version, unless the tool was modified since
+// the given version, in which case a *types.VersionConflict is returned.
+// A zero version stands for the version that the tool was read at.
+func (m *MCPService) updateTool(tool *model.Tool, name string, version int, updates map[string]any) error {
+	if version == 0 {
+		version = tool.Version
+	}
+	// the catalog is invalidated on conflicts too, the tool may have been modified through another instance
+	defer m.toolCatalog.invalidate()
+	if err := model.UpdateVersioned[model.Tool](m.db, "tool", name, tool.ID, version, updates); err != nil {
+		return err
+	}
+	tool.Version = version + 1
 	return nil
 }
+
+// beginToolCall rejects the call during an emergency stop, then evaluates the policies and enforces the budgets
+// of the caller before a tool is called.
+// It returns the usage record for the call, to be completed by finishToolCall, and the arguments
+// that the tool must be called with (policies may transform them).
+// The cost of the call is reserved in the caller's hard-stop budgets until releaseBudget is called.
+func (m *MCPService) beginToolCall(
+	ctx context.Context, name string, args map[string]any,
+) (*model.ToolInvocation, map[string]any, error) {
+	if err := m.checkEmergencyStop(name); err != nil {
+		return nil, nil, err
+	}
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientName, username := callerIdentity(ctx)
+	serverName, _, _ := splitServerToolName(name)
+
+	// the tool's own argument script runs first, everything else sees the arguments with its defaults applied
+	args, err = m.applyArgsScript(ctx, tool, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = m.eventBus.Check(ctx, events.Event{
+		Type:   events.ToolCall,
+		Server: serverName,
+		Tool:   name,
+		Client: clientName,
+		User:   username,
+		Args:   args,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// plugins run before the policies, so that the policies apply to the arguments the tool is called with
+	if m.wasmPlugins != nil {
+		args, err = m.wasmPlugins.OnRequest(ctx, wasm.Call{Tool: name, Client: clientName, User: username, Args: args})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	impersonatedBy, _ := ctx.Value("impersonated_by").(string)
+	err = m.authorizer.Authorize(ctx, &types.AuthorizationRequest{
+		Tool:           name,
+		Server:         serverName,
+		Client:         clientName,
+		User:           username,
+		ImpersonatedBy: impersonatedBy,
+		Args:           args,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, err = m.policyService.Evaluate(&policyengine.Input{
+		Client: clientName,
+		User:   username,
+		Tool:   toolPolicyMetadata(tool),
+		Args:   args,
+	})
+	if err != nil {
+		if errors.Is(err, policy.ErrPolicyDenied) {
+			m.eventBus.Publish(ctx, events.Event{
+				Type:   events.PolicyDenied,
+				Server: serverName,
+				Tool:   name,
+				Client: clientName,
+				User:   username,
+				Error:  err.Error(),
+			})
+			m.audit.Record(audit.Event{
+				Type:           audit.EventPolicyViolation,
+				Client:         clientName,
+				User:           username,
+				ImpersonatedBy: impersonatedBy,
+				Tool:           name,
+				Reason:         err.Error(),
+			})
+		}
+		return nil, nil, err
+	}
+
+	inv := &model.ToolInvocation{
+		ToolName:   name,
+		ClientName: clientName,
+		Username:   username,
+		Cost:       tool.CallCost(args),
+	}
+	// the creation time of the record marks the start of the call, it is used to measure the call's duration
+	inv.CreatedAt = time.Now()
+	inv.ImpersonatedBy = impersonatedBy
+	if samplePayload(tool) {
+		inv.Arguments = loggedArguments(args, tool.GetPayloadRedactions())
+	}
+	reservation, err := m.usageService.ReserveBudgets(clientName, username, inv.Cost)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reservation != nil {
+		m.budgetReservations.Store(inv, reservation)
+	}
+	return inv, args, nil
+}
+
+// releaseBudget releases the budget reservation of a tool call started by beginToolCall.
+// It must be called once the call is over, after finishToolCall recorded its cost if it got that far.
+func (m *MCPService) releaseBudget(inv *model.ToolInvocation) {
+	if r, ok := m.budgetReservations.LoadAndDelete(inv); ok {
+		r.(*usage.Reservation).Release()
+	}
+}
+
+// toolPolicyMetadata returns the metadata of a tool exposed to policies.
+func toolPolicyMetadata(t *model.Tool) map[string]any {
+	serverName, _, _ := splitServerToolName(t.Name)
+	annotations := map[string]any{}
+	if len(t.Annotations) > 0 {
+		_ = json.Unmarshal(t.Annotations, &annotations)
+	}
+	return map[string]any{
+		"name":        t.Name,
+		"server":      serverName,
+		"description": t.Description,
+		"annotations": annotations,
+	}
+}
+
+// finishToolCall records the usage of a tool call.
+// A call that failed to reach the upstream server is recorded as an error and not charged.
+func (m *MCPService) finishToolCall(inv *model.ToolInvocation, resp *mcp.CallToolResult, callErr error) {
+	if callErr != nil {
+		inv.IsError = true
+		inv.Cost = 0
+	} else if resp != nil {
+		inv.IsError = resp.IsError
+	}
+	inv.DurationMs = time.Since(inv.CreatedAt).Milliseconds()
+	if inv.Arguments != nil && resp != nil {
+		// the call was sampled for payload logging
+		if tool, err := m.GetTool(inv.ToolName); err == nil {
+			inv.Result = loggedResult(resp, tool.GetPayloadRedactions())
+		}
+	}
+	if err := m.usageService.RecordInvocation(inv); err != nil {
+		log.Printf("[ERROR] failed to record usage of tool %s: %v", inv.ToolName, err)
+	}
+	m.audit.Record(audit.Event{
+		Time:           inv.CreatedAt.UTC(),
+		Type:           audit.EventToolCall,
+		Client:         inv.ClientName,
+		User:           inv.Username,
+		ImpersonatedBy: inv.ImpersonatedBy,
+		Tool:           inv.ToolName,
+		IsError:        inv.IsError,
+		DurationMs:     inv.DurationMs,
+		Cost:           inv.Cost,
+	})
+	serverName, _, _ := splitServerToolName(inv.ToolName)
+	m.eventBus.Publish(context.Background(), events.Event{
+		Type:       events.ToolInvoked,
+		Time:       inv.CreatedAt.UTC(),
+		Server:     serverName,
+		Tool:       inv.ToolName,
+		Client:     inv.ClientName,
+		User:       inv.Username,
+		IsError:    inv.IsError,
+		DurationMs: inv.DurationMs,
+		Cost:       inv.Cost,
+	})
+}
+
+// SetAuthorizer makes every tool call subject to the decision of an external authorizer.
+func (m *MCPService) SetAuthorizer(a *authorizer.Authorizer) {
+	m.authorizer = a
+}
+
+// SetAuditService makes mcpjungle record every tool call in the given audit trail.
+func (m *MCPService) SetAuditService(a *audit.AuditService) {
+	m.audit = a
+}
+
+// contentToMap converts a content item of a tool's result to the map passed downstream.
+// The text, image & audio content is copied field by field so that large payloads (which mostly come as
+// one of these) are not serialized & parsed again, other types of content go through a JSON round-trip.
+func contentToMap(item mcp.Content) (map[string]any, error) {
+	var (
+		m           map[string]any
+		annotations *mcp.Annotations
+	)
+	switch c := item.(type) {
+	case mcp.TextContent:
+		m = map[string]any{"type": c.Type, "text": c.Text}
+		annotations = c.Annotations
+	case mcp.ImageContent:
+		m = map[string]any{"type": c.Type, "data": c.Data, "mimeType": c.MIMEType}
+		annotations = c.Annotations
+	case mcp.AudioContent:
+		m = map[string]any{"type": c.Type, "data": c.Data, "mimeType": c.MIMEType}
+		annotations = c.Annotations
+	default:
+		serialized, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(serialized, &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	if annotations != nil {
+		// annotations are small, the round-trip keeps them in the same shape as the other content
+		serialized, err := json.Marshal(annotations)
+		if err != nil {
+			return nil, err
+		}
+		var a map[string]any
+		if err = json.Unmarshal(serialized, &a); err != nil {
+			return nil, err
+		}
+		m["annotations"] = a
+	}
+	return m, nil
+}
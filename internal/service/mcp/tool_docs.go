@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/datatypes"
+)
+
+// SetToolDocs enriches the description of a tool that is served to MCP clients.
+// override replaces the description provided by the upstream server, appendix is added after it and
+// examples are listed at the end. All previously added documentation is replaced, empty values remove it.
+// If the tool is enabled, the MCP proxy starts serving the new description immediately.
+// If version is not 0, the change is rejected with a *types.VersionConflict if the tool is at another version.
+func (m *MCPService) SetToolDocs(name, override, appendix string, examples []string, version int) error {
+	tool, err := m.GetTool(name)
+	if err != nil {
+		return err
+	}
+	var examplesJSON any
+	if len(examples) > 0 {
+		b, err := json.Marshal(examples)
+		if err != nil {
+			return err
+		}
+		examplesJSON = datatypes.JSON(b)
+	}
+	err = m.updateTool(tool, name, version, map[string]any{
+		"description_override": override,
+		"description_append":   appendix,
+		"examples":             examplesJSON,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set documentation of tool %s: %w", name, err)
+	}
+
+	if tool.Enabled {
+		tool.DescriptionOverride = override
+		tool.DescriptionAppend = appendix
+		tool.Examples, _ = examplesJSON.(datatypes.JSON)
+		mcpTool, err := convertToolModelToMcpObject(tool)
+		if err != nil {
+			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", name, err)
+		}
+		m.addProxyTool(mcpTool)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestSetToolDocs(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	upstreamServer := server.NewMCPServer("search", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(
+		mcp.NewTool("query", mcp.WithDescription("Runs a query")),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		},
+	)
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("search", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	c, err := client.NewInProcessClient(m.mcpProxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	proxyDescription := func() string {
+		res, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil || len(res.Tools) != 1 {
+			t.Fatalf("ListTools() = %v, %v, want 1 tool", res, err)
+		}
+		return res.Tools[0].Description
+	}
+
+	if err := m.SetToolDocs("search__query", "", "Searches the product catalog.", []string{`{"q": "shoes"}`}, 0); err != nil {
+		t.Fatalf("SetToolDocs() error = %v", err)
+	}
+	want := "Runs a query\n\nSearches the product catalog.\n\nExamples:\n- {\"q\": \"shoes\"}"
+	if got := proxyDescription(); got != want {
+		t.Errorf("description after appending = %q, want %q", got, want)
+	}
+
+	if err := m.SetToolDocs("search__query", "Searches products", "", nil, 0); err != nil {
+		t.Fatalf("SetToolDocs() error = %v", err)
+	}
+	if got := proxyDescription(); got != "Searches products" {
+		t.Errorf("description after override = %q, want %q", got, "Searches products")
+	}
+
+	if err := m.SetToolDocs("search__query", "", "", nil, 0); err != nil {
+		t.Fatalf("SetToolDocs() error = %v", err)
+	}
+	if got := proxyDescription(); got != "Runs a query" {
+		t.Errorf("description after clearing = %q, want %q", got, "Runs a query")
+	}
+}
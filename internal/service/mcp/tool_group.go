@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+)
+
+// toolGroupProxy is the MCP proxy server of a tool group
+type toolGroupProxy struct {
+	tools     map[string]struct{}
+	mcpServer *server.MCPServer
+}
+
+// CreateToolGroup creates a new tool group and starts serving its MCP proxy.
+// All tools included in the group must exist in the registry.
+func (m *MCPService) CreateToolGroup(g *model.ToolGroup) error {
+	if err := validateServerName(g.Name); err != nil {
+		return fmt.Errorf("invalid tool group name: %w", err)
+	}
+	if g.ExtractionProfile != "" {
+		if _, err := m.GetExtractionProfile(g.ExtractionProfile); err != nil {
+			return fmt.Errorf("extraction profile %s not found: %w", g.ExtractionProfile, err)
+		}
+	}
+	tools := make([]*model.Tool, 0)
+	for _, name := range g.GetIncludedTools() {
+		t, err := m.GetTool(name)
+		if err != nil {
+			return fmt.Errorf("cannot add tool %s to group: %w", name, err)
+		}
+		tools = append(tools, t)
+	}
+
+	if err := m.db.Create(g).Error; err != nil {
+		return fmt.Errorf("failed to create tool group: %w", err)
+	}
+
+	p := m.newToolGroupProxy(g)
+	for _, t := range tools {
+		if !t.Enabled {
+			continue
+		}
+		mcpTool, err := convertToolModelToMcpObject(t)
+		if err != nil {
+			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", t.Name, err)
+		}
+		if m.lintBlocked(mcpTool) {
+			continue
+		}
+		p.mcpServer.AddTool(mcpTool, m.toolGroupToolCallHandler(g.Name))
+	}
+	return nil
+}
+
+// ListToolGroups returns all tool groups.
+func (m *MCPService) ListToolGroups() ([]*model.ToolGroup, error) {
+	var groups []*model.ToolGroup
+	if err := m.db.Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetToolGroup fetches a tool group by name.
+func (m *MCPService) GetToolGroup(name string) (*model.ToolGroup, error) {
+	var g model.ToolGroup
+	if err := m.db.Where("name = ?", name).First(&g).Error; err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// DeleteToolGroup deletes a tool group and stops serving its MCP proxy.
+// It is an idempotent operation. Deleting a group that does not exist will not return an error.
+func (m *MCPService) DeleteToolGroup(name string) error {
+	if err := m.db.Unscoped().Where("name = ?", name).Delete(&model.ToolGroup{}).Error; err != nil {
+		return err
+	}
+	m.groupsMu.Lock()
+	delete(m.toolGroups, name)
+	m.groupsMu.Unlock()
+	return nil
+}
+
+// GetToolGroupProxy returns the MCP proxy server of a tool group.
+func (m *MCPService) GetToolGroupProxy(name string) (*server.MCPServer, bool) {
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	p, ok := m.toolGroups[name]
+	if !ok {
+		return nil, false
+	}
+	return p.mcpServer, true
+}
+
+// initToolGroupProxies creates the (empty) MCP proxy servers of all tool groups in the DB.
+// Tools are added to them as they are added to the main MCP proxy server.
+func (m *MCPService) initToolGroupProxies() error {
+	groups, err := m.ListToolGroups()
+	if err != nil {
+		return fmt.Errorf("failed to list tool groups from DB: %w", err)
+	}
+	for _, g := range groups {
+		m.newToolGroupProxy(g)
+	}
+	return nil
+}
+
+func (m *MCPService) newToolGroupProxy(g *model.ToolGroup) *toolGroupProxy {
+	opts := []server.ServerOption{server.WithToolCapabilities(true)}
+	if m.proxyHooks != nil {
+		opts = append(opts, server.WithHooks(m.proxyHooks))
+	}
+	p := &toolGroupProxy{
+		tools:     make(map[string]struct{}),
+		mcpServer: server.NewMCPServer("MCPJungle Proxy MCP Server for tool group "+g.Name, "0.0.1", opts...),
+	}
+	for _, t := range g.GetIncludedTools() {
+		p.tools[t] = struct{}{}
+	}
+
+	m.groupsMu.Lock()
+	defer m.groupsMu.Unlock()
+	if m.toolGroups == nil {
+		m.toolGroups = make(map[string]*toolGroupProxy)
+	}
+	m.toolGroups[g.Name] = p
+	return p
+}
+
+// addProxyTool adds a tool to the main MCP proxy server and to the proxies of all tool groups that include it.
+// The tool's name must be in canonical form.
+// A tool that fails an enforced lint rule is removed from the proxies instead.
+func (m *MCPService) addProxyTool(tool mcp.Tool) {
+	if m.lintBlocked(tool) {
+		m.deleteProxyTools(tool.Name)
+		return
+	}
+	m.mcpProxyServer.AddTool(tool, m.mcpProxyToolCallHandler)
+
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	for name, p := range m.toolGroups {
+		if _, ok := p.tools[tool.Name]; ok {
+			p.mcpServer.AddTool(tool, m.toolGroupToolCallHandler(name))
+		}
+	}
+}
+
+// deleteProxyTools removes tools from the main MCP proxy server and from the proxies of all tool groups.
+func (m *MCPService) deleteProxyTools(names ...string) {
+	m.mcpProxyServer.DeleteTools(names...)
+
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	for _, p := range m.toolGroups {
+		p.mcpServer.DeleteTools(names...)
+	}
+}
+
+// toolGroupToolCallHandler returns the handler for tool calls made via the MCP proxy of a tool group.
+// Calls are handled like the ones made via the main proxy, but their results are post-processed
+// with the group's extraction profile, if any.
+func (m *MCPService) toolGroupToolCallHandler(group string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resp, err := m.mcpProxyToolCallHandler(ctx, request)
+		if err != nil || resp == nil || resp.IsError {
+			return resp, err
+		}
+		expr, err := m.extractionRule(group, request.Params.Name)
+		if err != nil {
+			log.Printf("[ERROR] failed to get extraction rule of tool group %s for tool %s: %v", group, request.Params.Name, err)
+			return resp, nil
+		}
+		if expr == "" {
+			return resp, nil
+		}
+		resp, err = applyExtraction(expr, resp)
+		if err != nil {
+			return nil, err
+		}
+		// the extraction changed the result, so its signature must be renewed
+		m.signToolResult(request.Params.Name, resp)
+		return resp, nil
+	}
+}
+
+// extractionRule returns the JSONPath expression that the extraction profile of a tool group applies
+// to the results of the given tool. It returns an empty string if there is no such expression.
+func (m *MCPService) extractionRule(group, tool string) (string, error) {
+	g, err := m.GetToolGroup(group)
+	if err != nil {
+		return "", err
+	}
+	if g.ExtractionProfile == "" {
+		return "", nil
+	}
+	p, err := m.GetExtractionProfile(g.ExtractionProfile)
+	if err != nil {
+		return "", err
+	}
+	return p.GetRules()[tool], nil
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestInvokeToolChecksClientAccess(t *testing.T) {
+	m := newTestMCPService(t, &model.AccessGrant{})
+
+	// an admin impersonating a client must be subject to the client's access control
+	c := &model.McpClient{Name: "cursor", AllowList: []byte(`["github"]`)}
+	ctx := context.WithValue(context.Background(), "client", c)
+
+	_, err := m.InvokeTool(ctx, "slack__post_message", map[string]any{})
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("InvokeTool() error = %v, want ErrAccessDenied", err)
+	}
+}
+
+func TestContentToMap(t *testing.T) {
+	text := mcp.NewTextContent("hello")
+	text.Annotations = &mcp.Annotations{Audience: []mcp.Role{mcp.RoleUser}, Priority: 0.5}
+	items := []mcp.Content{
+		text,
+		mcp.NewImageContent("aGk=", "image/png"),
+		mcp.NewAudioContent("aGk=", "audio/wav"),
+		mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "file:///a.txt", Text: "a"}),
+	}
+	for _, item := range items {
+		got, err := contentToMap(item)
+		if err != nil {
+			t.Fatalf("contentToMap(%T) error = %v", item, err)
+		}
+		// the result must be the same as with a JSON round-trip
+		var want map[string]any
+		serialized, _ := json.Marshal(item)
+		_ = json.Unmarshal(serialized, &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("contentToMap(%T) = %v, want %v", item, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/translation"
+)
+
+// SetTranslator makes the MCP proxy translate the text results of tool calls into the language
+// preferred by the calling MCP client, if it has one.
+func (m *MCPService) SetTranslator(p translation.Provider) {
+	m.translator = p
+}
+
+// translateResult returns the result of a tool call with its text content translated into the language of
+// the calling MCP client. Texts that are JSON documents are left as they are, so that they remain parseable.
+// Translation is best-effort: if it fails, the result is returned untranslated.
+func (m *MCPService) translateResult(ctx context.Context, name string, res *mcp.CallToolResult) *mcp.CallToolResult {
+	if m.translator == nil || res == nil {
+		return res
+	}
+	c, ok := ctx.Value("client").(*model.McpClient)
+	if !ok || c == nil || c.Language == "" {
+		return res
+	}
+
+	var texts []string
+	var indexes []int
+	for i, item := range res.Content {
+		text, ok := mcp.AsTextContent(item)
+		if !ok || text.Text == "" || json.Valid([]byte(text.Text)) {
+			continue
+		}
+		texts = append(texts, text.Text)
+		indexes = append(indexes, i)
+	}
+	if len(texts) == 0 {
+		return res
+	}
+	translated, err := m.translator.Translate(ctx, texts, c.Language)
+	if err != nil {
+		log.Printf("[WARN] failed to translate result of tool %s into %s: %v", name, c.Language, err)
+		return res
+	}
+
+	// the result is copied rather than modified, it may be shared with other callers (eg- by hedged calls)
+	out := *res
+	out.Content = append([]mcp.Content(nil), res.Content...)
+	for i, idx := range indexes {
+		text, _ := mcp.AsTextContent(res.Content[idx])
+		text.Text = translated[i]
+		out.Content[idx] = *text
+	}
+	return &out
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// upperTranslator "translates" texts by upper-casing them
+type upperTranslator struct{}
+
+func (upperTranslator) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = target + ":" + strings.ToUpper(t)
+	}
+	return out, nil
+}
+
+func TestTranslateResult(t *testing.T) {
+	m := &MCPService{}
+	m.SetTranslator(upperTranslator{})
+	res := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent("hello"),
+		mcp.NewTextContent(`{"greeting": "hello"}`),
+		mcp.NewImageContent("aGVsbG8=", "image/png"),
+	}}
+
+	// clients without a language get the result as it is
+	ctx := context.WithValue(context.Background(), "client", &model.McpClient{Name: "agent"})
+	if got := m.translateResult(ctx, "greeter__greet", res); got != res {
+		t.Errorf("translateResult() changed the result for a client without a language")
+	}
+
+	ctx = context.WithValue(context.Background(), "client", &model.McpClient{Name: "agent", Language: "fr"})
+	got := m.translateResult(ctx, "greeter__greet", res)
+	if text, _ := mcp.AsTextContent(got.Content[0]); text == nil || text.Text != "fr:HELLO" {
+		t.Errorf("translated text = %+v, want fr:HELLO", got.Content[0])
+	}
+	if text, _ := mcp.AsTextContent(got.Content[1]); text == nil || text.Text != `{"greeting": "hello"}` {
+		t.Errorf("JSON text = %+v, want it untranslated", got.Content[1])
+	}
+	if _, ok := mcp.AsImageContent(got.Content[2]); !ok {
+		t.Errorf("image content = %+v, want it untouched", got.Content[2])
+	}
+	if text, _ := mcp.AsTextContent(res.Content[0]); text.Text != "hello" {
+		t.Errorf("translateResult() modified the original result")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"io"
 	"log"
@@ -92,7 +93,7 @@ func isLoopbackURL(rawURL string) bool {
 func convertToolModelToMcpObject(t *model.Tool) (mcp.Tool, error) {
 	mcpTool := mcp.Tool{
 		Name:        t.Name,
-		Description: t.Description,
+		Description: t.EnrichedDescription(),
 	}
 
 	var inputSchema mcp.ToolInputSchema
@@ -103,17 +104,34 @@ func convertToolModelToMcpObject(t *model.Tool) (mcp.Tool, error) {
 	}
 	mcpTool.InputSchema = inputSchema
 
-	// TODO: Add other attributes to the tool, such as annotations
+	if len(t.Annotations) > 0 {
+		var annotations mcp.ToolAnnotation
+		if err := json.Unmarshal(t.Annotations, &annotations); err != nil {
+			return mcp.Tool{}, fmt.Errorf(
+				"failed to unmarshal annotations %s for tool %s: %w", t.Annotations, t.Name, err,
+			)
+		}
+		mcpTool.Annotations = annotations
+	}
+
+	// NOTE: the output schema of the tool is deliberately not advertised.
+	// The MCP spec requires tools with an output schema to return matching structured content,
+	// but mcp-go (v0.36) does not serialize the structuredContent of tool results sent by the proxy.
+	// Structured content is only returned by the API until it does.
+
 	// NOTE: if more fields are added to the tool in DB, they should be set here as well
 
 	return mcpTool, nil
 }
 
-// createHTTPMcpServerConn creates a new connection with a streamable http MCP server and returns the client.
-func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// createHTTPMcpServerConn creates a new connection with a streamable http MCP server.
+// It returns the client along with the server's response to the initialization request.
+func createHTTPMcpServerConn(
+	ctx context.Context, s *model.McpServer, svid *spiffe.Source,
+) (*client.Client, *mcp.InitializeResult, error) {
 	conf, err := s.GetStreamableHTTPConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get streamable HTTP config for MCP server %s: %w", s.Name, err)
+		return nil, nil, fmt.Errorf("failed to get streamable HTTP config for MCP server %s: %w", s.Name, err)
 	}
 
 	var opts []transport.StreamableHTTPCOption
@@ -124,10 +142,17 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 		})
 		opts = append(opts, o)
 	}
+	if conf.SpiffeID != "" {
+		o, err := spiffeTransportOption(svid, conf.SpiffeID)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, o)
+	}
 
 	c, err := client.NewStreamableHttpClient(conf.URL, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create streamable HTTP client for MCP server: %w", err)
+		return nil, nil, fmt.Errorf("failed to create streamable HTTP client for MCP server: %w", err)
 	}
 
 	initRequest := mcp.InitializeRequest{}
@@ -141,22 +166,22 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
 	defer cancel()
 
-	_, err = c.Initialize(initCtx, initRequest)
+	initResult, err := c.Initialize(initCtx, initRequest)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, fmt.Errorf("initialization request to MCP server timed out after %d seconds", serverInitRequestTimeout)
+			return nil, nil, fmt.Errorf("initialization request to MCP server timed out after %d seconds", serverInitRequestTimeout)
 		}
 		if errors.Is(err, syscall.ECONNREFUSED) && isLoopbackURL(conf.URL) {
-			return nil, fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"connection to the MCP server %s was refused. "+
 					"If mcpjungle is running inside Docker, use 'host.docker.internal' as your MCP server's hostname",
 				conf.URL,
 			)
 		}
-		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+		return nil, nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
 	}
 
-	return c, nil
+	return c, initResult, nil
 }
 
 // captureStdioServerStderr captures the stderr output of a stdio MCP server in the background
@@ -185,25 +210,19 @@ func captureStdioServerStderr(name string, c *client.Client) {
 	}()
 }
 
-// runStdioServer runs a stdio MCP server and returns the client.
-func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// runStdioServer runs a stdio MCP server.
+// It returns the client along with the server's response to the initialization request.
+func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, *mcp.InitializeResult, error) {
 	conf, err := s.GetStdioConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
-	}
-
-	// Convert the environment map to a slice of strings in the format "KEY=VALUE"
-	envVars := make([]string, 0)
-	if conf.Env != nil {
-		for k, v := range conf.Env {
-			envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
-		}
+		return nil, nil, fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
 	}
 
-	c, err := client.NewStdioMCPClient(conf.Command, envVars, conf.Args...)
+	stdioTransport, err := startStdioTransport(s.Name, conf)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdio client for MCP server: %w", err)
+		return nil, nil, fmt.Errorf("failed to create stdio client for MCP server: %w", err)
 	}
+	c := client.NewClient(stdioTransport)
 
 	// currently, we only capture the stderr output in the mcpjungle server logs.
 	// TODO: Propagate the stderr output to the client as well to provide them quicker feedback on errors.
@@ -220,39 +239,68 @@ func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, er
 	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
 	defer cancel()
 
-	_, err = c.Initialize(initCtx, initRequest)
+	initResult, err := c.Initialize(initCtx, initRequest)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"initialization request to MCP server timed out after %d seconds,"+
 					" check mcpungle server logs for any errors from this MCP server",
 				serverInitRequestTimeout,
 			)
 		}
-		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+		return nil, nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
 	}
 
-	return c, nil
+	return c, initResult, nil
 }
 
-func newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+func (m *MCPService) newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+	mcpClient, _, err := m.connectMcpServer(ctx, s)
+	return mcpClient, err
+}
+
+// connectMcpServer creates a new session with an MCP server.
+// It returns the client along with the server's response to the initialization request,
+// which contains the negotiated protocol version and the server's capabilities.
+func (m *MCPService) connectMcpServer(ctx context.Context, s *model.McpServer) (*client.Client, *mcp.InitializeResult, error) {
+	s, err := m.resolveSecrets(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.Transport == types.TransportBuiltin {
+		mcpClient, initResult, err := connectBuiltinServer(ctx, s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to built-in MCP server %s: %w", s.Name, err)
+		}
+		return mcpClient, initResult, nil
+	}
 	if s.Transport == types.TransportStreamableHTTP {
-		mcpClient, err := createHTTPMcpServerConn(ctx, s)
+		mcpClient, initResult, err := createHTTPMcpServerConn(ctx, s, m.spiffe)
 		if err != nil {
-			return nil, fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"failed to create connection to streamable http MCP server %s: %w", s.Name, err,
 			)
 		}
-		return mcpClient, nil
+		return mcpClient, initResult, nil
 	}
 
-	// A new sub-process is spun up for each call to a STDIO mcp server.
-	// This is especially a problem for the MCP proxy server, which is expected to call tools frequently.
-	// This causes a serious performance hit, but is easy to implement so it is used for now.
-	// TODO: Think of a better solution, ie, re-use connections to stdio MCP servers.
-	mcpClient, err := runStdioServer(ctx, s)
+	// A new sub-process is spun up for each call to a STDIO mcp server, unless it is marked keep_warm.
+	// Warm servers keep a single long-running process which is re-used by all tool calls (see warmPool).
+	mcpClient, initResult, err := runStdioServer(ctx, s)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run stdio MCP server %s: %w", s.Name, err)
+		return nil, nil, fmt.Errorf("failed to run stdio MCP server %s: %w", s.Name, err)
+	}
+	return mcpClient, initResult, nil
+}
+
+// callerIdentity returns the names of the MCP client and user on whose behalf a tool is being called.
+// These are only available in production mode, otherwise empty strings are returned.
+func callerIdentity(ctx context.Context) (clientName, username string) {
+	if c, ok := ctx.Value("client").(*model.McpClient); ok && c != nil {
+		clientName = c.Name
+	}
+	if u, ok := ctx.Value("user").(*model.User); ok && u != nil {
+		username = u.Username
 	}
-	return mcpClient, nil
+	return clientName, username
 }
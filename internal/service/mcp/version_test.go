@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestVersionedChanges(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	upstreamServer := server.NewMCPServer("calc", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("add"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("calc", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+
+	// the first admin disables the tool, the second one's change is based on the version before that
+	if _, err := m.DisableTools("calc__add", 1); err != nil {
+		t.Fatalf("DisableTools() error = %v", err)
+	}
+	_, err = m.EnableTools("calc__add", 1)
+	var conflict *types.VersionConflict
+	if !errors.As(err, &conflict) {
+		t.Fatalf("EnableTools() error = %v, want a version conflict", err)
+	}
+	if conflict.CurrentVersion != 2 || conflict.Diff["enabled"].Current != false {
+		t.Errorf("unexpected conflict: %+v", conflict)
+	}
+	if _, err := m.EnableTools("calc", 1); err == nil {
+		t.Error("EnableTools() accepted a version for a server")
+	}
+
+	if err := m.DeregisterMcpServer("calc", 7); !errors.As(err, &conflict) {
+		t.Fatalf("DeregisterMcpServer() error = %v, want a version conflict", err)
+	}
+	if _, err := m.GetTool("calc__add"); err != nil {
+		t.Fatalf("tool was deleted although the server deregistration conflicted: %v", err)
+	}
+	if err := m.DeregisterMcpServer("calc", 1); err != nil {
+		t.Fatalf("DeregisterMcpServer() error = %v", err)
+	}
+	if tools, _ := m.ListTools(); len(tools) != 0 {
+		t.Errorf("ListTools() = %d tools after the server was deregistered, want none", len(tools))
+	}
+}
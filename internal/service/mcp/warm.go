@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// warmPool holds long-lived connections to the MCP servers marked keep_warm.
+// These connections are shared by all tool calls to a server instead of creating a new one for every call.
+// For stdio servers, this means that the server process keeps running between calls.
+type warmPool struct {
+	mu      sync.Mutex
+	clients map[string]*client.Client
+
+	// ready is set once the warm-up at startup has completed
+	ready atomic.Bool
+}
+
+func (p *warmPool) get(name string) *client.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clients[name]
+}
+
+// put adds a connection to the pool. If the pool already has a connection to the server
+// (established concurrently), c is closed and the existing connection is returned instead.
+func (p *warmPool) put(name string, c *client.Client) *client.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.clients[name]; ok {
+		_ = c.Close()
+		return existing
+	}
+	if p.clients == nil {
+		p.clients = make(map[string]*client.Client)
+	}
+	p.clients[name] = c
+	return c
+}
+
+// discard closes & removes the pooled connection to a server.
+// If c is not nil, the connection is only discarded if it is still the pooled one.
+func (p *warmPool) discard(name string, c *client.Client) {
+	p.mu.Lock()
+	existing, ok := p.clients[name]
+	if !ok || (c != nil && existing != c) {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.clients, name)
+	p.mu.Unlock()
+
+	if err := existing.Close(); err != nil {
+		log.Printf("[WARN] failed to close warm connection to MCP server %s: %v", name, err)
+	}
+}
+
+// WarmUp connects to all MCP servers marked keep_warm, at most parallelism at a time.
+// It blocks until all connections are established or have failed, or until ctx is done.
+// Servers that fail to warm up are connected to on their first tool call instead.
+func (m *MCPService) WarmUp(ctx context.Context, parallelism int) error {
+	defer m.warm.ready.Store(true)
+
+	var servers []*model.McpServer
+	if err := m.db.Where("keep_warm = ? AND stopped = ?", true, false).Find(&servers).Error; err != nil {
+		return fmt.Errorf("failed to list MCP servers to keep warm: %w", err)
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	var warmed atomic.Int32
+	for _, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			c, err := m.connectUpstream(ctx, s)
+			if err != nil {
+				log.Printf("[WARN] failed to warm up MCP server %s: %v", s.Name, err)
+				m.notifyServerUnhealthy(s, err)
+				return
+			}
+			m.keepWarm(s, c)
+			warmed.Add(1)
+		}()
+	}
+	wg.Wait()
+
+	log.Printf("[INFO] warmed up %d of %d MCP servers marked keep_warm", warmed.Load(), len(servers))
+	return ctx.Err()
+}
+
+// snapshot returns the pooled connections by server name.
+func (p *warmPool) snapshot() map[string]*client.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clients := make(map[string]*client.Client, len(p.clients))
+	for name, c := range p.clients {
+		clients[name] = c
+	}
+	return clients
+}
+
+// maxHeartbeatTimeout is the maximum time a warm server is given to answer a ping
+const maxHeartbeatTimeout = 10 * time.Second
+
+// RunHeartbeat pings the warm connections every interval until ctx is done.
+// A connection whose ping fails (eg- because the server's process died or the remote server dropped an idle session)
+// is replaced with a new one, so that the next tool call doesn't fail with a stale connection.
+func (m *MCPService) RunHeartbeat(ctx context.Context, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			m.heartbeat(ctx, min(interval, maxHeartbeatTimeout))
+		}
+	}
+}
+
+// heartbeat pings all warm connections concurrently and recycles the dead ones.
+func (m *MCPService) heartbeat(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for name, c := range m.warm.snapshot() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			start := time.Now()
+			err := c.Ping(pingCtx)
+			cancel()
+			if err == nil {
+				m.setServerHealth(name, true, time.Since(start), nil)
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			metrics.UpstreamHeartbeatFailures.WithLabelValues(name).Inc()
+			log.Printf("[WARN] warm connection to MCP server %s is dead, reconnecting: %v", name, err)
+			m.warm.discard(name, c)
+
+			s, err := m.GetMcpServer(name)
+			if err != nil {
+				return // the server was deregistered in the meantime
+			}
+			fresh, err := m.connectUpstream(ctx, s)
+			if err != nil {
+				log.Printf("[WARN] failed to reconnect to MCP server %s: %v", name, err)
+				m.notifyServerUnhealthy(s, err)
+				return
+			}
+			m.keepWarm(s, fresh)
+		}()
+	}
+	wg.Wait()
+}
+
+// Ready returns true once the MCP servers marked keep_warm have been warmed up.
+func (m *MCPService) Ready() bool {
+	return m.warm.ready.Load()
+}
+
+// upstreamSession returns a client connected to an MCP server along with a function that must be called
+// once the client is no longer needed.
+// Servers marked keep_warm share a long-lived connection, all other servers get a new connection every time.
+func (m *MCPService) upstreamSession(ctx context.Context, s *model.McpServer) (*client.Client, func(), error) {
+	if !s.KeepWarm {
+		c, err := m.connectUpstream(ctx, s)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, func() { _ = c.Close() }, nil
+	}
+
+	if c := m.warm.get(s.Name); c != nil {
+		return c, func() {}, nil
+	}
+	if err := m.checkServerAvailable(s.Name); err != nil {
+		return nil, nil, err
+	}
+	c, err := m.connectUpstream(ctx, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m.keepWarm(s, c), func() {}, nil
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestWarmServerReusesConnection(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	// count the connections made to the upstream server
+	var inits atomic.Int32
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, req *mcp.InitializeRequest, res *mcp.InitializeResult) {
+		inits.Add(1)
+	})
+	upstreamServer := server.NewMCPServer("warm", "0.0.1", server.WithToolCapabilities(true), server.WithHooks(hooks))
+	upstreamServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("warm", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.KeepWarm = true
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	inits.Store(0)
+
+	if m.Ready() {
+		t.Fatal("Ready() = true before warm-up")
+	}
+	if err := m.WarmUp(context.Background(), 2); err != nil {
+		t.Fatalf("WarmUp() error = %v", err)
+	}
+	if !m.Ready() || inits.Load() != 1 {
+		t.Fatalf("after warm-up: ready = %v, connections = %d, want ready with 1 connection", m.Ready(), inits.Load())
+	}
+
+	for i := 0; i < 3; i++ {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "echo"
+		if _, _, err := m.callUpstreamTool(context.Background(), s, "warm__echo", req); err != nil {
+			t.Fatalf("callUpstreamTool() error = %v", err)
+		}
+	}
+	if got := inits.Load(); got != 1 {
+		t.Errorf("connections = %d after tool calls, want the warm connection to be re-used", got)
+	}
+
+	if err := m.DeregisterMcpServer("warm", 0); err != nil {
+		t.Fatalf("DeregisterMcpServer() error = %v", err)
+	}
+	if m.warm.get("warm") != nil {
+		t.Errorf("warm connection must be closed when the server is deregistered")
+	}
+}
+
+func TestHeartbeatRecyclesDeadConnection(t *testing.T) {
+	m := newTestMCPService(t, &model.McpServer{}, &model.Tool{})
+
+	upstreamServer := server.NewMCPServer("warm", "0.0.1", server.WithToolCapabilities(true))
+	upstreamServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	upstream := server.NewTestStreamableHTTPServer(upstreamServer)
+	defer upstream.Close()
+
+	s, err := model.NewStreamableHTTPServer("warm", "", upstream.URL+"/mcp", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.KeepWarm = true
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("RegisterMcpServer() error = %v", err)
+	}
+	if err := m.WarmUp(context.Background(), 1); err != nil {
+		t.Fatalf("WarmUp() error = %v", err)
+	}
+
+	healthy := m.warm.get("warm")
+	m.heartbeat(context.Background(), time.Second)
+	if m.warm.get("warm") != healthy {
+		t.Fatal("heartbeat replaced a healthy connection")
+	}
+
+	// simulate a connection that died while idle
+	_ = healthy.Close()
+	m.heartbeat(context.Background(), time.Second)
+	fresh := m.warm.get("warm")
+	if fresh == nil || fresh == healthy {
+		t.Fatal("heartbeat did not replace the dead connection")
+	}
+	if err := fresh.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() on the new connection error = %v", err)
+	}
+}
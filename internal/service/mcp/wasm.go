@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+)
+
+// SetWasmPlugins makes every tool call go through the hooks of the given WASM plugins.
+func (m *MCPService) SetWasmPlugins(p wasm.Plugins) {
+	m.wasmPlugins = p
+}
+
+// applyResponsePlugins passes the result of a successful tool call through the on_response hooks of
+// the WASM plugins. It returns the result unchanged if no plugin replaced it.
+func (m *MCPService) applyResponsePlugins(
+	ctx context.Context, name string, args map[string]any, res *mcp.CallToolResult,
+) (*mcp.CallToolResult, error) {
+	if m.wasmPlugins == nil || res == nil {
+		return res, nil
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize result of tool %s: %w", name, err)
+	}
+	clientName, username := callerIdentity(ctx)
+	out, err := m.wasmPlugins.OnResponse(ctx, wasm.Call{
+		Tool:   name,
+		Client: clientName,
+		User:   username,
+		Args:   args,
+		Result: b,
+	})
+	if err != nil || out == nil {
+		return res, err
+	}
+	var replaced mcp.CallToolResult
+	if err := json.Unmarshal(out, &replaced); err != nil {
+		return nil, fmt.Errorf("WASM plugins returned an invalid result for tool %s: %w", name, err)
+	}
+	return &replaced, nil
+}
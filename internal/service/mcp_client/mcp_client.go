@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/mcpjungle/mcpjungle/internal"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/spiffe"
 	"gorm.io/gorm"
 )
 
@@ -34,6 +35,15 @@ func (m *McpClientService) CreateClient(client model.McpClient) (*model.McpClien
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 	client.AccessToken = token
+	if client.SpiffeID != "" {
+		if err := spiffe.ValidateID(client.SpiffeID); err != nil {
+			return nil, err
+		}
+		// a SPIFFE ID must identify a single client
+		if existing, err := m.GetClientBySpiffeID(client.SpiffeID); err == nil {
+			return nil, fmt.Errorf("SPIFFE ID %s is already mapped to MCP client %s", client.SpiffeID, existing.Name)
+		}
+	}
 	if err := m.db.Create(&client).Error; err != nil {
 		return nil, err
 	}
@@ -53,9 +63,44 @@ func (m *McpClientService) GetClientByToken(token string) (*model.McpClient, err
 	return &client, nil
 }
 
+// GetClientBySpiffeID retrieves the MCP client mapped to a SPIFFE ID from the database.
+// It returns an error if no such client is found.
+func (m *McpClientService) GetClientBySpiffeID(spiffeID string) (*model.McpClient, error) {
+	var client model.McpClient
+	if err := m.db.Where("spiffe_id = ?", spiffeID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("client not found")
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// GetClient retrieves an MCP client by its name from the database.
+// It returns an error if no such client is found.
+func (m *McpClientService) GetClient(name string) (*model.McpClient, error) {
+	var client model.McpClient
+	if err := m.db.Where("name = ?", name).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("client not found")
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
 // DeleteClient removes an MCP client from the database and immediately revokes its access.
 // It is an idempotent operation. Deleting a client that does not exist will not return an error.
-func (m *McpClientService) DeleteClient(name string) error {
-	result := m.db.Unscoped().Where("name = ?", name).Delete(&model.McpClient{})
-	return result.Error
+// If version is not 0, the client is only deleted if it is still at that version,
+// otherwise a *types.VersionConflict is returned.
+func (m *McpClientService) DeleteClient(name string, version int) error {
+	if version == 0 {
+		result := m.db.Unscoped().Where("name = ?", name).Delete(&model.McpClient{})
+		return result.Error
+	}
+	client, err := m.GetClient(name)
+	if err != nil {
+		return err
+	}
+	return model.DeleteVersioned[model.McpClient](m.db, "client", name, client.ID, version)
 }
@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// NotificationService persists operational events so that admins can review them later.
+type NotificationService struct {
+	db *gorm.DB
+}
+
+func NewNotificationService(db *gorm.DB) *NotificationService {
+	return &NotificationService{db: db}
+}
+
+// Notify records an operational event.
+// If an unread notification of the same kind already exists for the subject, it is updated instead of
+// creating a new one, so that a recurring problem (eg- a server that is down) doesn't flood the notifications.
+// Failures are logged rather than returned, notifying must never fail the operation that raised the event.
+func (n *NotificationService) Notify(kind, subject, message string) {
+	n.NotifyByUser(kind, subject, "", message)
+}
+
+// NotifyByUser records an operational event raised by an action of the given user, like Notify.
+func (n *NotificationService) NotifyByUser(kind, subject, username, message string) {
+	if err := n.notify(kind, subject, username, message); err != nil {
+		log.Printf("[ERROR] failed to record %s notification for %s: %v", kind, subject, err)
+	}
+}
+
+func (n *NotificationService) notify(kind, subject, username, message string) error {
+	var existing model.Notification
+	err := n.db.Where("kind = ? AND subject = ? AND read_at IS NULL", kind, subject).First(&existing).Error
+	if err == nil {
+		return n.db.Model(&existing).Updates(map[string]any{
+			"message":  message,
+			"username": username,
+			"count":    gorm.Expr("count + 1"),
+		}).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return n.db.Create(&model.Notification{Kind: kind, Subject: subject, Message: message, Username: username, Count: 1}).Error
+}
+
+// ListNotifications returns the most recent notifications, newest first.
+// A non-positive limit returns all matching notifications.
+func (n *NotificationService) ListNotifications(unreadOnly bool, limit int) ([]*model.Notification, error) {
+	q := n.db.Order("updated_at DESC, id DESC")
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var notifications []*model.Notification
+	if err := q.Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkRead marks the notifications with the given IDs as read.
+// If no IDs are given, all unread notifications are marked as read.
+func (n *NotificationService) MarkRead(ids ...uint) error {
+	q := n.db.Model(&model.Notification{}).Where("read_at IS NULL")
+	if len(ids) > 0 {
+		q = q.Where("id IN ?", ids)
+	}
+	if err := q.Update("read_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return nil
+}
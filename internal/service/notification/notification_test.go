@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestNotifyDeduplicatesUnread(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Notification{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	n := NewNotificationService(db)
+
+	n.Notify(types.NotificationServerUnhealthy, "github", "connection refused")
+	n.Notify(types.NotificationServerUnhealthy, "github", "timed out")
+	n.Notify(types.NotificationServerUnhealthy, "slack", "connection refused")
+
+	unread, err := n.ListNotifications(true, 0)
+	if err != nil {
+		t.Fatalf("ListNotifications() error = %v", err)
+	}
+	if len(unread) != 2 {
+		t.Fatalf("got %d unread notifications, want 2", len(unread))
+	}
+	for _, notification := range unread {
+		if notification.Subject == "github" && (notification.Count != 2 || notification.Message != "timed out") {
+			t.Errorf("github notification: count = %d, message = %q, want 2 and the latest message",
+				notification.Count, notification.Message)
+		}
+	}
+
+	if err := n.MarkRead(); err != nil {
+		t.Fatalf("MarkRead() error = %v", err)
+	}
+	// once read, a recurring event raises a new notification
+	n.Notify(types.NotificationServerUnhealthy, "github", "connection refused")
+	all, _ := n.ListNotifications(false, 0)
+	unread, _ = n.ListNotifications(true, 0)
+	if len(all) != 3 || len(unread) != 1 {
+		t.Errorf("got %d notifications with %d unread, want 3 with 1 unread", len(all), len(unread))
+	}
+}
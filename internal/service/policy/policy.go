@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/policy"
+	"gorm.io/gorm"
+)
+
+// ErrPolicyDenied is returned when a tool call is denied by a policy
+var ErrPolicyDenied = errors.New("denied by policy")
+
+// compiledPolicy caches the evaluator of a policy along with the version of the policy it was compiled from
+type compiledPolicy struct {
+	updatedAt time.Time
+	evaluator policy.Evaluator
+}
+
+// PolicyService manages policies and evaluates them before tool invocations.
+type PolicyService struct {
+	db *gorm.DB
+
+	mu       sync.Mutex
+	compiled map[uint]*compiledPolicy
+}
+
+func NewPolicyService(db *gorm.DB) *PolicyService {
+	return &PolicyService{
+		db:       db,
+		compiled: make(map[uint]*compiledPolicy),
+	}
+}
+
+// CreatePolicy validates and stores a new policy.
+func (p *PolicyService) CreatePolicy(pol *model.Policy) error {
+	if pol.Name == "" {
+		return errors.New("policy name is required")
+	}
+	if _, err := policy.Compile(string(pol.Language), pol.Expression); err != nil {
+		return err
+	}
+	return p.db.Create(pol).Error
+}
+
+// ListPolicies returns all policies in the order in which they are evaluated.
+func (p *PolicyService) ListPolicies() ([]*model.Policy, error) {
+	var policies []*model.Policy
+	if err := p.db.Order("priority, name").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// DeletePolicy deletes a policy by name.
+// It is an idempotent operation. Deleting a policy that does not exist will not return an error.
+func (p *PolicyService) DeletePolicy(name string) error {
+	return p.db.Unscoped().Where("name = ?", name).Delete(&model.Policy{}).Error
+}
+
+// Evaluate runs all policies against a tool call, in order of priority.
+// It returns ErrPolicyDenied as soon as a policy denies the call.
+// Otherwise, it returns the arguments that the call must be made with, which are
+// the arguments returned by the last policy that transformed them (if any).
+func (p *PolicyService) Evaluate(in *policy.Input) (map[string]any, error) {
+	policies, err := p.ListPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+	for _, pol := range policies {
+		evaluator, err := p.evaluator(pol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile policy %s: %w", pol.Name, err)
+		}
+		d, err := evaluator.Evaluate(in)
+		if err != nil {
+			// fail closed, a broken policy must not let calls through
+			return nil, fmt.Errorf("%w %s: %v", ErrPolicyDenied, pol.Name, err)
+		}
+		switch d.Action {
+		case policy.ActionDeny:
+			if d.Reason != "" {
+				return nil, fmt.Errorf("%w %s: %s", ErrPolicyDenied, pol.Name, d.Reason)
+			}
+			return nil, fmt.Errorf("%w %s", ErrPolicyDenied, pol.Name)
+		case policy.ActionTransform:
+			// subsequent policies see the transformed arguments
+			in.Args = d.Args
+		}
+	}
+	return in.Args, nil
+}
+
+// evaluator returns the compiled evaluator of a policy, compiling it only if it changed since the last call.
+func (p *PolicyService) evaluator(pol *model.Policy) (policy.Evaluator, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.compiled[pol.ID]; ok && c.updatedAt.Equal(pol.UpdatedAt) {
+		return c.evaluator, nil
+	}
+	e, err := policy.Compile(string(pol.Language), pol.Expression)
+	if err != nil {
+		return nil, err
+	}
+	p.compiled[pol.ID] = &compiledPolicy{updatedAt: pol.UpdatedAt, evaluator: e}
+	return e, nil
+}
@@ -0,0 +1,270 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// Stores purged of the data of a subject, besides the audit sinks
+const (
+	purgeStoreInvocations   = "invocations"
+	purgeStoreProposals     = "server_proposals"
+	purgeStoreNotifications = "notifications"
+	purgeStoreToolViews     = "tool_views"
+	purgeStoreAccessGrants  = "access_grants"
+)
+
+// SetAuditService sets the audit trail whose locally stored events are purged along with the tables.
+func (r *RetentionService) SetAuditService(a *audit.AuditService) {
+	r.audit = a
+}
+
+// PurgeSubject deletes the stored tool invocations, server proposals, notifications, favorite tools & views,
+// expired access grants and audit events associated with an MCP client or user, then checks that none are left
+// and reports the outcome per store.
+// Records that only mention a user as the admin who impersonated a caller, reviewed a proposal or granted access
+// are kept, with the username removed from them.
+//
+// Unlike pruning, the invocations of the current month are deleted too, so the subject's spend towards
+// its budgets starts over. A failure in one store is reported in its result and doesn't stop the others.
+// An error is only returned for an invalid subject.
+func (r *RetentionService) PurgeSubject(subject types.DataSubject) (*types.PurgeReport, error) {
+	if err := subject.Validate(); err != nil {
+		return nil, err
+	}
+	report := &types.PurgeReport{Subject: subject, Results: []types.PurgeStoreResult{}}
+
+	report.Results = append(report.Results, r.purgeInvocations(subject), r.purgeAccessGrants(subject))
+	if subject.User != "" {
+		report.Results = append(
+			report.Results,
+			r.purgeProposals(subject.User),
+			r.purgeNotifications(subject.User),
+			r.purgeToolViews(subject.User),
+		)
+	}
+
+	for _, res := range r.audit.Purge(func(e audit.Event) bool { return subjectOf(e, subject) }) {
+		if !res.Purgeable {
+			report.Notes = append(report.Notes, fmt.Sprintf(
+				"audit events delivered to %s are stored outside of mcpjungle and must be purged there", res.Sink,
+			))
+			continue
+		}
+		result := types.PurgeStoreResult{Store: "audit:" + res.Sink, Deleted: res.Deleted, Remaining: res.Remaining}
+		if res.Err != nil {
+			result.Error = res.Err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	report.Verified = true
+	for _, res := range report.Results {
+		if res.Error != "" || res.Remaining > 0 {
+			report.Verified = false
+		}
+	}
+	report.Notes = append(report.Notes, r.remainingReferences(subject)...)
+	report.Notes = append(report.Notes,
+		"logs written by mcpjungle to stdout and stderr are not stored by it and must be purged from your log pipeline",
+	)
+	return report, nil
+}
+
+// subjectOf returns true if the audit event identifies the subject as the caller or the impersonating admin.
+func subjectOf(e audit.Event, subject types.DataSubject) bool {
+	if subject.Client != "" {
+		return e.Client == subject.Client
+	}
+	return e.User == subject.User || e.ImpersonatedBy == subject.User
+}
+
+func (r *RetentionService) purgeInvocations(subject types.DataSubject) types.PurgeStoreResult {
+	result := types.PurgeStoreResult{Store: purgeStoreInvocations}
+	owned := func() *gorm.DB {
+		q := r.db.Unscoped().Model(&model.ToolInvocation{})
+		if subject.Client != "" {
+			return q.Where("client_name = ?", subject.Client)
+		}
+		return q.Where("username = ?", subject.User)
+	}
+	res := owned().Delete(nil)
+	if res.Error != nil {
+		result.Error = res.Error.Error()
+		return result
+	}
+	result.Deleted = res.RowsAffected
+
+	// the spend of the subject's pruned invocations
+	prunedSpend := func() *gorm.DB {
+		q := r.db.Model(&model.PrunedSpend{})
+		if subject.Client != "" {
+			return q.Where("client_name = ?", subject.Client)
+		}
+		return q.Where("username = ?", subject.User)
+	}
+	if res = prunedSpend().Delete(nil); res.Error != nil {
+		result.Error = res.Error.Error()
+		return result
+	}
+	result.Deleted += res.RowsAffected
+
+	if subject.User != "" {
+		res = r.db.Unscoped().Model(&model.ToolInvocation{}).
+			Where("impersonated_by = ?", subject.User).Update("impersonated_by", "")
+		if res.Error != nil {
+			result.Error = res.Error.Error()
+			return result
+		}
+		result.Anonymized = res.RowsAffected
+	}
+
+	q := owned()
+	if subject.User != "" {
+		q = q.Or("impersonated_by = ?", subject.User)
+	}
+	if err := q.Count(&result.Remaining).Error; err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	var remainingSpend int64
+	if err := prunedSpend().Count(&remainingSpend).Error; err != nil {
+		result.Error = err.Error()
+	}
+	result.Remaining += remainingSpend
+	return result
+}
+
+func (r *RetentionService) purgeProposals(user string) types.PurgeStoreResult {
+	result := types.PurgeStoreResult{Store: purgeStoreProposals}
+	res := r.db.Unscoped().Where("proposed_by = ?", user).Delete(&model.ServerProposal{})
+	if res.Error != nil {
+		result.Error = res.Error.Error()
+		return result
+	}
+	result.Deleted = res.RowsAffected
+
+	res = r.db.Unscoped().Model(&model.ServerProposal{}).Where("reviewed_by = ?", user).Update("reviewed_by", "")
+	if res.Error != nil {
+		result.Error = res.Error.Error()
+		return result
+	}
+	result.Anonymized = res.RowsAffected
+
+	err := r.db.Unscoped().Model(&model.ServerProposal{}).
+		Where("proposed_by = ? OR reviewed_by = ?", user, user).Count(&result.Remaining).Error
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// purgeNotifications deletes the notifications raised by actions of the user, eg- the proposals of the user.
+func (r *RetentionService) purgeNotifications(user string) types.PurgeStoreResult {
+	result := types.PurgeStoreResult{Store: purgeStoreNotifications}
+	res := r.db.Unscoped().Where("username = ?", user).Delete(&model.Notification{})
+	if res.Error != nil {
+		result.Error = res.Error.Error()
+		return result
+	}
+	result.Deleted = res.RowsAffected
+
+	if err := r.db.Unscoped().Model(&model.Notification{}).Where("username = ?", user).Count(&result.Remaining).Error; err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// remainingReferences describes the configuration that still refers to the subject.
+// It is not purged because deleting it changes how mcpjungle behaves, so it's left to the admin.
+func (r *RetentionService) remainingReferences(subject types.DataSubject) []string {
+	var notes []string
+	column, name, kind := "client_name", subject.Client, "mcp-client"
+	account := r.db.Model(&model.McpClient{}).Where("name = ?", subject.Client)
+	if subject.User != "" {
+		column, name, kind = "username", subject.User, "user"
+		account = r.db.Model(&model.User{}).Where("username = ?", subject.User)
+	}
+
+	var n int64
+	if account.Count(&n).Error == nil && n > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"the %s still exists, delete it with 'mcpjungle delete %s %s'", subject, kind, name,
+		))
+	}
+	if r.db.Model(&model.Budget{}).Where(column+" = ?", name).Count(&n).Error == nil && n > 0 {
+		notes = append(notes, fmt.Sprintf("%d budgets still apply to the %s", n, subject))
+	}
+	if r.db.Model(&model.ResidencyPolicy{}).Where(column+" = ?", name).Count(&n).Error == nil && n > 0 {
+		notes = append(notes, fmt.Sprintf("%d residency policies still apply to the %s", n, subject))
+	}
+	if subject.Client != "" && r.activeAccessGrants(subject.Client).Count(&n).Error == nil && n > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"%d access grants of the %s are still active, revoke them with 'mcpjungle delete grant <id>'", n, subject,
+		))
+	}
+	return notes
+}
+
+// purgeToolViews deletes the favorite tools and the personal views of the user.
+func (r *RetentionService) purgeToolViews(user string) types.PurgeStoreResult {
+	result := types.PurgeStoreResult{Store: purgeStoreToolViews}
+	for _, m := range []any{&model.ToolFavorite{}, &model.ToolView{}} {
+		res := r.db.Unscoped().Where("username = ?", user).Delete(m)
+		if res.Error != nil {
+			result.Error = res.Error.Error()
+			return result
+		}
+		result.Deleted += res.RowsAffected
+
+		var remaining int64
+		if err := r.db.Unscoped().Model(m).Where("username = ?", user).Count(&remaining).Error; err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Remaining += remaining
+	}
+	return result
+}
+
+// purgeAccessGrants deletes the expired access grants of the client, and removes the user from the grants they created.
+// The active grants of the client are left to the admin, since revoking them changes what the client can call.
+func (r *RetentionService) purgeAccessGrants(subject types.DataSubject) types.PurgeStoreResult {
+	result := types.PurgeStoreResult{Store: purgeStoreAccessGrants}
+	var remaining *gorm.DB
+	if subject.Client != "" {
+		expired := func() *gorm.DB {
+			return r.db.Unscoped().Model(&model.AccessGrant{}).
+				Where("client_name = ? AND expires_at <= ?", subject.Client, time.Now())
+		}
+		res := expired().Delete(nil)
+		if res.Error != nil {
+			result.Error = res.Error.Error()
+			return result
+		}
+		result.Deleted = res.RowsAffected
+		remaining = expired()
+	} else {
+		res := r.db.Unscoped().Model(&model.AccessGrant{}).Where("granted_by = ?", subject.User).Update("granted_by", "")
+		if res.Error != nil {
+			result.Error = res.Error.Error()
+			return result
+		}
+		result.Anonymized = res.RowsAffected
+		remaining = r.db.Unscoped().Model(&model.AccessGrant{}).Where("granted_by = ?", subject.User)
+	}
+	if err := remaining.Count(&result.Remaining).Error; err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// activeAccessGrants selects the access grants of the client that haven't expired.
+func (r *RetentionService) activeAccessGrants(client string) *gorm.DB {
+	return r.db.Model(&model.AccessGrant{}).Where("client_name = ? AND expires_at > ?", client, time.Now())
+}
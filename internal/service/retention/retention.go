@@ -0,0 +1,189 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/audit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tables are the history tables that can be pruned, in the order in which they're pruned
+var tables = []string{types.RetentionTableInvocations, types.RetentionTableNotifications}
+
+// RetentionService deletes old rows from history tables so that long-running deployments don't grow unbounded.
+type RetentionService struct {
+	db       *gorm.DB
+	policies map[string]types.RetentionPolicy
+
+	// audit holds the audit trail that is purged along with the tables, it can be nil
+	audit *audit.AuditService
+}
+
+// NewRetentionService creates a service that prunes history tables according to the given policies,
+// keyed by table. Tables without a policy are never pruned.
+func NewRetentionService(db *gorm.DB, policies map[string]types.RetentionPolicy) (*RetentionService, error) {
+	if err := validatePolicies(policies); err != nil {
+		return nil, err
+	}
+	return &RetentionService{db: db, policies: policies}, nil
+}
+
+// Policies returns the configured retention policies, keyed by table.
+func (r *RetentionService) Policies() map[string]types.RetentionPolicy {
+	return r.policies
+}
+
+// Prune deletes the rows that are outside the retention policies and returns the number of rows deleted per table.
+// overrides replace the configured policies for the tables they're given for.
+//
+// Invocations made in the current month are never deleted, because daily & monthly budgets are computed from them.
+// The max number of rows of invocations is the number of invocations kept from before the current month,
+// on top of all those of the current month. The spend of the deleted invocations is added up per client & user
+// beforehand, for the budgets over the total period to keep counting it. Unread notifications are never deleted.
+func (r *RetentionService) Prune(overrides map[string]types.RetentionPolicy) ([]types.PruneResult, error) {
+	if err := validatePolicies(overrides); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	results := make([]types.PruneResult, 0, len(tables))
+	for _, table := range tables {
+		policy, ok := overrides[table]
+		if !ok {
+			policy, ok = r.policies[table]
+		}
+		if !ok || (policy.MaxAge == "" && policy.MaxRows == 0) {
+			continue
+		}
+
+		var deleted int64
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var q *gorm.DB
+			var beforeDelete func(*gorm.DB) error
+			switch table {
+			case types.RetentionTableInvocations:
+				q = tx.Unscoped().Model(&model.ToolInvocation{}).
+					Where("created_at < ?", model.BudgetPeriodMonthly.Start(now))
+				beforeDelete = rollUpSpend
+			case types.RetentionTableNotifications:
+				q = tx.Unscoped().Model(&model.Notification{}).Where("read_at IS NOT NULL")
+			}
+			var err error
+			deleted, err = pruneTable(q, policy, now, beforeDelete)
+			return err
+		})
+		if err != nil {
+			return results, fmt.Errorf("failed to prune %s: %w", table, err)
+		}
+		results = append(results, types.PruneResult{Table: table, Policy: policy, Deleted: deleted})
+	}
+	return results, nil
+}
+
+// Run prunes the history tables periodically according to the configured policies until ctx is cancelled.
+func (r *RetentionService) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		results, err := r.Prune(nil)
+		if err != nil {
+			log.Printf("[ERROR] failed to prune history: %v", err)
+		}
+		for _, res := range results {
+			if res.Deleted > 0 {
+				log.Printf("[INFO] pruned %d rows from %s", res.Deleted, res.Table)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// pruneTable deletes the rows selected by q that are older than the policy's max age
+// or beyond its max number of rows (newest rows first).
+// beforeDelete, if not nil, is called with the selection of the rows about to be deleted.
+func pruneTable(q *gorm.DB, policy types.RetentionPolicy, now time.Time, beforeDelete func(*gorm.DB) error) (int64, error) {
+	deleteRows := func(sel *gorm.DB) (int64, error) {
+		if beforeDelete != nil {
+			if err := beforeDelete(sel.Session(&gorm.Session{})); err != nil {
+				return 0, err
+			}
+		}
+		res := sel.Delete(nil)
+		return res.RowsAffected, res.Error
+	}
+
+	var deleted int64
+	if policy.MaxAge != "" {
+		maxAge, _ := types.ParseLookback(policy.MaxAge)
+		n, err := deleteRows(q.Session(&gorm.Session{}).Where("created_at < ?", now.Add(-maxAge)))
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	if policy.MaxRows > 0 {
+		// find the newest row beyond the limit, it and all older rows are deleted
+		var ids []uint
+		err := q.Session(&gorm.Session{}).Order("id DESC").Offset(policy.MaxRows).Limit(1).Pluck("id", &ids).Error
+		if err != nil {
+			return deleted, err
+		}
+		if len(ids) > 0 {
+			n, err := deleteRows(q.Session(&gorm.Session{}).Where("id <= ?", ids[0]))
+			if err != nil {
+				return deleted, err
+			}
+			deleted += n
+		}
+	}
+	return deleted, nil
+}
+
+// rollUpSpend adds the spend of the invocations selected by q to the spend pruned from their client or user.
+func rollUpSpend(q *gorm.DB) error {
+	var spends []model.PrunedSpend
+	err := q.Session(&gorm.Session{}).Where("cost > 0").
+		Select("client_name, username, SUM(cost) AS cost").Group("client_name, username").Scan(&spends).Error
+	if err != nil {
+		return fmt.Errorf("failed to add up the spend of the pruned invocations: %w", err)
+	}
+	for _, spend := range spends {
+		err := q.Session(&gorm.Session{NewDB: true}).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "client_name"}, {Name: "username"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"cost": gorm.Expr("pruned_spends.cost + ?", spend.Cost),
+			}),
+		}).Create(&spend).Error
+		if err != nil {
+			return fmt.Errorf("failed to record the spend of the pruned invocations: %w", err)
+		}
+	}
+	return nil
+}
+
+func validatePolicies(policies map[string]types.RetentionPolicy) error {
+	for table, p := range policies {
+		if !slices.Contains(tables, table) {
+			return fmt.Errorf("unknown history table '%s', must be one of %v", table, tables)
+		}
+		if p.MaxAge != "" {
+			if _, err := types.ParseLookback(p.MaxAge); err != nil {
+				return fmt.Errorf("invalid max age of %s: %w", table, err)
+			}
+		}
+		if p.MaxRows < 0 {
+			return fmt.Errorf("invalid max rows of %s: must not be negative", table)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package retention
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestPrune(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolInvocation{}, &model.Notification{}, &model.PrunedSpend{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	now := time.Now()
+	lastMonth := model.BudgetPeriodMonthly.Start(now).Add(-time.Hour)
+	for _, age := range []time.Duration{400 * 24 * time.Hour, 100 * 24 * time.Hour, 50 * 24 * time.Hour} {
+		inv := &model.ToolInvocation{ToolName: "a__b", Username: "alice", Cost: 1.5}
+		inv.CreatedAt = lastMonth.Add(-age)
+		db.Create(inv)
+	}
+	for range 2 {
+		inv := &model.ToolInvocation{ToolName: "a__b"}
+		inv.CreatedAt = lastMonth
+		db.Create(inv)
+	}
+	// calls made this month count towards budgets, they must be kept
+	db.Create(&model.ToolInvocation{ToolName: "a__b"})
+
+	read := now.Add(-24 * time.Hour)
+	for range 3 {
+		n := &model.Notification{Kind: "k", Subject: "s", ReadAt: &read}
+		n.CreatedAt = now.Add(-60 * 24 * time.Hour)
+		db.Create(n)
+	}
+	unread := &model.Notification{Kind: "k", Subject: "s"}
+	unread.CreatedAt = now.Add(-60 * 24 * time.Hour)
+	db.Create(unread)
+
+	r, err := NewRetentionService(db, map[string]types.RetentionPolicy{
+		types.RetentionTableInvocations: {MaxAge: "90d", MaxRows: 2},
+	})
+	if err != nil {
+		t.Fatalf("NewRetentionService() error = %v", err)
+	}
+	if _, err := NewRetentionService(db, map[string]types.RetentionPolicy{"users": {MaxRows: 1}}); err == nil {
+		t.Error("NewRetentionService() with an unknown table succeeded, want error")
+	}
+
+	results, err := r.Prune(map[string]types.RetentionPolicy{types.RetentionTableNotifications: {MaxAge: "30d"}})
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	want := map[string]int64{types.RetentionTableInvocations: 3, types.RetentionTableNotifications: 3}
+	if len(results) != len(want) {
+		t.Fatalf("Prune() = %+v, want results for %d tables", results, len(want))
+	}
+	for _, res := range results {
+		if res.Deleted != want[res.Table] {
+			t.Errorf("Prune() deleted %d rows from %s, want %d", res.Deleted, res.Table, want[res.Table])
+		}
+	}
+
+	var invocations, notifications int64
+	db.Unscoped().Model(&model.ToolInvocation{}).Count(&invocations)
+	db.Unscoped().Model(&model.Notification{}).Count(&notifications)
+	if invocations != 3 || notifications != 1 {
+		t.Errorf("%d invocations and %d notifications left, want 3 and 1", invocations, notifications)
+	}
+
+	// the spend of the deleted invocations still counts towards total budgets
+	var spends []model.PrunedSpend
+	db.Find(&spends)
+	if len(spends) != 1 || spends[0].Username != "alice" || spends[0].Cost != 4.5 {
+		t.Fatalf("pruned spend = %+v, want 4.5 for alice", spends)
+	}
+	inv := &model.ToolInvocation{ToolName: "a__b", Username: "alice", Cost: 1}
+	inv.CreatedAt = lastMonth.Add(-200 * 24 * time.Hour)
+	db.Create(inv)
+	if _, err := r.Prune(nil); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	db.Find(&spends)
+	if len(spends) != 1 || spends[0].Cost != 5.5 {
+		t.Errorf("pruned spend = %+v, want 5.5 for alice", spends)
+	}
+}
+
+func TestPurgeSubject(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	err = db.AutoMigrate(
+		&model.ToolInvocation{}, &model.Notification{}, &model.ServerProposal{}, &model.User{}, &model.McpClient{},
+		&model.Budget{}, &model.ResidencyPolicy{}, &model.ToolFavorite{}, &model.ToolView{}, &model.PrunedSpend{},
+		&model.AccessGrant{},
+	)
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	db.Create(&model.ToolInvocation{ToolName: "a__b", Username: "alice"})
+	db.Create(&model.ToolInvocation{ToolName: "a__b", Username: "alice"})
+	db.Create(&model.ToolInvocation{ToolName: "a__b", ClientName: "cursor", ImpersonatedBy: "alice"})
+	db.Create(&model.ToolInvocation{ToolName: "a__b", Username: "bob"})
+	db.Create(&model.PrunedSpend{Username: "alice", Cost: 3})
+	db.Create(&model.PrunedSpend{Username: "bob", Cost: 1})
+	db.Create(&model.ServerProposal{Name: "github", Server: []byte(`{}`), ProposedBy: "alice"})
+	db.Create(&model.ServerProposal{Name: "slack", Server: []byte(`{}`), ProposedBy: "bob", ReviewedBy: "alice"})
+	db.Create(&model.Notification{Kind: types.NotificationServerProposed, Subject: "github", Message: "alice proposed to register MCP server github", Username: "alice"})
+	db.Create(&model.Notification{Kind: types.NotificationServerProposed, Subject: "slack", Message: "bob proposed to register MCP server slack", Username: "bob"})
+	// a notification that only mentions the user in its message is not theirs
+	db.Create(&model.Notification{Kind: types.NotificationServerProposed, Subject: "alice", Message: "bob proposed to register MCP server alice", Username: "bob"})
+	db.Create(&model.User{Username: "alice"})
+	db.Create(&model.ToolFavorite{Username: "alice", Tool: "github__search"})
+	db.Create(&model.ToolView{Username: "alice", Name: "triage", Tools: []byte(`["github"]`)})
+	db.Create(&model.ToolFavorite{Username: "bob", Tool: "github__search"})
+	db.Create(&model.AccessGrant{ClientName: "cursor", ToolGroup: "k8s", ExpiresAt: time.Now().Add(time.Hour), GrantedBy: "alice"})
+	db.Create(&model.AccessGrant{ClientName: "cursor", ToolGroup: "k8s", ExpiresAt: time.Now().Add(-time.Hour), GrantedBy: "alice"})
+	db.Create(&model.AccessGrant{ClientName: "claude", ToolGroup: "k8s", ExpiresAt: time.Now().Add(-time.Hour), GrantedBy: "bob"})
+
+	r, err := NewRetentionService(db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.PurgeSubject(types.DataSubject{Client: "cursor", User: "alice"}); err == nil {
+		t.Error("PurgeSubject() with both a client and a user succeeded, want error")
+	}
+
+	report, err := r.PurgeSubject(types.DataSubject{User: "alice"})
+	if err != nil {
+		t.Fatalf("PurgeSubject() error = %v", err)
+	}
+	if !report.Verified {
+		t.Errorf("PurgeSubject() report is not verified: %+v", report)
+	}
+	want := map[string][2]int64{
+		purgeStoreInvocations:   {3, 1},
+		purgeStoreProposals:     {1, 1},
+		purgeStoreNotifications: {1, 0},
+		purgeStoreToolViews:     {2, 0},
+		purgeStoreAccessGrants:  {0, 2},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("PurgeSubject() = %+v, want results for %d stores", report.Results, len(want))
+	}
+	for _, res := range report.Results {
+		if w := want[res.Store]; res.Deleted != w[0] || res.Anonymized != w[1] || res.Remaining != 0 {
+			t.Errorf("unexpected result for %s: %+v", res.Store, res)
+		}
+	}
+	if len(report.Notes) != 2 || !strings.Contains(report.Notes[0], "mcpjungle delete user alice") {
+		t.Errorf("unexpected notes: %v", report.Notes)
+	}
+
+	var invocations, notifications int64
+	db.Unscoped().Model(&model.ToolInvocation{}).Count(&invocations)
+	db.Unscoped().Model(&model.Notification{}).Count(&notifications)
+	if invocations != 2 || notifications != 2 {
+		t.Errorf("%d invocations and %d notifications left, want 2 and 2", invocations, notifications)
+	}
+
+	// the expired access grants of a client are deleted, its active ones are reported
+	report, err = r.PurgeSubject(types.DataSubject{Client: "cursor"})
+	if err != nil {
+		t.Fatalf("PurgeSubject() error = %v", err)
+	}
+	for _, res := range report.Results {
+		if res.Store == purgeStoreAccessGrants && (res.Deleted != 1 || res.Remaining != 0) {
+			t.Errorf("unexpected result for %s: %+v", res.Store, res)
+		}
+	}
+	if !slices.ContainsFunc(report.Notes, func(n string) bool { return strings.Contains(n, "1 access grants") }) {
+		t.Errorf("expected the active access grant of the client to be reported, got %v", report.Notes)
+	}
+	var grants int64
+	db.Model(&model.AccessGrant{}).Count(&grants)
+	if grants != 2 {
+		t.Errorf("%d access grants left, want 2", grants)
+	}
+}
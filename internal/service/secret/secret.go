@@ -0,0 +1,221 @@
+// Package secret manages the secrets stored in the registry.
+// The configuration of MCP servers refers to secrets by name with ${secret:NAME}, the references are
+// resolved every time a server is connected to, so rotating a secret doesn't require re-registering the servers.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// encryptedPrefix marks the values encrypted with AES-256-GCM
+const encryptedPrefix = "aes256gcm:"
+
+// namePattern is the syntax of the names of secrets
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// referencePattern matches the references to secrets in the configuration of MCP servers
+var referencePattern = regexp.MustCompile(`\$\{secret:([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// ErrNotFound is returned when a secret does not exist
+var ErrNotFound = errors.New("secret not found")
+
+// SecretService stores secrets and resolves the references to them.
+type SecretService struct {
+	db   *gorm.DB
+	aead cipher.AEAD
+}
+
+// NewSecretService creates a secret store. If key (the base64 encoding of 32 random bytes) is not empty,
+// secret values are encrypted with it in the database, and the values stored in plaintext so far are encrypted.
+// Otherwise, they are stored in plaintext.
+func NewSecretService(db *gorm.DB, key string) (*SecretService, error) {
+	s := &SecretService{db: db}
+	if key == "" {
+		log.Printf("[WARN] no secrets encryption key is set, secrets are stored in plaintext in the database")
+		return s, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("the secrets encryption key must be the base64 encoding of 32 bytes")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	if s.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+	// the secrets stay readable if this fails, it is attempted again at the next startup
+	if err := s.sealPlaintext(); err != nil {
+		log.Printf("[ERROR] failed to encrypt the secrets stored in plaintext: %v", err)
+	}
+	return s, nil
+}
+
+// Encrypted returns true if secret values are encrypted in the database.
+func (s *SecretService) Encrypted() bool {
+	return s.aead != nil
+}
+
+// Set creates a secret or changes its value.
+func (s *SecretService) Set(name, value string) (*model.Secret, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid secret name '%s', it must start with a letter or underscore "+
+			"and only contain letters, digits, underscores, dots and dashes", name)
+	}
+	if value == "" {
+		return nil, errors.New("secret value must not be empty")
+	}
+	stored, err := s.seal(value)
+	if err != nil {
+		return nil, err
+	}
+	secret := &model.Secret{Name: name, Value: stored, Version: 1}
+	err = s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"value":      stored,
+			"version":    gorm.Expr("secrets.version + 1"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(secret).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to store secret %s: %w", name, err)
+	}
+	return s.get(name)
+}
+
+// List returns all secrets, without their values.
+func (s *SecretService) List() ([]*model.Secret, error) {
+	var secrets []*model.Secret
+	if err := s.db.Order("name").Find(&secrets).Error; err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// Delete deletes a secret.
+func (s *SecretService) Delete(name string) error {
+	res := s.db.Unscoped().Where("name = ?", name).Delete(&model.Secret{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return nil
+}
+
+// Resolve replaces the references to secrets (${secret:NAME}) in value by the values of the secrets.
+func (s *SecretService) Resolve(value string) (string, error) {
+	var resolveErr error
+	resolved := referencePattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := referencePattern.FindStringSubmatch(ref)[1]
+		v, err := s.value(name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return v
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// References returns the names of the secrets referred to in value.
+func References(value string) []string {
+	var names []string
+	for _, m := range referencePattern.FindAllStringSubmatch(value, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func (s *SecretService) get(name string) (*model.Secret, error) {
+	var secret model.Secret
+	err := s.db.Where("name = ?", name).First(&secret).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// value returns the plaintext value of a secret.
+func (s *SecretService) value(name string) (string, error) {
+	secret, err := s.get(name)
+	if err != nil {
+		return "", err
+	}
+	return s.open(secret)
+}
+
+// seal returns the value to store for a secret.
+func (s *SecretService) seal(value string) (string, error) {
+	if s.aead == nil {
+		return value, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// sealPlaintext encrypts the values of the secrets stored in plaintext, eg- before an encryption key was set.
+// Their versions are unchanged since their values are.
+func (s *SecretService) sealPlaintext() error {
+	var secrets []*model.Secret
+	if err := s.db.Where("value NOT LIKE ?", encryptedPrefix+"%").Find(&secrets).Error; err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		stored, err := s.seal(secret.Value)
+		if err != nil {
+			return err
+		}
+		if err := s.db.Model(secret).Update("value", stored).Error; err != nil {
+			return fmt.Errorf("failed to store secret %s: %w", secret.Name, err)
+		}
+	}
+	if len(secrets) > 0 {
+		log.Printf("[INFO] encrypted %d secrets that were stored in plaintext", len(secrets))
+	}
+	return nil
+}
+
+// open returns the plaintext value of a stored secret.
+func (s *SecretService) open(secret *model.Secret) (string, error) {
+	if !strings.HasPrefix(secret.Value, encryptedPrefix) {
+		return secret.Value, nil
+	}
+	if s.aead == nil {
+		return "", fmt.Errorf("secret %s is encrypted but no secrets encryption key is configured", secret.Name)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret.Value, encryptedPrefix))
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return "", fmt.Errorf("secret %s is corrupt", secret.Name)
+	}
+	n := s.aead.NonceSize()
+	plain, err := s.aead.Open(nil, sealed[:n], sealed[n:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s, was the encryption key changed? %w", secret.Name, err)
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,106 @@
+package secret
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testKey is the base64 encoding of 32 bytes
+const testKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Secret{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func TestSecretService(t *testing.T) {
+	db := newTestDB(t)
+	s, err := NewSecretService(db, testKey)
+	if err != nil {
+		t.Fatalf("NewSecretService() error = %v", err)
+	}
+
+	if _, err := s.Set("OPENAI_KEY", "sk-1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	secret, err := s.Set("OPENAI_KEY", "sk-2")
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if secret.Version != 2 {
+		t.Errorf("secret version = %d after rotation, want 2", secret.Version)
+	}
+	if !strings.HasPrefix(secret.Value, encryptedPrefix) || strings.Contains(secret.Value, "sk-2") {
+		t.Errorf("secret is stored as %q, want it encrypted", secret.Value)
+	}
+
+	got, err := s.Resolve("Bearer ${secret:OPENAI_KEY}")
+	if err != nil || got != "Bearer sk-2" {
+		t.Errorf("Resolve() = %q, %v, want %q", got, err, "Bearer sk-2")
+	}
+	if _, err := s.Resolve("${secret:MISSING}"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve() error = %v for a missing secret, want ErrNotFound", err)
+	}
+
+	other, err := NewSecretService(db, "")
+	if err != nil {
+		t.Fatalf("NewSecretService() error = %v", err)
+	}
+	if _, err := other.Resolve("${secret:OPENAI_KEY}"); err == nil {
+		t.Error("resolved an encrypted secret without the encryption key")
+	}
+
+	if _, err := s.Set("not a name", "x"); err == nil {
+		t.Error("Set() accepted an invalid name")
+	}
+	if err := s.Delete("OPENAI_KEY"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete("OPENAI_KEY"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() error = %v for a deleted secret, want ErrNotFound", err)
+	}
+}
+
+func TestSecretsAreEncryptedOnceAKeyIsSet(t *testing.T) {
+	db := newTestDB(t)
+	plain, err := NewSecretService(db, "")
+	if err != nil {
+		t.Fatalf("NewSecretService() error = %v", err)
+	}
+	if secret, err := plain.Set("OPENAI_KEY", "sk-1"); err != nil || secret.Value != "sk-1" {
+		t.Fatalf("Set() = %+v, %v, want the value stored in plaintext", secret, err)
+	}
+
+	s, err := NewSecretService(db, testKey)
+	if err != nil {
+		t.Fatalf("NewSecretService() error = %v", err)
+	}
+	secret, err := s.get("OPENAI_KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret.Value, encryptedPrefix) || secret.Version != 1 {
+		t.Errorf("secret is stored as %q at version %d, want it encrypted at version 1", secret.Value, secret.Version)
+	}
+	if got, err := s.Resolve("${secret:OPENAI_KEY}"); err != nil || got != "sk-1" {
+		t.Errorf("Resolve() = %q, %v, want %q", got, err, "sk-1")
+	}
+}
+
+func TestNewSecretServiceInvalidKey(t *testing.T) {
+	if _, err := NewSecretService(nil, "c2hvcnQ="); err == nil {
+		t.Error("NewSecretService() accepted a key shorter than 32 bytes")
+	}
+}
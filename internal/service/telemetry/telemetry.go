@@ -0,0 +1,195 @@
+// Package telemetry sends an anonymous, opt-in usage report to the maintainers of mcpjungle.
+// The report only contains the version of mcpjungle, the platform it runs on, the number of entities registered
+// in it and which features it uses. It never contains names, URLs, addresses, tokens or any identifier
+// of the deployment, so reports can't be linked to each other or to their sender.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// DefaultURL is the endpoint that receives usage reports
+const DefaultURL = "https://telemetry.mcpjungle.com/v0/report"
+
+// DefaultInterval is how often usage reports are sent
+const DefaultInterval = 24 * time.Hour
+
+const requestTimeout = 10 * time.Second
+
+// Report is the complete content of a usage report.
+type Report struct {
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	Mode    string `json:"mode"`
+
+	// Servers contains the number of MCP servers registered, by transport
+	Servers    map[types.McpServerTransport]int64 `json:"servers"`
+	Tools      int64                              `json:"tools"`
+	Prompts    int64                              `json:"prompts"`
+	ToolGroups int64                              `json:"tool_groups"`
+	McpClients int64                              `json:"mcp_clients"`
+
+	// Features contains the names of the experimental features that are enabled
+	Features []string `json:"features"`
+	// Uses contains the names of the optional subsystems that are configured (eg- "audit", "wasm_plugins")
+	Uses []string `json:"uses"`
+}
+
+// Enabled returns true if the user opted into telemetry via the given value of the TELEMETRY environment variable.
+// Telemetry is off unless explicitly turned on, and the DO_NOT_TRACK convention always turns it off.
+func Enabled(value string) bool {
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// TelemetryService collects and sends usage reports.
+type TelemetryService struct {
+	db             *gorm.DB
+	featureService *feature.FeatureService
+	url            string
+	version        string
+	uses           []string
+	httpClient     *http.Client
+}
+
+// NewTelemetryService creates a service that sends usage reports to url.
+// uses contains the names of the optional subsystems configured in this deployment.
+func NewTelemetryService(
+	db *gorm.DB, featureService *feature.FeatureService, url, version string, uses []string,
+) *TelemetryService {
+	if url == "" {
+		url = DefaultURL
+	}
+	return &TelemetryService{
+		db:             db,
+		featureService: featureService,
+		url:            url,
+		version:        version,
+		uses:           uses,
+		httpClient:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// URL returns the endpoint that receives the usage reports.
+func (t *TelemetryService) URL() string {
+	return t.url
+}
+
+// Collect builds the usage report of this deployment.
+func (t *TelemetryService) Collect() (*Report, error) {
+	r := &Report{
+		Version:  t.version,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Servers:  make(map[types.McpServerTransport]int64),
+		Features: []string{},
+		Uses:     t.uses,
+	}
+	if r.Uses == nil {
+		r.Uses = []string{}
+	}
+
+	var cfg model.ServerConfig
+	if err := t.db.First(&cfg).Error; err == nil {
+		r.Mode = string(cfg.Mode)
+	}
+
+	var servers []struct {
+		Transport types.McpServerTransport
+		Count     int64
+	}
+	err := t.db.Model(&model.McpServer{}).Select("transport, COUNT(*) AS count").Group("transport").Scan(&servers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count MCP servers: %w", err)
+	}
+	for _, s := range servers {
+		r.Servers[s.Transport] = s.Count
+	}
+	for _, c := range []struct {
+		model any
+		dst   *int64
+	}{
+		{&model.Tool{}, &r.Tools},
+		{&model.Prompt{}, &r.Prompts},
+		{&model.ToolGroup{}, &r.ToolGroups},
+		{&model.McpClient{}, &r.McpClients},
+	} {
+		if err := t.db.Model(c.model).Count(c.dst).Error; err != nil {
+			return nil, fmt.Errorf("failed to collect usage report: %w", err)
+		}
+	}
+
+	for _, f := range t.featureService.ListFeatures() {
+		if f.Enabled {
+			r.Features = append(r.Features, f.Name)
+		}
+	}
+	return r, nil
+}
+
+// Send collects the usage report and sends it.
+func (t *TelemetryService) Send(ctx context.Context) error {
+	r, err := t.Collect()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to serialize usage report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create usage report request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mcpjungle/"+t.version)
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send usage report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send usage report: status %s", resp.Status)
+	}
+	log.Printf("[INFO] sent usage report to %s: %s", t.url, body)
+	return nil
+}
+
+// Run sends a usage report periodically until ctx is cancelled.
+// Failures are only logged, telemetry never affects the operation of the server.
+func (t *TelemetryService) Run(ctx context.Context, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		if err := t.Send(ctx); err != nil {
+			log.Printf("[WARN] %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
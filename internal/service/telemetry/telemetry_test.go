@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "")
+	for value, want := range map[string]bool{"": false, "off": false, "on": true, "TRUE": true, "1": true, "no": false} {
+		if got := Enabled(value); got != want {
+			t.Errorf("Enabled(%q) = %v, want %v", value, got, want)
+		}
+	}
+	t.Setenv("DO_NOT_TRACK", "1")
+	if Enabled("on") {
+		t.Error("Enabled(on) = true with DO_NOT_TRACK set")
+	}
+}
+
+func TestSend(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	err = db.AutoMigrate(
+		&model.ServerConfig{}, &model.McpServer{}, &model.Tool{}, &model.Prompt{}, &model.ToolGroup{},
+		&model.McpClient{}, &model.FeatureFlag{},
+	)
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	db.Create(&model.ServerConfig{Mode: model.ModeProd, Initialized: true})
+	db.Create(&model.McpServer{Name: "github", Transport: types.TransportStreamableHTTP, Config: []byte(`{"url":"https://secret.example.com"}`)})
+	db.Create(&model.McpServer{Name: "fs", Transport: types.TransportStdio, Config: []byte(`{"command":"npx"}`)})
+	db.Create(&model.Tool{ServerID: 1, Name: "search"})
+
+	features, err := feature.NewFeatureService(db, "-hedging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	s := NewTelemetryService(db, features, srv.URL, "1.2.3", []string{"audit"})
+	if err := s.Send(context.Background()); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	var r Report
+	if err := json.Unmarshal(body, &r); err != nil {
+		t.Fatalf("invalid report %s: %v", body, err)
+	}
+	if r.Version != "1.2.3" || r.Mode != string(model.ModeProd) || r.Tools != 1 {
+		t.Errorf("unexpected report: %s", body)
+	}
+	if r.Servers[types.TransportStdio] != 1 || r.Servers[types.TransportStreamableHTTP] != 1 {
+		t.Errorf("unexpected server counts: %v", r.Servers)
+	}
+	if strings.Contains(string(body), "hedging") || len(r.Uses) != 1 {
+		t.Errorf("unexpected features: %s", body)
+	}
+	for _, s := range []string{"github", "secret.example.com", "npx", "search"} {
+		if strings.Contains(string(body), s) {
+			t.Errorf("report contains identifying data %q: %s", s, body)
+		}
+	}
+}
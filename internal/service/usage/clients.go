@@ -0,0 +1,151 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxClientInfoLength is the length beyond which the clientInfo & protocol version sent by clients are truncated,
+// they are arbitrary client input
+const maxClientInfoLength = 128
+
+// unknownClient is recorded for the clients that don't send their name
+const unknownClient = "unknown"
+
+// RegisterHooks registers the hook through which the sessions initialized on the MCP proxy are recorded.
+func (u *UsageService) RegisterHooks(hooks *server.Hooks) {
+	hooks.AddAfterInitialize(func(_ context.Context, _ any, req *mcp.InitializeRequest, _ *mcp.InitializeResult) {
+		err := u.RecordClientSession(
+			req.Params.ClientInfo, req.Params.ProtocolVersion, ClientCapabilityNames(req.Params.Capabilities), time.Now(),
+		)
+		if err != nil {
+			log.Printf("[WARN] failed to record the session of client %s: %v", req.Params.ClientInfo.Name, err)
+		}
+	})
+}
+
+// ClientCapabilityNames returns the names of the capabilities advertised by a client, sorted.
+func ClientCapabilityNames(c mcp.ClientCapabilities) []string {
+	var names []string
+	if c.Roots != nil {
+		names = append(names, "roots")
+		if c.Roots.ListChanged {
+			names = append(names, "roots.listChanged")
+		}
+	}
+	if c.Sampling != nil {
+		names = append(names, "sampling")
+	}
+	for name := range c.Experimental {
+		names = append(names, "experimental."+truncateClientInfo(name))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RecordClientSession counts a session initialized at the given time by a client application.
+func (u *UsageService) RecordClientSession(info mcp.Implementation, protocolVersion string, capabilities []string, at time.Time) error {
+	at = at.UTC()
+	name := truncateClientInfo(strings.TrimSpace(info.Name))
+	if name == "" {
+		name = unknownClient
+	}
+	stat := &model.ClientSessionStat{
+		Day:             time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC),
+		ClientName:      name,
+		ClientVersion:   truncateClientInfo(strings.TrimSpace(info.Version)),
+		ProtocolVersion: truncateClientInfo(protocolVersion),
+		Capabilities:    strings.Join(capabilities, ","),
+		Sessions:        1,
+		LastSeenAt:      at,
+	}
+	return u.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "day"}, {Name: "client_name"}, {Name: "client_version"}, {Name: "protocol_version"}, {Name: "capabilities"},
+		},
+		DoUpdates: clause.Assignments(map[string]any{
+			"sessions":     gorm.Expr("client_session_stats.sessions + 1"),
+			"last_seen_at": at,
+		}),
+	}).Create(stat).Error
+}
+
+// ClientBreakdown breaks down the sessions initialized since the given time by client application,
+// protocol version and capability. Sessions are counted per day, so the whole day containing since is included.
+func (u *UsageService) ClientBreakdown(since time.Time) (*types.ClientBreakdown, error) {
+	since = since.UTC()
+	day := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+
+	// there is a row per day & client, few enough to be aggregated here rather than in SQL,
+	// where the aggregation of timestamps is not portable across databases
+	var stats []model.ClientSessionStat
+	if err := db.ReadReplica(u.db).Where("day >= ?", day).Order("day").Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get client sessions: %w", err)
+	}
+
+	b := &types.ClientBreakdown{
+		Applications:     make(map[string]int64),
+		ProtocolVersions: make(map[string]int64),
+		Capabilities:     make(map[string]int64),
+		Clients:          []*types.ClientApplicationUsage{},
+	}
+	clients := make(map[[4]string]*types.ClientApplicationUsage)
+	for _, s := range stats {
+		key := [4]string{s.ClientName, s.ClientVersion, s.ProtocolVersion, s.Capabilities}
+		c, ok := clients[key]
+		if !ok {
+			c = &types.ClientApplicationUsage{
+				Name:            s.ClientName,
+				Version:         s.ClientVersion,
+				ProtocolVersion: s.ProtocolVersion,
+				Capabilities:    []string{},
+			}
+			if s.Capabilities != "" {
+				c.Capabilities = strings.Split(s.Capabilities, ",")
+			}
+			clients[key] = c
+			b.Clients = append(b.Clients, c)
+		}
+		c.Sessions += s.Sessions
+		if s.LastSeenAt.After(c.LastSeenAt) {
+			c.LastSeenAt = s.LastSeenAt
+		}
+
+		b.Sessions += s.Sessions
+		b.Applications[s.ClientName] += s.Sessions
+		b.ProtocolVersions[s.ProtocolVersion] += s.Sessions
+		for _, capability := range c.Capabilities {
+			b.Capabilities[capability] += s.Sessions
+		}
+	}
+	sort.SliceStable(b.Clients, func(i, j int) bool {
+		if b.Clients[i].Sessions != b.Clients[j].Sessions {
+			return b.Clients[i].Sessions > b.Clients[j].Sessions
+		}
+		if b.Clients[i].Name != b.Clients[j].Name {
+			return b.Clients[i].Name < b.Clients[j].Name
+		}
+		return b.Clients[i].Version < b.Clients[j].Version
+	})
+	return b, nil
+}
+
+func truncateClientInfo(s string) string {
+	if r := []rune(s); len(r) > maxClientInfoLength {
+		return string(r[:maxClientInfoLength])
+	}
+	return s
+}
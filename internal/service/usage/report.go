@@ -0,0 +1,129 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// TopTools returns the most called tools since the given time, most called first.
+// A non-positive limit returns all tools that were called.
+func (u *UsageService) TopTools(since time.Time, limit int) ([]*types.ToolUsage, error) {
+	var rows []struct {
+		ToolName string
+		Calls    int64
+		Errors   int64
+		Cost     float64
+		LastID   uint
+	}
+	q := db.ReadReplica(u.db).Model(&model.ToolInvocation{}).
+		Select(
+			"tool_name, COUNT(*) AS calls, "+
+				"SUM(CASE WHEN is_error THEN 1 ELSE 0 END) AS errors, "+
+				"COALESCE(SUM(cost), 0) AS cost, MAX(id) AS last_id",
+		).
+		Where("created_at >= ?", since).
+		Group("tool_name").
+		Order("calls DESC, tool_name")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute tool usage: %w", err)
+	}
+
+	ids := make([]uint, len(rows))
+	for i, r := range rows {
+		ids[i] = r.LastID
+	}
+	lastCalls, err := u.invocationTimes(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]*types.ToolUsage, len(rows))
+	for i, r := range rows {
+		usage[i] = &types.ToolUsage{
+			Tool:         r.ToolName,
+			Calls:        r.Calls,
+			Errors:       r.Errors,
+			Cost:         r.Cost,
+			LastCalledAt: lastCalls[r.LastID],
+		}
+	}
+	return usage, nil
+}
+
+// UnusedTools returns the tools, out of the given ones, that haven't been called since the given time.
+// Tools registered after that time are not reported since they haven't been around long enough.
+// Tools that have never been called come first, the rest are ordered by the time they were last called.
+func (u *UsageService) UnusedTools(tools []model.Tool, since time.Time) ([]*types.UnusedTool, error) {
+	// the last invocation of every tool is the one with the highest ID
+	var rows []struct {
+		ToolName string
+		LastID   uint
+	}
+	err := db.ReadReplica(u.db).Model(&model.ToolInvocation{}).
+		Select("tool_name, MAX(id) AS last_id").
+		Group("tool_name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the last invocations of tools: %w", err)
+	}
+	ids := make([]uint, len(rows))
+	for i, r := range rows {
+		ids[i] = r.LastID
+	}
+	times, err := u.invocationTimes(ids)
+	if err != nil {
+		return nil, err
+	}
+	lastCalls := make(map[string]time.Time, len(rows))
+	for _, r := range rows {
+		lastCalls[r.ToolName] = times[r.LastID]
+	}
+
+	var unused []*types.UnusedTool
+	for _, t := range tools {
+		if t.CreatedAt.After(since) {
+			continue
+		}
+		ut := &types.UnusedTool{Name: t.Name, Enabled: t.Enabled, RegisteredAt: t.CreatedAt}
+		if last, ok := lastCalls[t.Name]; ok {
+			if !last.Before(since) {
+				continue
+			}
+			ut.LastCalledAt = &last
+		}
+		unused = append(unused, ut)
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		a, b := unused[i].LastCalledAt, unused[j].LastCalledAt
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+	return unused, nil
+}
+
+// invocationTimes returns the time at which each of the given invocations was made.
+func (u *UsageService) invocationTimes(ids []uint) (map[uint]time.Time, error) {
+	times := make(map[uint]time.Time, len(ids))
+	if len(ids) == 0 {
+		return times, nil
+	}
+	var invocations []model.ToolInvocation
+	if err := db.ReadReplica(u.db).Select("id", "created_at").Where("id IN ?", ids).Find(&invocations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tool invocations: %w", err)
+	}
+	for _, inv := range invocations {
+		times[inv.ID] = inv.CreatedAt
+	}
+	return times, nil
+}
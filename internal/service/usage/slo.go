@@ -0,0 +1,134 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// serverToolPrefix is how the canonical names of all tools of an MCP server begin
+const serverToolPrefix = "%s__"
+
+// CreateSLO creates a new SLO in the database.
+func (u *UsageService) CreateSLO(s *model.SLO) error {
+	if s.Name == "" {
+		return errors.New("SLO name is required")
+	}
+	return u.db.Create(s).Error
+}
+
+// ListSLOs returns all SLOs along with their compliance in the current window.
+func (u *UsageService) ListSLOs() ([]*model.SLO, error) {
+	var slos []*model.SLO
+	if err := u.db.Order("name").Find(&slos).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, s := range slos {
+		status, err := u.sloStatus(s, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute compliance of SLO %s: %w", s.Name, err)
+		}
+		s.Status = status
+	}
+	return slos, nil
+}
+
+// DeleteSLO deletes an SLO by name.
+// It is an idempotent operation. Deleting an SLO that does not exist will not return an error.
+func (u *UsageService) DeleteSLO(name string) error {
+	return u.db.Unscoped().Where("name = ?", name).Delete(&model.SLO{}).Error
+}
+
+// RunSLOMetrics periodically computes the compliance of all SLOs and exports it as metrics until ctx is cancelled.
+func (u *UsageService) RunSLOMetrics(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		u.updateSLOMetrics()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *UsageService) updateSLOMetrics() {
+	slos, err := u.ListSLOs()
+	if err != nil {
+		log.Printf("[ERROR] failed to compute SLO compliance: %v", err)
+		return
+	}
+	// reset so that deleted SLOs stop being exported
+	metrics.SLOCompliant.Reset()
+	for _, s := range slos {
+		v := 0.0
+		if s.Status.Compliant {
+			v = 1
+		}
+		metrics.SLOCompliant.WithLabelValues(s.Name).Set(v)
+	}
+}
+
+// sloStatus computes the compliance of an SLO from the calls made within its window until now.
+func (u *UsageService) sloStatus(s *model.SLO, now time.Time) (*types.SLOStatus, error) {
+	window, err := types.ParseLookback(s.Window)
+	if err != nil {
+		return nil, err
+	}
+	q := db.ReadReplica(u.db).Model(&model.ToolInvocation{}).Where("created_at >= ?", now.Add(-window))
+	if s.ToolName != "" {
+		q = q.Where("tool_name = ?", s.ToolName)
+	} else {
+		q = q.Where(`tool_name LIKE ? ESCAPE '\'`, escapeLike(fmt.Sprintf(serverToolPrefix, s.ServerName))+"%")
+	}
+	var calls []struct {
+		DurationMs int64
+		IsError    bool
+	}
+	if err := q.Select("duration_ms", "is_error").Scan(&calls).Error; err != nil {
+		return nil, err
+	}
+
+	status := &types.SLOStatus{Calls: int64(len(calls))}
+	if len(calls) > 0 {
+		durations := make([]int64, len(calls))
+		var errs int
+		for i, c := range calls {
+			durations[i] = c.DurationMs
+			if c.IsError {
+				errs++
+			}
+		}
+		status.LatencyMs = percentile(durations, s.LatencyPercentile)
+		status.ErrorRate = float64(errs) / float64(len(calls)) * 100
+	}
+	status.LatencyMet = s.LatencyTargetMs == 0 || status.LatencyMs <= s.LatencyTargetMs
+	status.ErrorRateMet = s.MaxErrorRate == 0 || status.ErrorRate <= s.MaxErrorRate
+	status.Compliant = status.LatencyMet && status.ErrorRateMet
+	return status, nil
+}
+
+// percentile returns the p-th percentile of the given values using the nearest-rank method.
+// values must not be empty, it is sorted in place.
+func percentile(values []int64, p float64) int64 {
+	slices.Sort(values)
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	return values[min(max(rank, 1), len(values))-1]
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so that s is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
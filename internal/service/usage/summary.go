@@ -0,0 +1,110 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// rollup accumulates the calls counted in a UsageRollup
+type rollup struct {
+	errors    int64
+	durations []int64
+}
+
+func (r *rollup) add(durationMs int64, isError bool) {
+	r.durations = append(r.durations, durationMs)
+	if isError {
+		r.errors++
+	}
+}
+
+func (r *rollup) usage(name string) *types.UsageRollup {
+	u := &types.UsageRollup{Name: name, Calls: int64(len(r.durations)), Errors: r.errors}
+	if u.Calls > 0 {
+		u.ErrorRate = float64(r.errors) / float64(u.Calls) * 100
+		u.P95LatencyMs = percentile(r.durations, 95)
+	}
+	return u
+}
+
+// MetricsSummary aggregates the calls made since the given time by MCP server, by tool group and by MCP client.
+// Only the given tool groups are reported, including the ones whose tools weren't called.
+func (u *UsageService) MetricsSummary(since time.Time, groups []*model.ToolGroup) (*types.MetricsSummary, error) {
+	var calls []struct {
+		ToolName   string
+		ClientName string
+		DurationMs int64
+		IsError    bool
+	}
+	err := db.ReadReplica(u.db).Model(&model.ToolInvocation{}).
+		Select("tool_name", "client_name", "duration_ms", "is_error").
+		Where("created_at >= ?", since).
+		Scan(&calls).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool invocations: %w", err)
+	}
+
+	// the tool groups that include every tool
+	toolGroups := make(map[string][]string)
+	groupRollups := make(map[string]*rollup, len(groups))
+	for _, g := range groups {
+		groupRollups[g.Name] = &rollup{}
+		for _, t := range g.GetIncludedTools() {
+			toolGroups[t] = append(toolGroups[t], g.Name)
+		}
+	}
+
+	total := &rollup{}
+	servers := make(map[string]*rollup)
+	clients := make(map[string]*rollup)
+	for _, c := range calls {
+		total.add(c.DurationMs, c.IsError)
+		if server, _, ok := strings.Cut(c.ToolName, "__"); ok {
+			addTo(servers, server, c.DurationMs, c.IsError)
+		}
+		for _, g := range toolGroups[c.ToolName] {
+			groupRollups[g].add(c.DurationMs, c.IsError)
+		}
+		if c.ClientName != "" {
+			addTo(clients, c.ClientName, c.DurationMs, c.IsError)
+		}
+	}
+
+	return &types.MetricsSummary{
+		Since:      since,
+		Total:      total.usage("total"),
+		Servers:    sortedUsage(servers),
+		ToolGroups: sortedUsage(groupRollups),
+		Clients:    sortedUsage(clients),
+	}, nil
+}
+
+func addTo(rollups map[string]*rollup, name string, durationMs int64, isError bool) {
+	r, ok := rollups[name]
+	if !ok {
+		r = &rollup{}
+		rollups[name] = r
+	}
+	r.add(durationMs, isError)
+}
+
+// sortedUsage returns the usage of all rollups, most called first.
+func sortedUsage(rollups map[string]*rollup) []*types.UsageRollup {
+	usage := make([]*types.UsageRollup, 0, len(rollups))
+	for name, r := range rollups {
+		usage = append(usage, r.usage(name))
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Calls != usage[j].Calls {
+			return usage[i].Calls > usage[j].Calls
+		}
+		return usage[i].Name < usage[j].Name
+	})
+	return usage
+}
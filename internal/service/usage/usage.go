@@ -0,0 +1,326 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// alertWebhookTimeout is the maximum time allowed for delivering an alert to a budget's webhook
+const alertWebhookTimeout = 10 * time.Second
+
+// ErrBudgetExceeded is returned when a tool call is rejected because it would exceed a budget
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// requestSigner signs the payloads of outbound webhook requests
+type requestSigner interface {
+	SignRequest(req *http.Request, body []byte) error
+}
+
+// notifier records operational events for admins to review
+type notifier interface {
+	Notify(kind, subject, message string)
+}
+
+// UsageService keeps track of tool usage & spend and enforces budgets.
+type UsageService struct {
+	db         *gorm.DB
+	httpClient *http.Client
+	signer     requestSigner
+	notifier   notifier
+
+	// mu serializes the reservations of budgets, reserved is the cost of the calls in flight per hard-stop budget
+	mu       sync.Mutex
+	reserved map[uint]float64
+}
+
+// NewUsageService creates a new UsageService.
+// signer is used to sign alerts sent to budget webhooks, it may be nil in which case alerts are sent unsigned.
+// notifier, if not nil, is notified of all budget alerts.
+func NewUsageService(db *gorm.DB, signer requestSigner, notifier notifier) *UsageService {
+	return &UsageService{
+		db:         db,
+		httpClient: &http.Client{Timeout: alertWebhookTimeout},
+		signer:     signer,
+		notifier:   notifier,
+		reserved:   make(map[uint]float64),
+	}
+}
+
+// CreateBudget creates a new budget in the database.
+func (u *UsageService) CreateBudget(b *model.Budget) error {
+	if b.Name == "" {
+		return errors.New("budget name is required")
+	}
+	return u.db.Create(b).Error
+}
+
+// ListBudgets returns all budgets along with their spend in the current period.
+func (u *UsageService) ListBudgets() ([]*model.Budget, error) {
+	var budgets []*model.Budget
+	if err := u.db.Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, b := range budgets {
+		spend, err := u.periodSpend(b, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute spend for budget %s: %w", b.Name, err)
+		}
+		b.Spend = spend
+	}
+	return budgets, nil
+}
+
+// DeleteBudget deletes a budget by name.
+// It is an idempotent operation. Deleting a budget that does not exist will not return an error.
+func (u *UsageService) DeleteBudget(name string) error {
+	return u.db.Unscoped().Where("name = ?", name).Delete(&model.Budget{}).Error
+}
+
+// Reservation holds the cost of a tool call in the hard-stop budgets of its caller while the call is in flight.
+type Reservation struct {
+	u       *UsageService
+	budgets []uint
+	cost    float64
+	once    sync.Once
+}
+
+// Release gives the reserved cost back to the budgets. It must be called once the invocation is recorded,
+// or when the call is abandoned. It is a no-op on a nil Reservation and after the first call.
+func (r *Reservation) Release() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		r.u.mu.Lock()
+		defer r.u.mu.Unlock()
+		for _, id := range r.budgets {
+			if r.u.reserved[id] -= r.cost; r.u.reserved[id] <= 0 {
+				delete(r.u.reserved, id)
+			}
+		}
+	})
+}
+
+// ReserveBudgets returns ErrBudgetExceeded if a tool call of the given cost by the client or user
+// would take their spend beyond any of their hard-stop budgets, counting the cost of their calls in flight.
+// Otherwise, it reserves the cost of the call in these budgets until the returned reservation is released,
+// so that concurrent calls can't overspend a budget together.
+// Reservations are kept in memory: replicas of mcpjungle that share a database only see each other's calls
+// once they're recorded, so they can together overspend a budget by the cost of the calls they run at the same time.
+func (u *UsageService) ReserveBudgets(clientName, username string, cost float64) (*Reservation, error) {
+	if cost <= 0 {
+		return nil, nil
+	}
+	budgets, err := u.applicableBudgets(clientName, username)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	r := &Reservation{u: u, cost: cost}
+	now := time.Now()
+	for _, b := range budgets {
+		if !b.HardStop {
+			continue
+		}
+		spend, err := u.periodSpend(b, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute spend for budget %s: %w", b.Name, err)
+		}
+		spend += u.reserved[b.ID]
+		if spend+cost > b.MaxSpend {
+			return nil, fmt.Errorf(
+				"%w: call costs %.4f but only %.4f of %s budget %s remains",
+				ErrBudgetExceeded, cost, max(b.MaxSpend-spend, 0), b.Period, b.Name,
+			)
+		}
+		r.budgets = append(r.budgets, b.ID)
+	}
+	for _, id := range r.budgets {
+		u.reserved[id] += cost
+	}
+	return r, nil
+}
+
+// RecordInvocation stores a usage record for a tool call and raises any budget alerts that it triggers.
+func (u *UsageService) RecordInvocation(inv *model.ToolInvocation) error {
+	if err := u.db.Create(inv).Error; err != nil {
+		return fmt.Errorf("failed to record invocation of tool %s: %w", inv.ToolName, err)
+	}
+	if inv.Cost <= 0 {
+		return nil
+	}
+	budgets, err := u.applicableBudgets(inv.ClientName, inv.Username)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, b := range budgets {
+		if err := u.raiseAlerts(b, now); err != nil {
+			log.Printf("[ERROR] failed to process alerts for budget %s: %v", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListInvocations returns the most recent tool invocations, newest first.
+// The results can be filtered by tool name. A non-positive limit returns all matching invocations.
+func (u *UsageService) ListInvocations(toolName string, limit int) ([]*model.ToolInvocation, error) {
+	var invocations []*model.ToolInvocation
+	q := db.ReadReplica(u.db).Order("created_at DESC, id DESC")
+	if toolName != "" {
+		q = q.Where("tool_name = ?", toolName)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&invocations).Error; err != nil {
+		return nil, err
+	}
+	return invocations, nil
+}
+
+// applicableBudgets returns the budgets that track the spend of the given client or user.
+func (u *UsageService) applicableBudgets(clientName, username string) ([]*model.Budget, error) {
+	if clientName == "" && username == "" {
+		return nil, nil
+	}
+	var budgets []*model.Budget
+	q := u.db
+	switch {
+	case clientName != "" && username != "":
+		q = q.Where("client_name = ? OR username = ?", clientName, username)
+	case clientName != "":
+		q = q.Where("client_name = ?", clientName)
+	default:
+		q = q.Where("username = ?", username)
+	}
+	if err := q.Find(&budgets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+// periodSpend returns the amount spent by the budget's client or user in the budget's current period.
+// The spend of the total period includes the spend of the invocations deleted by the history retention.
+func (u *UsageService) periodSpend(b *model.Budget, now time.Time) (float64, error) {
+	owner := func(q *gorm.DB) *gorm.DB {
+		if b.ClientName != "" {
+			return q.Where("client_name = ?", b.ClientName)
+		}
+		return q.Where("username = ?", b.Username)
+	}
+	var spend float64
+	err := owner(u.db.Model(&model.ToolInvocation{}).Where("created_at >= ?", b.Period.Start(now))).
+		Select("COALESCE(SUM(cost), 0)").Scan(&spend).Error
+	if err != nil {
+		return 0, err
+	}
+	if b.Period == model.BudgetPeriodTotal {
+		var pruned float64
+		if err := owner(u.db.Model(&model.PrunedSpend{})).Select("COALESCE(SUM(cost), 0)").Scan(&pruned).Error; err != nil {
+			return 0, err
+		}
+		spend += pruned
+	}
+	return spend, nil
+}
+
+// raiseAlerts raises the threshold & exceeded alerts of a budget if they are due.
+// Each alert is raised at most once per budget period.
+func (u *UsageService) raiseAlerts(b *model.Budget, now time.Time) error {
+	spend, err := u.periodSpend(b, now)
+	if err != nil {
+		return err
+	}
+	periodStart := b.Period.Start(now)
+	alreadyAlerted := func(at *time.Time) bool {
+		return at != nil && !at.Before(periodStart)
+	}
+
+	var exceeded bool
+	switch {
+	case spend >= b.MaxSpend && !alreadyAlerted(b.ExceededAlertedAt):
+		exceeded = true
+		b.ExceededAlertedAt = &now
+		// crossing the max spend implies crossing the threshold, no need for a separate alert
+		b.ThresholdAlertedAt = &now
+	case b.AlertThreshold > 0 && spend >= b.MaxSpend*b.AlertThreshold/100 && !alreadyAlerted(b.ThresholdAlertedAt):
+		b.ThresholdAlertedAt = &now
+	default:
+		return nil
+	}
+	if err := u.db.Model(b).Select("ThresholdAlertedAt", "ExceededAlertedAt").Updates(b).Error; err != nil {
+		return fmt.Errorf("failed to save alert state: %w", err)
+	}
+
+	alert := &types.BudgetAlert{
+		Budget:   b.Name,
+		Client:   b.ClientName,
+		User:     b.Username,
+		Exceeded: exceeded,
+		Spend:    spend,
+		MaxSpend: b.MaxSpend,
+		Period:   string(b.Period),
+	}
+	kind, msg := types.NotificationBudgetThreshold, "crossed its alert threshold"
+	if exceeded {
+		kind, msg = types.NotificationBudgetExceeded, "exceeded"
+	}
+	msg = fmt.Sprintf("budget %s %s: spent %.4f of %.4f (%s)", b.Name, msg, spend, b.MaxSpend, b.Period)
+	log.Printf("[WARN] %s", msg)
+	if u.notifier != nil {
+		u.notifier.Notify(kind, b.Name, msg)
+	}
+	if b.WebhookURL != "" {
+		// deliver the alert in the background so that it doesn't slow down the tool call
+		go u.sendAlert(b.WebhookURL, alert)
+	}
+	return nil
+}
+
+// sendAlert POSTs the alert to the given webhook URL.
+func (u *UsageService) sendAlert(webhookURL string, alert *types.BudgetAlert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.Printf("[ERROR] failed to serialize alert for budget %s: %v", alert.Budget, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alertWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("[ERROR] failed to create alert request for budget %s: %v", alert.Budget, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if u.signer != nil {
+		if err := u.signer.SignRequest(req, body); err != nil {
+			log.Printf("[ERROR] failed to sign alert for budget %s: %v", alert.Budget, err)
+			return
+		}
+	}
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		log.Printf("[ERROR] failed to deliver alert for budget %s: %v", alert.Budget, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("[ERROR] webhook for budget %s responded with status %d", alert.Budget, resp.StatusCode)
+	}
+}
@@ -0,0 +1,276 @@
+package usage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestUsageService(t *testing.T) *UsageService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ToolInvocation{}, &model.Budget{}, &model.SLO{}, &model.ClientSessionStat{}, &model.PrunedSpend{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return NewUsageService(db, nil, nil)
+}
+
+func TestReserveBudgetsHardStop(t *testing.T) {
+	u := newTestUsageService(t)
+	b := &model.Budget{Name: "b", ClientName: "cursor", MaxSpend: 1, Period: model.BudgetPeriodMonthly, HardStop: true}
+	if err := u.CreateBudget(b); err != nil {
+		t.Fatalf("CreateBudget() error = %v", err)
+	}
+
+	r, err := u.ReserveBudgets("cursor", "", 0.6)
+	if err != nil {
+		t.Fatalf("ReserveBudgets() error = %v, want nil", err)
+	}
+	// the cost of a call in flight is reserved, a concurrent call can't overspend the budget
+	if _, err := u.ReserveBudgets("cursor", "", 0.6); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("ReserveBudgets() during a call in flight error = %v, want ErrBudgetExceeded", err)
+	}
+	if err := u.RecordInvocation(&model.ToolInvocation{ToolName: "a__b", ClientName: "cursor", Cost: 0.6}); err != nil {
+		t.Fatalf("RecordInvocation() error = %v", err)
+	}
+	r.Release()
+	r.Release()
+
+	if _, err := u.ReserveBudgets("cursor", "", 0.6); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("ReserveBudgets() error = %v, want ErrBudgetExceeded", err)
+	}
+	if r, err := u.ReserveBudgets("cursor", "", 0.4); err != nil {
+		t.Errorf("ReserveBudgets() within the remaining budget error = %v, want nil", err)
+	} else {
+		r.Release()
+	}
+	// other clients are not affected by the budget
+	if _, err := u.ReserveBudgets("claude", "", 0.6); err != nil {
+		t.Errorf("ReserveBudgets() for another client error = %v, want nil", err)
+	}
+}
+
+func TestRecordInvocationRaisesAlertOnce(t *testing.T) {
+	u := newTestUsageService(t)
+	b := &model.Budget{Name: "b", Username: "alice", MaxSpend: 10, Period: model.BudgetPeriodTotal, AlertThreshold: 50}
+	if err := u.CreateBudget(b); err != nil {
+		t.Fatalf("CreateBudget() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := u.RecordInvocation(&model.ToolInvocation{ToolName: "a__b", Username: "alice", Cost: 3}); err != nil {
+			t.Fatalf("RecordInvocation() error = %v", err)
+		}
+	}
+
+	budgets, err := u.ListBudgets()
+	if err != nil {
+		t.Fatalf("ListBudgets() error = %v", err)
+	}
+	if got := budgets[0]; got.Spend != 9 || got.ThresholdAlertedAt == nil || got.ExceededAlertedAt != nil {
+		t.Errorf("unexpected budget state: spend=%v thresholdAlerted=%v exceededAlerted=%v",
+			got.Spend, got.ThresholdAlertedAt, got.ExceededAlertedAt)
+	}
+}
+
+func TestCreateBudgetValidation(t *testing.T) {
+	u := newTestUsageService(t)
+	err := u.CreateBudget(&model.Budget{Name: "b", MaxSpend: 1, Period: model.BudgetPeriodDaily})
+	if err == nil {
+		t.Errorf("CreateBudget() without a client or user must fail")
+	}
+}
+
+func TestToolUsageReports(t *testing.T) {
+	u := newTestUsageService(t)
+	for _, inv := range []*model.ToolInvocation{
+		{ToolName: "a__search", Cost: 1},
+		{ToolName: "a__search", Cost: 2, IsError: true},
+		{ToolName: "a__fetch"},
+	} {
+		if err := u.RecordInvocation(inv); err != nil {
+			t.Fatalf("RecordInvocation() error = %v", err)
+		}
+	}
+
+	top, err := u.TopTools(time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("TopTools() error = %v", err)
+	}
+	if len(top) != 2 || top[0].Tool != "a__search" || top[0].Calls != 2 || top[0].Errors != 1 || top[0].Cost != 3 {
+		t.Fatalf("TopTools() = %+v, want a__search first with 2 calls, 1 error and a cost of 3", top[0])
+	}
+	if top[0].LastCalledAt.IsZero() {
+		t.Errorf("TopTools() did not return the time of the last call")
+	}
+
+	registered := time.Now().Add(-48 * time.Hour)
+	tools := []model.Tool{
+		{Name: "a__search"},
+		{Name: "a__unused"},
+		{Name: "a__new"},
+	}
+	tools[0].CreatedAt = registered
+	tools[1].CreatedAt = registered
+	tools[2].CreatedAt = time.Now()
+
+	unused, err := u.UnusedTools(tools, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("UnusedTools() error = %v", err)
+	}
+	if len(unused) != 1 || unused[0].Name != "a__unused" || unused[0].LastCalledAt != nil {
+		t.Errorf("UnusedTools() = %v, want only a__unused which was never called", unused)
+	}
+}
+
+func TestSLOCompliance(t *testing.T) {
+	u := newTestUsageService(t)
+	// 10 calls to the server's tools: 9 fast ones and a slow failing one
+	for i := 0; i < 10; i++ {
+		inv := &model.ToolInvocation{ToolName: "git__status", DurationMs: 100}
+		if i == 9 {
+			inv.ToolName, inv.DurationMs, inv.IsError = "git__log", 5000, true
+		}
+		if err := u.RecordInvocation(inv); err != nil {
+			t.Fatalf("RecordInvocation() error = %v", err)
+		}
+	}
+	// a call to another server whose name only matches if '_' is treated as a wildcard
+	if err := u.RecordInvocation(&model.ToolInvocation{ToolName: "gitXXlog", DurationMs: 9000, IsError: true}); err != nil {
+		t.Fatalf("RecordInvocation() error = %v", err)
+	}
+
+	slos := []*model.SLO{
+		{Name: "server", ServerName: "git", LatencyPercentile: 90, LatencyTargetMs: 200, MaxErrorRate: 10, Window: "1d"},
+		{Name: "strict", ServerName: "git", LatencyPercentile: 95, LatencyTargetMs: 200, Window: "1d"},
+		{Name: "tool", ToolName: "git__status", LatencyPercentile: 99, MaxErrorRate: 1, Window: "1h"},
+	}
+	for _, s := range slos {
+		if err := u.CreateSLO(s); err != nil {
+			t.Fatalf("CreateSLO(%s) error = %v", s.Name, err)
+		}
+	}
+	if err := u.CreateSLO(&model.SLO{Name: "none", ToolName: "a__b", LatencyPercentile: 95, Window: "1d"}); err == nil {
+		t.Errorf("CreateSLO() without objectives error = nil, want error")
+	}
+
+	got, err := u.ListSLOs()
+	if err != nil {
+		t.Fatalf("ListSLOs() error = %v", err)
+	}
+	status := make(map[string]*model.SLO)
+	for _, s := range got {
+		status[s.Name] = s
+	}
+	if s := status["server"].Status; s.Calls != 10 || s.LatencyMs != 100 || s.ErrorRate != 10 || !s.Compliant {
+		t.Errorf("status of SLO server = %+v, want 10 calls, 100ms p90, 10%% errors, compliant", s)
+	}
+	if s := status["strict"].Status; s.LatencyMs != 5000 || s.LatencyMet || s.Compliant {
+		t.Errorf("status of SLO strict = %+v, want 5000ms p95, not compliant", s)
+	}
+	if s := status["tool"].Status; s.Calls != 9 || s.ErrorRate != 0 || !s.Compliant {
+		t.Errorf("status of SLO tool = %+v, want 9 calls, no errors, compliant", s)
+	}
+}
+
+func TestMetricsSummary(t *testing.T) {
+	u := newTestUsageService(t)
+	invocations := []*model.ToolInvocation{
+		{ToolName: "git__status", ClientName: "cursor", DurationMs: 100},
+		{ToolName: "git__status", ClientName: "cursor", DurationMs: 300, IsError: true},
+		{ToolName: "git__log", ClientName: "claude", DurationMs: 200},
+		{ToolName: "time__now", Username: "alice", DurationMs: 10},
+	}
+	for _, inv := range invocations {
+		if err := u.RecordInvocation(inv); err != nil {
+			t.Fatalf("RecordInvocation() error = %v", err)
+		}
+	}
+	groups := []*model.ToolGroup{
+		{Name: "readers", IncludedTools: []byte(`["git__log","time__now"]`)},
+		{Name: "idle", IncludedTools: []byte(`["time__sleep"]`)},
+	}
+
+	s, err := u.MetricsSummary(time.Now().Add(-time.Hour), groups)
+	if err != nil {
+		t.Fatalf("MetricsSummary() error = %v", err)
+	}
+	if s.Total.Calls != 4 || s.Total.Errors != 1 || s.Total.ErrorRate != 25 || s.Total.P95LatencyMs != 300 {
+		t.Errorf("Total = %+v, want 4 calls, 1 error, 25%% errors, 300ms p95", s.Total)
+	}
+	if len(s.Servers) != 2 || s.Servers[0].Name != "git" || s.Servers[0].Calls != 3 || s.Servers[1].Name != "time" {
+		t.Errorf("Servers = %+v, want git (3 calls) then time", s.Servers)
+	}
+	if len(s.ToolGroups) != 2 || s.ToolGroups[0].Name != "readers" || s.ToolGroups[0].Calls != 2 ||
+		s.ToolGroups[0].P95LatencyMs != 200 || s.ToolGroups[1].Name != "idle" || s.ToolGroups[1].Calls != 0 {
+		t.Errorf("ToolGroups = %+v, want readers (2 calls, 200ms p95) then idle (no calls)", s.ToolGroups)
+	}
+	if len(s.Clients) != 2 || s.Clients[0].Name != "cursor" || s.Clients[0].ErrorRate != 50 {
+		t.Errorf("Clients = %+v, want cursor (50%% errors) then claude", s.Clients)
+	}
+
+	s, err = u.MetricsSummary(time.Now().Add(time.Minute), nil)
+	if err != nil {
+		t.Fatalf("MetricsSummary() error = %v", err)
+	}
+	if s.Total.Calls != 0 || len(s.Servers) != 0 {
+		t.Errorf("MetricsSummary() of an empty window = %+v, want no calls", s)
+	}
+}
+
+func TestClientBreakdown(t *testing.T) {
+	u := newTestUsageService(t)
+	now := time.Now()
+	cursor := mcp.Implementation{Name: "cursor", Version: "1.2.0"}
+	var caps mcp.ClientCapabilities
+	caps.Roots = &struct {
+		ListChanged bool `json:"listChanged,omitempty"`
+	}{ListChanged: true}
+	caps.Sampling = &struct{}{}
+	capabilities := ClientCapabilityNames(caps)
+	if strings.Join(capabilities, ",") != "roots,roots.listChanged,sampling" {
+		t.Fatalf("ClientCapabilityNames() = %v", capabilities)
+	}
+
+	record := func(info mcp.Implementation, version string, capabilities []string, at time.Time) {
+		t.Helper()
+		if err := u.RecordClientSession(info, version, capabilities, at); err != nil {
+			t.Fatalf("RecordClientSession() error = %v", err)
+		}
+	}
+	record(cursor, "2025-06-18", capabilities, now)
+	record(cursor, "2025-06-18", capabilities, now)
+	record(cursor, "2025-06-18", capabilities, now.AddDate(0, 0, -1))
+	record(mcp.Implementation{}, "2024-11-05", nil, now)
+	// outside of the window
+	record(cursor, "2025-03-26", nil, now.AddDate(0, 0, -40))
+
+	b, err := u.ClientBreakdown(now.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("ClientBreakdown() error = %v", err)
+	}
+	if b.Sessions != 4 || b.Applications["cursor"] != 3 || b.Applications["unknown"] != 1 {
+		t.Errorf("unexpected sessions by application: %d %v", b.Sessions, b.Applications)
+	}
+	if b.ProtocolVersions["2025-06-18"] != 3 || b.ProtocolVersions["2024-11-05"] != 1 || len(b.ProtocolVersions) != 2 {
+		t.Errorf("unexpected sessions by protocol version: %v", b.ProtocolVersions)
+	}
+	if b.Capabilities["sampling"] != 3 || b.Capabilities["roots.listChanged"] != 3 {
+		t.Errorf("unexpected sessions by capability: %v", b.Capabilities)
+	}
+	if len(b.Clients) != 2 || b.Clients[0].Name != "cursor" || b.Clients[0].Sessions != 3 || b.Clients[0].Version != "1.2.0" {
+		t.Fatalf("unexpected clients %+v", b.Clients)
+	}
+	if len(b.Clients[1].Capabilities) != 0 {
+		t.Errorf("expected no capabilities for the unknown client, got %v", b.Clients[1].Capabilities)
+	}
+}
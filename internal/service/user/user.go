@@ -48,6 +48,19 @@ func (u *UserService) GetUserByAccessToken(token string) (*model.User, error) {
 	return &user, nil
 }
 
+// GetUser returns the user with the specified username.
+// If no user is found, an error is returned.
+func (u *UserService) GetUser(username string) (*model.User, error) {
+	var user model.User
+	if err := u.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &user, nil
+}
+
 // CreateUser creates a new user with the specified username.
 // This method currently only supports creating a standard user, ie, user with the "user" role.
 func (u *UserService) CreateUser(username string) (*model.User, error) {
@@ -91,9 +104,53 @@ func (u *UserService) DeleteUser(username string) error {
 		return fmt.Errorf("cannot delete an admin user")
 	}
 
-	err = u.db.Unscoped().Where("username = ?", username).Delete(&model.User{}).Error
+	// the user's favorite tools & views are personal, they are deleted along with the user
+	err = u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("username = ?", username).Delete(&model.ToolFavorite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("username = ?", username).Delete(&model.ToolView{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("username = ?", username).Delete(&model.User{}).Error
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 	return nil
 }
+
+// ResetAccessToken replaces the access token of a user with a new one, revoking the old token.
+// It is the recovery path for an admin who lost their token, run with direct access to the database.
+// If username is empty, the token of the first admin user is reset.
+func (u *UserService) ResetAccessToken(username string) (*model.User, error) {
+	if !u.db.Migrator().HasTable(&model.User{}) {
+		return nil, errors.New("no users found, the server must be started and initialized first")
+	}
+	var user model.User
+	q := u.db.Order("id")
+	if username == "" {
+		q = q.Where("role = ?", types.UserRoleAdmin)
+	} else {
+		q = q.Where("username = ?", username)
+	}
+	if err := q.First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if username == "" {
+				return nil, errors.New("no admin user found, the server must be initialized first")
+			}
+			return nil, fmt.Errorf("user with username %s not found", username)
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	token, err := internal.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.db.Model(&user).Update("access_token", token).Error; err != nil {
+		return nil, fmt.Errorf("failed to reset access token: %w", err)
+	}
+	user.AccessToken = token
+	return &user, nil
+}
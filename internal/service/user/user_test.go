@@ -0,0 +1,50 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestResetAccessToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	u := NewUserService(db)
+
+	if _, err := u.ResetAccessToken(""); err == nil {
+		t.Fatal("ResetAccessToken() before the server was initialized succeeded, want error")
+	}
+	if _, err := u.CreateUser("alice"); err != nil {
+		t.Fatal(err)
+	}
+	admin, err := u.CreateAdminUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reset, err := u.ResetAccessToken("")
+	if err != nil {
+		t.Fatalf("ResetAccessToken() error = %v", err)
+	}
+	if reset.Username != "admin" || reset.AccessToken == admin.AccessToken || reset.AccessToken == "" {
+		t.Fatalf("ResetAccessToken() = %+v, want the admin with a new token", reset)
+	}
+	if _, err := u.GetUserByAccessToken(admin.AccessToken); err == nil {
+		t.Error("the old access token is still valid after the reset")
+	}
+	if got, err := u.GetUserByAccessToken(reset.AccessToken); err != nil || got.Username != "admin" {
+		t.Errorf("GetUserByAccessToken() with the new token = %v, %v, want admin", got, err)
+	}
+
+	if _, err := u.ResetAccessToken("bob"); err == nil {
+		t.Error("ResetAccessToken() of an unknown user succeeded, want error")
+	}
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// WebhookService manages the keys used to sign outbound webhook payloads and signs them.
+type WebhookService struct {
+	db *gorm.DB
+}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{db: db}
+}
+
+// RotateKey creates a new signing key which becomes the current key.
+// All previous keys that haven't expired yet remain valid for the given grace period.
+func (w *WebhookService) RotateKey(gracePeriod time.Duration) (*model.WebhookKey, error) {
+	secret, err := internal.GenerateAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook key: %w", err)
+	}
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook key ID: %w", err)
+	}
+	key := &model.WebhookKey{
+		KeyID:  "whk_" + hex.EncodeToString(id),
+		Secret: "whsec_" + secret,
+	}
+
+	expiresAt := time.Now().Add(gracePeriod)
+	err = w.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.WebhookKey{}).
+			Where("expires_at IS NULL OR expires_at > ?", expiresAt).
+			Update("expires_at", expiresAt).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(key).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate webhook key: %w", err)
+	}
+	return key, nil
+}
+
+// ListKeys returns the keys that are currently used for signing, newest first.
+// The secrets of the keys are not included.
+func (w *WebhookService) ListKeys() ([]*model.WebhookKey, error) {
+	keys, err := w.validKeys(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		k.Secret = ""
+	}
+	return keys, nil
+}
+
+// SignRequest adds the signature headers for the given body to an outbound webhook request.
+// If no webhook key has been created yet, the request is left unsigned.
+func (w *WebhookService) SignRequest(req *http.Request, body []byte) error {
+	now := time.Now()
+	keys, err := w.validKeys(now)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	ts := strconv.FormatInt(now.Unix(), 10)
+	parts := []string{"t=" + ts}
+	for _, k := range keys {
+		parts = append(parts, "v1="+ComputeSignature(k.Secret, ts, body))
+	}
+	req.Header.Set(types.WebhookSignatureHeader, strings.Join(parts, ","))
+	req.Header.Set(types.WebhookKeyIDHeader, keys[0].KeyID)
+	return nil
+}
+
+// ComputeSignature returns the hex-encoded HMAC-SHA256 of "<timestamp>.<body>" using the given secret.
+func ComputeSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validKeys returns the keys that haven't expired yet, newest first.
+func (w *WebhookService) validKeys(now time.Time) ([]*model.WebhookKey, error) {
+	var keys []*model.WebhookKey
+	err := w.db.
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("id DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook keys: %w", err)
+	}
+	return keys, nil
+}
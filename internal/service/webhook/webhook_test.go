@@ -0,0 +1,108 @@
+package webhook_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestWebhookService(t *testing.T) *webhook.WebhookService {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.WebhookKey{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return webhook.NewWebhookService(db)
+}
+
+func signedRequest(t *testing.T, w *webhook.WebhookService, body []byte) *http.Request {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	if err := w.SignRequest(req, body); err != nil {
+		t.Fatalf("SignRequest() error = %v", err)
+	}
+	return req
+}
+
+func TestSignRequestWithoutKeys(t *testing.T) {
+	w := newTestWebhookService(t)
+	req := signedRequest(t, w, []byte(`{}`))
+	if h := req.Header.Get(types.WebhookSignatureHeader); h != "" {
+		t.Errorf("expected request to be unsigned, got signature header %q", h)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	w := newTestWebhookService(t)
+	body := []byte(`{"budget":"b"}`)
+
+	oldKey, err := w.RotateKey(0)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	req := signedRequest(t, w, body)
+	if req.Header.Get(types.WebhookKeyIDHeader) != oldKey.KeyID {
+		t.Errorf("key ID header = %q, want %q", req.Header.Get(types.WebhookKeyIDHeader), oldKey.KeyID)
+	}
+	sig := req.Header.Get(types.WebhookSignatureHeader)
+	if err := client.VerifyWebhookSignature(body, sig, []string{oldKey.Secret}, 0); err != nil {
+		t.Errorf("VerifyWebhookSignature() error = %v", err)
+	}
+	if err := client.VerifyWebhookSignature([]byte(`{"budget":"x"}`), sig, []string{oldKey.Secret}, 0); !errors.Is(err, client.ErrInvalidWebhookSignature) {
+		t.Errorf("expected tampered body to be rejected, got %v", err)
+	}
+
+	// during the grace period, payloads are signed with both keys
+	newKey, err := w.RotateKey(time.Hour)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	sig = signedRequest(t, w, body).Header.Get(types.WebhookSignatureHeader)
+	for _, secret := range []string{oldKey.Secret, newKey.Secret} {
+		if err := client.VerifyWebhookSignature(body, sig, []string{secret}, 0); err != nil {
+			t.Errorf("VerifyWebhookSignature() error = %v", err)
+		}
+	}
+
+	// without a grace period, the previous keys stop being used immediately
+	latestKey, err := w.RotateKey(0)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	sig = signedRequest(t, w, body).Header.Get(types.WebhookSignatureHeader)
+	if err := client.VerifyWebhookSignature(body, sig, []string{latestKey.Secret}, 0); err != nil {
+		t.Errorf("VerifyWebhookSignature() error = %v", err)
+	}
+	if err := client.VerifyWebhookSignature(body, sig, []string{oldKey.Secret, newKey.Secret}, 0); err == nil {
+		t.Error("expected payload not to be signed with the expired keys")
+	}
+
+	keys, err := w.ListKeys()
+	if err != nil {
+		t.Fatalf("ListKeys() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].KeyID != latestKey.KeyID || keys[0].Secret != "" {
+		t.Errorf("ListKeys() = %+v, want only the latest key without its secret", keys)
+	}
+}
+
+func TestVerifyWebhookSignatureRejectsStalePayloads(t *testing.T) {
+	body := []byte(`{}`)
+	ts := "1000000000"
+	header := "t=" + ts + ",v1=" + webhook.ComputeSignature("secret", ts, body)
+	if err := client.VerifyWebhookSignature(body, header, []string{"secret"}, 0); !errors.Is(err, client.ErrInvalidWebhookSignature) {
+		t.Errorf("expected stale payload to be rejected, got %v", err)
+	}
+}
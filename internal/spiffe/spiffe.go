@@ -0,0 +1,303 @@
+// Package spiffe provides the SPIFFE workload identity of mcpjungle: its X.509 SVID, presented to the MCP servers
+// it connects to and to the MCP clients that connect to it, and the trust bundle that the SVIDs of its peers are
+// verified against.
+//
+// The SVID & bundle are read from PEM files, as written by the SPIRE agent's spiffe-helper, and re-read periodically
+// so that rotated SVIDs are picked up without restarting mcpjungle.
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval is how often the SVID & bundle files are re-read by default
+const DefaultRefreshInterval = time.Minute
+
+// Config describes the files containing the SVID of mcpjungle and the trust bundle.
+type Config struct {
+	// CertFile contains the PEM-encoded X.509 SVID, followed by its intermediate certificates if any
+	CertFile string
+	// KeyFile contains the PEM-encoded private key of the SVID
+	KeyFile string
+	// BundleFile contains the PEM-encoded CA certificates of the trust domains whose SVIDs are trusted.
+	// A CA certificate belongs to the trust domain of its SPIFFE ID (URI SAN), or to mcpjungle's own trust domain
+	// if it has none, eg- a federated bundle is a set of CA certificates with the SPIFFE ID of their trust domain.
+	BundleFile string
+}
+
+// Source holds the current SVID of mcpjungle and the trust bundles.
+type Source struct {
+	cfg Config
+
+	mu   sync.RWMutex
+	svid *tls.Certificate
+	id   string
+	// bundles maps trust domains to their CA certificates, an SVID is only verified against the bundle of its trust domain
+	bundles map[string]*x509.CertPool
+}
+
+// NewSource loads the SVID & bundle from the files of the given configuration.
+func NewSource(cfg Config) (*Source, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.BundleFile == "" {
+		return nil, errors.New("the SVID certificate, key and trust bundle files are all required")
+	}
+	s := &Source{cfg: cfg}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the SVID & bundle files. The current ones are kept if the files are invalid.
+func (s *Source) Reload() error {
+	svid, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the SVID: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(svid.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse the SVID: %w", err)
+	}
+	id, err := IDFromCertificate(leaf)
+	if err != nil {
+		return fmt.Errorf("invalid SVID in %s: %w", s.cfg.CertFile, err)
+	}
+	svid.Leaf = leaf
+
+	pemBundle, err := os.ReadFile(s.cfg.BundleFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the trust bundle: %w", err)
+	}
+	bundles, err := parseBundles(pemBundle, TrustDomain(id))
+	if err != nil {
+		return fmt.Errorf("invalid trust bundle %s: %w", s.cfg.BundleFile, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.svid = &svid
+	s.id = id
+	s.bundles = bundles
+	return nil
+}
+
+// parseBundles groups the PEM-encoded CA certificates of a bundle by trust domain.
+// The certificates without a SPIFFE ID belong to localDomain.
+func parseBundles(pemBundle []byte, localDomain string) (map[string]*x509.CertPool, error) {
+	bundles := make(map[string]*x509.CertPool)
+	for {
+		var block *pem.Block
+		block, pemBundle = pem.Decode(pemBundle)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		ca, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse a CA certificate: %w", err)
+		}
+		domain := localDomain
+		if len(ca.URIs) > 0 {
+			id, err := IDFromCertificate(ca)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CA certificate %s: %w", ca.Subject, err)
+			}
+			domain = TrustDomain(id)
+		}
+		if bundles[domain] == nil {
+			bundles[domain] = x509.NewCertPool()
+		}
+		bundles[domain].AddCert(ca)
+	}
+	if len(bundles) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return bundles, nil
+}
+
+// Run re-reads the SVID & bundle files every interval until ctx is cancelled (blocking call).
+func (s *Source) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(); err != nil {
+				log.Printf("[ERROR] failed to refresh the SPIFFE SVID, keeping the current one: %v", err)
+			}
+		}
+	}
+}
+
+// ID returns the SPIFFE ID of mcpjungle
+func (s *Source) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+func (s *Source) current() *tls.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.svid
+}
+
+// bundle returns the CA certificates of a trust domain, nil if it isn't trusted
+func (s *Source) bundle(domain string) *x509.CertPool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bundles[domain]
+}
+
+// ServerTLSConfig returns the TLS configuration of the mcpjungle server: it presents the SVID and verifies
+// the SVIDs that clients present against the bundle.
+// Client certificates are optional, so that clients can still authenticate with access tokens.
+func (s *Source) ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.current(), nil
+		},
+		// the chain is verified against the current bundle below, as the standard verification can't be given
+		// a bundle that changes
+		ClientAuth: tls.RequestClientCert,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return nil
+			}
+			_, err := s.Verify(rawCerts)
+			return err
+		},
+	}
+}
+
+// ClientTLSConfig returns the TLS configuration to connect to an upstream server with mTLS: it presents the SVID
+// and verifies that the server presents an SVID for the expected SPIFFE ID.
+func (s *Source) ClientTLSConfig(expectedID string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return s.current(), nil
+		},
+		// SVIDs are not issued for DNS names, the peer is authenticated by its SPIFFE ID instead of its hostname
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			id, err := s.Verify(rawCerts)
+			if err != nil {
+				return err
+			}
+			if id != expectedID {
+				return fmt.Errorf("unexpected SPIFFE ID %s, expected %s", id, expectedID)
+			}
+			return nil
+		},
+	}
+}
+
+// Verify verifies a chain of certificates presented by a peer against the bundle of its trust domain,
+// and returns the SPIFFE ID of the peer's SVID. SVIDs of trust domains without a bundle are rejected.
+func (s *Source) Verify(rawCerts [][]byte) (string, error) {
+	if len(rawCerts) == 0 {
+		return "", errors.New("no SVID presented")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		c, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the SVID: %w", err)
+		}
+		certs[i] = c
+	}
+	leaf := certs[0]
+	if leaf.IsCA {
+		return "", errors.New("the SVID must not be a CA certificate")
+	}
+	id, err := IDFromCertificate(leaf)
+	if err != nil {
+		return "", err
+	}
+
+	bundle := s.bundle(TrustDomain(id))
+	if bundle == nil {
+		return "", fmt.Errorf("the SVID of %s is not trusted: no bundle for trust domain %s", id, TrustDomain(id))
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         bundle,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return "", fmt.Errorf("the SVID of %s is not trusted: %w", id, err)
+	}
+	return id, nil
+}
+
+// IDFromCertificate returns the SPIFFE ID of an X.509 SVID, its only URI SAN.
+func IDFromCertificate(c *x509.Certificate) (string, error) {
+	if len(c.URIs) != 1 {
+		return "", fmt.Errorf("an SVID must have exactly one URI SAN, found %d", len(c.URIs))
+	}
+	id := c.URIs[0].String()
+	if err := ValidateID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// ValidateID checks that id is a valid SPIFFE ID (spiffe://<trust domain>/<path>).
+func ValidateID(id string) error {
+	u, err := url.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid SPIFFE ID '%s': %w", id, err)
+	}
+	if u.Scheme != "spiffe" {
+		return fmt.Errorf("invalid SPIFFE ID '%s': the scheme must be spiffe", id)
+	}
+	if u.Host == "" || u.Host != strings.ToLower(u.Host) {
+		return fmt.Errorf("invalid SPIFFE ID '%s': the trust domain must be a non-empty lowercase name", id)
+	}
+	if u.User != nil || u.Port() != "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid SPIFFE ID '%s': it must not contain a userinfo, port, query or fragment", id)
+	}
+	if strings.HasSuffix(u.Path, "/") {
+		return fmt.Errorf("invalid SPIFFE ID '%s': the path must not end with a slash", id)
+	}
+	return nil
+}
+
+// TrustDomain returns the trust domain of a valid SPIFFE ID, eg- example.org for spiffe://example.org/agent.
+func TrustDomain(id string) string {
+	domain, _, _ := strings.Cut(strings.TrimPrefix(id, "spiffe://"), "/")
+	return domain
+}
+
+// PeerID returns the SPIFFE ID of the verified SVID that the peer presented on a TLS connection, if any.
+func PeerID(state *tls.ConnectionState) (string, bool) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return "", false
+	}
+	id, err := IDFromCertificate(state.PeerCertificates[0])
+	if err != nil {
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,233 @@
+package spiffe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	return newTestDomainCA(t, "")
+}
+
+// newTestDomainCA creates a CA with the SPIFFE ID of the given trust domain, or without SPIFFE ID if it is empty
+func newTestDomainCA(t *testing.T, domain string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	if domain != "" {
+		tmpl.URIs = []*url.URL{{Scheme: "spiffe", Host: domain}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key}
+}
+
+// writeSVID issues an SVID for id and writes it, its key and the CA's bundle to files in dir
+func (ca *testCA) writeSVID(t *testing.T, dir, id string) Config {
+	t.Helper()
+	der, key := ca.issue(t, id)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{
+		CertFile:   filepath.Join(dir, "svid.pem"),
+		KeyFile:    filepath.Join(dir, "svid_key.pem"),
+		BundleFile: filepath.Join(dir, "bundle.pem"),
+	}
+	for file, block := range map[string]*pem.Block{
+		cfg.CertFile:   {Type: "CERTIFICATE", Bytes: der},
+		cfg.KeyFile:    {Type: "PRIVATE KEY", Bytes: keyDER},
+		cfg.BundleFile: {Type: "CERTIFICATE", Bytes: ca.cert.Raw},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cfg
+}
+
+// issue issues an SVID for id, it returns the DER-encoded certificate and its key
+func (ca *testCA) issue(t *testing.T, id string) ([]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(id)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{u},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der, key
+}
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"spiffe://example.org/ns/prod/sa/agent", "spiffe://example.org"} {
+		if err := ValidateID(id); err != nil {
+			t.Errorf("expected %s to be valid, got %v", id, err)
+		}
+	}
+	for _, id := range []string{
+		"https://example.org/agent",
+		"spiffe:///agent",
+		"spiffe://Example.org/agent",
+		"spiffe://example.org:8443/agent",
+		"spiffe://example.org/agent/",
+		"spiffe://example.org/agent?x=1",
+	} {
+		if err := ValidateID(id); err == nil {
+			t.Errorf("expected %s to be invalid", id)
+		}
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverSource, err := NewSource(ca.writeSVID(t, t.TempDir(), "spiffe://example.org/mcpjungle"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSource, err := NewSource(ca.writeSVID(t, t.TempDir(), "spiffe://example.org/agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clientSource.ID() != "spiffe://example.org/agent" {
+		t.Fatalf("unexpected SPIFFE ID %s", clientSource.ID())
+	}
+
+	// httptest.Server.StartTLS would serve its own certificate instead of the SVID
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := PeerID(r.TLS)
+		_, _ = w.Write([]byte(id))
+	}))
+	srv.Listener = tls.NewListener(srv.Listener, serverSource.ServerTLSConfig())
+	srv.Start()
+	defer srv.Close()
+	serverURL := strings.Replace(srv.URL, "http://", "https://", 1)
+
+	get := func(source *Source, expectedID string) (string, error) {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: source.ClientTLSConfig(expectedID)}}
+		resp, err := c.Get(serverURL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	peer, err := get(clientSource, "spiffe://example.org/mcpjungle")
+	if err != nil {
+		t.Fatalf("expected mTLS to succeed, got %v", err)
+	}
+	if peer != "spiffe://example.org/agent" {
+		t.Fatalf("expected the server to see the client's SPIFFE ID, got '%s'", peer)
+	}
+
+	if _, err := get(clientSource, "spiffe://example.org/other"); err == nil {
+		t.Fatal("expected the connection to fail when the server has another SPIFFE ID")
+	}
+
+	// an SVID issued by another trust domain's CA is rejected by the server
+	rogue, err := NewSource(newTestCA(t).writeSVID(t, t.TempDir(), "spiffe://example.org/agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rogue.bundles = clientSource.bundles
+	if _, err := get(rogue, "spiffe://example.org/mcpjungle"); err == nil {
+		t.Fatal("expected an untrusted SVID to be rejected")
+	}
+}
+
+func TestIDFromCertificateRequiresOneURI(t *testing.T) {
+	a, _ := url.Parse("spiffe://example.org/a")
+	b, _ := url.Parse("spiffe://example.org/b")
+	if _, err := IDFromCertificate(&x509.Certificate{URIs: []*url.URL{a, b}}); err == nil {
+		t.Fatal("expected an error for a certificate with two URI SANs")
+	}
+	if _, err := IDFromCertificate(&x509.Certificate{}); err == nil {
+		t.Fatal("expected an error for a certificate without URI SAN")
+	}
+}
+
+func TestVerifyAgainstTheBundleOfTheTrustDomain(t *testing.T) {
+	local, partner := newTestCA(t), newTestDomainCA(t, "partner.org")
+	cfg := local.writeSVID(t, t.TempDir(), "spiffe://example.org/mcpjungle")
+	// a federated bundle is appended to the bundle of the local trust domain
+	federated := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: local.cert.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: partner.cert.Raw})...)
+	if err := os.WriteFile(cfg.BundleFile, federated, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		ca      *testCA
+		id      string
+		trusted bool
+	}{
+		{local, "spiffe://example.org/agent", true},
+		{partner, "spiffe://partner.org/agent", true},
+		// a CA can't issue SVIDs for another trust domain
+		{partner, "spiffe://example.org/agent", false},
+		{local, "spiffe://partner.org/agent", false},
+		// nor for a trust domain without a bundle
+		{local, "spiffe://unknown.org/agent", false},
+	} {
+		der, _ := tc.ca.issue(t, tc.id)
+		id, err := source.Verify([][]byte{der})
+		if tc.trusted && (err != nil || id != tc.id) {
+			t.Errorf("Verify(%s) = %s, %v, want it to be trusted", tc.id, id, err)
+		}
+		if !tc.trusted && err == nil {
+			t.Errorf("Verify(%s) succeeded, want it to be rejected", tc.id)
+		}
+	}
+}
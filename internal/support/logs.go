@@ -0,0 +1,62 @@
+package support
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// DefaultLogLines is the number of recent log lines kept for support bundles
+const DefaultLogLines = 1000
+
+// Logs keeps the recent log lines of the server, the server's logger writes to it.
+var Logs = NewLogBuffer(DefaultLogLines)
+
+// LogBuffer is an io.Writer that keeps the last lines written to it.
+type LogBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial []byte
+}
+
+// NewLogBuffer creates a buffer that keeps the last size lines.
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{lines: make([]string, size)}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data := append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		b.lines[b.next] = string(data[:i])
+		b.next = (b.next + 1) % len(b.lines)
+		if b.next == 0 {
+			b.full = true
+		}
+		data = data[i+1:]
+	}
+	b.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// String returns the lines kept, oldest first.
+func (b *LogBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lines []string
+	if b.full {
+		lines = append(lines, b.lines[b.next:]...)
+	}
+	lines = append(lines, b.lines[:b.next]...)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,39 @@
+package support
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`{"bearer_token": "abc123", "url": "https://api.example.com"}`, `{"bearer_token": "[REDACTED]", "url": "https://api.example.com"}`},
+		{`{"GITHUB_TOKEN": "ghp_x\"y", "LOG_LEVEL": "debug"}`, `{"GITHUB_TOKEN": "[REDACTED]", "LOG_LEVEL": "debug"}`},
+		{`"AUTHORIZER_AUTHORIZATION": "Bearer s3cr3t"`, `"AUTHORIZER_AUTHORIZATION": "[REDACTED]"`},
+		{`connecting to postgres://admin:hunter2@db:5432/mcpjungle`, `connecting to postgres://admin:[REDACTED]@db:5432/mcpjungle`},
+		{`started with API_KEY=xyz LOG_LEVEL=info`, `started with API_KEY=[REDACTED] LOG_LEVEL=info`},
+		{`Authorization: Bearer eyJhbGciOi.x.y`, `Authorization: [REDACTED]`},
+		{`GET https://maps.example.com/geocode?q=paris&key=AIza123`, `GET https://maps.example.com/geocode?q=paris&key=[REDACTED]`},
+		{`mcpjungle_tool_calls_total{tool="github__search"} 3`, `mcpjungle_tool_calls_total{tool="github__search"} 3`},
+	}
+	for _, tt := range tests {
+		if got := string(Redact([]byte(tt.in))); got != tt.want {
+			t.Errorf("Redact(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogBuffer(t *testing.T) {
+	b := NewLogBuffer(3)
+	_, _ = b.Write([]byte("one\ntwo\nthr"))
+	_, _ = b.Write([]byte("ee\nfour\nfive"))
+	if got, want := b.String(), "two\nthree\nfour\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if strings.Contains(b.String(), "five") {
+		t.Error("String() contains an incomplete line")
+	}
+}
@@ -0,0 +1,157 @@
+package toolbox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Evaluate evaluates an arithmetic expression made of numbers, + - * / ^ (power), unary minus and parentheses.
+// Operators follow the usual precedence, ^ is right-associative.
+func Evaluate(expr string) (float64, error) {
+	p := &parser{input: expr}
+	v, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos+1)
+	}
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("result of '%s' is not a finite number", expr)
+	}
+	return v, nil
+}
+
+// parser is a recursive descent parser for arithmetic expressions
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// next consumes and returns the next operator if it is one of ops, otherwise it returns 0
+func (p *parser) next(ops string) byte {
+	p.skipSpace()
+	if p.pos < len(p.input) && strings.IndexByte(ops, p.input[p.pos]) >= 0 {
+		p.pos++
+		return p.input[p.pos-1]
+	}
+	return 0
+}
+
+// parseSum parses terms separated by + and -
+func (p *parser) parseSum() (float64, error) {
+	v, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.next("+-")
+		if op == 0 {
+			return v, nil
+		}
+		rhs, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+}
+
+// parseProduct parses factors separated by * and /
+func (p *parser) parseProduct() (float64, error) {
+	v, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.next("*/")
+		if op == 0 {
+			return v, nil
+		}
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			v *= rhs
+		} else {
+			if rhs == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			v /= rhs
+		}
+	}
+}
+
+// parseUnary parses an optionally negated power, so that -2^2 is -(2^2)
+func (p *parser) parseUnary() (float64, error) {
+	if op := p.next("-+"); op != 0 {
+		v, err := p.parseUnary()
+		if op == '-' {
+			v = -v
+		}
+		return v, err
+	}
+	return p.parsePower()
+}
+
+// parsePower parses an operand optionally raised to a power
+func (p *parser) parsePower() (float64, error) {
+	base, err := p.parseOperand()
+	if err != nil {
+		return 0, err
+	}
+	if p.next("^") == 0 {
+		return base, nil
+	}
+	exp, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exp), nil
+}
+
+// parseOperand parses a number or a parenthesized expression
+func (p *parser) parseOperand() (float64, error) {
+	if p.next("(") != 0 {
+		v, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if p.next(")") == 0 {
+			return 0, fmt.Errorf("missing ')' at position %d", p.pos+1)
+		}
+		return v, nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] == '.' || unicode.IsDigit(rune(p.input[p.pos]))) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos >= len(p.input) {
+			return 0, fmt.Errorf("unexpected end of expression")
+		}
+		return 0, fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos+1)
+	}
+	v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number '%s'", p.input[start:p.pos])
+	}
+	return v, nil
+}
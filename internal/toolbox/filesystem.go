@@ -0,0 +1,271 @@
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// readMaxBytes is the maximum size of a file that the read_file tool returns
+	readMaxBytes = 1 << 20
+
+	// searchMaxResults is the maximum number of paths returned by the search_files tool
+	searchMaxResults = 500
+)
+
+// errSearchLimit stops a search once enough results have been found
+var errSearchLimit = errors.New("search result limit reached")
+
+// NewFilesystemServer creates an MCP server that provides read-only access to the given root directories.
+// Paths passed to its tools are resolved (including symlinks) and rejected if they fall outside every root.
+func NewFilesystemServer(name, version string, roots []string) (*server.MCPServer, error) {
+	if len(roots) == 0 {
+		return nil, errors.New("at least one root directory is required")
+	}
+	sb := &sandbox{}
+	for _, r := range roots {
+		abs, err := filepath.Abs(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root directory %s: %w", r, err)
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root directory %s: %w", r, err)
+		}
+		if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("root %s is not a directory", r)
+		}
+		sb.roots = append(sb.roots, resolved)
+	}
+
+	s := server.NewMCPServer(name, version, server.WithToolCapabilities(true))
+	pathArg := mcp.WithString(
+		"path",
+		mcp.Required(),
+		mcp.Description("Absolute path, or a path relative to the first allowed directory"),
+	)
+
+	s.AddTool(
+		mcp.NewTool(
+			"list_allowed_directories",
+			mcp.WithDescription("Returns the directories that the other tools can access"),
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		sb.listAllowedDirectories,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			"list_directory",
+			mcp.WithDescription("Lists the entries of a directory, directories are suffixed with /"),
+			pathArg,
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		sb.listDirectory,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			"read_file",
+			mcp.WithDescription(fmt.Sprintf("Returns the content of a text file, up to %d bytes", readMaxBytes)),
+			pathArg,
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		sb.readFile,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			"get_file_info",
+			mcp.WithDescription("Returns the type, size, permissions and modification time of a file or directory"),
+			pathArg,
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		sb.getFileInfo,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			"search_files",
+			mcp.WithDescription(
+				fmt.Sprintf(
+					"Recursively searches a directory for files whose names match a glob pattern, eg- *.go."+
+						" Returns up to %d paths",
+					searchMaxResults,
+				),
+			),
+			pathArg,
+			mcp.WithString("pattern", mcp.Required(), mcp.Description("Glob pattern to match file names against")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		sb.searchFiles,
+	)
+	return s, nil
+}
+
+// sandbox confines file access to a set of root directories
+type sandbox struct {
+	// roots are absolute paths with all symlinks resolved
+	roots []string
+}
+
+// resolve returns the absolute, symlink-free form of path.
+// It returns an error if the path does not exist or is outside all roots.
+func (sb *sandbox) resolve(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(sb.roots[0], path)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("%s does not exist", path)
+		}
+		return "", err
+	}
+	if !sb.contains(resolved) {
+		return "", fmt.Errorf("access denied, %s is outside the allowed directories", path)
+	}
+	return resolved, nil
+}
+
+// contains returns true if the resolved path is one of the roots or inside one of them
+func (sb *sandbox) contains(resolved string) bool {
+	for _, root := range sb.roots {
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sb *sandbox) listAllowedDirectories(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(strings.Join(sb.roots, "\n")), nil
+}
+
+func (sb *sandbox) listDirectory(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := sb.pathArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list directory: %v", err)), nil
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(e.Name())
+		if e.IsDir() {
+			b.WriteString("/")
+		}
+		b.WriteString("\n")
+	}
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+func (sb *sandbox) readFile(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := sb.pathArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to open file: %v", err)), nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to open file: %v", err)), nil
+	}
+	if info.IsDir() {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is a directory", path)), nil
+	}
+	if info.Size() > readMaxBytes {
+		return mcp.NewToolResultError(
+			fmt.Sprintf("%s is too large (%d bytes), at most %d bytes can be read", path, info.Size(), readMaxBytes),
+		), nil
+	}
+	content, err := io.ReadAll(io.LimitReader(f, readMaxBytes))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read file: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(content)), nil
+}
+
+func (sb *sandbox) getFileInfo(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := sb.pathArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get file info: %v", err)), nil
+	}
+	kind := "file"
+	if info.IsDir() {
+		kind = "directory"
+	}
+	return mcp.NewToolResultText(
+		fmt.Sprintf(
+			"path: %s\ntype: %s\nsize: %d\npermissions: %s\nmodified: %s",
+			path, kind, info.Size(), info.Mode().Perm(), info.ModTime().UTC().Format("2006-01-02T15:04:05Z"),
+		),
+	), nil
+}
+
+func (sb *sandbox) searchFiles(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	root, err := sb.pathArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	pattern, err := req.RequireString("pattern")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid pattern: %v", err)), nil
+	}
+
+	var matches []string
+	// WalkDir does not follow symlinks, so the search cannot escape the sandbox
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// skip unreadable entries instead of failing the whole search
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); ok && p != root {
+			matches = append(matches, p)
+			if len(matches) >= searchMaxResults {
+				return errSearchLimit
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSearchLimit) {
+		return mcp.NewToolResultError(fmt.Sprintf("search failed: %v", err)), nil
+	}
+	if len(matches) == 0 {
+		return mcp.NewToolResultText("no matches found"), nil
+	}
+	return mcp.NewToolResultText(strings.Join(matches, "\n")), nil
+}
+
+// pathArg returns the resolved value of the "path" argument of a tool call
+func (sb *sandbox) pathArg(req mcp.CallToolRequest) (string, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return "", err
+	}
+	return sb.resolve(path)
+}
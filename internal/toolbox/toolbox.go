@@ -0,0 +1,180 @@
+// Package toolbox provides an MCP server with a handful of built-in utility tools.
+// It is useful for demos and smoke tests, and as a reference implementation of an MCP server.
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// fetchTimeout is the maximum time allowed for the fetch tool to retrieve a URL
+	fetchTimeout = 10 * time.Second
+
+	// fetchMaxBytes is the maximum size of the content returned by the fetch tool, longer content is truncated
+	fetchMaxBytes = 1 << 20
+)
+
+// Options configures the toolbox server.
+type Options struct {
+	// FetchAllowedHosts contains the hosts that the fetch tool may retrieve URLs from.
+	// Subdomains of these hosts are allowed as well. If empty, the fetch tool is not provided.
+	FetchAllowedHosts []string
+}
+
+// NewServer creates an MCP server that provides the toolbox tools:
+// echo, current_time, calculate and, if any hosts are allowed, fetch.
+func NewServer(name, version string, opts Options) *server.MCPServer {
+	s := server.NewMCPServer(name, version, server.WithToolCapabilities(true))
+
+	s.AddTool(
+		mcp.NewTool(
+			"echo",
+			mcp.WithDescription("Returns the message it receives"),
+			mcp.WithString("message", mcp.Required(), mcp.Description("Message to return")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		echo,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			"current_time",
+			mcp.WithDescription("Returns the current time in RFC 3339 format"),
+			mcp.WithString("timezone", mcp.Description("IANA time zone, eg- Europe/Berlin. Defaults to UTC")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		currentTime,
+	)
+	s.AddTool(
+		mcp.NewTool(
+			"calculate",
+			mcp.WithDescription("Evaluates an arithmetic expression with + - * / ^ and parentheses, eg- (2 + 3) * 4"),
+			mcp.WithString("expression", mcp.Required(), mcp.Description("Expression to evaluate")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		calculate,
+	)
+	if len(opts.FetchAllowedHosts) > 0 {
+		f := newFetcher(opts.FetchAllowedHosts)
+		s.AddTool(
+			mcp.NewTool(
+				"fetch",
+				mcp.WithDescription(
+					"Fetches a URL and returns its content. Only these hosts are allowed: "+
+						strings.Join(opts.FetchAllowedHosts, ", "),
+				),
+				mcp.WithString("url", mcp.Required(), mcp.Description("URL to fetch")),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithOpenWorldHintAnnotation(true),
+			),
+			f.fetch,
+		)
+	}
+	return s
+}
+
+func echo(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	msg, err := req.RequireString("message")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(msg), nil
+}
+
+func currentTime(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	loc, err := time.LoadLocation(req.GetString("timezone", "UTC"))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v", err)), nil
+	}
+	return mcp.NewToolResultText(time.Now().In(loc).Format(time.RFC3339)), nil
+}
+
+func calculate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	expr, err := req.RequireString("expression")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	v, err := Evaluate(expr)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(strconv.FormatFloat(v, 'g', -1, 64)), nil
+}
+
+// fetcher retrieves URLs from a set of allowed hosts
+type fetcher struct {
+	allowedHosts []string
+	httpClient   *http.Client
+}
+
+func newFetcher(allowedHosts []string) *fetcher {
+	f := &fetcher{allowedHosts: allowedHosts}
+	f.httpClient = &http.Client{
+		Timeout: fetchTimeout,
+		// redirects must not lead to hosts that aren't allowed
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+	return f
+}
+
+// checkURL returns an error if u may not be fetched.
+func (f *fetcher) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme '%s'", u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range f.allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host '%s' is not allowed", host)
+}
+
+func (f *fetcher) fetch(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rawURL, err := req.RequireString("url")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid URL: %v", err)), nil
+	}
+	if err := f.checkURL(u); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	resp, err := f.httpClient.Do(httpReq)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to fetch %s: %v", u, err)), nil
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, fetchMaxBytes))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to read response from %s: %v", u, err)), nil
+	}
+	if resp.StatusCode >= 400 {
+		return mcp.NewToolResultError(fmt.Sprintf("%s responded with status %d: %s", u, resp.StatusCode, body)), nil
+	}
+	return mcp.NewToolResultText(string(body)), nil
+}
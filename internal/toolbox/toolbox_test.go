@@ -0,0 +1,83 @@
+package toolbox
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 / 4 - 0.5", 2},
+		{"2 ^ 3 ^ 2", 512},
+		{"-2 ^ 2", -4},
+		{"2 ^ -1", 0.5},
+		{"--3", 3},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr)
+		if err != nil || got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, %v, want %v", tt.expr, got, err, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "1 +", "(1 + 2", "1 / 0", "2 * x", "1 2", "1.2.3"} {
+		if _, err := Evaluate(expr); err == nil {
+			t.Errorf("Evaluate(%q) error = nil, want error", expr)
+		}
+	}
+}
+
+func TestFetcherCheckURL(t *testing.T) {
+	f := newFetcher([]string{"example.com"})
+	allowed := map[string]bool{
+		"https://example.com/a":         true,
+		"http://docs.example.com/b":     true,
+		"https://notexample.com/":       false,
+		"https://example.com.evil.com/": false,
+		"file:///etc/passwd":            false,
+	}
+	for raw, want := range allowed {
+		u, _ := url.Parse(raw)
+		if got := f.checkURL(u) == nil; got != want {
+			t.Errorf("checkURL(%s) allowed = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestSandboxResolve(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("hi"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	resolvedRoot, _ := filepath.EvalSymlinks(root)
+	sb := &sandbox{roots: []string{resolvedRoot}}
+	allowed := map[string]bool{
+		"notes.txt":                          true,
+		filepath.Join(root, "notes.txt"):     true,
+		root:                                 true,
+		"../" + filepath.Base(outside):       false,
+		filepath.Join(outside, "secret.txt"): false,
+		"escape/secret.txt":                  false,
+		"missing.txt":                        false,
+	}
+	for path, want := range allowed {
+		if _, err := sb.resolve(path); (err == nil) != want {
+			t.Errorf("resolve(%s) error = %v, want allowed = %v", path, err, want)
+		}
+	}
+}
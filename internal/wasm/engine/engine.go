@@ -0,0 +1,249 @@
+//go:build !nowasm
+
+// Package engine implements the plugins of the wasm package with the wazero WebAssembly runtime.
+// Binaries built with the nowasm tag leave it out, they fail to load plugins.
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	hookOnRequest  = "on_request"
+	hookOnResponse = "on_response"
+
+	// memoryLimitPages limits the memory of a plugin to 16 MiB (pages are 64 KiB)
+	memoryLimitPages = 256
+)
+
+// Runtime holds the loaded plugins. A nil *Runtime is valid and leaves all calls unchanged.
+type Runtime struct {
+	runtime wazero.Runtime
+	plugins []*plugin
+	timeout time.Duration
+}
+
+type plugin struct {
+	name   string
+	module wazero.CompiledModule
+	hooks  map[string]bool
+}
+
+// pluginNameKey is the context key that holds the name of the plugin running a hook, for logging
+type pluginNameKey struct{}
+
+// Load compiles the plugins at the given paths. Their hooks run in the given order.
+// A zero timeout stands for wasm.DefaultTimeout.
+func Load(ctx context.Context, paths []string, timeout time.Duration) (wasm.Plugins, error) {
+	if timeout <= 0 {
+		timeout = wasm.DefaultTimeout
+	}
+	r := &Runtime{
+		runtime: wazero.NewRuntimeWithConfig(
+			ctx,
+			wazero.NewRuntimeConfig().WithCloseOnContextDone(true).WithMemoryLimitPages(memoryLimitPages),
+		),
+		timeout: timeout,
+	}
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r.runtime); err != nil {
+		_ = r.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+	_, err := r.runtime.NewHostModuleBuilder("mcpjungle").
+		NewFunctionBuilder().WithFunc(hostLog).Export("log").
+		Instantiate(ctx)
+	if err != nil {
+		_ = r.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate host functions: %w", err)
+	}
+
+	for _, path := range paths {
+		p, err := r.compile(ctx, path)
+		if err != nil {
+			_ = r.Close(ctx)
+			return nil, fmt.Errorf("failed to load WASM plugin %s: %w", path, err)
+		}
+		r.plugins = append(r.plugins, p)
+	}
+	return r, nil
+}
+
+// compile compiles a plugin and checks that it implements the ABI.
+func (r *Runtime) compile(ctx context.Context, path string) (*plugin, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	module, err := r.runtime.CompileModule(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	p := &plugin{
+		name:   strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		module: module,
+		hooks:  make(map[string]bool),
+	}
+	if _, ok := module.ExportedMemories()["memory"]; !ok {
+		return nil, errors.New("module does not export its memory as \"memory\"")
+	}
+	exports := module.ExportedFunctions()
+	if !hasSignature(exports["malloc"], []api.ValueType{api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}) {
+		return nil, errors.New("module does not export \"malloc(i32) i32\"")
+	}
+	for _, hook := range []string{hookOnRequest, hookOnResponse} {
+		def, ok := exports[hook]
+		if !ok {
+			continue
+		}
+		if !hasSignature(def, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64}) {
+			return nil, fmt.Errorf("export %s must have the signature (i32, i32) i64", hook)
+		}
+		p.hooks[hook] = true
+	}
+	if len(p.hooks) == 0 {
+		return nil, fmt.Errorf("module exports neither %s nor %s", hookOnRequest, hookOnResponse)
+	}
+	return p, nil
+}
+
+// Names returns the names of the loaded plugins, in the order their hooks run.
+func (r *Runtime) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, len(r.plugins))
+	for i, p := range r.plugins {
+		names[i] = p.name
+	}
+	return names
+}
+
+// OnRequest runs the on_request hooks of the plugins. Every hook receives the arguments returned by the previous one.
+// It returns the arguments that the tool must be called with.
+func (r *Runtime) OnRequest(ctx context.Context, call wasm.Call) (map[string]any, error) {
+	if r == nil {
+		return call.Args, nil
+	}
+	for _, p := range r.plugins {
+		if !p.hooks[hookOnRequest] {
+			continue
+		}
+		out, err := r.run(ctx, p, hookOnRequest, call)
+		if err != nil {
+			return nil, err
+		}
+		if out != nil && out.Args != nil {
+			call.Args = out.Args
+		}
+	}
+	return call.Args, nil
+}
+
+// OnResponse runs the on_response hooks of the plugins. Every hook receives the result returned by the previous one.
+// It returns the new result of the call, or nil if no plugin changed it.
+func (r *Runtime) OnResponse(ctx context.Context, call wasm.Call) (json.RawMessage, error) {
+	if r == nil {
+		return nil, nil
+	}
+	var changed json.RawMessage
+	for _, p := range r.plugins {
+		if !p.hooks[hookOnResponse] {
+			continue
+		}
+		out, err := r.run(ctx, p, hookOnResponse, call)
+		if err != nil {
+			return nil, err
+		}
+		if out != nil && len(out.Result) > 0 {
+			call.Result = out.Result
+			changed = out.Result
+		}
+	}
+	return changed, nil
+}
+
+// run calls a hook of a plugin in a fresh instance of its module.
+// Plugins fail closed: if the hook fails or times out, the call fails too.
+func (r *Runtime) run(ctx context.Context, p *plugin, hook string, call wasm.Call) (*wasm.Output, error) {
+	input, err := json.Marshal(call)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, pluginNameKey{}, p.name), r.timeout)
+	defer cancel()
+	// anonymous instances can run concurrently, and nothing leaks from one call to another
+	mod, err := r.runtime.InstantiateModule(
+		ctx, p.module, wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate WASM plugin %s: %w", p.name, err)
+	}
+	defer mod.Close(ctx)
+
+	res, err := mod.ExportedFunction("malloc").Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("WASM plugin %s failed to allocate memory: %w", p.name, err)
+	}
+	ptr := uint32(res[0])
+	if !mod.Memory().Write(ptr, input) {
+		return nil, fmt.Errorf("WASM plugin %s allocated memory out of range", p.name)
+	}
+	res, err = mod.ExportedFunction(hook).Call(ctx, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("%s hook of WASM plugin %s failed: %w", hook, p.name, err)
+	}
+	if res[0] == 0 {
+		return nil, nil
+	}
+	b, ok := mod.Memory().Read(uint32(res[0]>>32), uint32(res[0]))
+	if !ok {
+		return nil, fmt.Errorf("%s hook of WASM plugin %s returned output out of range", hook, p.name)
+	}
+	var out wasm.Output
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("%s hook of WASM plugin %s returned invalid output: %w", hook, p.name, err)
+	}
+	if out.Deny != "" {
+		return nil, fmt.Errorf("%w %s: %s", wasm.ErrDenied, p.name, out.Deny)
+	}
+	return &out, nil
+}
+
+// Close releases the resources of all plugins.
+func (r *Runtime) Close(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	return r.runtime.Close(ctx)
+}
+
+// hostLog implements the "log" function imported by plugins.
+func hostLog(ctx context.Context, m api.Module, ptr, length uint32) {
+	b, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		return
+	}
+	name, _ := ctx.Value(pluginNameKey{}).(string)
+	log.Printf("[wasm:%s] %s", name, b)
+}
+
+func hasSignature(def api.FunctionDefinition, params, results []api.ValueType) bool {
+	if def == nil {
+		return false
+	}
+	return string(def.ParamTypes()) == string(params) && string(def.ResultTypes()) == string(results)
+}
@@ -0,0 +1,16 @@
+//go:build nowasm
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+)
+
+// Load fails, this binary was built without the WebAssembly runtime.
+func Load(ctx context.Context, paths []string, timeout time.Duration) (wasm.Plugins, error) {
+	return nil, errors.New("WASM plugins are not supported by this build of mcpjungle (built with the nowasm tag)")
+}
@@ -0,0 +1,129 @@
+//go:build !nowasm
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/wasm"
+)
+
+// testPlugin assembles a WebAssembly module whose hook returns output, a constant JSON document.
+// Its malloc always returns the same address, which is enough for a single call per instance.
+func testPlugin(hook, output string) []byte {
+	const inputAddr, outputAddr = 1024, 32768
+	section := func(id byte, items ...[]byte) []byte {
+		content := uleb(uint64(len(items)))
+		for _, item := range items {
+			content = append(content, item...)
+		}
+		return append(append([]byte{id}, uleb(uint64(len(content)))...), content...)
+	}
+	name := func(s string) []byte { return append(uleb(uint64(len(s))), s...) }
+	body := func(expr ...byte) []byte {
+		code := append([]byte{0x00}, append(expr, 0x0b)...) // no locals
+		return append(uleb(uint64(len(code))), code...)
+	}
+
+	m := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	m = append(m, section(1,
+		[]byte{0x60, 0x01, 0x7f, 0x01, 0x7f},       // malloc: (i32) -> i32
+		[]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e}, // hook: (i32, i32) -> i64
+	)...)
+	m = append(m, section(3, []byte{0x00}, []byte{0x01})...)
+	m = append(m, section(5, []byte{0x00, 0x01})...)
+	m = append(m, section(7,
+		append(name("memory"), 0x02, 0x00),
+		append(name("malloc"), 0x00, 0x00),
+		append(name(hook), 0x00, 0x01),
+	)...)
+	m = append(m, section(10,
+		body(append([]byte{0x41}, sleb(inputAddr)...)...),
+		body(append([]byte{0x42}, sleb(outputAddr<<32|int64(len(output)))...)...),
+	)...)
+	segment := append([]byte{0x00, 0x41}, sleb(outputAddr)...)
+	segment = append(append(segment, 0x0b), name(output)...)
+	return append(m, section(11, segment)...)
+}
+
+func uleb(v uint64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func sleb(v int64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	write := func(name string, code []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, code, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	redact := write("redact.wasm", testPlugin("on_request", `{"args":{"query":"[redacted]"}}`))
+	deny := write("deny.wasm", testPlugin("on_request", `{"deny":"deployments are frozen"}`))
+	rewrite := write("rewrite.wasm", testPlugin("on_response", `{"result":{"content":[{"type":"text","text":"hi"}]}}`))
+
+	r, err := Load(ctx, []string{redact, rewrite}, 0)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defer r.Close(ctx)
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"redact", "rewrite"}) {
+		t.Errorf("Names() = %v", got)
+	}
+
+	args, err := r.OnRequest(ctx, wasm.Call{Tool: "search__query", Args: map[string]any{"query": "secret"}})
+	if err != nil {
+		t.Fatalf("OnRequest() error = %v", err)
+	}
+	if args["query"] != "[redacted]" {
+		t.Errorf("OnRequest() args = %v, want the query to be redacted", args)
+	}
+	result, err := r.OnResponse(ctx, wasm.Call{Tool: "search__query", Result: []byte(`{"content":[]}`)})
+	if err != nil {
+		t.Fatalf("OnResponse() error = %v", err)
+	}
+	if string(result) != `{"content":[{"type":"text","text":"hi"}]}` {
+		t.Errorf("OnResponse() result = %s", result)
+	}
+
+	r2, err := Load(ctx, []string{deny}, 0)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defer r2.Close(ctx)
+	if _, err := r2.OnRequest(ctx, wasm.Call{Tool: "ci__deploy"}); !errors.Is(err, wasm.ErrDenied) {
+		t.Errorf("OnRequest() error = %v, want the call to be denied", err)
+	}
+
+	invalid := write("invalid.wasm", testPlugin("on_call", `{}`))
+	if _, err := Load(ctx, []string{invalid}, 0); err == nil {
+		t.Error("Load() of a module without hooks succeeded, want error")
+	}
+}
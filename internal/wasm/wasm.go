@@ -0,0 +1,72 @@
+// Package wasm runs WebAssembly plugins in the tool call pipeline of mcpjungle.
+// Plugins are sandboxed: they have no access to the file system or the network, their memory is limited
+// and every hook must complete within a timeout.
+//
+// # ABI
+//
+// A plugin is a WebAssembly module that exports its linear memory as "memory", an allocation function
+// "malloc(size i32) i32" and at least one of the hooks:
+//
+//	on_request(ptr i32, len i32) i64   called before a tool is called
+//	on_response(ptr i32, len i32) i64  called with the result of a successful tool call
+//
+// mcpjungle writes a JSON object describing the call (see Call) to memory allocated with malloc
+// and passes its location to the hook. The hook returns 0 to leave the call unchanged, or the location
+// of a JSON object (see Output) packed as ptr<<32 | len.
+// Plugins can log messages with the imported function "mcpjungle"."log"(ptr i32, len i32).
+// WASI is available, so modules built by toolchains that require it (eg- TinyGo, Rust) can be loaded,
+// reactor modules are initialized by calling their "_initialize" export.
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultTimeout is the time a hook has to complete if no other timeout is configured
+const DefaultTimeout = time.Second
+
+// ErrDenied is returned (wrapped) when a plugin denies a tool call.
+var ErrDenied = errors.New("denied by WASM plugin")
+
+// Call is the input of the hooks.
+type Call struct {
+	Tool   string         `json:"tool"`
+	Client string         `json:"client,omitempty"`
+	User   string         `json:"user,omitempty"`
+	Args   map[string]any `json:"args"`
+
+	// Result is the result of the call in the MCP format, it is only passed to on_response
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// Output is the JSON object that a hook returns to change a call.
+type Output struct {
+	// Args replace the arguments of the call (on_request)
+	Args map[string]any `json:"args,omitempty"`
+
+	// Result replaces the result of the call (on_response)
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Deny fails the call with the given reason
+	Deny string `json:"deny,omitempty"`
+}
+
+// Plugins runs the hooks of loaded plugins. It is implemented by the runtime in the engine package,
+// which is kept apart so that packages that only need these types don't depend on the WebAssembly runtime.
+type Plugins interface {
+	// Names returns the names of the loaded plugins, in the order their hooks run.
+	Names() []string
+
+	// OnRequest runs the on_request hooks and returns the arguments that the tool must be called with.
+	OnRequest(ctx context.Context, call Call) (map[string]any, error)
+
+	// OnResponse runs the on_response hooks and returns the new result of the call,
+	// or nil if no plugin changed it.
+	OnResponse(ctx context.Context, call Call) (json.RawMessage, error)
+
+	// Close releases the resources of all plugins.
+	Close(ctx context.Context) error
+}
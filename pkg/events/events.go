@@ -0,0 +1,168 @@
+// Package events provides the event bus of mcpjungle, through which plugins compiled into the mcpjungle
+// binary observe what happens in the gateway (eg- for billing or bespoke logging) and veto tool calls
+// (eg- for custom authorization).
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of an event.
+type Type string
+
+const (
+	// ServerRegistered and ServerDeregistered are published when an MCP server is added to or removed from the registry
+	ServerRegistered   Type = "server.registered"
+	ServerDeregistered Type = "server.deregistered"
+
+	// ServerHealthChanged is published when mcpjungle fails to connect to an MCP server,
+	// and when it connects to it successfully again afterwards
+	ServerHealthChanged Type = "server.health_changed"
+
+	// ToolCall is checked by the guards before a tool is called, it is never published
+	ToolCall Type = "tool.call"
+
+	// ToolInvoked is published after a tool call has completed, successfully or not
+	ToolInvoked Type = "tool.invoked"
+
+	// PolicyDenied is published when a tool call is denied by a policy
+	PolicyDenied Type = "policy.denied"
+
+	// ToolSchemaDrifted is published when an MCP server changes the input schema of a tool incompatibly,
+	// Error lists the changes
+	ToolSchemaDrifted Type = "tool.schema_drifted"
+)
+
+// ErrRejected is returned (wrapped) when a guard rejects a tool call.
+var ErrRejected = errors.New("rejected by plugin")
+
+// Event describes something that happened in the gateway.
+// Only the fields relevant to the event's type are set.
+type Event struct {
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+
+	Server string `json:"server,omitempty"`
+	Tool   string `json:"tool,omitempty"`
+
+	// Client and User identify the caller of a tool
+	Client string `json:"client,omitempty"`
+	User   string `json:"user,omitempty"`
+
+	// Args are the arguments of a tool call, they are only set for ToolCall
+	Args map[string]any `json:"args,omitempty"`
+
+	// IsError, DurationMs and Cost describe the outcome of a ToolInvoked call
+	IsError    bool    `json:"is_error,omitempty"`
+	DurationMs int64   `json:"duration_ms,omitempty"`
+	Cost       float64 `json:"cost,omitempty"`
+
+	// Healthy is the new health of the server of a ServerHealthChanged event
+	Healthy bool `json:"healthy,omitempty"`
+
+	// Error describes why a tool call was denied, why a server is unhealthy or how the schema of a tool drifted
+	Error string `json:"error,omitempty"`
+}
+
+// Handler is called for every event published on the bus that it is subscribed to.
+// Handlers are called synchronously by the publisher, so they must return quickly
+// and hand slow work (eg- network calls) over to another goroutine.
+type Handler func(ctx context.Context, e Event)
+
+// Guard is called before a tool is called with a ToolCall event.
+// Returning an error rejects the call, the error is reported to the caller.
+type Guard func(ctx context.Context, e Event) error
+
+// Bus dispatches events to the handlers subscribed to them.
+// A nil *Bus is valid and discards all events.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	guards        []Guard
+}
+
+type subscription struct {
+	// types is nil if the handler is subscribed to all events
+	types   map[Type]bool
+	handler Handler
+}
+
+// NewBus creates an event bus without any subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe makes the handler receive the events of the given types, or all events if no type is given.
+func (b *Bus) Subscribe(h Handler, types ...Type) {
+	s := subscription{handler: h}
+	if len(types) > 0 {
+		s.types = make(map[Type]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, s)
+}
+
+// AddGuard adds a guard that can reject tool calls.
+func (b *Bus) AddGuard(g Guard) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.guards = append(b.guards, g)
+}
+
+// Publish delivers an event to the handlers subscribed to its type.
+// A handler that panics is logged and does not affect the publisher or the other handlers.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	for _, s := range subscriptions {
+		if s.types != nil && !s.types[e.Type] {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[ERROR] event handler panicked while handling %s event: %v", e.Type, r)
+				}
+			}()
+			s.handler(ctx, e)
+		}()
+	}
+}
+
+// Check runs the guards against an event, in the order they were added.
+// It returns an error wrapping ErrRejected as soon as a guard rejects the event.
+func (b *Bus) Check(ctx context.Context, e Event) error {
+	if b == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b.mu.RLock()
+	guards := b.guards
+	b.mu.RUnlock()
+
+	for _, g := range guards {
+		if err := g(ctx, e); err != nil {
+			return fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Plugin extends mcpjungle with custom hooks.
+// Plugins are compiled into the mcpjungle binary: a plugin package calls Register from its init function
+// and is imported (for its side effects) by a main package that runs mcpjungle's cmd.Execute.
+type Plugin interface {
+	// Name uniquely identifies the plugin
+	Name() string
+
+	// Init is called when the mcpjungle server starts, the plugin subscribes its handlers & guards to the bus.
+	// The server does not start if Init returns an error.
+	Init(bus *Bus) error
+}
+
+var plugins = struct {
+	sync.Mutex
+	byName map[string]Plugin
+}{byName: make(map[string]Plugin)}
+
+// Register makes a plugin available to the mcpjungle server.
+// It panics if a plugin with the same name is already registered.
+func Register(p Plugin) {
+	plugins.Lock()
+	defer plugins.Unlock()
+	if _, ok := plugins.byName[p.Name()]; ok {
+		panic(fmt.Sprintf("events: plugin %s is registered twice", p.Name()))
+	}
+	plugins.byName[p.Name()] = p
+}
+
+// Plugins returns the registered plugins, sorted by name.
+func Plugins() []Plugin {
+	plugins.Lock()
+	defer plugins.Unlock()
+	list := make([]Plugin, 0, len(plugins.byName))
+	for _, p := range plugins.byName {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// InitPlugins initializes all registered plugins with the bus.
+func InitPlugins(bus *Bus) error {
+	for _, p := range Plugins() {
+		if err := p.Init(bus); err != nil {
+			return fmt.Errorf("failed to initialize plugin %s: %w", p.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package mcptest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/feature"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp_client"
+	"github.com/mcpjungle/mcpjungle/internal/service/notification"
+	"github.com/mcpjungle/mcpjungle/internal/service/policy"
+	"github.com/mcpjungle/mcpjungle/internal/service/retention"
+	"github.com/mcpjungle/mcpjungle/internal/service/usage"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+)
+
+// Gateway is a complete mcpjungle server running in-process in development mode, backed by an in-memory database.
+type Gateway struct {
+	// URL is the base URL of the gateway, its MCP proxy is served on URL + "/mcp"
+	URL string
+
+	// Client is an API client connected to the gateway
+	Client *client.Client
+
+	httpServer *httptest.Server
+}
+
+// NewGateway starts an mcpjungle gateway for a test.
+// The gateway is closed and its data discarded when the test completes.
+func NewGateway(t testing.TB) *Gateway {
+	t.Helper()
+	dbConn, err := db.NewInMemoryDBConnection()
+	if err != nil {
+		t.Fatalf("mcptest: %v", err)
+	}
+	if err := migrations.Migrate(dbConn); err != nil {
+		t.Fatalf("mcptest: failed to run migrations: %v", err)
+	}
+
+	proxyHooks := &server.Hooks{}
+	mcpProxyServer := server.NewMCPServer(
+		"MCPJungle Proxy MCP Server",
+		"0.0.1",
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+		server.WithHooks(proxyHooks),
+	)
+	sessionManager := mcp.NewSessionManager(0, 0, mcpProxyServer)
+	sessionManager.RegisterHooks(proxyHooks)
+
+	webhookService := webhook.NewWebhookService(dbConn)
+	notificationService := notification.NewNotificationService(dbConn)
+	usageService := usage.NewUsageService(dbConn, webhookService, notificationService)
+	usageService.RegisterHooks(proxyHooks)
+	policyService := policy.NewPolicyService(dbConn)
+	featureService, err := feature.NewFeatureService(dbConn, "")
+	if err != nil {
+		t.Fatalf("mcptest: %v", err)
+	}
+	mcpService, err := mcp.NewMCPService(
+		dbConn, mcpProxyServer, proxyHooks, usageService, policyService, featureService, notificationService,
+	)
+	if err != nil {
+		t.Fatalf("mcptest: failed to create MCP service: %v", err)
+	}
+	mcpService.SetSessionManager(sessionManager)
+	if err := mcpService.WarmUp(context.Background(), 1); err != nil {
+		t.Fatalf("mcptest: %v", err)
+	}
+
+	retentionService, err := retention.NewRetentionService(dbConn, nil)
+	if err != nil {
+		t.Fatalf("mcptest: %v", err)
+	}
+
+	s, err := api.NewServer(&api.ServerOptions{
+		MCPProxyServer:      mcpProxyServer,
+		SessionManager:      sessionManager,
+		MCPService:          mcpService,
+		MCPClientService:    mcp_client.NewMCPClientService(dbConn),
+		ConfigService:       config.NewServerConfigService(dbConn),
+		UserService:         user.NewUserService(dbConn),
+		UsageService:        usageService,
+		PolicyService:       policyService,
+		WebhookService:      webhookService,
+		FeatureService:      featureService,
+		NotificationService: notificationService,
+		RetentionService:    retentionService,
+	})
+	if err != nil {
+		t.Fatalf("mcptest: failed to create server: %v", err)
+	}
+	if err := s.InitDev(); err != nil {
+		t.Fatalf("mcptest: %v", err)
+	}
+
+	g := &Gateway{httpServer: httptest.NewServer(s.Handler())}
+	g.URL = g.httpServer.URL
+	g.Client = client.NewClient(g.URL, "", http.DefaultClient)
+	t.Cleanup(g.Close)
+	return g
+}
+
+// Register registers fake MCP servers in the gateway.
+func (g *Gateway) Register(t testing.TB, servers ...*Server) {
+	t.Helper()
+	for _, s := range servers {
+		if _, err := g.Client.RegisterServer(s.RegisterInput()); err != nil {
+			t.Fatalf("mcptest: failed to register MCP server %s: %v", s.Name, err)
+		}
+	}
+}
+
+// Close shuts the gateway down.
+func (g *Gateway) Close() {
+	g.httpServer.Close()
+}
@@ -0,0 +1,128 @@
+package mcptest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestGatewayInvokesFakeServerTools(t *testing.T) {
+	upstream := NewServer(t, "fake",
+		Tool{Name: "echo", Description: "Returns its arguments"},
+		Tool{Name: "broken", Fail: true},
+	)
+	g := NewGateway(t)
+	g.Register(t, upstream)
+
+	tools, err := g.Client.ListTools("fake")
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("got %d tools, want 2", len(tools))
+	}
+
+	res, err := g.Client.InvokeTool("fake__echo", map[string]any{"msg": "hi"})
+	if err != nil {
+		t.Fatalf("InvokeTool() error = %v", err)
+	}
+	if res.IsError || len(res.Content) != 1 || res.Content[0]["text"] != `{"msg":"hi"}` {
+		t.Errorf("InvokeTool(fake__echo) = %+v, want the arguments echoed back", res)
+	}
+	if res, err := g.Client.InvokeTool("fake__broken", nil); err != nil || !res.IsError {
+		t.Errorf("InvokeTool(fake__broken) = %+v, %v, want a tool error", res, err)
+	}
+
+	// the same tools are available through the MCP proxy
+	c, err := client.NewStreamableHttpClient(g.URL + "/mcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx := context.Background()
+	if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "fake__echo"
+	if resp, err := c.CallTool(ctx, req); err != nil || resp.IsError {
+		t.Errorf("CallTool() via proxy = %+v, %v", resp, err)
+	}
+
+	if n := upstream.Calls("echo"); n != 2 {
+		t.Errorf("Calls(echo) = %d, want 2", n)
+	}
+}
+
+func TestProxyDoesNotAdvertiseOutputSchemas(t *testing.T) {
+	upstream := NewServer(t, "weather", Tool{Name: "forecast", Result: `{"temperature": 21}`})
+	g := NewGateway(t)
+	g.Register(t, upstream)
+	err := g.Client.SetToolOutputSchema(&types.SetToolOutputSchemaInput{
+		Name:         "weather__forecast",
+		OutputSchema: []byte(`{"type": "object", "required": ["temperature"]}`),
+	})
+	if err != nil {
+		t.Fatalf("SetToolOutputSchema() error = %v", err)
+	}
+
+	// the API returns the JSON text result as structured content
+	res, err := g.Client.InvokeTool("weather__forecast", nil)
+	if err != nil {
+		t.Fatalf("InvokeTool() error = %v", err)
+	}
+	if sc, ok := res.StructuredContent.(map[string]any); !ok || sc["temperature"] != float64(21) {
+		t.Errorf("InvokeTool() structured content = %v, want the JSON text result", res.StructuredContent)
+	}
+
+	// the proxy can't send structured content, so it must not declare an output schema either
+	var sessionID string
+	rpc := func(method string, params any) string {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+		req, _ := http.NewRequest(http.MethodPost, g.URL+"/mcp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		defer resp.Body.Close()
+		if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+			sessionID = id
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", method, resp.StatusCode, raw)
+		}
+		return string(raw)
+	}
+	rpc("initialize", map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"clientInfo":      map[string]any{"name": "test", "version": "1"},
+		"capabilities":    map[string]any{},
+	})
+
+	list := rpc("tools/list", map[string]any{})
+	if !strings.Contains(list, `"weather__forecast"`) {
+		t.Fatalf("tools/list does not contain the tool: %s", list)
+	}
+	if strings.Contains(list, "outputSchema") {
+		t.Errorf("tools/list advertises an output schema: %s", list)
+	}
+	call := rpc("tools/call", map[string]any{"name": "weather__forecast", "arguments": map[string]any{}})
+	if !strings.Contains(call, `{\"temperature\": 21}`) {
+		t.Errorf("tools/call did not return the tool's result: %s", call)
+	}
+}
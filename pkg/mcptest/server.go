@@ -0,0 +1,129 @@
+// Package mcptest provides helpers for testing code that uses mcpjungle without external binaries:
+// an in-process fake MCP server with configurable tools, and a gateway fixture that runs
+// a complete mcpjungle server in memory.
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Tool describes a tool provided by a fake MCP server.
+type Tool struct {
+	Name        string
+	Description string
+
+	// Result is the text returned by the tool.
+	// If it is empty, the tool returns its arguments as JSON.
+	Result string
+
+	// Handler, if set, handles calls to the tool instead of returning Result.
+	// Latency and failures are still applied.
+	Handler server.ToolHandlerFunc
+
+	// Latency delays every call to the tool
+	Latency time.Duration
+
+	// FailFirst makes the first n calls to the tool return a tool error, eg- to test retries
+	FailFirst int
+
+	// Fail makes every call to the tool return a tool error
+	Fail bool
+
+	// ReadOnly annotates the tool as read-only, which makes it safe to retry and hedge
+	ReadOnly bool
+}
+
+// Server is a fake MCP server that runs in-process over the streamable HTTP transport.
+type Server struct {
+	Name string
+
+	// URL is the MCP endpoint of the server
+	URL string
+
+	httpServer *httptest.Server
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// NewServer starts a fake MCP server that provides the given tools.
+// The server is closed when the test completes.
+func NewServer(t testing.TB, name string, tools ...Tool) *Server {
+	t.Helper()
+	s := &Server{Name: name, calls: make(map[string]int)}
+
+	mcpServer := server.NewMCPServer(name, "0.0.1", server.WithToolCapabilities(true))
+	for _, tool := range tools {
+		opts := []mcp.ToolOption{mcp.WithDescription(tool.Description)}
+		if tool.ReadOnly {
+			opts = append(opts, mcp.WithReadOnlyHintAnnotation(true))
+		}
+		mcpServer.AddTool(mcp.NewTool(tool.Name, opts...), s.handler(tool))
+	}
+	s.httpServer = server.NewTestStreamableHTTPServer(mcpServer)
+	s.URL = s.httpServer.URL + "/mcp"
+	t.Cleanup(s.Close)
+	return s
+}
+
+// handler returns the function that serves calls to a tool, applying its latency and failures.
+func (s *Server) handler(tool Tool) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.mu.Lock()
+		s.calls[tool.Name]++
+		n := s.calls[tool.Name]
+		s.mu.Unlock()
+
+		if tool.Latency > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(tool.Latency):
+			}
+		}
+		if tool.Fail || n <= tool.FailFirst {
+			return mcp.NewToolResultError("tool " + tool.Name + " failed"), nil
+		}
+		if tool.Handler != nil {
+			return tool.Handler(ctx, req)
+		}
+		if tool.Result != "" {
+			return mcp.NewToolResultText(tool.Result), nil
+		}
+		args, err := json.Marshal(req.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(args)), nil
+	}
+}
+
+// Calls returns the number of calls the server has received for a tool.
+func (s *Server) Calls(tool string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[tool]
+}
+
+// RegisterInput returns the input for registering the server in mcpjungle.
+func (s *Server) RegisterInput() *types.RegisterServerInput {
+	return &types.RegisterServerInput{
+		Name:      s.Name,
+		Transport: string(types.TransportStreamableHTTP),
+		URL:       s.URL,
+	}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
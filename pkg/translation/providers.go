@@ -0,0 +1,114 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// libreTranslate uses the API of LibreTranslate (https://libretranslate.com), which can be self-hosted
+type libreTranslate struct {
+	url    string
+	apiKey string
+}
+
+func newLibreTranslate(cfg Config) (Provider, error) {
+	url := cfg.URL
+	if url == "" {
+		url = "https://libretranslate.com"
+	}
+	return &libreTranslate{url: strings.TrimSuffix(url, "/"), apiKey: cfg.APIKey}, nil
+}
+
+func (l *libreTranslate) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	req := map[string]any{"q": texts, "source": "auto", "target": target, "format": "text"}
+	if l.apiKey != "" {
+		req["api_key"] = l.apiKey
+	}
+	var resp struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := postJSON(ctx, l.url+"/translate", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(resp.TranslatedText))
+	}
+	return resp.TranslatedText, nil
+}
+
+// deepL uses the API of DeepL (https://www.deepl.com)
+type deepL struct {
+	url    string
+	apiKey string
+}
+
+func newDeepL(cfg Config) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("the deepl translation provider requires an API key")
+	}
+	url := cfg.URL
+	if url == "" {
+		url = "https://api.deepl.com"
+		// keys of the free plan end with ":fx" and are only valid for the free API
+		if strings.HasSuffix(cfg.APIKey, ":fx") {
+			url = "https://api-free.deepl.com"
+		}
+	}
+	return &deepL{url: strings.TrimSuffix(url, "/"), apiKey: cfg.APIKey}, nil
+}
+
+func (d *deepL) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	req := map[string]any{"text": texts, "target_lang": strings.ToUpper(target)}
+	header := http.Header{"Authorization": []string{"DeepL-Auth-Key " + d.apiKey}}
+	var resp struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := postJSON(ctx, d.url+"/v2/translate", header, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(resp.Translations))
+	}
+	translated := make([]string, len(resp.Translations))
+	for i, t := range resp.Translations {
+		translated[i] = t.Text
+	}
+	return translated, nil
+}
+
+// postJSON sends a JSON request to a provider's API and decodes its JSON response into out
+func postJSON(ctx context.Context, url string, header http.Header, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to serialize translation request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create translation request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send translation request to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("translation request failed with status: %d, message: %s", resp.StatusCode, b)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode translation response: %w", err)
+	}
+	return nil
+}
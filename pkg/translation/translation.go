@@ -0,0 +1,83 @@
+// Package translation translates the text results of tool calls into the language preferred by an MCP client,
+// for agents that work in another language than the upstream tools.
+//
+// Translation providers are pluggable: the built-in providers (libretranslate & deepl) are registered by this
+// package, custom builds of mcpjungle can register their own with Register (see the events package for how
+// plugins are compiled in).
+package translation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Provider translates texts.
+type Provider interface {
+	// Translate translates the texts into the target language (eg- "fr") and returns them in the same order.
+	// The source language is detected by the provider.
+	Translate(ctx context.Context, texts []string, target string) ([]string, error)
+}
+
+// Config configures a translation provider
+type Config struct {
+	// URL is the base URL of the provider's API, providers use their public API if it is empty
+	URL string
+
+	APIKey string
+}
+
+// Factory creates a provider from its configuration
+type Factory func(cfg Config) (Provider, error)
+
+// requestTimeout is the maximum time the built-in providers wait for a translation
+const requestTimeout = 10 * time.Second
+
+var providers = struct {
+	sync.Mutex
+	byName map[string]Factory
+}{byName: make(map[string]Factory)}
+
+func init() {
+	Register("libretranslate", newLibreTranslate)
+	Register("deepl", newDeepL)
+}
+
+// Register makes a translation provider available under the given name.
+// It panics if a provider with the same name is already registered.
+func Register(name string, f Factory) {
+	providers.Lock()
+	defer providers.Unlock()
+	if _, ok := providers.byName[name]; ok {
+		panic(fmt.Sprintf("translation: provider %s is registered twice", name))
+	}
+	providers.byName[name] = f
+}
+
+// Providers returns the names of the registered providers, sorted.
+func Providers() []string {
+	providers.Lock()
+	defer providers.Unlock()
+	names := make([]string, 0, len(providers.byName))
+	for name := range providers.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the provider registered under the given name.
+func New(name string, cfg Config) (Provider, error) {
+	providers.Lock()
+	f, ok := providers.byName[name]
+	providers.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown translation provider '%s', must be one of %v", name, Providers())
+	}
+	return f(cfg)
+}
+
+var httpClient = &http.Client{Timeout: requestTimeout}
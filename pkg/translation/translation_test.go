@@ -0,0 +1,50 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLibreTranslate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Q      []string `json:"q"`
+			Target string   `json:"target"`
+			APIKey string   `json:"api_key"`
+		}
+		if r.URL.Path != "/translate" || json.NewDecoder(r.Body).Decode(&req) != nil || req.APIKey != "secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		translated := make([]string, len(req.Q))
+		for i, q := range req.Q {
+			translated[i] = req.Target + ":" + strings.ToUpper(q)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"translatedText": translated})
+	}))
+	defer srv.Close()
+
+	p, err := New("libretranslate", Config{URL: srv.URL + "/", APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	got, err := p.Translate(context.Background(), []string{"hello", "world"}, "fr")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if want := []string{"fr:HELLO", "fr:WORLD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Translate() = %v, want %v", got, want)
+	}
+
+	if _, err := New("babelfish", Config{}); err == nil {
+		t.Error("New() for an unknown provider succeeded, want error")
+	}
+	if _, err := New("deepl", Config{}); err == nil {
+		t.Error("New() for deepl without an API key succeeded, want error")
+	}
+}
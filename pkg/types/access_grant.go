@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// AccessGrant temporarily allows an MCP client to call the tools of a tool group.
+type AccessGrant struct {
+	ID        uint      `json:"ID"`
+	Client    string    `json:"client"`
+	ToolGroup string    `json:"tool_group"`
+	ExpiresAt time.Time `json:"expires_at"`
+	GrantedBy string    `json:"granted_by,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// CreateAccessGrantInput is the input for granting an MCP client temporary access to a tool group.
+type CreateAccessGrantInput struct {
+	Client    string `json:"client"`
+	ToolGroup string `json:"tool_group"`
+
+	// Duration is how long the grant lasts (eg- 2h), at most 7 days
+	Duration string `json:"duration"`
+	Reason   string `json:"reason,omitempty"`
+}
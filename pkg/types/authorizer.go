@@ -0,0 +1,22 @@
+package types
+
+// AuthorizationRequest is POSTed to the external authorizer before every tool call.
+type AuthorizationRequest struct {
+	Tool   string `json:"tool"`
+	Server string `json:"server"`
+
+	// Client and User identify the caller, ImpersonatedBy is set if an admin impersonates them
+	Client         string `json:"client,omitempty"`
+	User           string `json:"user,omitempty"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+
+	Args map[string]any `json:"args"`
+}
+
+// AuthorizationResponse is the decision of the external authorizer about a tool call.
+type AuthorizationResponse struct {
+	Allow bool `json:"allow"`
+
+	// Reason explains why the call is denied, it is reported to the caller
+	Reason string `json:"reason,omitempty"`
+}
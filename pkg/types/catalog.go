@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// CatalogSnapshot is the catalog of a gateway at a point in time: its MCP servers and their tools.
+// Snapshots are exported to files to compare environments (see CatalogDiff).
+type CatalogSnapshot struct {
+	// Registry is the URL of the gateway that the snapshot was taken from
+	Registry string       `json:"registry"`
+	TakenAt  time.Time    `json:"taken_at"`
+	Servers  []*McpServer `json:"servers"`
+	Tools    []*Tool      `json:"tools"`
+}
+
+// CatalogChange describes how an entity present in both catalogs differs between them.
+type CatalogChange struct {
+	Name string `json:"name"`
+
+	// Changes are human-readable descriptions of the differences, eg- "enabled: true -> false"
+	Changes []string `json:"changes"`
+}
+
+// CatalogDiff is the difference between two catalogs, from one to the other.
+type CatalogDiff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	ServersOnlyInFrom []string        `json:"servers_only_in_from"`
+	ServersOnlyInTo   []string        `json:"servers_only_in_to"`
+	ServersChanged    []CatalogChange `json:"servers_changed"`
+
+	ToolsOnlyInFrom []string        `json:"tools_only_in_from"`
+	ToolsOnlyInTo   []string        `json:"tools_only_in_to"`
+	ToolsChanged    []CatalogChange `json:"tools_changed"`
+}
+
+// Empty returns true if both catalogs are the same.
+func (d *CatalogDiff) Empty() bool {
+	return len(d.ServersOnlyInFrom) == 0 && len(d.ServersOnlyInTo) == 0 && len(d.ServersChanged) == 0 &&
+		len(d.ToolsOnlyInFrom) == 0 && len(d.ToolsOnlyInTo) == 0 && len(d.ToolsChanged) == 0
+}
+
+// CatalogSnapshotJWSType is the typ header of the signature of a published catalog snapshot.
+// The signature is a JWS with a detached payload: the exact bytes of the published snapshot.
+const CatalogSnapshotJWSType = "mcpjungle-catalog+json"
@@ -0,0 +1,36 @@
+package types
+
+import "fmt"
+
+// VersionConflict is returned (with status 409 Conflict) when a change is based on a version of a record
+// that is no longer current, because the record was modified by someone else in the meantime.
+type VersionConflict struct {
+	Message string `json:"error"`
+
+	// Kind is the type of the record (tool, server or client) and Name identifies it
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// ExpectedVersion is the version the change was based on, CurrentVersion is the version in the registry
+	ExpectedVersion int `json:"expected_version"`
+	CurrentVersion  int `json:"current_version"`
+
+	// Diff contains the current and requested values of the fields that the change sets
+	Diff map[string]FieldDiff `json:"diff,omitempty"`
+}
+
+// FieldDiff describes a field of a record that a conflicting change attempted to set.
+type FieldDiff struct {
+	Current   any `json:"current"`
+	Requested any `json:"requested"`
+}
+
+func (c *VersionConflict) Error() string {
+	if c.Message != "" {
+		return c.Message
+	}
+	return fmt.Sprintf(
+		"%s %s was modified concurrently: it is at version %d, but the change was based on version %d",
+		c.Kind, c.Name, c.CurrentVersion, c.ExpectedVersion,
+	)
+}
@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// EmergencyStatus describes the break-glass switch that disables all tool calls during an incident.
+type EmergencyStatus struct {
+	// Active is true while tool calls are disabled
+	Active bool `json:"active"`
+
+	// AllowedTools contains the tools (canonical names) and MCP servers that can still be called
+	AllowedTools []string   `json:"allowed_tools,omitempty"`
+	Reason       string     `json:"reason,omitempty"`
+	ActivatedBy  string     `json:"activated_by,omitempty"`
+	ActivatedAt  *time.Time `json:"activated_at,omitempty"`
+}
+
+// EmergencyDisableInput is the input for disabling all tool calls.
+type EmergencyDisableInput struct {
+	// AllowedTools contains the tools (canonical names) and MCP servers that can still be called
+	AllowedTools []string `json:"allowed_tools,omitempty"`
+	Reason       string   `json:"reason,omitempty"`
+}
@@ -0,0 +1,20 @@
+package types
+
+// FaultRule injects a fault into a share of the calls made to a tool or to all tools of an MCP server.
+// Rules are only applied while the fault_injection feature is enabled.
+type FaultRule struct {
+	Name string `json:"name"`
+
+	// Exactly one of Tool & Server must be set
+	Tool   string `json:"tool,omitempty"`
+	Server string `json:"server,omitempty"`
+
+	// Fault is one of "latency", "error" or "drop"
+	Fault string `json:"fault"`
+
+	// Rate is the percentage of calls that the fault is injected into
+	Rate float64 `json:"rate"`
+
+	// LatencyMs is the delay added to calls by a latency fault
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+// Sources from which the state of a feature is determined, in order of increasing precedence
+const (
+	FeatureSourceDefault     = "default"
+	FeatureSourceDatabase    = "database"
+	FeatureSourceEnvironment = "environment"
+)
+
+// Feature describes an experimental feature of mcpjungle that can be turned on or off per deployment
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+
+	// Source is where the state of the feature comes from, ie, its default, the database or the server's environment
+	Source string `json:"source"`
+}
+
+// SetFeatureInput is the input for turning a feature on or off
+type SetFeatureInput struct {
+	Name    string `json:"name" binding:"required"`
+	Enabled bool   `json:"enabled"`
+}
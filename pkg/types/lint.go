@@ -0,0 +1,14 @@
+package types
+
+// ToolLintIssue is a quality problem found in a tool's definition, eg- a missing description.
+type ToolLintIssue struct {
+	// Tool is the canonical name of the tool
+	Tool string `json:"tool"`
+
+	// Rule is the name of the lint rule that the tool fails
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+
+	// Blocked is true if the rule is enforced, ie, the tool is not exposed by the MCP proxy because of this issue
+	Blocked bool `json:"blocked,omitempty"`
+}
@@ -6,6 +6,33 @@ type McpClient struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 
+	// SpiffeID is the SPIFFE ID of the client's workload. If set, the client can authenticate
+	// with an X.509 SVID with this ID (mTLS) instead of its access token.
+	SpiffeID string `json:"spiffe_id,omitempty"`
+
 	// AllowList is a list of MCP Servers that this client is allowed to access from MCPJungle.
 	AllowList []string `json:"allow_list"`
+
+	// IPAllowList optionally restricts the networks (CIDRs or IP addresses) that the client can connect from.
+	IPAllowList []string `json:"ip_allow_list,omitempty"`
+
+	// IPDenyList contains the networks (CIDRs or IP addresses) that the client can never connect from.
+	IPDenyList []string `json:"ip_deny_list,omitempty"`
+
+	// Attributes contains key-value pairs that describe the client (eg- its team or environment).
+	// Prompt templates can refer to them as {{client.<key>}}.
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Language is the language (eg- "fr") that the text results of tool calls are translated into for this client
+	Language string `json:"language,omitempty"`
+
+	// ToolGroup is the tool group that the client is bound to, served to it on /mcp instead of all the tools
+	ToolGroup string `json:"tool_group,omitempty"`
+
+	// Version is incremented every time the client is modified
+	Version int `json:"version,omitempty"`
 }
+
+// ClientRegionAttribute is the attribute of an MCP client telling the region it runs in (eg- eu-west).
+// The client's calls to the tools of an MCP server with replicas are routed to the replica closest to that region.
+const ClientRegionAttribute = "region"
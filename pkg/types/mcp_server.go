@@ -1,6 +1,15 @@
 package types
 
-import "fmt"
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // McpServerTransport represents the transport protocol used by an MCP server.
 // All transport types supported by mcpjungle are defined in this file with this type.
@@ -9,21 +18,177 @@ type McpServerTransport string
 const (
 	TransportStdio          McpServerTransport = "stdio"
 	TransportStreamableHTTP McpServerTransport = "streamable_http"
+
+	// TransportBuiltin is used by the MCP servers that run inside mcpjungle itself.
+	// Such servers are enabled by configuring mcpjungle, they cannot be registered via the API.
+	TransportBuiltin McpServerTransport = "builtin"
+)
+
+// StdioFraming determines how mcpjungle handles output of a stdio MCP server that is not a JSON-RPC message.
+type StdioFraming string
+
+const (
+	// StdioFramingLenient drops noise (eg- log lines printed to stdout), strips byte order marks and
+	// joins messages split across lines. It is the default.
+	StdioFramingLenient StdioFraming = "lenient"
+
+	// StdioFramingStrict terminates the server's process as soon as it writes anything but a JSON-RPC message.
+	StdioFramingStrict StdioFraming = "strict"
+)
+
+// ValidateStdioFraming validates the input string and returns the corresponding StdioFraming.
+// An empty input stands for the default, lenient framing.
+func ValidateStdioFraming(input string) (StdioFraming, error) {
+	switch StdioFraming(input) {
+	case "", StdioFramingLenient:
+		return StdioFramingLenient, nil
+	case StdioFramingStrict:
+		return StdioFramingStrict, nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported stdio framing: %s (acceptable values: '%s', '%s')", input, StdioFramingLenient, StdioFramingStrict,
+		)
+	}
+}
+
+// StdioIntegrity pins the expected content of a file of a stdio MCP server (its executable, script or package),
+// so that mcpjungle refuses to start the server if the file was tampered with.
+// At least one of Checksum and Signature must be set.
+type StdioIntegrity struct {
+	// Path is the file that is verified. It defaults to the server's command, looked up in PATH.
+	// Relative paths are relative to the working directory of the server.
+	Path string `json:"path,omitempty"`
+
+	// Checksum is the expected digest of the file, in the form "sha256:<hex digest>"
+	Checksum string `json:"checksum,omitempty"`
+
+	// Signature is the base64-encoded Ed25519 signature of the file, verified with PublicKey
+	Signature string `json:"signature,omitempty"`
+
+	// PublicKey is the base64-encoded Ed25519 public key of the publisher of the file
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+// Validate checks that the integrity settings are well-formed.
+func (i *StdioIntegrity) Validate() error {
+	if i.Checksum == "" && i.Signature == "" {
+		return errors.New("integrity requires a checksum or a signature")
+	}
+	if i.Checksum != "" {
+		digest, ok := strings.CutPrefix(i.Checksum, "sha256:")
+		if b, err := hex.DecodeString(digest); !ok || err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("invalid checksum %s, must be sha256:<hex digest>", i.Checksum)
+		}
+	}
+	if i.Signature != "" {
+		if sig, err := base64.StdEncoding.DecodeString(i.Signature); err != nil || len(sig) != ed25519.SignatureSize {
+			return errors.New("invalid signature, must be a base64-encoded Ed25519 signature")
+		}
+		if key, err := base64.StdEncoding.DecodeString(i.PublicKey); err != nil || len(key) != ed25519.PublicKeySize {
+			return errors.New("a signature requires a base64-encoded Ed25519 public key")
+		}
+	}
+	return nil
+}
+
+// IdentityPinning determines what happens when an MCP server reports a different name or version than the one
+// recorded when it was registered (or last pinned), which may reveal that the server was swapped or tampered with.
+type IdentityPinning string
+
+const (
+	// IdentityPinningOff ignores changes of the server's identity. It is the default.
+	IdentityPinningOff IdentityPinning = "off"
+
+	// IdentityPinningAlert notifies admins of a change but keeps using the server.
+	IdentityPinningAlert IdentityPinning = "alert"
+
+	// IdentityPinningBlock notifies admins of a change and refuses to use the server until it is re-pinned.
+	IdentityPinningBlock IdentityPinning = "block"
 )
 
+// ValidateIdentityPinning validates the input string and returns the corresponding IdentityPinning.
+// An empty input stands for the default, off.
+func ValidateIdentityPinning(input string) (IdentityPinning, error) {
+	switch IdentityPinning(input) {
+	case "", IdentityPinningOff:
+		return IdentityPinningOff, nil
+	case IdentityPinningAlert, IdentityPinningBlock:
+		return IdentityPinning(input), nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported identity pinning: %s (acceptable values: '%s', '%s', '%s')",
+			input, IdentityPinningOff, IdentityPinningAlert, IdentityPinningBlock,
+		)
+	}
+}
+
 // McpServer represents an MCP server registered in the MCPJungle registry.
 type McpServer struct {
 	Name        string `json:"name"`
 	Transport   string `json:"transport"`
 	Description string `json:"description"`
 
+	// Region is the region tag of the server, used to enforce data residency policies
+	Region string `json:"region,omitempty"`
+
+	// ReplicaOf is the name of the server that this server is a replica of, see RegisterServerInput
+	ReplicaOf string `json:"replica_of,omitempty"`
+
 	URL string `json:"url"`
 
 	Command string            `json:"command"`
 	Args    []string          `json:"args"`
 	Env     map[string]string `json:"env"`
+
+	// KeepWarm is true if mcpjungle keeps a long-lived connection to this server
+	KeepWarm bool `json:"keep_warm,omitempty"`
+
+	// RetryPolicy describes how failed tool calls to this server are retried, if at all
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// ServerInfo is the implementation name & version that the server reported when it was registered
+	// or last pinned
+	ServerInfo *ServerInfo `json:"server_info,omitempty"`
+
+	// IdentityPinning determines what happens when the server reports a different identity than ServerInfo
+	IdentityPinning string `json:"identity_pinning,omitempty"`
+
+	// Group is the subsystem the server belongs to, see RegisterServerInput
+	Group string `json:"group,omitempty"`
+
+	// Stopped is true while the server is stopped, see ServerGroupLifecycleResult
+	Stopped bool `json:"stopped,omitempty"`
+
+	// Version is incremented every time the server is modified
+	Version int `json:"version,omitempty"`
+
+	// Status is the state of the server's long-lived connection (or process, for stdio servers)
+	Status ServerStatus `json:"status,omitempty"`
+
+	// Restarts is the number of times mcpjungle restarted the process of this stdio server after it crashed,
+	// since mcpjungle started
+	Restarts int `json:"restarts,omitempty"`
 }
 
+// ServerStatus is the state of the long-lived connection that mcpjungle keeps to an MCP server.
+type ServerStatus string
+
+const (
+	// ServerStatusRunning means that mcpjungle holds a warm connection to the server
+	ServerStatusRunning ServerStatus = "running"
+
+	// ServerStatusRestarting means that the process of a warm stdio server crashed and is being restarted.
+	// The server's tools are unavailable in the meantime.
+	ServerStatusRestarting ServerStatus = "restarting"
+
+	// ServerStatusIdle means that mcpjungle holds no connection to the server,
+	// it connects to it (or starts its process) for every tool call
+	ServerStatusIdle ServerStatus = "idle"
+
+	// ServerStatusStopped means that the server's group was stopped
+	ServerStatusStopped ServerStatus = "stopped"
+)
+
 // RegisterServerInput is the input structure for registering a new MCP server with mcpjungle.
 // It is also the basis for the JSON configuration file used to register a new MCP server.
 type RegisterServerInput struct {
@@ -36,6 +201,16 @@ type RegisterServerInput struct {
 
 	Description string `json:"description"`
 
+	// Region is an optional tag describing where the MCP server runs (eg- eu-west).
+	// It is used to enforce data residency policies.
+	Region string `json:"region,omitempty"`
+
+	// ReplicaOf is the name of an already registered MCP server that this server is a replica of,
+	// ie, the same MCP server deployed in another region. The replica requires a region.
+	// Its tools are not registered: calls to the tools of the primary server are routed to the replica
+	// in the region closest to the caller.
+	ReplicaOf string `json:"replica_of,omitempty"`
+
 	// URL is the URL of the remote mcp server
 	// It is mandatory when transport is streamable_http and must be a valid
 	//  http/https URL (e.g., https://example.com/mcp).
@@ -46,6 +221,11 @@ type RegisterServerInput struct {
 	// If the transport is "stdio", this field is ignored.
 	BearerToken string `json:"bearer_token"`
 
+	// SpiffeID is the SPIFFE ID of the remote MCP server's workload (eg- spiffe://example.org/ns/prod/sa/github-mcp).
+	// If set, mcpjungle connects to the server with mTLS using its own SPIFFE SVID, and only trusts an SVID with this ID.
+	// It requires mcpjungle to be started with an SVID (see SPIFFE_SVID_CERT_FILE).
+	SpiffeID string `json:"spiffe_id,omitempty"`
+
 	// Command is the command to run the mcp server.
 	// It is mandatory when the transport is "stdio".
 	Command string `json:"command"`
@@ -56,6 +236,85 @@ type RegisterServerInput struct {
 	// Env is the set of environment variables to pass to the mcp server when the transport is "stdio".
 	// Both the key and value must be of type string.
 	Env map[string]string `json:"env"`
+
+	// Framing is either "lenient" (default) or "strict", it determines how output of a stdio server
+	// that is not a JSON-RPC message is handled.
+	Framing string `json:"framing,omitempty"`
+
+	// Cwd is the absolute path of the working directory of a stdio server.
+	// By default, the server runs in the working directory of mcpjungle.
+	Cwd string `json:"cwd,omitempty"`
+
+	// RunAs is the user ("user" or "user:group", names or numeric IDs) that a stdio server runs as.
+	// It is only supported on Unix, and requires mcpjungle to run as root.
+	RunAs string `json:"run_as,omitempty"`
+
+	// Integrity optionally pins the checksum or signature of a file of a stdio server,
+	// which is verified every time before the server's process is started.
+	Integrity *StdioIntegrity `json:"integrity,omitempty"`
+
+	// KeepWarm makes mcpjungle connect to the server when it starts and re-use the connection for all tool calls.
+	// For stdio servers, this keeps the server's process running instead of starting it for every call.
+	KeepWarm bool `json:"keep_warm,omitempty"`
+
+	// RetryPolicy optionally enables retries of tool calls that fail with a transient error.
+	// By default, failed calls are not retried.
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// IdentityPinning is "off" (default), "alert" or "block". It determines what happens when the server
+	// later reports a different implementation name or version than it did at registration.
+	IdentityPinning string `json:"identity_pinning,omitempty"`
+
+	// Group optionally names the subsystem the server belongs to (eg- analytics).
+	// All the servers of a group can be stopped & started together, eg- for scheduled maintenance.
+	Group string `json:"group,omitempty"`
+}
+
+// ServerGroupLifecycleResult describes the servers of a group that were stopped or started.
+// Stopping a server disables its tools and tears down the connection to it (terminating the process of
+// a stdio server). Starting it re-enables the tools that were enabled when it was stopped.
+type ServerGroupLifecycleResult struct {
+	Group string `json:"group"`
+
+	// Servers contains the servers whose state changed, servers already in the requested state are skipped
+	Servers []string `json:"servers"`
+
+	// Tools contains the tools that were disabled or re-enabled
+	Tools []string `json:"tools"`
+}
+
+// PinServerInput is the input for re-pinning the identity of an MCP server.
+type PinServerInput struct {
+	// IdentityPinning optionally changes the pinning mode of the server, it is left unchanged if empty
+	IdentityPinning string `json:"identity_pinning,omitempty"`
+}
+
+// ServerPreflightResult describes what mcpjungle discovered while test-connecting to an MCP server
+// without registering it.
+type ServerPreflightResult struct {
+	// Tools contains the tools that the MCP server provides
+	Tools []*Tool `json:"tools"`
+}
+
+// ServerCapabilities describes what an MCP server negotiated with mcpjungle during initialization.
+type ServerCapabilities struct {
+	Name string `json:"name"`
+
+	// ProtocolVersion is the MCP protocol version that the server agreed to speak
+	ProtocolVersion string `json:"protocol_version"`
+
+	// ServerInfo is the server's self-reported implementation name & version
+	ServerInfo *ServerInfo `json:"server_info,omitempty"`
+
+	// Capabilities contains the capabilities advertised by the server, in the format defined by the MCP specification.
+	// eg- {"tools": {"listChanged": true}, "logging": {}}
+	Capabilities map[string]any `json:"capabilities"`
+}
+
+// ServerInfo describes the implementation of an MCP server.
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
 }
 
 // ValidateTransport validates the input string and returns the corresponding model.McpServerTransport.
@@ -74,3 +333,48 @@ func ValidateTransport(input string) (McpServerTransport, error) {
 		return "", fmt.Errorf("unsupported transport type: %s %s", input, errMsgExt)
 	}
 }
+
+// ServerHealthStatus is the health of an MCP server, or the aggregate health of all the MCP servers.
+type ServerHealthStatus string
+
+const (
+	ServerHealthy   ServerHealthStatus = "healthy"
+	ServerUnhealthy ServerHealthStatus = "unhealthy"
+	// ServerHealthUnknown is the status of the servers that mcpjungle hasn't connected to since it started
+	ServerHealthUnknown ServerHealthStatus = "unknown"
+	// ServerHealthStopped is the status of the servers whose group was stopped, they aren't checked
+	ServerHealthStopped ServerHealthStatus = "stopped"
+	// ServersDegraded is the aggregate status when some of the servers are unhealthy
+	ServersDegraded ServerHealthStatus = "degraded"
+)
+
+// ServerHealth is the health of an MCP server, as last observed by mcpjungle when connecting to it,
+// calling its tools or pinging its warm connection.
+type ServerHealth struct {
+	Name   string             `json:"name"`
+	Status ServerHealthStatus `json:"status"`
+
+	// LastCheck is when the health of the server was last observed, LastSuccess when it was last healthy
+	LastCheck   *time.Time `json:"last_check,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+
+	// ConsecutiveFailures is the number of failed connections since the server was last healthy
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastError is the error of the last failed connection to the server
+	LastError string `json:"last_error,omitempty"`
+
+	// LatencyMs is the duration of the last connection to the server, or of the last ping of its warm connection
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+}
+
+// ServersHealth is the aggregate health of all the MCP servers.
+// Its status is unhealthy if all the servers that were checked are unhealthy, degraded if some of them are.
+type ServersHealth struct {
+	Status    ServerHealthStatus `json:"status"`
+	Healthy   int                `json:"healthy"`
+	Unhealthy int                `json:"unhealthy"`
+	Unknown   int                `json:"unknown"`
+	Stopped   int                `json:"stopped"`
+	Servers   []*ServerHealth    `json:"servers"`
+}
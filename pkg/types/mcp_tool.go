@@ -1,5 +1,10 @@
 package types
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // ToolInputSchema defines the schema for the input parameters of a tool
 type ToolInputSchema struct {
 	Type       string         `json:"type"`
@@ -7,12 +12,75 @@ type ToolInputSchema struct {
 	Required   []string       `json:"required,omitempty"`
 }
 
+// ToolAnnotations contains optional hints describing a tool's behaviour, as supplied by its MCP server.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
 // Tool represents a tool provided by an MCP Server registered in the registry.
 type Tool struct {
-	Name        string          `json:"name"`
-	Enabled     bool            `json:"enabled"`
-	Description string          `json:"description"`
-	InputSchema ToolInputSchema `json:"input_schema"`
+	Name        string           `json:"name"`
+	Enabled     bool             `json:"enabled"`
+	Description string           `json:"description"`
+	InputSchema ToolInputSchema  `json:"input_schema"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+
+	// OutputSchema is the optional JSON schema of the tool's structured results
+	OutputSchema map[string]any `json:"output_schema,omitempty"`
+
+	// CostPerCall, CostPerUnit & CostUnitArg describe the pricing of the tool
+	CostPerCall float64 `json:"cost_per_call,omitempty"`
+	CostPerUnit float64 `json:"cost_per_unit,omitempty"`
+	CostUnitArg string  `json:"cost_unit_arg,omitempty"`
+
+	// HedgeDelayMs is the delay (in milliseconds) after which a hedged request is sent for a call, 0 if hedging is disabled
+	HedgeDelayMs int `json:"hedge_delay_ms,omitempty"`
+
+	// DescriptionOverride, DescriptionAppend & Examples enrich the description provided by the upstream server,
+	// see EnrichToolDescription
+	DescriptionOverride string   `json:"description_override,omitempty"`
+	DescriptionAppend   string   `json:"description_append,omitempty"`
+	Examples            []string `json:"examples,omitempty"`
+
+	// ArgsScript & ResultScript are the CEL scripts that transform the tool's arguments and results
+	ArgsScript   string `json:"args_script,omitempty"`
+	ResultScript string `json:"result_script,omitempty"`
+
+	// PayloadSampleRate & PayloadRedactions configure the logging of the tool's payloads, see SetToolPayloadLoggingInput
+	PayloadSampleRate float64  `json:"payload_sample_rate,omitempty"`
+	PayloadRedactions []string `json:"payload_redactions,omitempty"`
+
+	// Version is incremented every time the tool is modified
+	Version int `json:"version,omitempty"`
+}
+
+// EnrichedDescription returns the description of the tool that is served to MCP clients.
+func (t *Tool) EnrichedDescription() string {
+	return EnrichToolDescription(t.Description, t.DescriptionOverride, t.DescriptionAppend, t.Examples)
+}
+
+// EnrichToolDescription combines the description of a tool provided by its upstream server with the
+// documentation added by admins: the override replaces the description, the appendix is added after it
+// and the usage examples are listed at the end.
+func EnrichToolDescription(description, override, appendix string, examples []string) string {
+	parts := make([]string, 0, 3)
+	if override != "" {
+		description = override
+	}
+	if description != "" {
+		parts = append(parts, description)
+	}
+	if appendix != "" {
+		parts = append(parts, appendix)
+	}
+	if len(examples) > 0 {
+		parts = append(parts, "Examples:\n- "+strings.Join(examples, "\n- "))
+	}
+	return strings.Join(parts, "\n\n")
 }
 
 // ToolInvokeResult represents the result of a Tool call.
@@ -21,4 +89,108 @@ type ToolInvokeResult struct {
 	Meta    map[string]any   `json:"_meta,omitempty"`
 	IsError bool             `json:"isError,omitempty"`
 	Content []map[string]any `json:"content"`
+
+	// StructuredContent is the tool's result as a JSON object, if the tool declares an output schema
+	StructuredContent any `json:"structuredContent,omitempty"`
+}
+
+// The caller of a tool can bound the duration of the call with a timeout in milliseconds, in the TimeoutHeader
+// of the request or in the _meta of an MCP tools/call request. mcpjungle gives the upstream MCP server what is left
+// of it (minus the gateway's own overhead) and fails the call if the deadline passes.
+const (
+	TimeoutHeader  = "X-MCPJungle-Timeout-Ms"
+	TimeoutMetaKey = "mcpjungle/timeoutMs"
+)
+
+// ToolInvokeStreamContentType is the media type that a caller accepts to receive the result of a tool call
+// as a stream of newline-delimited ToolInvokeChunk objects instead of a single ToolInvokeResult.
+const ToolInvokeStreamContentType = "application/x-ndjson"
+
+// ToolInvokeChunk is a line of a streamed tool call result.
+// The content of the result comes first, in order. Large text content is split into several chunks
+// with the same Index, whose texts must be concatenated. The last chunk carries the rest of the result
+// (metadata, error flag and structured content) in Result, without its content.
+type ToolInvokeChunk struct {
+	// Index is the position of the content item in the result
+	Index   int               `json:"index"`
+	Content map[string]any    `json:"content,omitempty"`
+	Result  *ToolInvokeResult `json:"result,omitempty"`
+}
+
+// Text returns the text carried by the chunk, if it is part of a text content item.
+func (c *ToolInvokeChunk) Text() (string, bool) {
+	if c.Content == nil || c.Content["type"] != "text" {
+		return "", false
+	}
+	text, ok := c.Content["text"].(string)
+	return text, ok
+}
+
+// SetToolOutputSchemaInput is the input for setting the output schema of a tool.
+// An empty OutputSchema removes the tool's output schema.
+type SetToolOutputSchemaInput struct {
+	Name         string          `json:"name"`
+	OutputSchema json.RawMessage `json:"output_schema,omitempty"`
+
+	// Version is the version of the tool that the change is based on. If set, the change is rejected
+	// with a VersionConflict if the tool was modified since.
+	Version int `json:"version,omitempty"`
+}
+
+// SetToolHedgingInput is the input for configuring hedged requests for a tool.
+type SetToolHedgingInput struct {
+	Name string `json:"name"`
+
+	// Delay after which a hedged request is sent (eg- 300ms). An empty or zero delay disables hedging.
+	Delay string `json:"delay"`
+
+	// Version is the version of the tool that the change is based on, see SetToolOutputSchemaInput
+	Version int `json:"version,omitempty"`
+}
+
+// SetToolPayloadLoggingInput is the input for logging the arguments & results of a sample of the calls to a tool.
+type SetToolPayloadLoggingInput struct {
+	Name string `json:"name"`
+
+	// SampleRate is the fraction (0 to 1) of the calls whose payloads are logged, 0 turns payload logging off
+	SampleRate float64 `json:"sample_rate"`
+
+	// Redact contains the paths of the fields removed from the logged payloads, see the README for their syntax
+	Redact []string `json:"redact,omitempty"`
+
+	// Version is the version of the tool that the change is based on, see SetToolOutputSchemaInput
+	Version int `json:"version,omitempty"`
+}
+
+// SetToolScriptsInput is the input for attaching CEL scripts to a tool.
+// It replaces the scripts previously attached to the tool, empty scripts remove them.
+type SetToolScriptsInput struct {
+	Name string `json:"name"`
+
+	// Args is evaluated before every call, its fields are merged into the call's arguments
+	Args string `json:"args,omitempty"`
+
+	// Result is evaluated after every successful call, its fields are merged into the call's result
+	Result string `json:"result,omitempty"`
+
+	// Version is the version of the tool that the change is based on, see SetToolOutputSchemaInput
+	Version int `json:"version,omitempty"`
+}
+
+// SetToolDocsInput is the input for enriching the description of a tool.
+// It replaces all the documentation previously added to the tool, empty values remove it.
+type SetToolDocsInput struct {
+	Name string `json:"name"`
+
+	// Description replaces the description provided by the tool's upstream server
+	Description string `json:"description,omitempty"`
+
+	// Append is added after the tool's description
+	Append string `json:"append,omitempty"`
+
+	// Examples are usage examples listed at the end of the tool's description
+	Examples []string `json:"examples,omitempty"`
+
+	// Version is the version of the tool that the change is based on, see SetToolOutputSchemaInput
+	Version int `json:"version,omitempty"`
 }
@@ -0,0 +1,52 @@
+package types
+
+import "time"
+
+// Kinds of operational events that mcpjungle notifies admins about
+const (
+	// NotificationServerUnhealthy is raised when mcpjungle fails to connect to an MCP server
+	NotificationServerUnhealthy = "server_unhealthy"
+
+	// NotificationBudgetThreshold is raised when the spend of a budget crosses its alert threshold
+	NotificationBudgetThreshold = "budget_threshold"
+
+	// NotificationBudgetExceeded is raised when the spend of a budget reaches its max spend
+	NotificationBudgetExceeded = "budget_exceeded"
+
+	// NotificationServerProposed is raised when a user proposes the registration of an MCP server
+	NotificationServerProposed = "server_proposed"
+
+	// NotificationServerIdentityChanged is raised when an MCP server with a pinned identity reports another one
+	NotificationServerIdentityChanged = "server_identity_changed"
+
+	// NotificationAccessGranted is raised when an MCP client is granted temporary access to a tool group
+	NotificationAccessGranted = "access_granted"
+
+	// NotificationEmergencyStop is raised when all tool calls are disabled with the break-glass switch,
+	// and when they are enabled again
+	NotificationEmergencyStop = "emergency_stop"
+
+	// NotificationToolSchemaDrift is raised when an MCP server changes the input schema of a tool incompatibly
+	NotificationToolSchemaDrift = "tool_schema_drift"
+)
+
+// Notification is an operational event that needs the attention of an admin
+type Notification struct {
+	ID      uint   `json:"id"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+
+	// Count is the number of times the event occurred while the notification was unread
+	Count int `json:"count"`
+
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"CreatedAt"`
+	UpdatedAt time.Time  `json:"UpdatedAt"`
+}
+
+// MarkNotificationsReadInput is the input for marking notifications as read.
+// If no IDs are given, all notifications are marked as read.
+type MarkNotificationsReadInput struct {
+	IDs []uint `json:"ids,omitempty"`
+}
@@ -0,0 +1,17 @@
+package types
+
+// Policy is evaluated before every tool invocation to decide whether the call is allowed, denied or
+// allowed with transformed arguments.
+type Policy struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Language is the language the policy's expression is written in: "cel" (default), "args",
+	// a JSON document that restricts the argument values sent to tools, or "egress", a JSON document that
+	// allowlists the domains & file paths that arguments may point to.
+	Language   string `json:"language"`
+	Expression string `json:"expression"`
+
+	// Priority determines the order in which policies are evaluated, lowest first
+	Priority int `json:"priority"`
+}
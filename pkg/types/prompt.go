@@ -0,0 +1,31 @@
+package types
+
+// Prompt is a prompt template managed by mcpjungle and served to MCP clients by the MCP proxy.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+
+	// Template is the text of the prompt. Occurrences of {{argument}} are replaced by the argument's value.
+	// {{client.name}} and {{client.<attribute>}} are replaced by the name & attributes of the MCP client.
+	Template string `json:"template"`
+
+	// Overrides maps the names of MCP clients to the templates used instead of Template for them
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+// PromptArgument is an argument that MCP clients supply when getting a prompt.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// SetPromptOverrideInput is the input for setting the template of a prompt used for a specific MCP client.
+type SetPromptOverrideInput struct {
+	Prompt string `json:"prompt"`
+	Client string `json:"client"`
+
+	// Template replaces the prompt's template for the client. An empty template removes the override.
+	Template string `json:"template"`
+}
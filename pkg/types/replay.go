@@ -0,0 +1,101 @@
+package types
+
+// ReplayStatus describes how the outcome of a replayed call compares to the recorded one
+type ReplayStatus string
+
+const (
+	ReplayUnchanged ReplayStatus = "unchanged"
+	ReplayChanged   ReplayStatus = "changed"
+
+	// ReplayNewError means the call succeeded when it was recorded but fails now
+	ReplayNewError ReplayStatus = "new_error"
+
+	// ReplayFixed means the call failed when it was recorded but succeeds now
+	ReplayFixed ReplayStatus = "fixed"
+
+	// ReplayFailing means the call failed both when it was recorded and now
+	ReplayFailing ReplayStatus = "failing"
+)
+
+// RecordedCall is a tool call recorded in a replay suite, along with its outcome.
+type RecordedCall struct {
+	Tool string         `json:"tool"`
+	Args map[string]any `json:"args,omitempty"`
+
+	// Result is the result of the call, Error is set instead if the call failed
+	Result *ToolInvokeResult `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Failed returns true if the call failed or the tool returned an error result.
+func (c *RecordedCall) Failed() bool {
+	return c.Error != "" || (c.Result != nil && c.Result.IsError)
+}
+
+// ReplaySuite is a set of tool calls recorded against an MCP server, which can be replayed later
+// (eg- after upgrading the server) to find out what changed.
+type ReplaySuite struct {
+	Name   string         `json:"name"`
+	Server string         `json:"server"`
+	Calls  []RecordedCall `json:"calls"`
+}
+
+// CreateReplaySuiteInput is the input for recording a replay suite.
+type CreateReplaySuiteInput struct {
+	Name   string `json:"name"`
+	Server string `json:"server"`
+
+	// SavedCalls are the names of the saved calls to record.
+	// If empty, all saved calls of the server's tools are recorded.
+	SavedCalls []string `json:"saved_calls,omitempty"`
+}
+
+// RunReplaySuiteInput is the input for replaying a replay suite.
+type RunReplaySuiteInput struct {
+	// Server to replay the calls against, if it is not the one the suite was recorded against
+	// (eg- a new version of the server registered side by side with the current one)
+	Server string `json:"server,omitempty"`
+}
+
+// ResultChange is a difference between the recorded and the replayed result of a call.
+// Path locates the changed value in the result, eg- content[0].text
+type ResultChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// ReplayedCall compares the outcome of a replayed call with the recorded one.
+type ReplayedCall struct {
+	Tool   string         `json:"tool"`
+	Args   map[string]any `json:"args,omitempty"`
+	Status ReplayStatus   `json:"status"`
+
+	// Changes lists the differences between the results, if both calls succeeded
+	Changes []ResultChange `json:"changes,omitempty"`
+
+	// RecordedError & Error are the errors of the recorded and the replayed call, if they failed
+	RecordedError string `json:"recorded_error,omitempty"`
+	Error         string `json:"error,omitempty"`
+
+	RecordedMs     int64 `json:"recorded_ms"`
+	DurationMs     int64 `json:"duration_ms"`
+	LatencyDeltaMs int64 `json:"latency_delta_ms"`
+}
+
+// ReplayReport is the outcome of replaying a replay suite.
+type ReplayReport struct {
+	Suite  string         `json:"suite"`
+	Server string         `json:"server"`
+	Calls  []ReplayedCall `json:"calls"`
+
+	// Summary counts the replayed calls by status
+	Summary map[ReplayStatus]int `json:"summary"`
+}
+
+// HasRegressions returns true if any replayed call changed or started failing.
+func (r *ReplayReport) HasRegressions() bool {
+	return r.Summary[ReplayChanged] > 0 || r.Summary[ReplayNewError] > 0
+}
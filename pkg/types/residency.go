@@ -0,0 +1,18 @@
+package types
+
+// ResidencyPolicy restricts the tool calls of an MCP client or a user to MCP servers in specific regions.
+type ResidencyPolicy struct {
+	Name string `json:"name"`
+
+	// Exactly one of Client & User must be set
+	Client string `json:"client,omitempty"`
+	User   string `json:"user,omitempty"`
+
+	// AllowedRegions contains the regions that tool calls may be served from
+	AllowedRegions []string `json:"allowed_regions"`
+
+	// Action is either "reject" or "reroute".
+	// With "reroute", calls to servers outside the allowed regions are sent to an equivalent server
+	// (one that provides a tool with the same name) in an allowed region instead.
+	Action string `json:"action"`
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ToolResultSignatureMetaKey is the key in the _meta of a tool result under which mcpjungle puts its signature
+// of the result. The signature is a JWS with a detached payload, see ToolResultSigningPayload.
+const ToolResultSignatureMetaKey = "io.mcpjungle/signature"
+
+// JWK is a public key in the JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+}
+
+// JWKS is a set of public keys in the JSON Web Key format
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ToolResultSigningContent is the part of a tool result that its signature covers: everything but the _meta.
+// Signers and verifiers build the payload from this struct, so that it doesn't depend on how the result
+// they hold was serialized (eg- mcp-go's CallToolResult omits structuredContent from its JSON).
+type ToolResultSigningContent struct {
+	Content           []any `json:"content"`
+	IsError           bool  `json:"isError,omitempty"`
+	StructuredContent any   `json:"structuredContent,omitempty"`
+}
+
+// NewToolResultSigningContent extracts the signed part of a tool result.
+// result can be any value that serializes to a tool result, eg- a ToolInvokeResult.
+func NewToolResultSigningContent(result any) (ToolResultSigningContent, error) {
+	var c ToolResultSigningContent
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return c, err
+	}
+	d := json.NewDecoder(bytes.NewReader(raw))
+	// decode numbers as json.Number so that they are serialized exactly as received
+	d.UseNumber()
+	err = d.Decode(&c)
+	return c, err
+}
+
+// ToolResultSigningPayload returns the payload of the signature of a tool result.
+// It is the JSON serialization of the signed content, with object keys sorted,
+// no insignificant whitespace and no escaping of HTML characters.
+func ToolResultSigningPayload(c ToolResultSigningContent) ([]byte, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var obj map[string]any
+	if err := d.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(obj); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,82 @@
+package types
+
+import "errors"
+
+// Tables whose history can be pruned
+const (
+	RetentionTableInvocations   = "invocations"
+	RetentionTableNotifications = "notifications"
+)
+
+// RetentionPolicy limits how much history is kept in a table.
+type RetentionPolicy struct {
+	// MaxAge is the age beyond which rows are deleted (eg- "90d"), empty means no age limit
+	MaxAge string `json:"max_age,omitempty"`
+
+	// MaxRows is the number of most recent rows kept, 0 means no limit
+	MaxRows int `json:"max_rows,omitempty"`
+}
+
+// PruneInput is the input for pruning history tables on demand.
+type PruneInput struct {
+	// Policies are keyed by table (see the RetentionTable* constants).
+	// They replace the server's configured policies for the tables they're given for.
+	Policies map[string]RetentionPolicy `json:"policies,omitempty"`
+}
+
+// PruneResult is the number of rows deleted from a table by pruning.
+type PruneResult struct {
+	Table   string          `json:"table"`
+	Policy  RetentionPolicy `json:"policy"`
+	Deleted int64           `json:"deleted"`
+}
+
+// DataSubject identifies the MCP client or user whose data is purged. Exactly one of Client & User must be set.
+type DataSubject struct {
+	Client string `json:"client,omitempty"`
+	User   string `json:"user,omitempty"`
+}
+
+// Validate checks that the data subject identifies exactly one client or user.
+func (s DataSubject) Validate() error {
+	if (s.Client == "") == (s.User == "") {
+		return errors.New("exactly one of client and user must be given")
+	}
+	return nil
+}
+
+func (s DataSubject) String() string {
+	if s.Client != "" {
+		return "MCP client " + s.Client
+	}
+	return "user " + s.User
+}
+
+// PurgeStoreResult describes the purge of the data of a subject from one store (a table or an audit sink).
+type PurgeStoreResult struct {
+	Store string `json:"store"`
+
+	// Deleted is the number of records deleted
+	Deleted int64 `json:"deleted"`
+
+	// Anonymized is the number of records kept with the subject's identity removed from them,
+	// eg- calls the subject made on behalf of another client while impersonating it
+	Anonymized int64 `json:"anonymized,omitempty"`
+
+	// Remaining is the number of records about the subject still found after the purge
+	Remaining int64 `json:"remaining"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// PurgeReport is the verification report of the purge of a data subject.
+type PurgeReport struct {
+	Subject DataSubject        `json:"subject"`
+	Results []PurgeStoreResult `json:"results"`
+
+	// Verified is true if no record about the subject remains in any of the stores that were purged
+	Verified bool `json:"verified"`
+
+	// Notes describe the data about the subject that mcpjungle can't purge itself
+	Notes []string `json:"notes,omitempty"`
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Classes of upstream failures that a retry policy can retry
+const (
+	// RetryOnConnection covers failures to connect to the MCP server or connections dropped during a call
+	RetryOnConnection = "connection"
+	// RetryOnTimeout covers calls that timed out
+	RetryOnTimeout = "timeout"
+	// RetryOnToolError covers calls for which the tool itself reported an error (isError in the result)
+	RetryOnToolError = "tool_error"
+)
+
+const (
+	defaultRetryBackoff    = 200 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+)
+
+// RetryPolicy describes how tool calls to an MCP server are retried when they fail with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a call is attempted, including the first attempt
+	MaxAttempts int `json:"max_attempts"`
+
+	// Backoff is the delay before the first retry (eg- 200ms). It doubles with every subsequent retry.
+	Backoff string `json:"backoff,omitempty"`
+
+	// MaxBackoff caps the delay between two attempts (eg- 5s)
+	MaxBackoff string `json:"max_backoff,omitempty"`
+
+	// RetryOn contains the classes of failures that are retried ("connection", "timeout", "tool_error").
+	// Connection failures and timeouts are retried by default.
+	RetryOn []string `json:"retry_on,omitempty"`
+
+	// RetryNonIdempotent allows retrying calls to tools that are not annotated as idempotent or read-only
+	// after the call may have reached the tool. Failures to connect to the server are always safe to retry.
+	RetryNonIdempotent bool `json:"retry_non_idempotent,omitempty"`
+}
+
+// Validate checks that the retry policy is well-formed.
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("max_attempts of retry policy must be at least 1")
+	}
+	for _, d := range []string{p.Backoff, p.MaxBackoff} {
+		if d == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d); err != nil || v < 0 {
+			return fmt.Errorf("invalid duration '%s' in retry policy", d)
+		}
+	}
+	for _, c := range p.RetryOn {
+		if c != RetryOnConnection && c != RetryOnTimeout && c != RetryOnToolError {
+			return fmt.Errorf(
+				"unsupported failure class '%s' in retry policy (acceptable values: '%s', '%s', '%s')",
+				c, RetryOnConnection, RetryOnTimeout, RetryOnToolError,
+			)
+		}
+	}
+	return nil
+}
+
+// Retries returns true if failures of the given class must be retried.
+func (p *RetryPolicy) Retries(class string) bool {
+	if len(p.RetryOn) == 0 {
+		return class == RetryOnConnection || class == RetryOnTimeout
+	}
+	return slices.Contains(p.RetryOn, class)
+}
+
+// Delay returns the time to wait before the given retry (1 for the first retry).
+// It assumes that the policy is valid.
+func (p *RetryPolicy) Delay(retry int) time.Duration {
+	backoff, maxBackoff := defaultRetryBackoff, defaultRetryMaxBackoff
+	if p.Backoff != "" {
+		backoff, _ = time.ParseDuration(p.Backoff)
+	}
+	if p.MaxBackoff != "" {
+		maxBackoff, _ = time.ParseDuration(p.MaxBackoff)
+	}
+	d := backoff
+	for i := 1; i < retry && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
@@ -0,0 +1,13 @@
+package types
+
+// SavedCall is a named set of arguments for a tool, which can be replayed to invoke the tool the same way every time.
+type SavedCall struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Tool is the canonical name of the tool that the saved call invokes
+	Tool string `json:"tool"`
+
+	// Args are the arguments passed to the tool
+	Args map[string]any `json:"args,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+// Secret is a secret stored in the registry. Its value is never returned by the API.
+type Secret struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetSecretInput is the input for creating a secret or changing its value.
+type SetSecretInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SetSecretResult is the response to setting a secret.
+type SetSecretResult struct {
+	Secret
+
+	// Servers are the MCP servers whose environment refers to the secret, they use its new value from now on
+	Servers []string `json:"servers,omitempty"`
+}
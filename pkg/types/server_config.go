@@ -0,0 +1,50 @@
+package types
+
+// ReadOnlyStatus describes whether the registry is in read-only mode.
+// In read-only mode, management operations that modify the registry are rejected but tools can still be called.
+type ReadOnlyStatus struct {
+	ReadOnly bool `json:"read_only"`
+
+	// Forced is true if read-only mode was enabled when starting the server, in which case it can't be turned off via the API
+	Forced bool `json:"forced,omitempty"`
+}
+
+// SetReadOnlyInput is the input for turning the read-only mode of the registry on or off
+type SetReadOnlyInput struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Ways of authenticating to the registry, as reported in ServerMetadata
+const (
+	// AuthModeNone means that no authentication is required, ie, the server runs in development mode
+	AuthModeNone = "none"
+	// AuthModeUserToken means that API requests must carry the bearer access token of a user
+	AuthModeUserToken = "user_token"
+	// AuthModeMcpClientToken means that MCP clients must carry their bearer access token
+	AuthModeMcpClientToken = "mcp_client_token"
+	// AuthModeSpiffeSVID means that MCP clients can authenticate with their SPIFFE X.509 SVID instead of a token
+	AuthModeSpiffeSVID = "spiffe_svid"
+)
+
+// ServerMetadata describes a registry server to its clients, so that they can adapt to it.
+// It is served without authentication, so it only contains information that is safe to disclose.
+type ServerMetadata struct {
+	// Version is the version of mcpjungle that the server runs
+	Version string `json:"version"`
+
+	// APIVersions contains the versions of the REST API that the server supports, eg- "v0"
+	APIVersions []string `json:"api_versions"`
+
+	// ProtocolVersions contains the versions of the MCP protocol that the proxy supports, latest first
+	ProtocolVersions []string `json:"protocol_versions"`
+
+	// Initialized is false until the server has been initialized, in which case Mode and AuthModes are empty
+	Initialized bool   `json:"initialized"`
+	Mode        string `json:"mode,omitempty"`
+
+	// Features contains the names of the experimental features that are enabled
+	Features []string `json:"features"`
+
+	// AuthModes contains the ways of authenticating to the server
+	AuthModes []string `json:"auth_modes,omitempty"`
+}
@@ -0,0 +1,55 @@
+package types
+
+import "time"
+
+// ServerProposalStatus is the state of a proposed server registration
+type ServerProposalStatus string
+
+const (
+	// ServerProposalPending is the state of a proposal that awaits the review of an admin
+	ServerProposalPending ServerProposalStatus = "pending"
+	// ServerProposalApproved is the state of a proposal whose server has been registered
+	ServerProposalApproved ServerProposalStatus = "approved"
+	// ServerProposalRejected is the state of a proposal that an admin turned down
+	ServerProposalRejected ServerProposalStatus = "rejected"
+)
+
+// ServerProposal is a request of a user to register an MCP server, that an admin approves or rejects.
+// The server's credentials (bearer token & environment variables) are never included.
+type ServerProposal struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Transport   string `json:"transport"`
+	Description string `json:"description"`
+
+	// Target is the URL of a streamable http server or the command line of a stdio server
+	Target string `json:"target"`
+
+	ProposedBy string               `json:"proposed_by"`
+	Status     ServerProposalStatus `json:"status"`
+
+	// ReviewedBy & ReviewComment are set once an admin approved or rejected the proposal
+	ReviewedBy    string `json:"reviewed_by,omitempty"`
+	ReviewComment string `json:"review_comment,omitempty"`
+
+	// Preflighted is true once an admin test-connected to the server, Preflight contains what mcpjungle discovered.
+	// Proposing a server never connects to it.
+	Preflighted bool                    `json:"preflighted"`
+	Preflight   ServerProposalPreflight `json:"preflight"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ServerProposalPreflight describes a proposed MCP server, as discovered by test-connecting to it.
+type ServerProposalPreflight struct {
+	ProtocolVersion string         `json:"protocol_version"`
+	ServerInfo      *ServerInfo    `json:"server_info,omitempty"`
+	Capabilities    map[string]any `json:"capabilities"`
+	Tools           []*Tool        `json:"tools"`
+}
+
+// ReviewServerProposalInput is the input for approving or rejecting a proposal
+type ReviewServerProposalInput struct {
+	Comment string `json:"comment,omitempty"`
+}
@@ -0,0 +1,23 @@
+package types
+
+const (
+	// ToolViewHeader selects the tools listed by the tools/list requests of an MCP proxy session:
+	// ToolViewFavorites, ToolViewFavoritesFirst or the name of a personal view of the user.
+	// The user is identified by their access token, sent in UserTokenHeader.
+	ToolViewHeader  = "X-MCPJungle-Tool-View"
+	UserTokenHeader = "X-MCPJungle-User-Token"
+
+	// ToolViewFavorites only lists the tools starred by the user
+	ToolViewFavorites = "favorites"
+
+	// ToolViewFavoritesFirst lists all tools, starting with the ones starred by the user
+	ToolViewFavoritesFirst = "favorites-first"
+)
+
+// ToolView is a personal view of a user on the tools in the registry.
+type ToolView struct {
+	Name string `json:"name"`
+
+	// Tools contains the canonical names of the tools and the names of the MCP servers (all their tools) in the view
+	Tools []string `json:"tools"`
+}
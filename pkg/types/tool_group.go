@@ -0,0 +1,21 @@
+package types
+
+// ToolGroup is a named subset of the tools in the registry.
+// Each group is served on its own MCP proxy endpoint.
+type ToolGroup struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	IncludedTools []string `json:"included_tools"`
+
+	// ExtractionProfile is the optional name of the extraction profile applied to the group's tool results
+	ExtractionProfile string `json:"extraction_profile,omitempty"`
+}
+
+// ExtractionProfile contains JSONPath expressions that select the parts of tool results returned to clients.
+type ExtractionProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Rules maps canonical tool names to the JSONPath expression applied to their results
+	Rules map[string]string `json:"rules"`
+}
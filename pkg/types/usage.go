@@ -0,0 +1,218 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Budget limits how much an MCP client or a user can spend on tool calls within a period.
+type Budget struct {
+	Name string `json:"name"`
+
+	// Exactly one of Client & User must be set
+	Client string `json:"client,omitempty"`
+	User   string `json:"user,omitempty"`
+
+	MaxSpend float64 `json:"max_spend"`
+
+	// Period is one of "daily", "monthly" or "total"
+	Period string `json:"period"`
+
+	// AlertThreshold is the percentage of MaxSpend at which an alert is raised
+	AlertThreshold float64 `json:"alert_threshold"`
+	WebhookURL     string  `json:"webhook_url,omitempty"`
+
+	// HardStop rejects tool calls that would take the spend beyond MaxSpend
+	HardStop bool `json:"hard_stop"`
+
+	// Spend is the amount spent in the current period
+	Spend float64 `json:"spend"`
+}
+
+// BudgetAlert is the payload sent to a budget's webhook when an alert is raised.
+type BudgetAlert struct {
+	Budget string `json:"budget"`
+	Client string `json:"client,omitempty"`
+	User   string `json:"user,omitempty"`
+
+	// Exceeded is true if the spend has reached the budget's max spend.
+	// Otherwise, the alert threshold was crossed.
+	Exceeded bool `json:"exceeded"`
+
+	Spend    float64 `json:"spend"`
+	MaxSpend float64 `json:"max_spend"`
+	Period   string  `json:"period"`
+}
+
+// SetToolCostInput is the input for setting the pricing of a tool.
+type SetToolCostInput struct {
+	Name        string  `json:"name"`
+	CostPerCall float64 `json:"cost_per_call"`
+	CostPerUnit float64 `json:"cost_per_unit"`
+	CostUnitArg string  `json:"cost_unit_arg"`
+
+	// Version is the version of the tool that the change is based on, see SetToolOutputSchemaInput
+	Version int `json:"version,omitempty"`
+}
+
+// ToolInvocation is a record of a tool call made via mcpjungle.
+type ToolInvocation struct {
+	ToolName   string  `json:"tool_name"`
+	ClientName string  `json:"client_name,omitempty"`
+	Username   string  `json:"username,omitempty"`
+	Cost       float64 `json:"cost"`
+	IsError    bool    `json:"is_error"`
+
+	// Attempts is the number of times the call was attempted, retries included
+	Attempts int `json:"attempts"`
+
+	// DurationMs is the time taken by the call in milliseconds, retries included
+	DurationMs int64 `json:"duration_ms"`
+
+	// ImpersonatedBy is the admin who made the call on behalf of the client or user, if any
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+
+	// Arguments & Result are the redacted payloads of the call, if it was sampled for payload logging
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Result    map[string]any `json:"result,omitempty"`
+
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// ToolUsage summarizes the calls made to a tool within a period
+type ToolUsage struct {
+	Tool         string    `json:"tool"`
+	Calls        int64     `json:"calls"`
+	Errors       int64     `json:"errors"`
+	Cost         float64   `json:"cost"`
+	LastCalledAt time.Time `json:"last_called_at"`
+}
+
+// ClientApplicationUsage describes the MCP proxy sessions opened by a version of a client application
+// (eg- Claude Desktop, Cursor or an agent framework) within a period.
+type ClientApplicationUsage struct {
+	// Name & Version are the clientInfo sent by the application when initializing its sessions
+	Name    string `json:"name"`
+	Version string `json:"version"`
+
+	// ProtocolVersion is the latest MCP protocol revision supported by the application, as requested at initialize
+	ProtocolVersion string `json:"protocol_version"`
+
+	// Capabilities are the capabilities advertised by the application, eg- "roots", "roots.listChanged", "sampling"
+	// or "experimental.<name>"
+	Capabilities []string `json:"capabilities"`
+
+	Sessions   int64     `json:"sessions"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// ClientBreakdown breaks the MCP proxy sessions initialized within a period down by client application,
+// protocol version and capability, to know which agent stacks must be supported.
+type ClientBreakdown struct {
+	Sessions int64 `json:"sessions"`
+
+	// Applications, ProtocolVersions & Capabilities contain the number of sessions by client application name,
+	// by requested protocol version and by advertised capability
+	Applications     map[string]int64 `json:"applications"`
+	ProtocolVersions map[string]int64 `json:"protocol_versions"`
+	Capabilities     map[string]int64 `json:"capabilities"`
+
+	// Clients lists every distinct client, most sessions first
+	Clients []*ClientApplicationUsage `json:"clients"`
+}
+
+// UnusedTool is a tool that hasn't been called within a period
+type UnusedTool struct {
+	Name         string    `json:"name"`
+	Enabled      bool      `json:"enabled"`
+	RegisteredAt time.Time `json:"registered_at"`
+
+	// LastCalledAt is nil if the tool has never been called
+	LastCalledAt *time.Time `json:"last_called_at,omitempty"`
+}
+
+// UsageRollup aggregates the calls made to an MCP server, to the tools of a tool group or by an MCP client within a period
+type UsageRollup struct {
+	Name   string `json:"name"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+
+	// ErrorRate is the percentage of failed calls
+	ErrorRate float64 `json:"error_rate"`
+
+	// P95LatencyMs is the 95th percentile of the time taken by the calls
+	P95LatencyMs int64 `json:"p95_latency_ms"`
+}
+
+// MetricsSummary contains the usage of the registry within a period, pre-aggregated from the invocation history
+// for dashboards that can't query Prometheus.
+type MetricsSummary struct {
+	Since time.Time    `json:"since"`
+	Total *UsageRollup `json:"total"`
+
+	// Servers, ToolGroups & Clients are ordered by number of calls, most called first.
+	// Calls made to the tools of a tool group are counted for the group regardless of the endpoint they were made on.
+	Servers    []*UsageRollup `json:"servers"`
+	ToolGroups []*UsageRollup `json:"tool_groups"`
+	Clients    []*UsageRollup `json:"clients"`
+}
+
+// ParseLookback parses the length of a period to look back on, eg- "30d", "12h" or "90m".
+// In addition to the units supported by time.ParseDuration, it supports days ("d").
+func ParseLookback(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period '%s', must be a positive duration like 30d or 12h", s)
+	}
+	return d, nil
+}
+
+// SLO is a service level objective for the calls made to a tool or to all tools of an MCP server.
+type SLO struct {
+	Name string `json:"name"`
+
+	// Exactly one of Tool & Server must be set
+	Tool   string `json:"tool,omitempty"`
+	Server string `json:"server,omitempty"`
+
+	// LatencyPercentile & LatencyTargetMs declare the latency objective, eg- p95 of at most 2000ms.
+	// A LatencyTargetMs of 0 means there is no latency objective.
+	LatencyPercentile float64 `json:"latency_percentile"`
+	LatencyTargetMs   int64   `json:"latency_target_ms"`
+
+	// MaxErrorRate is the highest percentage of failed calls allowed, 0 means there is no error rate objective
+	MaxErrorRate float64 `json:"max_error_rate"`
+
+	// Window is the rolling period over which compliance is computed, eg- "1d" or "12h"
+	Window string `json:"window"`
+
+	Status *SLOStatus `json:"status,omitempty"`
+}
+
+// SLOStatus is the compliance of an SLO over its current window
+type SLOStatus struct {
+	Calls int64 `json:"calls"`
+
+	// LatencyMs is the observed latency at the SLO's percentile
+	LatencyMs int64 `json:"latency_ms"`
+
+	// ErrorRate is the observed percentage of failed calls
+	ErrorRate float64 `json:"error_rate"`
+
+	LatencyMet   bool `json:"latency_met"`
+	ErrorRateMet bool `json:"error_rate_met"`
+
+	// Compliant is true if all objectives of the SLO are met.
+	// An SLO with no calls in its window is compliant.
+	Compliant bool `json:"compliant"`
+}
@@ -25,3 +25,17 @@ type CreateUserResponse struct {
 	Role        string `json:"role"`
 	AccessToken string `json:"access_token"`
 }
+
+// Headers with which an admin impersonates an MCP client or a user when invoking a tool via the API.
+// The call is made exactly as the impersonated caller would make it (same access control, policies and budgets).
+const (
+	ImpersonateClientHeader = "X-Impersonate-Client"
+	ImpersonateUserHeader   = "X-Impersonate-User"
+)
+
+// Impersonation identifies the MCP client or user on whose behalf an admin invokes a tool.
+// At most one of the fields must be set.
+type Impersonation struct {
+	Client string
+	User   string
+}
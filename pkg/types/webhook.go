@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// WebhookSignatureHeader is the HTTP header that carries the signature of an outbound webhook's payload.
+// Its value has the form "t=<unix timestamp>,v1=<signature>[,v1=<signature>...]", where every signature
+// is the hex-encoded HMAC-SHA256 of "<timestamp>.<request body>" computed with one of the registry's
+// valid webhook keys.
+// Multiple signatures are present while a previous key is still within its grace period after a rotation.
+const WebhookSignatureHeader = "X-MCPJungle-Signature"
+
+// WebhookKeyIDHeader is the HTTP header that carries the ID of the key the first signature was computed with.
+const WebhookKeyIDHeader = "X-MCPJungle-Key-Id"
+
+// WebhookKey is a secret used by the registry to sign outbound webhook payloads.
+type WebhookKey struct {
+	KeyID string `json:"key_id"`
+
+	// Secret is only returned when the key is created
+	Secret string `json:"secret,omitempty"`
+
+	CreatedAt time.Time  `json:"CreatedAt"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RotateWebhookKeyInput is the input for creating a new webhook signing key.
+type RotateWebhookKeyInput struct {
+	// GracePeriod is the duration (eg- "24h") for which the previous keys remain valid.
+	// If empty, the previous keys expire immediately.
+	GracePeriod string `json:"grace_period,omitempty"`
+}